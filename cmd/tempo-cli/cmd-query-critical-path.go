@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type queryCriticalPathCmd struct {
+	APIEndpoint string `arg:"" help:"tempo api endpoint"`
+	TraceID     string `arg:"" help:"trace ID to retrieve the critical path for"`
+
+	OrgID string `help:"optional orgID"`
+}
+
+func (cmd *queryCriticalPathCmd) Run(_ *globalOptions) error {
+	client := util.NewClient(cmd.APIEndpoint, cmd.OrgID)
+
+	criticalPath, err := client.QueryTraceCriticalPath(cmd.TraceID)
+	if err != nil {
+		return err
+	}
+
+	return printAsJSON(criticalPath)
+}
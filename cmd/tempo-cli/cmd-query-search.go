@@ -18,9 +18,15 @@ type querySearchCmd struct {
 	End         string `arg:"" optional:"" help:"end time in ISO8601 format"`
 
 	OrgID string `help:"optional orgID"`
+	Saved string `help:"optional name of a saved query to run instead of the traceql query"`
 }
 
 func (cmd *querySearchCmd) Run(_ *globalOptions) error {
+	// the traceql query is omitted when running a saved query
+	if cmd.Saved != "" && cmd.End == "" {
+		cmd.TraceQL, cmd.Start, cmd.End = "", cmd.TraceQL, cmd.Start
+	}
+
 	startDate, err := time.Parse(time.RFC3339, cmd.Start)
 	if err != nil {
 		return err
@@ -47,6 +53,7 @@ func (cmd *querySearchCmd) Run(_ *globalOptions) error {
 
 	resp, err := client.Search(ctx, &tempopb.SearchRequest{
 		Query: cmd.TraceQL,
+		Saved: cmd.Saved,
 		Start: uint32(start),
 		End:   uint32(end),
 	})
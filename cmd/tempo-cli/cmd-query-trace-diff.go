@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type queryTraceDiffCmd struct {
+	APIEndpoint string `arg:"" help:"tempo api endpoint"`
+	TraceIDA    string `arg:"" name:"trace-id-a" help:"trace ID to compare"`
+	TraceIDB    string `arg:"" name:"trace-id-b" help:"trace ID to compare against"`
+
+	OrgID string `help:"optional orgID"`
+}
+
+func (cmd *queryTraceDiffCmd) Run(_ *globalOptions) error {
+	client := util.NewClient(cmd.APIEndpoint, cmd.OrgID)
+
+	diff, err := client.QueryTraceDiff(cmd.TraceIDA, cmd.TraceIDB)
+	if err != nil {
+		return err
+	}
+
+	return printAsJSON(diff)
+}
@@ -60,6 +60,8 @@ var cli struct {
 	Query struct {
 		API struct {
 			TraceID         queryTraceIDCmd         `cmd:"" help:"query Tempo by trace ID"`
+			TraceDiff       queryTraceDiffCmd       `cmd:"" help:"compare two traces by trace ID"`
+			CriticalPath    queryCriticalPathCmd    `cmd:"" help:"query the critical path of a trace by trace ID"`
 			SearchTags      querySearchTagsCmd      `cmd:"" help:"query Tempo search tags"`
 			SearchTagValues querySearchTagValuesCmd `cmd:"" help:"query Tempo search tag values"`
 			Search          querySearchCmd          `cmd:"" help:"query Tempo search"`
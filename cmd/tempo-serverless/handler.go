@@ -93,6 +93,8 @@ func Handler(r *http.Request) (*tempopb.SearchResponse, *HTTPError) {
 		DataEncoding:  searchReq.DataEncoding,
 		Size:          searchReq.Size_,
 		FooterSize:    searchReq.FooterSize,
+
+		DedicatedColumns: backend.DedicatedColumnsFromTempopb(searchReq.DedicatedColumns),
 	}
 
 	block, err := encoding.OpenBlock(meta, reader)
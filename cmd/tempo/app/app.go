@@ -34,6 +34,7 @@ import (
 	"github.com/grafana/tempo/modules/ingester"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier"
+	"github.com/grafana/tempo/modules/ruler"
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util"
@@ -70,6 +71,7 @@ type App struct {
 	compactor      *compactor.Compactor
 	ingester       *ingester.Ingester
 	generator      *generator.Generator
+	ruler          *ruler.Ruler
 	store          storage.Store
 	usageReport    *usagestats.Reporter
 	MemberlistKV   *memberlist.KVInitService
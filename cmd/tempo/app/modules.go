@@ -22,16 +22,19 @@ import (
 	"github.com/grafana/tempo/modules/compactor"
 	"github.com/grafana/tempo/modules/distributor"
 	"github.com/grafana/tempo/modules/frontend"
+	"github.com/grafana/tempo/modules/frontend/savedqueries"
 	frontend_v1pb "github.com/grafana/tempo/modules/frontend/v1/frontendv1pb"
 	"github.com/grafana/tempo/modules/generator"
 	"github.com/grafana/tempo/modules/ingester"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier"
+	"github.com/grafana/tempo/modules/ruler"
 	tempo_storage "github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
 	tempo_ring "github.com/grafana/tempo/pkg/ring"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/usagestats"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	util_log "github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -62,6 +65,7 @@ const (
 	Querier          string = "querier"
 	QueryFrontend    string = "query-frontend"
 	Compactor        string = "compactor"
+	Ruler            string = "ruler"
 
 	// composite targets
 	SingleBinary         string = "all"
@@ -219,6 +223,7 @@ func (t *App) initDistributor() (services.Service, error) {
 func (t *App) initIngester() (services.Service, error) {
 	t.cfg.Ingester.LifecyclerConfig.ListenPort = t.cfg.Server.GRPCListenPort
 	t.cfg.Ingester.AutocompleteFilteringEnabled = t.cfg.AutocompleteFilteringEnabled
+	t.cfg.Ingester.IngesterClientConfig = t.cfg.IngesterClient
 	ingester, err := ingester.New(t.cfg.Ingester, t.store, t.Overrides, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ingester: %w", err)
@@ -247,6 +252,9 @@ func (t *App) initGenerator() (services.Service, error) {
 	spanStatsHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.generator.SpanMetricsHandler))
 	t.Server.HTTP.Handle(path.Join(api.PathPrefixGenerator, addHTTPAPIPrefix(&t.cfg, api.PathSpanMetrics)), spanStatsHandler)
 
+	attributeProfileHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.generator.AttributeProfileHandler))
+	t.Server.HTTP.Handle(path.Join(api.PathPrefixGenerator, addHTTPAPIPrefix(&t.cfg, api.PathAttributeProfile)), attributeProfileHandler)
+
 	tempopb.RegisterMetricsGeneratorServer(t.Server.GRPC, t.generator)
 
 	return t.generator, nil
@@ -293,9 +301,16 @@ func (t *App) initQuerier() (services.Service, error) {
 		t.HTTPAuthMiddleware,
 	)
 
+	// the diff endpoint has to be registered before the trace by id endpoint. otherwise "diff" is matched as a trace id
+	traceDiffHandler := middleware.Wrap(http.HandlerFunc(t.querier.TraceDiffHandler))
+	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTraceDiff)), traceDiffHandler)
+
 	tracesHandler := middleware.Wrap(http.HandlerFunc(t.querier.TraceByIDHandler))
 	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTraces)), tracesHandler)
 
+	criticalPathHandler := middleware.Wrap(http.HandlerFunc(t.querier.TraceCriticalPathHandler))
+	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTraceCriticalPath)), criticalPathHandler)
+
 	searchHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchHandler))
 	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearch)), searchHandler)
 
@@ -314,6 +329,15 @@ func (t *App) initQuerier() (services.Service, error) {
 	spanMetricsSummaryHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SpanMetricsSummaryHandler))
 	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSpanMetricsSummary)), spanMetricsSummaryHandler)
 
+	queryRangeHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.QueryRangeHandler))
+	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange)), queryRangeHandler)
+
+	traceSummaryHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.TraceSummaryHandler))
+	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTraceSummary)), traceSummaryHandler)
+
+	attributeProfileHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.AttributeProfileHandler))
+	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathAttributeProfile)), attributeProfileHandler)
+
 	return t.querier, t.querier.CreateAndRegisterWorker(t.Server.HTTPServer.Handler)
 }
 
@@ -326,8 +350,14 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	}
 	t.frontend = v1
 
+	// saved queries are stored next to the blocks of the tenants
+	savedQueriesReader, savedQueriesWriter, err := t.rawBackend()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize saved queries: %w", err)
+	}
+
 	// create query frontend
-	queryFrontend, err := frontend.New(t.cfg.Frontend, cortexTripper, t.Overrides, t.store, t.cfg.HTTPAPIPrefix, log.Logger, prometheus.DefaultRegisterer)
+	queryFrontend, err := frontend.New(t.cfg.Frontend, cortexTripper, t.Overrides, t.store, savedqueries.NewStore(savedQueriesReader, savedQueriesWriter), t.cfg.HTTPAPIPrefix, log.Logger, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, err
 	}
@@ -339,8 +369,13 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	)
 
 	traceByIDHandler := middleware.Wrap(queryFrontend.TraceByIDHandler)
+	traceDiffHandler := middleware.Wrap(queryFrontend.TraceDiffHandler)
+	criticalPathHandler := middleware.Wrap(queryFrontend.TraceCriticalPathHandler)
 	searchHandler := middleware.Wrap(queryFrontend.SearchHandler)
 	spanMetricsSummaryHandler := middleware.Wrap(queryFrontend.SpanMetricsSummaryHandler)
+	queryRangeHandler := middleware.Wrap(queryFrontend.QueryRangeHandler)
+	traceSummaryHandler := middleware.Wrap(queryFrontend.TraceSummaryHandler)
+	savedQueriesHandler := middleware.Wrap(queryFrontend.SavedQueriesHandler)
 	searchTagsHandler := middleware.Wrap(queryFrontend.SearchTagsHandler)
 
 	// register grpc server for queriers to connect to
@@ -350,8 +385,10 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	tempopb.RegisterStreamingQuerierServer(t.Server.GRPC, queryFrontend)
 	tempopb.RegisterStreamingQuerierServer(t.Server.GRPCOnHTTPServer, queryFrontend)
 
-	// http trace by id endpoint
+	// http trace by id endpoints. the diff endpoint has to be registered first to not be matched as a trace id
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceDiff), traceDiffHandler)
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraces), traceByIDHandler)
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceCriticalPath), criticalPathHandler)
 
 	// http search endpoints
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearch), searchHandler)
@@ -359,9 +396,15 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsV2), searchTagsHandler)
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValues), searchTagsHandler)
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValuesV2), searchTagsHandler)
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceSummary), traceSummaryHandler)
+
+	// http saved queries endpoints
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathSavedQueries), savedQueriesHandler)
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathSavedQuery), savedQueriesHandler)
 
 	// http metrics endpoints
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathSpanMetricsSummary), spanMetricsSummaryHandler)
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange), queryRangeHandler)
 
 	// the query frontend needs to have knowledge of the blocks so it can shard search jobs
 	t.store.EnablePolling(nil)
@@ -394,6 +437,30 @@ func (t *App) initCompactor() (services.Service, error) {
 	return t.compactor, nil
 }
 
+func (t *App) initRuler() (services.Service, error) {
+	if t.cfg.Ruler.QueryFrontendAddress == "" && t.cfg.Target == SingleBinary {
+		t.cfg.Ruler.QueryFrontendAddress = fmt.Sprintf("http://127.0.0.1:%d%s", t.cfg.Server.HTTPListenPort, t.cfg.HTTPAPIPrefix)
+	}
+
+	// without multitenancy all rules are evaluated for the single tenant
+	tenants := t.Overrides.TenantIDs
+	if !t.cfg.MultitenancyIsEnabled() {
+		tenants = func() []string { return []string{util.FakeTenantID} }
+	}
+
+	rulerSvc, err := ruler.New(&t.cfg.Ruler, t.Overrides, tenants, prometheus.DefaultRegisterer, log.Logger)
+	if err == ruler.ErrUnconfigured && t.cfg.Target != Ruler { // just warn if we're not running the ruler
+		level.Warn(log.Logger).Log("msg", "ruler is not configured.", "err", err)
+		return services.NewIdleService(nil, nil), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ruler %w", err)
+	}
+	t.ruler = rulerSvc
+
+	return t.ruler, nil
+}
+
 func (t *App) initStore() (services.Service, error) {
 	store, err := tempo_storage.NewStore(t.cfg.StorageConfig, log.Logger)
 	if err != nil {
@@ -446,6 +513,22 @@ func (t *App) initUsageReport() (services.Service, error) {
 
 	usagestats.Target(t.cfg.Target)
 
+	reader, writer, err := t.rawBackend()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize usage report: %w", err)
+	}
+
+	ur, err := usagestats.NewReporter(t.cfg.UsageReport, t.cfg.Ingester.LifecyclerConfig.RingConfig.KVStore, reader, writer, util_log.Logger, prometheus.DefaultRegisterer)
+	if err != nil {
+		level.Info(util_log.Logger).Log("msg", "failed to initialize usage report", "err", err)
+		return nil, nil
+	}
+	t.usageReport = ur
+	return ur, nil
+}
+
+// rawBackend returns a reader and writer of the objects of the trace storage backend.
+func (t *App) rawBackend() (backend.RawReader, backend.RawWriter, error) {
 	var err error
 	var reader backend.RawReader
 	var writer backend.RawWriter
@@ -463,17 +546,7 @@ func (t *App) initUsageReport() (services.Service, error) {
 		err = fmt.Errorf("unknown backend %s", t.cfg.StorageConfig.Trace.Backend)
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize usage report: %w", err)
-	}
-
-	ur, err := usagestats.NewReporter(t.cfg.UsageReport, t.cfg.Ingester.LifecyclerConfig.RingConfig.KVStore, reader, writer, util_log.Logger, prometheus.DefaultRegisterer)
-	if err != nil {
-		level.Info(util_log.Logger).Log("msg", "failed to initialize usage report", "err", err)
-		return nil, nil
-	}
-	t.usageReport = ur
-	return ur, nil
+	return reader, writer, err
 }
 
 func (t *App) setupModuleManager() error {
@@ -500,6 +573,7 @@ func (t *App) setupModuleManager() error {
 	mm.RegisterModule(QueryFrontend, t.initQueryFrontend)
 	mm.RegisterModule(Compactor, t.initCompactor)
 	mm.RegisterModule(MetricsGenerator, t.initGenerator)
+	mm.RegisterModule(Ruler, t.initRuler)
 
 	mm.RegisterModule(SingleBinary, nil)
 	mm.RegisterModule(ScalableSingleBinary, nil)
@@ -524,8 +598,9 @@ func (t *App) setupModuleManager() error {
 		MetricsGenerator: {Common, MemberlistKV},
 		Querier:          {Common, Store, IngesterRing, MetricsGeneratorRing, SecondaryIngesterRing},
 		Compactor:        {Common, Store, MemberlistKV},
+		Ruler:            {Common},
 		// composite targets
-		SingleBinary:         {Compactor, QueryFrontend, Querier, Ingester, Distributor, MetricsGenerator},
+		SingleBinary:         {Compactor, QueryFrontend, Querier, Ingester, Distributor, MetricsGenerator, Ruler},
 		ScalableSingleBinary: {SingleBinary},
 	}
 
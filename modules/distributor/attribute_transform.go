@@ -0,0 +1,72 @@
+package distributor
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/spantransform"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+var metricAttributesTransformed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_attributes_transformed_total",
+	Help:      "The total number of attributes touched by the attribute transform rules per tenant and rule.",
+}, []string{"tenant", "rule"})
+
+// attributeTransformers caches the compiled transform rules per tenant. The rules are recompiled when
+// the overrides of the tenant change.
+type attributeTransformers struct {
+	mtx      sync.Mutex
+	byTenant map[string]*cachedTransformer
+}
+
+type cachedTransformer struct {
+	rules       spantransform.Rules
+	transformer *spantransform.Transformer
+}
+
+func newAttributeTransformers() *attributeTransformers {
+	return &attributeTransformers{
+		byTenant: map[string]*cachedTransformer{},
+	}
+}
+
+func (a *attributeTransformers) forTenant(userID string, rules spantransform.Rules) (*spantransform.Transformer, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if len(rules) == 0 {
+		delete(a.byTenant, userID)
+		return nil, nil
+	}
+
+	if c, ok := a.byTenant[userID]; ok && reflect.DeepEqual(c.rules, rules) {
+		return c.transformer, nil
+	}
+
+	t, err := spantransform.New(rules)
+	if err != nil {
+		return nil, err
+	}
+	a.byTenant[userID] = &cachedTransformer{rules: rules, transformer: t}
+	return t, nil
+}
+
+// transformAttributes applies the attribute transform rules of the tenant to the batches in place. It
+// returns true if any attribute was changed.
+func (d *Distributor) transformAttributes(userID string, batches []*v1.ResourceSpans) (bool, error) {
+	t, err := d.attributeTransformers.forTenant(userID, d.overrides.AttributeTransformRules(userID))
+	if err != nil || t == nil {
+		return false, err
+	}
+
+	touched := t.Transform(batches)
+	for rule, count := range touched {
+		metricAttributesTransformed.WithLabelValues(userID, rule).Add(float64(count))
+	}
+	return len(touched) > 0, nil
+}
@@ -0,0 +1,61 @@
+package distributor
+
+import (
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/spantransform"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestTransformAttributes(t *testing.T) {
+	limits := &overrides.Limits{}
+	flagext.DefaultValues(limits)
+	limits.AttributeTransformRules = spantransform.Rules{
+		{Name: "mask-url", Keys: []string{"http.url"}, Action: spantransform.ActionMask, Pattern: "token=[^&]*"},
+	}
+
+	d := prepare(t, limits, nil, nil)
+
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("svc", []*v1.ScopeSpans{
+			makeScope(
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "GET", nil, makeAttribute("http.url", "/?token=abc")),
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b371", "GET", nil, makeAttribute("http.url", "/")),
+			),
+		}),
+	}
+
+	before := testutil.ToFloat64(metricAttributesTransformed.WithLabelValues("test", "mask-url"))
+
+	transformed, err := d.transformAttributes("test", batches)
+	require.NoError(t, err)
+	require.True(t, transformed)
+
+	spans := batches[0].ScopeSpans[0].Spans
+	assert.Equal(t, "/?***", spans[0].Attributes[0].Value.GetStringValue())
+	assert.Equal(t, "/", spans[1].Attributes[0].Value.GetStringValue())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricAttributesTransformed.WithLabelValues("test", "mask-url"))-before)
+
+	// the compiled rules are cached per tenant
+	cached, err := d.attributeTransformers.forTenant("test", limits.AttributeTransformRules)
+	require.NoError(t, err)
+	again, err := d.attributeTransformers.forTenant("test", limits.AttributeTransformRules)
+	require.NoError(t, err)
+	require.Same(t, cached, again)
+
+	// and recompiled when they change
+	changed, err := d.attributeTransformers.forTenant("test", spantransform.Rules{{Name: "drop", Keys: []string{"http.url"}, Action: spantransform.ActionDrop}})
+	require.NoError(t, err)
+	require.NotSame(t, cached, changed)
+
+	// nothing to do without rules
+	transformed, err = d.transformAttributes("other", batches)
+	require.NoError(t, err)
+	require.False(t, transformed)
+}
@@ -49,6 +49,8 @@ const (
 	reasonLiveTracesExceeded = "live_traces_exceeded"
 	// reasonInternalError indicates an unexpected error occurred processing these spans. analogous to a 500
 	reasonInternalError = "internal_error"
+	// reasonIngestionPolicy indicates that the spans were dropped or not sampled by the ingestion policies of the tenant
+	reasonIngestionPolicy = "ingestion_policy"
 
 	distributorRingKey = "distributor"
 )
@@ -134,6 +136,12 @@ type Distributor struct {
 	// Per-user rate limiter.
 	ingestionRateLimiter *limiter.RateLimiter
 
+	// Per-user attribute transform rules
+	attributeTransformers *attributeTransformers
+
+	// Per-user ingestion policies
+	ingestionFilters *ingestionFilters
+
 	// Manager for subservices
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -185,17 +193,19 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 	subservices = append(subservices, pool)
 
 	d := &Distributor{
-		cfg:                  cfg,
-		clientCfg:            clientCfg,
-		ingestersRing:        ingestersRing,
-		pool:                 pool,
-		DistributorRing:      distributorRing,
-		ingestionRateLimiter: limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second),
-		generatorClientCfg:   generatorClientCfg,
-		generatorsRing:       generatorsRing,
-		overrides:            o,
-		traceEncoder:         model.MustNewSegmentDecoder(model.CurrentEncoding),
-		logger:               logger,
+		cfg:                   cfg,
+		clientCfg:             clientCfg,
+		ingestersRing:         ingestersRing,
+		pool:                  pool,
+		DistributorRing:       distributorRing,
+		ingestionRateLimiter:  limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second),
+		generatorClientCfg:    generatorClientCfg,
+		generatorsRing:        generatorsRing,
+		overrides:             o,
+		traceEncoder:          model.MustNewSegmentDecoder(model.CurrentEncoding),
+		attributeTransformers: newAttributeTransformers(),
+		ingestionFilters:      newIngestionFilters(),
+		logger:                logger,
 	}
 
 	d.generatorsPool = ring_client.NewPool(
@@ -296,6 +306,23 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		return nil, err
 	}
 
+	// transform the attributes before the spans are logged, forwarded or written to the ingesters
+	transformed, err := d.transformAttributes(userID, batches)
+	if err != nil {
+		return nil, err
+	}
+	if transformed {
+		// the generic forwarders receive the transformed spans as well
+		convert, err = trace.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		traces, err = (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(convert)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if d.cfg.LogReceivedSpans.Enabled || d.cfg.LogReceivedTraces {
 		if d.cfg.LogReceivedSpans.IncludeAllAttributes {
 			logSpansWithAllAttributes(batches, d.cfg.LogReceivedSpans.FilterByStatusError, d.logger)
@@ -336,12 +363,24 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		return nil, err
 	}
 
-	err = d.sendToIngestersViaBytes(ctx, userID, rebatchedTraces, keys)
+	ingesterKeys, ingesterTraces, discarded, err := d.applyIngestionPolicies(userID, keys, rebatchedTraces)
 	if err != nil {
-		recordDiscaredSpans(err, userID, spanCount)
+		overrides.RecordDiscardedSpans(spanCount, reasonInternalError, userID)
 		return nil, err
 	}
+	if discarded > 0 {
+		overrides.RecordDiscardedSpans(discarded, reasonIngestionPolicy, userID)
+	}
+
+	if len(ingesterTraces) > 0 {
+		err = d.sendToIngestersViaBytes(ctx, userID, ingesterTraces, ingesterKeys)
+		if err != nil {
+			recordDiscaredSpans(err, userID, spanCount-discarded)
+			return nil, err
+		}
+	}
 
+	// the metrics-generator receives all spans, metrics are not affected by the ingestion policies
 	if len(d.overrides.MetricsGeneratorProcessors(userID)) > 0 {
 		d.generatorForwarder.SendTraces(ctx, userID, keys, rebatchedTraces)
 	}
@@ -353,6 +392,43 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	return nil, nil // PushRequest is ignored, so no reason to create one
 }
 
+// applyIngestionPolicies drops and samples spans and traces according to the ingestion policies of the
+// tenant. It returns the keys and traces to write to the ingesters and the number of discarded spans.
+func (d *Distributor) applyIngestionPolicies(userID string, keys []uint32, traces []*rebatchedTrace) ([]uint32, []*rebatchedTrace, int, error) {
+	filter, err := d.ingestionFilters.forTenant(userID, d.overrides.IngestionPolicies(userID))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if filter == nil {
+		return keys, traces, 0, nil
+	}
+
+	keptKeys := make([]uint32, 0, len(keys))
+	keptTraces := make([]*rebatchedTrace, 0, len(traces))
+	discarded := 0
+
+	for i, t := range traces {
+		kept, n := filter.Apply(t.id, t.trace)
+		discarded += n
+		if kept == nil {
+			continue
+		}
+		if n > 0 {
+			t = &rebatchedTrace{
+				id:    t.id,
+				trace: kept,
+				start: t.start,
+				end:   t.end,
+			}
+		}
+
+		keptKeys = append(keptKeys, keys[i])
+		keptTraces = append(keptTraces, t)
+	}
+
+	return keptKeys, keptTraces, discarded, nil
+}
+
 func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string, traces []*rebatchedTrace, keys []uint32) error {
 	// Marshal to bytes once
 	marshalledTraces := make([][]byte, len(traces))
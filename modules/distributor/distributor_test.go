@@ -34,6 +34,7 @@ import (
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
@@ -681,6 +682,83 @@ func TestDistributor(t *testing.T) {
 	}
 }
 
+func TestIngestionPolicies(t *testing.T) {
+	limits := &overrides.Limits{}
+	flagext.DefaultValues(limits)
+	limits.IngestionPolicies = filterconfig.IngestionPolicies{
+		{
+			Level:  filterconfig.IngestionPolicyLevelSpan,
+			Action: filterconfig.IngestionPolicyActionDrop,
+			Include: &filterconfig.PolicyMatch{
+				MatchType:  filterconfig.Strict,
+				Attributes: []filterconfig.MatchPolicyAttribute{{Key: "name", Value: "health"}},
+			},
+		},
+		{
+			Level:  filterconfig.IngestionPolicyLevelBatch,
+			Action: filterconfig.IngestionPolicyActionDrop,
+			Include: &filterconfig.PolicyMatch{
+				MatchType:  filterconfig.Strict,
+				Attributes: []filterconfig.MatchPolicyAttribute{{Key: "resource.service.name", Value: "noisy"}},
+			},
+		},
+	}
+
+	d := prepare(t, limits, nil, nil)
+
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("svc", []*v1.ScopeSpans{
+			makeScope(
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "health", nil),
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b371", "GET /api", nil),
+				makeSpan("1a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b372", "health", nil),
+			),
+		}),
+		makeResourceSpans("noisy", []*v1.ScopeSpans{
+			makeScope(makeSpan("2a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b373", "GET /api", nil)),
+		}),
+		makeResourceSpans("svc", []*v1.ScopeSpans{
+			makeScope(makeSpan("2a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b374", "GET /api", nil)),
+		}),
+	}
+
+	keys, traces, err := requestsByTraceID(batches, "test", 5)
+	require.NoError(t, err)
+
+	keptKeys, keptTraces, discarded, err := d.applyIngestionPolicies("test", keys, traces)
+	require.NoError(t, err)
+	require.Equal(t, 4, discarded)
+	require.Len(t, keptKeys, 1)
+	require.Len(t, keptTraces, 1)
+
+	kept := keptTraces[0]
+	require.Equal(t, "0a0102030405060708090a0b0c0d0e0f", hex.EncodeToString(kept.id))
+	require.Len(t, kept.trace.Batches, 1)
+	require.Len(t, kept.trace.Batches[0].ScopeSpans[0].Spans, 1)
+	require.Equal(t, "GET /api", kept.trace.Batches[0].ScopeSpans[0].Spans[0].Name)
+
+	// the input traces are unchanged and still forwarded to the metrics-generators
+	require.Len(t, traces, 3)
+
+	// the compiled policies are cached per tenant and recompiled when they change
+	cached, err := d.ingestionFilters.forTenant("test", limits.IngestionPolicies)
+	require.NoError(t, err)
+	again, err := d.ingestionFilters.forTenant("test", limits.IngestionPolicies)
+	require.NoError(t, err)
+	require.Same(t, cached, again)
+	changed, err := d.ingestionFilters.forTenant("test", limits.IngestionPolicies[:1])
+	require.NoError(t, err)
+	require.NotSame(t, cached, changed)
+	none, err := d.ingestionFilters.forTenant("test", nil)
+	require.NoError(t, err)
+	require.Nil(t, none)
+
+	// a push with only discarded spans succeeds
+	response, err := d.PushTraces(ctx, batchesToTraces(t, batches[1:2]))
+	require.NoError(t, err)
+	require.Nil(t, response)
+}
+
 func TestLogSpans(t *testing.T) {
 	for i, tc := range []struct {
 		LogReceivedTraces       bool // Backwards compatibility with old config
@@ -1037,6 +1115,10 @@ func (i *mockIngester) PushBytesV2(ctx context.Context, in *tempopb.PushBytesReq
 	return nil, nil
 }
 
+func (i *mockIngester) TransferTraces(ctx context.Context, opts ...grpc.CallOption) (tempopb.Pusher_TransferTracesClient, error) {
+	return nil, nil
+}
+
 func (i *mockIngester) Close() error {
 	return nil
 }
@@ -0,0 +1,48 @@
+package distributor
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/grafana/tempo/pkg/spanfilter"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+)
+
+// ingestionFilters caches the compiled ingestion policies per tenant. The policies are recompiled when
+// the overrides of the tenant change.
+type ingestionFilters struct {
+	mtx      sync.Mutex
+	byTenant map[string]*cachedIngestionFilter
+}
+
+type cachedIngestionFilter struct {
+	policies filterconfig.IngestionPolicies
+	filter   *spanfilter.IngestionFilter
+}
+
+func newIngestionFilters() *ingestionFilters {
+	return &ingestionFilters{
+		byTenant: map[string]*cachedIngestionFilter{},
+	}
+}
+
+func (f *ingestionFilters) forTenant(userID string, policies filterconfig.IngestionPolicies) (*spanfilter.IngestionFilter, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if len(policies) == 0 {
+		delete(f.byTenant, userID)
+		return nil, nil
+	}
+
+	if c, ok := f.byTenant[userID]; ok && reflect.DeepEqual(c.policies, policies) {
+		return c.filter, nil
+	}
+
+	filter, err := spanfilter.NewIngestionFilter(policies)
+	if err != nil {
+		return nil, err
+	}
+	f.byTenant[userID] = &cachedIngestionFilter{policies: policies, filter: filter}
+	return filter, nil
+}
@@ -0,0 +1,85 @@
+package receiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/logging"
+	"github.com/weaveworks/common/user"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+type capturingPusher struct {
+	mtx     sync.Mutex
+	tenants []string
+	spans   int
+}
+
+func (p *capturingPusher) PushTraces(ctx context.Context, traces ptrace.Traces) (*tempopb.PushResponse, error) {
+	tenant, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.tenants = append(p.tenants, tenant)
+	p.spans += traces.SpanCount()
+	return &tempopb.PushResponse{}, nil
+}
+
+func TestReceiversShimOTLPHTTPJSON(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	pusher := &capturingPusher{}
+	shim, err := New(map[string]interface{}{
+		"otlp": map[string]interface{}{
+			"protocols": map[string]interface{}{
+				"http": map[string]interface{}{
+					"endpoint": endpoint,
+				},
+			},
+		},
+	}, pusher, MultiTenancyMiddleware(), logging.Level{})
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), shim))
+	// shut down the receivers directly, stopping the shim waits for pending requests
+	defer func() {
+		for _, r := range shim.(*receiversShim).receivers {
+			require.NoError(t, r.Shutdown(context.Background()))
+		}
+	}()
+
+	body := `{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"0102030405060708090a0b0c0d0e0f10","spanId":"0102030405060708","name":"test"}]}]}]}`
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/v1/traces", endpoint), strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(user.OrgIDHeaderName, "tenant")
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.DefaultClient.Do(req)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	pusher.mtx.Lock()
+	defer pusher.mtx.Unlock()
+	assert.Equal(t, []string{"tenant"}, pusher.tenants)
+	assert.Equal(t, 1, pusher.spans)
+}
@@ -0,0 +1,371 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/jsonpb" //nolint:all deprecated
+	"github.com/gogo/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// blockJobResponse is the response of a query evaluated on backend blocks.
+type blockJobResponse interface {
+	proto.Message
+	GetMetrics() *tempopb.SearchMetrics
+}
+
+// blockJobQuery is a query the blockJobSharder evaluates with jobs each covering a range of pages
+// of a single backend block.
+type blockJobQuery interface {
+	// timeRange returns the start and end of the query in unix nanoseconds
+	timeRange() (start, end uint64)
+	// jobRequest builds the request evaluating the query on the pages of the block in parent
+	jobRequest(parent *http.Request, block *tempopb.SearchBlockRequest) *http.Request
+	// newJobResponse returns the message the response of a job is read into
+	newJobResponse() blockJobResponse
+	// combine adds the response of a job to the result. It returns true once the result is complete
+	// and the remaining jobs can be abandoned. It's never called concurrently.
+	combine(resp blockJobResponse) bool
+	// response returns the combined result with the given metrics
+	response(metrics *tempopb.SearchMetrics) blockJobResponse
+}
+
+// parseBlockJobQueryFunc parses the query of a request. The error is returned to the client as a
+// bad request.
+type parseBlockJobQueryFunc func(r *http.Request) (blockJobQuery, error)
+
+type blockJobSharder struct {
+	next      http.RoundTripper
+	reader    tempodb.Reader
+	overrides overrides.Interface
+
+	cfg    SearchSharderConfig
+	logger log.Logger
+
+	// op is the operation of the sharded queries in the logs and metrics
+	op    string
+	parse parseBlockJobQueryFunc
+}
+
+// newBlockJobSharder creates a sharding middleware for the queries parsed by parse that are only
+// evaluated on backend blocks, each job covering a range of pages of a single block.
+func newBlockJobSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, logger log.Logger, op string, parse parseBlockJobQueryFunc) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return blockJobSharder{
+			next:      next,
+			reader:    reader,
+			overrides: o,
+			cfg:       cfg,
+			logger:    logger,
+			op:        op,
+			parse:     parse,
+		}
+	})
+}
+
+// RoundTrip implements http.RoundTripper
+// execute up to concurrentRequests simultaneously where each request scans ~targetMBsPerRequest
+// and combine the results until the query is complete
+func (s blockJobSharder) RoundTrip(r *http.Request) (*http.Response, error) {
+	query, err := s.parse(r)
+	if err != nil {
+		return badRequest(err), nil
+	}
+
+	ctx := r.Context()
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return badRequest(err), nil
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, "frontend.ShardBlockJobs")
+	defer span.Finish()
+	span.SetTag("op", s.op)
+
+	// calculate and enforce max search duration
+	start, end := query.timeRange()
+	maxDuration := s.maxDuration(tenantID)
+	if maxDuration != 0 && time.Duration(end-start) > maxDuration {
+		return badRequest(fmt.Errorf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, start/uint64(time.Second), end/uint64(time.Second))), nil
+	}
+
+	reqStart := time.Now()
+	// sub context to cancel in-progress sub requests
+	subCtx, subCancel := context.WithCancel(ctx)
+	defer subCancel()
+
+	blocks := s.blockMetas(start, end, tenantID)
+
+	var (
+		totalJobs       int
+		totalBlockBytes uint64
+	)
+	for _, b := range blocks {
+		p := pagesPerRequest(b, s.cfg.TargetBytesPerRequest)
+		if p == 0 {
+			continue
+		}
+
+		totalJobs += int(b.TotalRecords) / p
+		if int(b.TotalRecords)%p != 0 {
+			totalJobs++
+		}
+		totalBlockBytes += b.Size
+	}
+
+	reqCh := make(chan *backendReqMsg)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	go func() {
+		s.buildBackendRequests(subCtx, tenantID, r, query, blocks, reqCh, stopCh)
+	}()
+
+	var (
+		wg             = boundedwaitgroup.New(uint(s.cfg.ConcurrentRequests))
+		mtx            = sync.Mutex{}
+		firstErr       error
+		statusCode     = http.StatusOK
+		statusMsg      string
+		finished       int
+		inspectedBytes uint64
+		complete       bool
+	)
+
+	shouldQuit := func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return firstErr != nil || statusCode != http.StatusOK || complete
+	}
+
+	for req := range reqCh {
+		if req.err != nil {
+			return nil, fmt.Errorf("unexpected err building reqs: %w", req.err)
+		}
+
+		// a job failed or the result is complete, abandon the remaining ones
+		if shouldQuit() {
+			break
+		}
+
+		// When we hit capacity of boundedwaitgroup, wg.Add will block
+		wg.Add(1)
+
+		go func(innerR *http.Request) {
+			defer wg.Done()
+
+			resp, err := s.next.RoundTrip(innerR)
+			if err != nil {
+				// context cancelled error happens when we exit early.
+				// bail, and don't log and don't set this error.
+				if errors.Is(err, context.Canceled) {
+					_ = level.Debug(s.logger).Log("msg", "exiting early from sharded query", "url", innerR.RequestURI, "err", err)
+					return
+				}
+
+				_ = level.Error(s.logger).Log("msg", "error executing sharded query", "url", innerR.RequestURI, "err", err)
+				mtx.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			// if the status code is anything but happy, save the error and pass it down the line
+			if resp.StatusCode != http.StatusOK {
+				bytesMsg, err := io.ReadAll(resp.Body)
+				if err != nil {
+					_ = level.Error(s.logger).Log("msg", "error reading response body status != ok", "url", innerR.RequestURI, "err", err)
+				}
+				mtx.Lock()
+				if statusCode == http.StatusOK {
+					statusCode = resp.StatusCode
+					statusMsg = fmt.Sprintf("upstream: (%d) %s", resp.StatusCode, string(bytesMsg))
+				}
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			// successful query, read the body
+			results := query.newJobResponse()
+			err = (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(resp.Body, results)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error reading response body status == ok", "url", innerR.RequestURI, "err", err)
+				mtx.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mtx.Unlock()
+				subCancel()
+				return
+			}
+
+			// happy path
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			// jobs that finish after the result is complete are dropped
+			if complete {
+				return
+			}
+			complete = query.combine(results)
+			inspectedBytes += results.GetMetrics().GetInspectedBytes()
+			finished++
+
+			if complete {
+				subCancel()
+			}
+		}(req.req)
+	}
+
+	// wait for all goroutines running in wg to finish or cancelled
+	wg.Wait()
+
+	// all goroutines have finished, we can safely access the results directly now
+	metrics := &tempopb.SearchMetrics{
+		InspectedBytes:  inspectedBytes,
+		TotalBlocks:     uint32(len(blocks)),
+		TotalJobs:       uint32(totalJobs),
+		CompletedJobs:   uint32(finished),
+		TotalBlockBytes: totalBlockBytes,
+	}
+	res := query.response(metrics)
+
+	reqTime := time.Since(reqStart)
+	throughput := float64(metrics.InspectedBytes) / reqTime.Seconds()
+	queryThroughput.WithLabelValues(tenantID, s.op).Observe(throughput)
+
+	rawQuery, _ := url.PathUnescape(r.URL.RawQuery)
+	span.SetTag("query", rawQuery)
+	level.Info(s.logger).Log(
+		"msg", "sharded block jobs request stats",
+		"op", s.op,
+		"query", rawQuery,
+		"duration_seconds", reqTime,
+		"request_throughput", throughput,
+		"total_requests", totalJobs,
+		"finished_requests", finished,
+		"totalBlocks", metrics.TotalBlocks,
+		"inspectedBytes", metrics.InspectedBytes,
+		"totalBlockBytes", metrics.TotalBlockBytes)
+
+	span.SetTag("totalBlocks", metrics.TotalBlocks)
+	span.SetTag("inspectedBytes", metrics.InspectedBytes)
+	span.SetTag("totalBlockBytes", metrics.TotalBlockBytes)
+	span.SetTag("totalJobs", totalJobs)
+	span.SetTag("finishedJobs", finished)
+	span.SetTag("requestThroughput", throughput)
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if statusCode != http.StatusOK {
+		// translate all non-200s into 500s. if, for instance, we get a 400 back from an internal component
+		// it means that we created a bad request. 400 should not be propagated back to the user b/c
+		// the bad request was due to a bug on our side, so return 500 instead.
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(statusMsg)),
+		}, nil
+	}
+
+	m := &jsonpb.Marshaler{}
+	bodyString, err := m.MarshalToString(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+		},
+		Body:          io.NopCloser(strings.NewReader(bodyString)),
+		ContentLength: int64(len([]byte(bodyString))),
+	}, nil
+}
+
+// blockMetas returns all backend blocks overlapping the given start/end in unix nanoseconds
+func (s *blockJobSharder) blockMetas(start, end uint64, tenantID string) []*backend.BlockMeta {
+	allMetas := s.reader.BlockMetas(tenantID)
+	metas := make([]*backend.BlockMeta, 0, len(allMetas)/50) // divide by 50 for luck
+	for _, m := range allMetas {
+		if uint64(m.StartTime.UnixNano()) <= end &&
+			uint64(m.EndTime.UnixNano()) >= start {
+			metas = append(metas, m)
+		}
+	}
+
+	return metas
+}
+
+// buildBackendRequests sends requests covering all pages of the given blocks to reqCh. It takes
+// ownership of reqCh and closes it.
+func (s *blockJobSharder) buildBackendRequests(ctx context.Context, tenantID string, parent *http.Request, query blockJobQuery, metas []*backend.BlockMeta, reqCh chan<- *backendReqMsg, stopCh <-chan struct{}) {
+	defer close(reqCh)
+
+	for _, m := range metas {
+		pages := pagesPerRequest(m, s.cfg.TargetBytesPerRequest)
+		if pages == 0 {
+			continue
+		}
+
+		for startPage := 0; startPage < int(m.TotalRecords); startPage += pages {
+			subR := parent.Clone(ctx)
+			subR.Header.Set(user.OrgIDHeaderName, tenantID)
+			// the queriers answer in protobuf if the client asked for it
+			subR.Header.Set(api.HeaderAccept, api.HeaderAcceptJSON)
+
+			subR = query.jobRequest(subR, blockRequest(m, startPage, pages))
+
+			subR.RequestURI = buildUpstreamRequestURI(parent.URL.Path, subR.URL.Query())
+
+			select {
+			case reqCh <- &backendReqMsg{req: subR}:
+			case <-stopCh:
+				return
+			}
+		}
+	}
+}
+
+// maxDuration returns the max time range allowed for this tenant.
+func (s *blockJobSharder) maxDuration(tenantID string) time.Duration {
+	// check overrides first, if no overrides then grab from our config
+	maxDuration := s.overrides.MaxSearchDuration(tenantID)
+	if maxDuration != 0 {
+		return maxDuration
+	}
+
+	return s.cfg.MaxDuration
+}
+
+func badRequest(err error) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(err.Error())),
+	}
+}
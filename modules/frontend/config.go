@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/tempo/modules/frontend/transport"
 	v1 "github.com/grafana/tempo/modules/frontend/v1"
+	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/usagestats"
 )
 
@@ -27,6 +28,7 @@ type Config struct {
 type SearchConfig struct {
 	Sharder SearchSharderConfig `yaml:",inline"`
 	SLO     SLOConfig           `yaml:",inline"`
+	Cache   SearchCacheConfig   `yaml:"cache"`
 }
 
 type TraceByIDConfig struct {
@@ -65,6 +67,12 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 			TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		},
 		SLO: slo,
+		Cache: SearchCacheConfig{
+			BackgroundCache: &cache.BackgroundConfig{
+				WriteBackBuffer:     10000,
+				WriteBackGoroutines: 10,
+			},
+		},
 	}
 	cfg.TraceByID = TraceByIDConfig{
 		QueryShards: 50,
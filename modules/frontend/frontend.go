@@ -19,29 +19,36 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/weaveworks/common/user"
 
+	"github.com/grafana/tempo/modules/frontend/savedqueries"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/tempodb"
 )
 
 const (
-	traceByIDOp  = "traces"
-	searchOp     = "search"
-	searchTagsOp = "searchtags"
-	metricsOp    = "metrics"
+	traceByIDOp    = "traces"
+	traceDiffOp    = "tracediff"
+	criticalPathOp = "criticalpath"
+	searchOp       = "search"
+	searchTagsOp   = "searchtags"
+	metricsOp      = "metrics"
+
+	metricsQueryRangeOp = "metrics_query_range"
+	traceSummaryOp      = "trace_summary"
 )
 
 type streamingSearchHandler func(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error
 
 type QueryFrontend struct {
-	TraceByIDHandler, SearchHandler, SearchTagsHandler, SpanMetricsSummaryHandler http.Handler
-	streamingSearch                                                               streamingSearchHandler
-	logger                                                                        log.Logger
+	TraceByIDHandler, TraceDiffHandler, TraceCriticalPathHandler, SearchHandler, SearchTagsHandler, SpanMetricsSummaryHandler, QueryRangeHandler, TraceSummaryHandler, SavedQueriesHandler http.Handler
+	streamingSearch                                                                                                                                                                        streamingSearchHandler
+	logger                                                                                                                                                                                 log.Logger
 }
 
-// New returns a new QueryFrontend
-func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempodb.Reader, apiPrefix string, logger log.Logger, registerer prometheus.Registerer) (*QueryFrontend, error) {
+// New returns a new QueryFrontend. Saved queries are disabled if savedQueries is nil.
+func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempodb.Reader, savedQueries *savedqueries.Store, apiPrefix string, logger log.Logger, registerer prometheus.Registerer) (*QueryFrontend, error) {
 	level.Info(logger).Log("msg", "creating middleware in query frontend")
 
 	if cfg.TraceByID.QueryShards < minQueryShards || cfg.TraceByID.QueryShards > maxQueryShards {
@@ -60,6 +67,11 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 		return nil, fmt.Errorf("query backend after should be less than or equal to query ingester until")
 	}
 
+	searchCache, err := newSearchCache(cfg.Search.Cache, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	queriesPerTenant := promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tempo",
 		Name:      "query_frontend_queries_total",
@@ -70,27 +82,49 @@ func New(cfg Config, next http.RoundTripper, o overrides.Interface, reader tempo
 
 	// tracebyid middleware
 	traceByIDMiddleware := MergeMiddlewares(newTraceByIDMiddleware(cfg, logger), retryWare)
-	searchMiddleware := MergeMiddlewares(newSearchMiddleware(cfg, o, reader, logger), retryWare)
+	traceDiffMiddleware := MergeMiddlewares(newTraceDiffMiddleware(cfg, logger), retryWare)
+	criticalPathMiddleware := MergeMiddlewares(newTraceCriticalPathMiddleware(cfg, logger), retryWare)
+	searchMiddleware := MergeMiddlewares(newSearchMiddleware(cfg, o, reader, searchCache, savedQueries, logger), retryWare)
 	searchTagsMiddleware := MergeMiddlewares(newSearchTagsMiddleware(cfg, o, reader, logger), retryWare)
 
 	spanMetricsMiddleware := MergeMiddlewares(newSpanMetricsMiddleware(cfg, o, reader, logger), retryWare)
+	queryRangeMiddleware := MergeMiddlewares(newQueryRangeMiddleware(cfg, o, reader, logger), retryWare)
+	traceSummaryMiddleware := MergeMiddlewares(newTraceSummaryMiddleware(cfg, o, reader, logger), retryWare)
 
 	traceByIDCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": traceByIDOp})
+	traceDiffCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": traceDiffOp})
+	criticalPathCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": criticalPathOp})
 	searchCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": searchOp})
 	searchTagsCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": searchTagsOp})
 	spanMetricsCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": metricsOp})
+	queryRangeCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": metricsQueryRangeOp})
+	traceSummaryCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{"op": traceSummaryOp})
 
 	traces := traceByIDMiddleware.Wrap(next)
+	traceDiff := traceDiffMiddleware.Wrap(next)
+	criticalPath := criticalPathMiddleware.Wrap(next)
 	search := searchMiddleware.Wrap(next)
 	searchTags := searchTagsMiddleware.Wrap(next)
 	metrics := spanMetricsMiddleware.Wrap(next)
+	queryRange := queryRangeMiddleware.Wrap(next)
+	traceSummary := traceSummaryMiddleware.Wrap(next)
+
+	var savedQueriesHandler http.Handler
+	if savedQueries != nil {
+		savedQueriesHandler = savedqueries.NewHandler(savedQueries, logger)
+	}
 
 	return &QueryFrontend{
 		TraceByIDHandler:          newHandler(traces, traceByIDCounter, logger),
+		TraceDiffHandler:          newHandler(traceDiff, traceDiffCounter, logger),
+		TraceCriticalPathHandler:  newHandler(criticalPath, criticalPathCounter, logger),
 		SearchHandler:             newHandler(search, searchCounter, logger),
 		SearchTagsHandler:         newHandler(searchTags, searchTagsCounter, logger),
 		SpanMetricsSummaryHandler: newHandler(metrics, spanMetricsCounter, logger),
-		streamingSearch:           newSearchStreamingHandler(cfg, o, retryWare.Wrap(next), reader, apiPrefix, logger),
+		QueryRangeHandler:         newHandler(queryRange, queryRangeCounter, logger),
+		TraceSummaryHandler:       newHandler(traceSummary, traceSummaryCounter, logger),
+		SavedQueriesHandler:       savedQueriesHandler,
+		streamingSearch:           newSearchStreamingHandler(cfg, o, retryWare.Wrap(next), reader, searchCache, savedQueries, apiPrefix, logger),
 		logger:                    logger,
 	}, nil
 }
@@ -188,10 +222,70 @@ func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
 	})
 }
 
+// newTraceDiffMiddleware creates a new frontend middleware to handle trace diff requests.
+func newTraceDiffMiddleware(cfg Config, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		querierRT := next
+
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			// validate trace ids and start and end parameter
+			_, _, err := api.ParseTraceDiffRequest(r)
+			if err == nil {
+				_, _, _, _, _, err = api.ValidateAndSanitizeRequest(r)
+			}
+			if err != nil {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       io.NopCloser(strings.NewReader(err.Error())),
+					Header:     http.Header{},
+				}, nil
+			}
+
+			// both traces are retrieved and compared by a single querier
+			orgID, _ := user.ExtractOrgID(r.Context())
+
+			r.Header.Set(user.OrgIDHeaderName, orgID)
+			r.RequestURI = buildUpstreamRequestURI(r.RequestURI, nil)
+
+			return querierRT.RoundTrip(r)
+		})
+	})
+}
+
+// newTraceCriticalPathMiddleware creates a new frontend middleware to handle critical path requests.
+func newTraceCriticalPathMiddleware(cfg Config, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		querierRT := next
+
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			// validate trace id and start and end parameter
+			_, err := api.ParseTraceID(r)
+			if err == nil {
+				_, _, _, _, _, err = api.ValidateAndSanitizeRequest(r)
+			}
+			if err != nil {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       io.NopCloser(strings.NewReader(err.Error())),
+					Header:     http.Header{},
+				}, nil
+			}
+
+			// the critical path needs the complete trace and is computed by a single querier
+			orgID, _ := user.ExtractOrgID(r.Context())
+
+			r.Header.Set(user.OrgIDHeaderName, orgID)
+			r.RequestURI = buildUpstreamRequestURI(r.RequestURI, nil)
+
+			return querierRT.RoundTrip(r)
+		})
+	})
+}
+
 // newSearchMiddleware creates a new frontend middleware to handle search and search tags requests.
-func newSearchMiddleware(cfg Config, o overrides.Interface, reader tempodb.Reader, logger log.Logger) Middleware {
+func newSearchMiddleware(cfg Config, o overrides.Interface, reader tempodb.Reader, c cache.Cache, sq *savedqueries.Store, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
-		searchRT := NewRoundTripper(next, newSearchSharder(reader, o, cfg.Search.Sharder, cfg.Search.SLO, newSearchProgress, logger))
+		searchRT := NewRoundTripper(next, newSearchSharder(reader, o, cfg.Search.Sharder, cfg.Search.SLO, c, sq, newSearchProgress, logger))
 
 		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
 			// backend search queries require sharding, so we pass through a special roundtripper
@@ -234,6 +328,22 @@ func newSpanMetricsMiddleware(cfg Config, o overrides.Interface, reader tempodb.
 	})
 }
 
+// newQueryRangeMiddleware creates a new frontend middleware to handle TraceQL metrics query range requests.
+func newQueryRangeMiddleware(cfg Config, o overrides.Interface, reader tempodb.Reader, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		// backend metrics queries require sharding, so we pass through a special roundtripper
+		return NewRoundTripper(next, newQueryRangeSharder(reader, o, cfg.Search.Sharder, logger))
+	})
+}
+
+// newTraceSummaryMiddleware creates a new frontend middleware to handle trace summary requests.
+func newTraceSummaryMiddleware(cfg Config, o overrides.Interface, reader tempodb.Reader, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		// trace summaries are built from backend blocks and require sharding, so we pass through a special roundtripper
+		return NewRoundTripper(next, newTraceSummarySharder(reader, o, cfg.Search.Sharder, logger))
+	})
+}
+
 // buildUpstreamRequestURI returns a uri based on the passed parameters
 // we do this because weaveworks/common uses the RequestURI field to translate from http.Request to httpgrpc.Request
 // https://github.com/weaveworks/common/blob/47e357f4e1badb7da17ad74bae63e228bdd76e8f/httpgrpc/server/server.go#L48
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,7 +37,7 @@ func TestFrontendRoundTripsSearch(t *testing.T) {
 			},
 			SLO: testSLOcfg,
 		},
-	}, next, nil, nil, "", log.NewNopLogger(), nil)
+	}, next, nil, nil, nil, "", log.NewNopLogger(), nil)
 	require.NoError(t, err)
 
 	req := httptest.NewRequest("GET", "/", nil)
@@ -50,6 +51,24 @@ func TestFrontendRoundTripsSearch(t *testing.T) {
 	resSearch := httptest.NewRecorder()
 	f.SearchHandler.ServeHTTP(resSearch, req)
 	assert.Equal(t, resSearch.Body.String(), "no org id")
+
+	// trace diff is a pass through after validating the trace ids
+	resDiff := httptest.NewRecorder()
+	f.TraceDiffHandler.ServeHTTP(resDiff, httptest.NewRequest("GET", "/api/traces/diff?a=1234&b=5678", nil))
+	assert.Equal(t, resDiff.Body.String(), "next")
+
+	resDiff = httptest.NewRecorder()
+	f.TraceDiffHandler.ServeHTTP(resDiff, httptest.NewRequest("GET", "/api/traces/diff?a=1234", nil))
+	assert.Equal(t, http.StatusBadRequest, resDiff.Code)
+
+	// critical path is a pass through after validating the trace id
+	resCriticalPath := httptest.NewRecorder()
+	f.TraceCriticalPathHandler.ServeHTTP(resCriticalPath, mux.SetURLVars(httptest.NewRequest("GET", "/api/traces/1234/criticalpath", nil), map[string]string{"traceID": "1234"}))
+	assert.Equal(t, resCriticalPath.Body.String(), "next")
+
+	resCriticalPath = httptest.NewRecorder()
+	f.TraceCriticalPathHandler.ServeHTTP(resCriticalPath, httptest.NewRequest("GET", "/api/traces/1234/criticalpath", nil))
+	assert.Equal(t, http.StatusBadRequest, resCriticalPath.Code)
 }
 
 func TestFrontendBadConfigFails(t *testing.T) {
@@ -64,7 +83,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 			},
 			SLO: testSLOcfg,
 		},
-	}, nil, nil, nil, "", log.NewNopLogger(), nil)
+	}, nil, nil, nil, nil, "", log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend query shards should be between 2 and 100000 (both inclusive)")
 
 	assert.Nil(t, f)
@@ -81,7 +100,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 			},
 			SLO: testSLOcfg,
 		},
-	}, nil, nil, nil, "", log.NewNopLogger(), nil)
+	}, nil, nil, nil, nil, "", log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend query shards should be between 2 and 100000 (both inclusive)")
 	assert.Nil(t, f)
 
@@ -97,7 +116,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 			},
 			SLO: testSLOcfg,
 		},
-	}, nil, nil, nil, "", log.NewNopLogger(), nil)
+	}, nil, nil, nil, nil, "", log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend search concurrent requests should be greater than 0")
 	assert.Nil(t, f)
 
@@ -113,7 +132,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 			},
 			SLO: testSLOcfg,
 		},
-	}, nil, nil, nil, "", log.NewNopLogger(), nil)
+	}, nil, nil, nil, nil, "", log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend search target bytes per request should be greater than 0")
 	assert.Nil(t, f)
 
@@ -131,7 +150,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 			},
 			SLO: testSLOcfg,
 		},
-	}, nil, nil, nil, "", log.NewNopLogger(), nil)
+	}, nil, nil, nil, nil, "", log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "query backend after should be less than or equal to query ingester until")
 	assert.Nil(t, f)
 }
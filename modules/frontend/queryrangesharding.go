@@ -0,0 +1,82 @@
+package frontend
+
+import (
+	"net/http"
+
+	"github.com/go-kit/log"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb"
+)
+
+// queryRangeQuery combines the time series of the jobs of a TraceQL metrics query.
+type queryRangeQuery struct {
+	req      *tempopb.QueryRangeRequest
+	combiner *traceql.QueryRangeCombiner
+}
+
+// newQueryRangeSharder creates a sharding middleware for TraceQL metrics queries. Metrics
+// are only computed from backend blocks, each job covering a range of pages of a single block.
+func newQueryRangeSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, logger log.Logger) Middleware {
+	return newBlockJobSharder(reader, o, cfg, logger, metricsQueryRangeOp, parseQueryRangeQuery)
+}
+
+func parseQueryRangeQuery(r *http.Request) (blockJobQuery, error) {
+	queryRangeReq, err := api.ParseQueryRangeRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The jobs are built from the original request and each querier aligns it
+	// the same way, so the combiner works on an aligned copy.
+	alignedReq := *queryRangeReq
+	traceql.AlignRequest(&alignedReq)
+
+	// validate the query up front to return a 400 instead of failing every job
+	if _, err = traceql.NewEngine().CompileMetricsQueryRange(&alignedReq); err != nil {
+		return nil, err
+	}
+
+	combiner, err := traceql.NewQueryRangeCombiner(&alignedReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &queryRangeQuery{
+		req:      queryRangeReq,
+		combiner: combiner,
+	}, nil
+}
+
+func (q *queryRangeQuery) timeRange() (uint64, uint64) {
+	return q.req.Start, q.req.End
+}
+
+func (q *queryRangeQuery) jobRequest(parent *http.Request, block *tempopb.SearchBlockRequest) *http.Request {
+	return api.BuildQueryRangeRequest(parent, &tempopb.QueryRangeRequest{
+		Query: q.req.Query,
+		Start: q.req.Start,
+		End:   q.req.End,
+		Step:  q.req.Step,
+		Block: block,
+	})
+}
+
+func (q *queryRangeQuery) newJobResponse() blockJobResponse {
+	return &tempopb.QueryRangeResponse{}
+}
+
+// combine never completes the result, the series of all blocks are needed
+func (q *queryRangeQuery) combine(resp blockJobResponse) bool {
+	q.combiner.Combine(resp.(*tempopb.QueryRangeResponse))
+	return false
+}
+
+func (q *queryRangeQuery) response(metrics *tempopb.SearchMetrics) blockJobResponse {
+	res := q.combiner.Response()
+	res.Metrics = metrics
+	return res
+}
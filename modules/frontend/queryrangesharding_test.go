@@ -0,0 +1,155 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestQueryRangeSharderRoundTrip(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		blockIDs []string
+	)
+
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		q, err := url.ParseQuery(strings.Split(r.RequestURI, "?")[1])
+		require.NoError(t, err)
+
+		mtx.Lock()
+		blockIDs = append(blockIDs, q.Get("blockID"))
+		mtx.Unlock()
+
+		// every job returns the same series
+		resString, err := (&jsonpb.Marshaler{}).MarshalToString(&tempopb.QueryRangeResponse{
+			Series: []*tempopb.TimeSeries{
+				{
+					PromLabels: `{span.foo="bar"}`,
+					Labels:     []v1.KeyValue{{Key: "span.foo", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "bar"}}}},
+					Samples:    []tempopb.Sample{{TimestampMs: 1020_000, Value: 1}},
+				},
+			},
+			Metrics: &tempopb.SearchMetrics{InspectedBytes: 10},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(resString)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	sharder := newQueryRangeSharder(&mockReader{
+		metas: []*backend.BlockMeta{
+			{ // two jobs
+				StartTime:    time.Unix(1000, 0),
+				EndTime:      time.Unix(1100, 0),
+				Size:         defaultTargetBytesPerRequest * 2,
+				TotalRecords: 2,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+			},
+			{ // outside of the range
+				StartTime:    time.Unix(2000, 0),
+				EndTime:      time.Unix(2100, 0),
+				Size:         defaultTargetBytesPerRequest,
+				TotalRecords: 1,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			},
+		},
+	}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	req := httptest.NewRequest("GET", "/?q="+url.QueryEscape("{} | count_over_time() by(span.foo)")+"&start=1000&end=1060&step=30s", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+	resp, err := testRT.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	actualResp := &tempopb.QueryRangeResponse{}
+	bytesResp, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(bytesResp), actualResp))
+
+	assert.Equal(t, []string{"00000000-0000-0000-0000-000000000000", "00000000-0000-0000-0000-000000000000"}, blockIDs)
+	assert.Equal(t, &tempopb.QueryRangeResponse{
+		Series: []*tempopb.TimeSeries{
+			{
+				PromLabels: `{span.foo="bar"}`,
+				Labels:     []v1.KeyValue{{Key: "span.foo", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "bar"}}}},
+				Samples: []tempopb.Sample{
+					{TimestampMs: 990_000, Value: 0},
+					{TimestampMs: 1020_000, Value: 2},
+					{TimestampMs: 1050_000, Value: 0},
+				},
+			},
+		},
+		Metrics: &tempopb.SearchMetrics{
+			InspectedBytes:  20,
+			TotalBlocks:     1,
+			TotalJobs:       2,
+			CompletedJobs:   2,
+			TotalBlockBytes: defaultTargetBytesPerRequest * 2,
+		},
+	}, actualResp)
+}
+
+func TestQueryRangeSharderRoundTripBadRequest(t *testing.T) {
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	sharder := newQueryRangeSharder(&mockReader{}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		MaxDuration:           5 * time.Minute,
+	}, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	query := url.QueryEscape("{} | rate()")
+
+	// no org id
+	req := httptest.NewRequest("GET", "/?q="+query+"&start=1000&end=1100", nil)
+	resp, err := testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "no org id")
+
+	// start/end outside of max duration
+	req = httptest.NewRequest("GET", "/?q="+query+"&start=1000&end=1500", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "range specified by start and end exceeds 5m0s. received start=1000 end=1500")
+
+	// not a metrics query
+	req = httptest.NewRequest("GET", "/?q="+url.QueryEscape("{}")+"&start=1000&end=1100", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "query is not a metrics query")
+}
@@ -0,0 +1,130 @@
+package savedqueries
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+)
+
+// maxBodyBytes is the maximum size of the body of a request storing a saved query
+const maxBodyBytes = 64 * 1024
+
+// Handler serves the saved queries API:
+//   - GET /api/saved-queries lists the saved queries of the tenant
+//   - GET /api/saved-queries/{name} returns a saved query
+//   - PUT /api/saved-queries/{name} creates or replaces a saved query
+//   - DELETE /api/saved-queries/{name} removes a saved query
+type Handler struct {
+	store  *Store
+	logger log.Logger
+}
+
+// NewHandler returns a Handler for the saved queries of the store.
+func NewHandler(store *Store, logger log.Logger) *Handler {
+	return &Handler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// requests to /api/saved-queries don't have a name and list the saved queries
+	name, err := api.ParseSavedQueryName(r)
+	if err != nil {
+		if r.Method != http.MethodGet {
+			http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		h.list(w, r, tenantID)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r, tenantID, name)
+	case http.MethodPut:
+		h.put(w, r, tenantID, name)
+	case http.MethodDelete:
+		h.delete(w, r, tenantID, name)
+	default:
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request, tenantID string) {
+	queries, err := h.store.List(r.Context(), tenantID)
+	if err != nil {
+		h.writeError(w, tenantID, err)
+		return
+	}
+	if queries == nil {
+		queries = []SavedQuery{}
+	}
+	writeJSON(w, http.StatusOK, savedQueries{Queries: queries})
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, tenantID, name string) {
+	q, err := h.store.Get(r.Context(), tenantID, name)
+	if err != nil {
+		h.writeError(w, tenantID, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, q)
+}
+
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, tenantID, name string) {
+	q := SavedQuery{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodyBytes)).Decode(&q); err != nil {
+		http.Error(w, fmt.Sprintf("invalid saved query: %s", err), http.StatusBadRequest)
+		return
+	}
+	// the name of the path takes precedence
+	q.Name = name
+
+	if err := q.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid saved query: %s", err), http.StatusBadRequest)
+		return
+	}
+	if err := h.store.Put(r.Context(), tenantID, q); err != nil {
+		h.writeError(w, tenantID, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, q)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, tenantID, name string) {
+	if err := h.store.Delete(r.Context(), tenantID, name); err != nil {
+		h.writeError(w, tenantID, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, tenantID string, err error) {
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	level.Error(h.logger).Log("msg", "saved queries request failed", "tenant", tenantID, "err", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,66 @@
+package savedqueries
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+)
+
+func TestHandler(t *testing.T) {
+	router := mux.NewRouter()
+	h := NewHandler(newTestStore(t), log.NewNopLogger())
+	router.Handle(api.PathSavedQueries, h)
+	router.Handle(api.PathSavedQuery, h)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(user.InjectOrgID(context.Background(), "tenant"))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/api/saved-queries", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"queries":[]}`, rec.Body.String())
+
+	rec = do(http.MethodPut, "/api/saved-queries/errors", `{"query":"{ status = error }","description":"errors"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"errors","query":"{ status = error }","description":"errors"}`, rec.Body.String())
+
+	rec = do(http.MethodGet, "/api/saved-queries/errors", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"errors","query":"{ status = error }","description":"errors"}`, rec.Body.String())
+
+	rec = do(http.MethodGet, "/api/saved-queries", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"queries":[{"name":"errors","query":"{ status = error }","description":"errors"}]}`, rec.Body.String())
+
+	rec = do(http.MethodPut, "/api/saved-queries/invalid", `{"query":"{ status = "}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(http.MethodPut, "/api/saved-queries/invalid", `not json`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(http.MethodPost, "/api/saved-queries", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = do(http.MethodDelete, "/api/saved-queries/errors", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = do(http.MethodDelete, "/api/saved-queries/errors", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = do(http.MethodGet, "/api/saved-queries/errors", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
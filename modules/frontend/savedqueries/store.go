@@ -0,0 +1,174 @@
+package savedqueries
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+)
+
+const maxNameLength = 128
+
+var (
+	ErrNotFound = errors.New("saved query not found")
+
+	validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+)
+
+// SavedQuery is a named TraceQL query of a tenant.
+type SavedQuery struct {
+	Name        string `json:"name"`
+	Query       string `json:"query"`
+	Description string `json:"description,omitempty"`
+}
+
+// Validate checks the name and the query of a saved query.
+func (q *SavedQuery) Validate() error {
+	if len(q.Name) > maxNameLength {
+		return fmt.Errorf("name %s is longer than %d characters", q.Name, maxNameLength)
+	}
+	if !validName.MatchString(q.Name) {
+		return fmt.Errorf("invalid name %q, names must start with a letter or digit and only contain letters, digits, '_', '.' and '-'", q.Name)
+	}
+	if q.Query == "" {
+		return errors.New("query is empty")
+	}
+	// {} isn't handled by the parser yet, see api.ParseSearchRequest
+	if q.Query != "{}" {
+		if _, err := traceql.Parse(q.Query); err != nil {
+			return fmt.Errorf("invalid TraceQL query: %w", err)
+		}
+	}
+	return nil
+}
+
+// savedQueries is the object storing the saved queries of a tenant
+type savedQueries struct {
+	Queries []SavedQuery `json:"queries"`
+}
+
+// Store persists the saved queries of each tenant in a single object of the backend beneath the
+// tenant, next to its blocks and tenant index. Changes are serialized within a process. Concurrent
+// changes of other processes are not detected, the last write wins.
+type Store struct {
+	r backend.RawReader
+	w backend.RawWriter
+
+	mtx sync.Mutex
+}
+
+// NewStore returns a Store reading and writing the saved queries with the passed backend.
+func NewStore(r backend.RawReader, w backend.RawWriter) *Store {
+	return &Store{
+		r: r,
+		w: w,
+	}
+}
+
+// List returns the saved queries of the tenant sorted by name.
+func (s *Store) List(ctx context.Context, tenantID string) ([]SavedQuery, error) {
+	queries, err := s.read(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return queries.Queries, nil
+}
+
+// Get returns the saved query with the given name or ErrNotFound.
+func (s *Store) Get(ctx context.Context, tenantID, name string) (*SavedQuery, error) {
+	queries, err := s.read(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := queries.find(name); ok {
+		return &queries.Queries[i], nil
+	}
+	return nil, ErrNotFound
+}
+
+// Put creates or replaces a saved query.
+func (s *Store) Put(ctx context.Context, tenantID string, q SavedQuery) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	queries, err := s.read(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if i, ok := queries.find(q.Name); ok {
+		queries.Queries[i] = q
+	} else {
+		queries.Queries = append(queries.Queries, q)
+		sort.Slice(queries.Queries, func(i, j int) bool {
+			return queries.Queries[i].Name < queries.Queries[j].Name
+		})
+	}
+	return s.write(ctx, tenantID, queries)
+}
+
+// Delete removes a saved query or returns ErrNotFound.
+func (s *Store) Delete(ctx context.Context, tenantID, name string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	queries, err := s.read(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	i, ok := queries.find(name)
+	if !ok {
+		return ErrNotFound
+	}
+	queries.Queries = append(queries.Queries[:i], queries.Queries[i+1:]...)
+	return s.write(ctx, tenantID, queries)
+}
+
+func (s *Store) read(ctx context.Context, tenantID string) (*savedQueries, error) {
+	reader, size, err := s.r.Read(ctx, backend.SavedQueriesName, backend.KeyPath{tenantID}, false)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return &savedQueries{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	b, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := &savedQueries{}
+	if err := json.Unmarshal(b, queries); err != nil {
+		return nil, fmt.Errorf("error unmarshalling saved queries: %w", err)
+	}
+	return queries, nil
+}
+
+func (s *Store) write(ctx context.Context, tenantID string, queries *savedQueries) error {
+	b, err := json.Marshal(queries)
+	if err != nil {
+		return err
+	}
+	return s.w.Write(ctx, backend.SavedQueriesName, backend.KeyPath{tenantID}, bytes.NewReader(b), int64(len(b)), false)
+}
+
+func (q *savedQueries) find(name string) (int, bool) {
+	i := sort.Search(len(q.Queries), func(i int) bool {
+		return q.Queries[i].Name >= name
+	})
+	return i, i < len(q.Queries) && q.Queries[i].Name == name
+}
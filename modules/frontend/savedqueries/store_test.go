@@ -0,0 +1,119 @@
+package savedqueries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func newTestStore(t *testing.T) *Store {
+	r, w, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	return NewStore(r, w)
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	queries, err := s.List(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Empty(t, queries)
+
+	_, err = s.Get(ctx, "tenant", "errors")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	errors := SavedQuery{Name: "errors", Query: "{ status = error }"}
+	slow := SavedQuery{Name: "slow", Query: "{ duration > 2s }", Description: "slow spans"}
+	require.NoError(t, s.Put(ctx, "tenant", slow))
+	require.NoError(t, s.Put(ctx, "tenant", errors))
+
+	queries, err = s.List(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Equal(t, []SavedQuery{errors, slow}, queries)
+
+	// other tenants don't see the queries
+	queries, err = s.List(ctx, "other")
+	require.NoError(t, err)
+	assert.Empty(t, queries)
+
+	// replace
+	errors.Query = `{ status = error && resource.service.name = "billing" }`
+	require.NoError(t, s.Put(ctx, "tenant", errors))
+
+	q, err := s.Get(ctx, "tenant", "errors")
+	require.NoError(t, err)
+	assert.Equal(t, errors, *q)
+
+	// delete
+	require.NoError(t, s.Delete(ctx, "tenant", "errors"))
+	assert.ErrorIs(t, s.Delete(ctx, "tenant", "errors"), ErrNotFound)
+
+	queries, err = s.List(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Equal(t, []SavedQuery{slow}, queries)
+
+	// invalid queries are rejected
+	assert.Error(t, s.Put(ctx, "tenant", SavedQuery{Name: "invalid", Query: "{ status = "}))
+}
+
+func TestStoreDoesntBreakBlocklist(t *testing.T) {
+	ctx := context.Background()
+	r, w, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, NewStore(r, w).Put(ctx, "tenant", SavedQuery{Name: "errors", Query: "{ status = error }"}))
+
+	blocks, err := backend.NewReader(r).Blocks(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+}
+
+func TestSavedQueryValidate(t *testing.T) {
+	tcs := []struct {
+		name  string
+		query SavedQuery
+		err   string
+	}{
+		{
+			name:  "valid",
+			query: SavedQuery{Name: "billing-errors_v1.2", Query: "{ status = error }"},
+		},
+		{
+			name:  "empty query",
+			query: SavedQuery{Name: "query"},
+			err:   "query is empty",
+		},
+		{
+			name:  "empty name",
+			query: SavedQuery{Query: "{ status = error }"},
+			err:   `invalid name "", names must start with a letter or digit and only contain letters, digits, '_', '.' and '-'`,
+		},
+		{
+			name:  "invalid name",
+			query: SavedQuery{Name: "../index.json.gz", Query: "{ status = error }"},
+			err:   `invalid name "../index.json.gz", names must start with a letter or digit and only contain letters, digits, '_', '.' and '-'`,
+		},
+		{
+			name:  "invalid query",
+			query: SavedQuery{Name: "query", Query: "{ status = }"},
+			err:   "invalid TraceQL query: parse error at line 1, col 12: syntax error: unexpected }",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.query.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
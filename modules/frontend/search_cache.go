@@ -0,0 +1,176 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/cache/memcached"
+	"github.com/grafana/tempo/tempodb/backend/cache/redis"
+)
+
+const searchCacheName = "frontend-search"
+
+var searchCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_search_cache_requests_total",
+	Help:      "Total number of search jobs looked up in the search results cache by result.",
+}, []string{"result"})
+
+// SearchCacheConfig configures the cache of the results of search jobs of backend blocks.
+type SearchCacheConfig struct {
+	// Backend is the cache used for search results: redis, memcached or empty to disable the cache
+	Backend         string                  `yaml:"backend"`
+	BackgroundCache *cache.BackgroundConfig `yaml:"background_cache"`
+	Memcached       *memcached.Config       `yaml:"memcached"`
+	Redis           *redis.Config           `yaml:"redis"`
+}
+
+// newSearchCache returns the configured search results cache or nil if the cache is disabled.
+func newSearchCache(cfg SearchCacheConfig, logger log.Logger) (cache.Cache, error) {
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case "redis":
+		if cfg.Redis == nil {
+			return nil, fmt.Errorf("search cache backend redis requires redis config")
+		}
+		return redis.NewClient(cfg.Redis, cfg.BackgroundCache, searchCacheName, logger), nil
+	case "memcached":
+		if cfg.Memcached == nil {
+			return nil, fmt.Errorf("search cache backend memcached requires memcached config")
+		}
+		return memcached.NewClient(cfg.Memcached, cfg.BackgroundCache, searchCacheName, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown search cache backend %s", cfg.Backend)
+	}
+}
+
+// searchJobCacheKeyFunc returns the cache key of a search job covering pages [startPage, startPage+pages)
+// of a backend block.
+type searchJobCacheKeyFunc func(m *backend.BlockMeta, startPage, pages int) string
+
+// newSearchJobCacheKeyFunc returns the function computing the cache keys of the backend jobs of a search
+// request or nil if the results of the request must not be cached. Explained searches are not cached,
+// their jobs must run to return the statistics of the iterators. Blocks are immutable so the result of a
+// job only depends on the block, the pages and the search parameters. The time range is only part of the
+// key if it cuts the block: a query with a moving time range, such as a refreshing dashboard, keeps hitting
+// the cache for the blocks entirely inside the range.
+func newSearchJobCacheKeyFunc(tenantID string, searchReq *tempopb.SearchRequest) searchJobCacheKeyFunc {
+	if searchReq.Explain {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tenantID)
+
+	if searchReq.Query != "" {
+		// invalid queries fail and aren't cached. the raw query is the key, printing the parsed query loses
+		// precision, e.g. of float statics, and different queries would share results
+		if _, err := traceql.Parse(searchReq.Query); err != nil {
+			return nil
+		}
+		sb.WriteString("\x00q")
+		sb.WriteString(searchReq.Query)
+	} else {
+		keys := make([]string, 0, len(searchReq.Tags))
+		for k := range searchReq.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString("\x00t")
+			sb.WriteString(k)
+			sb.WriteString("=")
+			sb.WriteString(searchReq.Tags[k])
+		}
+		fmt.Fprintf(&sb, "\x00d%d-%d", searchReq.MinDurationMs, searchReq.MaxDurationMs)
+	}
+	fmt.Fprintf(&sb, "\x00l%d-%d", searchReq.Limit, searchReq.SpansPerSpanSet)
+
+	params := sb.String()
+	start, end := int64(searchReq.Start), int64(searchReq.End)
+	timeRange := fmt.Sprintf("\x00r%d-%d", start, end)
+
+	return func(m *backend.BlockMeta, startPage, pages int) string {
+		h := xxhash.New()
+		_, _ = h.WriteString(params)
+		if m.StartTime.Unix() < start || m.EndTime.Unix() >= end {
+			_, _ = h.WriteString(timeRange)
+		}
+
+		return "search:" + m.BlockID.String() + ":" + strconv.Itoa(startPage) + ":" + strconv.Itoa(pages) + ":" + strconv.FormatUint(h.Sum64(), 16)
+	}
+}
+
+// cachedJobs looks up the results of the search jobs of blocks in the cache. It returns the cached results by
+// cache key and the bytes of the blocks the cached jobs won't read.
+func (s *searchSharder) cachedJobs(ctx context.Context, blocks []*backend.BlockMeta, bytesPerRequest int, cacheKey searchJobCacheKeyFunc) (map[string]*tempopb.SearchResponse, uint64) {
+	keys := []string{}
+	jobBytes := map[string]uint64{}
+	for _, m := range blocks {
+		pages := pagesPerRequest(m, bytesPerRequest)
+		if pages == 0 {
+			continue
+		}
+
+		bytesPerPage := m.Size / uint64(m.TotalRecords)
+		for startPage := 0; startPage < int(m.TotalRecords); startPage += pages {
+			jobPages := pages
+			if remaining := int(m.TotalRecords) - startPage; remaining < jobPages {
+				jobPages = remaining
+			}
+
+			key := cacheKey(m, startPage, pages)
+			keys = append(keys, key)
+			jobBytes[key] = bytesPerPage * uint64(jobPages)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, 0
+	}
+
+	found, bufs, _ := s.cache.Fetch(ctx, keys)
+
+	cached := make(map[string]*tempopb.SearchResponse, len(found))
+	var cachedBytes uint64
+	for i, key := range found {
+		results := &tempopb.SearchResponse{}
+		if err := results.Unmarshal(bufs[i]); err != nil {
+			_ = level.Warn(s.logger).Log("msg", "error unmarshalling cached search results", "key", key, "err", err)
+			continue
+		}
+
+		// the job doesn't run so it inspects nothing
+		results.Metrics = &tempopb.SearchMetrics{}
+		cached[key] = results
+		cachedBytes += jobBytes[key]
+	}
+
+	searchCacheRequests.WithLabelValues("hit").Add(float64(len(cached)))
+	searchCacheRequests.WithLabelValues("miss").Add(float64(len(keys) - len(cached)))
+	return cached, cachedBytes
+}
+
+// storeResults caches the results of a search job.
+func (s *searchSharder) storeResults(ctx context.Context, key string, results *tempopb.SearchResponse) {
+	buf, err := results.Marshal()
+	if err != nil {
+		_ = level.Warn(s.logger).Log("msg", "error marshalling search results for the cache", "key", key, "err", err)
+		return
+	}
+
+	s.cache.Store(ctx, []string{key}, [][]byte{buf})
+}
@@ -0,0 +1,178 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSearchJobCacheKey(t *testing.T) {
+	meta := &backend.BlockMeta{
+		BlockID:   uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		StartTime: time.Unix(1000, 0),
+		EndTime:   time.Unix(2000, 0),
+	}
+	key := func(req *tempopb.SearchRequest) string {
+		return newSearchJobCacheKeyFunc("tenant", req)(meta, 0, 10)
+	}
+
+	req := &tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, Start: 500, End: 2500}
+	expected := key(req)
+	assert.True(t, strings.HasPrefix(expected, "search:00000000-0000-0000-0000-000000000001:0:10:"))
+
+	// time ranges containing the block don't matter
+	assert.Equal(t, expected, key(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, Start: 900, End: 3000}))
+
+	assert.NotEqual(t, expected, newSearchJobCacheKeyFunc("other", req)(meta, 0, 10))
+	assert.NotEqual(t, expected, newSearchJobCacheKeyFunc("tenant", req)(meta, 10, 10))
+	assert.NotEqual(t, expected, key(&tempopb.SearchRequest{Query: `{ .foo = "baz" }`, Limit: 20, Start: 500, End: 2500}))
+	assert.NotEqual(t, expected, key(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 30, Start: 500, End: 2500}))
+	assert.NotEqual(t, expected, key(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, SpansPerSpanSet: 5, Start: 500, End: 2500}))
+
+	// queries differing only in the precision of a float are different
+	assert.NotEqual(t,
+		key(&tempopb.SearchRequest{Query: `{ span.x > 0.000001 }`, Limit: 20, Start: 500, End: 2500}),
+		key(&tempopb.SearchRequest{Query: `{ span.x > 0.000002 }`, Limit: 20, Start: 500, End: 2500}))
+
+	// time ranges cutting the block are part of the key
+	cut := key(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, Start: 1500, End: 2500})
+	assert.NotEqual(t, expected, cut)
+	assert.NotEqual(t, cut, key(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Limit: 20, Start: 1600, End: 2500}))
+
+	// legacy tag search
+	tags := key(&tempopb.SearchRequest{Tags: map[string]string{"a": "1", "b": "2"}, Limit: 20, Start: 500, End: 2500})
+	assert.NotEqual(t, expected, tags)
+	assert.NotEqual(t, tags, key(&tempopb.SearchRequest{Tags: map[string]string{"a": "1", "b": "2"}, MinDurationMs: 10, Limit: 20, Start: 500, End: 2500}))
+
+	// invalid queries and explained searches are not cached
+	assert.Nil(t, newSearchJobCacheKeyFunc("tenant", &tempopb.SearchRequest{Query: `{ .foo = `}))
+	assert.Nil(t, newSearchJobCacheKeyFunc("tenant", &tempopb.SearchRequest{Query: `{ .foo = "bar" }`, Explain: true}))
+}
+
+func TestSearchSharderCache(t *testing.T) {
+	jobs := atomic.NewInt32(0)
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		jobs.Inc()
+
+		resp := &tempopb.SearchResponse{
+			Traces:  []*tempopb.TraceSearchMetadata{{TraceID: "page" + r.URL.Query().Get("startPage")}},
+			Metrics: &tempopb.SearchMetrics{InspectedTraces: 1, InspectedBytes: defaultTargetBytesPerRequest},
+		}
+		body, err := (&jsonpb.Marshaler{}).MarshalToString(resp)
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(body)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	// the search scans 2 jobs of defaultTargetBytesPerRequest, more than the limit
+	o, err := overrides.NewOverrides(overrides.Limits{MaxBytesPerSearch: defaultTargetBytesPerRequest})
+	require.NoError(t, err)
+	unlimited, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	blockStart := time.Now().Add(-2 * time.Hour).Unix()
+	reader := &mockReader{
+		metas: []*backend.BlockMeta{ // 2 jobs
+			{
+				StartTime:    time.Unix(blockStart, 0),
+				EndTime:      time.Unix(blockStart+60, 0),
+				Size:         defaultTargetBytesPerRequest * 2,
+				TotalRecords: 2,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+			},
+		},
+	}
+	cfg := SearchSharderConfig{
+		QueryIngestersUntil:   15 * time.Minute,
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}
+	c := cache.NewMockCache()
+	testRT := NewRoundTripper(next, newSearchSharder(reader, o, cfg, testSLOcfg, c, nil, newSearchProgress, log.NewNopLogger()))
+	unlimitedRT := NewRoundTripper(next, newSearchSharder(reader, unlimited, cfg, testSLOcfg, c, nil, newSearchProgress, log.NewNopLogger()))
+
+	doSearch := func(rt http.RoundTripper, query string, start, end int64) *http.Response {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/?q=%s&start=%d&end=%d", url.QueryEscape(query), start, end), nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		return resp
+	}
+	search := func(query string, start, end int64) *tempopb.SearchResponse {
+		resp := doSearch(unlimitedRT, query, start, end)
+		require.Equal(t, 200, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		actualResp := &tempopb.SearchResponse{}
+		require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(body), actualResp))
+		return actualResp
+	}
+
+	// the search exceeds max_bytes_per_search
+	assert.Equal(t, 400, doSearch(testRT, `{ .foo = "bar" }`, blockStart-60, blockStart+120).StatusCode)
+	assert.Equal(t, int32(0), jobs.Load())
+
+	first := search(`{ .foo = "bar" }`, blockStart-60, blockStart+120)
+	assert.Equal(t, int32(2), jobs.Load())
+	assert.Len(t, first.Traces, 2)
+	assert.Equal(t, uint32(2), first.Metrics.InspectedTraces)
+	assert.Equal(t, uint64(2*defaultTargetBytesPerRequest), first.Metrics.InspectedBytes)
+
+	// the same query with a moving time range is answered from the cache, nothing is inspected
+	second := search(`{ .foo = "bar" }`, blockStart-30, blockStart+150)
+	assert.Equal(t, int32(2), jobs.Load())
+	assert.ElementsMatch(t, first.Traces, second.Traces)
+	assert.Equal(t, uint32(0), second.Metrics.InspectedTraces)
+	assert.Equal(t, uint64(0), second.Metrics.InspectedBytes)
+	assert.Equal(t, uint32(2), second.Metrics.CompletedJobs)
+	assert.Equal(t, uint64(2*defaultTargetBytesPerRequest), second.Metrics.TotalBlockBytes)
+
+	// cached jobs don't count against max_bytes_per_search
+	resp := doSearch(testRT, `{ .foo = "bar" }`, blockStart-30, blockStart+150)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, int32(2), jobs.Load())
+
+	// a different query is not
+	search(`{ .foo = "baz" }`, blockStart-60, blockStart+120)
+	assert.Equal(t, int32(4), jobs.Load())
+
+	// neither is a time range cutting the block
+	search(`{ .foo = "bar" }`, blockStart+30, blockStart+120)
+	assert.Equal(t, int32(6), jobs.Load())
+}
+
+func TestNewSearchCache(t *testing.T) {
+	c, err := newSearchCache(SearchCacheConfig{}, log.NewNopLogger())
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	_, err = newSearchCache(SearchCacheConfig{Backend: "memcached"}, log.NewNopLogger())
+	assert.Error(t, err)
+
+	_, err = newSearchCache(SearchCacheConfig{Backend: "foo"}, log.NewNopLogger())
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package frontend
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// searchExplain is a thread safe struct combining the explanation of a search built by the query
+// frontend with the iterator statistics returned by the queriers.
+type searchExplain struct {
+	explain   *tempopb.SearchExplain
+	iterators map[string]*tempopb.ExplainIterator
+	mtx       sync.Mutex
+}
+
+// explainSearch explains how the search request is executed: the plan of the TraceQL query and the
+// blocks and pages in the time range of the search. The queriers add the statistics of the iterators.
+func (s *searchSharder) explainSearch(tenantID string, searchReq *tempopb.SearchRequest) (*searchExplain, error) {
+	explain := &tempopb.SearchExplain{}
+	if api.IsTraceQLQuery(searchReq) {
+		var err error
+		explain, err = traceql.NewEngine().ExplainSearch(searchReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// same blocks as backendRequests
+	var blocks []*backend.BlockMeta
+	if searchReq.Start != 0 && searchReq.End != 0 {
+		if start, end := backendRange(searchReq, s.cfg.QueryBackendAfter); start != end {
+			blocks = s.blockMetas(int64(start), int64(end), tenantID)
+		}
+	}
+
+	explain.SearchedBlocks = uint32(len(blocks))
+	// the blocklist may have been polled since the blocks were selected
+	if total := len(s.reader.BlockMetas(tenantID)); total > len(blocks) {
+		explain.SkippedBlocks = uint32(total - len(blocks))
+	}
+	for _, b := range blocks {
+		explain.SearchedPages += b.TotalRecords
+	}
+
+	return &searchExplain{
+		explain:   explain,
+		iterators: map[string]*tempopb.ExplainIterator{},
+	}, nil
+}
+
+// addIterators sums the iterator statistics of a search job by column.
+func (e *searchExplain) addIterators(iterators []*tempopb.ExplainIterator) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	for _, it := range iterators {
+		sum, ok := e.iterators[it.Column]
+		if !ok {
+			sum = &tempopb.ExplainIterator{Column: it.Column}
+			e.iterators[it.Column] = sum
+		}
+		sum.InspectedColumnChunks += it.InspectedColumnChunks
+		sum.KeptColumnChunks += it.KeptColumnChunks
+		sum.InspectedPages += it.InspectedPages
+		sum.KeptPages += it.KeptPages
+		sum.InspectedValues += it.InspectedValues
+		sum.KeptValues += it.KeptValues
+		sum.DurationNanos += it.DurationNanos
+	}
+}
+
+func (e *searchExplain) result() *tempopb.SearchExplain {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.explain.Iterators = make([]*tempopb.ExplainIterator, 0, len(e.iterators))
+	for _, it := range e.iterators {
+		e.explain.Iterators = append(e.explain.Iterators, it)
+	}
+	sort.Slice(e.explain.Iterators, func(i, j int) bool {
+		return e.explain.Iterators[i].Column < e.explain.Iterators[j].Column
+	})
+
+	return e.explain
+}
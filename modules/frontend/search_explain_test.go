@@ -0,0 +1,107 @@
+package frontend
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSearchSharderExplain(t *testing.T) {
+	jobs := atomic.NewInt32(0)
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		jobs.Inc()
+		assert.Equal(t, "true", r.URL.Query().Get("explain"))
+
+		resp := &tempopb.SearchResponse{
+			Metrics: &tempopb.SearchMetrics{},
+			Explain: &tempopb.SearchExplain{
+				Iterators: []*tempopb.ExplainIterator{
+					{Column: "rs.list.element.ss.list.element.Spans.list.element.Name", InspectedPages: 2, KeptPages: 1, DurationNanos: 10},
+				},
+			},
+		}
+		body, err := (&jsonpb.Marshaler{}).MarshalToString(resp)
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(body)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	now := time.Now().Add(-time.Hour).Unix()
+	meta := func(id string, start int64) *backend.BlockMeta {
+		return &backend.BlockMeta{
+			StartTime:    time.Unix(start, 0),
+			EndTime:      time.Unix(start+60, 0),
+			Size:         defaultTargetBytesPerRequest * 2,
+			TotalRecords: 2,
+			BlockID:      uuid.MustParse(id),
+		}
+	}
+
+	sharder := newSearchSharder(&mockReader{
+		metas: []*backend.BlockMeta{
+			meta("00000000-0000-0000-0000-000000000000", now),
+			meta("00000000-0000-0000-0000-000000000001", now-3600), // outside of the time range
+		},
+	}, o, SearchSharderConfig{
+		QueryIngestersUntil:   15 * time.Minute,
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, testSLOcfg, cache.NewMockCache(), nil, newSearchProgress, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	search := func() *tempopb.SearchResponse {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/?q=%s&start=%d&end=%d&explain=true", url.QueryEscape(`{ name = "foo" }`), now-60, now+120), nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+		resp, err := testRT.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		actualResp := &tempopb.SearchResponse{}
+		require.NoError(t, jsonpb.Unmarshal(resp.Body, actualResp))
+		return actualResp
+	}
+
+	actual := search().Explain
+	require.NotNil(t, actual)
+	assert.Equal(t, []string{"{ name = `foo` }"}, actual.Pipeline)
+	assert.Equal(t, []*tempopb.ExplainCondition{{Attribute: "name", Op: "=", Operands: []string{"`foo`"}, PushedDown: true}}, actual.Conditions)
+	assert.True(t, actual.AllConditions)
+	assert.Equal(t, uint32(1), actual.SearchedBlocks)
+	assert.Equal(t, uint32(1), actual.SkippedBlocks)
+	assert.Equal(t, uint32(2), actual.SearchedPages)
+
+	// the iterators of both jobs are summed
+	assert.Equal(t, []*tempopb.ExplainIterator{
+		{Column: "rs.list.element.ss.list.element.Spans.list.element.Name", InspectedPages: 4, KeptPages: 2, DurationNanos: 20},
+	}, actual.Iterators)
+	assert.Equal(t, int32(2), jobs.Load())
+
+	// explained searches are not answered from the cache
+	search()
+	assert.Equal(t, int32(4), jobs.Load())
+}
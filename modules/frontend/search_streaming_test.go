@@ -228,7 +228,7 @@ func testHandler(t *testing.T, next http.RoundTripper) streamingSearchHandler {
 				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
 			},
 		},
-	}, "", log.NewNopLogger())
+	}, nil, nil, "", log.NewNopLogger())
 
 	return handler
 }
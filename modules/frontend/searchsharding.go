@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/gogo/protobuf/jsonpb" //nolint:all deprecated
@@ -18,9 +19,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/weaveworks/common/user"
 
+	"github.com/grafana/tempo/modules/frontend/savedqueries"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -56,6 +59,10 @@ type searchSharder struct {
 	reader    tempodb.Reader
 	overrides overrides.Interface
 	progress  searchProgressFactory
+	// cache of the results of backend jobs, nil if disabled
+	cache cache.Cache
+	// saved queries of the tenants, nil if disabled
+	savedQueries *savedqueries.Store
 
 	cfg    SearchSharderConfig
 	sloCfg SLOConfig
@@ -75,18 +82,24 @@ type SearchSharderConfig struct {
 type backendReqMsg struct {
 	req *http.Request
 	err error
+	// cacheKey of the results of the request, empty if they must not be cached
+	cacheKey string
+	// cached results of the request, the request doesn't need to run
+	cached *tempopb.SearchResponse
 }
 
 // newSearchSharder creates a sharding middleware for search
-func newSearchSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, sloCfg SLOConfig, progress searchProgressFactory, logger log.Logger) Middleware {
+func newSearchSharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, sloCfg SLOConfig, c cache.Cache, sq *savedqueries.Store, progress searchProgressFactory, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		return searchSharder{
-			next:      next,
-			reader:    reader,
-			overrides: o,
-			cfg:       cfg,
-			sloCfg:    sloCfg,
-			logger:    logger,
+			next:         next,
+			reader:       reader,
+			overrides:    o,
+			cache:        c,
+			savedQueries: sq,
+			cfg:          cfg,
+			sloCfg:       sloCfg,
+			logger:       logger,
 
 			progress: progress,
 		}
@@ -119,6 +132,36 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "frontend.ShardSearch")
 	defer span.Finish()
 
+	// replace the name of a saved query with its query
+	if searchReq.Saved != "" {
+		if s.savedQueries == nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader("saved queries are not enabled")),
+			}, nil
+		}
+
+		saved, err := s.savedQueries.Get(ctx, tenantID, searchReq.Saved)
+		if errors.Is(err, savedqueries.ErrNotFound) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("saved query %s not found", searchReq.Saved))),
+			}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load saved query %s: %w", searchReq.Saved, err)
+		}
+
+		searchReq.Query = saved.Query
+		searchReq.Saved = ""
+
+		// the backend jobs are cloned from the parent request
+		r, err = api.BuildSearchRequest(r.Clone(ctx), searchReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	reqStart := time.Now()
 	// sub context to cancel in-progress sub requests
 	subCtx, subCancel := context.WithCancel(ctx)
@@ -148,11 +191,36 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 	}
 
 	// pass subCtx in requests so we can cancel and exit early
-	totalJobs, totalBlocks, totalBlockBytes := s.backendRequests(subCtx, tenantID, r, searchReq, reqCh, stopCh)
+	totalJobs, totalBlocks, totalBlockBytes, cachedBlockBytes := s.backendRequests(subCtx, tenantID, r, searchReq, reqCh, stopCh)
 	if ingesterReq != nil {
 		totalJobs++
 	}
 
+	// a dry run only returns the estimated cost of the search
+	if api.IsSearchEstimate(r) {
+		return estimateResponse(totalJobs, totalBlocks, totalBlockBytes)
+	}
+
+	// enforce the max bytes scanned by a search, the blocks of cached jobs aren't read
+	if maxBytes := s.overrides.MaxBytesPerSearch(tenantID); maxBytes > 0 && totalBlockBytes-cachedBlockBytes > uint64(maxBytes) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body: io.NopCloser(strings.NewReader(fmt.Sprintf("search would scan %s of blocks which exceeds the limit of %s (max_bytes_per_search), reduce the time range of the search",
+				humanize.Bytes(totalBlockBytes-cachedBlockBytes), humanize.Bytes(uint64(maxBytes))))),
+		}, nil
+	}
+
+	var explain *searchExplain
+	if searchReq.Explain {
+		explain, err = s.explainSearch(tenantID, searchReq)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+			}, nil
+		}
+	}
+
 	// execute requests
 	wg := boundedwaitgroup.New(uint(s.cfg.ConcurrentRequests))
 	progress := s.progress(ctx, int(searchReq.Limit), totalJobs, totalBlocks, totalBlockBytes)
@@ -168,11 +236,16 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 			break
 		}
 
+		if req.cached != nil {
+			progress.addResponse(req.cached)
+			continue
+		}
+
 		// When we hit capacity of boundedwaitgroup, wg.Add will block
 		wg.Add(1)
 		startedReqs++
 
-		go func(innerR *http.Request) {
+		go func(innerR *http.Request, cacheKey string) {
 			defer func() {
 				if progress.shouldQuit() {
 					subCancel()
@@ -215,9 +288,16 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 				return
 			}
 
+			if cacheKey != "" {
+				s.storeResults(innerR.Context(), cacheKey, results)
+			}
+			if explain != nil {
+				explain.addIterators(results.Explain.GetIterators())
+			}
+
 			// happy path
 			progress.addResponse(results)
-		}(req.req)
+		}(req.req, req.cacheKey)
 	}
 
 	// wait for all goroutines running in wg to finish or cancelled
@@ -271,6 +351,10 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 		}, nil
 	}
 
+	if explain != nil {
+		overallResponse.response.Explain = explain.result()
+	}
+
 	m := &jsonpb.Marshaler{}
 	bodyString, err := m.MarshalToString(overallResponse.response)
 	if err != nil {
@@ -289,6 +373,31 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+// estimateResponse returns the search response of a dry run. It only contains the jobs, blocks and bytes
+// the search would scan.
+func estimateResponse(totalJobs, totalBlocks int, totalBlockBytes uint64) (*http.Response, error) {
+	m := &jsonpb.Marshaler{}
+	bodyString, err := m.MarshalToString(&tempopb.SearchResponse{
+		Metrics: &tempopb.SearchMetrics{
+			TotalJobs:       uint32(totalJobs),
+			TotalBlocks:     uint32(totalBlocks),
+			TotalBlockBytes: totalBlockBytes,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptJSON},
+		},
+		Body:          io.NopCloser(strings.NewReader(bodyString)),
+		ContentLength: int64(len([]byte(bodyString))),
+	}, nil
+}
+
 // blockMetas returns all relevant blockMetas given a start/end
 func (s *searchSharder) blockMetas(start, end int64, tenantID string) []*backend.BlockMeta {
 	// reduce metas to those in the requested range
@@ -305,8 +414,8 @@ func (s *searchSharder) blockMetas(start, end int64, tenantID string) []*backend
 }
 
 // backendRequest builds backend requests to search backend blocks. backendRequest takes ownership of reqCh and closes it.
-// it returns 3 int values: totalBlocks, totalBlockBytes, and estimated jobs
-func (s *searchSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, reqCh chan<- *backendReqMsg, stopCh <-chan struct{}) (totalJobs, totalBlocks int, totalBlockBytes uint64) {
+// it returns the estimated jobs, totalBlocks, totalBlockBytes and the bytes of the blocks of the jobs answered from the cache
+func (s *searchSharder) backendRequests(ctx context.Context, tenantID string, parent *http.Request, searchReq *tempopb.SearchRequest, reqCh chan<- *backendReqMsg, stopCh <-chan struct{}) (totalJobs, totalBlocks int, totalBlockBytes, cachedBlockBytes uint64) {
 	var blocks []*backend.BlockMeta
 
 	// request without start or end, search only in ingester
@@ -341,8 +450,18 @@ func (s *searchSharder) backendRequests(ctx context.Context, tenantID string, pa
 		totalBlockBytes += b.Size
 	}
 
+	var cacheKey searchJobCacheKeyFunc
+	if s.cache != nil {
+		cacheKey = newSearchJobCacheKeyFunc(tenantID, searchReq)
+	}
+
+	var cached map[string]*tempopb.SearchResponse
+	if cacheKey != nil {
+		cached, cachedBlockBytes = s.cachedJobs(ctx, blocks, targetBytesPerRequest, cacheKey)
+	}
+
 	go func() {
-		buildBackendRequests(ctx, tenantID, parent, blocks, targetBytesPerRequest, reqCh, stopCh)
+		buildBackendRequests(ctx, tenantID, parent, blocks, targetBytesPerRequest, cacheKey, cached, reqCh, stopCh)
 	}()
 
 	return
@@ -370,8 +489,9 @@ func backendRange(searchReq *tempopb.SearchRequest, queryBackendAfter time.Durat
 }
 
 // buildBackendRequests returns a slice of requests that cover all blocks in the store
-// that are covered by start/end.
-func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Request, metas []*backend.BlockMeta, bytesPerRequest int, reqCh chan<- *backendReqMsg, stopCh <-chan struct{}) {
+// that are covered by start/end. The requests have a cache key if cacheKey is not nil and carry their results if they
+// are in cached.
+func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Request, metas []*backend.BlockMeta, bytesPerRequest int, cacheKey searchJobCacheKeyFunc, cached map[string]*tempopb.SearchResponse, reqCh chan<- *backendReqMsg, stopCh <-chan struct{}) {
 	defer close(reqCh)
 
 	for _, m := range metas {
@@ -380,23 +500,11 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Req
 			continue
 		}
 
-		blockID := m.BlockID.String()
 		for startPage := 0; startPage < int(m.TotalRecords); startPage += pages {
 			subR := parent.Clone(ctx)
 			subR.Header.Set(user.OrgIDHeaderName, tenantID)
 
-			subR, err := api.BuildSearchBlockRequest(subR, &tempopb.SearchBlockRequest{
-				BlockID:       blockID,
-				StartPage:     uint32(startPage),
-				PagesToSearch: uint32(pages),
-				Encoding:      m.Encoding.String(),
-				IndexPageSize: m.IndexPageSize,
-				TotalRecords:  m.TotalRecords,
-				DataEncoding:  m.DataEncoding,
-				Version:       m.Version,
-				Size_:         m.Size,
-				FooterSize:    m.FooterSize,
-			})
+			subR, err := api.BuildSearchBlockRequest(subR, blockRequest(m, startPage, pages))
 
 			if err != nil {
 				reqCh <- &backendReqMsg{err: err}
@@ -405,8 +513,14 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Req
 
 			subR.RequestURI = buildUpstreamRequestURI(parent.URL.Path, subR.URL.Query())
 
+			msg := &backendReqMsg{req: subR}
+			if cacheKey != nil {
+				msg.cacheKey = cacheKey(m, startPage, pages)
+				msg.cached = cached[msg.cacheKey]
+			}
+
 			select {
-			case reqCh <- &backendReqMsg{req: subR}:
+			case reqCh <- msg:
 			case <-stopCh:
 				return
 			}
@@ -414,6 +528,24 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent *http.Req
 	}
 }
 
+// blockRequest returns the backend block parameters of the job searching pages [startPage, startPage+pages)
+// of the block m.
+func blockRequest(m *backend.BlockMeta, startPage, pages int) *tempopb.SearchBlockRequest {
+	return &tempopb.SearchBlockRequest{
+		BlockID:          m.BlockID.String(),
+		StartPage:        uint32(startPage),
+		PagesToSearch:    uint32(pages),
+		Encoding:         m.Encoding.String(),
+		IndexPageSize:    m.IndexPageSize,
+		TotalRecords:     m.TotalRecords,
+		DataEncoding:     m.DataEncoding,
+		Version:          m.Version,
+		Size_:            m.Size,
+		FooterSize:       m.FooterSize,
+		DedicatedColumns: m.DedicatedColumns.ToTempopb(),
+	}
+}
+
 // pagesPerRequest returns an integer value that indicates the number of pages
 // that should be searched per query. This value is based on the target number of bytes
 // 0 is returned if there is no valid answer
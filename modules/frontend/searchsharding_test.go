@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
@@ -24,14 +26,26 @@ import (
 	"github.com/weaveworks/common/user"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/tempo/modules/frontend/savedqueries"
+	generator_client "github.com/grafana/tempo/modules/generator/client"
+	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/modules/querier"
+	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
+	tempo_model "github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+	"github.com/grafana/tempo/tempodb/wal"
 )
 
 var testSLOcfg = SLOConfig{
@@ -60,8 +74,9 @@ func (m *mockReader) Fetch(ctx context.Context, meta *backend.BlockMeta, req tra
 	return traceql.FetchSpansResponse{}, nil
 }
 
-func (m *mockReader) EnablePolling(sharder blocklist.JobSharder) {}
-func (m *mockReader) Shutdown()                                  {}
+func (m *mockReader) AddBlockMetas(tenantID string, metas []*backend.BlockMeta) {}
+func (m *mockReader) EnablePolling(sharder blocklist.JobSharder)                {}
+func (m *mockReader) Shutdown()                                                 {}
 
 func TestBuildBackendRequests(t *testing.T) {
 	tests := []struct {
@@ -187,7 +202,7 @@ func TestBuildBackendRequests(t *testing.T) {
 		reqCh := make(chan *backendReqMsg)
 
 		go func() {
-			buildBackendRequests(context.Background(), "test", req, tc.metas, tc.targetBytesPerRequest, reqCh, stopCh)
+			buildBackendRequests(context.Background(), "test", req, tc.metas, tc.targetBytesPerRequest, nil, nil, reqCh, stopCh)
 		}()
 
 		actualURIs := []string{}
@@ -292,7 +307,7 @@ func TestBackendRequests(t *testing.T) {
 			defer close(stopCh)
 			reqCh := make(chan *backendReqMsg)
 
-			jobs, blocks, blockBytes := s.backendRequests(context.TODO(), "test", r, searchReq, reqCh, stopCh)
+			jobs, blocks, blockBytes, _ := s.backendRequests(context.TODO(), "test", r, searchReq, reqCh, stopCh)
 			require.Equal(t, tc.expectedJobs, jobs)
 			require.Equal(t, tc.expectedBlocks, blocks)
 			require.Equal(t, tc.expectedBlockBytes, blockBytes)
@@ -686,7 +701,7 @@ func TestSearchSharderRoundTrip(t *testing.T) {
 			}, o, SearchSharderConfig{
 				ConcurrentRequests:    1, // 1 concurrent request to force order
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
-			}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+			}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
 			testRT := NewRoundTripper(next, sharder)
 
 			req := httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
@@ -752,7 +767,7 @@ func TestTotalJobsIncludesIngester(t *testing.T) {
 		QueryIngestersUntil:   15 * time.Minute,
 		ConcurrentRequests:    1, // 1 concurrent request to force order
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
-	}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
 	testRT := NewRoundTripper(next, sharder)
 
 	path := fmt.Sprintf("/?start=%d&end=%d", now-1, now+1)
@@ -787,7 +802,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 		ConcurrentRequests:    defaultConcurrentRequests,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		MaxDuration:           5 * time.Minute,
-	}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
 	testRT := NewRoundTripper(next, sharder)
 
 	// no org id
@@ -816,7 +831,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 		ConcurrentRequests:    defaultConcurrentRequests,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		MaxDuration:           5 * time.Minute,
-	}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
 	testRT = NewRoundTripper(next, sharder)
 
 	req = httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
@@ -825,6 +840,182 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	testBadRequest(t, resp, err, "range specified by start and end exceeds 1m0s. received start=1000 end=1500")
 }
 
+func TestSearchSharderMaxBytesPerSearch(t *testing.T) {
+	jobs := atomic.NewInt32(0)
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		jobs.Inc()
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(`{"metrics":{}}`)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{
+		MaxBytesPerSearch: 3 * defaultTargetBytesPerRequest,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().Add(-time.Hour).Unix()
+	meta := func(id string) *backend.BlockMeta {
+		return &backend.BlockMeta{
+			StartTime:    time.Unix(now, 0),
+			EndTime:      time.Unix(now, 0),
+			Size:         defaultTargetBytesPerRequest * 2,
+			TotalRecords: 2,
+			BlockID:      uuid.MustParse(id),
+		}
+	}
+
+	sharder := newSearchSharder(&mockReader{
+		metas: []*backend.BlockMeta{meta("00000000-0000-0000-0000-000000000000"), meta("00000000-0000-0000-0000-000000000001")},
+	}, o, SearchSharderConfig{
+		QueryIngestersUntil:   15 * time.Minute,
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	// the two blocks exceed the limit
+	req := httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d", now-1, now+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err := testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "search would scan 419 MB of blocks which exceeds the limit of 315 MB (max_bytes_per_search), reduce the time range of the search")
+	assert.Equal(t, int32(0), jobs.Load())
+
+	// a dry run returns the estimate without searching
+	req = httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d&estimate=true", now-1, now+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	actualResp := &tempopb.SearchResponse{}
+	require.NoError(t, jsonpb.Unmarshal(resp.Body, actualResp))
+	assert.Equal(t, &tempopb.SearchMetrics{
+		TotalJobs:       4,
+		TotalBlocks:     2,
+		TotalBlockBytes: 4 * defaultTargetBytesPerRequest,
+	}, actualResp.Metrics)
+	assert.Empty(t, actualResp.Traces)
+	assert.Equal(t, int32(0), jobs.Load())
+
+	// other tenants are not limited
+	o, err = overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+	sharder = newSearchSharder(&mockReader{
+		metas: []*backend.BlockMeta{meta("00000000-0000-0000-0000-000000000000"), meta("00000000-0000-0000-0000-000000000001")},
+	}, o, SearchSharderConfig{
+		QueryIngestersUntil:   15 * time.Minute,
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
+	testRT = NewRoundTripper(next, sharder)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d", now-1, now+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(4), jobs.Load())
+}
+
+func TestSearchSharderSavedQuery(t *testing.T) {
+	var mtx sync.Mutex
+	var queries []string
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		// neither the ingester nor the backend jobs reference the saved query
+		assert.False(t, r.URL.Query().Has("saved"))
+
+		mtx.Lock()
+		queries = append(queries, r.URL.Query().Get("q"))
+		mtx.Unlock()
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(`{"metrics":{}}`)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	rawR, rawW, _, err := local.New(&local.Config{Path: t.TempDir()})
+	require.NoError(t, err)
+	store := savedqueries.NewStore(rawR, rawW)
+	require.NoError(t, store.Put(context.Background(), "blerg", savedqueries.SavedQuery{Name: "errors", Query: "{ status = error }"}))
+
+	now := time.Now().Unix()
+	sharder := newSearchSharder(&mockReader{}, o, SearchSharderConfig{
+		QueryIngestersUntil:   15 * time.Minute,
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, testSLOcfg, nil, store, newSearchProgress, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	// the saved query is searched
+	req := httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d&saved=errors", now-1, now+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err := testRT.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"{ status = error }"}, queries)
+
+	// unknown saved queries are not found
+	req = httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d&saved=unknown", now-1, now+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// the saved queries of other tenants are not found
+	req = httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d&saved=errors", now-1, now+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "other"))
+	resp, err = testRT.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// saved queries are rejected if disabled
+	sharder = newSearchSharder(&mockReader{}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
+	testRT = NewRoundTripper(next, sharder)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d&saved=errors", now-1, now+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "saved queries are not enabled")
+	assert.Len(t, queries, 1)
+
+	// the backend jobs search the saved query
+	queries = nil
+	blockStart := time.Now().Add(-10 * time.Minute).Unix()
+	sharder = newSearchSharder(&mockReader{
+		metas: []*backend.BlockMeta{
+			{
+				StartTime:    time.Unix(blockStart, 0),
+				EndTime:      time.Unix(blockStart, 0),
+				Size:         defaultTargetBytesPerRequest * 2,
+				TotalRecords: 2,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+			},
+		},
+	}, o, SearchSharderConfig{
+		QueryIngestersUntil:   15 * time.Minute,
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, testSLOcfg, nil, store, newSearchProgress, log.NewNopLogger())
+	testRT = NewRoundTripper(next, sharder)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/?start=%d&end=%d&saved=errors", blockStart-1, blockStart+1), nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// 2 jobs for the block + 1 for the ingester
+	assert.Equal(t, []string{"{ status = error }", "{ status = error }", "{ status = error }"}, queries)
+}
+
 func testBadRequest(t *testing.T, resp *http.Response, err error, expectedBody string) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	assert.Nil(t, err)
@@ -941,7 +1132,7 @@ func TestSubRequestsCancelled(t *testing.T) {
 		ConcurrentRequests:    10,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		DefaultLimit:          2,
-	}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
 
 	// return some things and assert the right subrequests are cancelled
 	// 500, err, limit
@@ -1047,7 +1238,7 @@ func benchmarkSearchSharderRoundTrip(b *testing.B, s int32) {
 	}, o, SearchSharderConfig{
 		ConcurrentRequests:    100,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
-	}, testSLOcfg, newSearchProgress, log.NewNopLogger())
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
 	testRT := NewRoundTripper(next, sharder)
 
 	req := httptest.NewRequest("GET", "/?start=1000&end=1500&limit=1", nil) // limiting to 1 to let succeedAfter work
@@ -1062,3 +1253,105 @@ func benchmarkSearchSharderRoundTrip(b *testing.B, s int32) {
 		require.NoError(b, err)
 	}
 }
+
+func TestSearchSharderDedicatedColumns(t *testing.T) {
+	tempDir := t.TempDir()
+
+	store, err := storage.NewStore(storage.Config{
+		Trace: tempodb.Config{
+			Backend: "local",
+			Local: &local.Config{
+				Path: path.Join(tempDir, "traces"),
+			},
+			Block: &common.BlockConfig{
+				IndexDownsampleBytes: 17,
+				BloomFP:              .01,
+				BloomShardSizeBytes:  100_000,
+				Version:              vparquet3.VersionString,
+				IndexPageSizeBytes:   1000,
+				RowGroupSizeBytes:    10000,
+			},
+			WAL: &wal.Config{
+				Filepath:       path.Join(tempDir, "wal"),
+				IngestionSlack: time.Since(time.Time{}),
+			},
+			Search: &tempodb.SearchConfig{
+				ChunkSizeBytes:  1_000_000,
+				ReadBufferCount: 8, ReadBufferSizeBytes: 4 * 1024 * 1024,
+			},
+		},
+	}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	// write a block storing span.foo in a dedicated column
+	dedicatedColumns := backend.DedicatedColumns{{Scope: backend.DedicatedColumnScopeSpan, Name: "foo", Type: backend.DedicatedColumnTypeString}}
+	head, err := store.WAL().NewBlockWithDedicatedColumns(uuid.New(), "test", tempo_model.CurrentEncoding, dedicatedColumns)
+	require.NoError(t, err)
+
+	start := uint32(time.Now().Add(-2 * time.Hour).Unix())
+	end := start + 60
+
+	id := test.ValidTraceID(nil)
+	tr := &tempopb.Trace{Batches: []*v1_trace.ResourceSpans{test.MakeBatch(1, id)}}
+	span := tr.Batches[0].ScopeSpans[0].Spans[0]
+	span.StartTimeUnixNano = uint64(start) * uint64(time.Second)
+	span.EndTimeUnixNano = uint64(end) * uint64(time.Second)
+	span.Attributes = append(span.Attributes, &v1_common.KeyValue{
+		Key:   "foo",
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "bar"}},
+	})
+	dec := tempo_model.MustNewSegmentDecoder(tempo_model.CurrentEncoding)
+	segment, err := dec.PrepareForWrite(tr, start, end)
+	require.NoError(t, err)
+	obj, err := dec.ToObject([][]byte{segment})
+	require.NoError(t, err)
+	require.NoError(t, head.Append(id, obj, start, end))
+
+	block, err := store.CompleteBlock(context.Background(), head)
+	require.NoError(t, err)
+	meta := block.BlockMeta()
+	require.Equal(t, dedicatedColumns, meta.DedicatedColumns)
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	q, err := querier.New(querier.Config{Search: querier.SearchConfig{QueryTimeout: time.Minute}}, ingester_client.Config{}, nil, generator_client.Config{}, nil, store, o)
+	require.NoError(t, err)
+
+	// the backend jobs are searched by the querier, the ingesters have nothing
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if !api.IsSearchBlock(r) {
+			return &http.Response{
+				Body:       io.NopCloser(strings.NewReader("{}")),
+				StatusCode: http.StatusOK,
+			}, nil
+		}
+
+		w := httptest.NewRecorder()
+		q.SearchHandler(w, r)
+		return w.Result(), nil
+	})
+
+	sharder := newSearchSharder(&mockReader{metas: []*backend.BlockMeta{meta}}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, testSLOcfg, nil, nil, newSearchProgress, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	for query, expected := range map[string]int{
+		`{ span.foo = "bar" }`: 1,
+		`{ .foo = "bar" }`:     1,
+		`{ span.foo = "baz" }`: 0,
+	} {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/?q=%s&start=%d&end=%d", url.QueryEscape(query), start-60, end+60), nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+
+		resp, err := testRT.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		actual := &tempopb.SearchResponse{}
+		require.NoError(t, jsonpb.Unmarshal(resp.Body, actual))
+		assert.Len(t, actual.Traces, expected, query)
+	}
+}
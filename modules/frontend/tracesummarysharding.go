@@ -0,0 +1,80 @@
+package frontend
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-kit/log"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/traceqlmetrics"
+	"github.com/grafana/tempo/tempodb"
+)
+
+// traceSummaryQuery combines the call trees of the jobs of a trace summary until the trace limit
+// is reached.
+type traceSummaryQuery struct {
+	req     *tempopb.TraceSummaryRequest
+	summary *traceqlmetrics.TraceSummary
+}
+
+// newTraceSummarySharder creates a sharding middleware for trace summaries. Like metrics queries
+// summaries are only computed from backend blocks, each job covering a range of pages of a
+// single block.
+func newTraceSummarySharder(reader tempodb.Reader, o overrides.Interface, cfg SearchSharderConfig, logger log.Logger) Middleware {
+	return newBlockJobSharder(reader, o, cfg, logger, traceSummaryOp, parseTraceSummaryQuery)
+}
+
+func parseTraceSummaryQuery(r *http.Request) (blockJobQuery, error) {
+	summaryReq, err := api.ParseTraceSummaryRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate the query up front to return a 400 instead of failing every job
+	expr, err := traceql.Parse(summaryReq.Query)
+	if err != nil {
+		return nil, err
+	}
+	if expr.MetricsPipeline != nil {
+		return nil, errors.New("metrics queries are not supported by trace summaries")
+	}
+
+	return &traceSummaryQuery{
+		req:     summaryReq,
+		summary: traceqlmetrics.NewTraceSummary(),
+	}, nil
+}
+
+func (q *traceSummaryQuery) timeRange() (uint64, uint64) {
+	return q.req.Start, q.req.End
+}
+
+func (q *traceSummaryQuery) jobRequest(parent *http.Request, block *tempopb.SearchBlockRequest) *http.Request {
+	return api.BuildTraceSummaryRequest(parent, &tempopb.TraceSummaryRequest{
+		Query: q.req.Query,
+		Start: q.req.Start,
+		End:   q.req.End,
+		Limit: q.req.Limit,
+		Block: block,
+	})
+}
+
+func (q *traceSummaryQuery) newJobResponse() blockJobResponse {
+	return &tempopb.TraceSummaryResponse{}
+}
+
+// combine completes the result once the trace limit is reached
+func (q *traceSummaryQuery) combine(resp blockJobResponse) bool {
+	q.summary.Combine(traceqlmetrics.TraceSummaryFromProto(resp.(*tempopb.TraceSummaryResponse)))
+	return q.summary.TraceCount >= int(q.req.Limit)
+}
+
+func (q *traceSummaryQuery) response(metrics *tempopb.SearchMetrics) blockJobResponse {
+	res := q.summary.ToProto()
+	res.Metrics = metrics
+	return res
+}
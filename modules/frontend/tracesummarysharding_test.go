@@ -0,0 +1,192 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestTraceSummarySharderRoundTrip(t *testing.T) {
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		// every job returns the same trace
+		resString, err := (&jsonpb.Marshaler{}).MarshalToString(&tempopb.TraceSummaryResponse{
+			TraceCount: 1,
+			Nodes: []*tempopb.TraceSummaryNode{
+				{
+					Path:             "frontend:GET",
+					ServiceName:      "frontend",
+					Name:             "GET",
+					Count:            1,
+					ErrorCount:       1,
+					LatencyHistogram: []*tempopb.RawHistogram{{Bucket: 10, Count: 1}},
+				},
+			},
+			Metrics: &tempopb.SearchMetrics{InspectedBytes: 10},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(resString)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	sharder := newTraceSummarySharder(&mockReader{
+		metas: []*backend.BlockMeta{
+			{ // two jobs
+				StartTime:    time.Unix(1000, 0),
+				EndTime:      time.Unix(1100, 0),
+				Size:         defaultTargetBytesPerRequest * 2,
+				TotalRecords: 2,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+			},
+			{ // outside of the range
+				StartTime:    time.Unix(2000, 0),
+				EndTime:      time.Unix(2100, 0),
+				Size:         defaultTargetBytesPerRequest,
+				TotalRecords: 1,
+				BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			},
+		},
+	}, o, SearchSharderConfig{
+		ConcurrentRequests:    1, // process jobs in order for the limit to be deterministic
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	testCases := []struct {
+		limit    string
+		expected *tempopb.TraceSummaryResponse
+	}{
+		{
+			limit: "10",
+			expected: &tempopb.TraceSummaryResponse{
+				TraceCount: 2,
+				Nodes: []*tempopb.TraceSummaryNode{
+					{
+						Path:             "frontend:GET",
+						ServiceName:      "frontend",
+						Name:             "GET",
+						Count:            2,
+						ErrorCount:       2,
+						ErrorRate:        1,
+						P50:              724, // interpolated within the bucket
+						P90:              1024,
+						P95:              1024,
+						P99:              1024,
+						LatencyHistogram: []*tempopb.RawHistogram{{Bucket: 10, Count: 2}},
+					},
+				},
+				Metrics: &tempopb.SearchMetrics{
+					InspectedBytes:  20,
+					TotalBlocks:     1,
+					TotalJobs:       2,
+					CompletedJobs:   2,
+					TotalBlockBytes: defaultTargetBytesPerRequest * 2,
+				},
+			},
+		},
+		{
+			// the remaining jobs are abandoned once the limit is reached
+			limit: "1",
+			expected: &tempopb.TraceSummaryResponse{
+				TraceCount: 1,
+				Nodes: []*tempopb.TraceSummaryNode{
+					{
+						Path:             "frontend:GET",
+						ServiceName:      "frontend",
+						Name:             "GET",
+						Count:            1,
+						ErrorCount:       1,
+						ErrorRate:        1,
+						P50:              1024,
+						P90:              1024,
+						P95:              1024,
+						P99:              1024,
+						LatencyHistogram: []*tempopb.RawHistogram{{Bucket: 10, Count: 1}},
+					},
+				},
+				Metrics: &tempopb.SearchMetrics{
+					InspectedBytes:  10,
+					TotalBlocks:     1,
+					TotalJobs:       2,
+					CompletedJobs:   1,
+					TotalBlockBytes: defaultTargetBytesPerRequest * 2,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.limit, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/?q="+url.QueryEscape(`{ resource.service.name = "frontend" }`)+"&start=1000&end=1060&limit="+tc.limit, nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+
+			resp, err := testRT.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			actualResp := &tempopb.TraceSummaryResponse{}
+			bytesResp, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(bytesResp), actualResp))
+
+			assert.Equal(t, tc.expected, actualResp)
+		})
+	}
+}
+
+func TestTraceSummarySharderRoundTripBadRequest(t *testing.T) {
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+
+	o, err := overrides.NewOverrides(overrides.Limits{})
+	require.NoError(t, err)
+
+	sharder := newTraceSummarySharder(&mockReader{}, o, SearchSharderConfig{
+		ConcurrentRequests:    defaultConcurrentRequests,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+		MaxDuration:           5 * time.Minute,
+	}, log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	query := url.QueryEscape("{}")
+
+	// no org id
+	req := httptest.NewRequest("GET", "/?q="+query+"&start=1000&end=1100", nil)
+	resp, err := testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "no org id")
+
+	// start/end outside of max duration
+	req = httptest.NewRequest("GET", "/?q="+query+"&start=1000&end=1500", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "range specified by start and end exceeds 5m0s. received start=1000 end=1500")
+
+	// metrics query
+	req = httptest.NewRequest("GET", "/?q="+url.QueryEscape("{} | rate()")+"&start=1000&end=1100", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(req)
+	testBadRequest(t, resp, err, "metrics queries are not supported by trace summaries")
+}
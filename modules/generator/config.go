@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/grafana/tempo/modules/generator/processor/attributeprofiler"
 	"github.com/grafana/tempo/modules/generator/processor/localblocks"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
@@ -52,15 +53,17 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 }
 
 type ProcessorConfig struct {
-	ServiceGraphs servicegraphs.Config `yaml:"service_graphs"`
-	SpanMetrics   spanmetrics.Config   `yaml:"span_metrics"`
-	LocalBlocks   localblocks.Config   `yaml:"local_blocks"`
+	ServiceGraphs     servicegraphs.Config     `yaml:"service_graphs"`
+	SpanMetrics       spanmetrics.Config       `yaml:"span_metrics"`
+	LocalBlocks       localblocks.Config       `yaml:"local_blocks"`
+	AttributeProfiler attributeprofiler.Config `yaml:"attribute_profiler"`
 }
 
 func (cfg *ProcessorConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.ServiceGraphs.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.SpanMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.LocalBlocks.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.AttributeProfiler.RegisterFlagsAndApplyDefaults(prefix, f)
 }
 
 // copyWithOverrides creates a copy of the config using values set in the overrides.
@@ -91,6 +94,9 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 	if filterPolicies := o.MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID); filterPolicies != nil {
 		copyCfg.SpanMetrics.FilterPolicies = filterPolicies
 	}
+	if customMetrics := o.MetricsGeneratorProcessorSpanMetricsCustomMetrics(userID); customMetrics != nil {
+		copyCfg.SpanMetrics.CustomMetrics = customMetrics
+	}
 
 	if max := o.MetricsGeneratorProcessorLocalBlocksMaxLiveTraces(userID); max > 0 {
 		copyCfg.LocalBlocks.MaxLiveTraces = max
@@ -122,5 +128,9 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 
 	copyCfg.ServiceGraphs.EnableClientServerPrefix = o.MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID)
 
+	if o.MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID) {
+		copyCfg.ServiceGraphs.EnableMessagingSystemLatencyHistogram = true
+	}
+
 	return copyCfg, nil
 }
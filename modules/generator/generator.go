@@ -337,3 +337,18 @@ func (g *Generator) GetMetrics(ctx context.Context, req *tempopb.SpanMetricsRequ
 
 	return instance.GetMetrics(ctx, req)
 }
+
+func (g *Generator) GetAttributeProfile(ctx context.Context, req *tempopb.AttributeProfileRequest) (*tempopb.AttributeProfileResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// return empty if we don't have an instance
+	instance, ok := g.getInstanceByID(instanceID)
+	if !ok || instance == nil {
+		return &tempopb.AttributeProfileResponse{}, nil
+	}
+
+	return instance.GetAttributeProfile(ctx, req)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/processor/attributeprofiler"
 	"github.com/grafana/tempo/modules/generator/processor/localblocks"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
@@ -25,7 +26,7 @@ import (
 )
 
 var (
-	allSupportedProcessors = []string{servicegraphs.Name, spanmetrics.Name, localblocks.Name}
+	allSupportedProcessors = []string{servicegraphs.Name, spanmetrics.Name, localblocks.Name, attributeprofiler.Name}
 
 	metricActiveProcessors = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempo",
@@ -250,6 +251,10 @@ func (i *instance) diffProcessors(desiredProcessors map[string]struct{}, desired
 			if !reflect.DeepEqual(p.Cfg, desiredCfg.LocalBlocks) {
 				toReplace = append(toReplace, processorName)
 			}
+		case *attributeprofiler.Processor:
+			if !reflect.DeepEqual(p.Cfg, desiredCfg.AttributeProfiler) {
+				toReplace = append(toReplace, processorName)
+			}
 		default:
 			level.Error(i.logger).Log(
 				"msg", fmt.Sprintf("processor does not exist, supported processors: [%s]", strings.Join(allSupportedProcessors, ", ")),
@@ -284,6 +289,8 @@ func (i *instance) addProcessor(processorName string, cfg ProcessorConfig) error
 			return err
 		}
 		newProcessor = p
+	case attributeprofiler.Name:
+		newProcessor = attributeprofiler.New(cfg.AttributeProfiler, i.instanceID)
 	default:
 		level.Error(i.logger).Log(
 			"msg", fmt.Sprintf("processor does not exist, supported processors: [%s]", strings.Join(allSupportedProcessors, ", ")),
@@ -376,6 +383,19 @@ func (i *instance) GetMetrics(ctx context.Context, req *tempopb.SpanMetricsReque
 	return nil, fmt.Errorf("localblocks processor not found")
 }
 
+func (i *instance) GetAttributeProfile(ctx context.Context, req *tempopb.AttributeProfileRequest) (*tempopb.AttributeProfileResponse, error) {
+	i.processorsMtx.RLock()
+	defer i.processorsMtx.RUnlock()
+
+	for _, processor := range i.processors {
+		if p, ok := processor.(*attributeprofiler.Processor); ok {
+			return p.GetAttributeProfile(ctx, req), nil
+		}
+	}
+
+	return nil, fmt.Errorf("attribute profiler processor not found")
+}
+
 func (i *instance) updatePushMetrics(bytesIngested int, spanCount int, expiredSpanCount int) {
 	metricBytesIngested.WithLabelValues(i.instanceID).Add(float64(bytesIngested))
 	metricSpansIngested.WithLabelValues(i.instanceID).Add(float64(spanCount))
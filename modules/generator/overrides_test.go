@@ -8,23 +8,25 @@ import (
 )
 
 type mockOverrides struct {
-	processors                            map[string]struct{}
-	serviceGraphsHistogramBuckets         []float64
-	serviceGraphsDimensions               []string
-	serviceGraphsPeerAttributes           []string
-	serviceGraphsEnableClientServerPrefix bool
-	spanMetricsHistogramBuckets           []float64
-	spanMetricsDimensions                 []string
-	spanMetricsIntrinsicDimensions        map[string]bool
-	spanMetricsFilterPolicies             []filterconfig.FilterPolicy
-	spanMetricsDimensionMappings          []sharedconfig.DimensionMappings
-	spanMetricsEnableTargetInfo           bool
-	localBlocksMaxLiveTraces              uint64
-	localBlocksMaxBlockDuration           time.Duration
-	localBlocksMaxBlockBytes              uint64
-	localBlocksFlushCheckPeriod           time.Duration
-	localBlocksTraceIdlePeriod            time.Duration
-	localBlocksCompleteBlockTimeout       time.Duration
+	processors                                         map[string]struct{}
+	serviceGraphsHistogramBuckets                      []float64
+	serviceGraphsDimensions                            []string
+	serviceGraphsPeerAttributes                        []string
+	serviceGraphsEnableClientServerPrefix              bool
+	serviceGraphsEnableMessagingSystemLatencyHistogram bool
+	spanMetricsHistogramBuckets                        []float64
+	spanMetricsDimensions                              []string
+	spanMetricsIntrinsicDimensions                     map[string]bool
+	spanMetricsFilterPolicies                          []filterconfig.FilterPolicy
+	spanMetricsDimensionMappings                       []sharedconfig.DimensionMappings
+	spanMetricsEnableTargetInfo                        bool
+	spanMetricsCustomMetrics                           sharedconfig.CustomMetrics
+	localBlocksMaxLiveTraces                           uint64
+	localBlocksMaxBlockDuration                        time.Duration
+	localBlocksMaxBlockBytes                           uint64
+	localBlocksFlushCheckPeriod                        time.Duration
+	localBlocksTraceIdlePeriod                         time.Duration
+	localBlocksCompleteBlockTimeout                    time.Duration
 }
 
 var _ metricsGeneratorOverrides = (*mockOverrides)(nil)
@@ -73,6 +75,10 @@ func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsFilterPolicies(userI
 	return m.spanMetricsFilterPolicies
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsCustomMetrics(userID string) sharedconfig.CustomMetrics {
+	return m.spanMetricsCustomMetrics
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorLocalBlocksMaxLiveTraces(userID string) uint64 {
 	return m.localBlocksMaxLiveTraces
 }
@@ -110,3 +116,7 @@ func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsEnableTargetInfo(use
 func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID string) bool {
 	return m.serviceGraphsEnableClientServerPrefix
 }
+
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool {
+	return m.serviceGraphsEnableMessagingSystemLatencyHistogram
+}
@@ -0,0 +1,103 @@
+package attributeprofiler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/hll"
+)
+
+// Combine merges the attribute profiles returned by several generators. Counts and bytes are summed,
+// cardinalities are estimated from the merged sketches. The sketches are not included in the result.
+func Combine(resps ...*tempopb.AttributeProfileResponse) (*tempopb.AttributeProfileResponse, error) {
+	type combinedAttribute struct {
+		profile *tempopb.AttributeProfile
+		types   map[string]struct{}
+		sketch  *hll.Sketch
+	}
+	type combinedService struct {
+		profile    *tempopb.ServiceAttributeProfile
+		attributes map[attributeKey]*combinedAttribute
+	}
+
+	services := map[string]*combinedService{}
+	for _, resp := range resps {
+		for _, svc := range resp.Services {
+			cs, ok := services[svc.Service]
+			if !ok {
+				cs = &combinedService{
+					profile:    &tempopb.ServiceAttributeProfile{Service: svc.Service},
+					attributes: map[attributeKey]*combinedAttribute{},
+				}
+				services[svc.Service] = cs
+			}
+			cs.profile.SpanCount += svc.SpanCount
+
+			for _, a := range svc.Attributes {
+				sketch, err := hll.Unmarshal(a.Sketch)
+				if err != nil {
+					return nil, fmt.Errorf("invalid sketch for attribute %s of service %s: %w", a.Key, svc.Service, err)
+				}
+
+				k := attributeKey{scope: a.Scope, key: a.Key}
+				ca, ok := cs.attributes[k]
+				if !ok {
+					ca = &combinedAttribute{
+						profile: &tempopb.AttributeProfile{Scope: a.Scope, Key: a.Key},
+						types:   map[string]struct{}{},
+						sketch:  hll.New(),
+					}
+					cs.attributes[k] = ca
+				}
+
+				ca.profile.Count += a.Count
+				ca.profile.Bytes += a.Bytes
+				for _, t := range a.Types {
+					ca.types[t] = struct{}{}
+				}
+				ca.sketch.Merge(sketch)
+			}
+		}
+	}
+
+	combined := &tempopb.AttributeProfileResponse{
+		Services: make([]*tempopb.ServiceAttributeProfile, 0, len(services)),
+	}
+	for _, cs := range services {
+		cs.profile.Attributes = make([]*tempopb.AttributeProfile, 0, len(cs.attributes))
+		for _, ca := range cs.attributes {
+			ca.profile.Types = sortedTypes(ca.types)
+			ca.profile.Cardinality = ca.sketch.Estimate()
+			cs.profile.Attributes = append(cs.profile.Attributes, ca.profile)
+		}
+		SortAttributes(cs.profile.Attributes)
+		combined.Services = append(combined.Services, cs.profile)
+	}
+
+	sort.Slice(combined.Services, func(i, j int) bool {
+		return combined.Services[i].Service < combined.Services[j].Service
+	})
+
+	return combined, nil
+}
+
+// ClearSketches removes the sketches from the response. They are only needed to combine responses.
+func ClearSketches(resp *tempopb.AttributeProfileResponse) {
+	for _, svc := range resp.Services {
+		for _, a := range svc.Attributes {
+			a.Sketch = nil
+		}
+	}
+}
+
+// sortedTypes returns the type names in the order of valueTypeNames
+func sortedTypes(types map[string]struct{}) []string {
+	names := make([]string, 0, len(types))
+	for _, n := range valueTypeNames {
+		if _, ok := types[n.name]; ok {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
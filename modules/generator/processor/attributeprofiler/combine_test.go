@@ -0,0 +1,73 @@
+package attributeprofiler
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestCombine(t *testing.T) {
+	// two generators that received overlapping values
+	a := newTestProcessor(Config{MaxAttributes: 100, StaleDuration: time.Hour})
+	b := newTestProcessor(Config{MaxAttributes: 100, StaleDuration: time.Hour})
+
+	for i := 0; i < 100; i++ {
+		a.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{
+			batch("svc", &v1.Span{Attributes: []*v1_common.KeyValue{stringKV("user.id", strconv.Itoa(i))}}),
+		}})
+		b.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{
+			batch("svc", &v1.Span{Attributes: []*v1_common.KeyValue{intKV("user.id", int64(i))}}),
+			batch("other", &v1.Span{}),
+		}})
+	}
+
+	combined, err := Combine(
+		a.GetAttributeProfile(context.Background(), &tempopb.AttributeProfileRequest{}),
+		b.GetAttributeProfile(context.Background(), &tempopb.AttributeProfileRequest{}),
+	)
+	require.NoError(t, err)
+	require.Len(t, combined.Services, 2)
+
+	assert.Equal(t, "other", combined.Services[0].Service)
+	assert.Equal(t, uint64(100), combined.Services[0].SpanCount)
+
+	svc := combined.Services[1]
+	assert.Equal(t, "svc", svc.Service)
+	assert.Equal(t, uint64(200), svc.SpanCount)
+	assert.Equal(t, []string{"resource/service.name", "span/user.id"}, keys(svc))
+
+	resource := svc.Attributes[0]
+	assert.Equal(t, uint64(200), resource.Count)
+	assert.Equal(t, uint64(1), resource.Cardinality)
+	assert.Empty(t, resource.Sketch)
+
+	userID := svc.Attributes[1]
+	assert.Equal(t, []string{"string", "int"}, userID.Types)
+	assert.Equal(t, uint64(200), userID.Count)
+	assert.InDelta(t, 200, userID.Cardinality, 10)
+}
+
+func TestCombineInvalidSketch(t *testing.T) {
+	_, err := Combine(&tempopb.AttributeProfileResponse{Services: []*tempopb.ServiceAttributeProfile{{
+		Service:    "svc",
+		Attributes: []*tempopb.AttributeProfile{{Key: "foo", Sketch: []byte{1}}},
+	}}})
+	assert.Error(t, err)
+}
+
+func TestClearSketches(t *testing.T) {
+	resp := &tempopb.AttributeProfileResponse{Services: []*tempopb.ServiceAttributeProfile{{
+		Attributes: []*tempopb.AttributeProfile{{Key: "foo", Sketch: []byte{1}}},
+	}}}
+
+	ClearSketches(resp)
+	assert.Nil(t, resp.Services[0].Attributes[0].Sketch)
+}
@@ -0,0 +1,24 @@
+package attributeprofiler
+
+import (
+	"flag"
+	"time"
+)
+
+const (
+	Name = "attribute-profiler"
+)
+
+type Config struct {
+	// MaxAttributes is the maximum number of attributes tracked per tenant. Every attribute uses about
+	// 1KB of memory for its cardinality sketch. New attributes are dropped once the limit is reached.
+	MaxAttributes int `yaml:"max_attributes"`
+
+	// StaleDuration is the duration after which attributes that haven't been seen are removed
+	StaleDuration time.Duration `yaml:"stale_duration"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	cfg.MaxAttributes = 10_000
+	cfg.StaleDuration = time.Hour
+}
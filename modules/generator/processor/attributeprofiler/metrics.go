@@ -0,0 +1,26 @@
+package attributeprofiler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	namespace = "tempo"
+	subsystem = "metrics_generator_processor_attribute_profiler"
+)
+
+var (
+	metricActiveAttributes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "active_attributes",
+		Help:      "Number of attributes tracked",
+	}, []string{"tenant"})
+	metricDroppedAttributes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "attributes_dropped_total",
+		Help:      "Number of attribute occurrences not profiled because max_attributes was reached",
+	}, []string{"tenant"})
+)
@@ -0,0 +1,333 @@
+package attributeprofiler
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util/hll"
+)
+
+const (
+	scopeResource = "resource"
+	scopeSpan     = "span"
+	scopeEvent    = "event"
+	scopeLink     = "link"
+
+	pruneInterval = time.Minute
+)
+
+// valueType is a bit set of the value types an attribute was seen with
+type valueType uint8
+
+const (
+	typeString valueType = 1 << iota
+	typeInt
+	typeDouble
+	typeBool
+	typeBytes
+	typeArray
+	typeKVList
+	typeEmpty
+)
+
+var valueTypeNames = []struct {
+	t    valueType
+	name string
+}{
+	{typeString, "string"},
+	{typeInt, "int"},
+	{typeDouble, "double"},
+	{typeBool, "bool"},
+	{typeBytes, "bytes"},
+	{typeArray, "array"},
+	{typeKVList, "kvlist"},
+	{typeEmpty, "empty"},
+}
+
+// Processor profiles the attributes of the received spans. Per service it tracks which attribute
+// keys appear, the types of their values, the approximate number of distinct values and the number
+// of bytes they contribute.
+type Processor struct {
+	Cfg Config
+
+	tenant string
+
+	mtx        sync.Mutex
+	services   map[string]*serviceProfile
+	attributes int
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+type serviceProfile struct {
+	spanCount  uint64
+	attributes map[attributeKey]*attributeProfile
+}
+
+type attributeKey struct {
+	scope string
+	key   string
+}
+
+type attributeProfile struct {
+	types    valueType
+	count    uint64
+	bytes    uint64
+	sketch   *hll.Sketch
+	lastSeen time.Time
+}
+
+var _ gen.Processor = (*Processor)(nil)
+
+func New(cfg Config, tenant string) *Processor {
+	p := &Processor{
+		Cfg:      cfg,
+		tenant:   tenant,
+		services: map[string]*serviceProfile{},
+		closeCh:  make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.pruneLoop()
+
+	return p
+}
+
+func (p *Processor) Name() string {
+	return Name
+}
+
+func (p *Processor) PushSpans(_ context.Context, req *tempopb.PushSpansRequest) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	now := time.Now()
+	dropped := 0
+
+	for _, batch := range req.Batches {
+		var resourceAttrs []*v1_common.KeyValue
+		if batch.Resource != nil {
+			resourceAttrs = batch.Resource.Attributes
+		}
+		svcName, _ := processor_util.FindServiceName(resourceAttrs)
+
+		svc, ok := p.services[svcName]
+		if !ok {
+			svc = &serviceProfile{attributes: map[attributeKey]*attributeProfile{}}
+			p.services[svcName] = svc
+		}
+
+		// resource attributes are stored once per batch
+		for _, kv := range resourceAttrs {
+			if !p.observe(svc, scopeResource, kv, now) {
+				dropped++
+			}
+		}
+
+		for _, ils := range batch.ScopeSpans {
+			svc.spanCount += uint64(len(ils.Spans))
+
+			for _, span := range ils.Spans {
+				for _, kv := range span.Attributes {
+					if !p.observe(svc, scopeSpan, kv, now) {
+						dropped++
+					}
+				}
+				for _, e := range span.Events {
+					for _, kv := range e.Attributes {
+						if !p.observe(svc, scopeEvent, kv, now) {
+							dropped++
+						}
+					}
+				}
+				for _, l := range span.Links {
+					for _, kv := range l.Attributes {
+						if !p.observe(svc, scopeLink, kv, now) {
+							dropped++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if dropped > 0 {
+		metricDroppedAttributes.WithLabelValues(p.tenant).Add(float64(dropped))
+	}
+	metricActiveAttributes.WithLabelValues(p.tenant).Set(float64(p.attributes))
+}
+
+// observe records an occurrence of the attribute. It returns false if the attribute isn't tracked
+// because the max number of attributes was reached. Must be called under lock.
+func (p *Processor) observe(svc *serviceProfile, scope string, kv *v1_common.KeyValue, now time.Time) bool {
+	k := attributeKey{scope: scope, key: kv.Key}
+
+	a, ok := svc.attributes[k]
+	if !ok {
+		if p.Cfg.MaxAttributes > 0 && p.attributes >= p.Cfg.MaxAttributes {
+			return false
+		}
+		a = &attributeProfile{sketch: hll.New()}
+		svc.attributes[k] = a
+		p.attributes++
+	}
+
+	t, hash := typeAndHash(kv.Value)
+	a.types |= t
+	a.count++
+	a.bytes += uint64(kv.Size())
+	a.sketch.Insert(hash)
+	a.lastSeen = now
+
+	return true
+}
+
+// GetAttributeProfile returns the profiles of all services or only the requested service. Services
+// are sorted by name, their attributes by the number of bytes they contribute.
+func (p *Processor) GetAttributeProfile(_ context.Context, req *tempopb.AttributeProfileRequest) *tempopb.AttributeProfileResponse {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	resp := &tempopb.AttributeProfileResponse{}
+
+	for svcName, svc := range p.services {
+		if req.Service != "" && req.Service != svcName {
+			continue
+		}
+
+		profile := &tempopb.ServiceAttributeProfile{
+			Service:    svcName,
+			SpanCount:  svc.spanCount,
+			Attributes: make([]*tempopb.AttributeProfile, 0, len(svc.attributes)),
+		}
+		for k, a := range svc.attributes {
+			profile.Attributes = append(profile.Attributes, &tempopb.AttributeProfile{
+				Scope:       k.scope,
+				Key:         k.key,
+				Types:       a.types.names(),
+				Count:       a.count,
+				Bytes:       a.bytes,
+				Cardinality: a.sketch.Estimate(),
+				Sketch:      a.sketch.Marshal(),
+			})
+		}
+		SortAttributes(profile.Attributes)
+
+		resp.Services = append(resp.Services, profile)
+	}
+
+	sort.Slice(resp.Services, func(i, j int) bool {
+		return resp.Services[i].Service < resp.Services[j].Service
+	})
+
+	return resp
+}
+
+func (p *Processor) Shutdown(_ context.Context) {
+	close(p.closeCh)
+	p.wg.Wait()
+
+	metricActiveAttributes.DeleteLabelValues(p.tenant)
+}
+
+func (p *Processor) pruneLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.prune(time.Now())
+		case <-p.closeCh:
+			return
+		}
+	}
+}
+
+// prune removes the attributes that haven't been seen for the stale duration and services without
+// attributes.
+func (p *Processor) prune(now time.Time) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	staleBefore := now.Add(-p.Cfg.StaleDuration)
+	for svcName, svc := range p.services {
+		for k, a := range svc.attributes {
+			if a.lastSeen.Before(staleBefore) {
+				delete(svc.attributes, k)
+				p.attributes--
+			}
+		}
+		if len(svc.attributes) == 0 {
+			delete(p.services, svcName)
+		}
+	}
+
+	metricActiveAttributes.WithLabelValues(p.tenant).Set(float64(p.attributes))
+}
+
+// SortAttributes sorts attribute profiles by the number of bytes they contribute, largest first.
+func SortAttributes(attrs []*tempopb.AttributeProfile) {
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].Bytes != attrs[j].Bytes {
+			return attrs[i].Bytes > attrs[j].Bytes
+		}
+		if attrs[i].Scope != attrs[j].Scope {
+			return attrs[i].Scope < attrs[j].Scope
+		}
+		return attrs[i].Key < attrs[j].Key
+	})
+}
+
+func typeAndHash(v *v1_common.AnyValue) (valueType, uint64) {
+	var buf [8]byte
+
+	switch v := v.GetValue().(type) {
+	case *v1_common.AnyValue_StringValue:
+		return typeString, xxhash.Sum64String(v.StringValue)
+	case *v1_common.AnyValue_IntValue:
+		binary.LittleEndian.PutUint64(buf[:], uint64(v.IntValue))
+		return typeInt, xxhash.Sum64(buf[:])
+	case *v1_common.AnyValue_DoubleValue:
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v.DoubleValue))
+		return typeDouble, xxhash.Sum64(buf[:])
+	case *v1_common.AnyValue_BoolValue:
+		if v.BoolValue {
+			buf[0] = 1
+		}
+		return typeBool, xxhash.Sum64(buf[:1])
+	case *v1_common.AnyValue_BytesValue:
+		return typeBytes, xxhash.Sum64(v.BytesValue)
+	case *v1_common.AnyValue_ArrayValue:
+		b, _ := v.ArrayValue.Marshal()
+		return typeArray, xxhash.Sum64(b)
+	case *v1_common.AnyValue_KvlistValue:
+		b, _ := v.KvlistValue.Marshal()
+		return typeKVList, xxhash.Sum64(b)
+	default:
+		return typeEmpty, 0
+	}
+}
+
+func (t valueType) names() []string {
+	var names []string
+	for _, n := range valueTypeNames {
+		if t&n.t != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,154 @@
+package attributeprofiler
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestProcessor(t *testing.T) {
+	p := newTestProcessor(Config{MaxAttributes: 100, StaleDuration: time.Hour})
+
+	spans := make([]*v1.Span, 0, 100)
+	for i := 0; i < 100; i++ {
+		spans = append(spans, &v1.Span{
+			Attributes: []*v1_common.KeyValue{
+				stringKV("http.method", "GET"),
+				stringKV("user.id", strconv.Itoa(i)),
+				intKV("http.status_code", 200),
+			},
+			Events: []*v1.Span_Event{{Attributes: []*v1_common.KeyValue{stringKV("exception.message", "boom")}}},
+		})
+	}
+	// the same key with a different type
+	spans[0].Attributes = append(spans[0].Attributes, stringKV("http.status_code", "OK"))
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{
+		batch("frontend", spans...),
+		batch("backend", &v1.Span{Links: []*v1.Span_Link{{Attributes: []*v1_common.KeyValue{stringKV("link.kind", "follows")}}}}),
+	}})
+
+	resp := p.GetAttributeProfile(context.Background(), &tempopb.AttributeProfileRequest{})
+	require.Len(t, resp.Services, 2)
+
+	backend := resp.Services[0]
+	assert.Equal(t, "backend", backend.Service)
+	assert.Equal(t, uint64(1), backend.SpanCount)
+	assert.Equal(t, []string{"resource/service.name", "link/link.kind"}, keys(backend))
+
+	frontend := resp.Services[1]
+	assert.Equal(t, "frontend", frontend.Service)
+	assert.Equal(t, uint64(100), frontend.SpanCount)
+	// sorted by bytes
+	assert.Equal(t, []string{"event/exception.message", "span/http.status_code", "span/http.method", "span/user.id", "resource/service.name"}, keys(frontend))
+
+	statusCode := frontend.Attributes[1]
+	assert.Equal(t, []string{"string", "int"}, statusCode.Types)
+	assert.Equal(t, uint64(101), statusCode.Count)
+	assert.Equal(t, uint64(2), statusCode.Cardinality)
+
+	method := frontend.Attributes[2]
+	assert.Equal(t, []string{"string"}, method.Types)
+	assert.Equal(t, uint64(100), method.Count)
+	assert.Equal(t, uint64(100*stringKV("http.method", "GET").Size()), method.Bytes)
+	assert.Equal(t, uint64(1), method.Cardinality)
+	assert.NotEmpty(t, method.Sketch)
+
+	userID := frontend.Attributes[3]
+	assert.InDelta(t, 100, userID.Cardinality, 5)
+
+	// filter by service
+	resp = p.GetAttributeProfile(context.Background(), &tempopb.AttributeProfileRequest{Service: "backend"})
+	require.Len(t, resp.Services, 1)
+	assert.Equal(t, "backend", resp.Services[0].Service)
+}
+
+func TestProcessorMaxAttributes(t *testing.T) {
+	p := newTestProcessor(Config{MaxAttributes: 2, StaleDuration: time.Hour})
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{
+		batch("svc", &v1.Span{Attributes: []*v1_common.KeyValue{stringKV("a", "a"), stringKV("b", "b")}}),
+	}})
+
+	resp := p.GetAttributeProfile(context.Background(), &tempopb.AttributeProfileRequest{})
+	require.Len(t, resp.Services, 1)
+	assert.Equal(t, []string{"resource/service.name", "span/a"}, keys(resp.Services[0]))
+	assert.Equal(t, uint64(1), resp.Services[0].SpanCount)
+}
+
+func TestProcessorPrune(t *testing.T) {
+	p := newTestProcessor(Config{MaxAttributes: 100, StaleDuration: time.Minute})
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{
+		batch("svc", &v1.Span{Attributes: []*v1_common.KeyValue{stringKV("a", "a")}}),
+	}})
+
+	p.prune(time.Now())
+	require.Len(t, p.GetAttributeProfile(context.Background(), &tempopb.AttributeProfileRequest{}).Services, 1)
+
+	p.prune(time.Now().Add(2 * time.Minute))
+	assert.Empty(t, p.GetAttributeProfile(context.Background(), &tempopb.AttributeProfileRequest{}).Services)
+	assert.Equal(t, 0, p.attributes)
+}
+
+func TestTypeAndHash(t *testing.T) {
+	values := []*v1_common.AnyValue{
+		{Value: &v1_common.AnyValue_StringValue{StringValue: "1"}},
+		{Value: &v1_common.AnyValue_IntValue{IntValue: 1}},
+		{Value: &v1_common.AnyValue_DoubleValue{DoubleValue: 1}},
+		{Value: &v1_common.AnyValue_BoolValue{BoolValue: true}},
+		{Value: &v1_common.AnyValue_BytesValue{BytesValue: []byte{1}}},
+		{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{Values: []*v1_common.AnyValue{{Value: &v1_common.AnyValue_IntValue{IntValue: 1}}}}}},
+		{Value: &v1_common.AnyValue_KvlistValue{KvlistValue: &v1_common.KeyValueList{Values: []*v1_common.KeyValue{stringKV("a", "1")}}}},
+		nil,
+	}
+
+	var all valueType
+	for _, v := range values {
+		typ, _ := typeAndHash(v)
+		all |= typ
+	}
+	assert.Equal(t, []string{"string", "int", "double", "bool", "bytes", "array", "kvlist", "empty"}, all.names())
+}
+
+func newTestProcessor(cfg Config) *Processor {
+	// not started with New to keep the prune loop out of the tests
+	return &Processor{
+		Cfg:      cfg,
+		tenant:   "test",
+		services: map[string]*serviceProfile{},
+		closeCh:  make(chan struct{}),
+	}
+}
+
+func batch(svc string, spans ...*v1.Span) *v1.ResourceSpans {
+	return &v1.ResourceSpans{
+		Resource:   &v1_resource.Resource{Attributes: []*v1_common.KeyValue{stringKV("service.name", svc)}},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+	}
+}
+
+func keys(svc *tempopb.ServiceAttributeProfile) []string {
+	var keys []string
+	for _, a := range svc.Attributes {
+		keys = append(keys, a.Scope+"/"+a.Key)
+	}
+	return keys
+}
+
+func stringKV(k, v string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+}
+
+func intKV(k string, v int64) *v1_common.KeyValue {
+	return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: v}}}
+}
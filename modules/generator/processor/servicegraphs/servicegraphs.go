@@ -50,12 +50,26 @@ const (
 	metricRequestFailedTotal   = "traces_service_graph_request_failed_total"
 	metricRequestServerSeconds = "traces_service_graph_request_server_seconds"
 	metricRequestClientSeconds = "traces_service_graph_request_client_seconds"
+
+	metricRequestMessagingSystemSeconds = "traces_service_graph_request_messaging_system_seconds"
 )
 
 var defaultPeerAttributes = []attribute.Key{
 	semconv.PeerServiceKey, semconv.DBNameKey, semconv.DBSystemKey,
 }
 
+// databaseNameAttributes are searched in order to find the name of the database a client span calls.
+// Client spans with any of these attributes are recorded as database edges.
+var databaseNameAttributes = []string{
+	string(semconv.DBNameKey), string(semconv.DBSystemKey),
+}
+
+// messagingDestinationAttributes are searched in order to find the queue or topic of producer and
+// consumer spans. messaging.destination was renamed in semantic conventions v1.17.
+var messagingDestinationAttributes = []string{
+	string(semconv.MessagingDestinationNameKey), "messaging.destination", string(semconv.MessagingSystemKey),
+}
+
 type tooManySpansError struct {
 	droppedSpans int
 }
@@ -77,6 +91,8 @@ type Processor struct {
 	serviceGraphRequestFailedTotal            registry.Counter
 	serviceGraphRequestServerSecondsHistogram registry.Histogram
 	serviceGraphRequestClientSecondsHistogram registry.Histogram
+	// only set if EnableMessagingSystemLatencyHistogram is enabled
+	serviceGraphRequestMessagingSystemSecondsHistogram registry.Histogram
 
 	metricDroppedSpans prometheus.Counter
 	metricTotalEdges   prometheus.Counter
@@ -111,6 +127,10 @@ func New(cfg Config, tenant string, registry registry.Registry, logger log.Logge
 		logger:             log.With(logger, "component", "service-graphs"),
 	}
 
+	if cfg.EnableMessagingSystemLatencyHistogram {
+		p.serviceGraphRequestMessagingSystemSecondsHistogram = registry.NewHistogram(metricRequestMessagingSystemSeconds, cfg.HistogramBuckets)
+	}
+
 	p.store = store.NewStore(cfg.Wait, cfg.MaxItems, p.onComplete, p.onExpire)
 
 	expirationTicker := time.NewTicker(2 * time.Second)
@@ -177,14 +197,18 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 						e.ConnectionType = connectionType
 						e.ClientService = svcName
 						e.ClientLatencySec = spanDurationSec(span)
+						e.ClientEndTimeUnixNano = span.EndTimeUnixNano
 						e.Failed = e.Failed || p.spanFailed(span)
 						p.upsertDimensions("client_", e.Dimensions, rs.Resource.Attributes, span.Attributes)
 						e.SpanMultiplier = spanMultiplier
 						p.upsertPeerNode(e, span.Attributes)
+						if connectionType == store.MessagingSystem {
+							upsertMessagingDestination(e, span.Attributes)
+						}
 
 						// A database request will only have one span, we don't wait for the server
 						// span but just copy details from the client span
-						if dbName, ok := processor_util.FindAttributeValue("db.name", rs.Resource.Attributes, span.Attributes); ok {
+						if dbName, ok := findAnyAttributeValue(databaseNameAttributes, rs.Resource.Attributes, span.Attributes); ok {
 							e.ConnectionType = store.Database
 							e.ServerService = dbName
 							e.ServerLatencySec = spanDurationSec(span)
@@ -202,10 +226,14 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 						e.ConnectionType = connectionType
 						e.ServerService = svcName
 						e.ServerLatencySec = spanDurationSec(span)
+						e.ServerStartTimeUnixNano = span.StartTimeUnixNano
 						e.Failed = e.Failed || p.spanFailed(span)
 						p.upsertDimensions("server_", e.Dimensions, rs.Resource.Attributes, span.Attributes)
 						e.SpanMultiplier = spanMultiplier
 						p.upsertPeerNode(e, span.Attributes)
+						if connectionType == store.MessagingSystem {
+							upsertMessagingDestination(e, span.Attributes)
+						}
 					})
 				default:
 					// this span is not part of an edge
@@ -260,6 +288,22 @@ func (p *Processor) upsertPeerNode(e *store.Edge, spanAttr []*v1_common.KeyValue
 	}
 }
 
+func upsertMessagingDestination(e *store.Edge, spanAttr []*v1_common.KeyValue) {
+	if v, ok := findAnyAttributeValue(messagingDestinationAttributes, spanAttr); ok {
+		e.MessagingDestination = v
+	}
+}
+
+// findAnyAttributeValue returns the value of the first of the keys present in the attributes
+func findAnyAttributeValue(keys []string, attributes ...[]*v1_common.KeyValue) (string, bool) {
+	for _, key := range keys {
+		if v, ok := processor_util.FindAttributeValue(key, attributes...); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
 func (p *Processor) Shutdown(_ context.Context) {
 	close(p.closeCh)
 }
@@ -285,11 +329,29 @@ func (p *Processor) onComplete(e *store.Edge) {
 
 	p.serviceGraphRequestServerSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ServerLatencySec, e.TraceID, e.SpanMultiplier)
 	p.serviceGraphRequestClientSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, e.TraceID, e.SpanMultiplier)
+
+	// the messaging system latency is only known if both the producer and the consumer span were received
+	if p.serviceGraphRequestMessagingSystemSecondsHistogram != nil && e.ConnectionType == store.MessagingSystem && e.ClientEndTimeUnixNano > 0 && e.ServerStartTimeUnixNano > 0 {
+		p.serviceGraphRequestMessagingSystemSecondsHistogram.ObserveWithExemplar(registryLabelValues, messagingSystemLatencySec(e), e.TraceID, e.SpanMultiplier)
+	}
 }
 
 func (p *Processor) onExpire(e *store.Edge) {
 	p.metricExpiredEdges.Inc()
 
+	// The other side of a messaging system edge might not be instrumented or might not be a child of
+	// this span, e.g. when a consumer processes a batch of messages. Instead of dropping the edge, we
+	// record it to the queue or topic.
+	if e.ConnectionType == store.MessagingSystem && len(e.MessagingDestination) > 0 {
+		if len(e.ClientService) == 0 {
+			e.ClientService = e.MessagingDestination
+		} else {
+			e.ServerService = e.MessagingDestination
+		}
+		p.onComplete(e)
+		return
+	}
+
 	// If an edge is expired, we check if there are signs that the missing span is belongs to a "virtual node".
 	// These are nodes that are outside the user's reach (eg. an external service for payment processing),
 	// or that are not instrumented (eg. a frontend application).
@@ -314,6 +376,16 @@ func (p *Processor) spanFailed(span *v1_trace.Span) bool {
 	return span.GetStatus().GetCode() == v1_trace.Status_STATUS_CODE_ERROR
 }
 
+// messagingSystemLatencySec returns the time between the end of the producer span and the start of
+// the consumer span. Producers might end their span after the message was consumed, this is recorded
+// as zero.
+func messagingSystemLatencySec(e *store.Edge) float64 {
+	if e.ServerStartTimeUnixNano <= e.ClientEndTimeUnixNano {
+		return 0
+	}
+	return float64(e.ServerStartTimeUnixNano-e.ClientEndTimeUnixNano) / float64(time.Second.Nanoseconds())
+}
+
 func spanDurationSec(span *v1_trace.Span) float64 {
 	return float64(span.EndTimeUnixNano-span.StartTimeUnixNano) / float64(time.Second.Nanoseconds())
 }
@@ -16,8 +16,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/tempo/modules/generator/processor/servicegraphs/store"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 )
 
 func TestServiceGraphs(t *testing.T) {
@@ -228,6 +232,125 @@ func TestServiceGraphs_virtualNodes(t *testing.T) {
 	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_failed_total`, clientToVirtualPeerLabels))
 }
 
+func TestServiceGraphs_messagingSystemLatencyHistogram(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	cfg.HistogramBuckets = []float64{0.01}
+	cfg.EnableMessagingSystemLatencyHistogram = true
+
+	p := New(cfg, "test", testRegistry, log.NewNopLogger())
+	defer p.Shutdown(context.Background())
+
+	request, err := loadTestData("testdata/trace-with-queue-database.json")
+	require.NoError(t, err)
+
+	p.PushSpans(context.Background(), request)
+
+	requesterToRecorderLabels := labels.FromMap(map[string]string{
+		"client":          "mythical-requester",
+		"server":          "mythical-recorder",
+		"connection_type": "messaging_system",
+	})
+	requesterToServerLabels := labels.FromMap(map[string]string{
+		"client":          "mythical-requester",
+		"server":          "mythical-server",
+		"connection_type": "",
+	})
+
+	// time between the end of the producer span and the start of the consumer span
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_bucket`, withLe(requesterToRecorderLabels, 0.01)))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_count`, requesterToRecorderLabels))
+	assert.InDelta(t, 0.009882, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_sum`, requesterToRecorderLabels), 0.000001)
+
+	// only recorded for messaging system edges
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_count`, requesterToServerLabels))
+}
+
+func TestServiceGraphs_databaseAndMessagingDestinations(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	cfg.Wait = time.Nanosecond
+	cfg.EnableMessagingSystemLatencyHistogram = true
+
+	p := New(cfg, "test", testRegistry, log.NewNopLogger())
+	defer p.Shutdown(context.Background())
+
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	span := func(id byte, parentID byte, kind v1_trace.Span_SpanKind, attrs ...*v1_common.KeyValue) *v1_trace.Span {
+		s := &v1_trace.Span{
+			TraceId:           traceID,
+			SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+			Kind:              kind,
+			StartTimeUnixNano: 1_000_000_000,
+			EndTimeUnixNano:   1_500_000_000,
+			Attributes:        attrs,
+		}
+		if parentID != 0 {
+			s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parentID}
+		}
+		return s
+	}
+	batch := func(svc string, spans ...*v1_trace.Span) *v1_trace.ResourceSpans {
+		return &v1_trace.ResourceSpans{
+			Resource:   &v1_resource.Resource{Attributes: []*v1_common.KeyValue{stringKV("service.name", svc)}},
+			ScopeSpans: []*v1_trace.ScopeSpans{{Spans: spans}},
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{
+		batch("app",
+			// database client span without db.name
+			span(1, 0, v1_trace.Span_SPAN_KIND_CLIENT, stringKV("db.system", "redis")),
+			// producer span without consumer
+			span(2, 0, v1_trace.Span_SPAN_KIND_PRODUCER, stringKV("messaging.system", "kafka"), stringKV("messaging.destination.name", "orders")),
+		),
+		batch("worker",
+			// consumer span without producer, using the old destination attribute
+			span(3, 9, v1_trace.Span_SPAN_KIND_CONSUMER, stringKV("messaging.system", "rabbitmq"), stringKV("messaging.destination", "invoices")),
+		),
+	}})
+
+	// database edges don't wait for the server span
+	appToRedisLabels := labels.FromMap(map[string]string{
+		"client":          "app",
+		"server":          "redis",
+		"connection_type": "database",
+	})
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, appToRedisLabels))
+
+	p.(*Processor).store.Expire()
+
+	appToOrdersLabels := labels.FromMap(map[string]string{
+		"client":          "app",
+		"server":          "orders",
+		"connection_type": "messaging_system",
+	})
+	invoicesToWorkerLabels := labels.FromMap(map[string]string{
+		"client":          "invoices",
+		"server":          "worker",
+		"connection_type": "messaging_system",
+	})
+
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, appToOrdersLabels))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, invoicesToWorkerLabels))
+
+	// the latency in the messaging system is unknown if only one side was received
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_count`, appToOrdersLabels))
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_count`, invoicesToWorkerLabels))
+}
+
+func TestMessagingSystemLatencySec(t *testing.T) {
+	assert.Equal(t, 1.5, messagingSystemLatencySec(&store.Edge{ClientEndTimeUnixNano: 1_000_000_000, ServerStartTimeUnixNano: 2_500_000_000}))
+	// the producer span ended after the consumer span started
+	assert.Equal(t, 0.0, messagingSystemLatencySec(&store.Edge{ClientEndTimeUnixNano: 2_500_000_000, ServerStartTimeUnixNano: 1_000_000_000}))
+}
+
 func loadTestData(path string) (*tempopb.PushSpansRequest, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -239,6 +362,10 @@ func loadTestData(path string) (*tempopb.PushSpansRequest, error) {
 	return &tempopb.PushSpansRequest{Batches: trace.Batches}, err
 }
 
+func stringKV(k, v string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+}
+
 func withLe(lbls labels.Labels, le float64) labels.Labels {
 	lb := labels.NewBuilder(lbls)
 	lb = lb.Set(labels.BucketLabel, strconv.FormatFloat(le, 'f', -1, 64))
@@ -30,6 +30,14 @@ type Edge struct {
 	// PeerNode is the attribute that will be used to create a peer edge
 	PeerNode string
 
+	// MessagingDestination is the queue or topic of a messaging system edge. It's used as node if the
+	// other side of the edge is never received.
+	MessagingDestination string
+
+	// End of the client span and start of the server span, used to measure the time messages spend
+	// in the messaging system
+	ClientEndTimeUnixNano, ServerStartTimeUnixNano uint64
+
 	// expiration is the time at which the Edge expires, expressed as Unix time
 	expiration int64
 
@@ -45,6 +45,10 @@ type Config struct {
 
 	// FilterPolicies is a list of policies that will be applied to spans for inclusion or exlusion.
 	FilterPolicies []filterconfig.FilterPolicy `yaml:"filter_policies"`
+
+	// CustomMetrics are additional metric families recorded from numeric attributes or the events of
+	// spans. They have their own dimensions and filter policies, FilterPolicies doesn't apply to them.
+	CustomMetrics sharedconfig.CustomMetrics `yaml:"custom_metrics"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -0,0 +1,142 @@
+package spanmetrics
+
+import (
+	"fmt"
+	"strconv"
+
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	"github.com/grafana/tempo/pkg/spanfilter"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+)
+
+type customMetric struct {
+	cfg    sharedconfig.CustomMetric
+	labels []string
+	filter *spanfilter.SpanFilter
+
+	// only one of them is set, depending on the type
+	counter   registry.Counter
+	histogram registry.Histogram
+}
+
+func newCustomMetrics(cfg sharedconfig.CustomMetrics, intrinsicLabels []string, reg registry.Registry) ([]*customMetric, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	metrics := make([]*customMetric, 0, len(cfg))
+	for _, c := range cfg {
+		switch c.Name {
+		case metricCallsTotal, metricDurationSeconds, metricSizeTotal, targetInfo:
+			return nil, fmt.Errorf("custom metric name %s is used by the span metrics processor", c.Name)
+		}
+
+		filter, err := spanfilter.NewSpanFilter(c.FilterPolicies)
+		if err != nil {
+			return nil, err
+		}
+
+		labels := make([]string, 0, len(intrinsicLabels)+len(c.Dimensions))
+		labels = append(labels, intrinsicLabels...)
+		for _, d := range c.Dimensions {
+			labels = append(labels, sanitizeLabelNameWithCollisions(d))
+		}
+
+		m := &customMetric{
+			cfg:    c,
+			labels: labels,
+			filter: filter,
+		}
+		switch c.Type {
+		case sharedconfig.CustomMetricTypeCounter:
+			m.counter = reg.NewCounter(c.Name)
+		case sharedconfig.CustomMetricTypeHistogram:
+			m.histogram = reg.NewHistogram(c.Name, c.HistogramBuckets)
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
+}
+
+// record records the value of the span if it matches the filter policies. intrinsicValues must be in
+// the order of the intrinsic labels.
+func (m *customMetric) record(reg registry.Registry, rs *v1.Resource, span *v1_trace.Span, intrinsicValues []string, spanMultiplier float64) {
+	if !m.filter.ApplyFilterPolicy(rs, span) {
+		return
+	}
+
+	value, ok := m.value(rs, span)
+	if !ok {
+		return
+	}
+
+	labelValues := make([]string, 0, len(m.labels))
+	labelValues = append(labelValues, intrinsicValues...)
+	for _, d := range m.cfg.Dimensions {
+		v, _ := processor_util.FindAttributeValue(d, rs.Attributes, span.Attributes)
+		labelValues = append(labelValues, v)
+	}
+	registryLabelValues := reg.NewLabelValueCombo(m.labels, labelValues)
+
+	if m.counter != nil {
+		m.counter.Inc(registryLabelValues, value*spanMultiplier)
+	}
+	if m.histogram != nil {
+		m.histogram.ObserveWithExemplar(registryLabelValues, value, tempo_util.TraceIDToHexString(span.TraceId), spanMultiplier)
+	}
+}
+
+// value returns the value recorded for the span. Spans without the attribute don't have a value, counters
+// skip spans without events.
+func (m *customMetric) value(rs *v1.Resource, span *v1_trace.Span) (float64, bool) {
+	switch m.cfg.Source {
+	case sharedconfig.CustomMetricSourceAttribute:
+		if v, ok := findNumericAttribute(m.cfg.Attribute, span.Attributes); ok {
+			return v, true
+		}
+		return findNumericAttribute(m.cfg.Attribute, rs.Attributes)
+
+	case sharedconfig.CustomMetricSourceEvents:
+		count := 0
+		for _, e := range span.Events {
+			if m.cfg.EventName == "" || e.Name == m.cfg.EventName {
+				count++
+			}
+		}
+		if count == 0 && m.counter != nil {
+			return 0, false
+		}
+		return float64(count), true
+	}
+
+	return 0, false
+}
+
+// findNumericAttribute returns the value of an int or double attribute. String values are parsed,
+// some instrumentations record numbers as strings.
+func findNumericAttribute(key string, attributes []*v1_common.KeyValue) (float64, bool) {
+	for _, kv := range attributes {
+		if kv.Key != key {
+			continue
+		}
+
+		switch v := kv.GetValue().GetValue().(type) {
+		case *v1_common.AnyValue_IntValue:
+			return float64(v.IntValue), true
+		case *v1_common.AnyValue_DoubleValue:
+			return v.DoubleValue, true
+		case *v1_common.AnyValue_StringValue:
+			f, err := strconv.ParseFloat(v.StringValue, 64)
+			return f, err == nil
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,166 @@
+package spanmetrics
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSpanMetrics_customMetrics(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.HistogramBuckets = []float64{0.5, 1}
+	cfg.Dimensions = []string{"foo"}
+	// the filter policies of the processor don't apply to custom metrics
+	cfg.FilterPolicies = []filterconfig.FilterPolicy{{
+		Include: &filterconfig.PolicyMatch{
+			MatchType:  filterconfig.Strict,
+			Attributes: []filterconfig.MatchPolicyAttribute{{Key: "span.nope", Value: "nothere"}},
+		},
+	}}
+	cfg.CustomMetrics = sharedconfig.CustomMetrics{
+		{
+			Name:             "traces_spanmetrics_response_size",
+			Type:             sharedconfig.CustomMetricTypeHistogram,
+			Source:           sharedconfig.CustomMetricSourceAttribute,
+			Attribute:        "http.response_content_length",
+			HistogramBuckets: []float64{100, 1000},
+			Dimensions:       []string{"http.method"},
+		},
+		{
+			Name:      "traces_spanmetrics_exceptions_total",
+			Type:      sharedconfig.CustomMetricTypeCounter,
+			Source:    sharedconfig.CustomMetricSourceEvents,
+			EventName: "exception",
+			FilterPolicies: []filterconfig.FilterPolicy{{
+				Include: &filterconfig.PolicyMatch{
+					MatchType:  filterconfig.Strict,
+					Attributes: []filterconfig.MatchPolicyAttribute{{Key: "span.http.method", Value: "GET"}},
+				},
+			}},
+		},
+	}
+
+	p, err := New(cfg, testRegistry, filteredSpansCounter)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(10, nil)
+	i := 0
+	for _, ss := range batch.ScopeSpans {
+		for _, s := range ss.Spans {
+			method := "GET"
+			if i%2 == 1 {
+				method = "POST"
+			}
+			s.Attributes = []*common_v1.KeyValue{
+				stringKV("http.method", method),
+				{Key: "http.response_content_length", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_IntValue{IntValue: int64(i * 100)}}},
+			}
+			s.Events = []*trace_v1.Span_Event{{Name: "exception"}, {Name: "exception"}, {Name: "log"}}
+			i++
+		}
+	}
+	// a span without the attribute is not recorded by the histogram
+	batch.ScopeSpans[0].Spans[0].Attributes = batch.ScopeSpans[0].Spans[0].Attributes[:1]
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	intrinsic := map[string]string{
+		"service":     "test-service",
+		"span_name":   "test",
+		"span_kind":   "SPAN_KIND_CLIENT",
+		"status_code": "STATUS_CODE_OK",
+	}
+
+	// all spans are filtered by the processor
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_calls_total", labels.FromMap(mergeLabels(intrinsic, map[string]string{"foo": ""}))))
+
+	get := labels.FromMap(mergeLabels(intrinsic, map[string]string{"http_method": "GET"}))
+	post := labels.FromMap(mergeLabels(intrinsic, map[string]string{"http_method": "POST"}))
+
+	// GET spans have 200, 400, 600, 800
+	assert.Equal(t, 4.0, testRegistry.Query("traces_spanmetrics_response_size_count", get))
+	assert.Equal(t, 2000.0, testRegistry.Query("traces_spanmetrics_response_size_sum", get))
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_response_size_bucket", withLe(get, 100)))
+	assert.Equal(t, 4.0, testRegistry.Query("traces_spanmetrics_response_size_bucket", withLe(get, 1000)))
+	// POST spans have 100, 300, 500, 700, 900
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_response_size_count", post))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanmetrics_response_size_bucket", withLe(post, 100)))
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_response_size_bucket", withLe(post, math.Inf(1))))
+
+	// 5 GET spans with 2 exception events
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_exceptions_total", labels.FromMap(intrinsic)))
+}
+
+func TestSpanMetrics_customMetricsInvalid(t *testing.T) {
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+
+	for _, customMetrics := range []sharedconfig.CustomMetrics{
+		{{Name: "traces_spanmetrics_calls_total", Type: sharedconfig.CustomMetricTypeCounter, Source: sharedconfig.CustomMetricSourceEvents}},
+		{{Name: "invalid-name", Type: sharedconfig.CustomMetricTypeCounter, Source: sharedconfig.CustomMetricSourceEvents}},
+		{{Name: "no_buckets", Type: sharedconfig.CustomMetricTypeHistogram, Source: sharedconfig.CustomMetricSourceEvents}},
+		{{Name: "no_attribute", Type: sharedconfig.CustomMetricTypeCounter, Source: sharedconfig.CustomMetricSourceAttribute}},
+		{
+			{Name: "duplicate", Type: sharedconfig.CustomMetricTypeCounter, Source: sharedconfig.CustomMetricSourceEvents},
+			{Name: "duplicate", Type: sharedconfig.CustomMetricTypeCounter, Source: sharedconfig.CustomMetricSourceEvents},
+		},
+	} {
+		cfg := Config{}
+		cfg.RegisterFlagsAndApplyDefaults("", nil)
+		cfg.CustomMetrics = customMetrics
+
+		_, err := New(cfg, registry.NewTestRegistry(), filteredSpansCounter)
+		assert.Error(t, err, customMetrics[0].Name)
+	}
+}
+
+func TestFindNumericAttribute(t *testing.T) {
+	attributes := []*common_v1.KeyValue{
+		{Key: "int", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_IntValue{IntValue: 3}}},
+		{Key: "double", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_DoubleValue{DoubleValue: 1.5}}},
+		stringKV("string", "2.5"),
+		stringKV("text", "foo"),
+		{Key: "bool", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_BoolValue{BoolValue: true}}},
+	}
+
+	for key, expected := range map[string]float64{"int": 3, "double": 1.5, "string": 2.5} {
+		v, ok := findNumericAttribute(key, attributes)
+		assert.True(t, ok, key)
+		assert.Equal(t, expected, v, key)
+	}
+	for _, key := range []string{"text", "bool", "missing"} {
+		_, ok := findNumericAttribute(key, attributes)
+		assert.False(t, ok, key)
+	}
+}
+
+func stringKV(k, v string) *common_v1.KeyValue {
+	return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}}}
+}
+
+func mergeLabels(a, b map[string]string) map[string]string {
+	m := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
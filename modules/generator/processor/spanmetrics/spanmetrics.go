@@ -35,6 +35,10 @@ type Processor struct {
 	spanMetricsSizeTotal       registry.Counter
 	spanMetricsTargetInfo      registry.Gauge
 	labels                     []string
+	// intrinsicLabels are the labels of the enabled intrinsic dimensions, they are a prefix of labels
+	intrinsicLabels []string
+
+	customMetrics []*customMetric
 
 	filter               *spanfilter.SpanFilter
 	filteredSpansCounter prometheus.Counter
@@ -62,6 +66,8 @@ func New(cfg Config, registry registry.Registry, spanDiscardCounter prometheus.C
 		labels = append(labels, dimStatusMessage)
 	}
 
+	intrinsicLabels := labels[:len(labels):len(labels)]
+
 	for _, d := range cfg.Dimensions {
 		labels = append(labels, sanitizeLabelNameWithCollisions(d))
 	}
@@ -76,6 +82,7 @@ func New(cfg Config, registry registry.Registry, spanDiscardCounter prometheus.C
 		spanMetricsTargetInfo: registry.NewGauge(targetInfo),
 		now:                   time.Now,
 		labels:                labels,
+		intrinsicLabels:       intrinsicLabels,
 		filteredSpansCounter:  spanDiscardCounter,
 	}
 
@@ -94,6 +101,11 @@ func New(cfg Config, registry registry.Registry, spanDiscardCounter prometheus.C
 		return nil, err
 	}
 
+	p.customMetrics, err = newCustomMetrics(cfg.CustomMetrics, intrinsicLabels, registry)
+	if err != nil {
+		return nil, err
+	}
+
 	p.filteredSpansCounter = spanDiscardCounter
 	p.filter = filter
 	return p, nil
@@ -126,6 +138,10 @@ func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
 		}
 		for _, ils := range rs.ScopeSpans {
 			for _, span := range ils.Spans {
+				if len(p.customMetrics) > 0 {
+					p.aggregateCustomMetricsForSpan(svcName, rs.Resource, span)
+				}
+
 				if p.filter.ApplyFilterPolicy(rs.Resource, span) {
 					p.aggregateMetricsForSpan(svcName, jobName, instanceID, rs.Resource, span, resourceLabels, resourceValues)
 					continue
@@ -148,21 +164,7 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 	copy(targetInfoLabelValues, resourceValues)
 
 	// important: the order of labelValues must correspond to the order of labels / intrinsic dimensions
-	if p.Cfg.IntrinsicDimensions.Service {
-		labelValues = append(labelValues, svcName)
-	}
-	if p.Cfg.IntrinsicDimensions.SpanName {
-		labelValues = append(labelValues, span.GetName())
-	}
-	if p.Cfg.IntrinsicDimensions.SpanKind {
-		labelValues = append(labelValues, span.GetKind().String())
-	}
-	if p.Cfg.IntrinsicDimensions.StatusCode {
-		labelValues = append(labelValues, span.GetStatus().GetCode().String())
-	}
-	if p.Cfg.IntrinsicDimensions.StatusMessage {
-		labelValues = append(labelValues, span.GetStatus().GetMessage())
-	}
+	labelValues = p.appendIntrinsicLabelValues(labelValues, svcName, span)
 
 	for _, d := range p.Cfg.Dimensions {
 		value, _ := processor_util.FindAttributeValue(d, rs.Attributes, span.Attributes)
@@ -239,6 +241,36 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 
 }
 
+func (p *Processor) aggregateCustomMetricsForSpan(svcName string, rs *v1.Resource, span *v1_trace.Span) {
+	intrinsicValues := p.appendIntrinsicLabelValues(make([]string, 0, len(p.intrinsicLabels)), svcName, span)
+	spanMultiplier := processor_util.GetSpanMultiplier(p.Cfg.SpanMultiplierKey, span)
+
+	for _, m := range p.customMetrics {
+		m.record(p.registry, rs, span, intrinsicValues, spanMultiplier)
+	}
+}
+
+// appendIntrinsicLabelValues appends the values of the enabled intrinsic dimensions in the order of
+// intrinsicLabels.
+func (p *Processor) appendIntrinsicLabelValues(labelValues []string, svcName string, span *v1_trace.Span) []string {
+	if p.Cfg.IntrinsicDimensions.Service {
+		labelValues = append(labelValues, svcName)
+	}
+	if p.Cfg.IntrinsicDimensions.SpanName {
+		labelValues = append(labelValues, span.GetName())
+	}
+	if p.Cfg.IntrinsicDimensions.SpanKind {
+		labelValues = append(labelValues, span.GetKind().String())
+	}
+	if p.Cfg.IntrinsicDimensions.StatusCode {
+		labelValues = append(labelValues, span.GetStatus().GetCode().String())
+	}
+	if p.Cfg.IntrinsicDimensions.StatusMessage {
+		labelValues = append(labelValues, span.GetStatus().GetMessage())
+	}
+	return labelValues
+}
+
 func sanitizeLabelNameWithCollisions(name string) string {
 	sanitized := strutil.SanitizeLabelName(name)
 
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/ring"
 
+	"github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb"
 )
@@ -26,8 +27,20 @@ type Config struct {
 	CompleteBlockTimeout time.Duration `yaml:"complete_block_timeout"`
 	OverrideRingKey      string        `yaml:"override_ring_key"`
 	FlushAllOnShutdown   bool          `yaml:"flush_all_on_shutdown"`
+	MaxTransferRetries   int           `yaml:"max_transfer_retries"`
 
-	AutocompleteFilteringEnabled bool `yaml:"-"`
+	LateSpans LateSpansConfig `yaml:"late_spans"`
+
+	AutocompleteFilteringEnabled bool          `yaml:"-"`
+	IngesterClientConfig         client.Config `yaml:"-"`
+}
+
+// LateSpansConfig configures the handling of spans received after their trace was cut.
+type LateSpansConfig struct {
+	// Enabled tracks recently cut traces and writes late fragments merged with the earlier fragments of the trace
+	Enabled bool `yaml:"enabled"`
+	// MaxTracesPerTenant is the number of recently cut traces tracked per tenant
+	MaxTracesPerTenant int `yaml:"max_traces_per_tenant"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -42,10 +55,12 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.FlushCheckPeriod = 10 * time.Second
 	cfg.FlushOpTimeout = 5 * time.Minute
 	cfg.FlushAllOnShutdown = false
+	cfg.LateSpans.MaxTracesPerTenant = 100_000
 
 	f.DurationVar(&cfg.MaxTraceIdle, prefix+".trace-idle-period", 10*time.Second, "Duration after which to consider a trace complete if no spans have been received")
 	f.DurationVar(&cfg.MaxBlockDuration, prefix+".max-block-duration", 30*time.Minute, "Maximum duration which the head block can be appended to before cutting it.")
 	f.Uint64Var(&cfg.MaxBlockBytes, prefix+".max-block-bytes", 500*1024*1024, "Maximum size of the head block before cutting it.")
+	f.IntVar(&cfg.MaxTransferRetries, prefix+".max-transfer-retries", 0, "Number of times to try to transfer the live traces to a pending ingester on shutdown. 0 disables transfers.")
 	f.DurationVar(&cfg.CompleteBlockTimeout, prefix+".complete-block-timeout", 3*tempodb.DefaultBlocklistPoll, "Duration to keep blocks in the ingester after they have been flushed.")
 
 	hostname, err := os.Hostname()
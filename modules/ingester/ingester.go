@@ -281,6 +281,27 @@ func (i *Ingester) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequ
 	}, nil
 }
 
+// FlushedBlocks implements tempopb.Querier.
+func (i *Ingester) FlushedBlocks(ctx context.Context, _ *tempopb.FlushedBlocksRequest) (*tempopb.FlushedBlocksResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inst, ok := i.getInstanceByID(instanceID)
+	if !ok || inst == nil {
+		return &tempopb.FlushedBlocksResponse{}, nil
+	}
+
+	metas, err := inst.FlushedBlocks()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tempopb.FlushedBlocksResponse{
+		Metas: metas,
+	}, nil
+}
+
 func (i *Ingester) CheckReady(ctx context.Context) error {
 	if err := i.lifecycler.CheckReady(ctx); err != nil {
 		return fmt.Errorf("ingester check ready failed %w", err)
@@ -300,7 +321,7 @@ func (i *Ingester) getOrCreateInstance(instanceID string) (*instance, error) {
 	inst, ok = i.instances[instanceID]
 	if !ok {
 		var err error
-		inst, err = newInstance(instanceID, i.limiter, i.store, i.local, i.cfg.AutocompleteFilteringEnabled)
+		inst, err = newInstance(instanceID, i.limiter, i.store, i.local, i.cfg.LateSpans, i.cfg.AutocompleteFilteringEnabled)
 		if err != nil {
 			return nil, err
 		}
@@ -336,11 +357,6 @@ func (i *Ingester) stopIncomingRequests() {
 	i.readonly = true
 }
 
-// TransferOut implements ring.Lifecycler.
-func (i *Ingester) TransferOut(ctx context.Context) error {
-	return ring.ErrTransferDisabled
-}
-
 func (i *Ingester) replayWal() error {
 	level.Info(log.Logger).Log("msg", "beginning wal replay")
 
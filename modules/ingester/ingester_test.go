@@ -3,6 +3,7 @@ package ingester
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"os"
 	"testing"
 	"time"
@@ -339,8 +340,52 @@ func TestFlush(t *testing.T) {
 	}
 }
 
+func TestFlushedBlocks(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+
+	inst, ok := ingester.getInstanceByID("test")
+	require.True(t, ok)
+	require.NoError(t, inst.CutCompleteTraces(0, true))
+	blockID, err := inst.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	require.NoError(t, inst.CompleteBlock(blockID))
+
+	// blocks are returned once flushed
+	resp, err := ingester.FlushedBlocks(ctx, &tempopb.FlushedBlocksRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Metas)
+
+	retry, err := ingester.handleFlush(ctx, "test", blockID)
+	require.NoError(t, err)
+	require.False(t, retry)
+
+	resp, err = ingester.FlushedBlocks(ctx, &tempopb.FlushedBlocksRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Metas, 1)
+
+	meta := &backend.BlockMeta{}
+	require.NoError(t, json.Unmarshal(resp.Metas[0], meta))
+	require.Equal(t, blockID, meta.BlockID)
+	require.Equal(t, "test", meta.TenantID)
+
+	// and until cleared
+	require.NoError(t, inst.ClearFlushedBlocks(0))
+	resp, err = ingester.FlushedBlocks(ctx, &tempopb.FlushedBlocksRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Metas)
+
+	// unknown tenants don't have flushed blocks
+	resp, err = ingester.FlushedBlocks(user.InjectOrgID(context.Background(), "unknown"), &tempopb.FlushedBlocksRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Metas)
+}
+
 func defaultIngesterModule(t testing.TB, tmpDir string) *Ingester {
-	ingesterConfig := defaultIngesterTestConfig()
+	return defaultIngesterModuleWithConfig(t, tmpDir, defaultIngesterTestConfig())
+}
+
+func defaultIngesterModuleWithConfig(t testing.TB, tmpDir string, ingesterConfig Config) *Ingester {
 	limits, err := overrides.NewOverrides(defaultLimitsTestConfig())
 	require.NoError(t, err, "unexpected error creating overrides")
 
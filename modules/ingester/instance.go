@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"hash"
 	"hash/fnv"
@@ -97,6 +98,8 @@ type instance struct {
 	traces     map[uint32]*liveTrace
 	traceSizes map[uint32]uint32
 	traceCount atomic.Int32
+	// recentlyCut tracks the traces cut recently to detect late spans, nil if disabled
+	recentlyCut *recentTraces
 
 	headBlockMtx sync.RWMutex
 	headBlock    common.WALBlock
@@ -107,11 +110,14 @@ type instance struct {
 
 	lastBlockCut time.Time
 
-	instanceID         string
-	tracesCreatedTotal prometheus.Counter
-	bytesReceivedTotal *prometheus.CounterVec
-	limiter            *Limiter
-	writer             tempodb.Writer
+	instanceID             string
+	tracesCreatedTotal     prometheus.Counter
+	bytesReceivedTotal     *prometheus.CounterVec
+	lateSpansTotal         prometheus.Counter
+	lateSpansDupBytes      prometheus.Counter
+	lateSpansMergeFailures prometheus.Counter
+	limiter                *Limiter
+	writer                 tempodb.Writer
 
 	local       *local.Backend
 	localReader backend.Reader
@@ -122,16 +128,19 @@ type instance struct {
 	autocompleteFilteringEnabled bool
 }
 
-func newInstance(instanceID string, limiter *Limiter, writer tempodb.Writer, l *local.Backend, autocompleteFiltering bool) (*instance, error) {
+func newInstance(instanceID string, limiter *Limiter, writer tempodb.Writer, l *local.Backend, lateSpans LateSpansConfig, autocompleteFiltering bool) (*instance, error) {
 	i := &instance{
 		traces:     map[uint32]*liveTrace{},
 		traceSizes: map[uint32]uint32{},
 
-		instanceID:         instanceID,
-		tracesCreatedTotal: metricTracesCreatedTotal.WithLabelValues(instanceID),
-		bytesReceivedTotal: metricBytesReceivedTotal,
-		limiter:            limiter,
-		writer:             writer,
+		instanceID:             instanceID,
+		tracesCreatedTotal:     metricTracesCreatedTotal.WithLabelValues(instanceID),
+		bytesReceivedTotal:     metricBytesReceivedTotal,
+		lateSpansTotal:         metricLateSpansTotal.WithLabelValues(instanceID),
+		lateSpansDupBytes:      metricLateSpansDuplicatedBytesTotal.WithLabelValues(instanceID),
+		lateSpansMergeFailures: metricLateSpansMergeFailuresTotal.WithLabelValues(instanceID),
+		limiter:                limiter,
+		writer:                 writer,
 
 		local:       l,
 		localReader: backend.NewReader(l),
@@ -141,6 +150,9 @@ func newInstance(instanceID string, limiter *Limiter, writer tempodb.Writer, l *
 
 		autocompleteFilteringEnabled: autocompleteFiltering,
 	}
+	if lateSpans.Enabled && lateSpans.MaxTracesPerTenant > 0 {
+		i.recentlyCut = newRecentTraces(lateSpans.MaxTracesPerTenant)
+	}
 	err := i.resetHeadBlock()
 	if err != nil {
 		return nil, err
@@ -200,6 +212,14 @@ func (i *instance) push(ctx context.Context, id, traceBytes []byte) error {
 		return err
 	}
 
+	if trace.late {
+		spans, err := countSpans(trace.decoder, traceBytes)
+		if err != nil {
+			return err
+		}
+		i.lateSpansTotal.Add(float64(spans))
+	}
+
 	if maxBytes > 0 {
 		i.traceSizes[tkn] += uint32(len(traceBytes))
 	}
@@ -228,15 +248,31 @@ func (i *instance) CutCompleteTraces(cutoff time.Duration, immediate bool) error
 		// sort batches before cutting to reduce combinations during compaction
 		sortByteSlices(t.batches)
 
+		// write the spans of late traces together with their earlier fragments. if these can't be read the
+		// late spans are written alone, the fragments are still combined at query time
+		if t.late {
+			err := i.mergeEarlierFragments(context.Background(), segmentDecoder, t)
+			if err != nil {
+				level.Error(log.WithUserID(i.instanceID, log.Logger)).Log("msg", "failed to merge late spans with the earlier fragments of the trace",
+					"traceID", hex.EncodeToString(t.traceID), "err", err)
+				i.lateSpansMergeFailures.Inc()
+			}
+		}
+
 		out, err := segmentDecoder.ToObject(t.batches)
 		if err != nil {
 			return err
 		}
 
-		err = i.writeTraceToHeadBlock(t.traceID, out, t.start, t.end)
+		blockID, err := i.writeTraceToHeadBlock(t.traceID, out, t.start, t.end)
 		if err != nil {
 			return err
 		}
+		if i.recentlyCut != nil {
+			i.tracesMtx.Lock()
+			i.recentlyCut.setBlock(t.traceID, blockID)
+			i.tracesMtx.Unlock()
+		}
 
 		// return trace byte slices to be reused by proto marshalling
 		//  WARNING: can't reuse traceid's b/c the appender takes ownership of byte slices that are passed to it
@@ -383,6 +419,28 @@ func (i *instance) ClearFlushedBlocks(completeBlockTimeout time.Duration) error
 	return err
 }
 
+// FlushedBlocks returns the json encoded metas of the blocks flushed to the backend that are still held by the
+// instance. Queriers add them to their blocklist to find the traces of the blocks before polling the backend.
+func (i *instance) FlushedBlocks() ([][]byte, error) {
+	i.blocksMtx.RLock()
+	defer i.blocksMtx.RUnlock()
+
+	var metas [][]byte
+	for _, b := range i.completeBlocks {
+		if b.FlushedTime().IsZero() {
+			continue
+		}
+
+		meta, err := json.Marshal(b.BlockMeta())
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal block meta: %w", err)
+		}
+		metas = append(metas, meta)
+	}
+
+	return metas, nil
+}
+
 func (i *instance) FindTraceByID(ctx context.Context, id []byte) (*tempopb.Trace, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "instance.FindTraceByID")
 	defer span.Finish()
@@ -404,12 +462,22 @@ func (i *instance) FindTraceByID(ctx context.Context, id []byte) (*tempopb.Trace
 	combiner := trace.NewCombiner()
 	combiner.Consume(completeTrace)
 
+	if err := i.consumeTraceFromBlocks(ctx, id, combiner); err != nil {
+		return nil, err
+	}
+
+	result, _ := combiner.Result()
+	return result, nil
+}
+
+// consumeTraceFromBlocks passes the trace found in the head, completing and complete blocks to the combiner.
+func (i *instance) consumeTraceFromBlocks(ctx context.Context, id []byte, combiner *trace.Combiner) error {
 	// headBlock
 	i.headBlockMtx.RLock()
 	tr, err := i.headBlock.FindTraceByID(ctx, id, common.DefaultSearchOptions())
 	i.headBlockMtx.RUnlock()
 	if err != nil {
-		return nil, fmt.Errorf("headBlock.FindTraceByID failed: %w", err)
+		return fmt.Errorf("headBlock.FindTraceByID failed: %w", err)
 	}
 	combiner.Consume(tr)
 
@@ -420,7 +488,7 @@ func (i *instance) FindTraceByID(ctx context.Context, id []byte) (*tempopb.Trace
 	for _, c := range i.completingBlocks {
 		tr, err = c.FindTraceByID(ctx, id, common.DefaultSearchOptions())
 		if err != nil {
-			return nil, fmt.Errorf("completingBlock.FindTraceByID failed: %w", err)
+			return fmt.Errorf("completingBlock.FindTraceByID failed: %w", err)
 		}
 		combiner.Consume(tr)
 	}
@@ -429,13 +497,12 @@ func (i *instance) FindTraceByID(ctx context.Context, id []byte) (*tempopb.Trace
 	for _, c := range i.completeBlocks {
 		found, err := c.FindTraceByID(ctx, id, common.DefaultSearchOptions())
 		if err != nil {
-			return nil, fmt.Errorf("completeBlock.FindTraceByID failed: %w", err)
+			return fmt.Errorf("completeBlock.FindTraceByID failed: %w", err)
 		}
 		combiner.Consume(found)
 	}
 
-	result, _ := combiner.Result()
-	return result, nil
+	return nil
 }
 
 // AddCompletingBlock adds an AppendBlock directly to the slice of completing blocks.
@@ -458,6 +525,7 @@ func (i *instance) getOrCreateTrace(traceID []byte, fp uint32, maxBytes int) *li
 	}
 
 	trace = newTrace(traceID, maxBytes)
+	trace.late = i.recentlyCut != nil && i.recentlyCut.contains(traceID)
 	i.traces[fp] = trace
 	i.tracesCreatedTotal.Inc()
 	i.traceCount.Inc()
@@ -480,7 +548,8 @@ func (i *instance) resetHeadBlock() error {
 	i.traceSizes = make(map[uint32]uint32, len(i.traceSizes))
 	i.tracesMtx.Unlock()
 
-	newHeadBlock, err := i.writer.WAL().NewBlock(uuid.New(), i.instanceID, model.CurrentEncoding)
+	dedicatedColumns := i.limiter.limits.DedicatedColumns(i.instanceID)
+	newHeadBlock, err := i.writer.WAL().NewBlockWithDedicatedColumns(uuid.New(), i.instanceID, model.CurrentEncoding, dedicatedColumns)
 	if err != nil {
 		return err
 	}
@@ -505,6 +574,9 @@ func (i *instance) tracesToCut(cutoff time.Duration, immediate bool) []*liveTrac
 		if cutoffTime.After(trace.lastAppend) || immediate {
 			tracesToCut = append(tracesToCut, trace)
 			delete(i.traces, key)
+			if i.recentlyCut != nil {
+				i.recentlyCut.add(trace.traceID)
+			}
 		}
 	}
 	i.traceCount.Store(int32(len(i.traces)))
@@ -512,16 +584,17 @@ func (i *instance) tracesToCut(cutoff time.Duration, immediate bool) []*liveTrac
 	return tracesToCut
 }
 
-func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint32) error {
+// writeTraceToHeadBlock appends the trace to the head block and returns the ID of the block.
+func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint32) (uuid.UUID, error) {
 	i.headBlockMtx.Lock()
 	defer i.headBlockMtx.Unlock()
 
 	err := i.headBlock.Append(id, b, start, end)
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 
-	return nil
+	return i.headBlock.BlockMeta().BlockID, nil
 }
 
 func (i *instance) rediscoverLocalBlocks(ctx context.Context) ([]*localBlock, error) {
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 const testTenantID = "fake"
@@ -498,6 +499,97 @@ func TestInstanceMetrics(t *testing.T) {
 	cutAndVerify(0)
 }
 
+func TestInstanceLateSpans(t *testing.T) {
+	ctx := context.Background()
+	id := test.ValidTraceID(nil)
+
+	i, _ := defaultInstance(t)
+	i.recentlyCut = newRecentTraces(10)
+
+	before, err := test.GetCounterVecValue(metricLateSpansTotal, testTenantID)
+	require.NoError(t, err)
+	dupBytesBefore, err := test.GetCounterVecValue(metricLateSpansDuplicatedBytesTotal, testTenantID)
+	require.NoError(t, err)
+
+	// push and cut the trace, then move it to a completing block
+	require.NoError(t, i.PushBytesRequest(ctx, makeRequest(id)))
+	require.NoError(t, i.CutCompleteTraces(0, true))
+	_, err = i.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+
+	// push late spans of the same trace
+	require.NoError(t, i.PushBytesRequest(ctx, makeRequest(id)))
+	after, err := test.GetCounterVecValue(metricLateSpansTotal, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, float64(10), after-before)
+
+	// the head block contains the late spans and the earlier fragment in a single object
+	require.NoError(t, i.CutCompleteTraces(0, true))
+	tr, err := i.headBlock.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.NotNil(t, tr)
+	require.Equal(t, 20, countTraceSpans(tr))
+
+	// the earlier fragment is written again and reported
+	dupBytesAfter, err := test.GetCounterVecValue(metricLateSpansDuplicatedBytesTotal, testTenantID)
+	require.NoError(t, err)
+	require.Greater(t, dupBytesAfter, dupBytesBefore)
+
+	// the late spans are written alone if the block of the earlier fragments is gone
+	i.tracesMtx.Lock()
+	i.recentlyCut.setBlock(id, uuid.New())
+	i.tracesMtx.Unlock()
+	require.NoError(t, i.PushBytesRequest(ctx, makeRequest(id)))
+	require.NoError(t, i.CutCompleteTraces(0, true))
+	dupBytesFinal, err := test.GetCounterVecValue(metricLateSpansDuplicatedBytesTotal, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, dupBytesAfter, dupBytesFinal)
+
+	// traces that were not cut aren't late
+	require.NoError(t, i.PushBytesRequest(ctx, makeRequest(test.ValidTraceID(nil))))
+	final, err := test.GetCounterVecValue(metricLateSpansTotal, testTenantID)
+	require.NoError(t, err)
+	require.Equal(t, after+10, final)
+}
+
+func TestRecentTraces(t *testing.T) {
+	r := newRecentTraces(2)
+	id1, id2, id3, id4 := test.ValidTraceID(nil), test.ValidTraceID(nil), test.ValidTraceID(nil), test.ValidTraceID(nil)
+
+	r.add(id1)
+	r.add(id2)
+	require.Equal(t, uuid.Nil, r.block(id1))
+
+	// the block of a trace is kept when it is cut again
+	block := uuid.New()
+	r.setBlock(id1, block)
+	r.add(id1)
+	require.True(t, r.contains(id1))
+	require.True(t, r.contains(id2))
+	require.Equal(t, block, r.block(id1))
+
+	// the oldest id is evicted first
+	r.add(id3)
+	require.False(t, r.contains(id1))
+	require.True(t, r.contains(id2))
+	require.True(t, r.contains(id3))
+
+	r.add(id4)
+	require.False(t, r.contains(id2))
+	require.True(t, r.contains(id3))
+	require.True(t, r.contains(id4))
+}
+
+func countTraceSpans(tr *tempopb.Trace) int {
+	spans := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			spans += len(ss.Spans)
+		}
+	}
+	return spans
+}
+
 func TestInstanceFailsLargeTracesEvenAfterFlushing(t *testing.T) {
 	ctx := context.Background()
 	maxTraceBytes := 1000
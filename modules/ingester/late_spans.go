@@ -0,0 +1,191 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var (
+	metricLateSpansTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_spans_total",
+		Help:      "The total number of spans received after their trace was cut per tenant.",
+	}, []string{"tenant"})
+	metricLateSpansDuplicatedBytesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_spans_duplicated_bytes_total",
+		Help:      "The total number of bytes of earlier fragments written again with the late spans of their trace per tenant.",
+	}, []string{"tenant"})
+	metricLateSpansMergeFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_spans_merge_failures_total",
+		Help:      "The total number of late traces written without their earlier fragments because these failed to be read per tenant.",
+	}, []string{"tenant"})
+)
+
+// recentTraces is a bounded set of the IDs of recently cut traces. Once full the oldest IDs are evicted first.
+// Every ID maps to the block the trace was last written to, the only block holding all its earlier fragments.
+type recentTraces struct {
+	blocks map[string]uuid.UUID
+	ring   []string
+	next   int
+}
+
+func newRecentTraces(size int) *recentTraces {
+	return &recentTraces{
+		blocks: make(map[string]uuid.UUID, size),
+		ring:   make([]string, 0, size),
+	}
+}
+
+// add tracks a trace ID. The block of a tracked trace is kept until the trace is written again. It must be
+// called under the i.tracesMtx lock
+func (r *recentTraces) add(traceID []byte) {
+	if _, ok := r.blocks[string(traceID)]; ok {
+		return
+	}
+
+	id := string(traceID)
+	if len(r.ring) < cap(r.ring) {
+		r.ring = append(r.ring, id)
+	} else {
+		delete(r.blocks, r.ring[r.next])
+		r.ring[r.next] = id
+		r.next = (r.next + 1) % len(r.ring)
+	}
+	r.blocks[id] = uuid.Nil
+}
+
+// setBlock records the block a tracked trace was written to. It must be called under the i.tracesMtx lock
+func (r *recentTraces) setBlock(traceID []byte, blockID uuid.UUID) {
+	if _, ok := r.blocks[string(traceID)]; ok {
+		r.blocks[string(traceID)] = blockID
+	}
+}
+
+// contains returns true if the trace was cut recently. It must be called under the i.tracesMtx lock
+func (r *recentTraces) contains(traceID []byte) bool {
+	_, ok := r.blocks[string(traceID)]
+	return ok
+}
+
+// block returns the block a recently cut trace was last written to or uuid.Nil if it is unknown. It must be
+// called under the i.tracesMtx lock
+func (r *recentTraces) block(traceID []byte) uuid.UUID {
+	return r.blocks[string(traceID)]
+}
+
+// countSpans returns the number of spans of a segment pushed to the instance.
+func countSpans(decoder model.SegmentDecoder, segment []byte) (int, error) {
+	tr, err := decoder.PrepareForRead([][]byte{segment})
+	if err != nil {
+		return 0, err
+	}
+
+	spans := 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			spans += len(ss.Spans)
+		}
+	}
+	return spans, nil
+}
+
+// mergeEarlierFragments prepends the fragments of a late trace already written to the blocks of the instance
+// to the batches of the trace. The trace is then written to the head block as a single object containing all
+// its spans. Every write of a late trace contains all its earlier fragments so only the block it was last
+// written to is read. The blocks are append only so the earlier fragments are stored twice, the copy is
+// reported by tempo_ingester_late_spans_duplicated_bytes_total. Both copies are deduplicated when the blocks are
+// combined at query time or compacted together.
+func (i *instance) mergeEarlierFragments(ctx context.Context, decoder model.SegmentDecoder, t *liveTrace) error {
+	i.tracesMtx.Lock()
+	blockID := i.recentlyCut.block(t.traceID)
+	i.tracesMtx.Unlock()
+
+	if blockID == uuid.Nil {
+		// the earlier fragments failed to be written
+		return nil
+	}
+
+	earlier, err := i.findTraceInBlock(ctx, t.traceID, blockID)
+	if err != nil {
+		return err
+	}
+	if earlier == nil {
+		// the earlier fragments were already flushed and cleared
+		return nil
+	}
+
+	start, end := traceRange(earlier)
+	segment, err := decoder.PrepareForWrite(earlier, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to prepare earlier fragments: %w", err)
+	}
+	i.lateSpansDupBytes.Add(float64(len(segment)))
+
+	t.batches = append([][]byte{segment}, t.batches...)
+	if start < t.start {
+		t.start = start
+	}
+	if end > t.end {
+		t.end = end
+	}
+	return nil
+}
+
+// findTraceInBlock returns the trace from the head, completing or complete block with the given ID. It returns
+// nil if the block or the trace isn't found.
+func (i *instance) findTraceInBlock(ctx context.Context, id []byte, blockID uuid.UUID) (*tempopb.Trace, error) {
+	i.headBlockMtx.RLock()
+	if i.headBlock.BlockMeta().BlockID == blockID {
+		defer i.headBlockMtx.RUnlock()
+		return i.headBlock.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+	}
+	i.headBlockMtx.RUnlock()
+
+	i.blocksMtx.RLock()
+	defer i.blocksMtx.RUnlock()
+
+	for _, c := range i.completingBlocks {
+		if c.BlockMeta().BlockID == blockID {
+			return c.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		}
+	}
+	for _, c := range i.completeBlocks {
+		if c.BlockMeta().BlockID == blockID {
+			return c.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		}
+	}
+	return nil, nil
+}
+
+// traceRange returns the start and end of the spans of the trace in unix epoch seconds.
+func traceRange(tr *tempopb.Trace) (uint32, uint32) {
+	var start, end uint64 = math.MaxUint64, 0
+	for _, b := range tr.Batches {
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				if s.StartTimeUnixNano < start {
+					start = s.StartTimeUnixNano
+				}
+				if s.EndTimeUnixNano > end {
+					end = s.EndTimeUnixNano
+				}
+			}
+		}
+	}
+	if start == math.MaxUint64 {
+		return 0, 0
+	}
+	return uint32(start / uint64(time.Second)), uint32(end / uint64(time.Second))
+}
@@ -15,6 +15,8 @@ type liveTrace struct {
 	start      uint32
 	end        uint32
 	decoder    model.SegmentDecoder
+	// late is true if the trace was cut recently and this is a later fragment
+	late bool
 
 	// byte limits
 	maxBytes     int
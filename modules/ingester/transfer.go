@@ -0,0 +1,318 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/ring"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/ingester/client"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var (
+	metricTransferredTracesSentTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_transferred_traces_sent_total",
+		Help:      "The total number of traces sent to another ingester on shutdown.",
+	})
+	metricTransferredTracesReceivedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_transferred_traces_received_total",
+		Help:      "The total number of traces received from a leaving ingester.",
+	})
+)
+
+var errNoPendingIngester = errors.New("no pending ingester to transfer to")
+
+// TransferOut implements ring.FlushTransferer. It is called by the lifecycler on shutdown and hands the live
+// traces and the head blocks over to a pending ingester, which claims the tokens of this ingester. The flush on
+// shutdown is skipped if the transfer succeeds. Completing and complete blocks are not transferred, they are
+// flushed or replayed from the wal as usual.
+func (i *Ingester) TransferOut(ctx context.Context) error {
+	if i.cfg.MaxTransferRetries <= 0 {
+		return ring.ErrTransferDisabled
+	}
+
+	// the traces pushed after the transfer started would be lost
+	i.stopIncomingRequests()
+
+	b := backoff.New(ctx, backoff.Config{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+		MaxRetries: i.cfg.MaxTransferRetries,
+	})
+
+	var err error
+	for b.Ongoing() {
+		err = i.transferOut(ctx)
+		if err == nil {
+			return nil
+		}
+
+		level.Error(log.Logger).Log("msg", "transfer failed", "attempt", b.NumRetries()+1, "err", err)
+		b.Wait()
+	}
+	if err == nil {
+		err = b.Err()
+	}
+
+	return fmt.Errorf("failed to transfer traces after %d attempts: %w", b.NumRetries(), err)
+}
+
+func (i *Ingester) transferOut(ctx context.Context) error {
+	target, err := i.findTransferTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	level.Info(log.Logger).Log("msg", "sending traces", "to_ingester", target.Addr)
+
+	c, err := client.New(target.Addr, i.cfg.IngesterClientConfig)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// the stream holds the traces of all the tenants, the org id is only required by the grpc middleware
+	ctx = user.InjectOrgID(ctx, util.FakeTenantID)
+	stream, err := c.TransferTraces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open transfer stream: %w", err)
+	}
+
+	err = stream.Send(&tempopb.TransferTracesRequest{
+		FromIngesterID: i.lifecycler.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send transfer request: %w", err)
+	}
+
+	instances := i.getInstances()
+	blocks := make([]common.WALBlock, len(instances))
+	for j, inst := range instances {
+		blocks[j], err = inst.transferOut(ctx, func(id common.ID, segment []byte) error {
+			return stream.Send(&tempopb.TransferTracesRequest{
+				TenantID: inst.instanceID,
+				TraceID:  id,
+				Segment:  segment,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send traces of tenant %s: %w", inst.instanceID, err)
+		}
+	}
+
+	if _, err = stream.CloseAndRecv(); err != nil {
+		return fmt.Errorf("failed to close transfer stream: %w", err)
+	}
+
+	// the traces are owned by the receiving ingester now, clear the head blocks so they aren't replayed
+	for j, inst := range instances {
+		if err := inst.clearTransferredBlock(blocks[j]); err != nil {
+			level.Error(log.WithUserID(inst.instanceID, log.Logger)).Log("msg", "failed to clear transferred head block", "err", err)
+		}
+	}
+
+	level.Info(log.Logger).Log("msg", "successfully sent traces", "to_ingester", target.Addr)
+	return nil
+}
+
+// findTransferTarget returns another pending ingester from the ring. Pending ingesters that stopped
+// heartbeating are skipped.
+func (i *Ingester) findTransferTarget(ctx context.Context) (*ring.InstanceDesc, error) {
+	desc, err := i.lifecycler.KVStore.Get(ctx, i.lifecycler.RingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ringDesc, ok := desc.(*ring.Desc)
+	if !ok || ringDesc == nil {
+		return nil, errNoPendingIngester
+	}
+
+	now := time.Now()
+	heartbeatTimeout := i.cfg.LifecyclerConfig.RingConfig.HeartbeatTimeout
+	for id, ing := range ringDesc.Ingesters {
+		if id != i.lifecycler.ID && ing.State == ring.PENDING && ing.IsHeartbeatHealthy(heartbeatTimeout, now) {
+			return &ing, nil
+		}
+	}
+	return nil, errNoPendingIngester
+}
+
+// transferredTrace is a trace received from a leaving ingester, staged until the transfer succeeds.
+type transferredTrace struct {
+	inst    *instance
+	id      []byte
+	segment []byte
+}
+
+// TransferTraces implements tempopb.Pusher.TransferTraces. It receives the traces of a leaving ingester and claims
+// its tokens once all the traces have been received. Only a pending ingester accepts transfers. The traces are
+// pushed to the instances after the tokens are claimed, on failure they are discarded and the ingester returns
+// to pending so the leaving ingester can retry.
+func (i *Ingester) TransferTraces(stream tempopb.Pusher_TransferTracesServer) error {
+	if i.readonly {
+		return ErrReadOnly
+	}
+
+	// the transition to JOINING fails if the ingester isn't pending
+	if err := i.lifecycler.ChangeState(stream.Context(), ring.JOINING); err != nil {
+		return fmt.Errorf("failed to accept transfer: %w", err)
+	}
+
+	fromIngesterID, traces, err := i.receiveTraces(stream)
+	if err != nil {
+		return i.failTransfer(fromIngesterID, err)
+	}
+
+	if err := i.lifecycler.ClaimTokensFor(stream.Context(), fromIngesterID); err != nil {
+		return i.failTransfer(fromIngesterID, fmt.Errorf("failed to claim tokens of %s: %w", fromIngesterID, err))
+	}
+
+	if err := i.lifecycler.ChangeState(stream.Context(), ring.ACTIVE); err != nil {
+		return i.failTransfer(fromIngesterID, fmt.Errorf("failed to change state to active: %w", err))
+	}
+
+	for _, t := range traces {
+		// the traces were already accepted by the leaving ingester, a failure only loses this trace
+		if err := t.inst.pushTransferred(stream.Context(), t.id, t.segment); err != nil {
+			level.Error(log.WithUserID(t.inst.instanceID, log.Logger)).Log("msg", "failed to push transferred trace", "err", err)
+			continue
+		}
+		metricTransferredTracesReceivedTotal.Inc()
+	}
+
+	level.Info(log.Logger).Log("msg", "successfully received traces", "from_ingester", fromIngesterID)
+	return stream.SendAndClose(&tempopb.TransferTracesResponse{})
+}
+
+// failTransfer returns the ingester to pending after a failed transfer. The staged traces are discarded.
+func (i *Ingester) failTransfer(fromIngesterID string, err error) error {
+	level.Error(log.Logger).Log("msg", "transfer failed", "from_ingester", fromIngesterID, "err", err)
+	if err := i.lifecycler.ChangeState(context.Background(), ring.PENDING); err != nil {
+		level.Error(log.Logger).Log("msg", "failed to return to pending after failed transfer", "err", err)
+	}
+	return err
+}
+
+// receiveTraces stages the traces of the stream and returns them with the ID of the leaving ingester.
+func (i *Ingester) receiveTraces(stream tempopb.Pusher_TransferTracesServer) (string, []transferredTrace, error) {
+	fromIngesterID := ""
+	var traces []transferredTrace
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fromIngesterID, nil, err
+		}
+
+		if req.FromIngesterID != "" {
+			fromIngesterID = req.FromIngesterID
+		}
+		if req.TenantID == "" {
+			continue
+		}
+
+		inst, err := i.getOrCreateInstance(req.TenantID)
+		if err != nil {
+			return fromIngesterID, nil, err
+		}
+
+		traces = append(traces, transferredTrace{
+			inst:    inst,
+			id:      req.TraceID,
+			segment: req.Segment,
+		})
+	}
+
+	if fromIngesterID == "" {
+		return "", nil, errors.New("transfer didn't include the id of the leaving ingester")
+	}
+	return fromIngesterID, traces, nil
+}
+
+// transferOut cuts the live traces to the head block and sends the traces of the head block. It returns the head
+// block so it can be cleared once the transfer succeeded.
+func (i *instance) transferOut(ctx context.Context, send func(id common.ID, segment []byte) error) (common.WALBlock, error) {
+	if err := i.CutCompleteTraces(0, true); err != nil {
+		return nil, err
+	}
+
+	i.headBlockMtx.RLock()
+	defer i.headBlockMtx.RUnlock()
+
+	if i.headBlock == nil || i.headBlock.DataLength() == 0 {
+		return nil, nil
+	}
+
+	iter, err := i.headBlock.Iterator()
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	decoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+	for {
+		id, tr, err := iter.Next(ctx)
+		if err == io.EOF || (err == nil && id == nil) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, end := traceRange(tr)
+		segment, err := decoder.PrepareForWrite(tr, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := send(id, segment); err != nil {
+			return nil, err
+		}
+		metricTransferredTracesSentTotal.Inc()
+	}
+
+	return i.headBlock, nil
+}
+
+// clearTransferredBlock clears the head block if it is the transferred block.
+func (i *instance) clearTransferredBlock(b common.WALBlock) error {
+	i.headBlockMtx.Lock()
+	defer i.headBlockMtx.Unlock()
+
+	if b == nil || i.headBlock != b {
+		return nil
+	}
+
+	if err := b.Clear(); err != nil {
+		return err
+	}
+	return i.resetHeadBlock()
+}
+
+// pushTransferred pushes a trace received from a leaving ingester. The trace was already accepted by the leaving
+// ingester so the limits aren't applied.
+func (i *instance) pushTransferred(ctx context.Context, id, segment []byte) error {
+	i.tracesMtx.Lock()
+	defer i.tracesMtx.Unlock()
+
+	tkn := i.tokenForTraceID(id)
+	return i.getOrCreateTrace(id, tkn, 0).Push(ctx, i.instanceID, segment)
+}
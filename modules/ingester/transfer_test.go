@@ -0,0 +1,224 @@
+package ingester
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/ring"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestTransferOut(t *testing.T) {
+	kvStore := defaultIngesterTestConfig().LifecyclerConfig.RingConfig.KVStore.Mock
+
+	// the pending ingester taking over the tokens of the leaving ingester
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	pendingCfg := transferTestConfig(kvStore, "pending")
+	pendingCfg.LifecyclerConfig.Addr = "127.0.0.1"
+	pendingCfg.LifecyclerConfig.Port = l.Addr().(*net.TCPAddr).Port
+	pendingCfg.LifecyclerConfig.JoinAfter = time.Hour
+	pending := defaultIngesterModuleWithConfig(t, t.TempDir(), pendingCfg)
+
+	srv := grpc.NewServer()
+	tempopb.RegisterPusherServer(srv, pending)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	defer srv.Stop()
+
+	leavingCfg := transferTestConfig(kvStore, "leaving")
+	leavingCfg.MaxTransferRetries = 1
+	leaving := defaultIngesterModuleWithConfig(t, t.TempDir(), leavingCfg)
+
+	require.Eventually(t, func() bool {
+		return leaving.lifecycler.GetState() == ring.ACTIVE
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, ring.PENDING, pending.lifecycler.GetState())
+	tokens := transferTestRing(t, leaving).Ingesters["leaving"].Tokens
+	require.NotEmpty(t, tokens)
+
+	// half of the traces are cut to the head block, the others are live traces
+	traces, traceIDs := transferTestTraces(t, 10)
+	for j := range traces {
+		for _, batch := range traces[j].Batches {
+			pushBatchV2(t, leaving, batch, traceIDs[j])
+		}
+		if j == 4 {
+			inst, ok := leaving.getInstanceByID("test")
+			require.True(t, ok)
+			require.NoError(t, inst.CutCompleteTraces(0, true))
+		}
+	}
+
+	require.NoError(t, leaving.TransferOut(context.Background()))
+
+	// the pending ingester holds the traces and the tokens
+	require.Equal(t, ring.ACTIVE, pending.lifecycler.GetState())
+	require.Equal(t, tokens, transferTestRing(t, pending).Ingesters["pending"].Tokens)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	for j, id := range traceIDs {
+		foundTrace, err := pending.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+			TraceID: id,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, foundTrace.Trace)
+		trace.SortTrace(foundTrace.Trace)
+		require.True(t, proto.Equal(traces[j], foundTrace.Trace))
+	}
+
+	// the leaving ingester doesn't accept writes and its head block was cleared
+	require.True(t, leaving.readonly)
+	inst, ok := leaving.getInstanceByID("test")
+	require.True(t, ok)
+	require.Zero(t, inst.traceCount.Load())
+	require.Zero(t, inst.headBlock.DataLength())
+
+	// a second transfer is refused by the active ingester
+	require.Error(t, leaving.transferOut(context.Background()))
+}
+
+func TestTransferOutDisabled(t *testing.T) {
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+
+	require.Equal(t, ring.ErrTransferDisabled, ingester.TransferOut(context.Background()))
+	require.False(t, ingester.readonly)
+}
+
+func TestTransferOutNoPendingIngester(t *testing.T) {
+	cfg := defaultIngesterTestConfig()
+	cfg.MaxTransferRetries = 1
+	ingester := defaultIngesterModuleWithConfig(t, t.TempDir(), cfg)
+
+	traces, traceIDs := transferTestTraces(t, 1)
+	for _, batch := range traces[0].Batches {
+		pushBatchV2(t, ingester, batch, traceIDs[0])
+	}
+
+	require.ErrorIs(t, ingester.TransferOut(context.Background()), errNoPendingIngester)
+
+	// the traces are flushed on shutdown instead
+	inst, ok := ingester.getInstanceByID("test")
+	require.True(t, ok)
+	require.Equal(t, int32(1), inst.traceCount.Load())
+}
+
+func TestFindTransferTargetSkipsUnhealthyIngesters(t *testing.T) {
+	kvStore := defaultIngesterTestConfig().LifecyclerConfig.RingConfig.KVStore.Mock
+
+	cfg := transferTestConfig(kvStore, "leaving")
+	cfg.LifecyclerConfig.RingConfig.HeartbeatTimeout = time.Minute
+	ingester := defaultIngesterModuleWithConfig(t, t.TempDir(), cfg)
+
+	addPending := func(id string, heartbeat time.Time) {
+		err := kvStore.CAS(context.Background(), ingester.lifecycler.RingKey, func(in interface{}) (interface{}, bool, error) {
+			desc := in.(*ring.Desc)
+			desc.Ingesters[id] = ring.InstanceDesc{Addr: id, State: ring.PENDING, Timestamp: heartbeat.Unix()}
+			return desc, true, nil
+		})
+		require.NoError(t, err)
+	}
+
+	// a pending ingester that stopped heartbeating isn't a target
+	addPending("stale", time.Now().Add(-time.Hour))
+	_, err := ingester.findTransferTarget(context.Background())
+	require.ErrorIs(t, err, errNoPendingIngester)
+
+	addPending("healthy", time.Now())
+	target, err := ingester.findTransferTarget(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "healthy", target.Addr)
+}
+
+func TestTransferTracesFailureDiscardsTraces(t *testing.T) {
+	cfg := defaultIngesterTestConfig()
+	cfg.LifecyclerConfig.JoinAfter = time.Hour
+	ingester := defaultIngesterModuleWithConfig(t, t.TempDir(), cfg)
+	require.Equal(t, ring.PENDING, ingester.lifecycler.GetState())
+
+	traces, traceIDs := transferTestTraces(t, 1)
+	segment, err := model.MustNewSegmentDecoder(model.CurrentEncoding).PrepareForWrite(traces[0], 0, 0)
+	require.NoError(t, err)
+
+	// the stream fails after a trace was received
+	stream := &failingTransferStream{reqs: []*tempopb.TransferTracesRequest{
+		{FromIngesterID: "leaving"},
+		{TenantID: "test", TraceID: traceIDs[0], Segment: segment},
+	}}
+	require.Error(t, ingester.TransferTraces(stream))
+
+	// the ingester is pending again and the trace was discarded so the retry doesn't duplicate it
+	require.Equal(t, ring.PENDING, ingester.lifecycler.GetState())
+	inst, ok := ingester.getInstanceByID("test")
+	require.True(t, ok)
+	require.Zero(t, inst.traceCount.Load())
+}
+
+type failingTransferStream struct {
+	grpc.ServerStream
+	reqs []*tempopb.TransferTracesRequest
+}
+
+func (s *failingTransferStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *failingTransferStream) Recv() (*tempopb.TransferTracesRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, errors.New("stream broken")
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *failingTransferStream) SendAndClose(*tempopb.TransferTracesResponse) error {
+	return nil
+}
+
+func transferTestConfig(kvStore kv.Client, id string) Config {
+	cfg := defaultIngesterTestConfig()
+	cfg.LifecyclerConfig.RingConfig.KVStore.Mock = kvStore
+	cfg.LifecyclerConfig.ID = id
+	flagext.DefaultValues(&cfg.IngesterClientConfig)
+	return cfg
+}
+
+func transferTestRing(t *testing.T, i *Ingester) *ring.Desc {
+	desc, err := i.lifecycler.KVStore.Get(context.Background(), i.lifecycler.RingKey)
+	require.NoError(t, err)
+	return desc.(*ring.Desc)
+}
+
+func transferTestTraces(t *testing.T, count int) ([]*tempopb.Trace, [][]byte) {
+	traces := make([]*tempopb.Trace, 0, count)
+	traceIDs := make([][]byte, 0, count)
+	for j := 0; j < count; j++ {
+		id := make([]byte, 16)
+		_, err := rand.Read(id)
+		require.NoError(t, err)
+
+		testTrace := test.MakeTrace(10, id)
+		trace.SortTrace(testTrace)
+
+		traces = append(traces, testTrace)
+		traceIDs = append(traceIDs, id)
+	}
+	return traces, traceIDs
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/spantransform"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 type Service interface {
@@ -30,6 +32,8 @@ type Interface interface {
 	MaxBlocksPerTagValuesQuery(userID string) int
 	IngestionRateLimitBytes(userID string) float64
 	IngestionBurstSizeBytes(userID string) int
+	IngestionPolicies(userID string) config.IngestionPolicies
+	AttributeTransformRules(userID string) spantransform.Rules
 	MetricsGeneratorRingSize(userID string) int
 	MetricsGeneratorProcessors(userID string) map[string]struct{}
 	MetricsGeneratorMaxActiveSeries(userID string) uint32
@@ -44,6 +48,7 @@ type Interface interface {
 	MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string
 	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions(userID string) map[string]bool
 	MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID string) []config.FilterPolicy
+	MetricsGeneratorProcessorSpanMetricsCustomMetrics(userID string) sharedconfig.CustomMetrics
 	MetricsGeneratorProcessorLocalBlocksMaxLiveTraces(userID string) uint64
 	MetricsGeneratorProcessorLocalBlocksMaxBlockDuration(userID string) time.Duration
 	MetricsGeneratorProcessorLocalBlocksMaxBlockBytes(userID string) uint64
@@ -53,6 +58,11 @@ type Interface interface {
 	MetricsGeneratorProcessorSpanMetricsDimensionMappings(userID string) []sharedconfig.DimensionMappings
 	MetricsGeneratorProcessorSpanMetricsEnableTargetInfo(userID string) bool
 	MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID string) bool
+	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool
 	BlockRetention(userID string) time.Duration
 	MaxSearchDuration(userID string) time.Duration
+	MaxBytesPerSearch(userID string) int
+	RulerRules(userID string) sharedconfig.RulerRules
+	TenantIDs() []string
+	DedicatedColumns(userID string) backend.DedicatedColumns
 }
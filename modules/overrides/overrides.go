@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/grafana/dskit/runtimeconfig"
@@ -15,8 +16,10 @@ import (
 
 	"github.com/grafana/tempo/pkg/sharedconfig"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/spantransform"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 const wildcardTenant = "*"
@@ -54,6 +57,27 @@ func loadPerTenantOverrides(r io.Reader) (interface{}, error) {
 		return nil, err
 	}
 
+	for tenant, l := range overrides.TenantLimits {
+		if l == nil {
+			continue
+		}
+		if err := l.DedicatedColumns.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid parquet_dedicated_columns for tenant %s: %w", tenant, err)
+		}
+		if err := l.IngestionPolicies.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid ingestion_policies for tenant %s: %w", tenant, err)
+		}
+		if err := l.AttributeTransformRules.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid attribute_transform_rules for tenant %s: %w", tenant, err)
+		}
+		if err := l.MetricsGeneratorProcessorSpanMetricsCustomMetrics.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid metrics_generator_processor_span_metrics_custom_metrics for tenant %s: %w", tenant, err)
+		}
+		if err := l.RulerRules.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid ruler_rules for tenant %s: %w", tenant, err)
+		}
+	}
+
 	return overrides, nil
 }
 
@@ -81,6 +105,22 @@ type overrides struct {
 // are defaulted to those values.  As such, the last call to NewOverrides will
 // become the new global defaults.
 func NewOverrides(defaults Limits) (Service, error) {
+	if err := defaults.DedicatedColumns.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid parquet_dedicated_columns: %w", err)
+	}
+	if err := defaults.IngestionPolicies.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ingestion_policies: %w", err)
+	}
+	if err := defaults.AttributeTransformRules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid attribute_transform_rules: %w", err)
+	}
+	if err := defaults.MetricsGeneratorProcessorSpanMetricsCustomMetrics.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid metrics_generator_processor_span_metrics_custom_metrics: %w", err)
+	}
+	if err := defaults.RulerRules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ruler_rules: %w", err)
+	}
+
 	var manager *runtimeconfig.Manager
 	subservices := []services.Service(nil)
 
@@ -345,6 +385,11 @@ func (o *overrides) MetricsGeneratorProcessorSpanMetricsFilterPolicies(userID st
 	return o.getOverridesForUser(userID).MetricsGeneratorProcessorSpanMetricsFilterPolicies
 }
 
+// MetricsGeneratorProcessorSpanMetricsCustomMetrics controls the additional metric families recorded by the spanmetrics processor.
+func (o *overrides) MetricsGeneratorProcessorSpanMetricsCustomMetrics(userID string) sharedconfig.CustomMetrics {
+	return o.getOverridesForUser(userID).MetricsGeneratorProcessorSpanMetricsCustomMetrics
+}
+
 func (o *overrides) MetricsGeneratorProcessorLocalBlocksMaxLiveTraces(userID string) uint64 {
 	return o.getOverridesForUser(userID).MetricsGeneratorProcessorLocalBlocksMaxLiveTraces
 }
@@ -384,6 +429,11 @@ func (o *overrides) MetricsGeneratorProcessorServiceGraphsEnableClientServerPref
 	return o.getOverridesForUser(userID).MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix
 }
 
+// MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram enables the histogram of the time messages spend in messaging systems
+func (o *overrides) MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool {
+	return o.getOverridesForUser(userID).MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram
+}
+
 // BlockRetention is the duration of the block retention for this tenant.
 func (o *overrides) BlockRetention(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).BlockRetention)
@@ -394,6 +444,48 @@ func (o *overrides) MaxSearchDuration(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxSearchDuration)
 }
 
+// MaxBytesPerSearch is the maximum size of the backend blocks a search may scan for this tenant.
+func (o *overrides) MaxBytesPerSearch(userID string) int {
+	return o.getOverridesForUser(userID).MaxBytesPerSearch
+}
+
+// RulerRules returns the TraceQL rules evaluated by the ruler for this tenant.
+func (o *overrides) RulerRules(userID string) sharedconfig.RulerRules {
+	return o.getOverridesForUser(userID).RulerRules
+}
+
+// TenantIDs returns the tenants with per-tenant overrides, excluding the wildcard tenant.
+func (o *overrides) TenantIDs() []string {
+	tenantOverrides := o.tenantOverrides()
+	if tenantOverrides == nil {
+		return nil
+	}
+
+	tenantIDs := make([]string, 0, len(tenantOverrides.TenantLimits))
+	for tenantID := range tenantOverrides.TenantLimits {
+		if tenantID != wildcardTenant {
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+	sort.Strings(tenantIDs)
+	return tenantIDs
+}
+
+// IngestionPolicies returns the policies dropping or sampling spans in the distributor for this tenant.
+func (o *overrides) IngestionPolicies(userID string) filterconfig.IngestionPolicies {
+	return o.getOverridesForUser(userID).IngestionPolicies
+}
+
+// AttributeTransformRules returns the rules transforming attributes in the distributor for this tenant.
+func (o *overrides) AttributeTransformRules(userID string) spantransform.Rules {
+	return o.getOverridesForUser(userID).AttributeTransformRules
+}
+
+// DedicatedColumns returns the attributes stored in dedicated columns of new blocks for this tenant.
+func (o *overrides) DedicatedColumns(userID string) backend.DedicatedColumns {
+	return o.getOverridesForUser(userID).DedicatedColumns
+}
+
 func (o *overrides) getOverridesForUser(userID string) *Limits {
 	if tenantOverrides := o.tenantOverrides(); tenantOverrides != nil {
 		l := tenantOverrides.forUser(userID)
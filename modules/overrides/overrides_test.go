@@ -4,11 +4,15 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/tempo/pkg/sharedconfig"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/spantransform"
+	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
@@ -329,3 +333,275 @@ func TestMetricsGeneratorOverrides(t *testing.T) {
 		})
 	}
 }
+
+func TestDedicatedColumnsOverrides(t *testing.T) {
+	defaultColumns := backend.DedicatedColumns{
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "db.system", Type: backend.DedicatedColumnTypeString},
+	}
+	user1Columns := backend.DedicatedColumns{
+		{Scope: backend.DedicatedColumnScopeResource, Name: "tenant.id", Type: backend.DedicatedColumnTypeString},
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "messaging.destination", Type: backend.DedicatedColumnTypeString},
+	}
+
+	limits := Limits{DedicatedColumns: defaultColumns}
+
+	overridesFile := filepath.Join(t.TempDir(), "overrides.yaml")
+	buff, err := yaml.Marshal(&perTenantOverrides{
+		TenantLimits: map[string]*Limits{
+			"user1": {DedicatedColumns: user1Columns},
+		},
+	})
+	require.NoError(t, err)
+	err = os.WriteFile(overridesFile, buff, os.ModePerm)
+	require.NoError(t, err)
+
+	limits.PerTenantOverrideConfig = overridesFile
+	limits.PerTenantOverridePeriod = model.Duration(time.Hour)
+
+	prometheus.DefaultRegisterer = prometheus.NewRegistry() // have to overwrite the registry or test panics with multiple metric reg
+	overrides, err := NewOverrides(limits)
+	require.NoError(t, err)
+	err = services.StartAndAwaitRunning(context.TODO(), overrides)
+	require.NoError(t, err)
+
+	assert.Equal(t, user1Columns, overrides.DedicatedColumns("user1"))
+	assert.Equal(t, defaultColumns, overrides.DedicatedColumns("user2"))
+
+	err = services.StopAndAwaitTerminated(context.TODO(), overrides)
+	require.NoError(t, err)
+}
+
+func TestDedicatedColumnsOverridesInvalid(t *testing.T) {
+	_, err := NewOverrides(Limits{DedicatedColumns: backend.DedicatedColumns{{Scope: "event", Name: "foo", Type: backend.DedicatedColumnTypeString}}})
+	require.Error(t, err)
+
+	_, err = loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    parquet_dedicated_columns:
+      - name: foo
+      - name: foo
+`))
+	require.Error(t, err)
+
+	o, err := loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    parquet_dedicated_columns:
+      - name: foo
+      - name: foo
+        scope: resource
+`))
+	require.NoError(t, err)
+	assert.Equal(t, backend.DedicatedColumns{
+		{Scope: backend.DedicatedColumnScopeSpan, Name: "foo", Type: backend.DedicatedColumnTypeString},
+		{Scope: backend.DedicatedColumnScopeResource, Name: "foo", Type: backend.DedicatedColumnTypeString},
+	}, o.(*perTenantOverrides).forUser("user1").DedicatedColumns)
+}
+
+func TestIngestionPoliciesOverrides(t *testing.T) {
+	o, err := loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    ingestion_policies:
+      - level: span
+        action: drop
+        include:
+          match_type: strict
+          attributes:
+            - key: span.http.target
+              value: /health
+      - level: batch
+        action: sample
+        sample_rate: 0.1
+        include:
+          match_type: strict
+          attributes:
+            - key: resource.service.name
+              value: noisy
+`))
+	require.NoError(t, err)
+	assert.Equal(t, filterconfig.IngestionPolicies{
+		{
+			Level:   filterconfig.IngestionPolicyLevelSpan,
+			Action:  filterconfig.IngestionPolicyActionDrop,
+			Include: &filterconfig.PolicyMatch{MatchType: filterconfig.Strict, Attributes: []filterconfig.MatchPolicyAttribute{{Key: "span.http.target", Value: "/health"}}},
+		},
+		{
+			Level:      filterconfig.IngestionPolicyLevelBatch,
+			Action:     filterconfig.IngestionPolicyActionSample,
+			SampleRate: 0.1,
+			Include:    &filterconfig.PolicyMatch{MatchType: filterconfig.Strict, Attributes: []filterconfig.MatchPolicyAttribute{{Key: "resource.service.name", Value: "noisy"}}},
+		},
+	}, o.(*perTenantOverrides).forUser("user1").IngestionPolicies)
+
+	_, err = loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    ingestion_policies:
+      - level: span
+        action: sample
+        sample_rate: 2
+        include:
+          match_type: strict
+`))
+	require.Error(t, err)
+
+	// invalid regexes are rejected when the overrides are loaded
+	_, err = loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    ingestion_policies:
+      - level: span
+        action: drop
+        include:
+          match_type: regex
+          attributes:
+            - key: span.http.target
+              value: "/health("
+`))
+	require.ErrorContains(t, err, "invalid regex")
+
+	_, err = NewOverrides(Limits{IngestionPolicies: filterconfig.IngestionPolicies{{Level: "batch", Action: filterconfig.IngestionPolicyActionDrop}}})
+	require.Error(t, err)
+}
+
+func TestAttributeTransformRulesOverrides(t *testing.T) {
+	o, err := loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    attribute_transform_rules:
+      - name: mask-db-statements
+        keys: [db.statement]
+        action: mask
+        pattern: "'[^']*'"
+        replacement: "'?'"
+      - name: truncate-urls
+        scope: span
+        keys: [http\..*url]
+        action: truncate
+        max_length: 256
+`))
+	require.NoError(t, err)
+
+	replacement := "'?'"
+	assert.Equal(t, spantransform.Rules{
+		{Name: "mask-db-statements", Keys: []string{"db.statement"}, Action: spantransform.ActionMask, Pattern: "'[^']*'", Replacement: &replacement},
+		{Name: "truncate-urls", Scope: spantransform.ScopeSpan, Keys: []string{`http\..*url`}, Action: spantransform.ActionTruncate, MaxLength: 256},
+	}, o.(*perTenantOverrides).forUser("user1").AttributeTransformRules)
+
+	_, err = loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    attribute_transform_rules:
+      - name: broken
+        keys: [db.statement]
+        action: mask
+`))
+	require.Error(t, err)
+
+	_, err = NewOverrides(Limits{AttributeTransformRules: spantransform.Rules{{Name: "broken", Keys: []string{"foo"}, Action: "encrypt"}}})
+	require.Error(t, err)
+}
+
+func TestSpanMetricsCustomMetricsOverrides(t *testing.T) {
+	o, err := loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    metrics_generator_processor_span_metrics_custom_metrics:
+      - name: traces_spanmetrics_response_size
+        type: histogram
+        source: attribute
+        attribute: http.response_content_length
+        histogram_buckets: [100, 1000]
+        dimensions: [http.method]
+      - name: traces_spanmetrics_exceptions_total
+        type: counter
+        source: events
+        event_name: exception
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, sharedconfig.CustomMetrics{
+		{
+			Name:             "traces_spanmetrics_response_size",
+			Type:             sharedconfig.CustomMetricTypeHistogram,
+			Source:           sharedconfig.CustomMetricSourceAttribute,
+			Attribute:        "http.response_content_length",
+			HistogramBuckets: []float64{100, 1000},
+			Dimensions:       []string{"http.method"},
+		},
+		{
+			Name:      "traces_spanmetrics_exceptions_total",
+			Type:      sharedconfig.CustomMetricTypeCounter,
+			Source:    sharedconfig.CustomMetricSourceEvents,
+			EventName: "exception",
+		},
+	}, o.(*perTenantOverrides).forUser("user1").MetricsGeneratorProcessorSpanMetricsCustomMetrics)
+
+	_, err = loadPerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    metrics_generator_processor_span_metrics_custom_metrics:
+      - name: traces_spanmetrics_response_size
+        type: histogram
+        source: attribute
+        attribute: http.response_content_length
+`))
+	require.Error(t, err)
+
+	_, err = NewOverrides(Limits{MetricsGeneratorProcessorSpanMetricsCustomMetrics: sharedconfig.CustomMetrics{{Name: "foo", Type: "gauge", Source: sharedconfig.CustomMetricSourceEvents}}})
+	require.Error(t, err)
+}
+
+func TestRulerRulesOverrides(t *testing.T) {
+	overridesFile := filepath.Join(t.TempDir(), "overrides.yaml")
+	err := os.WriteFile(overridesFile, []byte(`
+overrides:
+  user1:
+    ruler_rules:
+      - name: slow-checkout
+        query: '{ name = "checkout" } | avg(duration) > 2s'
+        window: 10m
+        labels:
+          severity: warning
+  user2:
+    max_bytes_per_trace: 10
+  "*":
+    max_bytes_per_trace: 20
+`), os.ModePerm)
+	require.NoError(t, err)
+
+	prometheus.DefaultRegisterer = prometheus.NewRegistry() // have to overwrite the registry or test panics with multiple metric reg
+	o, err := NewOverrides(Limits{
+		PerTenantOverrideConfig: overridesFile,
+		PerTenantOverridePeriod: model.Duration(time.Hour),
+	})
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.TODO(), o))
+	defer func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.TODO(), o))
+	}()
+
+	assert.Equal(t, []string{"user1", "user2"}, o.TenantIDs())
+	assert.Equal(t, sharedconfig.RulerRules{
+		{
+			Name:   "slow-checkout",
+			Query:  `{ name = "checkout" } | avg(duration) > 2s`,
+			Window: model.Duration(10 * time.Minute),
+			Labels: map[string]string{"severity": "warning"},
+		},
+	}, o.RulerRules("user1"))
+	assert.Empty(t, o.RulerRules("user2"))
+
+	for _, invalid := range []string{
+		`[{name: foo, query: '{ .foo = '}]`,
+		`[{name: foo, query: '{ } | rate()'}]`,
+		`[{query: '{ }'}]`,
+		`[{name: foo, query: '{ }'}, {name: foo, query: '{ }'}]`,
+		`[{name: foo, query: '{ }', labels: {invalid-label: foo}}]`,
+	} {
+		_, err = loadPerTenantOverrides(strings.NewReader("overrides:\n  user1:\n    ruler_rules: " + invalid))
+		assert.Error(t, err, invalid)
+	}
+}
@@ -30,12 +30,23 @@ type SearchConfig struct {
 }
 
 type TraceByIDConfig struct {
-	QueryTimeout time.Duration `yaml:"query_timeout"`
+	QueryTimeout   time.Duration        `yaml:"query_timeout"`
+	ReadYourWrites ReadYourWritesConfig `yaml:"read_your_writes"`
+}
+
+// ReadYourWritesConfig configures adding the blocks flushed by the ingesters to the blocklist of the querier
+// before they are found by polling the backend.
+type ReadYourWritesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// RefreshInterval is the minimum time between two requests for the flushed blocks of a tenant. It must be
+	// lower than the complete_block_timeout of the ingesters to find the traces continuously.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
 }
 
 // RegisterFlagsAndApplyDefaults register flags.
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.TraceByID.QueryTimeout = 10 * time.Second
+	cfg.TraceByID.ReadYourWrites.RefreshInterval = 10 * time.Second
 	cfg.QueryRelevantIngesters = false
 	cfg.ExtraQueryDelay = 0
 	cfg.MaxConcurrentQueries = 20
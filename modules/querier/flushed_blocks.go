@@ -0,0 +1,80 @@
+package querier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// flushedBlocks tracks the last time the blocks flushed by the ingesters were requested per tenant.
+type flushedBlocks struct {
+	mtx     sync.Mutex
+	tenants map[string]*flushedBlocksTenant
+}
+
+type flushedBlocksTenant struct {
+	mtx         sync.Mutex
+	lastRefresh time.Time
+}
+
+func newFlushedBlocks() *flushedBlocks {
+	return &flushedBlocks{
+		tenants: map[string]*flushedBlocksTenant{},
+	}
+}
+
+func (f *flushedBlocks) tenant(tenantID string) *flushedBlocksTenant {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	t, ok := f.tenants[tenantID]
+	if !ok {
+		t = &flushedBlocksTenant{}
+		f.tenants[tenantID] = t
+	}
+	return t
+}
+
+// refreshFlushedBlocks requests the blocks flushed by the ingesters and adds them to the blocklist of the store.
+// The ingesters hold flushed blocks until complete_block_timeout and answer the queries for their traces until
+// then, the blocks are added to the blocklist so the traces stay visible until the backend is polled. Concurrent
+// queries of a tenant wait for a single refresh and refreshes are skipped until refresh_interval has passed.
+func (q *Querier) refreshFlushedBlocks(ctx context.Context, tenantID string) error {
+	t := q.flushedBlocks.tenant(tenantID)
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if time.Since(t.lastRefresh) < q.cfg.TraceByID.ReadYourWrites.RefreshInterval {
+		return nil
+	}
+
+	responses, err := q.forIngesterRings(ctx, nil, func(funcCtx context.Context, client tempopb.QuerierClient) (interface{}, error) {
+		return client.FlushedBlocks(funcCtx, &tempopb.FlushedBlocksRequest{})
+	})
+	if err != nil {
+		return fmt.Errorf("error querying ingesters for flushed blocks: %w", err)
+	}
+
+	var metas []*backend.BlockMeta
+	for _, r := range responses {
+		for _, b := range r.response.(*tempopb.FlushedBlocksResponse).Metas {
+			meta := &backend.BlockMeta{}
+			if err := json.Unmarshal(b, meta); err != nil {
+				return fmt.Errorf("error unmarshalling flushed block meta from %s: %w", r.addr, err)
+			}
+			if meta.TenantID != tenantID {
+				continue
+			}
+			metas = append(metas, meta)
+		}
+	}
+
+	q.store.AddBlockMetas(tenantID, metas)
+	t.lastRefresh = time.Now()
+	return nil
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/golang/protobuf/jsonpb" //nolint:all //deprecated
 	"github.com/golang/protobuf/proto"  //nolint:all //ProtoReflect
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/opentracing/opentracing-go"
 	ot_log "github.com/opentracing/opentracing-go/log"
 )
@@ -97,6 +99,101 @@ func (q *Querier) TraceByIDHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
 }
 
+// TraceDiffHandler is a http.HandlerFunc to compare two traces
+func (q *Querier) TraceDiffHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.TraceByID.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TraceDiffHandler")
+	defer span.Finish()
+
+	idA, idB, err := api.ParseTraceDiffRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	blockStart, blockEnd, queryMode, timeStart, timeEnd, err := api.ValidateAndSanitizeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	traces := make([]*tempopb.Trace, 0, 2)
+	for _, id := range [][]byte{idA, idB} {
+		resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+			TraceID:    id,
+			BlockStart: blockStart,
+			BlockEnd:   blockEnd,
+			QueryMode:  queryMode,
+		}, timeStart, timeEnd)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		if resp.Trace == nil || len(resp.Trace.Batches) == 0 {
+			http.Error(w, fmt.Sprintf("trace %s not found", util.TraceIDToHexString(id)), http.StatusNotFound)
+			return
+		}
+		traces = append(traces, resp.Trace)
+	}
+
+	resp := trace.Diff(traces[0], traces[1])
+	span.LogFields(
+		ot_log.Uint32("matchedSpans", resp.MatchedSpans),
+		ot_log.Uint32("spansOnlyInA", resp.SpansOnlyInA),
+		ot_log.Uint32("spansOnlyInB", resp.SpansOnlyInB))
+
+	writeResponse(w, r, resp)
+}
+
+// TraceCriticalPathHandler is a http.HandlerFunc to retrieve the critical path of a trace
+func (q *Querier) TraceCriticalPathHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.TraceByID.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TraceCriticalPathHandler")
+	defer span.Finish()
+
+	byteID, err := api.ParseTraceID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	blockStart, blockEnd, queryMode, timeStart, timeEnd, err := api.ValidateAndSanitizeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+		TraceID:    byteID,
+		BlockStart: blockStart,
+		BlockEnd:   blockEnd,
+		QueryMode:  queryMode,
+	}, timeStart, timeEnd)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if resp.Trace == nil || len(resp.Trace.Batches) == 0 {
+		http.Error(w, fmt.Sprintf("trace %s not found", util.TraceIDToHexString(byteID)), http.StatusNotFound)
+		return
+	}
+
+	criticalPath := trace.CriticalPath(resp.Trace)
+	span.LogFields(
+		ot_log.Int("segments", len(criticalPath.Segments)),
+		ot_log.Int("spans", len(criticalPath.Spans)))
+
+	writeResponse(w, r, criticalPath)
+}
+
 func (q *Querier) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	isSearchBlock := api.IsSearchBlock(r)
 
@@ -295,6 +392,107 @@ func (q *Querier) SpanMetricsSummaryHandler(w http.ResponseWriter, r *http.Reque
 	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
 }
 
+func (q *Querier) AttributeProfileHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying generators
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.AttributeProfileHandler")
+	defer span.Finish()
+
+	req, err := api.ParseAttributeProfileRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetTag("service", req.Service)
+
+	resp, err := q.AttributeProfile(ctx, req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeResponse(w, r, resp)
+}
+
+func (q *Querier) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.QueryRangeHandler")
+	defer span.Finish()
+
+	req, err := api.ParseQueryRangeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetTag("query", req.Query)
+	span.SetTag("blockID", req.Block.GetBlockID())
+
+	resp, err := q.QueryRange(ctx, req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeResponse(w, r, resp)
+}
+
+func (q *Querier) TraceSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TraceSummaryHandler")
+	defer span.Finish()
+
+	req, err := api.ParseTraceSummaryRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetTag("query", req.Query)
+	span.SetTag("blockID", req.Block.GetBlockID())
+
+	resp, err := q.TraceSummary(ctx, req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeResponse(w, r, resp)
+}
+
+// writeResponse writes resp as protobuf if the request accepts it and as json otherwise.
+func writeResponse(w http.ResponseWriter, r *http.Request, resp proto.Message) {
+	if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+		b, err := proto.Marshal(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptProtobuf)
+		_, err = w.Write(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	marshaller := &jsonpb.Marshaler{}
+	err := marshaller.Marshal(w, resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func handleError(w http.ResponseWriter, err error) {
 	if errors.Is(err, context.Canceled) {
 		// ignore this error. we regularly cancel context once queries are complete
@@ -29,6 +29,7 @@ import (
 	"golang.org/x/sync/semaphore"
 
 	generator_client "github.com/grafana/tempo/modules/generator/client"
+	"github.com/grafana/tempo/modules/generator/processor/attributeprofiler"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier/worker"
@@ -36,6 +37,7 @@ import (
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/hedgedmetrics"
 	"github.com/grafana/tempo/pkg/model/trace"
+	pq "github.com/grafana/tempo/pkg/parquetquery"
 	"github.com/grafana/tempo/pkg/search"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
@@ -92,6 +94,8 @@ type Querier struct {
 	searchClient     *http.Client
 	searchPreferSelf *semaphore.Weighted
 
+	flushedBlocks *flushedBlocks
+
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
 }
@@ -151,6 +155,7 @@ func New(
 		limits:           limits,
 		searchPreferSelf: semaphore.NewWeighted(int64(cfg.Search.PreferSelf)),
 		searchClient:     http.DefaultClient,
+		flushedBlocks:    newFlushedBlocks(),
 	}
 
 	//
@@ -289,6 +294,13 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 	}
 
 	if req.QueryMode == QueryModeBlocks || req.QueryMode == QueryModeAll {
+		if q.cfg.TraceByID.ReadYourWrites.Enabled {
+			// the trace is still found if the blocks were flushed and cleared by the ingesters before the backend was polled
+			if err := q.refreshFlushedBlocks(ctx, userID); err != nil {
+				level.Warn(log.Logger).Log("msg", "failed to refresh the blocks flushed by the ingesters", "tenant", userID, "err", err)
+			}
+		}
+
 		span.LogFields(ot_log.String("msg", "searching store"))
 		span.LogFields(ot_log.String("timeStart", fmt.Sprint(timeStart)))
 		span.LogFields(ot_log.String("timeEnd", fmt.Sprint(timeEnd)))
@@ -715,6 +727,31 @@ func (q *Querier) SpanMetricsSummary(
 	return resp, nil
 }
 
+// AttributeProfile returns the attribute profiles of all generators combined
+func (q *Querier) AttributeProfile(ctx context.Context, req *tempopb.AttributeProfileRequest) (*tempopb.AttributeProfileResponse, error) {
+	replicationSet, err := q.generatorRing.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding generators in Querier.AttributeProfile")
+	}
+	lookupResults, err := q.forGivenGenerators(
+		ctx,
+		replicationSet,
+		func(ctx context.Context, client tempopb.MetricsGeneratorClient) (interface{}, error) {
+			return client.GetAttributeProfile(ctx, req)
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying generators in Querier.AttributeProfile")
+	}
+
+	results := make([]*tempopb.AttributeProfileResponse, 0, len(lookupResults))
+	for _, result := range lookupResults {
+		results = append(results, result.response.(*tempopb.AttributeProfileResponse))
+	}
+
+	return attributeprofiler.Combine(results...)
+}
+
 func valuesToV2Response(distinctValues *util.DistinctValueCollector[tempopb.TagValue]) *tempopb.SearchTagValuesV2Response {
 	resp := &tempopb.SearchTagValuesV2Response{}
 	for _, v := range distinctValues.Values() {
@@ -755,16 +792,54 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 		return nil, errors.Wrap(err, "error extracting org id in Querier.BackendSearch")
 	}
 
-	blockID, err := uuid.Parse(req.BlockID)
+	meta, opts, err := q.blockSearchParams(tenantID, req)
 	if err != nil {
 		return nil, err
 	}
 
-	enc, err := backend.ParseEncoding(req.Encoding)
+	// collect the statistics of the parquet iterators, the query frontend combines them with the
+	// explanation of the query
+	var stats *pq.StatsCollector
+	if req.SearchReq.Explain {
+		stats = pq.NewStatsCollector()
+		ctx = pq.ContextWithStatsCollector(ctx, stats)
+	}
+
+	var resp *tempopb.SearchResponse
+	if api.IsTraceQLQuery(req.SearchReq) {
+		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+			return q.store.Fetch(ctx, meta, req, opts)
+		})
+
+		resp, err = q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+	} else {
+		resp, err = q.store.Search(ctx, meta, req.SearchReq, opts)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if stats != nil {
+		resp.Explain = &tempopb.SearchExplain{
+			Iterators: explainIterators(stats.Stats()),
+		}
+	}
+
+	return resp, nil
+}
+
+// blockSearchParams returns the meta of the backend block and the options to search the pages of req.
+func (q *Querier) blockSearchParams(tenantID string, req *tempopb.SearchBlockRequest) (*backend.BlockMeta, common.SearchOptions, error) {
+	blockID, err := uuid.Parse(req.BlockID)
+	if err != nil {
+		return nil, common.SearchOptions{}, err
+	}
+
+	enc, err := backend.ParseEncoding(req.Encoding)
+	if err != nil {
+		return nil, common.SearchOptions{}, err
+	}
+
 	meta := &backend.BlockMeta{
 		Version:       req.Version,
 		TenantID:      tenantID,
@@ -775,6 +850,8 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 		BlockID:       blockID,
 		DataEncoding:  req.DataEncoding,
 		FooterSize:    req.FooterSize,
+
+		DedicatedColumns: backend.DedicatedColumnsFromTempopb(req.DedicatedColumns),
 	}
 
 	opts := common.DefaultSearchOptions()
@@ -782,15 +859,103 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 	opts.TotalPages = int(req.PagesToSearch)
 	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
 
-	if api.IsTraceQLQuery(req.SearchReq) {
-		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
-			return q.store.Fetch(ctx, meta, req, opts)
+	return meta, opts, nil
+}
+
+func explainIterators(stats []pq.IteratorStats) []*tempopb.ExplainIterator {
+	iterators := make([]*tempopb.ExplainIterator, 0, len(stats))
+	for _, s := range stats {
+		iterators = append(iterators, &tempopb.ExplainIterator{
+			Column:                s.Column,
+			InspectedColumnChunks: s.InspectedColumnChunks,
+			KeptColumnChunks:      s.KeptColumnChunks,
+			InspectedPages:        s.InspectedPages,
+			KeptPages:             s.KeptPages,
+			InspectedValues:       s.InspectedValues,
+			KeptValues:            s.KeptValues,
+			DurationNanos:         s.Duration.Nanoseconds(),
 		})
+	}
+	return iterators
+}
+
+// QueryRange evaluates a TraceQL metrics query against the given pages of a single backend block.
+func (q *Querier) QueryRange(ctx context.Context, req *tempopb.QueryRangeRequest) (*tempopb.QueryRangeResponse, error) {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.QueryRange")
+	}
+
+	if req.Block == nil {
+		return nil, errors.New("blockID required, metrics queries are only supported on backend blocks")
+	}
+
+	meta, opts, err := q.blockSearchParams(tenantID, req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	traceql.AlignRequest(req)
+
+	eval, err := q.engine.CompileMetricsQueryRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return q.store.Fetch(ctx, meta, req, opts)
+	})
+
+	err = eval.Do(ctx, fetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	_, bytes := eval.Metrics()
+
+	return &tempopb.QueryRangeResponse{
+		Series: eval.Results().ToProto(req),
+		Metrics: &tempopb.SearchMetrics{
+			InspectedBytes: bytes,
+		},
+	}, nil
+}
+
+// TraceSummary merges the traces matching a TraceQL query in the given pages of a single backend
+// block into an aggregated call tree.
+func (q *Querier) TraceSummary(ctx context.Context, req *tempopb.TraceSummaryRequest) (*tempopb.TraceSummaryResponse, error) {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.TraceSummary")
+	}
 
-		return q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+	if req.Block == nil {
+		return nil, errors.New("blockID required, trace summaries are only supported on backend blocks")
 	}
 
-	return q.store.Search(ctx, meta, req.SearchReq, opts)
+	meta, opts, err := q.blockSearchParams(tenantID, req.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	var inspectedBytes func() uint64
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		resp, err := q.store.Fetch(ctx, meta, req, opts)
+		inspectedBytes = resp.Bytes
+		return resp, err
+	})
+
+	summary, err := traceqlmetrics.GetTraceSummary(ctx, req.Query, int(req.Limit), req.Start, req.End, fetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := summary.ToProto()
+	resp.Metrics = &tempopb.SearchMetrics{}
+	if inspectedBytes != nil {
+		resp.Metrics.InspectedBytes = inspectedBytes()
+	}
+	return resp, nil
 }
 
 func (q *Querier) postProcessIngesterSearchResults(req *tempopb.SearchRequest, rr []responseFromIngesters) *tempopb.SearchResponse {
@@ -0,0 +1,67 @@
+package ruler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/weaveworks/common/user"
+)
+
+const alertmanagerAlertsPath = "/api/v2/alerts"
+
+// alert is an alert of the Alertmanager v2 API.
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// alertmanagerClient sends alerts to an Alertmanager compatible endpoint.
+type alertmanagerClient struct {
+	url    string
+	client *http.Client
+}
+
+func newAlertmanagerClient(cfg AlertmanagerConfig) *alertmanagerClient {
+	return &alertmanagerClient{
+		url:    strings.TrimSuffix(cfg.URL, "/") + alertmanagerAlertsPath,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// send posts the alerts of a tenant. The tenant is sent in the X-Scope-OrgID header for multi-tenant
+// Alertmanagers.
+func (c *alertmanagerClient) send(ctx context.Context, tenantID string, alerts []alert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := user.InjectOrgIDIntoHTTPRequest(user.InjectOrgID(ctx, tenantID), req); err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("alertmanager returned %d: %s", resp.StatusCode, string(msg))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package ruler
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/tempo/modules/generator/storage"
+)
+
+// Config for the ruler.
+type Config struct {
+	// EvaluationInterval is the time between evaluations of the rules of all tenants
+	EvaluationInterval time.Duration `yaml:"evaluation_interval"`
+	// DefaultWindow is the time range searched by rules without a window
+	DefaultWindow time.Duration `yaml:"default_window"`
+	// MaxTracesPerRule is the limit of the searches of the rules
+	MaxTracesPerRule uint32 `yaml:"max_traces_per_rule"`
+
+	// QueryFrontendAddress is the http address of the query frontend, including the http_api_prefix
+	QueryFrontendAddress string        `yaml:"query_frontend_address"`
+	QueryTimeout         time.Duration `yaml:"query_timeout"`
+
+	Alertmanager AlertmanagerConfig `yaml:"alertmanager"`
+	// Storage writes the results of the rules as metrics, enabled if a path is configured
+	Storage storage.Config `yaml:"storage"`
+}
+
+type AlertmanagerConfig struct {
+	// URL of an Alertmanager compatible endpoint, alerts are disabled if empty
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// RegisterFlagsAndApplyDefaults registers the flags.
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	cfg.EvaluationInterval = time.Minute
+	cfg.DefaultWindow = 5 * time.Minute
+	cfg.MaxTracesPerRule = 100
+	cfg.QueryTimeout = 30 * time.Second
+	cfg.Alertmanager.Timeout = 10 * time.Second
+	cfg.Storage.RegisterFlagsAndApplyDefaults(prefix, f)
+}
@@ -0,0 +1,12 @@
+package ruler
+
+import (
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+)
+
+type rulerOverrides interface {
+	RulerRules(userID string) sharedconfig.RulerRules
+}
+
+var _ rulerOverrides = (overrides.Interface)(nil)
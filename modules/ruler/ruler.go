@@ -0,0 +1,282 @@
+package ruler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	tsdb_errors "github.com/prometheus/prometheus/tsdb/errors"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/generator/storage"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+const (
+	// metricMatchedTraces is written to the storage for every evaluation of a rule
+	metricMatchedTraces = "traces_ruler_matched_traces"
+	// maxTraceIDsPerAlert is the number of matched trace IDs added to the annotations of an alert
+	maxTraceIDsPerAlert = 10
+)
+
+var ErrUnconfigured = errors.New("no ruler.alertmanager.url or ruler.storage.path configured, ruler will be disabled")
+
+var (
+	metricEvaluations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ruler_evaluations_total",
+		Help:      "The total number of rule evaluations per tenant.",
+	}, []string{"tenant"})
+	metricEvaluationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ruler_evaluation_failures_total",
+		Help:      "The total number of failed rule evaluations per tenant.",
+	}, []string{"tenant"})
+	metricRulesMatched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ruler_rules_matched_total",
+		Help:      "The total number of rule evaluations that found traces per tenant.",
+	}, []string{"tenant"})
+)
+
+type ruleKey struct {
+	tenant string
+	rule   string
+}
+
+// Ruler periodically evaluates the TraceQL rules of the tenants through the search API of the query
+// frontend. Rules matching traces fire alerts and their results are written as metrics.
+type Ruler struct {
+	services.Service
+
+	cfg       *Config
+	overrides rulerOverrides
+	tenants   func() []string
+
+	client       *http.Client
+	alertmanager *alertmanagerClient // nil if alerts are disabled
+
+	// only accessed by the evaluation loop
+	storages map[string]storage.Storage
+	firing   map[ruleKey]*alert
+
+	reg    prometheus.Registerer
+	logger log.Logger
+}
+
+// New makes a new Ruler evaluating the rules of the tenants returned by tenants.
+func New(cfg *Config, overrides rulerOverrides, tenants func() []string, reg prometheus.Registerer, logger log.Logger) (*Ruler, error) {
+	if cfg.Alertmanager.URL == "" && cfg.Storage.Path == "" {
+		return nil, ErrUnconfigured
+	}
+	if cfg.QueryFrontendAddress == "" {
+		return nil, errors.New("no ruler.query_frontend_address configured")
+	}
+
+	r := &Ruler{
+		cfg:       cfg,
+		overrides: overrides,
+		tenants:   tenants,
+
+		client: &http.Client{},
+
+		storages: map[string]storage.Storage{},
+		firing:   map[ruleKey]*alert{},
+
+		reg:    reg,
+		logger: logger,
+	}
+	if cfg.Alertmanager.URL != "" {
+		r.alertmanager = newAlertmanagerClient(cfg.Alertmanager)
+	}
+
+	r.Service = services.NewTimerService(cfg.EvaluationInterval, nil, r.evaluate, r.stopping)
+	return r, nil
+}
+
+// evaluate evaluates the rules of all tenants. Failed evaluations are logged and don't stop the ruler.
+func (r *Ruler) evaluate(ctx context.Context) error {
+	now := time.Now()
+	active := map[ruleKey]struct{}{}
+
+	for _, tenant := range r.tenants() {
+		for _, rule := range r.overrides.RulerRules(tenant) {
+			active[ruleKey{tenant, rule.Name}] = struct{}{}
+
+			metricEvaluations.WithLabelValues(tenant).Inc()
+			if err := r.evaluateRule(ctx, tenant, rule, now); err != nil {
+				metricEvaluationFailures.WithLabelValues(tenant).Inc()
+				level.Error(r.logger).Log("msg", "failed to evaluate rule", "tenant", tenant, "rule", rule.Name, "err", err)
+			}
+		}
+	}
+
+	// resolve the alerts of removed rules
+	for key, a := range r.firing {
+		if _, ok := active[key]; ok {
+			continue
+		}
+		delete(r.firing, key)
+		if err := r.sendAlert(ctx, key.tenant, a, now); err != nil {
+			level.Error(r.logger).Log("msg", "failed to resolve alert of removed rule", "tenant", key.tenant, "rule", key.rule, "err", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Ruler) evaluateRule(ctx context.Context, tenant string, rule sharedconfig.RulerRule, now time.Time) error {
+	window := time.Duration(rule.Window)
+	if window == 0 {
+		window = r.cfg.DefaultWindow
+	}
+
+	resp, err := r.search(ctx, tenant, rule.Query, now.Add(-window), now)
+	if err != nil {
+		return err
+	}
+	if len(resp.Traces) > 0 {
+		metricRulesMatched.WithLabelValues(tenant).Inc()
+	}
+
+	errs := tsdb_errors.NewMulti()
+	if r.cfg.Storage.Path != "" {
+		errs.Add(r.writeResult(ctx, tenant, rule, len(resp.Traces), now))
+	}
+	if r.alertmanager != nil {
+		errs.Add(r.updateAlert(ctx, tenant, rule, resp.Traces, now))
+	}
+	return errs.Err()
+}
+
+// search runs the query of a rule through the search API of the query frontend.
+func (r *Ruler) search(ctx context.Context, tenant, query string, start, end time.Time) (*tempopb.SearchResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.QueryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(r.cfg.QueryFrontendAddress, "/")+api.PathSearch, nil)
+	if err != nil {
+		return nil, err
+	}
+	req, err = api.BuildSearchRequest(req, &tempopb.SearchRequest{
+		Query: query,
+		Start: uint32(start.Unix()),
+		End:   uint32(end.Unix()),
+		Limit: r.cfg.MaxTracesPerRule,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := user.InjectOrgIDIntoHTTPRequest(user.InjectOrgID(ctx, tenant), req); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("query frontend returned %d: %s", resp.StatusCode, string(msg))
+	}
+
+	searchResp := &tempopb.SearchResponse{}
+	if err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(resp.Body, searchResp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling search response: %w", err)
+	}
+	return searchResp, nil
+}
+
+// writeResult writes the number of traces matched by the rule.
+func (r *Ruler) writeResult(ctx context.Context, tenant string, rule sharedconfig.RulerRule, matched int, now time.Time) error {
+	s, ok := r.storages[tenant]
+	if !ok {
+		var err error
+		s, err = storage.New(&r.cfg.Storage, tenant, prometheus.WrapRegistererWithPrefix("tempo_ruler_", r.reg), r.logger)
+		if err != nil {
+			return fmt.Errorf("failed to create storage: %w", err)
+		}
+		r.storages[tenant] = s
+	}
+
+	lbls := labels.NewBuilder(labels.FromMap(rule.Labels)).
+		Set(labels.MetricName, metricMatchedTraces).
+		Set("rule", rule.Name).
+		Labels(nil)
+
+	app := s.Appender(ctx)
+	if _, err := app.Append(0, lbls, now.UnixMilli(), float64(matched)); err != nil {
+		_ = app.Rollback()
+		return err
+	}
+	return app.Commit()
+}
+
+// updateAlert fires the alert of a rule matching traces and resolves it once the rule doesn't match anymore.
+func (r *Ruler) updateAlert(ctx context.Context, tenant string, rule sharedconfig.RulerRule, traces []*tempopb.TraceSearchMetadata, now time.Time) error {
+	key := ruleKey{tenant, rule.Name}
+
+	a, firing := r.firing[key]
+	if len(traces) == 0 {
+		if !firing {
+			return nil
+		}
+		delete(r.firing, key)
+		return r.sendAlert(ctx, tenant, a, now)
+	}
+
+	if !firing {
+		a = &alert{StartsAt: now}
+		r.firing[key] = a
+	}
+
+	a.Labels = map[string]string{"alertname": rule.Name}
+	for k, v := range rule.Labels {
+		a.Labels[k] = v
+	}
+
+	traceIDs := make([]string, 0, maxTraceIDsPerAlert)
+	for i := 0; i < len(traces) && i < maxTraceIDsPerAlert; i++ {
+		traceIDs = append(traceIDs, traces[i].TraceID)
+	}
+	a.Annotations = map[string]string{
+		"query":          rule.Query,
+		"matched_traces": strconv.Itoa(len(traces)),
+		"trace_ids":      strings.Join(traceIDs, ","),
+	}
+	for k, v := range rule.Annotations {
+		a.Annotations[k] = v
+	}
+
+	// the alert resolves itself if the ruler stops sending it
+	return r.sendAlert(ctx, tenant, a, now.Add(4*r.cfg.EvaluationInterval))
+}
+
+func (r *Ruler) sendAlert(ctx context.Context, tenant string, a *alert, endsAt time.Time) error {
+	a.EndsAt = endsAt
+	return r.alertmanager.send(ctx, tenant, []alert{*a})
+}
+
+func (r *Ruler) stopping(_ error) error {
+	errs := tsdb_errors.NewMulti()
+	for _, s := range r.storages {
+		errs.Add(s.Close())
+	}
+	return errs.Err()
+}
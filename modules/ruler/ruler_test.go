@@ -0,0 +1,139 @@
+package ruler
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+type mockOverrides struct {
+	rules map[string]sharedconfig.RulerRules
+}
+
+func (m *mockOverrides) RulerRules(userID string) sharedconfig.RulerRules {
+	return m.rules[userID]
+}
+
+func TestRuler(t *testing.T) {
+	matching := atomic.NewBool(true)
+	frontend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tempo/api/search", r.URL.Path)
+		assert.Equal(t, "tenant", r.Header.Get("X-Scope-OrgID"))
+		assert.Equal(t, `{ status = error } | count() > 10`, r.URL.Query().Get("q"))
+		assert.Equal(t, "100", r.URL.Query().Get("limit"))
+
+		resp := &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}}
+		if matching.Load() {
+			resp.Traces = []*tempopb.TraceSearchMetadata{{TraceID: "1"}, {TraceID: "2"}}
+		}
+		require.NoError(t, (&jsonpb.Marshaler{}).Marshal(w, resp))
+	}))
+	defer frontend.Close()
+
+	var mtx sync.Mutex
+	var alerts []alert
+	alertmanager := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, alertmanagerAlertsPath, r.URL.Path)
+		assert.Equal(t, "tenant", r.Header.Get("X-Scope-OrgID"))
+
+		var received []alert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		alerts = append(alerts, received...)
+	}))
+	defer alertmanager.Close()
+
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.QueryFrontendAddress = frontend.URL + "/tempo/"
+	cfg.Alertmanager.URL = alertmanager.URL
+	cfg.Storage.Path = t.TempDir()
+
+	o := &mockOverrides{rules: map[string]sharedconfig.RulerRules{
+		"tenant": {{
+			Name:        "many-errors",
+			Query:       `{ status = error } | count() > 10`,
+			Window:      model.Duration(10 * time.Minute),
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "many errors"},
+		}},
+	}}
+
+	r, err := New(cfg, o, func() []string { return []string{"tenant"} }, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, r.stopping(nil))
+	}()
+
+	// the rule matches and fires
+	require.NoError(t, r.evaluate(context.Background()))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, map[string]string{"alertname": "many-errors", "severity": "critical"}, alerts[0].Labels)
+	assert.Equal(t, map[string]string{
+		"query":          `{ status = error } | count() > 10`,
+		"matched_traces": "2",
+		"trace_ids":      "1,2",
+		"summary":        "many errors",
+	}, alerts[0].Annotations)
+	assert.True(t, alerts[0].EndsAt.After(time.Now()))
+
+	// the result was written to the storage of the tenant
+	_, err = os.Stat(filepath.Join(cfg.Storage.Path, "tenant", "wal"))
+	require.NoError(t, err)
+
+	// the alert is resent while the rule matches
+	require.NoError(t, r.evaluate(context.Background()))
+	require.Len(t, alerts, 2)
+	assert.Equal(t, alerts[0].StartsAt, alerts[1].StartsAt)
+
+	// and resolved once it doesn't
+	matching.Store(false)
+	require.NoError(t, r.evaluate(context.Background()))
+	require.Len(t, alerts, 3)
+	assert.False(t, alerts[2].EndsAt.After(time.Now()))
+
+	// nothing is sent for rules that don't match
+	require.NoError(t, r.evaluate(context.Background()))
+	require.Len(t, alerts, 3)
+
+	// the alerts of removed rules are resolved
+	matching.Store(true)
+	require.NoError(t, r.evaluate(context.Background()))
+	require.Len(t, alerts, 4)
+	o.rules = nil
+	require.NoError(t, r.evaluate(context.Background()))
+	require.Len(t, alerts, 5)
+	assert.False(t, alerts[4].EndsAt.After(time.Now()))
+}
+
+func TestRulerUnconfigured(t *testing.T) {
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+
+	_, err := New(cfg, &mockOverrides{}, nil, prometheus.NewRegistry(), log.NewNopLogger())
+	assert.Equal(t, ErrUnconfigured, err)
+
+	cfg.Alertmanager.URL = "http://alertmanager"
+	_, err = New(cfg, &mockOverrides{}, nil, prometheus.NewRegistry(), log.NewNopLogger())
+	assert.Error(t, err)
+}
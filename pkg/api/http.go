@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -31,18 +32,22 @@ const (
 	urlParamStart           = "start"
 	urlParamEnd             = "end"
 	urlParamSpansPerSpanSet = "spss"
+	urlParamEstimate        = "estimate"
+	urlParamExplain         = "explain"
+	urlParamSaved           = "saved"
 
 	// backend search (querier/serverless)
-	urlParamStartPage     = "startPage"
-	urlParamPagesToSearch = "pagesToSearch"
-	urlParamBlockID       = "blockID"
-	urlParamEncoding      = "encoding"
-	urlParamIndexPageSize = "indexPageSize"
-	urlParamTotalRecords  = "totalRecords"
-	urlParamDataEncoding  = "dataEncoding"
-	urlParamVersion       = "version"
-	urlParamSize          = "size"
-	urlParamFooterSize    = "footerSize"
+	urlParamStartPage        = "startPage"
+	urlParamPagesToSearch    = "pagesToSearch"
+	urlParamBlockID          = "blockID"
+	urlParamEncoding         = "encoding"
+	urlParamIndexPageSize    = "indexPageSize"
+	urlParamTotalRecords     = "totalRecords"
+	urlParamDataEncoding     = "dataEncoding"
+	urlParamVersion          = "version"
+	urlParamSize             = "size"
+	urlParamFooterSize       = "footerSize"
+	urlParamDedicatedColumns = "dc"
 
 	// maxBytes (serverless only)
 	urlParamMaxBytes = "maxBytes"
@@ -53,6 +58,16 @@ const (
 	// generator summary
 	urlParamGroupBy = "groupBy"
 
+	// attribute profile
+	urlParamService = "service"
+
+	// query range
+	urlParamStep = "step"
+
+	// trace diff
+	urlParamTraceA = "a"
+	urlParamTraceB = "b"
+
 	HeaderAccept         = "Accept"
 	HeaderContentType    = "Content-Type"
 	HeaderAcceptProtobuf = "application/protobuf"
@@ -62,6 +77,8 @@ const (
 	PathPrefixGenerator = "/generator"
 
 	PathTraces             = "/api/traces/{traceID}"
+	PathTraceDiff          = "/api/traces/diff"
+	PathTraceCriticalPath  = "/api/traces/{traceID}/criticalpath"
 	PathSearch             = "/api/search"
 	PathSearchTags         = "/api/search/tags"
 	PathSearchTagValues    = "/api/search/tag/{" + muxVarTagName + "}/values"
@@ -69,6 +86,9 @@ const (
 	PathUsageStats         = "/status/usage-stats"
 	PathSpanMetrics        = "/api/metrics"
 	PathSpanMetricsSummary = "/api/metrics/summary"
+	PathMetricsQueryRange  = "/api/metrics/query_range"
+	PathTraceSummary       = "/api/search/summary"
+	PathAttributeProfile   = "/api/metrics/attributes"
 
 	PathSearchTagValuesV2 = "/api/v2/search/tag/{" + muxVarTagName + "}/values"
 	PathSearchTagsV2      = "/api/v2/search/tags"
@@ -82,6 +102,16 @@ const (
 
 	defaultLimit           = 20
 	defaultSpansPerSpanSet = 3
+
+	// query range defaults. the step is chosen so that the range is covered by
+	// about defaultQueryRangePoints samples
+	defaultQueryRangeWindow = time.Hour
+	defaultQueryRangePoints = 100
+	maxQueryRangePoints     = 11000
+
+	// trace summary defaults
+	defaultTraceSummaryWindow = time.Hour
+	defaultTraceSummaryLimit  = 1000
 )
 
 func ParseTraceID(r *http.Request) ([]byte, error) {
@@ -99,6 +129,33 @@ func ParseTraceID(r *http.Request) ([]byte, error) {
 	return byteID, nil
 }
 
+// ParseTraceDiffRequest returns the IDs of the two traces to compare
+func ParseTraceDiffRequest(r *http.Request) ([]byte, []byte, error) {
+	parse := func(param string) ([]byte, error) {
+		id, ok := extractQueryParam(r, param)
+		if !ok {
+			return nil, fmt.Errorf("please provide a traceID for %s", param)
+		}
+
+		byteID, err := util.HexStringToTraceID(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid traceID for %s: %w", param, err)
+		}
+		return byteID, nil
+	}
+
+	a, err := parse(urlParamTraceA)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := parse(urlParamTraceB)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a, b, nil
+}
+
 // ParseSearchRequest takes an http.Request and decodes query params to create a tempopb.SearchRequest
 func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	req := &tempopb.SearchRequest{
@@ -135,12 +192,24 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 		req.Query = query
 	}
 
+	saved, savedFound := extractQueryParam(r, urlParamSaved)
+	if savedFound {
+		// saved queries are resolved by the query frontend and replace q
+		if queryFound {
+			return nil, fmt.Errorf("invalid request: can't specify saved and q in the same query")
+		}
+		req.Saved = saved
+	}
+
 	encodedTags, tagsFound := extractQueryParam(r, urlParamTags)
 	if tagsFound {
 		// tags and traceQL API are mutually exclusive
 		if queryFound {
 			return nil, fmt.Errorf("invalid request: can't specify tags and q in the same query")
 		}
+		if savedFound {
+			return nil, fmt.Errorf("invalid request: can't specify tags and saved in the same query")
+		}
 
 		decoder := logfmt.NewDecoder(strings.NewReader(encodedTags))
 
@@ -165,13 +234,13 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	// if we don't have a query or tags, and we don't see start or end treat this like an old style search
 	// if we have no tags but we DO have start/end we have to treat this like a range search with no
 	// tags specified.
-	if !queryFound && !tagsFound && req.Start == 0 && req.End == 0 {
+	if !queryFound && !tagsFound && !savedFound && req.Start == 0 && req.End == 0 {
 		// Passing tags as individual query parameters is not supported anymore, clients should use the tags
 		// query parameter instead. We still parse these tags since the initial Grafana implementation uses this.
 		// As Grafana gets updated and/or versions using this get old we can remove this section.
 		for k, v := range r.URL.Query() {
 			// Skip reserved keywords
-			if k == urlParamQuery || k == urlParamTags || k == urlParamMinDuration || k == urlParamMaxDuration || k == urlParamLimit || k == urlParamSpansPerSpanSet || k == urlParamStart || k == urlParamEnd {
+			if k == urlParamQuery || k == urlParamTags || k == urlParamMinDuration || k == urlParamMaxDuration || k == urlParamLimit || k == urlParamSpansPerSpanSet || k == urlParamStart || k == urlParamEnd || k == urlParamEstimate || k == urlParamExplain || k == urlParamSaved {
 				continue
 			}
 
@@ -223,6 +292,14 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 		req.SpansPerSpanSet = uint32(spansPerSpanSet)
 	}
 
+	if s, ok := extractQueryParam(r, urlParamExplain); ok {
+		explain, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid explain: %w", err)
+		}
+		req.Explain = explain
+	}
+
 	// start and end == 0 is fine
 	if req.End == 0 && req.Start == 0 {
 		return req, nil
@@ -250,55 +327,63 @@ func ParseSearchBlockRequest(r *http.Request) (*tempopb.SearchBlockRequest, erro
 	req := &tempopb.SearchBlockRequest{
 		SearchReq: searchReq,
 	}
+	if err := parseBlockParams(r, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
 
+// parseBlockParams parses the http parameters of the backend block and the pages to search into req.
+func parseBlockParams(r *http.Request, req *tempopb.SearchBlockRequest) error {
 	s := r.URL.Query().Get(urlParamStartPage)
 	startPage, err := strconv.ParseInt(s, 10, 32)
 	if err != nil {
-		return nil, fmt.Errorf("invalid startPage: %w", err)
+		return fmt.Errorf("invalid startPage: %w", err)
 	}
 	if startPage < 0 {
-		return nil, fmt.Errorf("startPage must be non-negative. received: %s", s)
+		return fmt.Errorf("startPage must be non-negative. received: %s", s)
 	}
 	req.StartPage = uint32(startPage)
 
 	s = r.URL.Query().Get(urlParamPagesToSearch)
 	pagesToSearch64, err := strconv.ParseInt(s, 10, 32)
 	if err != nil {
-		return nil, fmt.Errorf("invalid pagesToSearch %s: %w", s, err)
+		return fmt.Errorf("invalid pagesToSearch %s: %w", s, err)
 	}
 	if pagesToSearch64 <= 0 {
-		return nil, fmt.Errorf("pagesToSearch must be greater than 0. received: %s", s)
+		return fmt.Errorf("pagesToSearch must be greater than 0. received: %s", s)
 	}
 	req.PagesToSearch = uint32(pagesToSearch64)
 
 	s = r.URL.Query().Get(urlParamBlockID)
 	blockID, err := uuid.Parse(s)
 	if err != nil {
-		return nil, fmt.Errorf("invalid blockID: %w", err)
+		return fmt.Errorf("invalid blockID: %w", err)
 	}
 	req.BlockID = blockID.String()
 
 	s = r.URL.Query().Get(urlParamEncoding)
 	encoding, err := backend.ParseEncoding(s)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	req.Encoding = encoding.String()
 
 	s = r.URL.Query().Get(urlParamIndexPageSize)
 	indexPageSize, err := strconv.ParseInt(s, 10, 32)
 	if err != nil {
-		return nil, fmt.Errorf("invalid indexPageSize %s: %w", s, err)
+		return fmt.Errorf("invalid indexPageSize %s: %w", s, err)
 	}
 	req.IndexPageSize = uint32(indexPageSize)
 
 	s = r.URL.Query().Get(urlParamTotalRecords)
 	totalRecords, err := strconv.ParseInt(s, 10, 32)
 	if err != nil {
-		return nil, fmt.Errorf("invalid totalRecords %s: %w", s, err)
+		return fmt.Errorf("invalid totalRecords %s: %w", s, err)
 	}
 	if totalRecords <= 0 {
-		return nil, fmt.Errorf("totalRecords must be greater than 0. received %d", totalRecords)
+		return fmt.Errorf("totalRecords must be greater than 0. received %d", totalRecords)
 	}
 	req.TotalRecords = uint32(totalRecords)
 
@@ -310,14 +395,14 @@ func ParseSearchBlockRequest(r *http.Request) (*tempopb.SearchBlockRequest, erro
 
 	version := r.URL.Query().Get(urlParamVersion)
 	if version == "" {
-		return nil, errors.New("version required")
+		return errors.New("version required")
 	}
 	req.Version = version
 
 	s = r.URL.Query().Get(urlParamSize)
 	size, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid size %s: %w", s, err)
+		return fmt.Errorf("invalid size %s: %w", s, err)
 	}
 	req.Size_ = size
 
@@ -326,11 +411,23 @@ func ParseSearchBlockRequest(r *http.Request) (*tempopb.SearchBlockRequest, erro
 	f := r.URL.Query().Get(urlParamFooterSize)
 	footerSize, err := strconv.ParseUint(f, 10, 32)
 	if err != nil {
-		return nil, fmt.Errorf("invalid footerSize %s: %w", f, err)
+		return fmt.Errorf("invalid footerSize %s: %w", f, err)
 	}
 	req.FooterSize = uint32(footerSize)
 
-	return req, nil
+	// dedicated columns are only set for blocks storing attributes in them
+	if s, ok := extractQueryParam(r, urlParamDedicatedColumns); ok {
+		var dedicatedColumns []*tempopb.DedicatedColumn
+		if err := json.Unmarshal([]byte(s), &dedicatedColumns); err != nil {
+			return fmt.Errorf("invalid dedicated columns %s: %w", s, err)
+		}
+		if err := backend.DedicatedColumnsFromTempopb(dedicatedColumns).Validate(); err != nil {
+			return fmt.Errorf("invalid dedicated columns: %w", err)
+		}
+		req.DedicatedColumns = dedicatedColumns
+	}
+
+	return nil
 }
 
 func ParseSpanMetricsRequest(r *http.Request) (*tempopb.SpanMetricsRequest, error) {
@@ -407,6 +504,216 @@ func ParseSpanMetricsSummaryRequest(r *http.Request) (*tempopb.SpanMetricsSummar
 	return req, nil
 }
 
+// ParseAttributeProfileRequest takes an http.Request and decodes query params to create a tempopb.AttributeProfileRequest
+func ParseAttributeProfileRequest(r *http.Request) (*tempopb.AttributeProfileRequest, error) {
+	req := &tempopb.AttributeProfileRequest{}
+
+	if s, ok := extractQueryParam(r, urlParamService); ok {
+		req.Service = s
+	}
+
+	return req, nil
+}
+
+// ParseQueryRangeRequest takes an http.Request and decodes query params to create a tempopb.QueryRangeRequest.
+// Start and end are unix epoch seconds and step is a duration (i.e. 30s) or a number of seconds. If
+// blockID is set the remaining backend block params are required.
+func ParseQueryRangeRequest(r *http.Request) (*tempopb.QueryRangeRequest, error) {
+	req := &tempopb.QueryRangeRequest{}
+
+	req.Query = r.URL.Query().Get(urlParamQuery)
+	if req.Query == "" {
+		return nil, errors.New("query required")
+	}
+
+	end := time.Now()
+	if s, ok := extractQueryParam(r, urlParamEnd); ok {
+		e, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+		end = time.Unix(e, 0)
+	}
+
+	start := end.Add(-defaultQueryRangeWindow)
+	if s, ok := extractQueryParam(r, urlParamStart); ok {
+		st, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+		start = time.Unix(st, 0)
+	}
+
+	if !end.After(start) {
+		return nil, errors.New("end must be greater than start")
+	}
+	req.Start = uint64(start.UnixNano())
+	req.End = uint64(end.UnixNano())
+
+	step := end.Sub(start) / defaultQueryRangePoints
+	if step < time.Second {
+		step = time.Second
+	}
+	step = step.Truncate(time.Second)
+	if s, ok := extractQueryParam(r, urlParamStep); ok {
+		var err error
+		step, err = parseStep(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step: %w", err)
+		}
+	}
+	if step <= 0 {
+		return nil, errors.New("step must be greater than 0")
+	}
+	req.Step = uint64(step)
+
+	if points := (req.End - req.Start) / req.Step; points > maxQueryRangePoints {
+		return nil, fmt.Errorf("exceeded maximum resolution of %d points per time series. increase the step or reduce the time range", maxQueryRangePoints)
+	}
+
+	// backend block params are only present on requests from the query frontend
+	if _, ok := extractQueryParam(r, urlParamBlockID); !ok {
+		return req, nil
+	}
+
+	req.Block = &tempopb.SearchBlockRequest{}
+	if err := parseBlockParams(r, req.Block); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// ParseTraceSummaryRequest takes an http.Request and decodes query params to create a tempopb.TraceSummaryRequest
+func ParseTraceSummaryRequest(r *http.Request) (*tempopb.TraceSummaryRequest, error) {
+	req := &tempopb.TraceSummaryRequest{
+		Limit: defaultTraceSummaryLimit,
+	}
+
+	req.Query = r.URL.Query().Get(urlParamQuery)
+	if req.Query == "" {
+		return nil, errors.New("query required")
+	}
+
+	end := time.Now()
+	if s, ok := extractQueryParam(r, urlParamEnd); ok {
+		e, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+		end = time.Unix(e, 0)
+	}
+
+	start := end.Add(-defaultTraceSummaryWindow)
+	if s, ok := extractQueryParam(r, urlParamStart); ok {
+		st, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+		start = time.Unix(st, 0)
+	}
+
+	if !end.After(start) {
+		return nil, errors.New("end must be greater than start")
+	}
+	req.Start = uint64(start.UnixNano())
+	req.End = uint64(end.UnixNano())
+
+	if s, ok := extractQueryParam(r, urlParamLimit); ok {
+		limit, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit: %w", err)
+		}
+		if limit == 0 {
+			return nil, errors.New("limit must be greater than 0")
+		}
+		req.Limit = uint32(limit)
+	}
+
+	// backend block params are only present on requests from the query frontend
+	if _, ok := extractQueryParam(r, urlParamBlockID); !ok {
+		return req, nil
+	}
+
+	req.Block = &tempopb.SearchBlockRequest{}
+	if err := parseBlockParams(r, req.Block); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// parseStep accepts either a duration string or a number of seconds
+func parseStep(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q as a duration or number of seconds", s)
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+// BuildQueryRangeRequest takes a tempopb.QueryRangeRequest and populates the passed http.Request
+// with the appropriate params. If no http.Request is provided a new one is created. Backend
+// block params are only set if the request has a block.
+func BuildQueryRangeRequest(req *http.Request, queryRangeReq *tempopb.QueryRangeRequest) *http.Request {
+	if req == nil {
+		req = &http.Request{
+			URL: &url.URL{},
+		}
+	}
+
+	if queryRangeReq == nil {
+		return req
+	}
+
+	q := req.URL.Query()
+	q.Set(urlParamQuery, queryRangeReq.Query)
+	q.Set(urlParamStart, strconv.FormatUint(queryRangeReq.Start/uint64(time.Second), 10))
+	q.Set(urlParamEnd, strconv.FormatUint(queryRangeReq.End/uint64(time.Second), 10))
+	q.Set(urlParamStep, time.Duration(queryRangeReq.Step).String())
+
+	if queryRangeReq.Block != nil {
+		setBlockParams(q, queryRangeReq.Block)
+	}
+
+	req.URL.RawQuery = q.Encode()
+
+	return req
+}
+
+// BuildTraceSummaryRequest takes a tempopb.TraceSummaryRequest and populates the passed http.Request
+// with the appropriate params. If no http.Request is provided a new one is created. Backend
+// block params are only set if the request has a block.
+func BuildTraceSummaryRequest(req *http.Request, summaryReq *tempopb.TraceSummaryRequest) *http.Request {
+	if req == nil {
+		req = &http.Request{
+			URL: &url.URL{},
+		}
+	}
+
+	if summaryReq == nil {
+		return req
+	}
+
+	q := req.URL.Query()
+	q.Set(urlParamQuery, summaryReq.Query)
+	q.Set(urlParamStart, strconv.FormatUint(summaryReq.Start/uint64(time.Second), 10))
+	q.Set(urlParamEnd, strconv.FormatUint(summaryReq.End/uint64(time.Second), 10))
+	q.Set(urlParamLimit, strconv.FormatUint(uint64(summaryReq.Limit), 10))
+
+	if summaryReq.Block != nil {
+		setBlockParams(q, summaryReq.Block)
+	}
+
+	req.URL.RawQuery = q.Encode()
+
+	return req
+}
+
 // BuildSearchRequest takes a tempopb.SearchRequest and populates the passed http.Request
 // with the appropriate params. If no http.Request is provided a new one is created.
 func BuildSearchRequest(req *http.Request, searchReq *tempopb.SearchRequest) (*http.Request, error) {
@@ -437,6 +744,17 @@ func BuildSearchRequest(req *http.Request, searchReq *tempopb.SearchRequest) (*h
 		q.Set(urlParamQuery, searchReq.Query)
 	}
 
+	// the query frontend resolves saved queries and reuses the parameters of the resolved request
+	if len(searchReq.Saved) > 0 {
+		q.Set(urlParamSaved, searchReq.Saved)
+	} else {
+		q.Del(urlParamSaved)
+	}
+
+	if searchReq.Explain {
+		q.Set(urlParamExplain, "true")
+	}
+
 	if len(searchReq.Tags) > 0 {
 		builder := &strings.Builder{}
 		encoder := logfmt.NewEncoder(builder)
@@ -471,22 +789,31 @@ func BuildSearchBlockRequest(req *http.Request, searchReq *tempopb.SearchBlockRe
 	}
 
 	q := req.URL.Query()
-	q.Set(urlParamSize, strconv.FormatUint(searchReq.Size_, 10))
-	q.Set(urlParamBlockID, searchReq.BlockID)
-	q.Set(urlParamStartPage, strconv.FormatUint(uint64(searchReq.StartPage), 10))
-	q.Set(urlParamPagesToSearch, strconv.FormatUint(uint64(searchReq.PagesToSearch), 10))
-	q.Set(urlParamEncoding, searchReq.Encoding)
-	q.Set(urlParamIndexPageSize, strconv.FormatUint(uint64(searchReq.IndexPageSize), 10))
-	q.Set(urlParamTotalRecords, strconv.FormatUint(uint64(searchReq.TotalRecords), 10))
-	q.Set(urlParamDataEncoding, searchReq.DataEncoding)
-	q.Set(urlParamVersion, searchReq.Version)
-	q.Set(urlParamFooterSize, strconv.FormatUint(uint64(searchReq.FooterSize), 10))
-
+	setBlockParams(q, searchReq)
 	req.URL.RawQuery = q.Encode()
 
 	return req, nil
 }
 
+// setBlockParams sets the http parameters of the backend block and the pages to search of req.
+func setBlockParams(q url.Values, req *tempopb.SearchBlockRequest) {
+	q.Set(urlParamSize, strconv.FormatUint(req.Size_, 10))
+	q.Set(urlParamBlockID, req.BlockID)
+	q.Set(urlParamStartPage, strconv.FormatUint(uint64(req.StartPage), 10))
+	q.Set(urlParamPagesToSearch, strconv.FormatUint(uint64(req.PagesToSearch), 10))
+	q.Set(urlParamEncoding, req.Encoding)
+	q.Set(urlParamIndexPageSize, strconv.FormatUint(uint64(req.IndexPageSize), 10))
+	q.Set(urlParamTotalRecords, strconv.FormatUint(uint64(req.TotalRecords), 10))
+	q.Set(urlParamDataEncoding, req.DataEncoding)
+	q.Set(urlParamVersion, req.Version)
+	q.Set(urlParamFooterSize, strconv.FormatUint(uint64(req.FooterSize), 10))
+	if len(req.DedicatedColumns) > 0 {
+		// marshalling the plain columns can't fail
+		dedicatedColumns, _ := json.Marshal(req.DedicatedColumns)
+		q.Set(urlParamDedicatedColumns, string(dedicatedColumns))
+	}
+}
+
 // AddServerlessParams takes an already existing http.Request and adds maxBytes
 // to it
 func AddServerlessParams(req *http.Request, maxBytes int) *http.Request {
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -80,6 +81,63 @@ func TestQuerierParseSearchRequest(t *testing.T) {
 				SpansPerSpanSet: defaultSpansPerSpanSet,
 			},
 		},
+		{
+			name:     "estimate is not a tag",
+			urlQuery: "service.name=foo&estimate=true",
+			expected: &tempopb.SearchRequest{
+				Tags:            map[string]string{"service.name": "foo"},
+				Limit:           defaultLimit,
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+			},
+		},
+		{
+			name:     "explain",
+			urlQuery: "q=" + url.QueryEscape(`{ .foo="bar" }`) + "&explain=true",
+			expected: &tempopb.SearchRequest{
+				Query:           `{ .foo="bar" }`,
+				Tags:            map[string]string{},
+				Limit:           defaultLimit,
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+				Explain:         true,
+			},
+		},
+		{
+			name:     "invalid explain",
+			urlQuery: "explain=yes",
+			err:      "invalid explain: strconv.ParseBool: parsing \"yes\": invalid syntax",
+		},
+		{
+			name:     "saved",
+			urlQuery: "saved=errors&start=10&end=20",
+			expected: &tempopb.SearchRequest{
+				Saved:           "errors",
+				Tags:            map[string]string{},
+				Start:           10,
+				End:             20,
+				Limit:           defaultLimit,
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+			},
+		},
+		{
+			name:     "saved is not a tag",
+			urlQuery: "saved=errors",
+			expected: &tempopb.SearchRequest{
+				Saved:           "errors",
+				Tags:            map[string]string{},
+				Limit:           defaultLimit,
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+			},
+		},
+		{
+			name:     "saved and q",
+			urlQuery: "saved=errors&q=" + url.QueryEscape(`{ .foo="bar" }`),
+			err:      "invalid request: can't specify saved and q in the same query",
+		},
+		{
+			name:     "saved and tags",
+			urlQuery: "saved=errors&tags=foo%3Dbar",
+			err:      "invalid request: can't specify tags and saved in the same query",
+		},
 		{
 			name:     "minDuration greater than maxDuration",
 			urlQuery: "minDuration=20s&maxDuration=5s",
@@ -366,6 +424,32 @@ func TestParseSearchBlockRequest(t *testing.T) {
 				FooterSize:    2000,
 			},
 		},
+		{
+			url:           "/?start=10&end=20&startPage=0&pagesToSearch=10&blockID=b92ec614-3fd7-4299-b6db-f657e7025a9b&encoding=s2&footerSize=2000&indexPageSize=10&totalRecords=11&version=vParquet3&size=1000&dc=" + url.QueryEscape(`[{"scope":"event","name":"foo","type":"string"}]`),
+			expectedError: `invalid dedicated columns: dedicated column foo has invalid scope "event", must be "resource" or "span"`,
+		},
+		{
+			url: "/?start=10&end=20&startPage=0&pagesToSearch=10&blockID=b92ec614-3fd7-4299-b6db-f657e7025a9b&encoding=s2&footerSize=2000&indexPageSize=10&totalRecords=11&version=vParquet3&size=1000&dc=" + url.QueryEscape(`[{"scope":"span","name":"foo","type":"string"}]`),
+			expected: &tempopb.SearchBlockRequest{
+				SearchReq: &tempopb.SearchRequest{
+					Tags:            map[string]string{},
+					Start:           10,
+					End:             20,
+					Limit:           defaultLimit,
+					SpansPerSpanSet: defaultSpansPerSpanSet,
+				},
+				StartPage:        0,
+				PagesToSearch:    10,
+				BlockID:          "b92ec614-3fd7-4299-b6db-f657e7025a9b",
+				Encoding:         "s2",
+				IndexPageSize:    10,
+				TotalRecords:     11,
+				Version:          "vParquet3",
+				Size_:            1000,
+				FooterSize:       2000,
+				DedicatedColumns: []*tempopb.DedicatedColumn{{Scope: "span", Name: "foo", Type: "string"}},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -452,6 +536,165 @@ func TestBuildSearchBlockRequest(t *testing.T) {
 	}
 }
 
+func TestParseQueryRangeRequest(t *testing.T) {
+	tests := []struct {
+		query       string
+		expected    *tempopb.QueryRangeRequest
+		expectedErr string
+	}{
+		{
+			query:       "/",
+			expectedErr: "query required",
+		},
+		{
+			query:       "/?q={}|rate()&start=10&end=5",
+			expectedErr: "end must be greater than start",
+		},
+		{
+			query:       "/?q={}|rate()&start=10&end=20&step=foo",
+			expectedErr: "invalid step: cannot parse \"foo\" as a duration or number of seconds",
+		},
+		{
+			query:       "/?q={}|rate()&start=0&end=20000&step=1s",
+			expectedErr: "exceeded maximum resolution of 11000 points per time series. increase the step or reduce the time range",
+		},
+		{
+			query: "/?q={}|rate()&start=10&end=1010",
+			expected: &tempopb.QueryRangeRequest{
+				Query: "{}|rate()",
+				Start: 10 * uint64(time.Second),
+				End:   1010 * uint64(time.Second),
+				Step:  10 * uint64(time.Second),
+			},
+		},
+		{
+			query: "/?q={}|rate()&start=10&end=20&step=1.5",
+			expected: &tempopb.QueryRangeRequest{
+				Query: "{}|rate()",
+				Start: 10 * uint64(time.Second),
+				End:   20 * uint64(time.Second),
+				Step:  1500 * uint64(time.Millisecond),
+			},
+		},
+		{
+			query: "/?q={}|rate()&start=10&end=20&step=5s&blockID=b92ec614-3fd7-4299-b6db-f657e7025a9b&startPage=1&pagesToSearch=2&encoding=none&indexPageSize=0&version=vParquet2&size=1000&footerSize=100&totalRecords=3",
+			expected: &tempopb.QueryRangeRequest{
+				Query: "{}|rate()",
+				Start: 10 * uint64(time.Second),
+				End:   20 * uint64(time.Second),
+				Step:  5 * uint64(time.Second),
+				Block: &tempopb.SearchBlockRequest{
+					BlockID:       "b92ec614-3fd7-4299-b6db-f657e7025a9b",
+					StartPage:     1,
+					PagesToSearch: 2,
+					Encoding:      "none",
+					Version:       "vParquet2",
+					Size_:         1000,
+					FooterSize:    100,
+					TotalRecords:  3,
+				},
+			},
+		},
+		{
+			query:       "/?q={}|rate()&start=10&end=20&step=5s&blockID=b92ec614-3fd7-4299-b6db-f657e7025a9b&startPage=1&pagesToSearch=0",
+			expectedErr: "pagesToSearch must be greater than 0. received: 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.query, nil)
+
+			actual, err := ParseQueryRangeRequest(r)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			// build and parse again to confirm the request round trips
+			roundTrip, err := ParseQueryRangeRequest(BuildQueryRangeRequest(nil, actual))
+			assert.NoError(t, err)
+			assert.Equal(t, actual, roundTrip)
+		})
+	}
+}
+
+func TestParseTraceSummaryRequest(t *testing.T) {
+	tests := []struct {
+		query       string
+		expected    *tempopb.TraceSummaryRequest
+		expectedErr string
+	}{
+		{
+			query:       "/",
+			expectedErr: "query required",
+		},
+		{
+			query:       "/?q={}&start=10&end=5",
+			expectedErr: "end must be greater than start",
+		},
+		{
+			query:       "/?q={}&start=10&end=20&limit=0",
+			expectedErr: "limit must be greater than 0",
+		},
+		{
+			query: "/?q={}&start=10&end=20",
+			expected: &tempopb.TraceSummaryRequest{
+				Query: "{}",
+				Start: 10 * uint64(time.Second),
+				End:   20 * uint64(time.Second),
+				Limit: defaultTraceSummaryLimit,
+			},
+		},
+		{
+			query: "/?q={}&start=10&end=20&limit=5&blockID=b92ec614-3fd7-4299-b6db-f657e7025a9b&startPage=1&pagesToSearch=2&encoding=none&indexPageSize=0&version=vParquet3&size=1000&footerSize=100&totalRecords=3",
+			expected: &tempopb.TraceSummaryRequest{
+				Query: "{}",
+				Start: 10 * uint64(time.Second),
+				End:   20 * uint64(time.Second),
+				Limit: 5,
+				Block: &tempopb.SearchBlockRequest{
+					BlockID:       "b92ec614-3fd7-4299-b6db-f657e7025a9b",
+					StartPage:     1,
+					PagesToSearch: 2,
+					Encoding:      "none",
+					Version:       "vParquet3",
+					Size_:         1000,
+					FooterSize:    100,
+					TotalRecords:  3,
+				},
+			},
+		},
+		{
+			query:       "/?q={}&start=10&end=20&blockID=b92ec614-3fd7-4299-b6db-f657e7025a9b&startPage=1&pagesToSearch=0",
+			expectedErr: "pagesToSearch must be greater than 0. received: 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.query, nil)
+
+			actual, err := ParseTraceSummaryRequest(r)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			// build and parse again to confirm the request round trips
+			roundTrip, err := ParseTraceSummaryRequest(BuildTraceSummaryRequest(nil, actual))
+			assert.NoError(t, err)
+			assert.Equal(t, actual, roundTrip)
+		})
+	}
+}
+
 func TestValidateAndSanitizeRequest(t *testing.T) {
 	tests := []struct {
 		httpReq       *http.Request
@@ -521,6 +764,39 @@ func TestValidateAndSanitizeRequest(t *testing.T) {
 
 }
 
+func TestParseTraceDiffRequest(t *testing.T) {
+	tests := []struct {
+		url           string
+		a, b          []byte
+		expectedError string
+	}{
+		{
+			url: "/api/traces/diff?a=1234&b=abcd",
+			a:   []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x12, 0x34},
+			b:   []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xab, 0xcd},
+		},
+		{
+			url:           "/api/traces/diff?b=abcd",
+			expectedError: "please provide a traceID for a",
+		},
+		{
+			url:           "/api/traces/diff?a=1234&b=xyz",
+			expectedError: "invalid traceID for b: trace IDs can only contain hex characters: invalid character 'x' at position 1",
+		},
+	}
+
+	for _, tc := range tests {
+		a, b, err := ParseTraceDiffRequest(httptest.NewRequest("GET", tc.url, nil))
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, err, tc.expectedError)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tc.a, a)
+		assert.Equal(t, tc.b, b)
+	}
+}
+
 func TestBuildSearchRequest(t *testing.T) {
 	tests := []struct {
 		req     *tempopb.SearchRequest
@@ -586,6 +862,32 @@ func TestBuildSearchRequest(t *testing.T) {
 			},
 			query: "?end=20&maxDuration=40ms&minDuration=30ms&start=10",
 		},
+		{
+			req: &tempopb.SearchRequest{
+				Query:   "{}",
+				Start:   10,
+				End:     20,
+				Explain: true,
+			},
+			query: "?end=20&explain=true&q=%7B%7D&start=10",
+		},
+		{
+			req: &tempopb.SearchRequest{
+				Saved: "errors",
+				Start: 10,
+				End:   20,
+			},
+			query: "?end=20&saved=errors&start=10",
+		},
+		{
+			httpReq: httptest.NewRequest("GET", "/?saved=errors", nil),
+			req: &tempopb.SearchRequest{
+				Query: "{}",
+				Start: 10,
+				End:   20,
+			},
+			query: "/?end=20&q=%7B%7D&start=10",
+		},
 	}
 
 	for _, tc := range tests {
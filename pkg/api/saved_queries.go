@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+const (
+	muxVarSavedQueryName = "name"
+
+	PathSavedQueries = "/api/saved-queries"
+	PathSavedQuery   = PathSavedQueries + "/{" + muxVarSavedQueryName + "}"
+)
+
+// ParseSavedQueryName returns the name of the saved query of requests to /api/saved-queries/{name}
+func ParseSavedQueryName(r *http.Request) (string, error) {
+	name, ok := mux.Vars(r)[muxVarSavedQueryName]
+	if !ok || name == "" {
+		return "", errors.New("please provide a saved query name")
+	}
+	return name, nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/tempo/pkg/tempopb"
 )
@@ -20,6 +21,12 @@ func IsSearchBlock(r *http.Request) bool {
 	return q.Get(urlParamBlockID) != ""
 }
 
+// IsSearchEstimate returns true if the search request only asks for an estimate of its cost
+func IsSearchEstimate(r *http.Request) bool {
+	estimate, err := strconv.ParseBool(r.URL.Query().Get(urlParamEstimate))
+	return err == nil && estimate
+}
+
 // IsTraceQLQuery returns true if the request contains a traceQL query.
 func IsTraceQLQuery(r *tempopb.SearchRequest) bool {
 	return len(r.Query) > 0
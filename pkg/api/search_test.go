@@ -30,3 +30,12 @@ func TestIsSearchBlock(t *testing.T) {
 	assert.True(t, IsSearchBlock(httptest.NewRequest("GET", "/querier/api/search?blockID=blerg", nil)))
 	assert.True(t, IsSearchBlock(httptest.NewRequest("GET", "/querier/api/search/?blockID=blerg", nil)))
 }
+
+func TestIsSearchEstimate(t *testing.T) {
+	assert.False(t, IsSearchEstimate(httptest.NewRequest("GET", "/api/search?start=1&end=2", nil)))
+	assert.False(t, IsSearchEstimate(httptest.NewRequest("GET", "/api/search?estimate=false", nil)))
+	assert.False(t, IsSearchEstimate(httptest.NewRequest("GET", "/api/search?estimate=blerg", nil)))
+
+	assert.True(t, IsSearchEstimate(httptest.NewRequest("GET", "/api/search?estimate=true", nil)))
+	assert.True(t, IsSearchEstimate(httptest.NewRequest("GET", "/api/search?start=1&end=2&estimate=1", nil)))
+}
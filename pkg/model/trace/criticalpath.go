@@ -0,0 +1,159 @@
+package trace
+
+import (
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// CriticalPath returns the critical path of the trace. The critical path starts at the end of the
+// longest root span and walks backwards in time. At every point it descends into the child that
+// finished last before that point, children overlapping with the previously chosen child are skipped.
+// Time in which no such child is running is attributed to the span itself. Children are clipped to
+// the time range of their parent.
+//
+// Additionally every span reports its self time and the time spent waiting on children. Concurrent
+// children are only counted once.
+func CriticalPath(t *tempopb.Trace) *tempopb.TraceCriticalPathResponse {
+	resp := &tempopb.TraceCriticalPathResponse{}
+
+	roots := buildSpanTree(t)
+	if len(roots) == 0 {
+		return resp
+	}
+
+	var root *spanNode
+	for _, r := range roots {
+		if root == nil || spanDuration(r.span) > spanDuration(root.span) {
+			root = r
+		}
+	}
+
+	c := &criticalPathBuilder{onPath: map[*spanNode]uint64{}}
+	if d := spanDuration(root.span); d > 0 {
+		c.walk(root, root.span.StartTimeUnixNano, root.span.EndTimeUnixNano)
+	}
+
+	// segments were added walking backwards in time
+	for i, j := 0, len(c.segments)-1; i < j; i, j = i+1, j-1 {
+		c.segments[i], c.segments[j] = c.segments[j], c.segments[i]
+	}
+
+	resp.Segments = c.segments
+	resp.DurationNanos = spanDuration(root.span)
+	for _, r := range roots {
+		resp.Spans = c.appendSpans(resp.Spans, r)
+	}
+	return resp
+}
+
+type criticalPathBuilder struct {
+	segments []*tempopb.CriticalPathSegment
+	onPath   map[*spanNode]uint64
+}
+
+// walk adds the critical path of n within [start, end) to the builder
+func (c *criticalPathBuilder) walk(n *spanNode, start, end uint64) {
+	cursor := end
+	for cursor > start {
+		var (
+			next               *spanNode
+			nextStart, nextEnd uint64
+		)
+		for _, child := range n.children {
+			s, e := clip(child, start, end)
+			if s >= e || e > cursor {
+				continue
+			}
+			if next == nil || e > nextEnd {
+				next, nextStart, nextEnd = child, s, e
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		if nextEnd < cursor {
+			c.addSegment(n, nextEnd, cursor)
+		}
+		c.walk(next, nextStart, nextEnd)
+		cursor = nextStart
+	}
+
+	if cursor > start {
+		c.addSegment(n, start, cursor)
+	}
+}
+
+func (c *criticalPathBuilder) addSegment(n *spanNode, start, end uint64) {
+	c.onPath[n] += end - start
+	c.segments = append(c.segments, &tempopb.CriticalPathSegment{
+		SpanID:            util.SpanIDToHexString(n.span.SpanId),
+		ServiceName:       n.service,
+		Name:              n.span.Name,
+		StartTimeUnixNano: start,
+		DurationNanos:     end - start,
+	})
+}
+
+// appendSpans appends the time attribution of n and its descendants in depth-first order
+func (c *criticalPathBuilder) appendSpans(spans []*tempopb.CriticalPathSpan, n *spanNode) []*tempopb.CriticalPathSpan {
+	duration := spanDuration(n.span)
+	waiting := childrenTime(n)
+
+	s := &tempopb.CriticalPathSpan{
+		SpanID:            util.SpanIDToHexString(n.span.SpanId),
+		ServiceName:       n.service,
+		Name:              n.span.Name,
+		StartTimeUnixNano: n.span.StartTimeUnixNano,
+		DurationNanos:     duration,
+		SelfTimeNanos:     duration - waiting,
+		ChildrenTimeNanos: waiting,
+		CriticalPathNanos: c.onPath[n],
+	}
+	if len(n.span.ParentSpanId) > 0 {
+		s.ParentSpanID = util.SpanIDToHexString(n.span.ParentSpanId)
+	}
+	spans = append(spans, s)
+
+	for _, child := range n.children {
+		spans = c.appendSpans(spans, child)
+	}
+	return spans
+}
+
+// childrenTime returns the time in which at least one child of n is running. Children are sorted
+// by start time, so the union of their intervals can be computed in a single pass.
+func childrenTime(n *spanNode) uint64 {
+	var (
+		total    uint64
+		curStart uint64
+		curEnd   uint64
+	)
+	for _, child := range n.children {
+		s, e := clip(child, n.span.StartTimeUnixNano, n.span.EndTimeUnixNano)
+		if s >= e {
+			continue
+		}
+		if s > curEnd {
+			total += curEnd - curStart
+			curStart, curEnd = s, e
+			continue
+		}
+		if e > curEnd {
+			curEnd = e
+		}
+	}
+	return total + curEnd - curStart
+}
+
+// clip returns the start and end of n within [start, end)
+func clip(n *spanNode, start, end uint64) (uint64, uint64) {
+	s, e := n.span.StartTimeUnixNano, n.span.EndTimeUnixNano
+	if s < start {
+		s = start
+	}
+	if e > end {
+		e = end
+	}
+	return s, e
+}
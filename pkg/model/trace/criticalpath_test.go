@@ -0,0 +1,95 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestCriticalPath(t *testing.T) {
+	span := func(id, parent byte, name string, start, end uint64) *v1.Span {
+		s := &v1.Span{
+			SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+			Name:              name,
+			StartTimeUnixNano: start,
+			EndTimeUnixNano:   end,
+		}
+		if parent != 0 {
+			s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parent}
+		}
+		return s
+	}
+
+	// a [0, 100]
+	// ├── b [10, 50]
+	// ├── c [20, 80]
+	// │   └── e [30, 70]
+	// └── d [85, 95]
+	//     └── f [90, 120] (outlives its parent)
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		Resource: &v1_resource.Resource{
+			Attributes: []*v1_common.KeyValue{{Key: "service.name", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "svc"}}}},
+		},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+			span(6, 4, "f", 90, 120),
+			span(5, 3, "e", 30, 70),
+			span(4, 1, "d", 85, 95),
+			span(3, 1, "c", 20, 80),
+			span(2, 1, "b", 10, 50),
+			span(1, 0, "a", 0, 100),
+		}}},
+	}}}
+
+	resp := CriticalPath(tr)
+	require.Equal(t, uint64(100), resp.DurationNanos)
+
+	type segment struct {
+		name       string
+		start, end uint64
+	}
+	var segments []segment
+	for _, s := range resp.Segments {
+		require.Equal(t, "svc", s.ServiceName)
+		segments = append(segments, segment{s.Name, s.StartTimeUnixNano, s.StartTimeUnixNano + s.DurationNanos})
+	}
+	// b overlaps with c which finished later and is not on the critical path
+	assert.Equal(t, []segment{
+		{"a", 0, 20},
+		{"c", 20, 30},
+		{"e", 30, 70},
+		{"c", 70, 80},
+		{"a", 80, 85},
+		{"d", 85, 90},
+		{"f", 90, 95},
+		{"a", 95, 100},
+	}, segments)
+
+	expected := []*tempopb.CriticalPathSpan{
+		{SpanID: "0000000000000001", ServiceName: "svc", Name: "a", StartTimeUnixNano: 0, DurationNanos: 100, SelfTimeNanos: 20, ChildrenTimeNanos: 80, CriticalPathNanos: 30},
+		{SpanID: "0000000000000002", ParentSpanID: "0000000000000001", ServiceName: "svc", Name: "b", StartTimeUnixNano: 10, DurationNanos: 40, SelfTimeNanos: 40},
+		{SpanID: "0000000000000003", ParentSpanID: "0000000000000001", ServiceName: "svc", Name: "c", StartTimeUnixNano: 20, DurationNanos: 60, SelfTimeNanos: 20, ChildrenTimeNanos: 40, CriticalPathNanos: 20},
+		{SpanID: "0000000000000005", ParentSpanID: "0000000000000003", ServiceName: "svc", Name: "e", StartTimeUnixNano: 30, DurationNanos: 40, SelfTimeNanos: 40, CriticalPathNanos: 40},
+		{SpanID: "0000000000000004", ParentSpanID: "0000000000000001", ServiceName: "svc", Name: "d", StartTimeUnixNano: 85, DurationNanos: 10, SelfTimeNanos: 5, ChildrenTimeNanos: 5, CriticalPathNanos: 5},
+		{SpanID: "0000000000000006", ParentSpanID: "0000000000000004", ServiceName: "svc", Name: "f", StartTimeUnixNano: 90, DurationNanos: 30, SelfTimeNanos: 30, CriticalPathNanos: 5},
+	}
+	assert.Equal(t, expected, resp.Spans)
+}
+
+func TestCriticalPathEmpty(t *testing.T) {
+	resp := CriticalPath(nil)
+	assert.Empty(t, resp.Segments)
+	assert.Empty(t, resp.Spans)
+
+	resp = CriticalPath(&tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{{SpanId: []byte{1}, Name: "root"}}}},
+	}}})
+	assert.Empty(t, resp.Segments)
+	require.Len(t, resp.Spans, 1)
+	assert.Equal(t, "root", resp.Spans[0].Name)
+}
@@ -0,0 +1,139 @@
+package trace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const diffPathSeparator = " > "
+
+// Diff compares trace a to trace b. Spans are aligned by their path of service and span names
+// starting at the roots of the traces. Siblings with the same service and span name are matched
+// in the order of their start times. Spans without a match are returned with their entire subtree
+// as only existing in one of the traces.
+func Diff(a, b *tempopb.Trace) *tempopb.TraceDiffResponse {
+	d := &differ{resp: &tempopb.TraceDiffResponse{}}
+	d.align("", buildSpanTree(a), buildSpanTree(b))
+	return d.resp
+}
+
+type differ struct {
+	resp *tempopb.TraceDiffResponse
+}
+
+// align matches the sibling spans as and bs and recurses into the children of the matched pairs
+func (d *differ) align(parentPath string, as, bs []*spanNode) {
+	pending := make(map[string][]*spanNode, len(bs))
+	for _, n := range bs {
+		pending[n.key()] = append(pending[n.key()], n)
+	}
+
+	matched := make(map[*spanNode]struct{}, len(bs))
+	for _, na := range as {
+		key := na.key()
+		path := joinDiffPath(parentPath, key)
+
+		candidates := pending[key]
+		if len(candidates) == 0 {
+			d.unmatched(path, na, true)
+			continue
+		}
+		nb := candidates[0]
+		pending[key] = candidates[1:]
+		matched[nb] = struct{}{}
+
+		d.matched(path, na, nb)
+		d.align(path, na.children, nb.children)
+	}
+
+	for _, nb := range bs {
+		if _, ok := matched[nb]; !ok {
+			d.unmatched(joinDiffPath(parentPath, nb.key()), nb, false)
+		}
+	}
+}
+
+func (d *differ) matched(path string, a, b *spanNode) {
+	durationA := spanDuration(a.span)
+	durationB := spanDuration(b.span)
+
+	d.resp.MatchedSpans++
+	d.resp.Spans = append(d.resp.Spans, &tempopb.SpanDiff{
+		Path:               path,
+		ServiceName:        a.service,
+		Name:               a.span.Name,
+		SpanIDA:            util.SpanIDToHexString(a.span.SpanId),
+		SpanIDB:            util.SpanIDToHexString(b.span.SpanId),
+		DurationNanosA:     durationA,
+		DurationNanosB:     durationB,
+		DurationDeltaNanos: int64(durationB) - int64(durationA),
+		Attributes:         diffAttributes(a.span.Attributes, b.span.Attributes),
+	})
+}
+
+// unmatched adds n and all its descendants as only existing in trace a or b
+func (d *differ) unmatched(path string, n *spanNode, inA bool) {
+	diff := &tempopb.SpanDiff{
+		Path:        path,
+		ServiceName: n.service,
+		Name:        n.span.Name,
+	}
+	if inA {
+		d.resp.SpansOnlyInA++
+		diff.SpanIDA = util.SpanIDToHexString(n.span.SpanId)
+		diff.DurationNanosA = spanDuration(n.span)
+	} else {
+		d.resp.SpansOnlyInB++
+		diff.SpanIDB = util.SpanIDToHexString(n.span.SpanId)
+		diff.DurationNanosB = spanDuration(n.span)
+	}
+	d.resp.Spans = append(d.resp.Spans, diff)
+
+	for _, c := range n.children {
+		d.unmatched(joinDiffPath(path, c.key()), c, inA)
+	}
+}
+
+// diffAttributes returns the attributes that are different in a and b sorted by key
+func diffAttributes(a, b []*v1_common.KeyValue) []tempopb.AttributeDiff {
+	valuesA := make(map[string]*v1_common.AnyValue, len(a))
+	for _, kv := range a {
+		valuesA[kv.Key] = kv.Value
+	}
+	valuesB := make(map[string]*v1_common.AnyValue, len(b))
+	for _, kv := range b {
+		valuesB[kv.Key] = kv.Value
+	}
+
+	var diffs []tempopb.AttributeDiff
+	for k, va := range valuesA {
+		vb, ok := valuesB[k]
+		if ok && proto.Equal(va, vb) {
+			continue
+		}
+		diffs = append(diffs, tempopb.AttributeDiff{Key: k, A: va, B: vb})
+	}
+	for k, vb := range valuesB {
+		if _, ok := valuesA[k]; !ok {
+			diffs = append(diffs, tempopb.AttributeDiff{Key: k, B: vb})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+func joinDiffPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return strings.Join([]string{parent, key}, diffPathSeparator)
+}
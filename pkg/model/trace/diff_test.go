@@ -0,0 +1,116 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestDiff(t *testing.T) {
+	strVal := func(s string) *v1_common.AnyValue {
+		return &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: s}}
+	}
+	span := func(id, parent byte, name string, start, end uint64, attrs ...*v1_common.KeyValue) *v1.Span {
+		s := &v1.Span{
+			SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+			Name:              name,
+			StartTimeUnixNano: start,
+			EndTimeUnixNano:   end,
+			Attributes:        attrs,
+		}
+		if parent != 0 {
+			s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parent}
+		}
+		return s
+	}
+	batch := func(service string, spans ...*v1.Span) *v1.ResourceSpans {
+		return &v1.ResourceSpans{
+			Resource: &v1_resource.Resource{
+				Attributes: []*v1_common.KeyValue{{Key: "service.name", Value: strVal(service)}},
+			},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+		}
+	}
+
+	// a: frontend:GET > backend:query (x2) > db:SELECT
+	a := &tempopb.Trace{Batches: []*v1.ResourceSpans{
+		batch("frontend", span(1, 0, "GET", 0, 100)),
+		batch("backend",
+			span(3, 1, "query", 50, 90, &v1_common.KeyValue{Key: "only.a", Value: strVal("x")}),
+			span(2, 1, "query", 10, 40, &v1_common.KeyValue{Key: "status", Value: strVal("ok")}),
+		),
+		batch("db", span(4, 2, "SELECT", 20, 30)),
+	}}
+	// b: the first query is slower and has a different status, the second query is missing and a cache
+	// span was added
+	b := &tempopb.Trace{Batches: []*v1.ResourceSpans{
+		batch("frontend", span(11, 0, "GET", 0, 150)),
+		batch("backend",
+			span(12, 11, "query", 10, 60, &v1_common.KeyValue{Key: "status", Value: strVal("error")}),
+			span(15, 11, "cache", 60, 70),
+		),
+		batch("db", span(14, 12, "SELECT", 20, 30)),
+	}}
+
+	resp := Diff(a, b)
+	require.Equal(t, uint32(3), resp.MatchedSpans)
+	require.Equal(t, uint32(1), resp.SpansOnlyInA)
+	require.Equal(t, uint32(1), resp.SpansOnlyInB)
+
+	expected := []*tempopb.SpanDiff{
+		{
+			Path: "frontend:GET", ServiceName: "frontend", Name: "GET",
+			SpanIDA: "0000000000000001", SpanIDB: "000000000000000b",
+			DurationNanosA: 100, DurationNanosB: 150, DurationDeltaNanos: 50,
+		},
+		{
+			Path: "frontend:GET > backend:query", ServiceName: "backend", Name: "query",
+			SpanIDA: "0000000000000002", SpanIDB: "000000000000000c",
+			DurationNanosA: 30, DurationNanosB: 50, DurationDeltaNanos: 20,
+			Attributes: []tempopb.AttributeDiff{{Key: "status", A: strVal("ok"), B: strVal("error")}},
+		},
+		{
+			Path: "frontend:GET > backend:query > db:SELECT", ServiceName: "db", Name: "SELECT",
+			SpanIDA: "0000000000000004", SpanIDB: "000000000000000e",
+			DurationNanosA: 10, DurationNanosB: 10,
+		},
+		{
+			Path: "frontend:GET > backend:query", ServiceName: "backend", Name: "query",
+			SpanIDA: "0000000000000003", DurationNanosA: 40,
+		},
+		{
+			Path: "frontend:GET > backend:cache", ServiceName: "backend", Name: "cache",
+			SpanIDB: "000000000000000f", DurationNanosB: 10,
+		},
+	}
+	assert.Equal(t, expected, resp.Spans)
+}
+
+func TestDiffUnmatchedSubtree(t *testing.T) {
+	a := &tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+			{SpanId: []byte{1}, Name: "root"},
+			{SpanId: []byte{2}, ParentSpanId: []byte{1}, Name: "child"},
+			{SpanId: []byte{3}, ParentSpanId: []byte{2}, Name: "grandchild"},
+		}}},
+	}}}
+
+	resp := Diff(a, &tempopb.Trace{})
+	require.Equal(t, uint32(0), resp.MatchedSpans)
+	require.Equal(t, uint32(3), resp.SpansOnlyInA)
+
+	var paths []string
+	for _, s := range resp.Spans {
+		paths = append(paths, s.Path)
+	}
+	assert.Equal(t, []string{":root", ":root > :child", ":root > :child > :grandchild"}, paths)
+
+	resp = Diff(nil, nil)
+	assert.Empty(t, resp.Spans)
+}
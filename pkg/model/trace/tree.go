@@ -0,0 +1,86 @@
+package trace
+
+import (
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// spanNode is a span in the span tree of a trace
+type spanNode struct {
+	span     *v1.Span
+	service  string
+	children []*spanNode
+}
+
+func (n *spanNode) key() string {
+	return n.service + ":" + n.span.Name
+}
+
+// buildSpanTree returns the root spans of the trace. Spans whose parent is not part of the trace
+// are treated as roots. Siblings are sorted by start time.
+func buildSpanTree(t *tempopb.Trace) []*spanNode {
+	if t == nil {
+		return nil
+	}
+
+	var (
+		nodes []*spanNode
+		byID  = map[string]*spanNode{}
+	)
+	for _, b := range t.Batches {
+		service := serviceName(b.Resource)
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				n := &spanNode{span: s, service: service}
+				nodes = append(nodes, n)
+				if _, ok := byID[string(s.SpanId)]; !ok {
+					byID[string(s.SpanId)] = n
+				}
+			}
+		}
+	}
+
+	var roots []*spanNode
+	for _, n := range nodes {
+		parent, ok := byID[string(n.span.ParentSpanId)]
+		if len(n.span.ParentSpanId) == 0 || !ok || parent == n {
+			roots = append(roots, n)
+			continue
+		}
+		parent.children = append(parent.children, n)
+	}
+
+	sortSpanNodes(roots)
+	for _, n := range nodes {
+		sortSpanNodes(n.children)
+	}
+	return roots
+}
+
+func sortSpanNodes(nodes []*spanNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return compareSpans(nodes[i].span, nodes[j].span)
+	})
+}
+
+func serviceName(r *v1_resource.Resource) string {
+	if r == nil {
+		return ""
+	}
+	for _, kv := range r.Attributes {
+		if kv.Key == "service.name" {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+func spanDuration(s *v1.Span) uint64 {
+	if s.EndTimeUnixNano < s.StartTimeUnixNano {
+		return 0
+	}
+	return s.EndTimeUnixNano - s.StartTimeUnixNano
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/opentracing/opentracing-go"
@@ -496,9 +497,11 @@ type SyncIterator struct {
 	readSize   int
 	selectAs   string
 	filter     *InstrumentedPredicate
+	stats      *StatsCollector // Optional, only set if the context has a collector
 
 	// Status
 	span            opentracing.Span
+	duration        time.Duration
 	curr            RowNumber
 	currRowGroup    pq.RowGroup
 	currRowGroupMin RowNumber
@@ -544,6 +547,7 @@ func NewSyncIterator(ctx context.Context, rgs []pq.RowGroup, column int, columnN
 		rgsMin:     rgsMin,
 		rgsMax:     rgsMax,
 		filter:     &InstrumentedPredicate{pred: filter},
+		stats:      statsCollectorFromContext(ctx),
 		curr:       EmptyRowNumber(),
 	}
 }
@@ -557,6 +561,10 @@ func (c *SyncIterator) String() string {
 }
 
 func (c *SyncIterator) Next() (*IteratorResult, error) {
+	if c.stats != nil {
+		defer c.measure(time.Now())
+	}
+
 	rn, v, err := c.next()
 	if err != nil {
 		return nil, err
@@ -570,6 +578,9 @@ func (c *SyncIterator) Next() (*IteratorResult, error) {
 // SeekTo moves this iterator to the next result that is greater than
 // or equal to the given row number (and based on the given definition level)
 func (c *SyncIterator) SeekTo(to RowNumber, definitionLevel int) (*IteratorResult, error) {
+	if c.stats != nil {
+		defer c.measure(time.Now())
+	}
 
 	if c.seekRowGroup(to, definitionLevel) {
 		return nil, nil
@@ -860,6 +871,16 @@ func (c *SyncIterator) Close() {
 	c.span.SetTag("keptPages", c.filter.KeptPages)
 	c.span.SetTag("keptValues", c.filter.KeptValues)
 	c.span.Finish()
+
+	if c.stats != nil {
+		c.stats.add(c.columnName, c.filter, c.duration)
+		c.stats = nil
+	}
+}
+
+// measure adds the time since start to the duration of the iterator.
+func (c *SyncIterator) measure(start time.Time) {
+	c.duration += time.Since(start)
 }
 
 // ColumnIterator asynchronously iterates through the given row groups and column. Applies
@@ -921,6 +942,7 @@ func (c *ColumnIterator) iterate(ctx context.Context, readSize int) {
 		"columnIndex": c.col,
 		"column":      c.colName,
 	})
+	start := time.Now()
 	defer func() {
 		span.SetTag("inspectedColumnChunks", c.filter.InspectedColumnChunks)
 		span.SetTag("inspectedPages", c.filter.InspectedPages)
@@ -929,6 +951,10 @@ func (c *ColumnIterator) iterate(ctx context.Context, readSize int) {
 		span.SetTag("keptPages", c.filter.KeptPages)
 		span.SetTag("keptValues", c.filter.KeptValues)
 		span.Finish()
+
+		if stats := statsCollectorFromContext(ctx); stats != nil {
+			stats.add(c.colName, c.filter, time.Since(start))
+		}
 	}()
 
 	rn := EmptyRowNumber()
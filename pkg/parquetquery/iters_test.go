@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/segmentio/parquet-go"
 	"github.com/stretchr/testify/require"
@@ -193,6 +194,51 @@ func testColumnIteratorPredicate(t *testing.T, makeIter makeTestIterFn) {
 	}
 }
 
+func TestIteratorStats(t *testing.T) {
+	count := 10_000
+	pf := createTestFile(t, count)
+	idx, _ := GetColumnIndexByPath(pf, "A")
+
+	newIters := map[string]func(ctx context.Context) Iterator{
+		"async": func(ctx context.Context) Iterator {
+			return NewColumnIterator(ctx, pf.RowGroups(), idx, "A", 1000, NewIntBetweenPredicate(7001, 7003), "A")
+		},
+		"sync": func(ctx context.Context) Iterator {
+			return NewSyncIterator(ctx, pf.RowGroups(), idx, "A", 1000, NewIntBetweenPredicate(7001, 7003), "A")
+		},
+	}
+
+	for name, newIter := range newIters {
+		t.Run(name, func(t *testing.T) {
+			stats := NewStatsCollector()
+
+			iter := newIter(ContextWithStatsCollector(context.Background(), stats))
+			for {
+				res, err := iter.Next()
+				require.NoError(t, err)
+				if res == nil {
+					break
+				}
+			}
+			iter.Close()
+
+			actual := stats.Stats()
+			require.Len(t, actual, 1)
+			require.Equal(t, "A", actual[0].Column)
+			require.Equal(t, int64(3), actual[0].KeptValues)
+			require.Greater(t, actual[0].InspectedValues, actual[0].KeptValues)
+			require.Greater(t, actual[0].Duration, time.Duration(0))
+
+			// iterators without a collector in their context don't report anything
+			iter = newIter(context.Background())
+			_, err := iter.Next()
+			require.NoError(t, err)
+			iter.Close()
+			require.Len(t, stats.Stats(), 1)
+		})
+	}
+}
+
 func TestColumnIteratorExitEarly(t *testing.T) {
 	type T struct{ A int }
 
@@ -0,0 +1,79 @@
+package parquetquery
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IteratorStats are the statistics of the column iterators of a column.
+type IteratorStats struct {
+	Column                string
+	InspectedColumnChunks int64
+	KeptColumnChunks      int64
+	InspectedPages        int64
+	KeptPages             int64
+	InspectedValues       int64
+	KeptValues            int64
+	// Duration is the time spent reading the column
+	Duration time.Duration
+}
+
+// StatsCollector collects the statistics of the column iterators created with a context containing it,
+// see ContextWithStatsCollector. Iterators report their statistics when they are done.
+type StatsCollector struct {
+	mtx   sync.Mutex
+	stats map[string]*IteratorStats
+}
+
+func NewStatsCollector() *StatsCollector {
+	return &StatsCollector{
+		stats: map[string]*IteratorStats{},
+	}
+}
+
+type statsCollectorKey struct{}
+
+// ContextWithStatsCollector returns a context collecting the statistics of the iterators created with it.
+func ContextWithStatsCollector(ctx context.Context, c *StatsCollector) context.Context {
+	return context.WithValue(ctx, statsCollectorKey{}, c)
+}
+
+func statsCollectorFromContext(ctx context.Context) *StatsCollector {
+	c, _ := ctx.Value(statsCollectorKey{}).(*StatsCollector)
+	return c
+}
+
+func (c *StatsCollector) add(column string, p *InstrumentedPredicate, d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	s, ok := c.stats[column]
+	if !ok {
+		s = &IteratorStats{Column: column}
+		c.stats[column] = s
+	}
+	s.InspectedColumnChunks += p.InspectedColumnChunks
+	s.KeptColumnChunks += p.KeptColumnChunks
+	s.InspectedPages += p.InspectedPages
+	s.KeptPages += p.KeptPages
+	s.InspectedValues += p.InspectedValues
+	s.KeptValues += p.KeptValues
+	s.Duration += d
+}
+
+// Stats returns the statistics collected so far summed by column, sorted by column.
+func (c *StatsCollector) Stats() []IteratorStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	stats := make([]IteratorStats, 0, len(c.stats))
+	for _, s := range c.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Column < stats[j].Column
+	})
+	return stats
+}
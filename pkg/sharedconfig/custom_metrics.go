@@ -0,0 +1,96 @@
+package sharedconfig
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/common/model"
+
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+)
+
+type CustomMetricType string
+
+const (
+	CustomMetricTypeCounter   CustomMetricType = "counter"
+	CustomMetricTypeHistogram CustomMetricType = "histogram"
+)
+
+type CustomMetricSource string
+
+const (
+	// CustomMetricSourceAttribute records the value of a numeric attribute of the span
+	CustomMetricSourceAttribute CustomMetricSource = "attribute"
+	// CustomMetricSourceEvents records the number of events of the span
+	CustomMetricSourceEvents CustomMetricSource = "events"
+)
+
+// CustomMetric is an additional metric family of the span metrics processor. Every span matching the
+// filter policies records a value, spans without a value are skipped.
+type CustomMetric struct {
+	Name   string             `yaml:"name"`
+	Type   CustomMetricType   `yaml:"type"`
+	Source CustomMetricSource `yaml:"source"`
+	// Attribute is the span or resource attribute recorded by the attribute source
+	Attribute string `yaml:"attribute,omitempty"`
+	// EventName restricts the events source to the events with this name
+	EventName string `yaml:"event_name,omitempty"`
+	// HistogramBuckets are the buckets of histograms, in the unit of the recorded value
+	HistogramBuckets []float64 `yaml:"histogram_buckets,omitempty"`
+	// Dimensions are added to the intrinsic dimensions of the span metrics processor
+	Dimensions     []string                    `yaml:"dimensions,omitempty"`
+	FilterPolicies []filterconfig.FilterPolicy `yaml:"filter_policies,omitempty"`
+}
+
+type CustomMetrics []CustomMetric
+
+func (c CustomMetrics) Validate() error {
+	names := map[string]struct{}{}
+	for _, m := range c {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[m.Name]; ok {
+			return fmt.Errorf("custom metric names must be unique: %s", m.Name)
+		}
+		names[m.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (m CustomMetric) Validate() error {
+	if !model.IsValidMetricName(model.LabelValue(m.Name)) {
+		return fmt.Errorf("invalid custom metric name: %q", m.Name)
+	}
+
+	switch m.Type {
+	case CustomMetricTypeCounter:
+	case CustomMetricTypeHistogram:
+		if len(m.HistogramBuckets) == 0 {
+			return fmt.Errorf("custom metric %s of type histogram must have histogram_buckets", m.Name)
+		}
+		if !sort.Float64sAreSorted(m.HistogramBuckets) {
+			return fmt.Errorf("histogram_buckets of custom metric %s must be sorted", m.Name)
+		}
+	default:
+		return fmt.Errorf("invalid type for custom metric %s: %s", m.Name, m.Type)
+	}
+
+	switch m.Source {
+	case CustomMetricSourceAttribute:
+		if m.Attribute == "" {
+			return fmt.Errorf("custom metric %s with source attribute must have an attribute", m.Name)
+		}
+	case CustomMetricSourceEvents:
+	default:
+		return fmt.Errorf("invalid source for custom metric %s: %s", m.Name, m.Source)
+	}
+
+	for _, policy := range m.FilterPolicies {
+		if err := filterconfig.ValidateFilterPolicy(policy); err != nil {
+			return fmt.Errorf("invalid filter policy for custom metric %s: %w", m.Name, err)
+		}
+	}
+
+	return nil
+}
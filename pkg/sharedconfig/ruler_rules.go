@@ -0,0 +1,64 @@
+package sharedconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+// RulerRule is a TraceQL query evaluated periodically by the ruler. The rule matches when the query
+// finds traces in the window ending at the time of the evaluation.
+type RulerRule struct {
+	Name  string `yaml:"name" json:"name"`
+	Query string `yaml:"query" json:"query"`
+	// Window is the time range searched by every evaluation, the ruler default if not set
+	Window model.Duration `yaml:"window,omitempty" json:"window,omitempty"`
+	// Labels are added to the alerts and metrics of the rule
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+type RulerRules []RulerRule
+
+func (r RulerRules) Validate() error {
+	names := map[string]struct{}{}
+	for _, rule := range r {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("ruler rule names must be unique: %s", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (r RulerRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("ruler rule must have a name")
+	}
+
+	expr, err := traceql.Parse(r.Query)
+	if err != nil {
+		return fmt.Errorf("invalid query for ruler rule %s: %w", r.Name, err)
+	}
+	if expr.MetricsPipeline != nil {
+		return fmt.Errorf("invalid query for ruler rule %s: metrics queries are not supported", r.Name)
+	}
+
+	if r.Window < 0 {
+		return fmt.Errorf("window of ruler rule %s must not be negative", r.Name)
+	}
+
+	for name := range r.Labels {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q for ruler rule %s", name, r.Name)
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/pkg/errors"
@@ -78,7 +79,70 @@ func ValidatePolicyMatch(match *PolicyMatch) error {
 				return fmt.Errorf("currently unsupported intrinsic: %s; supported intrinsics: %q", a.Intrinsic, supportedIntrinsics)
 			}
 		}
+
+		if pattern, ok := attr.Value.(string); ok && match.MatchType == Regex {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid regex %q for attribute %s: %w", pattern, attr.Key, err)
+			}
+		}
 	}
 
 	return nil
 }
+
+type IngestionPolicyLevel string
+
+const (
+	IngestionPolicyLevelSpan  IngestionPolicyLevel = "span"
+	IngestionPolicyLevelBatch IngestionPolicyLevel = "batch"
+)
+
+type IngestionPolicyAction string
+
+const (
+	IngestionPolicyActionDrop   IngestionPolicyAction = "drop"
+	IngestionPolicyActionSample IngestionPolicyAction = "sample"
+)
+
+// IngestionPolicy drops or samples the spans matching the include and exclude policies before they
+// are written to the ingesters. A span matches if it matches the include policy and doesn't match the
+// exclude policy. On the batch level the policy applies to all spans of a trace in the same push request
+// if any of them matches. Spans of the trace pushed in other requests are evaluated separately.
+type IngestionPolicy struct {
+	Level      IngestionPolicyLevel  `yaml:"level"`
+	Action     IngestionPolicyAction `yaml:"action"`
+	SampleRate float64               `yaml:"sample_rate"`
+	Include    *PolicyMatch          `yaml:"include"`
+	Exclude    *PolicyMatch          `yaml:"exclude"`
+}
+
+type IngestionPolicies []IngestionPolicy
+
+func (p IngestionPolicies) Validate() error {
+	for _, policy := range p {
+		if err := ValidateIngestionPolicy(policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateIngestionPolicy(policy IngestionPolicy) error {
+	switch policy.Level {
+	case IngestionPolicyLevelSpan, IngestionPolicyLevelBatch:
+	default:
+		return fmt.Errorf("invalid ingestion policy level: %v", policy.Level)
+	}
+
+	switch policy.Action {
+	case IngestionPolicyActionDrop:
+	case IngestionPolicyActionSample:
+		if policy.SampleRate < 0 || policy.SampleRate > 1 {
+			return fmt.Errorf("invalid ingestion policy sample rate: %v; must be between 0 and 1", policy.SampleRate)
+		}
+	default:
+		return fmt.Errorf("invalid ingestion policy action: %v", policy.Action)
+	}
+
+	return ValidateFilterPolicy(FilterPolicy{Include: policy.Include, Exclude: policy.Exclude})
+}
@@ -0,0 +1,176 @@
+package spanfilter
+
+import (
+	"math"
+
+	"github.com/segmentio/fasthash/fnv1a"
+
+	"github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// IngestionFilter applies ingestion policies to the traces of a push request. Batch level policies are
+// evaluated first against all the spans of a trace in the request, the spans of kept batches are then
+// evaluated against the span level policies. In both levels the first matching policy decides.
+type IngestionFilter struct {
+	batchPolicies []*ingestionPolicy
+	spanPolicies  []*ingestionPolicy
+}
+
+type ingestionPolicy struct {
+	filter     *SpanFilter
+	action     config.IngestionPolicyAction
+	sampleRate float64
+}
+
+func NewIngestionFilter(policies config.IngestionPolicies) (*IngestionFilter, error) {
+	f := &IngestionFilter{}
+
+	for _, policy := range policies {
+		if err := config.ValidateIngestionPolicy(policy); err != nil {
+			return nil, err
+		}
+
+		filter, err := NewSpanFilter([]config.FilterPolicy{{Include: policy.Include, Exclude: policy.Exclude}})
+		if err != nil {
+			return nil, err
+		}
+
+		p := &ingestionPolicy{
+			filter:     filter,
+			action:     policy.Action,
+			sampleRate: policy.SampleRate,
+		}
+
+		switch policy.Level {
+		case config.IngestionPolicyLevelBatch:
+			f.batchPolicies = append(f.batchPolicies, p)
+		case config.IngestionPolicyLevelSpan:
+			f.spanPolicies = append(f.spanPolicies, p)
+		}
+	}
+
+	return f, nil
+}
+
+// Apply returns the trace with the spans that are kept by the policies and the number of discarded
+// spans. The passed trace is not modified, if spans are discarded a new trace is returned. If all spans
+// are discarded the returned trace is nil.
+//
+// Sampling decisions are based on a hash of the trace ID and span ID. They are consistent across
+// distributors and push requests.
+func (f *IngestionFilter) Apply(traceID []byte, t *tempopb.Trace) (*tempopb.Trace, int) {
+	traceHash := fnv1a.HashString64(string(traceID))
+
+	for _, p := range f.batchPolicies {
+		if !p.matchesAny(t) {
+			continue
+		}
+		if !p.keep(traceHash) {
+			return nil, countSpans(t)
+		}
+		break
+	}
+
+	if len(f.spanPolicies) == 0 {
+		return t, 0
+	}
+
+	var (
+		discarded int
+		kept      = &tempopb.Trace{Batches: make([]*v1_trace.ResourceSpans, 0, len(t.Batches))}
+	)
+	for _, b := range t.Batches {
+		rs := resourceOrEmpty(b.Resource)
+		keptBatch := &v1_trace.ResourceSpans{
+			Resource:  b.Resource,
+			SchemaUrl: b.SchemaUrl,
+		}
+
+		for _, ss := range b.ScopeSpans {
+			keptSpans := make([]*v1_trace.Span, 0, len(ss.Spans))
+			for _, s := range ss.Spans {
+				if f.keepSpan(traceHash, rs, s) {
+					keptSpans = append(keptSpans, s)
+				} else {
+					discarded++
+				}
+			}
+
+			if len(keptSpans) > 0 {
+				keptBatch.ScopeSpans = append(keptBatch.ScopeSpans, &v1_trace.ScopeSpans{
+					Scope:     ss.Scope,
+					Spans:     keptSpans,
+					SchemaUrl: ss.SchemaUrl,
+				})
+			}
+		}
+
+		if len(keptBatch.ScopeSpans) > 0 {
+			kept.Batches = append(kept.Batches, keptBatch)
+		}
+	}
+
+	switch {
+	case discarded == 0:
+		return t, 0
+	case len(kept.Batches) == 0:
+		return nil, discarded
+	default:
+		return kept, discarded
+	}
+}
+
+func (f *IngestionFilter) keepSpan(traceHash uint64, rs *v1.Resource, s *v1_trace.Span) bool {
+	for _, p := range f.spanPolicies {
+		if p.filter.ApplyFilterPolicy(rs, s) {
+			return p.keep(fnv1a.AddString64(traceHash, string(s.SpanId)))
+		}
+	}
+	return true
+}
+
+// matchesAny returns true if any span of the batch of a trace matches the policy.
+func (p *ingestionPolicy) matchesAny(t *tempopb.Trace) bool {
+	for _, b := range t.Batches {
+		rs := resourceOrEmpty(b.Resource)
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				if p.filter.ApplyFilterPolicy(rs, s) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// keep returns true if the matching span or trace with the given hash is kept.
+func (p *ingestionPolicy) keep(hash uint64) bool {
+	if p.action == config.IngestionPolicyActionDrop {
+		return false
+	}
+	if p.sampleRate >= 1 {
+		return true
+	}
+	return hash < uint64(p.sampleRate*math.MaxUint64)
+}
+
+func countSpans(t *tempopb.Trace) int {
+	count := 0
+	for _, b := range t.Batches {
+		for _, ss := range b.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
+
+func resourceOrEmpty(rs *v1.Resource) *v1.Resource {
+	if rs == nil {
+		return &v1.Resource{}
+	}
+	return rs
+}
@@ -0,0 +1,178 @@
+package spanfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestIngestionFilter_NewIngestionFilter(t *testing.T) {
+	_, err := NewIngestionFilter(config.IngestionPolicies{{Level: "batch", Action: config.IngestionPolicyActionDrop}})
+	require.Error(t, err)
+
+	_, err = NewIngestionFilter(config.IngestionPolicies{{Level: config.IngestionPolicyLevelSpan, Action: "keep"}})
+	require.Error(t, err)
+
+	_, err = NewIngestionFilter(config.IngestionPolicies{{Level: config.IngestionPolicyLevelSpan, Action: config.IngestionPolicyActionSample, SampleRate: 1.5}})
+	require.Error(t, err)
+
+	// policies need an include or exclude
+	_, err = NewIngestionFilter(config.IngestionPolicies{{Level: config.IngestionPolicyLevelSpan, Action: config.IngestionPolicyActionDrop}})
+	require.Error(t, err)
+
+	f, err := NewIngestionFilter(nil)
+	require.NoError(t, err)
+	tr := makeIngestionTrace("svc", "GET /health", "GET /api")
+	kept, discarded := f.Apply(test.ValidTraceID(nil), tr)
+	require.Equal(t, 0, discarded)
+	require.Same(t, tr, kept)
+}
+
+func TestIngestionFilter_Apply(t *testing.T) {
+	health := &config.PolicyMatch{
+		MatchType:  config.Strict,
+		Attributes: []config.MatchPolicyAttribute{{Key: "name", Value: "GET /health"}},
+	}
+	noisy := &config.PolicyMatch{
+		MatchType:  config.Strict,
+		Attributes: []config.MatchPolicyAttribute{{Key: "resource.service.name", Value: "noisy"}},
+	}
+
+	testCases := []struct {
+		name              string
+		policies          config.IngestionPolicies
+		trace             *tempopb.Trace
+		expectedSpans     []string
+		expectedDiscarded int
+	}{
+		{
+			name:              "drop span",
+			policies:          config.IngestionPolicies{{Level: config.IngestionPolicyLevelSpan, Action: config.IngestionPolicyActionDrop, Include: health}},
+			trace:             makeIngestionTrace("svc", "GET /health", "GET /api"),
+			expectedSpans:     []string{"GET /api"},
+			expectedDiscarded: 1,
+		},
+		{
+			name:              "drop span with exclude",
+			policies:          config.IngestionPolicies{{Level: config.IngestionPolicyLevelSpan, Action: config.IngestionPolicyActionDrop, Exclude: health}},
+			trace:             makeIngestionTrace("svc", "GET /health", "GET /api"),
+			expectedSpans:     []string{"GET /health"},
+			expectedDiscarded: 1,
+		},
+		{
+			name:              "drop all spans",
+			policies:          config.IngestionPolicies{{Level: config.IngestionPolicyLevelSpan, Action: config.IngestionPolicyActionDrop, Include: noisy}},
+			trace:             makeIngestionTrace("noisy", "GET /health", "GET /api"),
+			expectedDiscarded: 2,
+		},
+		{
+			name:              "drop batch",
+			policies:          config.IngestionPolicies{{Level: config.IngestionPolicyLevelBatch, Action: config.IngestionPolicyActionDrop, Include: health}},
+			trace:             makeIngestionTrace("svc", "GET /health", "GET /api"),
+			expectedDiscarded: 2,
+		},
+		{
+			name:          "batch doesn't match",
+			policies:      config.IngestionPolicies{{Level: config.IngestionPolicyLevelBatch, Action: config.IngestionPolicyActionDrop, Include: noisy}},
+			trace:         makeIngestionTrace("svc", "GET /health", "GET /api"),
+			expectedSpans: []string{"GET /health", "GET /api"},
+		},
+		{
+			name: "first matching policy decides",
+			policies: config.IngestionPolicies{
+				{Level: config.IngestionPolicyLevelSpan, Action: config.IngestionPolicyActionSample, SampleRate: 1, Include: health},
+				{Level: config.IngestionPolicyLevelSpan, Action: config.IngestionPolicyActionDrop, Include: noisy},
+			},
+			trace:             makeIngestionTrace("noisy", "GET /health", "GET /api"),
+			expectedSpans:     []string{"GET /health"},
+			expectedDiscarded: 1,
+		},
+		{
+			name:              "sample rate 0",
+			policies:          config.IngestionPolicies{{Level: config.IngestionPolicyLevelBatch, Action: config.IngestionPolicyActionSample, SampleRate: 0, Include: noisy}},
+			trace:             makeIngestionTrace("noisy", "GET /health", "GET /api"),
+			expectedDiscarded: 2,
+		},
+		{
+			name:          "sample rate 1",
+			policies:      config.IngestionPolicies{{Level: config.IngestionPolicyLevelBatch, Action: config.IngestionPolicyActionSample, SampleRate: 1, Include: noisy}},
+			trace:         makeIngestionTrace("noisy", "GET /health", "GET /api"),
+			expectedSpans: []string{"GET /health", "GET /api"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewIngestionFilter(tc.policies)
+			require.NoError(t, err)
+
+			before := tc.trace.String()
+			kept, discarded := f.Apply(test.ValidTraceID(nil), tc.trace)
+			require.Equal(t, tc.expectedDiscarded, discarded)
+			require.Equal(t, before, tc.trace.String(), "the passed trace must not be modified")
+
+			if len(tc.expectedSpans) == 0 {
+				require.Nil(t, kept)
+				return
+			}
+
+			var names []string
+			for _, b := range kept.Batches {
+				for _, ss := range b.ScopeSpans {
+					for _, s := range ss.Spans {
+						names = append(names, s.Name)
+					}
+				}
+			}
+			assert.Equal(t, tc.expectedSpans, names)
+		})
+	}
+}
+
+func TestIngestionFilter_Sample(t *testing.T) {
+	f, err := NewIngestionFilter(config.IngestionPolicies{{
+		Level:      config.IngestionPolicyLevelBatch,
+		Action:     config.IngestionPolicyActionSample,
+		SampleRate: 0.25,
+		Include:    &config.PolicyMatch{MatchType: config.Regex, Attributes: []config.MatchPolicyAttribute{{Key: "name", Value: ".*"}}},
+	}})
+	require.NoError(t, err)
+
+	const traces = 10000
+	kept := 0
+	for i := 0; i < traces; i++ {
+		id := test.ValidTraceID(nil)
+		k, _ := f.Apply(id, makeIngestionTrace("svc", "GET /api"))
+		if k != nil {
+			kept++
+		}
+
+		// the decision is consistent for a trace
+		k2, _ := f.Apply(id, makeIngestionTrace("svc", "GET /api"))
+		require.Equal(t, k == nil, k2 == nil)
+	}
+
+	require.InDelta(t, 0.25, float64(kept)/traces, 0.03)
+}
+
+func makeIngestionTrace(service string, names ...string) *tempopb.Trace {
+	var spans []*trace_v1.Span
+	for i, name := range names {
+		spans = append(spans, &trace_v1.Span{SpanId: []byte{byte(i + 1)}, Name: name})
+	}
+
+	return &tempopb.Trace{Batches: []*trace_v1.ResourceSpans{{
+		Resource: &v1.Resource{Attributes: []*common_v1.KeyValue{
+			{Key: "service.name", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: service}}},
+		}},
+		ScopeSpans: []*trace_v1.ScopeSpans{{Spans: spans}},
+	}}}
+}
@@ -1,6 +1,7 @@
 package spanfilter
 
 import (
+	"fmt"
 	"reflect"
 	"regexp"
 
@@ -38,9 +39,12 @@ func NewSpanFilter(filterPolicies []config.FilterPolicy) (*SpanFilter, error) {
 			return nil, err
 		}
 
-		p := &filterPolicy{
-			Include: getSplitPolicy(policy.Include),
-			Exclude: getSplitPolicy(policy.Exclude),
+		p := &filterPolicy{}
+		if p.Include, err = getSplitPolicy(policy.Include); err != nil {
+			return nil, err
+		}
+		if p.Exclude, err = getSplitPolicy(policy.Exclude); err != nil {
+			return nil, err
 		}
 
 		if p.Include != nil || p.Exclude != nil {
@@ -77,18 +81,42 @@ func (f *SpanFilter) ApplyFilterPolicy(rs *v1.Resource, span *v1_trace.Span) boo
 	return true
 }
 
-func stringMatch(matchType config.MatchType, s, pattern string) bool {
+// stringMatch returns true if s matches the pattern of the policy. The patterns of regex policies must be compiled
+// by compilePolicyMatch.
+func stringMatch(matchType config.MatchType, s string, pattern interface{}) bool {
 	switch matchType {
 	case config.Strict:
-		return s == pattern
+		p, ok := pattern.(string)
+		return ok && s == p
 	case config.Regex:
-		re := regexp.MustCompile(pattern)
-		return re.MatchString(s)
+		re, ok := pattern.(*regexp.Regexp)
+		return ok && re.MatchString(s)
 	default:
 		return false
 	}
 }
 
+// compilePolicyMatch compiles the string values of a regex policy in place. Other values and strict policies are
+// left unchanged.
+func compilePolicyMatch(policy *config.PolicyMatch) error {
+	if policy == nil || policy.MatchType != config.Regex {
+		return nil
+	}
+
+	for i, pa := range policy.Attributes {
+		pattern, ok := pa.Value.(string)
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q for attribute %s: %w", pattern, pa.Key, err)
+		}
+		policy.Attributes[i].Value = re
+	}
+	return nil
+}
+
 // policyMatch returns true when the resource attribtues and span attributes match the policy.
 func policyMatch(policy *splitPolicy, rs *v1.Resource, span *v1_trace.Span) bool {
 	return policyMatchAttrs(policy.ResourceMatch, rs.Attributes) &&
@@ -111,7 +139,7 @@ func policyMatchIntrinsicAttrs(policy *config.PolicyMatch, span *v1_trace.Span)
 		// case traceql.IntrinsicChildCount:
 		// case traceql.IntrinsicParent:
 		case traceql.IntrinsicName:
-			if !stringMatch(policy.MatchType, span.GetName(), pa.Value.(string)) {
+			if !stringMatch(policy.MatchType, span.GetName(), pa.Value) {
 				return false
 			}
 			matches++
@@ -124,7 +152,7 @@ func policyMatchIntrinsicAttrs(policy *config.PolicyMatch, span *v1_trace.Span)
 					return false
 				}
 			default:
-				if !stringMatch(policy.MatchType, span.GetStatus().GetCode().String(), pa.Value.(string)) {
+				if !stringMatch(policy.MatchType, span.GetStatus().GetCode().String(), pa.Value) {
 					return false
 				}
 			}
@@ -138,7 +166,7 @@ func policyMatchIntrinsicAttrs(policy *config.PolicyMatch, span *v1_trace.Span)
 					return false
 				}
 			default:
-				if !stringMatch(policy.MatchType, span.GetKind().String(), pa.Value.(string)) {
+				if !stringMatch(policy.MatchType, span.GetKind().String(), pa.Value) {
 					return false
 				}
 
@@ -167,11 +195,7 @@ func policyMatchAttrs(policy *config.PolicyMatch, attrs []*v1_common.KeyValue) b
 				// For each type of value, check if the policy attribute value matches the span attribute value.
 				switch v.Value.(type) {
 				case *v1_common.AnyValue_StringValue:
-					if pAttrValueType != "string" {
-						return false
-					}
-
-					if !stringMatch(policy.MatchType, v.GetStringValue(), pa.Value.(string)) {
+					if !stringMatch(policy.MatchType, v.GetStringValue(), pa.Value) {
 						return false
 					}
 					matches++
@@ -210,9 +234,10 @@ func policyMatchAttrs(policy *config.PolicyMatch, attrs []*v1_common.KeyValue) b
 	return len(policy.Attributes) == matches
 }
 
-func getSplitPolicy(policy *config.PolicyMatch) *splitPolicy {
+// getSplitPolicy splits the policy by the scope of its attributes and compiles the patterns of regex policies.
+func getSplitPolicy(policy *config.PolicyMatch) (*splitPolicy, error) {
 	if policy == nil {
-		return nil
+		return nil, nil
 	}
 
 	// A policy to match against the resource attributes
@@ -260,9 +285,15 @@ func getSplitPolicy(policy *config.PolicyMatch) *splitPolicy {
 		}
 	}
 
+	for _, p := range []*config.PolicyMatch{resourcePolicy, spanPolicy, intrinsicPolicy} {
+		if err := compilePolicyMatch(p); err != nil {
+			return nil, err
+		}
+	}
+
 	return &splitPolicy{
 		ResourceMatch:  resourcePolicy,
 		SpanMatch:      spanPolicy,
 		IntrinsicMatch: intrinsicPolicy,
-	}
+	}, nil
 }
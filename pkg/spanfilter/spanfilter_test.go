@@ -243,7 +243,9 @@ func TestSpanFilter_policyMatch(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.testName, func(t *testing.T) {
-			r := policyMatch(getSplitPolicy(tc.policy), tc.resource, tc.span)
+			split, err := getSplitPolicy(tc.policy)
+			require.NoError(t, err)
+			r := policyMatch(split, tc.resource, tc.span)
 			require.Equal(t, tc.expect, r)
 		})
 	}
@@ -444,6 +446,7 @@ func TestSpanFilter_policyMatchIntrinsicAttrs(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, compilePolicyMatch(tc.policy))
 			r := policyMatchIntrinsicAttrs(tc.policy, tc.span)
 			require.Equal(t, tc.expect, r)
 		})
@@ -819,6 +822,7 @@ func TestSpanFilter_policyMatchAttrs(t *testing.T) {
 	}
 
 	for _, tc := range cases {
+		require.NoError(t, compilePolicyMatch(tc.policy))
 		r := policyMatchAttrs(tc.policy, tc.attrs)
 		require.Equal(t, tc.expect, r)
 	}
@@ -1077,6 +1081,16 @@ func TestSpanFilter_stringMatch(t *testing.T) {
 	}
 }
 
+func TestSpanFilter_invalidRegex(t *testing.T) {
+	_, err := NewSpanFilter([]config.FilterPolicy{{
+		Include: &config.PolicyMatch{
+			MatchType:  config.Regex,
+			Attributes: []config.MatchPolicyAttribute{{Key: "span.foo", Value: "("}},
+		},
+	}})
+	require.Error(t, err)
+}
+
 func TestSpanFilter_getSplitPolicy(t *testing.T) {
 	cases := []struct {
 		policy *config.PolicyMatch
@@ -1133,7 +1147,8 @@ func TestSpanFilter_getSplitPolicy(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			s := getSplitPolicy(tc.policy)
+			s, err := getSplitPolicy(tc.policy)
+			require.NoError(t, err)
 
 			require.NotNil(t, s)
 			require.NotNil(t, s.IntrinsicMatch)
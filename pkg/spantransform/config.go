@@ -0,0 +1,103 @@
+package spantransform
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type Action string
+
+const (
+	// ActionMask replaces the parts of string values matching the pattern with the replacement
+	ActionMask Action = "mask"
+	// ActionHash replaces string values with their SHA-256 hash
+	ActionHash Action = "hash"
+	// ActionDrop removes the attributes
+	ActionDrop Action = "drop"
+	// ActionTruncate shortens string values to the max length
+	ActionTruncate Action = "truncate"
+)
+
+type Scope string
+
+const (
+	ScopeAll      Scope = ""
+	ScopeResource Scope = "resource"
+	ScopeSpan     Scope = "span"
+	ScopeEvent    Scope = "event"
+	ScopeLink     Scope = "link"
+)
+
+const defaultMaskReplacement = "***"
+
+// Rule transforms the attributes whose keys match one of the key patterns. Key patterns are
+// anchored regular expressions.
+type Rule struct {
+	Name        string   `yaml:"name" json:"name"`
+	Scope       Scope    `yaml:"scope,omitempty" json:"scope,omitempty"`
+	Keys        []string `yaml:"keys" json:"keys"`
+	Action      Action   `yaml:"action" json:"action"`
+	Pattern     string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Replacement *string  `yaml:"replacement,omitempty" json:"replacement,omitempty"`
+	MaxLength   int      `yaml:"max_length,omitempty" json:"max_length,omitempty"`
+}
+
+type Rules []Rule
+
+func (r Rules) Validate() error {
+	names := map[string]struct{}{}
+	for _, rule := range r {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("rule names must be unique: %s", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule must have a name")
+	}
+
+	switch r.Scope {
+	case ScopeAll, ScopeResource, ScopeSpan, ScopeEvent, ScopeLink:
+	default:
+		return fmt.Errorf("invalid scope for rule %s: %s", r.Name, r.Scope)
+	}
+
+	if len(r.Keys) == 0 {
+		return fmt.Errorf("rule %s must have at least one key", r.Name)
+	}
+	for _, k := range r.Keys {
+		if _, err := compileKeyPattern(k); err != nil {
+			return fmt.Errorf("invalid key pattern for rule %s: %w", r.Name, err)
+		}
+	}
+
+	switch r.Action {
+	case ActionMask:
+		if r.Pattern == "" {
+			return fmt.Errorf("rule %s with action mask must have a pattern", r.Name)
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern for rule %s: %w", r.Name, err)
+		}
+	case ActionTruncate:
+		if r.MaxLength <= 0 {
+			return fmt.Errorf("rule %s with action truncate must have a positive max_length", r.Name)
+		}
+	case ActionHash, ActionDrop:
+	default:
+		return fmt.Errorf("invalid action for rule %s: %s", r.Name, r.Action)
+	}
+
+	return nil
+}
+
+func compileKeyPattern(k string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + k + ")$")
+}
@@ -0,0 +1,163 @@
+package spantransform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"unicode/utf8"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// Transformer applies transform rules to the attributes of spans. Rules are applied in order, a rule
+// sees the attributes as transformed by the previous rules.
+type Transformer struct {
+	rules []*rule
+}
+
+type rule struct {
+	Rule
+	keys        []*regexp.Regexp
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func New(rules Rules) (*Transformer, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	t := &Transformer{rules: make([]*rule, 0, len(rules))}
+	for _, r := range rules {
+		compiled := &rule{
+			Rule:        r,
+			replacement: defaultMaskReplacement,
+		}
+		for _, k := range r.Keys {
+			re, _ := compileKeyPattern(k) // validated above
+			compiled.keys = append(compiled.keys, re)
+		}
+		if r.Pattern != "" {
+			compiled.pattern = regexp.MustCompile(r.Pattern)
+		}
+		if r.Replacement != nil {
+			compiled.replacement = *r.Replacement
+		}
+		t.rules = append(t.rules, compiled)
+	}
+
+	return t, nil
+}
+
+// Transform applies the rules to the attributes of the batches in place. It returns the number of
+// attributes touched by each rule keyed by the rule name. Rules that didn't touch any attribute are
+// omitted.
+func (t *Transformer) Transform(batches []*v1.ResourceSpans) map[string]int {
+	touched := map[string]int{}
+
+	for _, b := range batches {
+		if b.Resource != nil {
+			b.Resource.Attributes = t.apply(ScopeResource, b.Resource.Attributes, touched)
+		}
+
+		for _, ss := range b.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.Attributes = t.apply(ScopeSpan, s.Attributes, touched)
+				for _, e := range s.Events {
+					e.Attributes = t.apply(ScopeEvent, e.Attributes, touched)
+				}
+				for _, l := range s.Links {
+					l.Attributes = t.apply(ScopeLink, l.Attributes, touched)
+				}
+			}
+		}
+	}
+
+	return touched
+}
+
+func (t *Transformer) apply(scope Scope, attrs []*v1_common.KeyValue, touched map[string]int) []*v1_common.KeyValue {
+	for _, r := range t.rules {
+		if r.Scope != ScopeAll && r.Scope != scope {
+			continue
+		}
+
+		kept := attrs[:0]
+		for _, kv := range attrs {
+			if !r.matchesKey(kv.Key) {
+				kept = append(kept, kv)
+				continue
+			}
+
+			if r.Action == ActionDrop {
+				touched[r.Name]++
+				continue
+			}
+
+			if r.transformValue(kv) {
+				touched[r.Name]++
+			}
+			kept = append(kept, kv)
+		}
+
+		// clear the dropped attributes at the end of the backing array
+		for i := len(kept); i < len(attrs); i++ {
+			attrs[i] = nil
+		}
+		attrs = kept
+	}
+
+	return attrs
+}
+
+func (r *rule) matchesKey(key string) bool {
+	for _, k := range r.keys {
+		if k.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// transformValue applies the rule to string values and returns true if the value was changed.
+func (r *rule) transformValue(kv *v1_common.KeyValue) bool {
+	sv, ok := kv.GetValue().GetValue().(*v1_common.AnyValue_StringValue)
+	if !ok {
+		return false
+	}
+
+	var transformed string
+	switch r.Action {
+	case ActionMask:
+		transformed = r.pattern.ReplaceAllString(sv.StringValue, r.replacement)
+	case ActionHash:
+		h := sha256.Sum256([]byte(sv.StringValue))
+		transformed = hex.EncodeToString(h[:])
+	case ActionTruncate:
+		transformed = truncate(sv.StringValue, r.MaxLength)
+	default:
+		return false
+	}
+
+	if transformed == sv.StringValue {
+		return false
+	}
+
+	// replace the value instead of modifying it, it might be shared with other attributes
+	kv.Value = &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: transformed}}
+	return true
+}
+
+// truncate shortens s to at most max bytes without splitting a multi-byte character.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	n := max
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,129 @@
+package spantransform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestRulesValidate(t *testing.T) {
+	replacement := "<redacted>"
+
+	valid := Rules{
+		{Name: "mask", Keys: []string{"http.url"}, Action: ActionMask, Pattern: "token=[^&]*", Replacement: &replacement},
+		{Name: "hash", Scope: ScopeResource, Keys: []string{"user.email"}, Action: ActionHash},
+		{Name: "drop", Keys: []string{"secret\\..*"}, Action: ActionDrop},
+		{Name: "truncate", Scope: ScopeSpan, Keys: []string{"db.statement"}, Action: ActionTruncate, MaxLength: 10},
+	}
+	require.NoError(t, valid.Validate())
+
+	invalid := []Rules{
+		{{Keys: []string{"foo"}, Action: ActionDrop}},
+		{{Name: "a", Keys: []string{"foo"}, Action: ActionDrop}, {Name: "a", Keys: []string{"bar"}, Action: ActionDrop}},
+		{{Name: "a", Scope: "trace", Keys: []string{"foo"}, Action: ActionDrop}},
+		{{Name: "a", Action: ActionDrop}},
+		{{Name: "a", Keys: []string{"("}, Action: ActionDrop}},
+		{{Name: "a", Keys: []string{"foo"}, Action: "encrypt"}},
+		{{Name: "a", Keys: []string{"foo"}, Action: ActionMask}},
+		{{Name: "a", Keys: []string{"foo"}, Action: ActionMask, Pattern: "("}},
+		{{Name: "a", Keys: []string{"foo"}, Action: ActionTruncate}},
+	}
+	for _, rules := range invalid {
+		require.Error(t, rules.Validate(), rules)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	batches := []*v1.ResourceSpans{{
+		Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{
+			stringKV("service.name", "svc"),
+			stringKV("user.email", "jane@example.com"),
+		}},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{{
+			Attributes: []*v1_common.KeyValue{
+				stringKV("http.url", "https://example.com/?token=abc&page=1"),
+				stringKV("db.statement", "SELECT * FROM users WHERE name = 'jane'"),
+				stringKV("secret.key", "hunter2"),
+				stringKV("secret.other", "hunter3"),
+				{Key: "http.status_code", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: 200}}},
+			},
+			Events: []*v1.Span_Event{{Attributes: []*v1_common.KeyValue{
+				stringKV("db.statement", "SELECT 1"),
+				stringKV("secret.key", "hunter2"),
+			}}},
+		}}}},
+	}}
+
+	replacement := "token=<redacted>"
+	transformer, err := New(Rules{
+		{Name: "mask-token", Keys: []string{"http\\.url"}, Action: ActionMask, Pattern: "token=[^&]*", Replacement: &replacement},
+		{Name: "hash-email", Scope: ScopeResource, Keys: []string{"user.email"}, Action: ActionHash},
+		{Name: "drop-secrets", Keys: []string{"secret\\..*"}, Action: ActionDrop},
+		{Name: "truncate-statements", Scope: ScopeSpan, Keys: []string{"db.statement"}, Action: ActionTruncate, MaxLength: 8},
+		{Name: "unused", Keys: []string{"http.status_code"}, Action: ActionHash},
+	})
+	require.NoError(t, err)
+
+	touched := transformer.Transform(batches)
+	assert.Equal(t, map[string]int{
+		"mask-token":          1,
+		"hash-email":          1,
+		"drop-secrets":        3,
+		"truncate-statements": 1,
+	}, touched)
+
+	emailHash := sha256.Sum256([]byte("jane@example.com"))
+	assert.Equal(t, []*v1_common.KeyValue{
+		stringKV("service.name", "svc"),
+		stringKV("user.email", hex.EncodeToString(emailHash[:])),
+	}, batches[0].Resource.Attributes)
+
+	span := batches[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, []*v1_common.KeyValue{
+		stringKV("http.url", "https://example.com/?token=<redacted>&page=1"),
+		stringKV("db.statement", "SELECT *"),
+		{Key: "http.status_code", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: 200}}},
+	}, span.Attributes)
+
+	// the truncate rule only applies to span attributes
+	assert.Equal(t, []*v1_common.KeyValue{
+		stringKV("db.statement", "SELECT 1"),
+	}, span.Events[0].Attributes)
+}
+
+func TestTransformSharedValue(t *testing.T) {
+	shared := &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "secret"}}
+	batches := []*v1.ResourceSpans{{
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{{
+			Attributes: []*v1_common.KeyValue{{Key: "password", Value: shared}, {Key: "other", Value: shared}},
+		}}}},
+	}}
+
+	transformer, err := New(Rules{{Name: "mask", Keys: []string{"password"}, Action: ActionMask, Pattern: ".+"}})
+	require.NoError(t, err)
+	transformer.Transform(batches)
+
+	attrs := batches[0].ScopeSpans[0].Spans[0].Attributes
+	assert.Equal(t, "***", attrs[0].Value.GetStringValue())
+	assert.Equal(t, "secret", attrs[1].Value.GetStringValue())
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 5))
+	assert.Equal(t, "ab", truncate("abc", 2))
+	// don't split the 2 byte ü
+	assert.Equal(t, "m", truncate("müller", 2))
+	assert.Equal(t, "mü", truncate("müller", 3))
+	assert.Equal(t, "", truncate("üb", 1))
+}
+
+func stringKV(k, v string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+}
@@ -187,6 +187,87 @@ func (m *TraceByIDMetrics) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_TraceByIDMetrics proto.InternalMessageInfo
 
+// FlushedBlocksRequest requests the blocks flushed to the backend and still held by an ingester
+type FlushedBlocksRequest struct {
+}
+
+func (m *FlushedBlocksRequest) Reset()         { *m = FlushedBlocksRequest{} }
+func (m *FlushedBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*FlushedBlocksRequest) ProtoMessage()    {}
+func (*FlushedBlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{3}
+}
+func (m *FlushedBlocksRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FlushedBlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FlushedBlocksRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FlushedBlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FlushedBlocksRequest.Merge(m, src)
+}
+func (m *FlushedBlocksRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *FlushedBlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FlushedBlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FlushedBlocksRequest proto.InternalMessageInfo
+
+type FlushedBlocksResponse struct {
+	Metas [][]byte `protobuf:"bytes,1,rep,name=metas,proto3" json:"metas,omitempty"`
+}
+
+func (m *FlushedBlocksResponse) Reset()         { *m = FlushedBlocksResponse{} }
+func (m *FlushedBlocksResponse) String() string { return proto.CompactTextString(m) }
+func (*FlushedBlocksResponse) ProtoMessage()    {}
+func (*FlushedBlocksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{4}
+}
+func (m *FlushedBlocksResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FlushedBlocksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FlushedBlocksResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FlushedBlocksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FlushedBlocksResponse.Merge(m, src)
+}
+func (m *FlushedBlocksResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *FlushedBlocksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FlushedBlocksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FlushedBlocksResponse proto.InternalMessageInfo
+
+func (m *FlushedBlocksResponse) GetMetas() [][]byte {
+	if m != nil {
+		return m.Metas
+	}
+	return nil
+}
+
 // SearchRequest takes no block parameters and implies a "recent traces" search
 type SearchRequest struct {
 	// case insensitive partial match
@@ -199,13 +280,17 @@ type SearchRequest struct {
 	// TraceQL query
 	Query           string `protobuf:"bytes,8,opt,name=Query,proto3" json:"Query,omitempty"`
 	SpansPerSpanSet uint32 `protobuf:"varint,9,opt,name=SpansPerSpanSet,proto3" json:"SpansPerSpanSet,omitempty"`
+	// Explain returns how the query is executed along with the results
+	Explain bool `protobuf:"varint,10,opt,name=Explain,proto3" json:"Explain,omitempty"`
+	// Name of a saved query of the tenant to run instead of Query
+	Saved string `protobuf:"bytes,11,opt,name=Saved,proto3" json:"Saved,omitempty"`
 }
 
 func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
 func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchRequest) ProtoMessage()    {}
 func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{3}
+	return fileDescriptor_f22805646f4f62b6, []int{5}
 }
 func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -290,27 +375,42 @@ func (m *SearchRequest) GetSpansPerSpanSet() uint32 {
 	return 0
 }
 
+func (m *SearchRequest) GetExplain() bool {
+	if m != nil {
+		return m.Explain
+	}
+	return false
+}
+
+func (m *SearchRequest) GetSaved() string {
+	if m != nil {
+		return m.Saved
+	}
+	return ""
+}
+
 // SearchBlockRequest takes SearchRequest parameters as well as all information necessary
 // to search a block in the backend.
 type SearchBlockRequest struct {
-	SearchReq     *SearchRequest `protobuf:"bytes,1,opt,name=searchReq,proto3" json:"searchReq,omitempty"`
-	BlockID       string         `protobuf:"bytes,2,opt,name=blockID,proto3" json:"blockID,omitempty"`
-	StartPage     uint32         `protobuf:"varint,3,opt,name=startPage,proto3" json:"startPage,omitempty"`
-	PagesToSearch uint32         `protobuf:"varint,4,opt,name=pagesToSearch,proto3" json:"pagesToSearch,omitempty"`
-	Encoding      string         `protobuf:"bytes,5,opt,name=encoding,proto3" json:"encoding,omitempty"`
-	IndexPageSize uint32         `protobuf:"varint,6,opt,name=indexPageSize,proto3" json:"indexPageSize,omitempty"`
-	TotalRecords  uint32         `protobuf:"varint,7,opt,name=totalRecords,proto3" json:"totalRecords,omitempty"`
-	DataEncoding  string         `protobuf:"bytes,8,opt,name=dataEncoding,proto3" json:"dataEncoding,omitempty"`
-	Version       string         `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"`
-	Size_         uint64         `protobuf:"varint,10,opt,name=size,proto3" json:"size,omitempty"`
-	FooterSize    uint32         `protobuf:"varint,11,opt,name=footerSize,proto3" json:"footerSize,omitempty"`
+	SearchReq        *SearchRequest     `protobuf:"bytes,1,opt,name=searchReq,proto3" json:"searchReq,omitempty"`
+	BlockID          string             `protobuf:"bytes,2,opt,name=blockID,proto3" json:"blockID,omitempty"`
+	StartPage        uint32             `protobuf:"varint,3,opt,name=startPage,proto3" json:"startPage,omitempty"`
+	PagesToSearch    uint32             `protobuf:"varint,4,opt,name=pagesToSearch,proto3" json:"pagesToSearch,omitempty"`
+	Encoding         string             `protobuf:"bytes,5,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	IndexPageSize    uint32             `protobuf:"varint,6,opt,name=indexPageSize,proto3" json:"indexPageSize,omitempty"`
+	TotalRecords     uint32             `protobuf:"varint,7,opt,name=totalRecords,proto3" json:"totalRecords,omitempty"`
+	DataEncoding     string             `protobuf:"bytes,8,opt,name=dataEncoding,proto3" json:"dataEncoding,omitempty"`
+	Version          string             `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"`
+	Size_            uint64             `protobuf:"varint,10,opt,name=size,proto3" json:"size,omitempty"`
+	FooterSize       uint32             `protobuf:"varint,11,opt,name=footerSize,proto3" json:"footerSize,omitempty"`
+	DedicatedColumns []*DedicatedColumn `protobuf:"bytes,12,rep,name=dedicatedColumns,proto3" json:"dedicatedColumns,omitempty"`
 }
 
 func (m *SearchBlockRequest) Reset()         { *m = SearchBlockRequest{} }
 func (m *SearchBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchBlockRequest) ProtoMessage()    {}
 func (*SearchBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{4}
+	return fileDescriptor_f22805646f4f62b6, []int{6}
 }
 func (m *SearchBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -416,16 +516,86 @@ func (m *SearchBlockRequest) GetFooterSize() uint32 {
 	return 0
 }
 
+func (m *SearchBlockRequest) GetDedicatedColumns() []*DedicatedColumn {
+	if m != nil {
+		return m.DedicatedColumns
+	}
+	return nil
+}
+
+// DedicatedColumn is an attribute stored in a dedicated column of a block
+type DedicatedColumn struct {
+	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type  string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (m *DedicatedColumn) Reset()         { *m = DedicatedColumn{} }
+func (m *DedicatedColumn) String() string { return proto.CompactTextString(m) }
+func (*DedicatedColumn) ProtoMessage()    {}
+func (*DedicatedColumn) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{7}
+}
+func (m *DedicatedColumn) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DedicatedColumn) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DedicatedColumn.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DedicatedColumn) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DedicatedColumn.Merge(m, src)
+}
+func (m *DedicatedColumn) XXX_Size() int {
+	return m.Size()
+}
+func (m *DedicatedColumn) XXX_DiscardUnknown() {
+	xxx_messageInfo_DedicatedColumn.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DedicatedColumn proto.InternalMessageInfo
+
+func (m *DedicatedColumn) GetScope() string {
+	if m != nil {
+		return m.Scope
+	}
+	return ""
+}
+
+func (m *DedicatedColumn) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DedicatedColumn) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
 type SearchResponse struct {
 	Traces  []*TraceSearchMetadata `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
 	Metrics *SearchMetrics         `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// only set if explain is requested
+	Explain *SearchExplain `protobuf:"bytes,3,opt,name=explain,proto3" json:"explain,omitempty"`
 }
 
 func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
 func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchResponse) ProtoMessage()    {}
 func (*SearchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{5}
+	return fileDescriptor_f22805646f4f62b6, []int{8}
 }
 func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -468,28 +638,43 @@ func (m *SearchResponse) GetMetrics() *SearchMetrics {
 	return nil
 }
 
-type TraceSearchMetadata struct {
-	TraceID           string     `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
-	RootServiceName   string     `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
-	RootTraceName     string     `protobuf:"bytes,3,opt,name=rootTraceName,proto3" json:"rootTraceName,omitempty"`
-	StartTimeUnixNano uint64     `protobuf:"varint,4,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
-	DurationMs        uint32     `protobuf:"varint,5,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
-	SpanSet           *SpanSet   `protobuf:"bytes,6,opt,name=spanSet,proto3" json:"spanSet,omitempty"`
-	SpanSets          []*SpanSet `protobuf:"bytes,7,rep,name=spanSets,proto3" json:"spanSets,omitempty"`
+func (m *SearchResponse) GetExplain() *SearchExplain {
+	if m != nil {
+		return m.Explain
+	}
+	return nil
 }
 
-func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
-func (m *TraceSearchMetadata) String() string { return proto.CompactTextString(m) }
-func (*TraceSearchMetadata) ProtoMessage()    {}
-func (*TraceSearchMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{6}
+// SearchExplain describes how a TraceQL search is executed.
+type SearchExplain struct {
+	// stages of the pipeline of the parsed query
+	Pipeline []string `protobuf:"bytes,1,rep,name=pipeline,proto3" json:"pipeline,omitempty"`
+	// conditions fetched by the storage layer in the first pass
+	Conditions []*ExplainCondition `protobuf:"bytes,2,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	// true if the storage layer only returns spansets matching all conditions
+	AllConditions bool `protobuf:"varint,3,opt,name=allConditions,proto3" json:"allConditions,omitempty"`
+	// conditions fetched for the spansets returned by the engine
+	SecondPassConditions []*ExplainCondition `protobuf:"bytes,4,rep,name=secondPassConditions,proto3" json:"secondPassConditions,omitempty"`
+	// blocks of the tenant outside of the time range of the search
+	SkippedBlocks  uint32 `protobuf:"varint,5,opt,name=skippedBlocks,proto3" json:"skippedBlocks,omitempty"`
+	SearchedBlocks uint32 `protobuf:"varint,6,opt,name=searchedBlocks,proto3" json:"searchedBlocks,omitempty"`
+	// pages (row groups of parquet blocks) of the searched blocks
+	SearchedPages uint32             `protobuf:"varint,7,opt,name=searchedPages,proto3" json:"searchedPages,omitempty"`
+	Iterators     []*ExplainIterator `protobuf:"bytes,8,rep,name=iterators,proto3" json:"iterators,omitempty"`
+}
+
+func (m *SearchExplain) Reset()         { *m = SearchExplain{} }
+func (m *SearchExplain) String() string { return proto.CompactTextString(m) }
+func (*SearchExplain) ProtoMessage()    {}
+func (*SearchExplain) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{9}
 }
-func (m *TraceSearchMetadata) XXX_Unmarshal(b []byte) error {
+func (m *SearchExplain) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TraceSearchMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchExplain) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TraceSearchMetadata.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchExplain.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -499,85 +684,95 @@ func (m *TraceSearchMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *TraceSearchMetadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TraceSearchMetadata.Merge(m, src)
+func (m *SearchExplain) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchExplain.Merge(m, src)
 }
-func (m *TraceSearchMetadata) XXX_Size() int {
+func (m *SearchExplain) XXX_Size() int {
 	return m.Size()
 }
-func (m *TraceSearchMetadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_TraceSearchMetadata.DiscardUnknown(m)
+func (m *SearchExplain) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchExplain.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TraceSearchMetadata proto.InternalMessageInfo
+var xxx_messageInfo_SearchExplain proto.InternalMessageInfo
 
-func (m *TraceSearchMetadata) GetTraceID() string {
+func (m *SearchExplain) GetPipeline() []string {
 	if m != nil {
-		return m.TraceID
+		return m.Pipeline
 	}
-	return ""
+	return nil
 }
 
-func (m *TraceSearchMetadata) GetRootServiceName() string {
+func (m *SearchExplain) GetConditions() []*ExplainCondition {
 	if m != nil {
-		return m.RootServiceName
+		return m.Conditions
 	}
-	return ""
+	return nil
 }
 
-func (m *TraceSearchMetadata) GetRootTraceName() string {
+func (m *SearchExplain) GetAllConditions() bool {
 	if m != nil {
-		return m.RootTraceName
+		return m.AllConditions
 	}
-	return ""
+	return false
 }
 
-func (m *TraceSearchMetadata) GetStartTimeUnixNano() uint64 {
+func (m *SearchExplain) GetSecondPassConditions() []*ExplainCondition {
 	if m != nil {
-		return m.StartTimeUnixNano
+		return m.SecondPassConditions
+	}
+	return nil
+}
+
+func (m *SearchExplain) GetSkippedBlocks() uint32 {
+	if m != nil {
+		return m.SkippedBlocks
 	}
 	return 0
 }
 
-func (m *TraceSearchMetadata) GetDurationMs() uint32 {
+func (m *SearchExplain) GetSearchedBlocks() uint32 {
 	if m != nil {
-		return m.DurationMs
+		return m.SearchedBlocks
 	}
 	return 0
 }
 
-func (m *TraceSearchMetadata) GetSpanSet() *SpanSet {
+func (m *SearchExplain) GetSearchedPages() uint32 {
 	if m != nil {
-		return m.SpanSet
+		return m.SearchedPages
 	}
-	return nil
+	return 0
 }
 
-func (m *TraceSearchMetadata) GetSpanSets() []*SpanSet {
+func (m *SearchExplain) GetIterators() []*ExplainIterator {
 	if m != nil {
-		return m.SpanSets
+		return m.Iterators
 	}
 	return nil
 }
 
-type SpanSet struct {
-	Spans      []*Span        `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans,omitempty"`
-	Matched    uint32         `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
-	Attributes []*v1.KeyValue `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty"`
+type ExplainCondition struct {
+	Attribute string   `protobuf:"bytes,1,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	Op        string   `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`
+	Operands  []string `protobuf:"bytes,3,rep,name=operands,proto3" json:"operands,omitempty"`
+	// true if the condition is evaluated by the storage layer, false if the storage layer only
+	// fetches the attribute and the engine evaluates it
+	PushedDown bool `protobuf:"varint,4,opt,name=pushedDown,proto3" json:"pushedDown,omitempty"`
 }
 
-func (m *SpanSet) Reset()         { *m = SpanSet{} }
-func (m *SpanSet) String() string { return proto.CompactTextString(m) }
-func (*SpanSet) ProtoMessage()    {}
-func (*SpanSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{7}
+func (m *ExplainCondition) Reset()         { *m = ExplainCondition{} }
+func (m *ExplainCondition) String() string { return proto.CompactTextString(m) }
+func (*ExplainCondition) ProtoMessage()    {}
+func (*ExplainCondition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{10}
 }
-func (m *SpanSet) XXX_Unmarshal(b []byte) error {
+func (m *ExplainCondition) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpanSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ExplainCondition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpanSet.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ExplainCondition.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -587,59 +782,70 @@ func (m *SpanSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *SpanSet) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpanSet.Merge(m, src)
+func (m *ExplainCondition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExplainCondition.Merge(m, src)
 }
-func (m *SpanSet) XXX_Size() int {
+func (m *ExplainCondition) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpanSet) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpanSet.DiscardUnknown(m)
+func (m *ExplainCondition) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExplainCondition.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SpanSet proto.InternalMessageInfo
+var xxx_messageInfo_ExplainCondition proto.InternalMessageInfo
 
-func (m *SpanSet) GetSpans() []*Span {
+func (m *ExplainCondition) GetAttribute() string {
 	if m != nil {
-		return m.Spans
+		return m.Attribute
 	}
-	return nil
+	return ""
 }
 
-func (m *SpanSet) GetMatched() uint32 {
+func (m *ExplainCondition) GetOp() string {
 	if m != nil {
-		return m.Matched
+		return m.Op
 	}
-	return 0
+	return ""
 }
 
-func (m *SpanSet) GetAttributes() []*v1.KeyValue {
+func (m *ExplainCondition) GetOperands() []string {
 	if m != nil {
-		return m.Attributes
+		return m.Operands
 	}
 	return nil
 }
 
-type Span struct {
-	SpanID            string         `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
-	Name              string         `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	StartTimeUnixNano uint64         `protobuf:"varint,3,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
-	DurationNanos     uint64         `protobuf:"varint,4,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
-	Attributes        []*v1.KeyValue `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty"`
+func (m *ExplainCondition) GetPushedDown() bool {
+	if m != nil {
+		return m.PushedDown
+	}
+	return false
 }
 
-func (m *Span) Reset()         { *m = Span{} }
-func (m *Span) String() string { return proto.CompactTextString(m) }
-func (*Span) ProtoMessage()    {}
-func (*Span) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{8}
+// ExplainIterator are the statistics of the parquet iterators of a column, summed over all searched pages.
+type ExplainIterator struct {
+	Column                string `protobuf:"bytes,1,opt,name=column,proto3" json:"column,omitempty"`
+	InspectedColumnChunks int64  `protobuf:"varint,2,opt,name=inspectedColumnChunks,proto3" json:"inspectedColumnChunks,omitempty"`
+	KeptColumnChunks      int64  `protobuf:"varint,3,opt,name=keptColumnChunks,proto3" json:"keptColumnChunks,omitempty"`
+	InspectedPages        int64  `protobuf:"varint,4,opt,name=inspectedPages,proto3" json:"inspectedPages,omitempty"`
+	KeptPages             int64  `protobuf:"varint,5,opt,name=keptPages,proto3" json:"keptPages,omitempty"`
+	InspectedValues       int64  `protobuf:"varint,6,opt,name=inspectedValues,proto3" json:"inspectedValues,omitempty"`
+	KeptValues            int64  `protobuf:"varint,7,opt,name=keptValues,proto3" json:"keptValues,omitempty"`
+	DurationNanos         int64  `protobuf:"varint,8,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
 }
-func (m *Span) XXX_Unmarshal(b []byte) error {
+
+func (m *ExplainIterator) Reset()         { *m = ExplainIterator{} }
+func (m *ExplainIterator) String() string { return proto.CompactTextString(m) }
+func (*ExplainIterator) ProtoMessage()    {}
+func (*ExplainIterator) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{11}
+}
+func (m *ExplainIterator) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Span) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ExplainIterator) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Span.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ExplainIterator.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -649,74 +855,96 @@ func (m *Span) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Span) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Span.Merge(m, src)
+func (m *ExplainIterator) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExplainIterator.Merge(m, src)
 }
-func (m *Span) XXX_Size() int {
+func (m *ExplainIterator) XXX_Size() int {
 	return m.Size()
 }
-func (m *Span) XXX_DiscardUnknown() {
-	xxx_messageInfo_Span.DiscardUnknown(m)
+func (m *ExplainIterator) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExplainIterator.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Span proto.InternalMessageInfo
+var xxx_messageInfo_ExplainIterator proto.InternalMessageInfo
 
-func (m *Span) GetSpanID() string {
+func (m *ExplainIterator) GetColumn() string {
 	if m != nil {
-		return m.SpanID
+		return m.Column
 	}
 	return ""
 }
 
-func (m *Span) GetName() string {
+func (m *ExplainIterator) GetInspectedColumnChunks() int64 {
 	if m != nil {
-		return m.Name
+		return m.InspectedColumnChunks
 	}
-	return ""
+	return 0
 }
 
-func (m *Span) GetStartTimeUnixNano() uint64 {
+func (m *ExplainIterator) GetKeptColumnChunks() int64 {
 	if m != nil {
-		return m.StartTimeUnixNano
+		return m.KeptColumnChunks
 	}
 	return 0
 }
 
-func (m *Span) GetDurationNanos() uint64 {
+func (m *ExplainIterator) GetInspectedPages() int64 {
 	if m != nil {
-		return m.DurationNanos
+		return m.InspectedPages
 	}
 	return 0
 }
 
-func (m *Span) GetAttributes() []*v1.KeyValue {
+func (m *ExplainIterator) GetKeptPages() int64 {
 	if m != nil {
-		return m.Attributes
+		return m.KeptPages
 	}
-	return nil
+	return 0
 }
 
-type SearchMetrics struct {
-	InspectedTraces uint32 `protobuf:"varint,1,opt,name=inspectedTraces,proto3" json:"inspectedTraces,omitempty"`
-	InspectedBytes  uint64 `protobuf:"varint,2,opt,name=inspectedBytes,proto3" json:"inspectedBytes,omitempty"`
-	TotalBlocks     uint32 `protobuf:"varint,3,opt,name=totalBlocks,proto3" json:"totalBlocks,omitempty"`
-	CompletedJobs   uint32 `protobuf:"varint,4,opt,name=completedJobs,proto3" json:"completedJobs,omitempty"`
-	TotalJobs       uint32 `protobuf:"varint,5,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
-	TotalBlockBytes uint64 `protobuf:"varint,6,opt,name=totalBlockBytes,proto3" json:"totalBlockBytes,omitempty"`
+func (m *ExplainIterator) GetInspectedValues() int64 {
+	if m != nil {
+		return m.InspectedValues
+	}
+	return 0
 }
 
-func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
-func (m *SearchMetrics) String() string { return proto.CompactTextString(m) }
-func (*SearchMetrics) ProtoMessage()    {}
-func (*SearchMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{9}
-}
-func (m *SearchMetrics) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
+func (m *ExplainIterator) GetKeptValues() int64 {
+	if m != nil {
+		return m.KeptValues
+	}
+	return 0
 }
-func (m *SearchMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_SearchMetrics.Marshal(b, m, deterministic)
+
+func (m *ExplainIterator) GetDurationNanos() int64 {
+	if m != nil {
+		return m.DurationNanos
+	}
+	return 0
+}
+
+type TraceSearchMetadata struct {
+	TraceID           string     `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
+	RootServiceName   string     `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
+	RootTraceName     string     `protobuf:"bytes,3,opt,name=rootTraceName,proto3" json:"rootTraceName,omitempty"`
+	StartTimeUnixNano uint64     `protobuf:"varint,4,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationMs        uint32     `protobuf:"varint,5,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	SpanSet           *SpanSet   `protobuf:"bytes,6,opt,name=spanSet,proto3" json:"spanSet,omitempty"`
+	SpanSets          []*SpanSet `protobuf:"bytes,7,rep,name=spanSets,proto3" json:"spanSets,omitempty"`
+}
+
+func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
+func (m *TraceSearchMetadata) String() string { return proto.CompactTextString(m) }
+func (*TraceSearchMetadata) ProtoMessage()    {}
+func (*TraceSearchMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{12}
+}
+func (m *TraceSearchMetadata) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceSearchMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceSearchMetadata.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -726,76 +954,85 @@ func (m *SearchMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error
 		return b[:n], nil
 	}
 }
-func (m *SearchMetrics) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchMetrics.Merge(m, src)
+func (m *TraceSearchMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSearchMetadata.Merge(m, src)
 }
-func (m *SearchMetrics) XXX_Size() int {
+func (m *TraceSearchMetadata) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchMetrics) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchMetrics.DiscardUnknown(m)
+func (m *TraceSearchMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSearchMetadata.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchMetrics proto.InternalMessageInfo
+var xxx_messageInfo_TraceSearchMetadata proto.InternalMessageInfo
 
-func (m *SearchMetrics) GetInspectedTraces() uint32 {
+func (m *TraceSearchMetadata) GetTraceID() string {
 	if m != nil {
-		return m.InspectedTraces
+		return m.TraceID
 	}
-	return 0
+	return ""
 }
 
-func (m *SearchMetrics) GetInspectedBytes() uint64 {
+func (m *TraceSearchMetadata) GetRootServiceName() string {
 	if m != nil {
-		return m.InspectedBytes
+		return m.RootServiceName
 	}
-	return 0
+	return ""
 }
 
-func (m *SearchMetrics) GetTotalBlocks() uint32 {
+func (m *TraceSearchMetadata) GetRootTraceName() string {
 	if m != nil {
-		return m.TotalBlocks
+		return m.RootTraceName
 	}
-	return 0
+	return ""
 }
 
-func (m *SearchMetrics) GetCompletedJobs() uint32 {
+func (m *TraceSearchMetadata) GetStartTimeUnixNano() uint64 {
 	if m != nil {
-		return m.CompletedJobs
+		return m.StartTimeUnixNano
 	}
 	return 0
 }
 
-func (m *SearchMetrics) GetTotalJobs() uint32 {
+func (m *TraceSearchMetadata) GetDurationMs() uint32 {
 	if m != nil {
-		return m.TotalJobs
+		return m.DurationMs
 	}
 	return 0
 }
 
-func (m *SearchMetrics) GetTotalBlockBytes() uint64 {
+func (m *TraceSearchMetadata) GetSpanSet() *SpanSet {
 	if m != nil {
-		return m.TotalBlockBytes
+		return m.SpanSet
 	}
-	return 0
+	return nil
 }
 
-type SearchTagsRequest struct {
-	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+func (m *TraceSearchMetadata) GetSpanSets() []*SpanSet {
+	if m != nil {
+		return m.SpanSets
+	}
+	return nil
 }
 
-func (m *SearchTagsRequest) Reset()         { *m = SearchTagsRequest{} }
-func (m *SearchTagsRequest) String() string { return proto.CompactTextString(m) }
-func (*SearchTagsRequest) ProtoMessage()    {}
-func (*SearchTagsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{10}
+type SpanSet struct {
+	Spans      []*Span        `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans,omitempty"`
+	Matched    uint32         `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
+	Attributes []*v1.KeyValue `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty"`
 }
-func (m *SearchTagsRequest) XXX_Unmarshal(b []byte) error {
+
+func (m *SpanSet) Reset()         { *m = SpanSet{} }
+func (m *SpanSet) String() string { return proto.CompactTextString(m) }
+func (*SpanSet) ProtoMessage()    {}
+func (*SpanSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{13}
+}
+func (m *SpanSet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SpanSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagsRequest.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SpanSet.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -805,41 +1042,59 @@ func (m *SearchTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 		return b[:n], nil
 	}
 }
-func (m *SearchTagsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagsRequest.Merge(m, src)
+func (m *SpanSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanSet.Merge(m, src)
 }
-func (m *SearchTagsRequest) XXX_Size() int {
+func (m *SpanSet) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagsRequest.DiscardUnknown(m)
+func (m *SpanSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanSet.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagsRequest proto.InternalMessageInfo
+var xxx_messageInfo_SpanSet proto.InternalMessageInfo
 
-func (m *SearchTagsRequest) GetScope() string {
+func (m *SpanSet) GetSpans() []*Span {
 	if m != nil {
-		return m.Scope
+		return m.Spans
 	}
-	return ""
+	return nil
 }
 
-type SearchTagsResponse struct {
-	TagNames []string `protobuf:"bytes,1,rep,name=tagNames,proto3" json:"tagNames,omitempty"`
+func (m *SpanSet) GetMatched() uint32 {
+	if m != nil {
+		return m.Matched
+	}
+	return 0
 }
 
-func (m *SearchTagsResponse) Reset()         { *m = SearchTagsResponse{} }
-func (m *SearchTagsResponse) String() string { return proto.CompactTextString(m) }
-func (*SearchTagsResponse) ProtoMessage()    {}
-func (*SearchTagsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{11}
+func (m *SpanSet) GetAttributes() []*v1.KeyValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
 }
-func (m *SearchTagsResponse) XXX_Unmarshal(b []byte) error {
+
+type Span struct {
+	SpanID            string         `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
+	Name              string         `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartTimeUnixNano uint64         `protobuf:"varint,3,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationNanos     uint64         `protobuf:"varint,4,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
+	Attributes        []*v1.KeyValue `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty"`
+}
+
+func (m *Span) Reset()         { *m = Span{} }
+func (m *Span) String() string { return proto.CompactTextString(m) }
+func (*Span) ProtoMessage()    {}
+func (*Span) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{14}
+}
+func (m *Span) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Span) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagsResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Span.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -849,41 +1104,74 @@ func (m *SearchTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte,
 		return b[:n], nil
 	}
 }
-func (m *SearchTagsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagsResponse.Merge(m, src)
+func (m *Span) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Span.Merge(m, src)
 }
-func (m *SearchTagsResponse) XXX_Size() int {
+func (m *Span) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagsResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagsResponse.DiscardUnknown(m)
+func (m *Span) XXX_DiscardUnknown() {
+	xxx_messageInfo_Span.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagsResponse proto.InternalMessageInfo
+var xxx_messageInfo_Span proto.InternalMessageInfo
 
-func (m *SearchTagsResponse) GetTagNames() []string {
+func (m *Span) GetSpanID() string {
 	if m != nil {
-		return m.TagNames
+		return m.SpanID
+	}
+	return ""
+}
+
+func (m *Span) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Span) GetStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.StartTimeUnixNano
+	}
+	return 0
+}
+
+func (m *Span) GetDurationNanos() uint64 {
+	if m != nil {
+		return m.DurationNanos
+	}
+	return 0
+}
+
+func (m *Span) GetAttributes() []*v1.KeyValue {
+	if m != nil {
+		return m.Attributes
 	}
 	return nil
 }
 
-type SearchTagsV2Response struct {
-	Scopes []*SearchTagsV2Scope `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
+type SearchMetrics struct {
+	InspectedTraces uint32 `protobuf:"varint,1,opt,name=inspectedTraces,proto3" json:"inspectedTraces,omitempty"`
+	InspectedBytes  uint64 `protobuf:"varint,2,opt,name=inspectedBytes,proto3" json:"inspectedBytes,omitempty"`
+	TotalBlocks     uint32 `protobuf:"varint,3,opt,name=totalBlocks,proto3" json:"totalBlocks,omitempty"`
+	CompletedJobs   uint32 `protobuf:"varint,4,opt,name=completedJobs,proto3" json:"completedJobs,omitempty"`
+	TotalJobs       uint32 `protobuf:"varint,5,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
+	TotalBlockBytes uint64 `protobuf:"varint,6,opt,name=totalBlockBytes,proto3" json:"totalBlockBytes,omitempty"`
 }
 
-func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
-func (m *SearchTagsV2Response) String() string { return proto.CompactTextString(m) }
-func (*SearchTagsV2Response) ProtoMessage()    {}
-func (*SearchTagsV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{12}
+func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
+func (m *SearchMetrics) String() string { return proto.CompactTextString(m) }
+func (*SearchMetrics) ProtoMessage()    {}
+func (*SearchMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{15}
 }
-func (m *SearchTagsV2Response) XXX_Unmarshal(b []byte) error {
+func (m *SearchMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SearchTagsV2Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SearchTagsV2Response.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchMetrics.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -893,35 +1181,202 @@ func (m *SearchTagsV2Response) XXX_Marshal(b []byte, deterministic bool) ([]byte
 		return b[:n], nil
 	}
 }
-func (m *SearchTagsV2Response) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchTagsV2Response.Merge(m, src)
+func (m *SearchMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchMetrics.Merge(m, src)
 }
-func (m *SearchTagsV2Response) XXX_Size() int {
+func (m *SearchMetrics) XXX_Size() int {
 	return m.Size()
 }
-func (m *SearchTagsV2Response) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchTagsV2Response.DiscardUnknown(m)
+func (m *SearchMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchMetrics.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SearchTagsV2Response proto.InternalMessageInfo
+var xxx_messageInfo_SearchMetrics proto.InternalMessageInfo
 
-func (m *SearchTagsV2Response) GetScopes() []*SearchTagsV2Scope {
+func (m *SearchMetrics) GetInspectedTraces() uint32 {
 	if m != nil {
-		return m.Scopes
+		return m.InspectedTraces
 	}
-	return nil
+	return 0
 }
 
-type SearchTagsV2Scope struct {
-	Name string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+func (m *SearchMetrics) GetInspectedBytes() uint64 {
+	if m != nil {
+		return m.InspectedBytes
+	}
+	return 0
 }
 
-func (m *SearchTagsV2Scope) Reset()         { *m = SearchTagsV2Scope{} }
-func (m *SearchTagsV2Scope) String() string { return proto.CompactTextString(m) }
+func (m *SearchMetrics) GetTotalBlocks() uint32 {
+	if m != nil {
+		return m.TotalBlocks
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetCompletedJobs() uint32 {
+	if m != nil {
+		return m.CompletedJobs
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetTotalJobs() uint32 {
+	if m != nil {
+		return m.TotalJobs
+	}
+	return 0
+}
+
+func (m *SearchMetrics) GetTotalBlockBytes() uint64 {
+	if m != nil {
+		return m.TotalBlockBytes
+	}
+	return 0
+}
+
+type SearchTagsRequest struct {
+	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+}
+
+func (m *SearchTagsRequest) Reset()         { *m = SearchTagsRequest{} }
+func (m *SearchTagsRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchTagsRequest) ProtoMessage()    {}
+func (*SearchTagsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{16}
+}
+func (m *SearchTagsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SearchTagsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SearchTagsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagsRequest.Merge(m, src)
+}
+func (m *SearchTagsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchTagsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchTagsRequest proto.InternalMessageInfo
+
+func (m *SearchTagsRequest) GetScope() string {
+	if m != nil {
+		return m.Scope
+	}
+	return ""
+}
+
+type SearchTagsResponse struct {
+	TagNames []string `protobuf:"bytes,1,rep,name=tagNames,proto3" json:"tagNames,omitempty"`
+}
+
+func (m *SearchTagsResponse) Reset()         { *m = SearchTagsResponse{} }
+func (m *SearchTagsResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchTagsResponse) ProtoMessage()    {}
+func (*SearchTagsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{17}
+}
+func (m *SearchTagsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SearchTagsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SearchTagsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagsResponse.Merge(m, src)
+}
+func (m *SearchTagsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchTagsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchTagsResponse proto.InternalMessageInfo
+
+func (m *SearchTagsResponse) GetTagNames() []string {
+	if m != nil {
+		return m.TagNames
+	}
+	return nil
+}
+
+type SearchTagsV2Response struct {
+	Scopes []*SearchTagsV2Scope `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
+}
+
+func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
+func (m *SearchTagsV2Response) String() string { return proto.CompactTextString(m) }
+func (*SearchTagsV2Response) ProtoMessage()    {}
+func (*SearchTagsV2Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{18}
+}
+func (m *SearchTagsV2Response) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchTagsV2Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SearchTagsV2Response.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SearchTagsV2Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTagsV2Response.Merge(m, src)
+}
+func (m *SearchTagsV2Response) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchTagsV2Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTagsV2Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchTagsV2Response proto.InternalMessageInfo
+
+func (m *SearchTagsV2Response) GetScopes() []*SearchTagsV2Scope {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+type SearchTagsV2Scope struct {
+	Name string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (m *SearchTagsV2Scope) Reset()         { *m = SearchTagsV2Scope{} }
+func (m *SearchTagsV2Scope) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsV2Scope) ProtoMessage()    {}
 func (*SearchTagsV2Scope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{13}
+	return fileDescriptor_f22805646f4f62b6, []int{19}
 }
 func (m *SearchTagsV2Scope) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -973,7 +1428,7 @@ func (m *SearchTagValuesRequest) Reset()         { *m = SearchTagValuesRequest{}
 func (m *SearchTagValuesRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesRequest) ProtoMessage()    {}
 func (*SearchTagValuesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{14}
+	return fileDescriptor_f22805646f4f62b6, []int{20}
 }
 func (m *SearchTagValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1024,7 +1479,7 @@ func (m *SearchTagValuesResponse) Reset()         { *m = SearchTagValuesResponse
 func (m *SearchTagValuesResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesResponse) ProtoMessage()    {}
 func (*SearchTagValuesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{15}
+	return fileDescriptor_f22805646f4f62b6, []int{21}
 }
 func (m *SearchTagValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1069,7 +1524,7 @@ func (m *TagValue) Reset()         { *m = TagValue{} }
 func (m *TagValue) String() string { return proto.CompactTextString(m) }
 func (*TagValue) ProtoMessage()    {}
 func (*TagValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{16}
+	return fileDescriptor_f22805646f4f62b6, []int{22}
 }
 func (m *TagValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1120,7 +1575,7 @@ func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Resp
 func (m *SearchTagValuesV2Response) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesV2Response) ProtoMessage()    {}
 func (*SearchTagValuesV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{17}
+	return fileDescriptor_f22805646f4f62b6, []int{23}
 }
 func (m *SearchTagValuesV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1164,7 +1619,7 @@ func (m *Trace) Reset()         { *m = Trace{} }
 func (m *Trace) String() string { return proto.CompactTextString(m) }
 func (*Trace) ProtoMessage()    {}
 func (*Trace) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{18}
+	return fileDescriptor_f22805646f4f62b6, []int{24}
 }
 func (m *Trace) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1208,7 +1663,7 @@ func (m *PushResponse) Reset()         { *m = PushResponse{} }
 func (m *PushResponse) String() string { return proto.CompactTextString(m) }
 func (*PushResponse) ProtoMessage()    {}
 func (*PushResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{19}
+	return fileDescriptor_f22805646f4f62b6, []int{25}
 }
 func (m *PushResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1237,8 +1692,115 @@ func (m *PushResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PushResponse proto.InternalMessageInfo
 
+// TransferTracesRequest holds a trace of a leaving ingester. The first request of a stream only holds the ID of the
+// leaving ingester.
+type TransferTracesRequest struct {
+	FromIngesterID string `protobuf:"bytes,1,opt,name=fromIngesterID,proto3" json:"fromIngesterID,omitempty"`
+	TenantID       string `protobuf:"bytes,2,opt,name=tenantID,proto3" json:"tenantID,omitempty"`
+	TraceID        []byte `protobuf:"bytes,3,opt,name=traceID,proto3" json:"traceID,omitempty"`
+	Segment        []byte `protobuf:"bytes,4,opt,name=segment,proto3" json:"segment,omitempty"`
+}
+
+func (m *TransferTracesRequest) Reset()         { *m = TransferTracesRequest{} }
+func (m *TransferTracesRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferTracesRequest) ProtoMessage()    {}
+func (*TransferTracesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{26}
+}
+func (m *TransferTracesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransferTracesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransferTracesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransferTracesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferTracesRequest.Merge(m, src)
+}
+func (m *TransferTracesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransferTracesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferTracesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferTracesRequest proto.InternalMessageInfo
+
+func (m *TransferTracesRequest) GetFromIngesterID() string {
+	if m != nil {
+		return m.FromIngesterID
+	}
+	return ""
+}
+
+func (m *TransferTracesRequest) GetTenantID() string {
+	if m != nil {
+		return m.TenantID
+	}
+	return ""
+}
+
+func (m *TransferTracesRequest) GetTraceID() []byte {
+	if m != nil {
+		return m.TraceID
+	}
+	return nil
+}
+
+func (m *TransferTracesRequest) GetSegment() []byte {
+	if m != nil {
+		return m.Segment
+	}
+	return nil
+}
+
+type TransferTracesResponse struct {
+}
+
+func (m *TransferTracesResponse) Reset()         { *m = TransferTracesResponse{} }
+func (m *TransferTracesResponse) String() string { return proto.CompactTextString(m) }
+func (*TransferTracesResponse) ProtoMessage()    {}
+func (*TransferTracesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{27}
+}
+func (m *TransferTracesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransferTracesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransferTracesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransferTracesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferTracesResponse.Merge(m, src)
+}
+func (m *TransferTracesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransferTracesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferTracesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferTracesResponse proto.InternalMessageInfo
+
 // PushBytesRequest pushes slices of traces, ids and searchdata. Traces are encoded using the
-//  current BatchDecoder in ./pkg/model
+//
+//	current BatchDecoder in ./pkg/model
 type PushBytesRequest struct {
 	// pre-marshalled Traces. length must match ids
 	Traces []PreallocBytes `protobuf:"bytes,2,rep,name=traces,proto3,customtype=PreallocBytes" json:"traces"`
@@ -1252,7 +1814,7 @@ func (m *PushBytesRequest) Reset()         { *m = PushBytesRequest{} }
 func (m *PushBytesRequest) String() string { return proto.CompactTextString(m) }
 func (*PushBytesRequest) ProtoMessage()    {}
 func (*PushBytesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{20}
+	return fileDescriptor_f22805646f4f62b6, []int{28}
 }
 func (m *PushBytesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1290,7 +1852,7 @@ func (m *PushSpansRequest) Reset()         { *m = PushSpansRequest{} }
 func (m *PushSpansRequest) String() string { return proto.CompactTextString(m) }
 func (*PushSpansRequest) ProtoMessage()    {}
 func (*PushSpansRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{21}
+	return fileDescriptor_f22805646f4f62b6, []int{29}
 }
 func (m *PushSpansRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1335,7 +1897,7 @@ func (m *TraceBytes) Reset()         { *m = TraceBytes{} }
 func (m *TraceBytes) String() string { return proto.CompactTextString(m) }
 func (*TraceBytes) ProtoMessage()    {}
 func (*TraceBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{22}
+	return fileDescriptor_f22805646f4f62b6, []int{30}
 }
 func (m *TraceBytes) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1382,7 +1944,7 @@ func (m *LinkSlice) Reset()         { *m = LinkSlice{} }
 func (m *LinkSlice) String() string { return proto.CompactTextString(m) }
 func (*LinkSlice) ProtoMessage()    {}
 func (*LinkSlice) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{23}
+	return fileDescriptor_f22805646f4f62b6, []int{31}
 }
 func (m *LinkSlice) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1430,7 +1992,7 @@ func (m *SpanMetricsRequest) Reset()         { *m = SpanMetricsRequest{} }
 func (m *SpanMetricsRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsRequest) ProtoMessage()    {}
 func (*SpanMetricsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{24}
+	return fileDescriptor_f22805646f4f62b6, []int{32}
 }
 func (m *SpanMetricsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1506,7 +2068,7 @@ func (m *SpanMetricsSummaryRequest) Reset()         { *m = SpanMetricsSummaryReq
 func (m *SpanMetricsSummaryRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryRequest) ProtoMessage()    {}
 func (*SpanMetricsSummaryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{25}
+	return fileDescriptor_f22805646f4f62b6, []int{33}
 }
 func (m *SpanMetricsSummaryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1581,7 +2143,7 @@ func (m *SpanMetricsResponse) Reset()         { *m = SpanMetricsResponse{} }
 func (m *SpanMetricsResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResponse) ProtoMessage()    {}
 func (*SpanMetricsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{26}
+	return fileDescriptor_f22805646f4f62b6, []int{34}
 }
 func (m *SpanMetricsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1647,7 +2209,7 @@ func (m *RawHistogram) Reset()         { *m = RawHistogram{} }
 func (m *RawHistogram) String() string { return proto.CompactTextString(m) }
 func (*RawHistogram) ProtoMessage()    {}
 func (*RawHistogram) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{27}
+	return fileDescriptor_f22805646f4f62b6, []int{35}
 }
 func (m *RawHistogram) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1700,7 +2262,7 @@ func (m *SpanMetrics) Reset()         { *m = SpanMetrics{} }
 func (m *SpanMetrics) String() string { return proto.CompactTextString(m) }
 func (*SpanMetrics) ProtoMessage()    {}
 func (*SpanMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{28}
+	return fileDescriptor_f22805646f4f62b6, []int{36}
 }
 func (m *SpanMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1764,7 +2326,7 @@ func (m *SpanMetricsSummary) Reset()         { *m = SpanMetricsSummary{} }
 func (m *SpanMetricsSummary) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummary) ProtoMessage()    {}
 func (*SpanMetricsSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{29}
+	return fileDescriptor_f22805646f4f62b6, []int{37}
 }
 func (m *SpanMetricsSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1850,7 +2412,7 @@ func (m *SpanMetricsSummaryResponse) Reset()         { *m = SpanMetricsSummaryRe
 func (m *SpanMetricsSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryResponse) ProtoMessage()    {}
 func (*SpanMetricsSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{30}
+	return fileDescriptor_f22805646f4f62b6, []int{38}
 }
 func (m *SpanMetricsSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1901,7 +2463,7 @@ func (m *TraceQLStatic) Reset()         { *m = TraceQLStatic{} }
 func (m *TraceQLStatic) String() string { return proto.CompactTextString(m) }
 func (*TraceQLStatic) ProtoMessage()    {}
 func (*TraceQLStatic) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{31}
+	return fileDescriptor_f22805646f4f62b6, []int{39}
 }
 func (m *TraceQLStatic) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1986,3081 +2548,10461 @@ func (m *TraceQLStatic) GetKind() int32 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*TraceByIDRequest)(nil), "tempopb.TraceByIDRequest")
-	proto.RegisterType((*TraceByIDResponse)(nil), "tempopb.TraceByIDResponse")
-	proto.RegisterType((*TraceByIDMetrics)(nil), "tempopb.TraceByIDMetrics")
-	proto.RegisterType((*SearchRequest)(nil), "tempopb.SearchRequest")
-	proto.RegisterMapType((map[string]string)(nil), "tempopb.SearchRequest.TagsEntry")
-	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
-	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
-	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
-	proto.RegisterType((*SpanSet)(nil), "tempopb.SpanSet")
-	proto.RegisterType((*Span)(nil), "tempopb.Span")
-	proto.RegisterType((*SearchMetrics)(nil), "tempopb.SearchMetrics")
-	proto.RegisterType((*SearchTagsRequest)(nil), "tempopb.SearchTagsRequest")
-	proto.RegisterType((*SearchTagsResponse)(nil), "tempopb.SearchTagsResponse")
-	proto.RegisterType((*SearchTagsV2Response)(nil), "tempopb.SearchTagsV2Response")
-	proto.RegisterType((*SearchTagsV2Scope)(nil), "tempopb.SearchTagsV2Scope")
-	proto.RegisterType((*SearchTagValuesRequest)(nil), "tempopb.SearchTagValuesRequest")
-	proto.RegisterType((*SearchTagValuesResponse)(nil), "tempopb.SearchTagValuesResponse")
-	proto.RegisterType((*TagValue)(nil), "tempopb.TagValue")
-	proto.RegisterType((*SearchTagValuesV2Response)(nil), "tempopb.SearchTagValuesV2Response")
-	proto.RegisterType((*Trace)(nil), "tempopb.Trace")
-	proto.RegisterType((*PushResponse)(nil), "tempopb.PushResponse")
-	proto.RegisterType((*PushBytesRequest)(nil), "tempopb.PushBytesRequest")
-	proto.RegisterType((*PushSpansRequest)(nil), "tempopb.PushSpansRequest")
-	proto.RegisterType((*TraceBytes)(nil), "tempopb.TraceBytes")
-	proto.RegisterType((*LinkSlice)(nil), "tempopb.LinkSlice")
-	proto.RegisterType((*SpanMetricsRequest)(nil), "tempopb.SpanMetricsRequest")
-	proto.RegisterType((*SpanMetricsSummaryRequest)(nil), "tempopb.SpanMetricsSummaryRequest")
-	proto.RegisterType((*SpanMetricsResponse)(nil), "tempopb.SpanMetricsResponse")
-	proto.RegisterType((*RawHistogram)(nil), "tempopb.RawHistogram")
-	proto.RegisterType((*SpanMetrics)(nil), "tempopb.SpanMetrics")
-	proto.RegisterType((*SpanMetricsSummary)(nil), "tempopb.SpanMetricsSummary")
-	proto.RegisterType((*SpanMetricsSummaryResponse)(nil), "tempopb.SpanMetricsSummaryResponse")
-	proto.RegisterType((*TraceQLStatic)(nil), "tempopb.TraceQLStatic")
+// QueryRangeRequest evaluates a TraceQL metrics query over a step-aligned time range. When
+// blockID is set the request is limited to the given pages of a single backend block.
+type QueryRangeRequest struct {
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Start uint64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint64 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	Step  uint64 `protobuf:"varint,4,opt,name=step,proto3" json:"step,omitempty"`
+	// backend block pages to evaluate the query on, set by the query frontend when sharding.
+	// only the block parameters are used, searchReq is not set
+	Block *SearchBlockRequest `protobuf:"bytes,5,opt,name=block,proto3" json:"block,omitempty"`
 }
 
-func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
-
-var fileDescriptor_f22805646f4f62b6 = []byte{
-	// 1862 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x4f, 0x8f, 0x1b, 0x49,
-	0x15, 0x9f, 0x1e, 0xff, 0x1b, 0x3f, 0xcf, 0x24, 0x9e, 0xda, 0x64, 0xe2, 0x38, 0x61, 0x32, 0xea,
-	0x8d, 0x60, 0x40, 0xbb, 0x9e, 0x89, 0x37, 0xa3, 0x25, 0x1b, 0x04, 0xc2, 0x4c, 0x48, 0xb2, 0x9b,
-	0x59, 0xb2, 0xe5, 0x21, 0x48, 0x5c, 0x56, 0xed, 0xee, 0x8a, 0xd3, 0x8c, 0xdd, 0xe5, 0xed, 0x2e,
-	0x0f, 0x63, 0x4e, 0x08, 0x09, 0x24, 0x24, 0x0e, 0x5c, 0x38, 0x70, 0x41, 0xe2, 0xb4, 0xe2, 0x73,
-	0x70, 0xd9, 0x13, 0x5a, 0x71, 0x42, 0x1c, 0x56, 0x28, 0xf9, 0x04, 0x7c, 0x03, 0xf4, 0x5e, 0x55,
-	0xf5, 0x1f, 0xdb, 0x33, 0x4b, 0xe0, 0xb0, 0x27, 0xd7, 0xfb, 0xd5, 0xaf, 0x5e, 0xbd, 0x7a, 0xef,
-	0xd5, 0xab, 0xd7, 0x86, 0x6b, 0x93, 0x93, 0xe1, 0x9e, 0x12, 0xe3, 0x89, 0x9c, 0x0c, 0xf4, 0x6f,
-	0x67, 0x12, 0x4b, 0x25, 0x59, 0xcd, 0x80, 0xed, 0x2b, 0x2a, 0xf6, 0x7c, 0xb1, 0x77, 0x7a, 0x67,
-	0x8f, 0x06, 0x7a, 0xba, 0xbd, 0xe5, 0xcb, 0xf1, 0x58, 0x46, 0x08, 0xeb, 0x91, 0xc1, 0xdf, 0x1e,
-	0x86, 0xea, 0xc5, 0x74, 0xd0, 0xf1, 0xe5, 0x78, 0x6f, 0x28, 0x87, 0x72, 0x8f, 0xe0, 0xc1, 0xf4,
-	0x39, 0x49, 0x24, 0xd0, 0x48, 0xd3, 0xdd, 0xdf, 0x38, 0xd0, 0x3c, 0x46, 0xb5, 0xbd, 0xd9, 0xe3,
-	0x43, 0x2e, 0x3e, 0x99, 0x8a, 0x44, 0xb1, 0x16, 0xd4, 0x68, 0xab, 0xc7, 0x87, 0x2d, 0x67, 0xc7,
-	0xd9, 0x5d, 0xe7, 0x56, 0x64, 0xdb, 0x00, 0x83, 0x91, 0xf4, 0x4f, 0xfa, 0xca, 0x8b, 0x55, 0x6b,
-	0x75, 0xc7, 0xd9, 0xad, 0xf3, 0x1c, 0xc2, 0xda, 0xb0, 0x46, 0xd2, 0x83, 0x28, 0x68, 0x95, 0x68,
-	0x36, 0x95, 0xd9, 0x4d, 0xa8, 0x7f, 0x32, 0x15, 0xf1, 0xec, 0x48, 0x06, 0xa2, 0x55, 0xa1, 0xc9,
-	0x0c, 0x70, 0x23, 0xd8, 0xcc, 0xd9, 0x91, 0x4c, 0x64, 0x94, 0x08, 0x76, 0x1b, 0x2a, 0xb4, 0x33,
-	0x99, 0xd1, 0xe8, 0x5e, 0xea, 0x18, 0x9f, 0x74, 0x88, 0xca, 0xf5, 0x24, 0x7b, 0x07, 0x6a, 0x63,
-	0xa1, 0xe2, 0xd0, 0x4f, 0xc8, 0xa2, 0x46, 0xf7, 0x7a, 0x91, 0x87, 0x2a, 0x8f, 0x34, 0x81, 0x5b,
-	0xa6, 0xcb, 0x72, 0xe7, 0x36, 0x93, 0xee, 0xdf, 0x56, 0x61, 0xa3, 0x2f, 0xbc, 0xd8, 0x7f, 0x61,
-	0x3d, 0xf1, 0x1e, 0x94, 0x8f, 0xbd, 0x61, 0xd2, 0x72, 0x76, 0x4a, 0xbb, 0x8d, 0xee, 0x4e, 0xaa,
-	0xb7, 0xc0, 0xea, 0x20, 0xe5, 0x41, 0xa4, 0xe2, 0x59, 0xaf, 0xfc, 0xd9, 0x17, 0xb7, 0x56, 0x38,
-	0xad, 0x61, 0xb7, 0x61, 0xe3, 0x28, 0x8c, 0x0e, 0xa7, 0xb1, 0xa7, 0x42, 0x19, 0x1d, 0x69, 0xe3,
-	0x36, 0x78, 0x11, 0x24, 0x96, 0x77, 0x96, 0x63, 0x95, 0x0c, 0x2b, 0x0f, 0xb2, 0x2b, 0x50, 0x79,
-	0x12, 0x8e, 0x43, 0xd5, 0x2a, 0xd3, 0xac, 0x16, 0x10, 0x4d, 0x28, 0x10, 0x15, 0x8d, 0x92, 0xc0,
-	0x9a, 0x50, 0x12, 0x51, 0xd0, 0xaa, 0x12, 0x86, 0x43, 0xe4, 0x7d, 0x84, 0x8e, 0x6e, 0xad, 0x91,
-	0xd7, 0xb5, 0xc0, 0x76, 0xe1, 0x72, 0x7f, 0xe2, 0x45, 0xc9, 0x53, 0x11, 0xe3, 0x6f, 0x5f, 0xa8,
-	0x56, 0x9d, 0xd6, 0xcc, 0xc3, 0xed, 0x77, 0xa1, 0x9e, 0x1e, 0x11, 0xd5, 0x9f, 0x88, 0x19, 0x45,
-	0xa4, 0xce, 0x71, 0x88, 0xea, 0x4f, 0xbd, 0xd1, 0x54, 0x98, 0x7c, 0xd0, 0xc2, 0x7b, 0xab, 0xdf,
-	0x76, 0xdc, 0x5f, 0x96, 0x80, 0x69, 0x57, 0xf5, 0x30, 0x0b, 0xac, 0x57, 0xef, 0x42, 0x3d, 0xb1,
-	0x0e, 0x34, 0xa1, 0xdd, 0x5a, 0xee, 0x5a, 0x9e, 0x11, 0x31, 0x2b, 0x29, 0x97, 0x1e, 0x1f, 0x9a,
-	0x8d, 0xac, 0x88, 0x99, 0x45, 0x47, 0x7f, 0xea, 0x0d, 0x85, 0xf1, 0x5f, 0x06, 0xa0, 0x87, 0x27,
-	0xde, 0x50, 0x24, 0xc7, 0x52, 0xab, 0x36, 0x3e, 0x2c, 0x82, 0x98, 0xb9, 0x22, 0xf2, 0x65, 0x10,
-	0x46, 0x43, 0x93, 0x9c, 0xa9, 0x8c, 0x1a, 0xc2, 0x28, 0x10, 0x67, 0xa8, 0xae, 0x1f, 0xfe, 0x42,
-	0x18, 0xdf, 0x16, 0x41, 0xe6, 0xc2, 0xba, 0x92, 0xca, 0x1b, 0x71, 0xe1, 0xcb, 0x38, 0x48, 0x5a,
-	0x35, 0x22, 0x15, 0x30, 0xe4, 0x04, 0x9e, 0xf2, 0x1e, 0xd8, 0x9d, 0x74, 0x40, 0x0a, 0x18, 0x9e,
-	0xf3, 0x54, 0xc4, 0x49, 0x28, 0x23, 0x8a, 0x47, 0x9d, 0x5b, 0x91, 0x31, 0x28, 0x27, 0xb8, 0x3d,
-	0xec, 0x38, 0xbb, 0x65, 0x4e, 0x63, 0xbc, 0x91, 0xcf, 0xa5, 0x54, 0x22, 0x26, 0xc3, 0x1a, 0xb4,
-	0x67, 0x0e, 0x71, 0xcf, 0xe0, 0x92, 0xf5, 0xa8, 0xb9, 0x54, 0x77, 0xa1, 0x4a, 0xf7, 0xc6, 0x66,
-	0xf5, 0xcd, 0xe2, 0x6d, 0xd1, 0xec, 0x23, 0xa1, 0x3c, 0xb4, 0x8a, 0x1b, 0x2e, 0xdb, 0x9f, 0xbf,
-	0x64, 0xf3, 0x11, 0x5b, 0xb8, 0x61, 0x9f, 0xae, 0xc2, 0x1b, 0x4b, 0x34, 0xce, 0x57, 0x97, 0x7a,
-	0x56, 0x5d, 0x76, 0xe1, 0x72, 0x2c, 0xa5, 0xea, 0x8b, 0xf8, 0x34, 0xf4, 0xc5, 0x87, 0xde, 0xd8,
-	0xa6, 0xd4, 0x3c, 0x8c, 0x11, 0x41, 0x88, 0xd4, 0x13, 0x4f, 0x17, 0x9b, 0x22, 0xc8, 0xde, 0x82,
-	0x4d, 0x4a, 0x83, 0xe3, 0x70, 0x2c, 0x7e, 0x1c, 0x85, 0x67, 0x1f, 0x7a, 0x91, 0xa4, 0xe8, 0x97,
-	0xf9, 0xe2, 0x04, 0x7a, 0x32, 0xc8, 0xae, 0xa1, 0xbe, 0x52, 0x39, 0x84, 0x7d, 0x0b, 0x6a, 0x89,
-	0xb9, 0x27, 0x55, 0xf2, 0x40, 0x33, 0xf3, 0x80, 0xc6, 0xb9, 0x25, 0xb0, 0xb7, 0x60, 0xcd, 0x0c,
-	0x31, 0x0f, 0x4a, 0x4b, 0xc9, 0x29, 0xc3, 0xfd, 0xb5, 0x03, 0x35, 0x83, 0xb2, 0x37, 0xa1, 0x82,
-	0xb8, 0x0d, 0xce, 0x46, 0x61, 0x19, 0xd7, 0x73, 0xe8, 0xc2, 0xb1, 0xa7, 0xfc, 0x17, 0x22, 0x30,
-	0x45, 0xc5, 0x8a, 0xec, 0x3e, 0x80, 0xa7, 0x54, 0x1c, 0x0e, 0xa6, 0x4a, 0x60, 0x2d, 0x41, 0x1d,
-	0x37, 0x52, 0x1d, 0xe6, 0xa5, 0x38, 0xbd, 0xd3, 0xf9, 0x40, 0xcc, 0x9e, 0xe1, 0x35, 0xe5, 0x39,
-	0xba, 0xfb, 0x57, 0x07, 0xca, 0xb8, 0x0d, 0xdb, 0x82, 0x2a, 0x6e, 0x94, 0x46, 0xc8, 0x48, 0x98,
-	0x80, 0x51, 0x16, 0x15, 0x1a, 0x2f, 0x77, 0x72, 0xe9, 0x3c, 0x27, 0xdf, 0x86, 0x0d, 0xeb, 0x52,
-	0x94, 0x13, 0x13, 0x8e, 0x22, 0x38, 0x77, 0x8a, 0xca, 0xeb, 0x9d, 0xe2, 0xdf, 0x8e, 0xad, 0xe2,
-	0x26, 0x25, 0x31, 0xaf, 0xc2, 0x28, 0x99, 0x08, 0x5f, 0x89, 0xe0, 0xd8, 0xa6, 0x3e, 0x55, 0xba,
-	0x39, 0x98, 0x7d, 0x1d, 0x2e, 0xa5, 0x50, 0x6f, 0x86, 0x9b, 0xaf, 0x92, 0x7d, 0x73, 0x28, 0xdb,
-	0x81, 0x06, 0xdd, 0x6b, 0x2a, 0x6b, 0xb6, 0x66, 0xe7, 0x21, 0x3c, 0xa8, 0x2f, 0xc7, 0x93, 0x91,
-	0x50, 0x22, 0x78, 0x5f, 0x0e, 0x12, 0x5b, 0x75, 0x0a, 0x20, 0x56, 0x2e, 0x5a, 0x44, 0x0c, 0x9d,
-	0x72, 0x19, 0x80, 0x76, 0x67, 0x2a, 0xb5, 0x39, 0x55, 0x32, 0x67, 0x1e, 0x76, 0xbf, 0x09, 0x9b,
-	0xfa, 0xc8, 0x58, 0xa7, 0x6d, 0x99, 0xc5, 0xe7, 0xc1, 0x97, 0x13, 0x61, 0x82, 0xa8, 0x05, 0x77,
-	0xdf, 0x96, 0x64, 0x4d, 0x35, 0x45, 0xa1, 0x0d, 0x6b, 0xca, 0x1b, 0xe2, 0xad, 0xd1, 0x99, 0x57,
-	0xe7, 0xa9, 0xec, 0xbe, 0x0f, 0x57, 0xb2, 0x15, 0xcf, 0xba, 0xe9, 0x9a, 0x2e, 0x54, 0x49, 0xa5,
-	0xcd, 0xd5, 0xf6, 0x5c, 0x45, 0xd0, 0xf4, 0x3e, 0x52, 0xb8, 0x61, 0xba, 0xf7, 0xf3, 0x86, 0x9a,
-	0xc9, 0x34, 0xad, 0x9c, 0x5c, 0x5a, 0x31, 0x28, 0x2b, 0x7c, 0x79, 0x57, 0xc9, 0x18, 0x1a, 0xbb,
-	0x8f, 0x60, 0x2b, 0x5d, 0x4c, 0x71, 0x4f, 0xf2, 0x1d, 0x8b, 0x36, 0x37, 0xad, 0x29, 0x5a, 0x44,
-	0x27, 0x50, 0x93, 0x61, 0x1f, 0x27, 0x12, 0xdc, 0x77, 0xe1, 0xda, 0x82, 0x26, 0x73, 0x2a, 0x0c,
-	0x89, 0x05, 0x8d, 0x2b, 0x32, 0xc0, 0xbd, 0x0b, 0x6b, 0x76, 0x09, 0x99, 0x38, 0x4b, 0xdd, 0x4b,
-	0xe3, 0xe5, 0x6f, 0xa1, 0xfb, 0x04, 0xae, 0xcf, 0x6d, 0x97, 0x73, 0xe3, 0xde, 0xfc, 0x86, 0x8d,
-	0xee, 0x66, 0x56, 0x92, 0xcd, 0x4c, 0xde, 0x86, 0x1e, 0x54, 0x28, 0x5d, 0xd9, 0x3d, 0xa8, 0x0d,
-	0xe8, 0xde, 0xdb, 0x75, 0xb7, 0xd2, 0x75, 0xba, 0x55, 0x3c, 0xbd, 0xd3, 0xe1, 0x22, 0x91, 0xd3,
-	0xd8, 0x17, 0xf4, 0xa6, 0x73, 0xcb, 0x77, 0x2f, 0xc1, 0xfa, 0xd3, 0x69, 0x92, 0x3e, 0x0a, 0xee,
-	0x9f, 0x1d, 0x68, 0x22, 0x40, 0xe9, 0x64, 0xbd, 0xfa, 0x76, 0xfa, 0x52, 0x60, 0x14, 0xd6, 0x7b,
-	0x57, 0xb1, 0xbb, 0xf9, 0xe7, 0x17, 0xb7, 0x36, 0x9e, 0xc6, 0xc2, 0x1b, 0x8d, 0xa4, 0xaf, 0xd9,
-	0xf6, 0x89, 0xf8, 0x06, 0x94, 0xc2, 0x40, 0x17, 0x9d, 0x73, 0xb9, 0xc8, 0x60, 0x07, 0x00, 0xfa,
-	0x59, 0x3f, 0xf4, 0x94, 0xd7, 0x2a, 0x5f, 0xc4, 0xcf, 0x11, 0xdd, 0x23, 0x6d, 0xa2, 0x3e, 0x89,
-	0x31, 0xf1, 0xff, 0x70, 0xc1, 0x6d, 0x00, 0xd3, 0x01, 0xe2, 0x8d, 0xde, 0x2a, 0xbc, 0x8a, 0xeb,
-	0xf6, 0x50, 0xee, 0x77, 0xa1, 0xfe, 0x24, 0x8c, 0x4e, 0xfa, 0xa3, 0xd0, 0x17, 0xec, 0x0e, 0x54,
-	0x46, 0x61, 0x74, 0x62, 0xf7, 0xba, 0xb1, 0xb8, 0x17, 0xee, 0xd1, 0xc1, 0x05, 0x5c, 0x33, 0xdd,
-	0x5f, 0x39, 0xc0, 0x10, 0xb4, 0xcf, 0x63, 0x76, 0x37, 0x75, 0x5a, 0x3a, 0xb9, 0xb4, 0xc4, 0x34,
-	0x1e, 0xc6, 0x72, 0x3a, 0xe9, 0xd9, 0x74, 0xb5, 0x22, 0xf2, 0x47, 0xd4, 0x00, 0xea, 0xca, 0xaa,
-	0x85, 0xac, 0x01, 0x2c, 0x2f, 0x69, 0x00, 0x2b, 0x69, 0x03, 0xe8, 0xfe, 0xd6, 0x81, 0xeb, 0x39,
-	0x23, 0xfa, 0xd3, 0xf1, 0xd8, 0x8b, 0x67, 0x5f, 0x8d, 0x2d, 0x7f, 0x71, 0xe0, 0x8d, 0x82, 0x43,
-	0xb2, 0x7b, 0x27, 0x12, 0x15, 0x8e, 0x3d, 0x25, 0x02, 0xb2, 0x64, 0x8d, 0x67, 0x00, 0xb5, 0x78,
-	0x13, 0x2f, 0xfa, 0x81, 0x9c, 0x46, 0xca, 0xd4, 0xe4, 0x0c, 0xc0, 0xb2, 0x2d, 0xe2, 0x58, 0x52,
-	0xc3, 0xaa, 0x29, 0xda, 0xb4, 0x39, 0x94, 0x75, 0xb2, 0x26, 0xa6, 0x4c, 0x11, 0xbc, 0x52, 0x78,
-	0x5e, 0x17, 0x5a, 0x98, 0xef, 0xc0, 0x3a, 0xf7, 0x7e, 0xfe, 0x28, 0x4c, 0x94, 0x1c, 0xc6, 0xde,
-	0x18, 0x93, 0x64, 0x30, 0xf5, 0x4f, 0x84, 0x22, 0x03, 0xcb, 0xdc, 0x48, 0x78, 0x76, 0x3f, 0x67,
-	0x99, 0x16, 0xdc, 0x3f, 0x3a, 0xd0, 0xc8, 0xa9, 0x65, 0x3d, 0xd8, 0x1c, 0x79, 0x4a, 0x44, 0xfe,
-	0xec, 0xe3, 0x17, 0x56, 0xa5, 0xc9, 0xa4, 0xab, 0xa9, 0x1d, 0xf9, 0xfd, 0x78, 0xd3, 0xf0, 0x33,
-	0x0b, 0x3a, 0x50, 0x4d, 0x94, 0xa7, 0x42, 0x7f, 0xa1, 0x0b, 0xa3, 0x5c, 0xfe, 0xe8, 0x49, 0x9f,
-	0x66, 0xb9, 0x61, 0xa1, 0xc5, 0xe4, 0x83, 0xc4, 0x78, 0xc4, 0x48, 0xee, 0xdf, 0x8b, 0x69, 0x69,
-	0x32, 0xa2, 0xe8, 0x66, 0xe7, 0xcb, 0xdd, 0xbc, 0x7a, 0x8e, 0x9b, 0xad, 0x91, 0xa5, 0xff, 0xca,
-	0xc8, 0x26, 0x94, 0x26, 0xf7, 0xee, 0x99, 0x56, 0x00, 0x87, 0x1a, 0x39, 0xa0, 0xb4, 0x21, 0xe4,
-	0x40, 0x23, 0xfb, 0xe6, 0xfd, 0xc3, 0x21, 0x21, 0x07, 0xfb, 0xd4, 0x66, 0x23, 0x72, 0xb0, 0xef,
-	0xfe, 0x04, 0xda, 0xcb, 0xb2, 0xdc, 0x24, 0xd8, 0x3d, 0xa8, 0x27, 0x04, 0x85, 0x62, 0xf1, 0x02,
-	0x2f, 0x59, 0x97, 0xb1, 0xdd, 0x3f, 0x38, 0xb0, 0x51, 0x30, 0xbd, 0x50, 0xfb, 0x2b, 0xa6, 0xf6,
-	0xaf, 0x83, 0x13, 0x91, 0x47, 0x4a, 0xdc, 0x89, 0x50, 0x7a, 0x4e, 0xe7, 0x77, 0xb8, 0xf3, 0x1c,
-	0x25, 0xdd, 0x02, 0xd4, 0xb9, 0x93, 0xa0, 0x34, 0xa0, 0xc3, 0xad, 0x71, 0x67, 0x80, 0x52, 0x60,
-	0x0e, 0xe6, 0x04, 0xd4, 0x7b, 0x29, 0x4f, 0x4d, 0xf5, 0x07, 0x44, 0x85, 0x1b, 0x09, 0x77, 0x3c,
-	0x09, 0xa3, 0x80, 0x3e, 0x19, 0x2a, 0x9c, 0xc6, 0xdd, 0xdf, 0x39, 0x50, 0xc5, 0x92, 0x28, 0x62,
-	0xf6, 0x3d, 0xa8, 0xa7, 0xf5, 0x9b, 0x65, 0x1f, 0xc0, 0xf3, 0x35, 0xbd, 0x7d, 0xb5, 0x30, 0x95,
-	0xd6, 0xff, 0x15, 0xf6, 0x7d, 0x68, 0xa4, 0xe4, 0x67, 0xdd, 0xff, 0x45, 0x45, 0xf7, 0x4f, 0x0e,
-	0x34, 0x8d, 0x13, 0x1f, 0x8a, 0x48, 0xc4, 0x9e, 0x92, 0xa9, 0x61, 0x54, 0x7c, 0xe7, 0xb4, 0xe6,
-	0x2b, 0xf9, 0xf9, 0x86, 0x3d, 0x06, 0x78, 0x28, 0x94, 0xbd, 0x44, 0x4b, 0x43, 0x66, 0x75, 0xdc,
-	0x5c, 0x3e, 0x99, 0x1a, 0xf8, 0x69, 0x19, 0x6a, 0xf8, 0xf1, 0x1b, 0x8a, 0x98, 0x3d, 0x82, 0x8d,
-	0x1f, 0x86, 0x51, 0x90, 0xfe, 0x09, 0xc0, 0x96, 0xfc, 0x6b, 0x60, 0xf5, 0xb6, 0x97, 0x4d, 0xe5,
-	0x3c, 0xb7, 0x6e, 0x3f, 0xb1, 0x7c, 0x11, 0x29, 0x76, 0xce, 0xb7, 0x6c, 0xfb, 0xda, 0x02, 0x9e,
-	0xaa, 0x78, 0x00, 0x8d, 0xdc, 0x77, 0x72, 0xfe, 0x90, 0x0b, 0x5f, 0xcf, 0x17, 0xa9, 0x79, 0x08,
-	0x90, 0x75, 0x57, 0x6c, 0x59, 0x3f, 0x66, 0x95, 0xdc, 0x58, 0x3a, 0x97, 0x2a, 0xfa, 0xc0, 0x1e,
-	0x49, 0xb7, 0x69, 0x17, 0xaa, 0xfa, 0xda, 0xd2, 0xb6, 0x2f, 0xa7, 0xec, 0x19, 0x5c, 0x9e, 0xeb,
-	0x7e, 0xd8, 0xad, 0xc5, 0x35, 0x85, 0x86, 0xae, 0xbd, 0x73, 0x3e, 0x21, 0xd5, 0xfb, 0xd3, 0x5c,
-	0x2f, 0x69, 0xbb, 0xaa, 0x2f, 0xd7, 0xec, 0x9e, 0x47, 0xc8, 0xdb, 0xdc, 0xfd, 0x11, 0x34, 0xfb,
-	0x2a, 0x16, 0xde, 0x38, 0x8c, 0x86, 0x36, 0x63, 0xee, 0x43, 0xd5, 0xfc, 0x59, 0xf0, 0xba, 0x11,
-	0xde, 0x77, 0xba, 0x3f, 0x83, 0x9a, 0x4d, 0xe1, 0x8f, 0x97, 0x96, 0x5e, 0xf7, 0xa2, 0x5a, 0x64,
-	0xf4, 0xbf, 0x79, 0x21, 0xc7, 0x1a, 0xdf, 0x6b, 0x7d, 0xf6, 0x72, 0xdb, 0xf9, 0xfc, 0xe5, 0xb6,
-	0xf3, 0xaf, 0x97, 0xdb, 0xce, 0xef, 0x5f, 0x6d, 0xaf, 0x7c, 0xfe, 0x6a, 0x7b, 0xe5, 0x1f, 0xaf,
-	0xb6, 0x57, 0x06, 0x55, 0xfa, 0xd7, 0xef, 0x9d, 0xff, 0x04, 0x00, 0x00, 0xff, 0xff, 0x0c, 0x4a,
-	0xef, 0xf5, 0x76, 0x14, 0x00, 0x00,
+func (m *QueryRangeRequest) Reset()         { *m = QueryRangeRequest{} }
+func (m *QueryRangeRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryRangeRequest) ProtoMessage()    {}
+func (*QueryRangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{40}
+}
+func (m *QueryRangeRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryRangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRangeRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryRangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRangeRequest.Merge(m, src)
+}
+func (m *QueryRangeRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRangeRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_QueryRangeRequest proto.InternalMessageInfo
 
-// PusherClient is the client API for Pusher service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PusherClient interface {
-	// different versions of PushBytes expect the trace data to be pushed in different formats
-	PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
-	PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
+func (m *QueryRangeRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
 }
 
-type pusherClient struct {
-	cc *grpc.ClientConn
+func (m *QueryRangeRequest) GetStart() uint64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
 }
 
-func NewPusherClient(cc *grpc.ClientConn) PusherClient {
-	return &pusherClient{cc}
+func (m *QueryRangeRequest) GetEnd() uint64 {
+	if m != nil {
+		return m.End
+	}
+	return 0
 }
 
-func (c *pusherClient) PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytes", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *QueryRangeRequest) GetStep() uint64 {
+	if m != nil {
+		return m.Step
 	}
-	return out, nil
+	return 0
 }
 
-func (c *pusherClient) PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytesV2", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *QueryRangeRequest) GetBlock() *SearchBlockRequest {
+	if m != nil {
+		return m.Block
 	}
-	return out, nil
+	return nil
 }
 
-// PusherServer is the server API for Pusher service.
-type PusherServer interface {
-	// different versions of PushBytes expect the trace data to be pushed in different formats
-	PushBytes(context.Context, *PushBytesRequest) (*PushResponse, error)
-	PushBytesV2(context.Context, *PushBytesRequest) (*PushResponse, error)
+type QueryRangeResponse struct {
+	Series  []*TimeSeries  `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	Metrics *SearchMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
 }
 
-// UnimplementedPusherServer can be embedded to have forward compatible implementations.
-type UnimplementedPusherServer struct {
+func (m *QueryRangeResponse) Reset()         { *m = QueryRangeResponse{} }
+func (m *QueryRangeResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryRangeResponse) ProtoMessage()    {}
+func (*QueryRangeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{41}
 }
-
-func (*UnimplementedPusherServer) PushBytes(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PushBytes not implemented")
+func (m *QueryRangeResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (*UnimplementedPusherServer) PushBytesV2(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PushBytesV2 not implemented")
+func (m *QueryRangeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryRangeResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-
-func RegisterPusherServer(s *grpc.Server, srv PusherServer) {
-	s.RegisterService(&_Pusher_serviceDesc, srv)
+func (m *QueryRangeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRangeResponse.Merge(m, src)
+}
+func (m *QueryRangeResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryRangeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRangeResponse.DiscardUnknown(m)
 }
 
-func _Pusher_PushBytes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PushBytesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(PusherServer).PushBytes(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Pusher/PushBytes",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PusherServer).PushBytes(ctx, req.(*PushBytesRequest))
+var xxx_messageInfo_QueryRangeResponse proto.InternalMessageInfo
+
+func (m *QueryRangeResponse) GetSeries() []*TimeSeries {
+	if m != nil {
+		return m.Series
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Pusher_PushBytesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PushBytesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(PusherServer).PushBytesV2(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Pusher/PushBytesV2",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PusherServer).PushBytesV2(ctx, req.(*PushBytesRequest))
+func (m *QueryRangeResponse) GetMetrics() *SearchMetrics {
+	if m != nil {
+		return m.Metrics
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _Pusher_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.Pusher",
-	HandlerType: (*PusherServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "PushBytes",
-			Handler:    _Pusher_PushBytes_Handler,
-		},
-		{
-			MethodName: "PushBytesV2",
-			Handler:    _Pusher_PushBytesV2_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+type Sample struct {
+	TimestampMs int64   `protobuf:"varint,1,opt,name=timestampMs,proto3" json:"timestampMs,omitempty"`
+	Value       float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-// MetricsGeneratorClient is the client API for MetricsGenerator service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MetricsGeneratorClient interface {
-	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
-	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
+func (m *Sample) Reset()         { *m = Sample{} }
+func (m *Sample) String() string { return proto.CompactTextString(m) }
+func (*Sample) ProtoMessage()    {}
+func (*Sample) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{42}
 }
-
-type metricsGeneratorClient struct {
-	cc *grpc.ClientConn
+func (m *Sample) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
-	return &metricsGeneratorClient{cc}
+func (m *Sample) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Sample.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Sample) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Sample.Merge(m, src)
+}
+func (m *Sample) XXX_Size() int {
+	return m.Size()
+}
+func (m *Sample) XXX_DiscardUnknown() {
+	xxx_messageInfo_Sample.DiscardUnknown(m)
 }
 
-func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
-	out := new(PushResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_Sample proto.InternalMessageInfo
+
+func (m *Sample) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
 	}
-	return out, nil
+	return 0
 }
 
-func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
-	out := new(SpanMetricsResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Sample) GetValue() float64 {
+	if m != nil {
+		return m.Value
 	}
-	return out, nil
+	return 0
 }
 
-// MetricsGeneratorServer is the server API for MetricsGenerator service.
-type MetricsGeneratorServer interface {
-	PushSpans(context.Context, *PushSpansRequest) (*PushResponse, error)
-	GetMetrics(context.Context, *SpanMetricsRequest) (*SpanMetricsResponse, error)
+type TimeSeries struct {
+	Labels []v1.KeyValue `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels"`
+	// sorted by timestamp
+	Samples []Sample `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples"`
+	// prometheus formatted series labels, i.e. {resource.service.name="foo"}
+	PromLabels string `protobuf:"bytes,3,opt,name=promLabels,proto3" json:"promLabels,omitempty"`
 }
 
-// UnimplementedMetricsGeneratorServer can be embedded to have forward compatible implementations.
-type UnimplementedMetricsGeneratorServer struct {
+func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
+func (m *TimeSeries) String() string { return proto.CompactTextString(m) }
+func (*TimeSeries) ProtoMessage()    {}
+func (*TimeSeries) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{43}
 }
-
-func (*UnimplementedMetricsGeneratorServer) PushSpans(ctx context.Context, req *PushSpansRequest) (*PushResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PushSpans not implemented")
+func (m *TimeSeries) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (*UnimplementedMetricsGeneratorServer) GetMetrics(ctx context.Context, req *SpanMetricsRequest) (*SpanMetricsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
+func (m *TimeSeries) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TimeSeries.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-
-func RegisterMetricsGeneratorServer(s *grpc.Server, srv MetricsGeneratorServer) {
-	s.RegisterService(&_MetricsGenerator_serviceDesc, srv)
+func (m *TimeSeries) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimeSeries.Merge(m, src)
+}
+func (m *TimeSeries) XXX_Size() int {
+	return m.Size()
+}
+func (m *TimeSeries) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimeSeries.DiscardUnknown(m)
 }
 
-func _MetricsGenerator_PushSpans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PushSpansRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MetricsGeneratorServer).PushSpans(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.MetricsGenerator/PushSpans",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MetricsGeneratorServer).PushSpans(ctx, req.(*PushSpansRequest))
+var xxx_messageInfo_TimeSeries proto.InternalMessageInfo
+
+func (m *TimeSeries) GetLabels() []v1.KeyValue {
+	if m != nil {
+		return m.Labels
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _MetricsGenerator_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SpanMetricsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *TimeSeries) GetSamples() []Sample {
+	if m != nil {
+		return m.Samples
 	}
-	if interceptor == nil {
-		return srv.(MetricsGeneratorServer).GetMetrics(ctx, in)
+	return nil
+}
+
+func (m *TimeSeries) GetPromLabels() string {
+	if m != nil {
+		return m.PromLabels
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.MetricsGenerator/GetMetrics",
+	return ""
+}
+
+type TraceDiffResponse struct {
+	// spans of both traces aligned by their path of service and span names in depth-first order
+	Spans        []*SpanDiff `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans,omitempty"`
+	MatchedSpans uint32      `protobuf:"varint,2,opt,name=matchedSpans,proto3" json:"matchedSpans,omitempty"`
+	SpansOnlyInA uint32      `protobuf:"varint,3,opt,name=spansOnlyInA,proto3" json:"spansOnlyInA,omitempty"`
+	SpansOnlyInB uint32      `protobuf:"varint,4,opt,name=spansOnlyInB,proto3" json:"spansOnlyInB,omitempty"`
+}
+
+func (m *TraceDiffResponse) Reset()         { *m = TraceDiffResponse{} }
+func (m *TraceDiffResponse) String() string { return proto.CompactTextString(m) }
+func (*TraceDiffResponse) ProtoMessage()    {}
+func (*TraceDiffResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{44}
+}
+func (m *TraceDiffResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceDiffResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceDiffResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MetricsGeneratorServer).GetMetrics(ctx, req.(*SpanMetricsRequest))
+}
+func (m *TraceDiffResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceDiffResponse.Merge(m, src)
+}
+func (m *TraceDiffResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceDiffResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceDiffResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceDiffResponse proto.InternalMessageInfo
+
+func (m *TraceDiffResponse) GetSpans() []*SpanDiff {
+	if m != nil {
+		return m.Spans
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _MetricsGenerator_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.MetricsGenerator",
-	HandlerType: (*MetricsGeneratorServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "PushSpans",
-			Handler:    _MetricsGenerator_PushSpans_Handler,
-		},
-		{
-			MethodName: "GetMetrics",
-			Handler:    _MetricsGenerator_GetMetrics_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *TraceDiffResponse) GetMatchedSpans() uint32 {
+	if m != nil {
+		return m.MatchedSpans
+	}
+	return 0
 }
 
-// QuerierClient is the client API for Querier service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type QuerierClient interface {
-	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error)
-	SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
-	SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error)
-	SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error)
-	SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error)
-	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error)
-	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error)
+func (m *TraceDiffResponse) GetSpansOnlyInA() uint32 {
+	if m != nil {
+		return m.SpansOnlyInA
+	}
+	return 0
 }
 
-type querierClient struct {
-	cc *grpc.ClientConn
+func (m *TraceDiffResponse) GetSpansOnlyInB() uint32 {
+	if m != nil {
+		return m.SpansOnlyInB
+	}
+	return 0
 }
 
-func NewQuerierClient(cc *grpc.ClientConn) QuerierClient {
-	return &querierClient{cc}
+type SpanDiff struct {
+	// service and span names from the root to the span, i.e. "frontend:GET /api > db:SELECT"
+	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ServiceName string `protobuf:"bytes,2,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// hex encoded span ids, empty if the span doesn't exist in the trace
+	SpanIDA        string `protobuf:"bytes,4,opt,name=spanIDA,proto3" json:"spanIDA,omitempty"`
+	SpanIDB        string `protobuf:"bytes,5,opt,name=spanIDB,proto3" json:"spanIDB,omitempty"`
+	DurationNanosA uint64 `protobuf:"varint,6,opt,name=durationNanosA,proto3" json:"durationNanosA,omitempty"`
+	DurationNanosB uint64 `protobuf:"varint,7,opt,name=durationNanosB,proto3" json:"durationNanosB,omitempty"`
+	// durationNanosB - durationNanosA. only set if the span exists in both traces
+	DurationDeltaNanos int64 `protobuf:"varint,8,opt,name=durationDeltaNanos,proto3" json:"durationDeltaNanos,omitempty"`
+	// span attributes that are different or only exist in one of the traces
+	Attributes []AttributeDiff `protobuf:"bytes,9,rep,name=attributes,proto3" json:"attributes"`
+}
+
+func (m *SpanDiff) Reset()         { *m = SpanDiff{} }
+func (m *SpanDiff) String() string { return proto.CompactTextString(m) }
+func (*SpanDiff) ProtoMessage()    {}
+func (*SpanDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{45}
+}
+func (m *SpanDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpanDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpanDiff.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SpanDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpanDiff.Merge(m, src)
+}
+func (m *SpanDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpanDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpanDiff.DiscardUnknown(m)
 }
 
-func (c *querierClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error) {
-	out := new(TraceByIDResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/FindTraceByID", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SpanDiff proto.InternalMessageInfo
+
+func (m *SpanDiff) GetPath() string {
+	if m != nil {
+		return m.Path
 	}
-	return out, nil
+	return ""
 }
 
-func (c *querierClient) SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
-	out := new(SearchResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchRecent", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanDiff) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
 	}
-	return out, nil
+	return ""
 }
 
-func (c *querierClient) SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
-	out := new(SearchResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchBlock", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanDiff) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-func (c *querierClient) SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error) {
-	out := new(SearchTagsResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTags", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanDiff) GetSpanIDA() string {
+	if m != nil {
+		return m.SpanIDA
 	}
-	return out, nil
+	return ""
 }
 
-func (c *querierClient) SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error) {
-	out := new(SearchTagsV2Response)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagsV2", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanDiff) GetSpanIDB() string {
+	if m != nil {
+		return m.SpanIDB
 	}
-	return out, nil
+	return ""
 }
 
-func (c *querierClient) SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error) {
-	out := new(SearchTagValuesResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValues", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanDiff) GetDurationNanosA() uint64 {
+	if m != nil {
+		return m.DurationNanosA
 	}
-	return out, nil
+	return 0
 }
 
-func (c *querierClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error) {
-	out := new(SearchTagValuesV2Response)
-	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValuesV2", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SpanDiff) GetDurationNanosB() uint64 {
+	if m != nil {
+		return m.DurationNanosB
 	}
-	return out, nil
+	return 0
 }
 
-// QuerierServer is the server API for Querier service.
-type QuerierServer interface {
-	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
-	SearchRecent(context.Context, *SearchRequest) (*SearchResponse, error)
-	SearchBlock(context.Context, *SearchBlockRequest) (*SearchResponse, error)
-	SearchTags(context.Context, *SearchTagsRequest) (*SearchTagsResponse, error)
-	SearchTagsV2(context.Context, *SearchTagsRequest) (*SearchTagsV2Response, error)
-	SearchTagValues(context.Context, *SearchTagValuesRequest) (*SearchTagValuesResponse, error)
-	SearchTagValuesV2(context.Context, *SearchTagValuesRequest) (*SearchTagValuesV2Response, error)
+func (m *SpanDiff) GetDurationDeltaNanos() int64 {
+	if m != nil {
+		return m.DurationDeltaNanos
+	}
+	return 0
 }
 
-// UnimplementedQuerierServer can be embedded to have forward compatible implementations.
-type UnimplementedQuerierServer struct {
+func (m *SpanDiff) GetAttributes() []AttributeDiff {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
 }
 
-func (*UnimplementedQuerierServer) FindTraceByID(ctx context.Context, req *TraceByIDRequest) (*TraceByIDResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+type AttributeDiff struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// nil if the attribute doesn't exist in the span
+	A *v1.AnyValue `protobuf:"bytes,2,opt,name=a,proto3" json:"a,omitempty"`
+	B *v1.AnyValue `protobuf:"bytes,3,opt,name=b,proto3" json:"b,omitempty"`
 }
-func (*UnimplementedQuerierServer) SearchRecent(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchRecent not implemented")
+
+func (m *AttributeDiff) Reset()         { *m = AttributeDiff{} }
+func (m *AttributeDiff) String() string { return proto.CompactTextString(m) }
+func (*AttributeDiff) ProtoMessage()    {}
+func (*AttributeDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{46}
 }
-func (*UnimplementedQuerierServer) SearchBlock(ctx context.Context, req *SearchBlockRequest) (*SearchResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchBlock not implemented")
+func (m *AttributeDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (*UnimplementedQuerierServer) SearchTags(ctx context.Context, req *SearchTagsRequest) (*SearchTagsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTags not implemented")
+func (m *AttributeDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AttributeDiff.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
 }
-func (*UnimplementedQuerierServer) SearchTagsV2(ctx context.Context, req *SearchTagsRequest) (*SearchTagsV2Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTagsV2 not implemented")
+func (m *AttributeDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttributeDiff.Merge(m, src)
 }
-func (*UnimplementedQuerierServer) SearchTagValues(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValues not implemented")
+func (m *AttributeDiff) XXX_Size() int {
+	return m.Size()
 }
-func (*UnimplementedQuerierServer) SearchTagValuesV2(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesV2Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValuesV2 not implemented")
+func (m *AttributeDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttributeDiff.DiscardUnknown(m)
 }
 
-func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
-	s.RegisterService(&_Querier_serviceDesc, srv)
-}
+var xxx_messageInfo_AttributeDiff proto.InternalMessageInfo
 
-func _Querier_FindTraceByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TraceByIDRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).FindTraceByID(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/FindTraceByID",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).FindTraceByID(ctx, req.(*TraceByIDRequest))
+func (m *AttributeDiff) GetKey() string {
+	if m != nil {
+		return m.Key
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Querier_SearchRecent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchRecent(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchRecent",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchRecent(ctx, req.(*SearchRequest))
+func (m *AttributeDiff) GetA() *v1.AnyValue {
+	if m != nil {
+		return m.A
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Querier_SearchBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchBlockRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchBlock(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchBlock",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchBlock(ctx, req.(*SearchBlockRequest))
+func (m *AttributeDiff) GetB() *v1.AnyValue {
+	if m != nil {
+		return m.B
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Querier_SearchTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTags(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTags",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTags(ctx, req.(*SearchTagsRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+type TraceSummaryRequest struct {
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Start uint64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint64 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	Limit uint32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// backend block pages to evaluate the query on, set by the query frontend when sharding.
+	// only the block parameters are used, searchReq is not set
+	Block *SearchBlockRequest `protobuf:"bytes,5,opt,name=block,proto3" json:"block,omitempty"`
 }
 
-func _Querier_SearchTagsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagsRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTagsV2(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTagsV2",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTagsV2(ctx, req.(*SearchTagsRequest))
+func (m *TraceSummaryRequest) Reset()         { *m = TraceSummaryRequest{} }
+func (m *TraceSummaryRequest) String() string { return proto.CompactTextString(m) }
+func (*TraceSummaryRequest) ProtoMessage()    {}
+func (*TraceSummaryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{47}
+}
+func (m *TraceSummaryRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceSummaryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceSummaryRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return interceptor(ctx, in, info, handler)
+}
+func (m *TraceSummaryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummaryRequest.Merge(m, src)
+}
+func (m *TraceSummaryRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceSummaryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummaryRequest.DiscardUnknown(m)
 }
 
-func _Querier_SearchTagValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagValuesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTagValues(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTagValues",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTagValues(ctx, req.(*SearchTagValuesRequest))
+var xxx_messageInfo_TraceSummaryRequest proto.InternalMessageInfo
+
+func (m *TraceSummaryRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Querier_SearchTagValuesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchTagValuesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(QuerierServer).SearchTagValuesV2(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Querier/SearchTagValuesV2",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(QuerierServer).SearchTagValuesV2(ctx, req.(*SearchTagValuesRequest))
+func (m *TraceSummaryRequest) GetStart() uint64 {
+	if m != nil {
+		return m.Start
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _Querier_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.Querier",
-	HandlerType: (*QuerierServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "FindTraceByID",
-			Handler:    _Querier_FindTraceByID_Handler,
-		},
-		{
-			MethodName: "SearchRecent",
-			Handler:    _Querier_SearchRecent_Handler,
-		},
-		{
-			MethodName: "SearchBlock",
-			Handler:    _Querier_SearchBlock_Handler,
-		},
-		{
-			MethodName: "SearchTags",
-			Handler:    _Querier_SearchTags_Handler,
-		},
-		{
-			MethodName: "SearchTagsV2",
-			Handler:    _Querier_SearchTagsV2_Handler,
-		},
-		{
-			MethodName: "SearchTagValues",
-			Handler:    _Querier_SearchTagValues_Handler,
-		},
-		{
-			MethodName: "SearchTagValuesV2",
-			Handler:    _Querier_SearchTagValuesV2_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *TraceSummaryRequest) GetEnd() uint64 {
+	if m != nil {
+		return m.End
+	}
+	return 0
 }
 
-// StreamingQuerierClient is the client API for StreamingQuerier service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type StreamingQuerierClient interface {
-	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error)
+func (m *TraceSummaryRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
 }
 
-type streamingQuerierClient struct {
-	cc *grpc.ClientConn
+func (m *TraceSummaryRequest) GetBlock() *SearchBlockRequest {
+	if m != nil {
+		return m.Block
+	}
+	return nil
 }
 
-func NewStreamingQuerierClient(cc *grpc.ClientConn) StreamingQuerierClient {
-	return &streamingQuerierClient{cc}
+type TraceSummaryResponse struct {
+	// nodes of the aggregated call tree sorted by path
+	Nodes      []*TraceSummaryNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	TraceCount uint32              `protobuf:"varint,2,opt,name=traceCount,proto3" json:"traceCount,omitempty"`
+	Metrics    *SearchMetrics      `protobuf:"bytes,3,opt,name=metrics,proto3" json:"metrics,omitempty"`
 }
 
-func (c *streamingQuerierClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_StreamingQuerier_serviceDesc.Streams[0], "/tempopb.StreamingQuerier/Search", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &streamingQuerierSearchClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *TraceSummaryResponse) Reset()         { *m = TraceSummaryResponse{} }
+func (m *TraceSummaryResponse) String() string { return proto.CompactTextString(m) }
+func (*TraceSummaryResponse) ProtoMessage()    {}
+func (*TraceSummaryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{48}
+}
+func (m *TraceSummaryResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceSummaryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceSummaryResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return x, nil
 }
-
-type StreamingQuerier_SearchClient interface {
-	Recv() (*SearchResponse, error)
-	grpc.ClientStream
+func (m *TraceSummaryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummaryResponse.Merge(m, src)
 }
-
-type streamingQuerierSearchClient struct {
-	grpc.ClientStream
+func (m *TraceSummaryResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceSummaryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummaryResponse.DiscardUnknown(m)
 }
 
-func (x *streamingQuerierSearchClient) Recv() (*SearchResponse, error) {
-	m := new(SearchResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_TraceSummaryResponse proto.InternalMessageInfo
+
+func (m *TraceSummaryResponse) GetNodes() []*TraceSummaryNode {
+	if m != nil {
+		return m.Nodes
 	}
-	return m, nil
+	return nil
 }
 
-// StreamingQuerierServer is the server API for StreamingQuerier service.
-type StreamingQuerierServer interface {
-	Search(*SearchRequest, StreamingQuerier_SearchServer) error
+func (m *TraceSummaryResponse) GetTraceCount() uint32 {
+	if m != nil {
+		return m.TraceCount
+	}
+	return 0
 }
 
-// UnimplementedStreamingQuerierServer can be embedded to have forward compatible implementations.
-type UnimplementedStreamingQuerierServer struct {
+func (m *TraceSummaryResponse) GetMetrics() *SearchMetrics {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
 }
 
-func (*UnimplementedStreamingQuerierServer) Search(req *SearchRequest, srv StreamingQuerier_SearchServer) error {
-	return status.Errorf(codes.Unimplemented, "method Search not implemented")
+type TraceSummaryNode struct {
+	// service and span names from the root to the span, i.e. "frontend:GET /api > db:SELECT"
+	Path        string  `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ServiceName string  `protobuf:"bytes,2,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Name        string  `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Count       uint64  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	ErrorCount  uint64  `protobuf:"varint,5,opt,name=errorCount,proto3" json:"errorCount,omitempty"`
+	ErrorRate   float64 `protobuf:"fixed64,6,opt,name=errorRate,proto3" json:"errorRate,omitempty"`
+	P50         uint64  `protobuf:"varint,7,opt,name=p50,proto3" json:"p50,omitempty"`
+	P90         uint64  `protobuf:"varint,8,opt,name=p90,proto3" json:"p90,omitempty"`
+	P95         uint64  `protobuf:"varint,9,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99         uint64  `protobuf:"varint,10,opt,name=p99,proto3" json:"p99,omitempty"`
+	// power of 2 latency buckets, used to combine the results of the sharded jobs
+	LatencyHistogram []*RawHistogram `protobuf:"bytes,11,rep,name=latencyHistogram,proto3" json:"latencyHistogram,omitempty"`
+}
+
+func (m *TraceSummaryNode) Reset()         { *m = TraceSummaryNode{} }
+func (m *TraceSummaryNode) String() string { return proto.CompactTextString(m) }
+func (*TraceSummaryNode) ProtoMessage()    {}
+func (*TraceSummaryNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{49}
+}
+func (m *TraceSummaryNode) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TraceSummaryNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceSummaryNode.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TraceSummaryNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceSummaryNode.Merge(m, src)
+}
+func (m *TraceSummaryNode) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceSummaryNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceSummaryNode.DiscardUnknown(m)
 }
 
-func RegisterStreamingQuerierServer(s *grpc.Server, srv StreamingQuerierServer) {
-	s.RegisterService(&_StreamingQuerier_serviceDesc, srv)
+var xxx_messageInfo_TraceSummaryNode proto.InternalMessageInfo
+
+func (m *TraceSummaryNode) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
 }
 
-func _StreamingQuerier_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SearchRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (m *TraceSummaryNode) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
 	}
-	return srv.(StreamingQuerierServer).Search(m, &streamingQuerierSearchServer{stream})
+	return ""
 }
 
-type StreamingQuerier_SearchServer interface {
-	Send(*SearchResponse) error
-	grpc.ServerStream
+func (m *TraceSummaryNode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
 }
 
-type streamingQuerierSearchServer struct {
-	grpc.ServerStream
+func (m *TraceSummaryNode) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
 }
 
-func (x *streamingQuerierSearchServer) Send(m *SearchResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (m *TraceSummaryNode) GetErrorCount() uint64 {
+	if m != nil {
+		return m.ErrorCount
+	}
+	return 0
 }
 
-var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.StreamingQuerier",
-	HandlerType: (*StreamingQuerierServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "Search",
-			Handler:       _StreamingQuerier_Search_Handler,
-			ServerStreams: true,
-		},
-	},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *TraceSummaryNode) GetErrorRate() float64 {
+	if m != nil {
+		return m.ErrorRate
+	}
+	return 0
 }
 
-// MetricsClient is the client API for Metrics service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MetricsClient interface {
-	SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error)
+func (m *TraceSummaryNode) GetP50() uint64 {
+	if m != nil {
+		return m.P50
+	}
+	return 0
 }
 
-type metricsClient struct {
-	cc *grpc.ClientConn
+func (m *TraceSummaryNode) GetP90() uint64 {
+	if m != nil {
+		return m.P90
+	}
+	return 0
 }
 
-func NewMetricsClient(cc *grpc.ClientConn) MetricsClient {
-	return &metricsClient{cc}
+func (m *TraceSummaryNode) GetP95() uint64 {
+	if m != nil {
+		return m.P95
+	}
+	return 0
 }
 
-func (c *metricsClient) SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error) {
-	out := new(SpanMetricsSummaryResponse)
-	err := c.cc.Invoke(ctx, "/tempopb.Metrics/SpanMetricsSummary", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *TraceSummaryNode) GetP99() uint64 {
+	if m != nil {
+		return m.P99
 	}
-	return out, nil
+	return 0
 }
 
-// MetricsServer is the server API for Metrics service.
-type MetricsServer interface {
-	SpanMetricsSummary(context.Context, *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error)
+func (m *TraceSummaryNode) GetLatencyHistogram() []*RawHistogram {
+	if m != nil {
+		return m.LatencyHistogram
+	}
+	return nil
 }
 
-// UnimplementedMetricsServer can be embedded to have forward compatible implementations.
-type UnimplementedMetricsServer struct {
+type TraceCriticalPathResponse struct {
+	// segments of the critical path in chronological order
+	Segments []*CriticalPathSegment `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	// time attribution of all spans of the trace in depth-first order
+	Spans []*CriticalPathSpan `protobuf:"bytes,2,rep,name=spans,proto3" json:"spans,omitempty"`
+	// duration of the root span the critical path was computed for
+	DurationNanos uint64 `protobuf:"varint,3,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
 }
 
-func (*UnimplementedMetricsServer) SpanMetricsSummary(ctx context.Context, req *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SpanMetricsSummary not implemented")
+func (m *TraceCriticalPathResponse) Reset()         { *m = TraceCriticalPathResponse{} }
+func (m *TraceCriticalPathResponse) String() string { return proto.CompactTextString(m) }
+func (*TraceCriticalPathResponse) ProtoMessage()    {}
+func (*TraceCriticalPathResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{50}
 }
-
-func RegisterMetricsServer(s *grpc.Server, srv MetricsServer) {
-	s.RegisterService(&_Metrics_serviceDesc, srv)
+func (m *TraceCriticalPathResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func _Metrics_SpanMetricsSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SpanMetricsSummaryRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MetricsServer).SpanMetricsSummary(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/tempopb.Metrics/SpanMetricsSummary",
+func (m *TraceCriticalPathResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TraceCriticalPathResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MetricsServer).SpanMetricsSummary(ctx, req.(*SpanMetricsSummaryRequest))
+}
+func (m *TraceCriticalPathResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceCriticalPathResponse.Merge(m, src)
+}
+func (m *TraceCriticalPathResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TraceCriticalPathResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceCriticalPathResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceCriticalPathResponse proto.InternalMessageInfo
+
+func (m *TraceCriticalPathResponse) GetSegments() []*CriticalPathSegment {
+	if m != nil {
+		return m.Segments
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _Metrics_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "tempopb.Metrics",
-	HandlerType: (*MetricsServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "SpanMetricsSummary",
-			Handler:    _Metrics_SpanMetricsSummary_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "pkg/tempopb/tempo.proto",
+func (m *TraceCriticalPathResponse) GetSpans() []*CriticalPathSpan {
+	if m != nil {
+		return m.Spans
+	}
+	return nil
 }
 
-func (m *TraceByIDRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *TraceCriticalPathResponse) GetDurationNanos() uint64 {
+	if m != nil {
+		return m.DurationNanos
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *TraceByIDRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type CriticalPathSegment struct {
+	SpanID            string `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
+	ServiceName       string `protobuf:"bytes,2,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Name              string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	StartTimeUnixNano uint64 `protobuf:"varint,4,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationNanos     uint64 `protobuf:"varint,5,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
 }
 
-func (m *TraceByIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.QueryMode) > 0 {
-		i -= len(m.QueryMode)
-		copy(dAtA[i:], m.QueryMode)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.QueryMode)))
-		i--
-		dAtA[i] = 0x2a
+func (m *CriticalPathSegment) Reset()         { *m = CriticalPathSegment{} }
+func (m *CriticalPathSegment) String() string { return proto.CompactTextString(m) }
+func (*CriticalPathSegment) ProtoMessage()    {}
+func (*CriticalPathSegment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{51}
+}
+func (m *CriticalPathSegment) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CriticalPathSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CriticalPathSegment.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if len(m.BlockEnd) > 0 {
-		i -= len(m.BlockEnd)
-		copy(dAtA[i:], m.BlockEnd)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockEnd)))
-		i--
-		dAtA[i] = 0x1a
+}
+func (m *CriticalPathSegment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CriticalPathSegment.Merge(m, src)
+}
+func (m *CriticalPathSegment) XXX_Size() int {
+	return m.Size()
+}
+func (m *CriticalPathSegment) XXX_DiscardUnknown() {
+	xxx_messageInfo_CriticalPathSegment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CriticalPathSegment proto.InternalMessageInfo
+
+func (m *CriticalPathSegment) GetSpanID() string {
+	if m != nil {
+		return m.SpanID
 	}
-	if len(m.BlockStart) > 0 {
-		i -= len(m.BlockStart)
-		copy(dAtA[i:], m.BlockStart)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockStart)))
-		i--
-		dAtA[i] = 0x12
+	return ""
+}
+
+func (m *CriticalPathSegment) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
 	}
-	if len(m.TraceID) > 0 {
-		i -= len(m.TraceID)
-		copy(dAtA[i:], m.TraceID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
-		i--
-		dAtA[i] = 0xa
+	return ""
+}
+
+func (m *CriticalPathSegment) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *TraceByIDResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *CriticalPathSegment) GetStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.StartTimeUnixNano
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *TraceByIDResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *CriticalPathSegment) GetDurationNanos() uint64 {
+	if m != nil {
+		return m.DurationNanos
+	}
+	return 0
 }
 
-func (m *TraceByIDResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Metrics != nil {
-		{
-			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
+type CriticalPathSpan struct {
+	SpanID            string `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
+	ParentSpanID      string `protobuf:"bytes,2,opt,name=parentSpanID,proto3" json:"parentSpanID,omitempty"`
+	ServiceName       string `protobuf:"bytes,3,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Name              string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	StartTimeUnixNano uint64 `protobuf:"varint,5,opt,name=startTimeUnixNano,proto3" json:"startTimeUnixNano,omitempty"`
+	DurationNanos     uint64 `protobuf:"varint,6,opt,name=durationNanos,proto3" json:"durationNanos,omitempty"`
+	// time in which none of the children of the span were running
+	SelfTimeNanos uint64 `protobuf:"varint,7,opt,name=selfTimeNanos,proto3" json:"selfTimeNanos,omitempty"`
+	// time waiting on children, concurrent children are only counted once
+	ChildrenTimeNanos uint64 `protobuf:"varint,8,opt,name=childrenTimeNanos,proto3" json:"childrenTimeNanos,omitempty"`
+	// time the span itself is on the critical path
+	CriticalPathNanos uint64 `protobuf:"varint,9,opt,name=criticalPathNanos,proto3" json:"criticalPathNanos,omitempty"`
+}
+
+func (m *CriticalPathSpan) Reset()         { *m = CriticalPathSpan{} }
+func (m *CriticalPathSpan) String() string { return proto.CompactTextString(m) }
+func (*CriticalPathSpan) ProtoMessage()    {}
+func (*CriticalPathSpan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{52}
+}
+func (m *CriticalPathSpan) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CriticalPathSpan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CriticalPathSpan.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0x12
+		return b[:n], nil
 	}
-	if m.Trace != nil {
-		{
-			size, err := m.Trace.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+}
+func (m *CriticalPathSpan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CriticalPathSpan.Merge(m, src)
+}
+func (m *CriticalPathSpan) XXX_Size() int {
+	return m.Size()
+}
+func (m *CriticalPathSpan) XXX_DiscardUnknown() {
+	xxx_messageInfo_CriticalPathSpan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CriticalPathSpan proto.InternalMessageInfo
+
+func (m *CriticalPathSpan) GetSpanID() string {
+	if m != nil {
+		return m.SpanID
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *TraceByIDMetrics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *CriticalPathSpan) GetParentSpanID() string {
+	if m != nil {
+		return m.ParentSpanID
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *TraceByIDMetrics) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *CriticalPathSpan) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
 }
 
-func (m *TraceByIDMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+func (m *CriticalPathSpan) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
 }
 
-func (m *SearchRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *CriticalPathSpan) GetStartTimeUnixNano() uint64 {
+	if m != nil {
+		return m.StartTimeUnixNano
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *SearchRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *CriticalPathSpan) GetDurationNanos() uint64 {
+	if m != nil {
+		return m.DurationNanos
+	}
+	return 0
 }
 
-func (m *SearchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.SpansPerSpanSet != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.SpansPerSpanSet))
-		i--
-		dAtA[i] = 0x48
-	}
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0x42
-	}
-	if m.End != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.End))
-		i--
-		dAtA[i] = 0x30
-	}
-	if m.Start != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Limit != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.MaxDurationMs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.MaxDurationMs))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.MinDurationMs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.MinDurationMs))
-		i--
-		dAtA[i] = 0x10
+func (m *CriticalPathSpan) GetSelfTimeNanos() uint64 {
+	if m != nil {
+		return m.SelfTimeNanos
 	}
-	if len(m.Tags) > 0 {
-		for k := range m.Tags {
-			v := m.Tags[k]
-			baseI := i
-			i -= len(v)
-			copy(dAtA[i:], v)
-			i = encodeVarintTempo(dAtA, i, uint64(len(v)))
-			i--
-			dAtA[i] = 0x12
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintTempo(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintTempo(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0xa
-		}
+	return 0
+}
+
+func (m *CriticalPathSpan) GetChildrenTimeNanos() uint64 {
+	if m != nil {
+		return m.ChildrenTimeNanos
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *SearchBlockRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *CriticalPathSpan) GetCriticalPathNanos() uint64 {
+	if m != nil {
+		return m.CriticalPathNanos
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *SearchBlockRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type AttributeProfileRequest struct {
+	// only return the attributes of this service, all services are returned if empty
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
 }
 
-func (m *SearchBlockRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.FooterSize != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.FooterSize))
-		i--
-		dAtA[i] = 0x58
-	}
-	if m.Size_ != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Size_))
-		i--
-		dAtA[i] = 0x50
-	}
-	if len(m.Version) > 0 {
-		i -= len(m.Version)
-		copy(dAtA[i:], m.Version)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Version)))
-		i--
-		dAtA[i] = 0x4a
-	}
-	if len(m.DataEncoding) > 0 {
-		i -= len(m.DataEncoding)
-		copy(dAtA[i:], m.DataEncoding)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.DataEncoding)))
-		i--
-		dAtA[i] = 0x42
-	}
-	if m.TotalRecords != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalRecords))
-		i--
-		dAtA[i] = 0x38
-	}
-	if m.IndexPageSize != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.IndexPageSize))
-		i--
-		dAtA[i] = 0x30
-	}
-	if len(m.Encoding) > 0 {
-		i -= len(m.Encoding)
-		copy(dAtA[i:], m.Encoding)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Encoding)))
-		i--
-		dAtA[i] = 0x2a
-	}
-	if m.PagesToSearch != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.PagesToSearch))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.StartPage != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.StartPage))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.BlockID) > 0 {
-		i -= len(m.BlockID)
-		copy(dAtA[i:], m.BlockID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockID)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.SearchReq != nil {
-		{
-			size, err := m.SearchReq.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
+func (m *AttributeProfileRequest) Reset()         { *m = AttributeProfileRequest{} }
+func (m *AttributeProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*AttributeProfileRequest) ProtoMessage()    {}
+func (*AttributeProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{53}
+}
+func (m *AttributeProfileRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AttributeProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AttributeProfileRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *AttributeProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttributeProfileRequest.Merge(m, src)
+}
+func (m *AttributeProfileRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *AttributeProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttributeProfileRequest.DiscardUnknown(m)
 }
 
-func (m *SearchResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_AttributeProfileRequest proto.InternalMessageInfo
+
+func (m *AttributeProfileRequest) GetService() string {
+	if m != nil {
+		return m.Service
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *SearchResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type AttributeProfileResponse struct {
+	Services []*ServiceAttributeProfile `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
 }
 
-func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Metrics != nil {
-		{
-			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
+func (m *AttributeProfileResponse) Reset()         { *m = AttributeProfileResponse{} }
+func (m *AttributeProfileResponse) String() string { return proto.CompactTextString(m) }
+func (*AttributeProfileResponse) ProtoMessage()    {}
+func (*AttributeProfileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{54}
+}
+func (m *AttributeProfileResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AttributeProfileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AttributeProfileResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0x12
+		return b[:n], nil
 	}
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Traces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+}
+func (m *AttributeProfileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttributeProfileResponse.Merge(m, src)
+}
+func (m *AttributeProfileResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *AttributeProfileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttributeProfileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttributeProfileResponse proto.InternalMessageInfo
+
+func (m *AttributeProfileResponse) GetServices() []*ServiceAttributeProfile {
+	if m != nil {
+		return m.Services
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *TraceSearchMetadata) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type ServiceAttributeProfile struct {
+	Service    string              `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	SpanCount  uint64              `protobuf:"varint,2,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
+	Attributes []*AttributeProfile `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty"`
 }
 
-func (m *TraceSearchMetadata) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *ServiceAttributeProfile) Reset()         { *m = ServiceAttributeProfile{} }
+func (m *ServiceAttributeProfile) String() string { return proto.CompactTextString(m) }
+func (*ServiceAttributeProfile) ProtoMessage()    {}
+func (*ServiceAttributeProfile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{55}
 }
-
-func (m *TraceSearchMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.SpanSets) > 0 {
-		for iNdEx := len(m.SpanSets) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.SpanSets[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x3a
-		}
-	}
-	if m.SpanSet != nil {
-		{
-			size, err := m.SpanSet.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
+func (m *ServiceAttributeProfile) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ServiceAttributeProfile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ServiceAttributeProfile.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i--
-		dAtA[i] = 0x32
-	}
-	if m.DurationMs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.DurationMs))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.StartTimeUnixNano != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
-		i--
-		dAtA[i] = 0x20
-	}
-	if len(m.RootTraceName) > 0 {
-		i -= len(m.RootTraceName)
-		copy(dAtA[i:], m.RootTraceName)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootTraceName)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.RootServiceName) > 0 {
-		i -= len(m.RootServiceName)
-		copy(dAtA[i:], m.RootServiceName)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootServiceName)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.TraceID) > 0 {
-		i -= len(m.TraceID)
-		copy(dAtA[i:], m.TraceID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
-		i--
-		dAtA[i] = 0xa
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *ServiceAttributeProfile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceAttributeProfile.Merge(m, src)
+}
+func (m *ServiceAttributeProfile) XXX_Size() int {
+	return m.Size()
+}
+func (m *ServiceAttributeProfile) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceAttributeProfile.DiscardUnknown(m)
 }
 
-func (m *SpanSet) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ServiceAttributeProfile proto.InternalMessageInfo
+
+func (m *ServiceAttributeProfile) GetService() string {
+	if m != nil {
+		return m.Service
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *SpanSet) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *ServiceAttributeProfile) GetSpanCount() uint64 {
+	if m != nil {
+		return m.SpanCount
+	}
+	return 0
 }
 
-func (m *SpanSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Attributes) > 0 {
-		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if m.Matched != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Matched))
-		i--
-		dAtA[i] = 0x10
+func (m *ServiceAttributeProfile) GetAttributes() []*AttributeProfile {
+	if m != nil {
+		return m.Attributes
 	}
-	if len(m.Spans) > 0 {
-		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Spans[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
+	return nil
+}
+
+type AttributeProfile struct {
+	// resource, span, event or link
+	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	Key   string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// value types the attribute was seen with
+	Types []string `protobuf:"bytes,3,rep,name=types,proto3" json:"types,omitempty"`
+	// number of times the attribute was seen
+	Count uint64 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	// proto encoded size of all occurrences of the attribute
+	Bytes uint64 `protobuf:"varint,5,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// estimated number of distinct values
+	Cardinality uint64 `protobuf:"varint,6,opt,name=cardinality,proto3" json:"cardinality,omitempty"`
+	// hyperloglog registers, used to combine the cardinality reported by several generators
+	Sketch []byte `protobuf:"bytes,7,opt,name=sketch,proto3" json:"sketch,omitempty"`
+}
+
+func (m *AttributeProfile) Reset()         { *m = AttributeProfile{} }
+func (m *AttributeProfile) String() string { return proto.CompactTextString(m) }
+func (*AttributeProfile) ProtoMessage()    {}
+func (*AttributeProfile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{56}
+}
+func (m *AttributeProfile) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AttributeProfile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AttributeProfile.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *AttributeProfile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttributeProfile.Merge(m, src)
+}
+func (m *AttributeProfile) XXX_Size() int {
+	return m.Size()
+}
+func (m *AttributeProfile) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttributeProfile.DiscardUnknown(m)
 }
 
-func (m *Span) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_AttributeProfile proto.InternalMessageInfo
+
+func (m *AttributeProfile) GetScope() string {
+	if m != nil {
+		return m.Scope
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Span) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *AttributeProfile) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
 }
 
-func (m *Span) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Attributes) > 0 {
-		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x2a
-		}
-	}
-	if m.DurationNanos != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.StartTimeUnixNano != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.SpanID) > 0 {
-		i -= len(m.SpanID)
-		copy(dAtA[i:], m.SpanID)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
-		i--
-		dAtA[i] = 0xa
+func (m *AttributeProfile) GetTypes() []string {
+	if m != nil {
+		return m.Types
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *SearchMetrics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *AttributeProfile) GetCount() uint64 {
+	if m != nil {
+		return m.Count
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *SearchMetrics) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *AttributeProfile) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
 }
 
-func (m *SearchMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.TotalBlockBytes != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlockBytes))
-		i--
-		dAtA[i] = 0x30
+func (m *AttributeProfile) GetCardinality() uint64 {
+	if m != nil {
+		return m.Cardinality
 	}
-	if m.TotalJobs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalJobs))
-		i--
-		dAtA[i] = 0x28
+	return 0
+}
+
+func (m *AttributeProfile) GetSketch() []byte {
+	if m != nil {
+		return m.Sketch
 	}
-	if m.CompletedJobs != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.CompletedJobs))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.TotalBlocks != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlocks))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.InspectedBytes != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedBytes))
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.InspectedTraces != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedTraces))
-		i--
-		dAtA[i] = 0x8
-	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *SearchTagsRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func init() {
+	proto.RegisterType((*TraceByIDRequest)(nil), "tempopb.TraceByIDRequest")
+	proto.RegisterType((*TraceByIDResponse)(nil), "tempopb.TraceByIDResponse")
+	proto.RegisterType((*TraceByIDMetrics)(nil), "tempopb.TraceByIDMetrics")
+	proto.RegisterType((*FlushedBlocksRequest)(nil), "tempopb.FlushedBlocksRequest")
+	proto.RegisterType((*FlushedBlocksResponse)(nil), "tempopb.FlushedBlocksResponse")
+	proto.RegisterType((*SearchRequest)(nil), "tempopb.SearchRequest")
+	proto.RegisterMapType((map[string]string)(nil), "tempopb.SearchRequest.TagsEntry")
+	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
+	proto.RegisterType((*DedicatedColumn)(nil), "tempopb.DedicatedColumn")
+	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
+	proto.RegisterType((*SearchExplain)(nil), "tempopb.SearchExplain")
+	proto.RegisterType((*ExplainCondition)(nil), "tempopb.ExplainCondition")
+	proto.RegisterType((*ExplainIterator)(nil), "tempopb.ExplainIterator")
+	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
+	proto.RegisterType((*SpanSet)(nil), "tempopb.SpanSet")
+	proto.RegisterType((*Span)(nil), "tempopb.Span")
+	proto.RegisterType((*SearchMetrics)(nil), "tempopb.SearchMetrics")
+	proto.RegisterType((*SearchTagsRequest)(nil), "tempopb.SearchTagsRequest")
+	proto.RegisterType((*SearchTagsResponse)(nil), "tempopb.SearchTagsResponse")
+	proto.RegisterType((*SearchTagsV2Response)(nil), "tempopb.SearchTagsV2Response")
+	proto.RegisterType((*SearchTagsV2Scope)(nil), "tempopb.SearchTagsV2Scope")
+	proto.RegisterType((*SearchTagValuesRequest)(nil), "tempopb.SearchTagValuesRequest")
+	proto.RegisterType((*SearchTagValuesResponse)(nil), "tempopb.SearchTagValuesResponse")
+	proto.RegisterType((*TagValue)(nil), "tempopb.TagValue")
+	proto.RegisterType((*SearchTagValuesV2Response)(nil), "tempopb.SearchTagValuesV2Response")
+	proto.RegisterType((*Trace)(nil), "tempopb.Trace")
+	proto.RegisterType((*PushResponse)(nil), "tempopb.PushResponse")
+	proto.RegisterType((*TransferTracesRequest)(nil), "tempopb.TransferTracesRequest")
+	proto.RegisterType((*TransferTracesResponse)(nil), "tempopb.TransferTracesResponse")
+	proto.RegisterType((*PushBytesRequest)(nil), "tempopb.PushBytesRequest")
+	proto.RegisterType((*PushSpansRequest)(nil), "tempopb.PushSpansRequest")
+	proto.RegisterType((*TraceBytes)(nil), "tempopb.TraceBytes")
+	proto.RegisterType((*LinkSlice)(nil), "tempopb.LinkSlice")
+	proto.RegisterType((*SpanMetricsRequest)(nil), "tempopb.SpanMetricsRequest")
+	proto.RegisterType((*SpanMetricsSummaryRequest)(nil), "tempopb.SpanMetricsSummaryRequest")
+	proto.RegisterType((*SpanMetricsResponse)(nil), "tempopb.SpanMetricsResponse")
+	proto.RegisterType((*RawHistogram)(nil), "tempopb.RawHistogram")
+	proto.RegisterType((*SpanMetrics)(nil), "tempopb.SpanMetrics")
+	proto.RegisterType((*SpanMetricsSummary)(nil), "tempopb.SpanMetricsSummary")
+	proto.RegisterType((*SpanMetricsSummaryResponse)(nil), "tempopb.SpanMetricsSummaryResponse")
+	proto.RegisterType((*TraceQLStatic)(nil), "tempopb.TraceQLStatic")
+	proto.RegisterType((*QueryRangeRequest)(nil), "tempopb.QueryRangeRequest")
+	proto.RegisterType((*QueryRangeResponse)(nil), "tempopb.QueryRangeResponse")
+	proto.RegisterType((*Sample)(nil), "tempopb.Sample")
+	proto.RegisterType((*TimeSeries)(nil), "tempopb.TimeSeries")
+	proto.RegisterType((*TraceDiffResponse)(nil), "tempopb.TraceDiffResponse")
+	proto.RegisterType((*SpanDiff)(nil), "tempopb.SpanDiff")
+	proto.RegisterType((*AttributeDiff)(nil), "tempopb.AttributeDiff")
+	proto.RegisterType((*TraceSummaryRequest)(nil), "tempopb.TraceSummaryRequest")
+	proto.RegisterType((*TraceSummaryResponse)(nil), "tempopb.TraceSummaryResponse")
+	proto.RegisterType((*TraceSummaryNode)(nil), "tempopb.TraceSummaryNode")
+	proto.RegisterType((*TraceCriticalPathResponse)(nil), "tempopb.TraceCriticalPathResponse")
+	proto.RegisterType((*CriticalPathSegment)(nil), "tempopb.CriticalPathSegment")
+	proto.RegisterType((*CriticalPathSpan)(nil), "tempopb.CriticalPathSpan")
+	proto.RegisterType((*AttributeProfileRequest)(nil), "tempopb.AttributeProfileRequest")
+	proto.RegisterType((*AttributeProfileResponse)(nil), "tempopb.AttributeProfileResponse")
+	proto.RegisterType((*ServiceAttributeProfile)(nil), "tempopb.ServiceAttributeProfile")
+	proto.RegisterType((*AttributeProfile)(nil), "tempopb.AttributeProfile")
 }
 
-func (m *SearchTagsRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
+func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
 
-func (m *SearchTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Scope) > 0 {
-		i -= len(m.Scope)
-		copy(dAtA[i:], m.Scope)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Scope)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+var fileDescriptor_f22805646f4f62b6 = []byte{
+	// 3115 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x5a, 0xcd, 0x6f, 0x1c, 0xc7,
+	0xb1, 0xd7, 0x70, 0x3f, 0xb8, 0x5b, 0x5c, 0x52, 0xcb, 0x16, 0x45, 0xad, 0xd6, 0x7e, 0x14, 0xdf,
+	0xd8, 0xb0, 0x69, 0x3f, 0x9b, 0x94, 0xd6, 0xe2, 0xb3, 0x69, 0x1b, 0x49, 0x48, 0x51, 0x96, 0x69,
+	0x8b, 0x36, 0x3d, 0x2b, 0x2b, 0x41, 0x10, 0xc0, 0xe8, 0xdd, 0x6d, 0x2e, 0x07, 0xdc, 0x9d, 0x19,
+	0xcf, 0xf4, 0xd2, 0x62, 0x90, 0x53, 0x80, 0x24, 0x08, 0x10, 0x20, 0xb9, 0x04, 0xb0, 0x81, 0x5c,
+	0x72, 0xca, 0x07, 0x72, 0x0b, 0x90, 0x1c, 0x72, 0x0a, 0x02, 0x18, 0x3e, 0x1a, 0x39, 0x05, 0x3e,
+	0x18, 0x81, 0xf5, 0x17, 0xe4, 0x18, 0x20, 0x08, 0x82, 0xea, 0x8f, 0x99, 0xe9, 0x99, 0x59, 0x4a,
+	0x72, 0x02, 0xe4, 0xc4, 0xa9, 0x5f, 0xff, 0xba, 0xbb, 0xba, 0xbb, 0xaa, 0xba, 0xba, 0x96, 0x70,
+	0x29, 0x38, 0x1e, 0x6e, 0x70, 0x36, 0x0e, 0xfc, 0xa0, 0x27, 0xff, 0xae, 0x07, 0xa1, 0xcf, 0x7d,
+	0x32, 0xab, 0xc0, 0xf6, 0x12, 0x0f, 0x69, 0x9f, 0x6d, 0x9c, 0x5c, 0xdb, 0x10, 0x1f, 0xb2, 0xb9,
+	0xbd, 0xdc, 0xf7, 0xc7, 0x63, 0xdf, 0x43, 0x58, 0x7e, 0x29, 0xfc, 0xf9, 0xa1, 0xcb, 0x8f, 0x26,
+	0xbd, 0xf5, 0xbe, 0x3f, 0xde, 0x18, 0xfa, 0x43, 0x7f, 0x43, 0xc0, 0xbd, 0xc9, 0xa1, 0x90, 0x84,
+	0x20, 0xbe, 0x24, 0xdd, 0xfe, 0xbe, 0x05, 0xcd, 0x3b, 0x38, 0xec, 0xce, 0xe9, 0xde, 0xae, 0xc3,
+	0xde, 0x9f, 0xb0, 0x88, 0x93, 0x16, 0xcc, 0x8a, 0xa9, 0xf6, 0x76, 0x5b, 0xd6, 0xaa, 0xb5, 0xd6,
+	0x70, 0xb4, 0x48, 0x56, 0x00, 0x7a, 0x23, 0xbf, 0x7f, 0xdc, 0xe5, 0x34, 0xe4, 0xad, 0x99, 0x55,
+	0x6b, 0xad, 0xee, 0xa4, 0x10, 0xd2, 0x86, 0x9a, 0x90, 0x6e, 0x7a, 0x83, 0x56, 0x49, 0xb4, 0xc6,
+	0x32, 0x79, 0x1c, 0xea, 0xef, 0x4f, 0x58, 0x78, 0xba, 0xef, 0x0f, 0x58, 0xab, 0x22, 0x1a, 0x13,
+	0xc0, 0xf6, 0x60, 0x31, 0xa5, 0x47, 0x14, 0xf8, 0x5e, 0xc4, 0xc8, 0x93, 0x50, 0x11, 0x33, 0x0b,
+	0x35, 0xe6, 0x3a, 0x0b, 0xeb, 0x6a, 0x4f, 0xd6, 0x05, 0xd5, 0x91, 0x8d, 0xe4, 0x05, 0x98, 0x1d,
+	0x33, 0x1e, 0xba, 0xfd, 0x48, 0x68, 0x34, 0xd7, 0xb9, 0x6c, 0xf2, 0x70, 0xc8, 0x7d, 0x49, 0x70,
+	0x34, 0xd3, 0x26, 0xd0, 0xcc, 0x36, 0xda, 0xcb, 0xb0, 0xf4, 0xda, 0x68, 0x12, 0x1d, 0xb1, 0xc1,
+	0x0e, 0x2a, 0x1d, 0xa9, 0xfd, 0xb0, 0x9f, 0x87, 0x8b, 0x19, 0x5c, 0xe9, 0xb7, 0x04, 0x95, 0x31,
+	0xe3, 0x34, 0x6a, 0x59, 0xab, 0xa5, 0xb5, 0x86, 0x23, 0x05, 0xfb, 0x9f, 0x33, 0x30, 0xdf, 0x65,
+	0x34, 0xec, 0x1f, 0xe9, 0x0d, 0x7d, 0x19, 0xca, 0x77, 0xe8, 0x50, 0xd2, 0xe6, 0x3a, 0xab, 0xb1,
+	0x7a, 0x06, 0x6b, 0x1d, 0x29, 0x37, 0x3d, 0x1e, 0x9e, 0xee, 0x94, 0x3f, 0xf9, 0xfc, 0xca, 0x39,
+	0x47, 0xf4, 0x21, 0x4f, 0xc2, 0xfc, 0xbe, 0xeb, 0xed, 0x4e, 0x42, 0xca, 0x5d, 0xdf, 0xdb, 0x97,
+	0x6b, 0x9c, 0x77, 0x4c, 0x50, 0xb0, 0xe8, 0xbd, 0x14, 0xab, 0xa4, 0x58, 0x69, 0x10, 0xf5, 0xbd,
+	0xed, 0x8e, 0x5d, 0xde, 0x2a, 0x8b, 0x56, 0x29, 0x20, 0x1a, 0x89, 0xf3, 0xac, 0x48, 0x54, 0x08,
+	0xa4, 0x09, 0x25, 0xe6, 0x0d, 0x5a, 0x55, 0x81, 0xe1, 0x27, 0xf2, 0xde, 0xc1, 0xf3, 0x6a, 0xd5,
+	0xc4, 0xe1, 0x49, 0x81, 0xac, 0xc1, 0xf9, 0x6e, 0x40, 0xbd, 0xe8, 0x80, 0x85, 0xf8, 0xb7, 0xcb,
+	0x78, 0xab, 0x2e, 0xfa, 0x64, 0x61, 0x34, 0xab, 0x9b, 0xf7, 0x82, 0x11, 0x75, 0xbd, 0x16, 0xac,
+	0x5a, 0x6b, 0x35, 0x47, 0x8b, 0x38, 0x72, 0x97, 0x9e, 0xb0, 0x41, 0x6b, 0x4e, 0x8e, 0x2c, 0x84,
+	0xf6, 0x8b, 0x50, 0x8f, 0xb7, 0x04, 0xd5, 0x39, 0x66, 0xa7, 0xc2, 0x10, 0xea, 0x0e, 0x7e, 0x62,
+	0xa7, 0x13, 0x3a, 0x9a, 0x30, 0x65, 0x86, 0x52, 0x78, 0x79, 0xe6, 0x25, 0xcb, 0xfe, 0xb8, 0x04,
+	0x44, 0x6e, 0xad, 0x38, 0x2f, 0x7d, 0x0a, 0xd7, 0xa1, 0x1e, 0xe9, 0x0d, 0x57, 0x16, 0xb5, 0x5c,
+	0x7c, 0x14, 0x4e, 0x42, 0x44, 0xad, 0x85, 0x09, 0xef, 0xed, 0xaa, 0x89, 0xb4, 0x88, 0x06, 0x2d,
+	0xb6, 0xea, 0x80, 0x0e, 0x99, 0xda, 0xef, 0x04, 0xc0, 0x13, 0x09, 0xe8, 0x90, 0x45, 0x77, 0x7c,
+	0x39, 0xb4, 0xda, 0x73, 0x13, 0x44, 0x87, 0x61, 0x5e, 0xdf, 0x1f, 0xb8, 0xde, 0x50, 0xf9, 0x44,
+	0x2c, 0xe3, 0x08, 0xae, 0x37, 0x60, 0xf7, 0x70, 0xb8, 0xae, 0xfb, 0x6d, 0xa6, 0xce, 0xc2, 0x04,
+	0x89, 0x0d, 0x0d, 0xee, 0x73, 0x3a, 0x72, 0x58, 0xdf, 0x0f, 0x07, 0x51, 0x6b, 0x56, 0x90, 0x0c,
+	0x0c, 0x39, 0x03, 0xca, 0xe9, 0x4d, 0x3d, 0x93, 0x3c, 0x40, 0x03, 0xc3, 0x75, 0x9e, 0xb0, 0x30,
+	0x72, 0x7d, 0x4f, 0x9c, 0x5f, 0xdd, 0xd1, 0x22, 0x21, 0x50, 0x8e, 0x70, 0x7a, 0x3c, 0xb4, 0xb2,
+	0x23, 0xbe, 0x31, 0x10, 0x1c, 0xfa, 0x3e, 0x67, 0xa1, 0x50, 0x6c, 0x4e, 0xcc, 0x99, 0x42, 0xc8,
+	0x2e, 0x34, 0x07, 0x6c, 0xe0, 0xf6, 0x29, 0x67, 0x83, 0x1b, 0xfe, 0x68, 0x32, 0xf6, 0xa2, 0x56,
+	0x43, 0x58, 0x7f, 0x2b, 0xde, 0xf2, 0x5d, 0x93, 0xe0, 0xe4, 0x7a, 0xd8, 0x6f, 0xc3, 0xf9, 0x0c,
+	0x49, 0x18, 0x6b, 0xdf, 0x0f, 0x98, 0xb2, 0x04, 0x29, 0xa0, 0x8a, 0x1e, 0x1d, 0x6b, 0x53, 0x10,
+	0xdf, 0x88, 0xf1, 0xd3, 0x80, 0xa9, 0x38, 0x24, 0xbe, 0xed, 0x5f, 0x5b, 0xb0, 0xa0, 0x4f, 0x5a,
+	0xf9, 0xf0, 0x75, 0xa8, 0x8a, 0x30, 0xa2, 0xbd, 0xf3, 0x71, 0x33, 0x78, 0x48, 0xf6, 0x3e, 0xe3,
+	0x14, 0x77, 0xcb, 0x51, 0x5c, 0x72, 0x35, 0x1b, 0x73, 0xb2, 0x96, 0x94, 0x0d, 0x38, 0xd8, 0x83,
+	0x29, 0xeb, 0x2f, 0x15, 0xf6, 0x50, 0xce, 0xe0, 0x68, 0x9a, 0xfd, 0x83, 0x12, 0xcc, 0x1b, 0x4d,
+	0x68, 0x2d, 0x81, 0x1b, 0xb0, 0x91, 0xeb, 0x31, 0xa1, 0x6d, 0xdd, 0x89, 0x65, 0xb2, 0x05, 0xd0,
+	0xf7, 0xbd, 0x81, 0x8b, 0xae, 0x8e, 0x4a, 0x95, 0x8c, 0x40, 0xa8, 0x46, 0xb8, 0xa1, 0x19, 0x4e,
+	0x8a, 0x8c, 0x86, 0x46, 0x47, 0xa3, 0x1b, 0x49, 0xef, 0x92, 0x70, 0x4f, 0x13, 0x24, 0xfb, 0xb0,
+	0x14, 0x31, 0xec, 0x75, 0x40, 0xa3, 0x28, 0x45, 0x2e, 0x3f, 0x68, 0xaa, 0xc2, 0x6e, 0x38, 0x69,
+	0x74, 0xec, 0x06, 0x81, 0x0e, 0xaa, 0x2a, 0xfa, 0x98, 0x20, 0x79, 0x0a, 0x16, 0xa4, 0x2b, 0xc6,
+	0x34, 0xe9, 0x04, 0x19, 0x54, 0x8c, 0xa6, 0x10, 0xf4, 0x0c, 0xed, 0x06, 0x26, 0x48, 0xfe, 0x1f,
+	0xea, 0x2e, 0x67, 0x21, 0xe5, 0x7e, 0x18, 0xb5, 0x6a, 0x19, 0x73, 0x54, 0x7a, 0xef, 0x29, 0x82,
+	0x93, 0x50, 0xed, 0xef, 0x40, 0x33, 0xbb, 0x2a, 0xf4, 0x7e, 0xca, 0x79, 0xe8, 0xf6, 0x26, 0x5c,
+	0x1b, 0x63, 0x02, 0x90, 0x05, 0x98, 0xf1, 0x03, 0x65, 0x8e, 0x33, 0x7e, 0x80, 0x27, 0xe7, 0x07,
+	0x2c, 0xa4, 0xde, 0x00, 0x77, 0x57, 0x9c, 0x9c, 0x96, 0xd1, 0x97, 0x02, 0x71, 0xbb, 0xec, 0xfa,
+	0x1f, 0x78, 0x22, 0x4c, 0xd4, 0x9c, 0x14, 0x62, 0xff, 0x71, 0x06, 0xce, 0x67, 0x94, 0x23, 0xcb,
+	0x50, 0xed, 0x0b, 0x87, 0x50, 0x53, 0x2b, 0x89, 0x5c, 0x87, 0x8b, 0xae, 0x17, 0x05, 0xac, 0x1f,
+	0x7b, 0xcc, 0x8d, 0xa3, 0x89, 0x77, 0x2c, 0xad, 0xb4, 0xe4, 0x14, 0x37, 0x92, 0x67, 0xa1, 0x79,
+	0xcc, 0x02, 0x6e, 0x74, 0x28, 0x89, 0x0e, 0x39, 0x1c, 0x4f, 0x24, 0x1e, 0x44, 0x6e, 0x75, 0x59,
+	0x30, 0x33, 0x28, 0xee, 0x0f, 0xf6, 0x95, 0x94, 0x8a, 0xa0, 0x24, 0x00, 0xde, 0x1a, 0x31, 0xff,
+	0x2e, 0x06, 0x6e, 0x79, 0xb0, 0x25, 0x27, 0x0b, 0xe3, 0xee, 0x60, 0x37, 0x45, 0x9a, 0x15, 0xa4,
+	0x14, 0x82, 0x27, 0x3f, 0x50, 0x37, 0xdc, 0x5b, 0xd4, 0xf3, 0x23, 0x11, 0xdc, 0x4a, 0x8e, 0x09,
+	0xda, 0xbf, 0x98, 0x81, 0x0b, 0x05, 0xfe, 0x9c, 0x4d, 0x75, 0xea, 0x49, 0xaa, 0xb3, 0x06, 0xe7,
+	0x43, 0xdf, 0xe7, 0x5d, 0x16, 0x9e, 0xb8, 0x7d, 0xf6, 0x56, 0x12, 0x5d, 0xb2, 0x30, 0x6a, 0x80,
+	0x90, 0x18, 0x5e, 0xf0, 0x64, 0xc4, 0x31, 0x41, 0xf2, 0x1c, 0x2c, 0x8a, 0xcb, 0xe1, 0x8e, 0x3b,
+	0x66, 0xef, 0x7a, 0xee, 0x3d, 0xd4, 0x4b, 0x6c, 0x5d, 0xd9, 0xc9, 0x37, 0xe0, 0xaa, 0x07, 0xc9,
+	0x65, 0x2e, 0x5d, 0x23, 0x85, 0x90, 0x67, 0x61, 0x36, 0x52, 0xb7, 0x6d, 0x55, 0x44, 0x93, 0x66,
+	0x12, 0x4d, 0x24, 0xee, 0x68, 0x02, 0x79, 0x0e, 0x6a, 0xea, 0x13, 0xf7, 0xaf, 0x54, 0x48, 0x8e,
+	0x19, 0xf6, 0xf7, 0x2c, 0x98, 0x55, 0x28, 0x79, 0x02, 0x2a, 0x88, 0xeb, 0xd0, 0x38, 0x6f, 0x74,
+	0x73, 0x64, 0x1b, 0x6e, 0xe1, 0x98, 0x72, 0x74, 0x32, 0x95, 0x9a, 0x68, 0x91, 0xbc, 0x02, 0x10,
+	0x7b, 0x84, 0x34, 0xfb, 0xb9, 0xce, 0x63, 0xf1, 0x18, 0x2a, 0x6d, 0x3d, 0xb9, 0xb6, 0xfe, 0x26,
+	0x3b, 0x15, 0x87, 0xe9, 0xa4, 0xe8, 0xf6, 0x9f, 0x2c, 0x28, 0xe3, 0x34, 0x68, 0xea, 0x38, 0x51,
+	0x7c, 0x42, 0x4a, 0x2a, 0x8c, 0xf9, 0x85, 0x9b, 0x5c, 0x9a, 0xb6, 0xc9, 0x39, 0xd3, 0x91, 0xc7,
+	0x61, 0x82, 0x99, 0x55, 0x54, 0x1e, 0x6d, 0x15, 0x7f, 0xb3, 0x60, 0xde, 0xb8, 0x10, 0x0c, 0xcb,
+	0xbf, 0xa3, 0x2f, 0x1e, 0x91, 0x2f, 0x65, 0x60, 0xc3, 0xd3, 0x76, 0x4e, 0x71, 0xf2, 0x19, 0xa1,
+	0x5f, 0x06, 0x25, 0xab, 0x30, 0x27, 0x6e, 0x7b, 0x15, 0x20, 0x65, 0x26, 0x92, 0x86, 0x70, 0xa1,
+	0x7d, 0x7f, 0x1c, 0x8c, 0x18, 0x67, 0x83, 0x37, 0xfc, 0x5e, 0xa4, 0x73, 0x11, 0x03, 0x44, 0x8f,
+	0x15, 0x9d, 0x04, 0x43, 0x9a, 0x5c, 0x02, 0xa0, 0xde, 0xc9, 0x90, 0x52, 0x9d, 0xaa, 0x50, 0x27,
+	0x0b, 0xdb, 0xcf, 0xc0, 0xa2, 0x5c, 0x32, 0x66, 0x6f, 0x3a, 0xf9, 0x2a, 0xbc, 0xb7, 0xed, 0xab,
+	0x40, 0xd2, 0x54, 0x75, 0x25, 0xb7, 0xa1, 0xc6, 0xe9, 0x10, 0xbd, 0x26, 0xd2, 0xd7, 0x9c, 0x96,
+	0xed, 0x37, 0x60, 0x29, 0xe9, 0x71, 0xb7, 0x13, 0xf7, 0xe9, 0x40, 0x55, 0x0c, 0xa9, 0x6d, 0xb5,
+	0x9d, 0xb9, 0x5d, 0x25, 0xbd, 0x8b, 0x14, 0x47, 0x31, 0xed, 0x57, 0x60, 0x31, 0xd7, 0x18, 0x9b,
+	0x95, 0x95, 0x49, 0x25, 0xe8, 0x50, 0xde, 0xaa, 0x98, 0x4a, 0xd0, 0x61, 0x64, 0xbf, 0x0e, 0xcb,
+	0x71, 0x67, 0x19, 0x8a, 0xd2, 0xcf, 0x27, 0xa9, 0x6e, 0x1c, 0x53, 0xa4, 0x88, 0x9b, 0x20, 0x5e,
+	0x3c, 0x3a, 0x65, 0x15, 0x82, 0xfd, 0x22, 0x5c, 0xca, 0x8d, 0xa4, 0x56, 0x85, 0x47, 0xa2, 0x41,
+	0xb5, 0x15, 0x09, 0x60, 0x5f, 0x87, 0x9a, 0xee, 0x12, 0x67, 0x3b, 0x56, 0x92, 0xed, 0x14, 0x67,
+	0xc8, 0xf6, 0x6d, 0xb8, 0x9c, 0x99, 0x2e, 0xb5, 0x8d, 0x1b, 0xd9, 0x09, 0xe7, 0x3a, 0x8b, 0x49,
+	0x42, 0xa4, 0x5a, 0xd2, 0x3a, 0xec, 0x40, 0x45, 0x98, 0x2b, 0xd9, 0x82, 0xd9, 0x9e, 0xf0, 0x7b,
+	0xdd, 0xef, 0x4a, 0xdc, 0x4f, 0xbe, 0x5b, 0x4f, 0xae, 0xad, 0x3b, 0x2c, 0xf2, 0x27, 0x61, 0x9f,
+	0x89, 0x97, 0x81, 0xa3, 0xf9, 0xf6, 0x02, 0x34, 0x0e, 0x26, 0x51, 0x9c, 0x92, 0xd9, 0x3f, 0xb6,
+	0xe0, 0xe2, 0x9d, 0x90, 0x7a, 0xd1, 0x21, 0x0b, 0xa5, 0x2f, 0xe8, 0xad, 0x7d, 0x0a, 0x16, 0x0e,
+	0x43, 0x7f, 0xbc, 0xe7, 0x0d, 0x59, 0xc4, 0x59, 0x18, 0xc7, 0x84, 0x0c, 0x2a, 0x2c, 0x88, 0x79,
+	0xd4, 0xe3, 0x71, 0xd6, 0x1e, 0xcb, 0xe9, 0x90, 0x5f, 0x32, 0x5f, 0xb7, 0x2d, 0x98, 0x8d, 0xd8,
+	0x70, 0xcc, 0x3c, 0xf9, 0x40, 0x6a, 0x38, 0x5a, 0xb4, 0x5b, 0xb0, 0x9c, 0x55, 0x48, 0xe9, 0xfa,
+	0x73, 0x0b, 0x9a, 0xa8, 0xbc, 0x30, 0x7d, 0xad, 0xe6, 0xf3, 0x71, 0x4e, 0x89, 0x16, 0xd3, 0xd8,
+	0xb9, 0x88, 0xef, 0xb9, 0xcf, 0x3e, 0xbf, 0x32, 0x7f, 0x10, 0x32, 0x3a, 0x1a, 0xf9, 0x7d, 0xc9,
+	0x56, 0x24, 0xf2, 0x34, 0x94, 0x5c, 0x95, 0x17, 0x4c, 0xe5, 0x22, 0x83, 0x6c, 0x02, 0xc8, 0x84,
+	0x66, 0x97, 0x72, 0xda, 0x2a, 0x9f, 0xc5, 0x4f, 0x11, 0xed, 0x7d, 0xa9, 0xa2, 0xdc, 0x75, 0xa5,
+	0xe2, 0xbf, 0x71, 0x5c, 0x4f, 0x02, 0xa8, 0xa7, 0x33, 0x46, 0x9f, 0x65, 0x23, 0x7f, 0x6e, 0xe8,
+	0x45, 0xd9, 0x5f, 0x81, 0xfa, 0x6d, 0xd7, 0x3b, 0xee, 0x8e, 0xdc, 0x3e, 0x23, 0xd7, 0xa0, 0x32,
+	0x72, 0xbd, 0x63, 0xc9, 0x49, 0x87, 0xcf, 0x78, 0x2e, 0x9c, 0x63, 0x1d, 0x3b, 0x38, 0x92, 0x69,
+	0x7f, 0xd7, 0x02, 0x82, 0xa0, 0x4e, 0xa4, 0x93, 0x38, 0x22, 0x5d, 0xc8, 0x4a, 0xb9, 0x10, 0x9e,
+	0xdc, 0x30, 0xf4, 0x27, 0xc1, 0x8e, 0x76, 0x2d, 0x2d, 0x22, 0x7f, 0x24, 0x9e, 0xbc, 0xf2, 0x16,
+	0x90, 0x42, 0xf2, 0xe4, 0x2d, 0x17, 0x3c, 0x79, 0x2b, 0xf1, 0x93, 0xd7, 0xfe, 0xa1, 0x05, 0x97,
+	0x53, 0x4a, 0x74, 0x27, 0xe3, 0x31, 0x0d, 0x4f, 0xff, 0x3b, 0xba, 0xfc, 0xca, 0x82, 0x0b, 0xc6,
+	0x86, 0x24, 0x31, 0x82, 0x45, 0xdc, 0x1d, 0xe3, 0x23, 0x49, 0x68, 0x52, 0x73, 0x12, 0x00, 0x5b,
+	0xf1, 0xbe, 0xbc, 0xe1, 0x4f, 0x3c, 0xae, 0xee, 0x8f, 0x04, 0x40, 0x7f, 0x62, 0x61, 0xe8, 0x87,
+	0x5d, 0x8d, 0x28, 0xd5, 0x32, 0x28, 0x59, 0x4f, 0x9e, 0x3b, 0x32, 0xdd, 0x5f, 0x32, 0x52, 0x81,
+	0x5c, 0x75, 0xe5, 0x55, 0x68, 0x38, 0xf4, 0x83, 0xd7, 0xdd, 0x88, 0xfb, 0xc3, 0x90, 0x8e, 0xd1,
+	0x48, 0x7a, 0x93, 0xfe, 0x31, 0xe3, 0x42, 0xc1, 0xb2, 0xa3, 0x24, 0x5c, 0x7b, 0x3f, 0xa5, 0x99,
+	0x14, 0xec, 0x8f, 0x2c, 0x98, 0x4b, 0x0d, 0x4b, 0x76, 0x60, 0x71, 0x44, 0x39, 0xf3, 0xfa, 0xa7,
+	0xef, 0x1d, 0xe9, 0x21, 0x95, 0x25, 0x5d, 0x8c, 0xf5, 0x48, 0xcf, 0xe7, 0x34, 0x15, 0x3f, 0xd1,
+	0x60, 0x1d, 0xaa, 0x11, 0xa7, 0xdc, 0xed, 0xe7, 0xde, 0x6b, 0xc2, 0x96, 0xdf, 0xb9, 0xdd, 0x15,
+	0xad, 0x8e, 0x62, 0xa1, 0xc6, 0x62, 0x0f, 0x22, 0xb5, 0x23, 0x4a, 0xb2, 0xff, 0x6c, 0x9a, 0xa5,
+	0xb2, 0x08, 0x73, 0x9b, 0xad, 0x07, 0x6f, 0xf3, 0xcc, 0x94, 0x6d, 0xd6, 0x4a, 0x96, 0x1e, 0x4a,
+	0xc9, 0x26, 0x94, 0x82, 0xad, 0x2d, 0x95, 0xb6, 0xe0, 0xa7, 0x44, 0x36, 0x5b, 0x15, 0x8d, 0x6c,
+	0x4a, 0xe4, 0xaa, 0xba, 0xab, 0xf1, 0x53, 0x20, 0x9b, 0x57, 0x5b, 0xb3, 0x0a, 0xd9, 0xbc, 0x6a,
+	0x7f, 0x1d, 0xda, 0x45, 0x56, 0xae, 0x0c, 0x6c, 0x0b, 0xea, 0x91, 0x80, 0x5c, 0x96, 0x77, 0xe0,
+	0x82, 0x7e, 0x09, 0xdb, 0xfe, 0xa9, 0x05, 0xf3, 0x86, 0xea, 0xc6, 0x3d, 0x55, 0x51, 0xf7, 0x54,
+	0x03, 0x2c, 0x4f, 0x3d, 0x50, 0x2c, 0x0f, 0xa5, 0x43, 0xb1, 0x7e, 0xcb, 0xb1, 0x0e, 0x51, 0x92,
+	0xe9, 0x4a, 0xdd, 0xb1, 0x22, 0x94, 0x7a, 0x62, 0x71, 0x35, 0xc7, 0xea, 0xa1, 0x34, 0x50, 0x0b,
+	0xb3, 0x06, 0x22, 0x4f, 0xe4, 0x94, 0x4f, 0xe4, 0x23, 0xa1, 0xe2, 0x28, 0x09, 0x67, 0x3c, 0x76,
+	0xbd, 0x81, 0x78, 0x17, 0x54, 0x1c, 0xf1, 0x6d, 0x7f, 0x68, 0xc1, 0xa2, 0x28, 0x5f, 0x39, 0xd4,
+	0x1b, 0xb2, 0xb3, 0xfd, 0x39, 0xf6, 0x4f, 0x65, 0xa3, 0x86, 0x7f, 0x4a, 0xe3, 0xc0, 0x4f, 0x9c,
+	0x27, 0xe2, 0x2c, 0x50, 0xa7, 0x21, 0xbe, 0x31, 0xee, 0x89, 0x6a, 0x91, 0xd0, 0xd9, 0xd8, 0xb6,
+	0x5c, 0x79, 0xca, 0x91, 0x4c, 0x3b, 0x02, 0x92, 0xd6, 0x4c, 0x9d, 0xc1, 0xff, 0x41, 0x35, 0x62,
+	0xa9, 0x03, 0xb8, 0x90, 0x58, 0x86, 0x3b, 0x66, 0x5d, 0xd1, 0xe4, 0x28, 0xca, 0xa3, 0x17, 0x27,
+	0xec, 0xaf, 0x41, 0xb5, 0x4b, 0x31, 0x17, 0x14, 0xc9, 0xa4, 0x3b, 0x66, 0x11, 0xa7, 0xe3, 0x60,
+	0x5f, 0xa6, 0xa6, 0x25, 0x27, 0x0d, 0x99, 0x59, 0x85, 0xa5, 0xb3, 0x8a, 0x0f, 0x2d, 0x80, 0x44,
+	0x15, 0xb2, 0x05, 0xd5, 0x11, 0xed, 0xb1, 0x51, 0xde, 0x60, 0xf2, 0x09, 0xb3, 0x2a, 0x77, 0xaa,
+	0x0e, 0x64, 0x03, 0x66, 0x23, 0xa1, 0x8b, 0xae, 0x62, 0x9c, 0x4f, 0xb4, 0x17, 0xb8, 0xe2, 0x6b,
+	0x96, 0x78, 0x3f, 0x87, 0xfe, 0xf8, 0xb6, 0x9c, 0x4f, 0x3e, 0xbe, 0x52, 0x88, 0xfd, 0x4b, 0x4b,
+	0xd5, 0x96, 0x77, 0xdd, 0xc3, 0xc3, 0x78, 0x47, 0x9f, 0x36, 0xdf, 0x36, 0x8b, 0x86, 0x45, 0x0b,
+	0xa6, 0x6c, 0xc7, 0xe2, 0x99, 0x7a, 0xd0, 0x74, 0x05, 0x5f, 0x3e, 0x72, 0x0c, 0x0c, 0x39, 0x82,
+	0xfc, 0xb6, 0x37, 0x3a, 0xdd, 0xf3, 0xb6, 0x55, 0x0e, 0x6e, 0x60, 0x19, 0xce, 0x8e, 0x0a, 0xf7,
+	0x06, 0x66, 0x7f, 0x3c, 0x03, 0x35, 0x3d, 0x3f, 0x1a, 0x54, 0x40, 0xf9, 0x91, 0x4e, 0xe9, 0xf0,
+	0x1b, 0x8f, 0x27, 0xca, 0xbd, 0x48, 0xd3, 0x50, 0x9c, 0xbf, 0x96, 0x52, 0xf9, 0x6b, 0x4b, 0xbe,
+	0x16, 0xf7, 0x76, 0xb7, 0x95, 0x2b, 0x69, 0x31, 0x69, 0xd9, 0x51, 0xe5, 0x47, 0x2d, 0x62, 0xcc,
+	0x32, 0xde, 0x41, 0xdb, 0xca, 0xd3, 0x32, 0x68, 0x8e, 0xb7, 0xa3, 0x02, 0x4b, 0x06, 0x25, 0xeb,
+	0x40, 0x34, 0xb2, 0xcb, 0x46, 0x9c, 0xa6, 0x1f, 0xeb, 0x05, 0x2d, 0xe4, 0x55, 0xe3, 0xd9, 0x55,
+	0x5f, 0x2d, 0x19, 0x76, 0xbc, 0xad, 0x9b, 0x70, 0xa7, 0x94, 0x41, 0xa4, 0xdf, 0x5d, 0x1f, 0xc0,
+	0xbc, 0x41, 0x29, 0xa8, 0x1f, 0x3f, 0x03, 0x16, 0x55, 0xfe, 0x51, 0x64, 0x9d, 0xdb, 0x9e, 0x7a,
+	0xce, 0x59, 0x14, 0xa9, 0xbd, 0x56, 0xe9, 0x21, 0xa8, 0x3d, 0xfb, 0x67, 0x96, 0x2e, 0x34, 0x3c,
+	0x4c, 0xae, 0xf0, 0xb0, 0xb1, 0x25, 0xce, 0x1c, 0x54, 0x8e, 0x20, 0x84, 0x2f, 0x13, 0x5d, 0x3e,
+	0xb2, 0x60, 0xc9, 0x54, 0x2f, 0x4e, 0xfc, 0x2b, 0x9e, 0x3f, 0x88, 0xe3, 0x4b, 0xe6, 0x27, 0x14,
+	0xc5, 0x7e, 0xcb, 0x1f, 0x30, 0x47, 0xf2, 0xd0, 0xeb, 0x44, 0xf2, 0x96, 0xdc, 0x67, 0xf3, 0x4e,
+	0x0a, 0x49, 0x07, 0xa1, 0xd2, 0xc3, 0x05, 0xa1, 0x3f, 0xcc, 0x40, 0x33, 0x3b, 0xdb, 0x7f, 0xd0,
+	0x09, 0xe2, 0x5c, 0xa3, 0x9c, 0xca, 0x35, 0x70, 0x19, 0xe2, 0x12, 0x96, 0xcb, 0x90, 0xf7, 0x66,
+	0x0a, 0x11, 0xd9, 0x15, 0x4a, 0x0e, 0xe5, 0xb2, 0x00, 0x6f, 0x39, 0x09, 0x90, 0xbf, 0x4a, 0xf5,
+	0x75, 0x5b, 0x33, 0xaf, 0xdb, 0xad, 0xcd, 0x56, 0x5d, 0x23, 0x9b, 0xfa, 0xda, 0x86, 0xe4, 0xda,
+	0xde, 0x86, 0x5c, 0xc6, 0xd2, 0x9a, 0x7b, 0xa4, 0x04, 0xc7, 0xfe, 0x8d, 0x05, 0x97, 0xc5, 0xee,
+	0xdd, 0x08, 0x5d, 0xee, 0xf6, 0xe9, 0xe8, 0x80, 0xf2, 0xa4, 0xca, 0xfd, 0x12, 0xd4, 0xd4, 0x5b,
+	0x26, 0x5f, 0xe7, 0x4e, 0x77, 0xe8, 0x4a, 0x92, 0x13, 0xb3, 0xd1, 0x30, 0x22, 0x15, 0xf7, 0x4c,
+	0xc3, 0x30, 0xba, 0xa5, 0xea, 0x41, 0xb9, 0xaa, 0x4a, 0xa9, 0xa0, 0xaa, 0x62, 0xff, 0xde, 0x82,
+	0x0b, 0x05, 0x13, 0x4f, 0xad, 0xf6, 0x7c, 0xb9, 0x33, 0x7f, 0xb4, 0xa2, 0x5b, 0x4e, 0xf3, 0x4a,
+	0x91, 0xe6, 0x9f, 0xcd, 0x40, 0x33, 0xbb, 0xf6, 0xa9, 0x6a, 0xdb, 0xd0, 0x08, 0x68, 0xc8, 0x3c,
+	0xde, 0x95, 0xad, 0x52, 0x6f, 0x03, 0xcb, 0x2e, 0xad, 0x34, 0x7d, 0x69, 0xe5, 0x07, 0x2d, 0xad,
+	0xf2, 0xd0, 0x4b, 0xab, 0x16, 0x2c, 0x4d, 0x56, 0xd1, 0x47, 0x87, 0xd8, 0x53, 0xb2, 0xa4, 0x61,
+	0x9b, 0x20, 0xce, 0xdc, 0x3f, 0x72, 0x47, 0x83, 0x90, 0x79, 0x09, 0x53, 0x1a, 0x7c, 0xbe, 0x41,
+	0xb0, 0x53, 0xbb, 0x25, 0xd9, 0x75, 0xc5, 0xce, 0x36, 0xd8, 0x2f, 0xc0, 0xa5, 0x38, 0x6e, 0x1f,
+	0x84, 0xfe, 0xa1, 0x3b, 0x62, 0xa9, 0xb2, 0x8a, 0xda, 0x13, 0x5d, 0x56, 0x51, 0xa2, 0xfd, 0x0d,
+	0x68, 0xe5, 0x3b, 0x29, 0xc3, 0x7f, 0x15, 0x6a, 0x8a, 0x56, 0xf4, 0xf3, 0xab, 0x68, 0xc8, 0xf5,
+	0x8d, 0x7b, 0xd8, 0x3f, 0xb2, 0xb0, 0x36, 0x53, 0xc8, 0x9a, 0xae, 0xcf, 0x03, 0xde, 0x5c, 0x5b,
+	0x05, 0x55, 0xd1, 0xcb, 0xf9, 0x8b, 0x4d, 0x2b, 0x93, 0xbe, 0xd5, 0x7e, 0x67, 0x41, 0x33, 0xa7,
+	0x47, 0xf1, 0x2f, 0x62, 0xea, 0xbe, 0x9b, 0x31, 0x7e, 0x2f, 0xc5, 0x6c, 0x5b, 0xff, 0xfe, 0x20,
+	0x85, 0x29, 0x51, 0x71, 0x09, 0x2a, 0xbd, 0x53, 0xce, 0xb4, 0x07, 0x48, 0x01, 0x0d, 0xb5, 0x4f,
+	0xc3, 0x81, 0xeb, 0xd1, 0x91, 0xcb, 0x4f, 0x95, 0x09, 0xa5, 0x21, 0xe1, 0x06, 0xc7, 0x8c, 0xf7,
+	0x8f, 0x84, 0xe5, 0x34, 0x1c, 0x25, 0x75, 0xee, 0x5b, 0x50, 0xc5, 0x12, 0x04, 0x0b, 0xc9, 0x57,
+	0xa1, 0x1e, 0xd7, 0x4b, 0x48, 0xb2, 0xee, 0x6c, 0x0d, 0xa5, 0x7d, 0xd1, 0x68, 0x8a, 0xeb, 0x2d,
+	0xe7, 0xc8, 0x36, 0xcc, 0xc5, 0xe4, 0xbb, 0x9d, 0x2f, 0x35, 0xc4, 0xbb, 0xb0, 0x60, 0x96, 0x73,
+	0xc8, 0x4a, 0xfa, 0xbe, 0xcb, 0x17, 0x9e, 0xda, 0x57, 0xa6, 0xb6, 0xeb, 0x41, 0xd7, 0xac, 0xce,
+	0x3f, 0x2c, 0x68, 0xaa, 0x5b, 0xed, 0x16, 0xf3, 0xd4, 0x2f, 0x35, 0x6a, 0xbd, 0x32, 0x4f, 0x34,
+	0x95, 0x4d, 0x17, 0x64, 0xa6, 0x2b, 0xbb, 0x07, 0x70, 0x8b, 0x71, 0x35, 0x2e, 0x29, 0x7c, 0x79,
+	0xe9, 0x31, 0x1e, 0x2f, 0x6e, 0x8c, 0x87, 0xfa, 0x16, 0x5c, 0xb8, 0xc5, 0x78, 0xce, 0x82, 0x56,
+	0xa7, 0x5b, 0x9f, 0x1a, 0xf8, 0x7f, 0xcf, 0x60, 0xe8, 0xd1, 0x3b, 0x7f, 0x2f, 0xc3, 0x2c, 0x3e,
+	0x5d, 0x5c, 0x16, 0x92, 0xd7, 0x61, 0xfe, 0x35, 0xd7, 0x1b, 0xc4, 0xff, 0x62, 0x41, 0x0a, 0xfe,
+	0x27, 0x43, 0x0f, 0xde, 0x2e, 0x6a, 0x4a, 0x1d, 0x77, 0x43, 0xff, 0x62, 0xdb, 0x17, 0x17, 0x44,
+	0xf1, 0x4f, 0xf6, 0xed, 0x4b, 0x39, 0x3c, 0x1e, 0xe2, 0x26, 0xcc, 0xa5, 0x32, 0x22, 0x72, 0x56,
+	0x9e, 0x74, 0xd6, 0x30, 0xb7, 0x00, 0x92, 0x72, 0x31, 0x29, 0x2a, 0x30, 0xeb, 0x41, 0x1e, 0x2b,
+	0x6c, 0x8b, 0x07, 0x7a, 0x13, 0x1a, 0x09, 0x7e, 0xb7, 0x73, 0xe6, 0x50, 0xff, 0x53, 0x58, 0xc7,
+	0x4e, 0x0d, 0x76, 0x17, 0xce, 0x67, 0xca, 0xb9, 0xe4, 0x4a, 0xbe, 0x8f, 0x51, 0xa1, 0x6e, 0xaf,
+	0x4e, 0x27, 0xc4, 0xe3, 0x7e, 0x13, 0x16, 0x33, 0x8d, 0x77, 0x3b, 0x0f, 0x1e, 0xd9, 0x9e, 0x46,
+	0x30, 0x74, 0x3e, 0x80, 0x79, 0xe3, 0x1f, 0x6a, 0x48, 0xb2, 0xca, 0xa2, 0x7f, 0xc0, 0x69, 0xaf,
+	0x4c, 0x6b, 0x8e, 0x6d, 0xef, 0x6d, 0x68, 0x76, 0x79, 0xc8, 0xe8, 0xd8, 0xf5, 0x86, 0xda, 0x06,
+	0x5f, 0x81, 0xaa, 0x54, 0xe2, 0x91, 0x6d, 0xe6, 0xaa, 0xd5, 0xf9, 0xad, 0x05, 0xb3, 0xda, 0xe7,
+	0xde, 0x2b, 0x2c, 0xf9, 0xd8, 0x67, 0xd5, 0x40, 0xd4, 0x04, 0x4f, 0x9c, 0xc9, 0x49, 0x5b, 0x56,
+	0xf2, 0xe6, 0x4f, 0x99, 0x43, 0xae, 0x44, 0xd1, 0x7e, 0xac, 0xb0, 0x4d, 0x0f, 0xb4, 0xd3, 0xfa,
+	0xe4, 0x8b, 0x15, 0xeb, 0xd3, 0x2f, 0x56, 0xac, 0xbf, 0x7e, 0xb1, 0x62, 0xfd, 0xe4, 0xfe, 0xca,
+	0xb9, 0x4f, 0xef, 0xaf, 0x9c, 0xfb, 0xcb, 0xfd, 0x95, 0x73, 0xbd, 0xaa, 0xf8, 0x7f, 0xaf, 0x17,
+	0xfe, 0x35, 0x00, 0xed, 0x04, 0x54, 0x5e, 0x70, 0x26, 0x00, 0x00,
 }
 
-func (m *SearchTagsResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PusherClient is the client API for Pusher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PusherClient interface {
+	// different versions of PushBytes expect the trace data to be pushed in different formats
+	PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	// TransferTraces streams the traces of a leaving ingester to the ingester claiming its tokens
+	TransferTraces(ctx context.Context, opts ...grpc.CallOption) (Pusher_TransferTracesClient, error)
 }
 
-func (m *SearchTagsResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type pusherClient struct {
+	cc *grpc.ClientConn
 }
 
-func (m *SearchTagsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.TagNames) > 0 {
-		for iNdEx := len(m.TagNames) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.TagNames[iNdEx])
-			copy(dAtA[i:], m.TagNames[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagNames[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+func NewPusherClient(cc *grpc.ClientConn) PusherClient {
+	return &pusherClient{cc}
 }
 
-func (m *SearchTagsV2Response) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *pusherClient) PushBytes(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytes", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
-}
-
-func (m *SearchTagsV2Response) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	return out, nil
 }
 
-func (m *SearchTagsV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Scopes) > 0 {
-		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Scopes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+func (c *pusherClient) PushBytesV2(ctx context.Context, in *PushBytesRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Pusher/PushBytesV2", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *SearchTagsV2Scope) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *pusherClient) TransferTraces(ctx context.Context, opts ...grpc.CallOption) (Pusher_TransferTracesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Pusher_serviceDesc.Streams[0], "/tempopb.Pusher/TransferTraces", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	x := &pusherTransferTracesClient{stream}
+	return x, nil
 }
 
-func (m *SearchTagsV2Scope) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type Pusher_TransferTracesClient interface {
+	Send(*TransferTracesRequest) error
+	CloseAndRecv() (*TransferTracesResponse, error)
+	grpc.ClientStream
 }
 
-func (m *SearchTagsV2Scope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Tags) > 0 {
-		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Tags[iNdEx])
-			copy(dAtA[i:], m.Tags[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.Tags[iNdEx])))
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+type pusherTransferTracesClient struct {
+	grpc.ClientStream
 }
 
-func (m *SearchTagValuesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func (x *pusherTransferTracesClient) Send(m *TransferTracesRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *pusherTransferTracesClient) CloseAndRecv() (*TransferTracesResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	m := new(TransferTracesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func (m *SearchTagValuesRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// PusherServer is the server API for Pusher service.
+type PusherServer interface {
+	// different versions of PushBytes expect the trace data to be pushed in different formats
+	PushBytes(context.Context, *PushBytesRequest) (*PushResponse, error)
+	PushBytesV2(context.Context, *PushBytesRequest) (*PushResponse, error)
+	// TransferTraces streams the traces of a leaving ingester to the ingester claiming its tokens
+	TransferTraces(Pusher_TransferTracesServer) error
 }
 
-func (m *SearchTagValuesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.TagName) > 0 {
-		i -= len(m.TagName)
-		copy(dAtA[i:], m.TagName)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.TagName)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+// UnimplementedPusherServer can be embedded to have forward compatible implementations.
+type UnimplementedPusherServer struct {
 }
 
-func (m *SearchTagValuesResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (*UnimplementedPusherServer) PushBytes(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushBytes not implemented")
 }
-
-func (m *SearchTagValuesResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (*UnimplementedPusherServer) PushBytesV2(ctx context.Context, req *PushBytesRequest) (*PushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushBytesV2 not implemented")
+}
+func (*UnimplementedPusherServer) TransferTraces(srv Pusher_TransferTracesServer) error {
+	return status.Errorf(codes.Unimplemented, "method TransferTraces not implemented")
 }
 
-func (m *SearchTagValuesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.TagValues) > 0 {
-		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.TagValues[iNdEx])
-			copy(dAtA[i:], m.TagValues[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagValues[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
+func RegisterPusherServer(s *grpc.Server, srv PusherServer) {
+	s.RegisterService(&_Pusher_serviceDesc, srv)
+}
+
+func _Pusher_PushBytes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushBytesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	if interceptor == nil {
+		return srv.(PusherServer).PushBytes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Pusher/PushBytes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PusherServer).PushBytes(ctx, req.(*PushBytesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *TagValue) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func _Pusher_PushBytesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushBytesRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(PusherServer).PushBytesV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Pusher/PushBytesV2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PusherServer).PushBytesV2(ctx, req.(*PushBytesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *TagValue) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func _Pusher_TransferTraces_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PusherServer).TransferTraces(&pusherTransferTracesServer{stream})
 }
 
-func (m *TagValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Value)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Type) > 0 {
-		i -= len(m.Type)
-		copy(dAtA[i:], m.Type)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Type)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+type Pusher_TransferTracesServer interface {
+	SendAndClose(*TransferTracesResponse) error
+	Recv() (*TransferTracesRequest, error)
+	grpc.ServerStream
 }
 
-func (m *SearchTagValuesV2Response) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+type pusherTransferTracesServer struct {
+	grpc.ServerStream
+}
+
+func (x *pusherTransferTracesServer) SendAndClose(m *TransferTracesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *pusherTransferTracesServer) Recv() (*TransferTracesRequest, error) {
+	m := new(TransferTracesRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return m, nil
 }
 
-func (m *SearchTagValuesV2Response) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var _Pusher_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.Pusher",
+	HandlerType: (*PusherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PushBytes",
+			Handler:    _Pusher_PushBytes_Handler,
+		},
+		{
+			MethodName: "PushBytesV2",
+			Handler:    _Pusher_PushBytesV2_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TransferTraces",
+			Handler:       _Pusher_TransferTraces_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-func (m *SearchTagValuesV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.TagValues) > 0 {
-		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.TagValues[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+// MetricsGeneratorClient is the client API for MetricsGenerator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MetricsGeneratorClient interface {
+	PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error)
+	GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error)
+	GetAttributeProfile(ctx context.Context, in *AttributeProfileRequest, opts ...grpc.CallOption) (*AttributeProfileResponse, error)
 }
 
-func (m *Trace) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+type metricsGeneratorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMetricsGeneratorClient(cc *grpc.ClientConn) MetricsGeneratorClient {
+	return &metricsGeneratorClient{cc}
+}
+
+func (c *metricsGeneratorClient) PushSpans(ctx context.Context, in *PushSpansRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/PushSpans", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Trace) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	return out, nil
 }
 
-func (m *Trace) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Batches) > 0 {
-		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+func (c *metricsGeneratorClient) GetMetrics(ctx context.Context, in *SpanMetricsRequest, opts ...grpc.CallOption) (*SpanMetricsResponse, error) {
+	out := new(SpanMetricsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *PushResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *metricsGeneratorClient) GetAttributeProfile(ctx context.Context, in *AttributeProfileRequest, opts ...grpc.CallOption) (*AttributeProfileResponse, error) {
+	out := new(AttributeProfileResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.MetricsGenerator/GetAttributeProfile", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *PushResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// MetricsGeneratorServer is the server API for MetricsGenerator service.
+type MetricsGeneratorServer interface {
+	PushSpans(context.Context, *PushSpansRequest) (*PushResponse, error)
+	GetMetrics(context.Context, *SpanMetricsRequest) (*SpanMetricsResponse, error)
+	GetAttributeProfile(context.Context, *AttributeProfileRequest) (*AttributeProfileResponse, error)
 }
 
-func (m *PushResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+// UnimplementedMetricsGeneratorServer can be embedded to have forward compatible implementations.
+type UnimplementedMetricsGeneratorServer struct {
 }
 
-func (m *PushBytesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func (*UnimplementedMetricsGeneratorServer) PushSpans(ctx context.Context, req *PushSpansRequest) (*PushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushSpans not implemented")
+}
+func (*UnimplementedMetricsGeneratorServer) GetMetrics(ctx context.Context, req *SpanMetricsRequest) (*SpanMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
+}
+func (*UnimplementedMetricsGeneratorServer) GetAttributeProfile(ctx context.Context, req *AttributeProfileRequest) (*AttributeProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAttributeProfile not implemented")
+}
+
+func RegisterMetricsGeneratorServer(s *grpc.Server, srv MetricsGeneratorServer) {
+	s.RegisterService(&_MetricsGenerator_serviceDesc, srv)
+}
+
+func _MetricsGenerator_PushSpans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushSpansRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).PushSpans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/PushSpans",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).PushSpans(ctx, req.(*PushSpansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *PushBytesRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func _MetricsGenerator_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpanMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).GetMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/GetMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).GetMetrics(ctx, req.(*SpanMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *PushBytesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.SearchData) > 0 {
-		for iNdEx := len(m.SearchData) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.SearchData[iNdEx].Size()
-				i -= size
-				if _, err := m.SearchData[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
+func _MetricsGenerator_GetAttributeProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttributeProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if len(m.Ids) > 0 {
-		for iNdEx := len(m.Ids) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Ids[iNdEx].Size()
-				i -= size
-				if _, err := m.Ids[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
+	if interceptor == nil {
+		return srv.(MetricsGeneratorServer).GetAttributeProfile(ctx, in)
 	}
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Traces[iNdEx].Size()
-				i -= size
-				if _, err := m.Traces[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.MetricsGenerator/GetAttributeProfile",
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *PushSpansRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsGeneratorServer).GetAttributeProfile(ctx, req.(*AttributeProfileRequest))
 	}
-	return dAtA[:n], nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *PushSpansRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var _MetricsGenerator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.MetricsGenerator",
+	HandlerType: (*MetricsGeneratorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PushSpans",
+			Handler:    _MetricsGenerator_PushSpans_Handler,
+		},
+		{
+			MethodName: "GetMetrics",
+			Handler:    _MetricsGenerator_GetMetrics_Handler,
+		},
+		{
+			MethodName: "GetAttributeProfile",
+			Handler:    _MetricsGenerator_GetAttributeProfile_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-func (m *PushSpansRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Batches) > 0 {
-		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+// QuerierClient is the client API for Querier service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type QuerierClient interface {
+	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error)
+	SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error)
+	SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error)
+	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error)
+	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error)
+	FlushedBlocks(ctx context.Context, in *FlushedBlocksRequest, opts ...grpc.CallOption) (*FlushedBlocksResponse, error)
 }
 
-func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type querierClient struct {
+	cc *grpc.ClientConn
 }
 
-func (m *TraceBytes) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func NewQuerierClient(cc *grpc.ClientConn) QuerierClient {
+	return &querierClient{cc}
 }
 
-func (m *TraceBytes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Traces) > 0 {
-		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Traces[iNdEx])
-			copy(dAtA[i:], m.Traces[iNdEx])
-			i = encodeVarintTempo(dAtA, i, uint64(len(m.Traces[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
+func (c *querierClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error) {
+	out := new(TraceByIDResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/FindTraceByID", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *LinkSlice) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchRecent(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchRecent", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *LinkSlice) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchBlock(ctx context.Context, in *SearchBlockRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (m *LinkSlice) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Links) > 0 {
-		for iNdEx := len(m.Links) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Links[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+func (c *querierClient) SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsResponse, error) {
+	out := new(SearchTagsResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTags", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *SpanMetricsRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (*SearchTagsV2Response, error) {
+	out := new(SearchTagsV2Response)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagsV2", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *SpanMetricsRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesResponse, error) {
+	out := new(SearchTagValuesResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValues", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (m *SpanMetricsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.End != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.End))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Start != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Limit != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.GroupBy) > 0 {
-		i -= len(m.GroupBy)
-		copy(dAtA[i:], m.GroupBy)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0xa
+func (c *querierClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (*SearchTagValuesV2Response, error) {
+	out := new(SearchTagValuesV2Response)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/SearchTagValuesV2", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func (m *SpanMetricsSummaryRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *querierClient) FlushedBlocks(ctx context.Context, in *FlushedBlocksRequest, opts ...grpc.CallOption) (*FlushedBlocksResponse, error) {
+	out := new(FlushedBlocksResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Querier/FlushedBlocks", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *SpanMetricsSummaryRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// QuerierServer is the server API for Querier service.
+type QuerierServer interface {
+	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
+	SearchRecent(context.Context, *SearchRequest) (*SearchResponse, error)
+	SearchBlock(context.Context, *SearchBlockRequest) (*SearchResponse, error)
+	SearchTags(context.Context, *SearchTagsRequest) (*SearchTagsResponse, error)
+	SearchTagsV2(context.Context, *SearchTagsRequest) (*SearchTagsV2Response, error)
+	SearchTagValues(context.Context, *SearchTagValuesRequest) (*SearchTagValuesResponse, error)
+	SearchTagValuesV2(context.Context, *SearchTagValuesRequest) (*SearchTagValuesV2Response, error)
+	FlushedBlocks(context.Context, *FlushedBlocksRequest) (*FlushedBlocksResponse, error)
 }
 
-func (m *SpanMetricsSummaryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.End != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.End))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Start != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
-		i--
-		dAtA[i] = 0x20
+// UnimplementedQuerierServer can be embedded to have forward compatible implementations.
+type UnimplementedQuerierServer struct {
+}
+
+func (*UnimplementedQuerierServer) FindTraceByID(ctx context.Context, req *TraceByIDRequest) (*TraceByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+}
+func (*UnimplementedQuerierServer) SearchRecent(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchRecent not implemented")
+}
+func (*UnimplementedQuerierServer) SearchBlock(ctx context.Context, req *SearchBlockRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchBlock not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTags(ctx context.Context, req *SearchTagsRequest) (*SearchTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTags not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTagsV2(ctx context.Context, req *SearchTagsRequest) (*SearchTagsV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagsV2 not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTagValues(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValues not implemented")
+}
+func (*UnimplementedQuerierServer) SearchTagValuesV2(ctx context.Context, req *SearchTagValuesRequest) (*SearchTagValuesV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTagValuesV2 not implemented")
+}
+func (*UnimplementedQuerierServer) FlushedBlocks(ctx context.Context, req *FlushedBlocksRequest) (*FlushedBlocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushedBlocks not implemented")
+}
+
+func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
+	s.RegisterService(&_Querier_serviceDesc, srv)
+}
+
+func _Querier_FindTraceByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.Limit != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
-		i--
-		dAtA[i] = 0x18
+	if interceptor == nil {
+		return srv.(QuerierServer).FindTraceByID(ctx, in)
 	}
-	if len(m.GroupBy) > 0 {
-		i -= len(m.GroupBy)
-		copy(dAtA[i:], m.GroupBy)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
-		i--
-		dAtA[i] = 0x12
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/FindTraceByID",
 	}
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
-		i--
-		dAtA[i] = 0xa
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).FindTraceByID(ctx, req.(*TraceByIDRequest))
 	}
-	return len(dAtA) - i, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SpanMetricsResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func _Querier_SearchRecent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchRecent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchRecent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchRecent(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SpanMetricsResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func _Querier_SearchBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchBlock(ctx, req.(*SearchBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SpanMetricsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Metrics) > 0 {
-		for iNdEx := len(m.Metrics) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Metrics[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
+func _Querier_SearchTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.ErrorSpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
-		i--
-		dAtA[i] = 0x18
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTags(ctx, in)
 	}
-	if m.SpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
-		i--
-		dAtA[i] = 0x10
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTags",
 	}
-	if m.Estimated {
-		i--
-		if m.Estimated {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x8
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTags(ctx, req.(*SearchTagsRequest))
 	}
-	return len(dAtA) - i, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *RawHistogram) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func _Querier_SearchTagsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTagsV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTagsV2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTagsV2(ctx, req.(*SearchTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *RawHistogram) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func _Querier_SearchTagValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTagValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTagValues",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTagValues(ctx, req.(*SearchTagValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *RawHistogram) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Count != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Count))
-		i--
-		dAtA[i] = 0x10
+func _Querier_SearchTagValuesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTagValuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.Bucket != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Bucket))
-		i--
-		dAtA[i] = 0x8
+	if interceptor == nil {
+		return srv.(QuerierServer).SearchTagValuesV2(ctx, in)
 	}
-	return len(dAtA) - i, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/SearchTagValuesV2",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).SearchTagValuesV2(ctx, req.(*SearchTagValuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SpanMetrics) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+func _Querier_FlushedBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushedBlocksRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	if interceptor == nil {
+		return srv.(QuerierServer).FlushedBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Querier/FlushedBlocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).FlushedBlocks(ctx, req.(*FlushedBlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SpanMetrics) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var _Querier_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.Querier",
+	HandlerType: (*QuerierServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindTraceByID",
+			Handler:    _Querier_FindTraceByID_Handler,
+		},
+		{
+			MethodName: "SearchRecent",
+			Handler:    _Querier_SearchRecent_Handler,
+		},
+		{
+			MethodName: "SearchBlock",
+			Handler:    _Querier_SearchBlock_Handler,
+		},
+		{
+			MethodName: "SearchTags",
+			Handler:    _Querier_SearchTags_Handler,
+		},
+		{
+			MethodName: "SearchTagsV2",
+			Handler:    _Querier_SearchTagsV2_Handler,
+		},
+		{
+			MethodName: "SearchTagValues",
+			Handler:    _Querier_SearchTagValues_Handler,
+		},
+		{
+			MethodName: "SearchTagValuesV2",
+			Handler:    _Querier_SearchTagValuesV2_Handler,
+		},
+		{
+			MethodName: "FlushedBlocks",
+			Handler:    _Querier_FlushedBlocks_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tempopb/tempo.proto",
 }
 
-func (m *SpanMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Errors != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Errors))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.Static != nil {
-		{
-			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.LatencyHistogram) > 0 {
-		for iNdEx := len(m.LatencyHistogram) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.LatencyHistogram[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+// StreamingQuerierClient is the client API for StreamingQuerier service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StreamingQuerierClient interface {
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error)
 }
 
-func (m *SpanMetricsSummary) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type streamingQuerierClient struct {
+	cc *grpc.ClientConn
 }
 
-func (m *SpanMetricsSummary) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func NewStreamingQuerierClient(cc *grpc.ClientConn) StreamingQuerierClient {
+	return &streamingQuerierClient{cc}
 }
 
-func (m *SpanMetricsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.P50 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P50))
-		i--
-		dAtA[i] = 0x38
-	}
-	if m.P90 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P90))
-		i--
-		dAtA[i] = 0x30
-	}
-	if m.P95 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P95))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.P99 != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.P99))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Static != nil {
-		{
-			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTempo(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x1a
+func (c *streamingQuerierClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (StreamingQuerier_SearchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingQuerier_serviceDesc.Streams[0], "/tempopb.StreamingQuerier/Search", opts...)
+	if err != nil {
+		return nil, err
 	}
-	if m.ErrorSpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
-		i--
-		dAtA[i] = 0x10
+	x := &streamingQuerierSearchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
 	}
-	if m.SpanCount != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
-		i--
-		dAtA[i] = 0x8
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return x, nil
 }
 
-func (m *SpanMetricsSummaryResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
+type StreamingQuerier_SearchClient interface {
+	Recv() (*SearchResponse, error)
+	grpc.ClientStream
+}
+
+type streamingQuerierSearchClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingQuerierSearchClient) Recv() (*SearchResponse, error) {
+	m := new(SearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return m, nil
 }
 
-func (m *SpanMetricsSummaryResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// StreamingQuerierServer is the server API for StreamingQuerier service.
+type StreamingQuerierServer interface {
+	Search(*SearchRequest, StreamingQuerier_SearchServer) error
 }
 
-func (m *SpanMetricsSummaryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Summaries) > 0 {
-		for iNdEx := len(m.Summaries) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Summaries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintTempo(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+// UnimplementedStreamingQuerierServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamingQuerierServer struct {
 }
 
-func (m *TraceQLStatic) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (*UnimplementedStreamingQuerierServer) Search(req *SearchRequest, srv StreamingQuerier_SearchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
 
-func (m *TraceQLStatic) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func RegisterStreamingQuerierServer(s *grpc.Server, srv StreamingQuerierServer) {
+	s.RegisterService(&_StreamingQuerier_serviceDesc, srv)
 }
 
-func (m *TraceQLStatic) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Kind != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Kind))
-		i--
-		dAtA[i] = 0x40
-	}
-	if m.Status != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Status))
-		i--
-		dAtA[i] = 0x38
-	}
-	if m.D != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.D))
-		i--
-		dAtA[i] = 0x30
-	}
-	if m.B {
-		i--
-		if m.B {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x28
-	}
-	if len(m.S) > 0 {
-		i -= len(m.S)
-		copy(dAtA[i:], m.S)
-		i = encodeVarintTempo(dAtA, i, uint64(len(m.S)))
-		i--
-		dAtA[i] = 0x22
+func _StreamingQuerier_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	if m.F != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.F))))
-		i--
-		dAtA[i] = 0x19
-	}
-	if m.N != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.N))
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.Type != 0 {
-		i = encodeVarintTempo(dAtA, i, uint64(m.Type))
-		i--
-		dAtA[i] = 0x8
-	}
-	return len(dAtA) - i, nil
+	return srv.(StreamingQuerierServer).Search(m, &streamingQuerierSearchServer{stream})
 }
 
-func encodeVarintTempo(dAtA []byte, offset int, v uint64) int {
-	offset -= sovTempo(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
+type StreamingQuerier_SearchServer interface {
+	Send(*SearchResponse) error
+	grpc.ServerStream
 }
-func (m *TraceByIDRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.TraceID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.BlockStart)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.BlockEnd)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.QueryMode)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	return n
+
+type streamingQuerierSearchServer struct {
+	grpc.ServerStream
 }
 
-func (m *TraceByIDResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.Metrics != nil {
-		l = m.Metrics.Size()
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	return n
+func (x *streamingQuerierSearchServer) Send(m *SearchResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (m *TraceByIDMetrics) Size() (n int) {
-	if m == nil {
-		return 0
+var _StreamingQuerier_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.StreamingQuerier",
+	HandlerType: (*StreamingQuerierServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Search",
+			Handler:       _StreamingQuerier_Search_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/tempopb/tempo.proto",
+}
+
+// MetricsClient is the client API for Metrics service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MetricsClient interface {
+	SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error)
+	QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error)
+}
+
+type metricsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMetricsClient(cc *grpc.ClientConn) MetricsClient {
+	return &metricsClient{cc}
+}
+
+func (c *metricsClient) SpanMetricsSummary(ctx context.Context, in *SpanMetricsSummaryRequest, opts ...grpc.CallOption) (*SpanMetricsSummaryResponse, error) {
+	out := new(SpanMetricsSummaryResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Metrics/SpanMetricsSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	return n
+	return out, nil
 }
 
-func (m *SearchRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (c *metricsClient) QueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (*QueryRangeResponse, error) {
+	out := new(QueryRangeResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.Metrics/QueryRange", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	if len(m.Tags) > 0 {
-		for k, v := range m.Tags {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovTempo(uint64(len(k))) + 1 + len(v) + sovTempo(uint64(len(v)))
-			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
-		}
+	return out, nil
+}
+
+// MetricsServer is the server API for Metrics service.
+type MetricsServer interface {
+	SpanMetricsSummary(context.Context, *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error)
+	QueryRange(context.Context, *QueryRangeRequest) (*QueryRangeResponse, error)
+}
+
+// UnimplementedMetricsServer can be embedded to have forward compatible implementations.
+type UnimplementedMetricsServer struct {
+}
+
+func (*UnimplementedMetricsServer) SpanMetricsSummary(ctx context.Context, req *SpanMetricsSummaryRequest) (*SpanMetricsSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpanMetricsSummary not implemented")
+}
+func (*UnimplementedMetricsServer) QueryRange(ctx context.Context, req *QueryRangeRequest) (*QueryRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRange not implemented")
+}
+
+func RegisterMetricsServer(s *grpc.Server, srv MetricsServer) {
+	s.RegisterService(&_Metrics_serviceDesc, srv)
+}
+
+func _Metrics_SpanMetricsSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpanMetricsSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.MinDurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.MinDurationMs))
+	if interceptor == nil {
+		return srv.(MetricsServer).SpanMetricsSummary(ctx, in)
 	}
-	if m.MaxDurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.MaxDurationMs))
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Metrics/SpanMetricsSummary",
 	}
-	if m.Limit != 0 {
-		n += 1 + sovTempo(uint64(m.Limit))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServer).SpanMetricsSummary(ctx, req.(*SpanMetricsSummaryRequest))
 	}
-	if m.Start != 0 {
-		n += 1 + sovTempo(uint64(m.Start))
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Metrics_QueryRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.End != 0 {
-		n += 1 + sovTempo(uint64(m.End))
+	if interceptor == nil {
+		return srv.(MetricsServer).QueryRange(ctx, in)
 	}
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.Metrics/QueryRange",
 	}
-	if m.SpansPerSpanSet != 0 {
-		n += 1 + sovTempo(uint64(m.SpansPerSpanSet))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServer).QueryRange(ctx, req.(*QueryRangeRequest))
 	}
-	return n
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *SearchBlockRequest) Size() (n int) {
-	if m == nil {
-		return 0
+var _Metrics_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.Metrics",
+	HandlerType: (*MetricsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SpanMetricsSummary",
+			Handler:    _Metrics_SpanMetricsSummary_Handler,
+		},
+		{
+			MethodName: "QueryRange",
+			Handler:    _Metrics_QueryRange_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/tempopb/tempo.proto",
+}
+
+func (m *TraceByIDRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceByIDRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceByIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.SearchReq != nil {
-		l = m.SearchReq.Size()
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.QueryMode) > 0 {
+		i -= len(m.QueryMode)
+		copy(dAtA[i:], m.QueryMode)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.QueryMode)))
+		i--
+		dAtA[i] = 0x2a
 	}
-	l = len(m.BlockID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.BlockEnd) > 0 {
+		i -= len(m.BlockEnd)
+		copy(dAtA[i:], m.BlockEnd)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockEnd)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.StartPage != 0 {
-		n += 1 + sovTempo(uint64(m.StartPage))
+	if len(m.BlockStart) > 0 {
+		i -= len(m.BlockStart)
+		copy(dAtA[i:], m.BlockStart)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockStart)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.PagesToSearch != 0 {
-		n += 1 + sovTempo(uint64(m.PagesToSearch))
+	if len(m.TraceID) > 0 {
+		i -= len(m.TraceID)
+		copy(dAtA[i:], m.TraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
+		i--
+		dAtA[i] = 0xa
 	}
-	l = len(m.Encoding)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.IndexPageSize != 0 {
-		n += 1 + sovTempo(uint64(m.IndexPageSize))
-	}
-	if m.TotalRecords != 0 {
-		n += 1 + sovTempo(uint64(m.TotalRecords))
-	}
-	l = len(m.DataEncoding)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.Version)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.Size_ != 0 {
-		n += 1 + sovTempo(uint64(m.Size_))
-	}
-	if m.FooterSize != 0 {
-		n += 1 + sovTempo(uint64(m.FooterSize))
-	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Traces) > 0 {
-		for _, e := range m.Traces {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
-	}
-	if m.Metrics != nil {
-		l = m.Metrics.Size()
-		n += 1 + l + sovTempo(uint64(l))
+func (m *TraceByIDResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *TraceSearchMetadata) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.TraceID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.RootServiceName)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.RootTraceName)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.StartTimeUnixNano != 0 {
-		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
-	}
-	if m.DurationMs != 0 {
-		n += 1 + sovTempo(uint64(m.DurationMs))
-	}
-	if m.SpanSet != nil {
-		l = m.SpanSet.Size()
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if len(m.SpanSets) > 0 {
-		for _, e := range m.SpanSets {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
-	}
-	return n
+func (m *TraceByIDResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SpanSet) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *TraceByIDResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Spans) > 0 {
-		for _, e := range m.Spans {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if m.Metrics != nil {
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.Matched != 0 {
-		n += 1 + sovTempo(uint64(m.Matched))
-	}
-	if len(m.Attributes) > 0 {
-		for _, e := range m.Attributes {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if m.Trace != nil {
+		{
+			size, err := m.Trace.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Span) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.SpanID)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.StartTimeUnixNano != 0 {
-		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
-	}
-	if m.DurationNanos != 0 {
-		n += 1 + sovTempo(uint64(m.DurationNanos))
-	}
-	if len(m.Attributes) > 0 {
-		for _, e := range m.Attributes {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+func (m *TraceByIDMetrics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SearchMetrics) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *TraceByIDMetrics) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceByIDMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.InspectedTraces != 0 {
-		n += 1 + sovTempo(uint64(m.InspectedTraces))
-	}
-	if m.InspectedBytes != 0 {
-		n += 1 + sovTempo(uint64(m.InspectedBytes))
-	}
-	if m.TotalBlocks != 0 {
-		n += 1 + sovTempo(uint64(m.TotalBlocks))
-	}
-	if m.CompletedJobs != 0 {
-		n += 1 + sovTempo(uint64(m.CompletedJobs))
-	}
-	if m.TotalJobs != 0 {
-		n += 1 + sovTempo(uint64(m.TotalJobs))
-	}
-	if m.TotalBlockBytes != 0 {
-		n += 1 + sovTempo(uint64(m.TotalBlockBytes))
-	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchTagsRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FlushedBlocksRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FlushedBlocksRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FlushedBlocksRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Scope)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchTagsResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FlushedBlocksResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FlushedBlocksResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FlushedBlocksResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.TagNames) > 0 {
-		for _, s := range m.TagNames {
-			l = len(s)
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.Metas) > 0 {
+		for iNdEx := len(m.Metas) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Metas[iNdEx])
+			copy(dAtA[i:], m.Metas[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Metas[iNdEx])))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SearchTagsV2Response) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Scopes) > 0 {
-		for _, e := range m.Scopes {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+func (m *SearchRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SearchTagsV2Scope) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if len(m.Tags) > 0 {
-		for _, s := range m.Tags {
-			l = len(s)
-			n += 1 + l + sovTempo(uint64(l))
-		}
-	}
-	return n
+func (m *SearchRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SearchTagValuesRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SearchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.TagName)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.Saved) > 0 {
+		i -= len(m.Saved)
+		copy(dAtA[i:], m.Saved)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Saved)))
+		i--
+		dAtA[i] = 0x5a
 	}
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.Explain {
+		i--
+		if m.Explain {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
 	}
-	return n
-}
-
-func (m *SearchTagValuesResponse) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.SpansPerSpanSet != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpansPerSpanSet))
+		i--
+		dAtA[i] = 0x48
 	}
-	var l int
-	_ = l
-	if len(m.TagValues) > 0 {
-		for _, s := range m.TagValues {
-			l = len(s)
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x42
 	}
-	return n
-}
-
-func (m *TagValue) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x30
 	}
-	var l int
-	_ = l
-	l = len(m.Type)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x28
 	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	if m.Limit != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x20
 	}
-	return n
-}
-
-func (m *SearchTagValuesV2Response) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.MaxDurationMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.MaxDurationMs))
+		i--
+		dAtA[i] = 0x18
 	}
-	var l int
-	_ = l
-	if len(m.TagValues) > 0 {
-		for _, e := range m.TagValues {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if m.MinDurationMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.MinDurationMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Tags) > 0 {
+		for k := range m.Tags {
+			v := m.Tags[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintTempo(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintTempo(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintTempo(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Trace) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Batches) > 0 {
-		for _, e := range m.Batches {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+func (m *SearchBlockRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *PushResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	return n
+func (m *SearchBlockRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *PushBytesRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SearchBlockRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Traces) > 0 {
-		for _, e := range m.Traces {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.DedicatedColumns) > 0 {
+		for iNdEx := len(m.DedicatedColumns) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.DedicatedColumns[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x62
 		}
 	}
-	if len(m.Ids) > 0 {
-		for _, e := range m.Ids {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if m.FooterSize != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.FooterSize))
+		i--
+		dAtA[i] = 0x58
 	}
-	if len(m.SearchData) > 0 {
-		for _, e := range m.SearchData {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if m.Size_ != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Size_))
+		i--
+		dAtA[i] = 0x50
 	}
-	return n
-}
-
-func (m *PushSpansRequest) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0x4a
 	}
-	var l int
-	_ = l
-	if len(m.Batches) > 0 {
-		for _, e := range m.Batches {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if len(m.DataEncoding) > 0 {
+		i -= len(m.DataEncoding)
+		copy(dAtA[i:], m.DataEncoding)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.DataEncoding)))
+		i--
+		dAtA[i] = 0x42
 	}
-	return n
-}
-
-func (m *TraceBytes) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.TotalRecords != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalRecords))
+		i--
+		dAtA[i] = 0x38
 	}
-	var l int
-	_ = l
-	if len(m.Traces) > 0 {
-		for _, b := range m.Traces {
-			l = len(b)
-			n += 1 + l + sovTempo(uint64(l))
-		}
+	if m.IndexPageSize != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.IndexPageSize))
+		i--
+		dAtA[i] = 0x30
 	}
-	return n
-}
-
-func (m *LinkSlice) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(m.Encoding) > 0 {
+		i -= len(m.Encoding)
+		copy(dAtA[i:], m.Encoding)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Encoding)))
+		i--
+		dAtA[i] = 0x2a
 	}
-	var l int
-	_ = l
-	if len(m.Links) > 0 {
-		for _, e := range m.Links {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if m.PagesToSearch != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.PagesToSearch))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.StartPage != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartPage))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.BlockID) > 0 {
+		i -= len(m.BlockID)
+		copy(dAtA[i:], m.BlockID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.SearchReq != nil {
+		{
+			size, err := m.SearchReq.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *DedicatedColumn) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *DedicatedColumn) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DedicatedColumn) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.GroupBy)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.Limit != 0 {
-		n += 1 + sovTempo(uint64(m.Limit))
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.Start != 0 {
-		n += 1 + sovTempo(uint64(m.Start))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.End != 0 {
-		n += 1 + sovTempo(uint64(m.End))
+	if len(m.Scope) > 0 {
+		i -= len(m.Scope)
+		copy(dAtA[i:], m.Scope)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Scope)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummaryRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	l = len(m.GroupBy)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
-	}
-	if m.Limit != 0 {
-		n += 1 + sovTempo(uint64(m.Limit))
+	if m.Explain != nil {
+		{
+			size, err := m.Explain.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.Start != 0 {
-		n += 1 + sovTempo(uint64(m.Start))
+	if m.Metrics != nil {
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.End != 0 {
-		n += 1 + sovTempo(uint64(m.End))
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Traces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SearchExplain) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchExplain) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchExplain) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Estimated {
-		n += 2
+	if len(m.Iterators) > 0 {
+		for iNdEx := len(m.Iterators) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Iterators[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
 	}
-	if m.SpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.SpanCount))
+	if m.SearchedPages != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SearchedPages))
+		i--
+		dAtA[i] = 0x38
 	}
-	if m.ErrorSpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
+	if m.SearchedBlocks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SearchedBlocks))
+		i--
+		dAtA[i] = 0x30
 	}
-	if len(m.Metrics) > 0 {
-		for _, e := range m.Metrics {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if m.SkippedBlocks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SkippedBlocks))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.SecondPassConditions) > 0 {
+		for iNdEx := len(m.SecondPassConditions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.SecondPassConditions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
 		}
 	}
-	return n
-}
-
-func (m *RawHistogram) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.AllConditions {
+		i--
+		if m.AllConditions {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
 	}
-	var l int
-	_ = l
-	if m.Bucket != 0 {
-		n += 1 + sovTempo(uint64(m.Bucket))
+	if len(m.Conditions) > 0 {
+		for iNdEx := len(m.Conditions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Conditions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if m.Count != 0 {
-		n += 1 + sovTempo(uint64(m.Count))
+	if len(m.Pipeline) > 0 {
+		for iNdEx := len(m.Pipeline) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Pipeline[iNdEx])
+			copy(dAtA[i:], m.Pipeline[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Pipeline[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetrics) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ExplainCondition) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ExplainCondition) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExplainCondition) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.LatencyHistogram) > 0 {
-		for _, e := range m.LatencyHistogram {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if m.PushedDown {
+		i--
+		if m.PushedDown {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x20
 	}
-	if m.Static != nil {
-		l = m.Static.Size()
-		n += 1 + l + sovTempo(uint64(l))
+	if len(m.Operands) > 0 {
+		for iNdEx := len(m.Operands) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Operands[iNdEx])
+			copy(dAtA[i:], m.Operands[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Operands[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
-	if m.Errors != 0 {
-		n += 1 + sovTempo(uint64(m.Errors))
+	if len(m.Op) > 0 {
+		i -= len(m.Op)
+		copy(dAtA[i:], m.Op)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Op)))
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
+	if len(m.Attribute) > 0 {
+		i -= len(m.Attribute)
+		copy(dAtA[i:], m.Attribute)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Attribute)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummary) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ExplainIterator) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ExplainIterator) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExplainIterator) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.SpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.SpanCount))
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x40
 	}
-	if m.ErrorSpanCount != 0 {
-		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
+	if m.KeptValues != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.KeptValues))
+		i--
+		dAtA[i] = 0x38
 	}
-	if m.Static != nil {
-		l = m.Static.Size()
-		n += 1 + l + sovTempo(uint64(l))
+	if m.InspectedValues != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedValues))
+		i--
+		dAtA[i] = 0x30
 	}
-	if m.P99 != 0 {
-		n += 1 + sovTempo(uint64(m.P99))
+	if m.KeptPages != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.KeptPages))
+		i--
+		dAtA[i] = 0x28
 	}
-	if m.P95 != 0 {
-		n += 1 + sovTempo(uint64(m.P95))
+	if m.InspectedPages != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedPages))
+		i--
+		dAtA[i] = 0x20
 	}
-	if m.P90 != 0 {
-		n += 1 + sovTempo(uint64(m.P90))
+	if m.KeptColumnChunks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.KeptColumnChunks))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.P50 != 0 {
-		n += 1 + sovTempo(uint64(m.P50))
+	if m.InspectedColumnChunks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedColumnChunks))
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if len(m.Column) > 0 {
+		i -= len(m.Column)
+		copy(dAtA[i:], m.Column)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Column)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *SpanMetricsSummaryResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TraceSearchMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceSearchMetadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceSearchMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Summaries) > 0 {
-		for _, e := range m.Summaries {
-			l = e.Size()
-			n += 1 + l + sovTempo(uint64(l))
+	if len(m.SpanSets) > 0 {
+		for iNdEx := len(m.SpanSets) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.SpanSets[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
 		}
 	}
-	return n
+	if m.SpanSet != nil {
+		{
+			size, err := m.SpanSet.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.DurationMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationMs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.RootTraceName) > 0 {
+		i -= len(m.RootTraceName)
+		copy(dAtA[i:], m.RootTraceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootTraceName)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.RootServiceName) > 0 {
+		i -= len(m.RootServiceName)
+		copy(dAtA[i:], m.RootServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.RootServiceName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.TraceID) > 0 {
+		i -= len(m.TraceID)
+		copy(dAtA[i:], m.TraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *TraceQLStatic) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SpanSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanSet) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Type != 0 {
-		n += 1 + sovTempo(uint64(m.Type))
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
-	if m.N != 0 {
-		n += 1 + sovTempo(uint64(m.N))
+	if m.Matched != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Matched))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.F != 0 {
-		n += 9
+	if len(m.Spans) > 0 {
+		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Spans[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	l = len(m.S)
-	if l > 0 {
-		n += 1 + l + sovTempo(uint64(l))
+	return len(dAtA) - i, nil
+}
+
+func (m *Span) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	if m.B {
-		n += 2
+	return dAtA[:n], nil
+}
+
+func (m *Span) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Span) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
 	}
-	if m.D != 0 {
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.SpanID) > 0 {
+		i -= len(m.SpanID)
+		copy(dAtA[i:], m.SpanID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchMetrics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchMetrics) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.TotalBlockBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlockBytes))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.TotalJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalJobs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.CompletedJobs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.CompletedJobs))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.TotalBlocks != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TotalBlocks))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.InspectedBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedBytes))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.InspectedTraces != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedTraces))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchTagsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Scope) > 0 {
+		i -= len(m.Scope)
+		copy(dAtA[i:], m.Scope)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Scope)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchTagsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TagNames) > 0 {
+		for iNdEx := len(m.TagNames) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TagNames[iNdEx])
+			copy(dAtA[i:], m.TagNames[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagNames[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchTagsV2Response) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsV2Response) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Scopes) > 0 {
+		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Scopes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchTagsV2Scope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagsV2Scope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagsV2Scope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Tags) > 0 {
+		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Tags[iNdEx])
+			copy(dAtA[i:], m.Tags[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Tags[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchTagValuesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagValuesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagValuesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.TagName) > 0 {
+		i -= len(m.TagName)
+		copy(dAtA[i:], m.TagName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TagName)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchTagValuesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagValuesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagValuesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TagValues) > 0 {
+		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TagValues[iNdEx])
+			copy(dAtA[i:], m.TagValues[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.TagValues[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TagValue) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TagValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TagValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchTagValuesV2Response) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTagValuesV2Response) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTagValuesV2Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TagValues) > 0 {
+		for iNdEx := len(m.TagValues) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.TagValues[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Trace) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Trace) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Trace) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PushResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PushResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *TransferTracesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TransferTracesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransferTracesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Segment) > 0 {
+		i -= len(m.Segment)
+		copy(dAtA[i:], m.Segment)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Segment)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.TraceID) > 0 {
+		i -= len(m.TraceID)
+		copy(dAtA[i:], m.TraceID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TraceID)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.TenantID) > 0 {
+		i -= len(m.TenantID)
+		copy(dAtA[i:], m.TenantID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.TenantID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.FromIngesterID) > 0 {
+		i -= len(m.FromIngesterID)
+		copy(dAtA[i:], m.FromIngesterID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.FromIngesterID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TransferTracesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TransferTracesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransferTracesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *PushBytesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PushBytesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushBytesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SearchData) > 0 {
+		for iNdEx := len(m.SearchData) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.SearchData[iNdEx].Size()
+				i -= size
+				if _, err := m.SearchData[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Ids) > 0 {
+		for iNdEx := len(m.Ids) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Ids[iNdEx].Size()
+				i -= size
+				if _, err := m.Ids[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Traces[iNdEx].Size()
+				i -= size
+				if _, err := m.Traces[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PushSpansRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PushSpansRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PushSpansRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for iNdEx := len(m.Batches) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Batches[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceBytes) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceBytes) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceBytes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for iNdEx := len(m.Traces) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Traces[iNdEx])
+			copy(dAtA[i:], m.Traces[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Traces[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LinkSlice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LinkSlice) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LinkSlice) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Links) > 0 {
+		for iNdEx := len(m.Links) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Links[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Limit != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.GroupBy) > 0 {
+		i -= len(m.GroupBy)
+		copy(dAtA[i:], m.GroupBy)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsSummaryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsSummaryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsSummaryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Limit != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.GroupBy) > 0 {
+		i -= len(m.GroupBy)
+		copy(dAtA[i:], m.GroupBy)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.GroupBy)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Metrics) > 0 {
+		for iNdEx := len(m.Metrics) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Metrics[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.ErrorSpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Estimated {
+		i--
+		if m.Estimated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RawHistogram) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RawHistogram) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RawHistogram) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Bucket != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Bucket))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetrics) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetrics) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Errors != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Errors))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Static != nil {
+		{
+			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.LatencyHistogram) > 0 {
+		for iNdEx := len(m.LatencyHistogram) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.LatencyHistogram[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsSummary) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsSummary) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.P50 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P50))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.P90 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P90))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.P95 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P95))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.P99 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P99))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Static != nil {
+		{
+			size, err := m.Static.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.ErrorSpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorSpanCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanMetricsSummaryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanMetricsSummaryResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanMetricsSummaryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Summaries) > 0 {
+		for iNdEx := len(m.Summaries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Summaries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceQLStatic) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceQLStatic) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceQLStatic) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Kind != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Kind))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Status != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.D != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.D))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.B {
+		i--
+		if m.B {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.S) > 0 {
+		i -= len(m.S)
+		copy(dAtA[i:], m.S)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.S)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.F != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.F))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.N != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.N))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Type != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRangeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRangeRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRangeRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Block != nil {
+		{
+			size, err := m.Block.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Step != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Step))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryRangeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryRangeResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryRangeResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Metrics != nil {
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Series) > 0 {
+		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Series[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Sample) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Sample) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Sample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Value != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if m.TimestampMs != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TimeSeries) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TimeSeries) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TimeSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.PromLabels) > 0 {
+		i -= len(m.PromLabels)
+		copy(dAtA[i:], m.PromLabels)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.PromLabels)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Samples) > 0 {
+		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Labels[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceDiffResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceDiffResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceDiffResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.SpansOnlyInB != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpansOnlyInB))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.SpansOnlyInA != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpansOnlyInA))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MatchedSpans != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.MatchedSpans))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Spans) > 0 {
+		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Spans[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SpanDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpanDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpanDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if m.DurationDeltaNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationDeltaNanos))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.DurationNanosB != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanosB))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.DurationNanosA != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanosA))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.SpanIDB) > 0 {
+		i -= len(m.SpanIDB)
+		copy(dAtA[i:], m.SpanIDB)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanIDB)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.SpanIDA) > 0 {
+		i -= len(m.SpanIDA)
+		copy(dAtA[i:], m.SpanIDA)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanIDA)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ServiceName) > 0 {
+		i -= len(m.ServiceName)
+		copy(dAtA[i:], m.ServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ServiceName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AttributeDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AttributeDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AttributeDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.B != nil {
+		{
+			size, err := m.B.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.A != nil {
+		{
+			size, err := m.A.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceSummaryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceSummaryRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceSummaryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Block != nil {
+		{
+			size, err := m.Block.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Limit != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.End != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.End))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Start != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Start))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceSummaryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceSummaryResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceSummaryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Metrics != nil {
+		{
+			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTempo(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.TraceCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TraceCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Nodes) > 0 {
+		for iNdEx := len(m.Nodes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Nodes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceSummaryNode) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceSummaryNode) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceSummaryNode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.LatencyHistogram) > 0 {
+		for iNdEx := len(m.LatencyHistogram) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.LatencyHistogram[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if m.P99 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P99))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.P95 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P95))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.P90 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P90))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.P50 != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.P50))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.ErrorRate != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ErrorRate))))
+		i--
+		dAtA[i] = 0x31
+	}
+	if m.ErrorCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ErrorCount))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Count != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ServiceName) > 0 {
+		i -= len(m.ServiceName)
+		copy(dAtA[i:], m.ServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ServiceName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TraceCriticalPathResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TraceCriticalPathResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TraceCriticalPathResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Spans) > 0 {
+		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Spans[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Segments) > 0 {
+		for iNdEx := len(m.Segments) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Segments[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CriticalPathSegment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CriticalPathSegment) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CriticalPathSegment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ServiceName) > 0 {
+		i -= len(m.ServiceName)
+		copy(dAtA[i:], m.ServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ServiceName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.SpanID) > 0 {
+		i -= len(m.SpanID)
+		copy(dAtA[i:], m.SpanID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CriticalPathSpan) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CriticalPathSpan) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CriticalPathSpan) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CriticalPathNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.CriticalPathNanos))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.ChildrenTimeNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.ChildrenTimeNanos))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.SelfTimeNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SelfTimeNanos))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.DurationNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.DurationNanos))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.StartTimeUnixNano != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartTimeUnixNano))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.ServiceName) > 0 {
+		i -= len(m.ServiceName)
+		copy(dAtA[i:], m.ServiceName)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ServiceName)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ParentSpanID) > 0 {
+		i -= len(m.ParentSpanID)
+		copy(dAtA[i:], m.ParentSpanID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.ParentSpanID)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.SpanID) > 0 {
+		i -= len(m.SpanID)
+		copy(dAtA[i:], m.SpanID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.SpanID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AttributeProfileRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AttributeProfileRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AttributeProfileRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Service) > 0 {
+		i -= len(m.Service)
+		copy(dAtA[i:], m.Service)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Service)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AttributeProfileResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AttributeProfileResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AttributeProfileResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Services) > 0 {
+		for iNdEx := len(m.Services) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Services[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ServiceAttributeProfile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ServiceAttributeProfile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ServiceAttributeProfile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Service) > 0 {
+		i -= len(m.Service)
+		copy(dAtA[i:], m.Service)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Service)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AttributeProfile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AttributeProfile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AttributeProfile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sketch) > 0 {
+		i -= len(m.Sketch)
+		copy(dAtA[i:], m.Sketch)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Sketch)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Cardinality != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Cardinality))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Bytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Bytes))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Count != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Types) > 0 {
+		for iNdEx := len(m.Types) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Types[iNdEx])
+			copy(dAtA[i:], m.Types[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Types[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Scope) > 0 {
+		i -= len(m.Scope)
+		copy(dAtA[i:], m.Scope)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.Scope)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintTempo(dAtA []byte, offset int, v uint64) int {
+	offset -= sovTempo(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *TraceByIDRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockStart)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockEnd)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.QueryMode)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Trace != nil {
+		l = m.Trace.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceByIDMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *FlushedBlocksRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *FlushedBlocksResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Metas) > 0 {
+		for _, b := range m.Metas {
+			l = len(b)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Tags) > 0 {
+		for k, v := range m.Tags {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovTempo(uint64(len(k))) + 1 + len(v) + sovTempo(uint64(len(v)))
+			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
+		}
+	}
+	if m.MinDurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.MinDurationMs))
+	}
+	if m.MaxDurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.MaxDurationMs))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovTempo(uint64(m.Limit))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.SpansPerSpanSet != 0 {
+		n += 1 + sovTempo(uint64(m.SpansPerSpanSet))
+	}
+	if m.Explain {
+		n += 2
+	}
+	l = len(m.Saved)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchBlockRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SearchReq != nil {
+		l = m.SearchReq.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.BlockID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartPage != 0 {
+		n += 1 + sovTempo(uint64(m.StartPage))
+	}
+	if m.PagesToSearch != 0 {
+		n += 1 + sovTempo(uint64(m.PagesToSearch))
+	}
+	l = len(m.Encoding)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.IndexPageSize != 0 {
+		n += 1 + sovTempo(uint64(m.IndexPageSize))
+	}
+	if m.TotalRecords != 0 {
+		n += 1 + sovTempo(uint64(m.TotalRecords))
+	}
+	l = len(m.DataEncoding)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Size_ != 0 {
+		n += 1 + sovTempo(uint64(m.Size_))
+	}
+	if m.FooterSize != 0 {
+		n += 1 + sovTempo(uint64(m.FooterSize))
+	}
+	if len(m.DedicatedColumns) > 0 {
+		for _, e := range m.DedicatedColumns {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DedicatedColumn) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Scope)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for _, e := range m.Traces {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Explain != nil {
+		l = m.Explain.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchExplain) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Pipeline) > 0 {
+		for _, s := range m.Pipeline {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Conditions) > 0 {
+		for _, e := range m.Conditions {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.AllConditions {
+		n += 2
+	}
+	if len(m.SecondPassConditions) > 0 {
+		for _, e := range m.SecondPassConditions {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.SkippedBlocks != 0 {
+		n += 1 + sovTempo(uint64(m.SkippedBlocks))
+	}
+	if m.SearchedBlocks != 0 {
+		n += 1 + sovTempo(uint64(m.SearchedBlocks))
+	}
+	if m.SearchedPages != 0 {
+		n += 1 + sovTempo(uint64(m.SearchedPages))
+	}
+	if len(m.Iterators) > 0 {
+		for _, e := range m.Iterators {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ExplainCondition) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Attribute)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Op)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.Operands) > 0 {
+		for _, s := range m.Operands {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.PushedDown {
+		n += 2
+	}
+	return n
+}
+
+func (m *ExplainIterator) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Column)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.InspectedColumnChunks != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedColumnChunks))
+	}
+	if m.KeptColumnChunks != 0 {
+		n += 1 + sovTempo(uint64(m.KeptColumnChunks))
+	}
+	if m.InspectedPages != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedPages))
+	}
+	if m.KeptPages != 0 {
+		n += 1 + sovTempo(uint64(m.KeptPages))
+	}
+	if m.InspectedValues != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedValues))
+	}
+	if m.KeptValues != 0 {
+		n += 1 + sovTempo(uint64(m.KeptValues))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	return n
+}
+
+func (m *TraceSearchMetadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.RootServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.RootTraceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationMs != 0 {
+		n += 1 + sovTempo(uint64(m.DurationMs))
+	}
+	if m.SpanSet != nil {
+		l = m.SpanSet.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.SpanSets) > 0 {
+		for _, e := range m.SpanSets {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SpanSet) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Spans) > 0 {
+		for _, e := range m.Spans {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Matched != 0 {
+		n += 1 + sovTempo(uint64(m.Matched))
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Span) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SpanID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.InspectedTraces != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedTraces))
+	}
+	if m.InspectedBytes != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedBytes))
+	}
+	if m.TotalBlocks != 0 {
+		n += 1 + sovTempo(uint64(m.TotalBlocks))
+	}
+	if m.CompletedJobs != 0 {
+		n += 1 + sovTempo(uint64(m.CompletedJobs))
+	}
+	if m.TotalJobs != 0 {
+		n += 1 + sovTempo(uint64(m.TotalJobs))
+	}
+	if m.TotalBlockBytes != 0 {
+		n += 1 + sovTempo(uint64(m.TotalBlockBytes))
+	}
+	return n
+}
+
+func (m *SearchTagsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Scope)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchTagsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TagNames) > 0 {
+		for _, s := range m.TagNames {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchTagsV2Response) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Scopes) > 0 {
+		for _, e := range m.Scopes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchTagsV2Scope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchTagValuesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TagName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchTagValuesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TagValues) > 0 {
+		for _, s := range m.TagValues {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TagValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *SearchTagValuesV2Response) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TagValues) > 0 {
+		for _, e := range m.TagValues {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Trace) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for _, e := range m.Batches {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PushResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *TransferTracesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.FromIngesterID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.TenantID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Segment)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TransferTracesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *PushBytesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for _, e := range m.Traces {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Ids) > 0 {
+		for _, e := range m.Ids {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.SearchData) > 0 {
+		for _, e := range m.SearchData {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PushSpansRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Batches) > 0 {
+		for _, e := range m.Batches {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TraceBytes) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Traces) > 0 {
+		for _, b := range m.Traces {
+			l = len(b)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LinkSlice) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Links) > 0 {
+		for _, e := range m.Links {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SpanMetricsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.GroupBy)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovTempo(uint64(m.Limit))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	return n
+}
+
+func (m *SpanMetricsSummaryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.GroupBy)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovTempo(uint64(m.Limit))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	return n
+}
+
+func (m *SpanMetricsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Estimated {
+		n += 2
+	}
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if m.ErrorSpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
+	}
+	if len(m.Metrics) > 0 {
+		for _, e := range m.Metrics {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RawHistogram) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Bucket != 0 {
+		n += 1 + sovTempo(uint64(m.Bucket))
+	}
+	if m.Count != 0 {
+		n += 1 + sovTempo(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *SpanMetrics) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.LatencyHistogram) > 0 {
+		for _, e := range m.LatencyHistogram {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Static != nil {
+		l = m.Static.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Errors != 0 {
+		n += 1 + sovTempo(uint64(m.Errors))
+	}
+	return n
+}
+
+func (m *SpanMetricsSummary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if m.ErrorSpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorSpanCount))
+	}
+	if m.Static != nil {
+		l = m.Static.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.P99 != 0 {
+		n += 1 + sovTempo(uint64(m.P99))
+	}
+	if m.P95 != 0 {
+		n += 1 + sovTempo(uint64(m.P95))
+	}
+	if m.P90 != 0 {
+		n += 1 + sovTempo(uint64(m.P90))
+	}
+	if m.P50 != 0 {
+		n += 1 + sovTempo(uint64(m.P50))
+	}
+	return n
+}
+
+func (m *SpanMetricsSummaryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Summaries) > 0 {
+		for _, e := range m.Summaries {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TraceQLStatic) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovTempo(uint64(m.Type))
+	}
+	if m.N != 0 {
+		n += 1 + sovTempo(uint64(m.N))
+	}
+	if m.F != 0 {
+		n += 9
+	}
+	l = len(m.S)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.B {
+		n += 2
+	}
+	if m.D != 0 {
 		n += 1 + sovTempo(uint64(m.D))
 	}
-	if m.Status != 0 {
-		n += 1 + sovTempo(uint64(m.Status))
+	if m.Status != 0 {
+		n += 1 + sovTempo(uint64(m.Status))
+	}
+	if m.Kind != 0 {
+		n += 1 + sovTempo(uint64(m.Kind))
+	}
+	return n
+}
+
+func (m *QueryRangeRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	if m.Step != 0 {
+		n += 1 + sovTempo(uint64(m.Step))
+	}
+	if m.Block != nil {
+		l = m.Block.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryRangeResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Series) > 0 {
+		for _, e := range m.Series {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *Sample) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.TimestampMs != 0 {
+		n += 1 + sovTempo(uint64(m.TimestampMs))
+	}
+	if m.Value != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *TimeSeries) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	l = len(m.PromLabels)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceDiffResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Spans) > 0 {
+		for _, e := range m.Spans {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.MatchedSpans != 0 {
+		n += 1 + sovTempo(uint64(m.MatchedSpans))
+	}
+	if m.SpansOnlyInA != 0 {
+		n += 1 + sovTempo(uint64(m.SpansOnlyInA))
+	}
+	if m.SpansOnlyInB != 0 {
+		n += 1 + sovTempo(uint64(m.SpansOnlyInB))
+	}
+	return n
+}
+
+func (m *SpanDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.SpanIDA)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.SpanIDB)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.DurationNanosA != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanosA))
+	}
+	if m.DurationNanosB != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanosB))
+	}
+	if m.DurationDeltaNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationDeltaNanos))
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AttributeDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.A != nil {
+		l = m.A.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.B != nil {
+		l = m.B.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceSummaryRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Start != 0 {
+		n += 1 + sovTempo(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovTempo(uint64(m.End))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovTempo(uint64(m.Limit))
+	}
+	if m.Block != nil {
+		l = m.Block.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceSummaryResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Nodes) > 0 {
+		for _, e := range m.Nodes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.TraceCount != 0 {
+		n += 1 + sovTempo(uint64(m.TraceCount))
+	}
+	if m.Metrics != nil {
+		l = m.Metrics.Size()
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *TraceSummaryNode) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.Count != 0 {
+		n += 1 + sovTempo(uint64(m.Count))
+	}
+	if m.ErrorCount != 0 {
+		n += 1 + sovTempo(uint64(m.ErrorCount))
+	}
+	if m.ErrorRate != 0 {
+		n += 9
+	}
+	if m.P50 != 0 {
+		n += 1 + sovTempo(uint64(m.P50))
+	}
+	if m.P90 != 0 {
+		n += 1 + sovTempo(uint64(m.P90))
+	}
+	if m.P95 != 0 {
+		n += 1 + sovTempo(uint64(m.P95))
+	}
+	if m.P99 != 0 {
+		n += 1 + sovTempo(uint64(m.P99))
+	}
+	if len(m.LatencyHistogram) > 0 {
+		for _, e := range m.LatencyHistogram {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TraceCriticalPathResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Segments) > 0 {
+		for _, e := range m.Segments {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Spans) > 0 {
+		for _, e := range m.Spans {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	return n
+}
+
+func (m *CriticalPathSegment) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SpanID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	return n
+}
+
+func (m *CriticalPathSpan) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SpanID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.ParentSpanID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartTimeUnixNano != 0 {
+		n += 1 + sovTempo(uint64(m.StartTimeUnixNano))
+	}
+	if m.DurationNanos != 0 {
+		n += 1 + sovTempo(uint64(m.DurationNanos))
+	}
+	if m.SelfTimeNanos != 0 {
+		n += 1 + sovTempo(uint64(m.SelfTimeNanos))
+	}
+	if m.ChildrenTimeNanos != 0 {
+		n += 1 + sovTempo(uint64(m.ChildrenTimeNanos))
+	}
+	if m.CriticalPathNanos != 0 {
+		n += 1 + sovTempo(uint64(m.CriticalPathNanos))
+	}
+	return n
+}
+
+func (m *AttributeProfileRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Service)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func (m *AttributeProfileResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Services) > 0 {
+		for _, e := range m.Services {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ServiceAttributeProfile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Service)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AttributeProfile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Scope)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if len(m.Types) > 0 {
+		for _, s := range m.Types {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.Count != 0 {
+		n += 1 + sovTempo(uint64(m.Count))
+	}
+	if m.Bytes != 0 {
+		n += 1 + sovTempo(uint64(m.Bytes))
+	}
+	if m.Cardinality != 0 {
+		n += 1 + sovTempo(uint64(m.Cardinality))
+	}
+	l = len(m.Sketch)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	return n
+}
+
+func sovTempo(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozTempo(x uint64) (n int) {
+	return sovTempo(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceID = append(m.TraceID[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceID == nil {
+				m.TraceID = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockStart", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockStart = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockEnd", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockEnd = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryMode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QueryMode = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Trace == nil {
+				m.Trace = &Trace{}
+			}
+			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metrics == nil {
+				m.Metrics = &TraceByIDMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceByIDMetrics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceByIDMetrics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceByIDMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FlushedBlocksRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FlushedBlocksRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FlushedBlocksRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FlushedBlocksResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FlushedBlocksResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FlushedBlocksResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metas", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metas = append(m.Metas, make([]byte, postIndex-iNdEx))
+			copy(m.Metas[len(m.Metas)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Tags == nil {
+				m.Tags = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTempo
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTempo
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthTempo
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipTempo(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthTempo
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Tags[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinDurationMs", wireType)
+			}
+			m.MinDurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinDurationMs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxDurationMs", wireType)
+			}
+			m.MaxDurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxDurationMs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpansPerSpanSet", wireType)
+			}
+			m.SpansPerSpanSet = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpansPerSpanSet |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Explain", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Explain = bool(v != 0)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Saved", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Saved = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchBlockRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SearchReq == nil {
+				m.SearchReq = &SearchRequest{}
+			}
+			if err := m.SearchReq.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
+			}
+			m.StartPage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartPage |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PagesToSearch", wireType)
+			}
+			m.PagesToSearch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PagesToSearch |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Encoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexPageSize", wireType)
+			}
+			m.IndexPageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IndexPageSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalRecords", wireType)
+			}
+			m.TotalRecords = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalRecords |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataEncoding", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataEncoding = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Size_ |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
+			}
+			m.FooterSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FooterSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DedicatedColumns", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DedicatedColumns = append(m.DedicatedColumns, &DedicatedColumn{})
+			if err := m.DedicatedColumns[len(m.DedicatedColumns)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DedicatedColumn) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DedicatedColumn: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DedicatedColumn: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Traces = append(m.Traces, &TraceSearchMetadata{})
+			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metrics == nil {
+				m.Metrics = &SearchMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Explain", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Explain == nil {
+				m.Explain = &SearchExplain{}
+			}
+			if err := m.Explain.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchExplain) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchExplain: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchExplain: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pipeline", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Pipeline = append(m.Pipeline, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Conditions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Conditions = append(m.Conditions, &ExplainCondition{})
+			if err := m.Conditions[len(m.Conditions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllConditions", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AllConditions = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecondPassConditions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SecondPassConditions = append(m.SecondPassConditions, &ExplainCondition{})
+			if err := m.SecondPassConditions[len(m.SecondPassConditions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SkippedBlocks", wireType)
+			}
+			m.SkippedBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SkippedBlocks |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchedBlocks", wireType)
+			}
+			m.SearchedBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SearchedBlocks |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchedPages", wireType)
+			}
+			m.SearchedPages = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SearchedPages |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Iterators", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Iterators = append(m.Iterators, &ExplainIterator{})
+			if err := m.Iterators[len(m.Iterators)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExplainCondition) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExplainCondition: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExplainCondition: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attribute", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attribute = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Op = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operands", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operands = append(m.Operands, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PushedDown", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.PushedDown = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExplainIterator) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExplainIterator: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExplainIterator: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Column", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Column = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedColumnChunks", wireType)
+			}
+			m.InspectedColumnChunks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedColumnChunks |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeptColumnChunks", wireType)
+			}
+			m.KeptColumnChunks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KeptColumnChunks |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedPages", wireType)
+			}
+			m.InspectedPages = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedPages |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeptPages", wireType)
+			}
+			m.KeptPages = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KeptPages |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedValues", wireType)
+			}
+			m.InspectedValues = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedValues |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeptValues", wireType)
+			}
+			m.KeptValues = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KeptValues |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
+			}
+			m.DurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanos |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TraceSearchMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TraceSearchMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RootServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootTraceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RootTraceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+			}
+			m.StartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
+			}
+			m.DurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationMs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanSet", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SpanSet == nil {
+				m.SpanSet = &SpanSet{}
+			}
+			if err := m.SpanSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanSets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanSets = append(m.SpanSets, &SpanSet{})
+			if err := m.SpanSets[len(m.SpanSets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpanSet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanSet: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanSet: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Spans = append(m.Spans, &Span{})
+			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
+			}
+			m.Matched = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Matched |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attributes = append(m.Attributes, &v1.KeyValue{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Span) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Span: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Span: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+			}
+			m.StartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
+			}
+			m.DurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attributes = append(m.Attributes, &v1.KeyValue{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchMetrics: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedTraces", wireType)
+			}
+			m.InspectedTraces = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedTraces |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBytes", wireType)
+			}
+			m.InspectedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlocks", wireType)
+			}
+			m.TotalBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalBlocks |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompletedJobs", wireType)
+			}
+			m.CompletedJobs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompletedJobs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
+			}
+			m.TotalJobs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalJobs |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlockBytes", wireType)
+			}
+			m.TotalBlockBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalBlockBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagNames", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagNames = append(m.TagNames, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagsV2Response: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagsV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scopes = append(m.Scopes, &SearchTagsV2Scope{})
+			if err := m.Scopes[len(m.Scopes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagsV2Scope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagsV2Scope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagValuesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagValuesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagValuesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagValuesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagValues = append(m.TagValues, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TagValue) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TagValue: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TagValue: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	if m.Kind != 0 {
-		n += 1 + sovTempo(uint64(m.Kind))
+	return nil
+}
+func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTagValuesV2Response: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTagValuesV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagValues = append(m.TagValues, &TagValue{})
+			if err := m.TagValues[len(m.TagValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Trace) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Trace: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Trace: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Batches = append(m.Batches, &v11.ResourceSpans{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PushResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PushResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PushResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func sovTempo(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozTempo(x uint64) (n int) {
-	return sovTempo(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
+func (m *TransferTracesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5083,13 +13025,77 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceByIDRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TransferTracesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TransferTracesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromIngesterID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FromIngesterID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TenantID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TenantID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
 			}
@@ -5123,11 +13129,145 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 				m.TraceID = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Segment", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Segment = append(m.Segment[:0], dAtA[iNdEx:postIndex]...)
+			if m.Segment == nil {
+				m.Segment = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TransferTracesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TransferTracesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TransferTracesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PushBytesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PushBytesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockStart", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5137,29 +13277,32 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BlockStart = string(dAtA[iNdEx:postIndex])
+			var v PreallocBytes
+			m.Traces = append(m.Traces, v)
+			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockEnd", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Ids", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5169,29 +13312,32 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BlockEnd = string(dAtA[iNdEx:postIndex])
+			var v PreallocBytes
+			m.Ids = append(m.Ids, v)
+			if err := m.Ids[len(m.Ids)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 5:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QueryMode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SearchData", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5201,23 +13347,26 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.QueryMode = string(dAtA[iNdEx:postIndex])
+			var v PreallocBytes
+			m.SearchData = append(m.SearchData, v)
+			if err := m.SearchData[len(m.SearchData)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5240,7 +13389,7 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
+func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5263,51 +13412,15 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceByIDResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushSpansRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceByIDResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Trace == nil {
-				m.Trace = &Trace{}
-			}
-			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5334,10 +13447,8 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Metrics == nil {
-				m.Metrics = &TraceByIDMetrics{}
-			}
-			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Batches = append(m.Batches, &v11.ResourceSpans{})
+			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -5362,7 +13473,7 @@ func (m *TraceByIDResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceByIDMetrics) Unmarshal(dAtA []byte) error {
+func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5385,12 +13496,44 @@ func (m *TraceByIDMetrics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceByIDMetrics: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceBytes: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceByIDMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceBytes: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Traces = append(m.Traces, make([]byte, postIndex-iNdEx))
+			copy(m.Traces[len(m.Traces)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -5412,7 +13555,7 @@ func (m *TraceByIDMetrics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchRequest) Unmarshal(dAtA []byte) error {
+func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5435,163 +13578,17 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: LinkSlice: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LinkSlice: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Tags == nil {
-				m.Tags = make(map[string]string)
-			}
-			var mapkey string
-			var mapvalue string
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowTempo
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowTempo
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthTempo
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var stringLenmapvalue uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowTempo
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapvalue |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapvalue := int(stringLenmapvalue)
-					if intStringLenmapvalue < 0 {
-						return ErrInvalidLengthTempo
-					}
-					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
-					if postStringIndexmapvalue < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if postStringIndexmapvalue > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
-					iNdEx = postStringIndexmapvalue
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipTempo(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if (skippy < 0) || (iNdEx+skippy) < 0 {
-						return ErrInvalidLengthTempo
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
-			}
-			m.Tags[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MinDurationMs", wireType)
-			}
-			m.MinDurationMs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.MinDurationMs |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaxDurationMs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Links", wireType)
 			}
-			m.MaxDurationMs = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5601,16 +13598,81 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MaxDurationMs |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.Limit = 0
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Links = append(m.Links, &v11.Span_Link{})
+			if err := m.Links[len(m.Links)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanMetricsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanMetricsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5620,16 +13682,29 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.Start = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5639,16 +13714,29 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Start |= uint32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GroupBy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
 			}
-			m.End = 0
+			m.Limit = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5658,16 +13746,16 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= uint32(b&0x7F) << shift
+				m.Limit |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
 			}
-			var stringLen uint64
+			m.Start = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5677,29 +13765,16 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Start |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Query = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpansPerSpanSet", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
 			}
-			m.SpansPerSpanSet = 0
+			m.End = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5709,7 +13784,7 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SpansPerSpanSet |= uint32(b&0x7F) << shift
+				m.End |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -5735,7 +13810,7 @@ func (m *SearchRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
+func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5758,17 +13833,17 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchBlockRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetricsSummaryRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchBlockRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetricsSummaryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchReq", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5778,31 +13853,27 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.SearchReq == nil {
-				m.SearchReq = &SearchRequest{}
-			}
-			if err := m.SearchReq.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5830,13 +13901,13 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BlockID = string(dAtA[iNdEx:postIndex])
+			m.GroupBy = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
 			}
-			m.StartPage = 0
+			m.Limit = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5846,35 +13917,104 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartPage |= uint32(b&0x7F) << shift
+				m.Limit |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PagesToSearch", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			m.Start = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Start |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-			m.PagesToSearch = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.PagesToSearch |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Encoding", wireType)
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanMetricsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanMetricsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Estimated", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5884,29 +14024,17 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Encoding = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 6:
+			m.Estimated = bool(v != 0)
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IndexPageSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
 			}
-			m.IndexPageSize = 0
+			m.SpanCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5916,16 +14044,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.IndexPageSize |= uint32(b&0x7F) << shift
+				m.SpanCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalRecords", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
 			}
-			m.TotalRecords = 0
+			m.ErrorSpanCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5935,16 +14063,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalRecords |= uint32(b&0x7F) << shift
+				m.ErrorSpanCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DataEncoding", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -5954,61 +14082,81 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DataEncoding = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			m.Metrics = append(m.Metrics, &SpanMetrics{})
+			if err := m.Metrics[len(m.Metrics)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			if postIndex > l {
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RawHistogram) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Version = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 10:
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RawHistogram: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RawHistogram: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
 			}
-			m.Size_ = 0
+			m.Bucket = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6018,16 +14166,16 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Size_ |= uint64(b&0x7F) << shift
+				m.Bucket |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 11:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FooterSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
-			m.FooterSize = 0
+			m.Count = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6037,7 +14185,7 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.FooterSize |= uint32(b&0x7F) << shift
+				m.Count |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -6063,7 +14211,7 @@ func (m *SearchBlockRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchResponse) Unmarshal(dAtA []byte) error {
+func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6086,15 +14234,15 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetrics: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyHistogram", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6121,14 +14269,14 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Traces = append(m.Traces, &TraceSearchMetadata{})
-			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.LatencyHistogram = append(m.LatencyHistogram, &RawHistogram{})
+			if err := m.LatencyHistogram[len(m.LatencyHistogram)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6155,13 +14303,32 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Metrics == nil {
-				m.Metrics = &SearchMetrics{}
+			if m.Static == nil {
+				m.Static = &TraceQLStatic{}
 			}
-			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+			}
+			m.Errors = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Errors |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -6183,7 +14350,7 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
+func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6206,17 +14373,17 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceSearchMetadata: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanMetricsSummary: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceSearchMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanMetricsSummary: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
 			}
-			var stringLen uint64
+			m.SpanCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6226,29 +14393,35 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.SpanCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.ErrorSpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorSpanCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.TraceID = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootServiceName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6258,29 +14431,33 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RootServiceName = string(dAtA[iNdEx:postIndex])
+			if m.Static == nil {
+				m.Static = &TraceQLStatic{}
+			}
+			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootTraceName", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field P99", wireType)
 			}
-			var stringLen uint64
+			m.P99 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6290,29 +14467,16 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.P99 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RootTraceName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P95", wireType)
 			}
-			m.StartTimeUnixNano = 0
+			m.P95 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6322,16 +14486,16 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				m.P95 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P90", wireType)
 			}
-			m.DurationMs = 0
+			m.P90 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6341,16 +14505,16 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.DurationMs |= uint32(b&0x7F) << shift
+				m.P90 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanSet", wireType)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field P50", wireType)
 			}
-			var msglen int
+			m.P50 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6360,31 +14524,64 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.P50 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthTempo
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.SpanSet == nil {
-				m.SpanSet = &SpanSet{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
 			}
-			if err := m.SpanSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-			iNdEx = postIndex
-		case 7:
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SpanMetricsSummaryResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SpanMetricsSummaryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanSets", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Summaries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6411,8 +14608,8 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.SpanSets = append(m.SpanSets, &SpanSet{})
-			if err := m.SpanSets[len(m.SpanSets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Summaries = append(m.Summaries, &SpanMetricsSummary{})
+			if err := m.Summaries[len(m.Summaries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6437,7 +14634,7 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanSet) Unmarshal(dAtA []byte) error {
+func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6460,17 +14657,66 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanSet: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceQLStatic: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanSet: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceQLStatic: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field N", wireType)
+			}
+			m.N = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.N |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field F", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.F = float64(math.Float64frombits(v))
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field S", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6480,31 +14726,29 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Spans = append(m.Spans, &Span{})
-			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.S = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matched", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field B", wireType)
 			}
-			m.Matched = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6514,16 +14758,17 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Matched |= uint32(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			m.B = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field D", wireType)
 			}
-			var msglen int
+			m.D = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6533,26 +14778,49 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.D |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+			}
+			m.Kind = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Kind |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Attributes = append(m.Attributes, &v1.KeyValue{})
-			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -6574,7 +14842,7 @@ func (m *SpanSet) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Span) Unmarshal(dAtA []byte) error {
+func (m *QueryRangeRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6597,15 +14865,15 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Span: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRangeRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Span: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRangeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6633,13 +14901,13 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.SpanID = string(dAtA[iNdEx:postIndex])
+			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
 			}
-			var stringLen uint64
+			m.Start = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6649,29 +14917,16 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Start |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Name = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
 			}
-			m.StartTimeUnixNano = 0
+			m.End = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6681,16 +14936,16 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				m.End |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Step", wireType)
 			}
-			m.DurationNanos = 0
+			m.Step = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6700,14 +14955,14 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.DurationNanos |= uint64(b&0x7F) << shift
+				m.Step |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6734,8 +14989,10 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Attributes = append(m.Attributes, &v1.KeyValue{})
-			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Block == nil {
+				m.Block = &SearchBlockRequest{}
+			}
+			if err := m.Block.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -6760,7 +15017,7 @@ func (m *Span) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
+func (m *QueryRangeResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6783,17 +15040,17 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchMetrics: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRangeResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRangeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InspectedTraces", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Series", wireType)
 			}
-			m.InspectedTraces = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6803,54 +15060,31 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.InspectedTraces |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBytes", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.InspectedBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.InspectedBytes |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlocks", wireType)
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.TotalBlocks = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TotalBlocks |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.Series = append(m.Series, &TimeSeries{})
+			if err := m.Series[len(m.Series)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CompletedJobs", wireType)
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
 			}
-			m.CompletedJobs = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6860,49 +15094,28 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CompletedJobs |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalJobs", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.TotalJobs = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TotalJobs |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalBlockBytes", wireType)
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.TotalBlockBytes = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TotalBlockBytes |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if m.Metrics == nil {
+				m.Metrics = &SearchMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -6924,7 +15137,7 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
+func (m *Sample) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6947,17 +15160,17 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Sample: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Sample: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
 			}
-			var stringLen uint64
+			m.TimestampMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -6967,24 +15180,22 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.TimestampMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			if postIndex > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scope = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -7006,7 +15217,7 @@ func (m *SearchTagsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
+func (m *TimeSeries) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7029,15 +15240,83 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TimeSeries: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TimeSeries: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagNames", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, v1.KeyValue{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, Sample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PromLabels", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7065,7 +15344,7 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagNames = append(m.TagNames, string(dAtA[iNdEx:postIndex]))
+			m.PromLabels = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7088,7 +15367,7 @@ func (m *SearchTagsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
+func (m *TraceDiffResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7111,15 +15390,15 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsV2Response: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceDiffResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceDiffResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Scopes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7146,11 +15425,68 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Scopes = append(m.Scopes, &SearchTagsV2Scope{})
-			if err := m.Scopes[len(m.Scopes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Spans = append(m.Spans, &SpanDiff{})
+			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MatchedSpans", wireType)
+			}
+			m.MatchedSpans = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MatchedSpans |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpansOnlyInA", wireType)
+			}
+			m.SpansOnlyInA = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpansOnlyInA |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpansOnlyInB", wireType)
+			}
+			m.SpansOnlyInB = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpansOnlyInB |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -7172,7 +15508,7 @@ func (m *SearchTagsV2Response) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
+func (m *SpanDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7195,15 +15531,15 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagsV2Scope: wiretype end group for non-group")
+			return fmt.Errorf("proto: SpanDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagsV2Scope: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SpanDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7231,11 +15567,11 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7263,61 +15599,11 @@ func (m *SearchTagsV2Scope) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7345,11 +15631,11 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagName = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanIDA", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7377,61 +15663,11 @@ func (m *SearchTagValuesRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
+			m.SpanIDA = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesResponse: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanIDB", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7459,63 +15695,51 @@ func (m *SearchTagValuesResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagValues = append(m.TagValues, string(dAtA[iNdEx:postIndex]))
+			m.SpanIDB = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanosA", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *TagValue) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+			m.DurationNanosA = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanosA |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanosB", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.DurationNanosB = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanosB |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: TagValue: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TagValue: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationDeltaNanos", wireType)
 			}
-			var stringLen uint64
+			m.DurationDeltaNanos = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7525,29 +15749,16 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.DurationDeltaNanos |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Type = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7557,23 +15768,25 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Value = string(dAtA[iNdEx:postIndex])
+			m.Attributes = append(m.Attributes, AttributeDiff{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7596,7 +15809,7 @@ func (m *TagValue) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
+func (m *AttributeDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7619,17 +15832,17 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SearchTagValuesV2Response: wiretype end group for non-group")
+			return fmt.Errorf("proto: AttributeDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SearchTagValuesV2Response: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AttributeDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagValues", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7639,79 +15852,27 @@ func (m *SearchTagValuesV2Response) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagValues = append(m.TagValues, &TagValue{})
-			if err := m.TagValues[len(m.TagValues)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Key = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Trace) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Trace: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Trace: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field A", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -7738,61 +15899,49 @@ func (m *Trace) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Batches = append(m.Batches, &v11.ResourceSpans{})
-			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.A == nil {
+				m.A = &v1.AnyValue{}
+			}
+			if err := m.A.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field B", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *PushResponse) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if m.B == nil {
+				m.B = &v1.AnyValue{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: PushResponse: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushResponse: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
+			if err := m.B.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -7814,7 +15963,7 @@ func (m *PushResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
+func (m *TraceSummaryRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7837,17 +15986,17 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PushBytesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceSummaryRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushBytesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceSummaryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 2:
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7857,32 +16006,29 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v PreallocBytes
-			m.Traces = append(m.Traces, v)
-			if err := m.Traces[len(m.Traces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Ids", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
 			}
-			var byteLen int
+			m.Start = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7892,32 +16038,54 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.Start |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthTempo
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
+			m.End = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.End |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
 			}
-			var v PreallocBytes
-			m.Ids = append(m.Ids, v)
-			if err := m.Ids[len(m.Ids)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 4:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SearchData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -7927,24 +16095,25 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v PreallocBytes
-			m.SearchData = append(m.SearchData, v)
-			if err := m.SearchData[len(m.SearchData)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Block == nil {
+				m.Block = &SearchBlockRequest{}
+			}
+			if err := m.Block.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -7969,7 +16138,7 @@ func (m *PushBytesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
+func (m *TraceSummaryResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7992,15 +16161,15 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PushSpansRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceSummaryResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PushSpansRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceSummaryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Batches", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Nodes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8027,8 +16196,63 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Batches = append(m.Batches, &v11.ResourceSpans{})
-			if err := m.Batches[len(m.Batches)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Nodes = append(m.Nodes, &TraceSummaryNode{})
+			if err := m.Nodes[len(m.Nodes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceCount", wireType)
+			}
+			m.TraceCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TraceCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metrics == nil {
+				m.Metrics = &SearchMetrics{}
+			}
+			if err := m.Metrics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -8053,7 +16277,7 @@ func (m *PushSpansRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceBytes) Unmarshal(dAtA []byte) error {
+func (m *TraceSummaryNode) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8076,17 +16300,17 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceBytes: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceSummaryNode: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceBytes: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceSummaryNode: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Traces", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8096,79 +16320,61 @@ func (m *TraceBytes) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Traces = append(m.Traces, make([]byte, postIndex-iNdEx))
-			copy(m.Traces[len(m.Traces)-1], dAtA[iNdEx:postIndex])
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *LinkSlice) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: LinkSlice: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LinkSlice: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Links", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8178,81 +16384,78 @@ func (m *LinkSlice) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Links = append(m.Links, &v11.Span_Link{})
-			if err := m.Links[len(m.Links)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTempo
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCount", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+			m.ErrorCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 6:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorRate", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ErrorRate = float64(math.Float64frombits(v))
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field P50", wireType)
 			}
-			var stringLen uint64
+			m.P50 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8262,29 +16465,16 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.P50 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Query = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field P90", wireType)
 			}
-			var stringLen uint64
+			m.P90 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8294,29 +16484,16 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.P90 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.GroupBy = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
+		case 9:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P95", wireType)
 			}
-			m.Limit = 0
+			m.P95 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8326,16 +16503,16 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Limit |= uint64(b&0x7F) << shift
+				m.P95 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 10:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field P99", wireType)
 			}
-			m.Start = 0
+			m.P99 = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8345,16 +16522,16 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Start |= uint32(b&0x7F) << shift
+				m.P99 |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatencyHistogram", wireType)
 			}
-			m.End = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8364,11 +16541,26 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= uint32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LatencyHistogram = append(m.LatencyHistogram, &RawHistogram{})
+			if err := m.LatencyHistogram[len(m.LatencyHistogram)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -8390,7 +16582,7 @@ func (m *SpanMetricsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
+func (m *TraceCriticalPathResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8413,17 +16605,17 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsSummaryRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TraceCriticalPathResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsSummaryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TraceCriticalPathResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Segments", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8433,29 +16625,31 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Query = string(dAtA[iNdEx:postIndex])
+			m.Segments = append(m.Segments, &CriticalPathSegment{})
+			if err := m.Segments[len(m.Segments)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupBy", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Spans", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8465,67 +16659,31 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GroupBy = string(dAtA[iNdEx:postIndex])
+			m.Spans = append(m.Spans, &CriticalPathSpan{})
+			if err := m.Spans[len(m.Spans)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
-			}
-			m.Limit = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Limit |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
-			}
-			m.Start = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Start |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
 			}
-			m.End = 0
+			m.DurationNanos = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8535,7 +16693,7 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.End |= uint32(b&0x7F) << shift
+				m.DurationNanos |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -8561,7 +16719,7 @@ func (m *SpanMetricsSummaryRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
+func (m *CriticalPathSegment) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8584,17 +16742,17 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: CriticalPathSegment: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CriticalPathSegment: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Estimated", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8604,17 +16762,29 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Estimated = bool(v != 0)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
 			}
-			m.SpanCount = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8624,16 +16794,29 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SpanCount |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			m.ErrorSpanCount = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8643,16 +16826,29 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ErrorSpanCount |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
 			}
-			var msglen int
+			m.StartTimeUnixNano = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8662,26 +16858,30 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTempo
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTempo
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
 			}
-			m.Metrics = append(m.Metrics, &SpanMetrics{})
-			if err := m.Metrics[len(m.Metrics)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.DurationNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -8703,7 +16903,7 @@ func (m *SpanMetricsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RawHistogram) Unmarshal(dAtA []byte) error {
+func (m *CriticalPathSpan) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8726,17 +16926,17 @@ func (m *RawHistogram) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RawHistogram: wiretype end group for non-group")
+			return fmt.Errorf("proto: CriticalPathSpan: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RawHistogram: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CriticalPathSpan: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bucket", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanID", wireType)
 			}
-			m.Bucket = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8746,16 +16946,29 @@ func (m *RawHistogram) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Bucket |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SpanID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentSpanID", wireType)
 			}
-			m.Count = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8765,66 +16978,29 @@ func (m *RawHistogram) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Count |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTempo(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTempo
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetrics: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetrics: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.ParentSpanID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LatencyHistogram", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8834,31 +17010,29 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LatencyHistogram = append(m.LatencyHistogram, &RawHistogram{})
-			if err := m.LatencyHistogram[len(m.LatencyHistogram)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8868,33 +17042,48 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTempo
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTempo
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Static == nil {
-				m.Static = &TraceQLStatic{}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTimeUnixNano", wireType)
 			}
-			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.StartTimeUnixNano = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTimeUnixNano |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 3:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationNanos", wireType)
 			}
-			m.Errors = 0
+			m.DurationNanos = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8904,7 +17093,64 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Errors |= uint64(b&0x7F) << shift
+				m.DurationNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SelfTimeNanos", wireType)
+			}
+			m.SelfTimeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SelfTimeNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChildrenTimeNanos", wireType)
+			}
+			m.ChildrenTimeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ChildrenTimeNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CriticalPathNanos", wireType)
+			}
+			m.CriticalPathNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CriticalPathNanos |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -8930,7 +17176,7 @@ func (m *SpanMetrics) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
+func (m *AttributeProfileRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8953,17 +17199,17 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsSummary: wiretype end group for non-group")
+			return fmt.Errorf("proto: AttributeProfileRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsSummary: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AttributeProfileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Service", wireType)
 			}
-			m.SpanCount = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -8973,33 +17219,77 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SpanCount |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ErrorSpanCount", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			m.ErrorSpanCount = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.ErrorSpanCount |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Service = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AttributeProfileResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
 			}
-		case 3:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AttributeProfileResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AttributeProfileResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Static", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Services", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -9026,89 +17316,11 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Static == nil {
-				m.Static = &TraceQLStatic{}
-			}
-			if err := m.Static.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Services = append(m.Services, &ServiceAttributeProfile{})
+			if err := m.Services[len(m.Services)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P99", wireType)
-			}
-			m.P99 = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.P99 |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P95", wireType)
-			}
-			m.P95 = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.P95 |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P90", wireType)
-			}
-			m.P90 = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.P90 |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field P50", wireType)
-			}
-			m.P50 = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTempo
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.P50 |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -9130,7 +17342,7 @@ func (m *SpanMetricsSummary) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
+func (m *ServiceAttributeProfile) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -9153,15 +17365,66 @@ func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SpanMetricsSummaryResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ServiceAttributeProfile: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SpanMetricsSummaryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ServiceAttributeProfile: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Summaries", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Service", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Service = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			}
+			m.SpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -9188,8 +17451,8 @@ func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Summaries = append(m.Summaries, &SpanMetricsSummary{})
-			if err := m.Summaries[len(m.Summaries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Attributes = append(m.Attributes, &AttributeProfile{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -9214,7 +17477,7 @@ func (m *SpanMetricsSummaryResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
+func (m *AttributeProfile) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -9237,17 +17500,17 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TraceQLStatic: wiretype end group for non-group")
+			return fmt.Errorf("proto: AttributeProfile: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TraceQLStatic: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AttributeProfile: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
 			}
-			m.Type = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9257,16 +17520,29 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= int32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scope = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field N", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
-			m.N = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9276,25 +17552,27 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.N |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field F", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
 			}
-			var v uint64
-			if (iNdEx + 8) > l {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.F = float64(math.Float64frombits(v))
-		case 4:
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field S", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Types", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -9322,13 +17600,13 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.S = string(dAtA[iNdEx:postIndex])
+			m.Types = append(m.Types, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 5:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field B", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
-			var v int
+			m.Count = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9338,17 +17616,16 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				m.Count |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.B = bool(v != 0)
-		case 6:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field D", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
 			}
-			m.D = 0
+			m.Bytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9358,16 +17635,16 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.D |= uint64(b&0x7F) << shift
+				m.Bytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cardinality", wireType)
 			}
-			m.Status = 0
+			m.Cardinality = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9377,16 +17654,16 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= int32(b&0x7F) << shift
+				m.Cardinality |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sketch", wireType)
 			}
-			m.Kind = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTempo
@@ -9396,11 +17673,26 @@ func (m *TraceQLStatic) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Kind |= int32(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sketch = append(m.Sketch[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sketch == nil {
+				m.Sketch = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
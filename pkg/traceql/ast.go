@@ -1,9 +1,14 @@
 package traceql
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
 )
 
 type Element interface {
@@ -21,8 +26,19 @@ type typedExpression interface {
 	impliedType() StaticType
 }
 
+// metricsFirstStageElement is the first stage of a metrics query. It observes
+// the spans returned by the spanset pipeline and turns them into time series.
+type metricsFirstStageElement interface {
+	Element
+	extractConditions(request *FetchSpansRequest)
+	init(req *tempopb.QueryRangeRequest)
+	observe(Span)
+	result() SeriesSet
+}
+
 type RootExpr struct {
-	Pipeline Pipeline
+	Pipeline        Pipeline
+	MetricsPipeline metricsFirstStageElement
 }
 
 func newRootExpr(e pipelineElement) *RootExpr {
@@ -36,6 +52,12 @@ func newRootExpr(e pipelineElement) *RootExpr {
 	}
 }
 
+func newRootExprWithMetrics(e pipelineElement, m metricsFirstStageElement) *RootExpr {
+	r := newRootExpr(e)
+	r.MetricsPipeline = m
+	return r
+}
+
 // **********************
 // Pipeline
 // **********************
@@ -236,6 +258,15 @@ func (o SpansetOperation) extractConditions(request *FetchSpansRequest) {
 	o.LHS.extractConditions(request)
 	o.RHS.extractConditions(request)
 	request.AllConditions = false
+
+	// structural operators require the nested set values of the spans
+	if o.Op.isStructural() {
+		for _, c := range nestedSetConditions() {
+			if !request.hasCondition(c) {
+				request.appendCondition(c)
+			}
+		}
+	}
 }
 
 func newSpansetOperation(op Operator, lhs SpansetExpression, rhs SpansetExpression) SpansetOperation {
@@ -400,6 +431,10 @@ func newUnaryOperation(op Operator, e FieldExpression) UnaryOperation {
 func (UnaryOperation) __fieldExpression() {}
 
 func (o UnaryOperation) impliedType() StaticType {
+	if o.Op == OpLen {
+		return TypeInt
+	}
+
 	// both operators (opPower and opNot) will just be based on the operand type
 	return o.Expression.impliedType()
 }
@@ -420,6 +455,9 @@ type Static struct {
 	D      time.Duration
 	Status Status // todo: can we just use the N member for status and kind?
 	Kind   Kind
+	// elems holds the elements of arrays, S their encoding so equal arrays are equal. elems is ignored by
+	// Equals and dropped by MapKey.
+	elems *[]Static
 }
 
 // nolint: revive
@@ -455,7 +493,14 @@ func (s Static) Equals(other Static) bool {
 	}
 
 	// no special cases, just compare directly
-	return s == other
+	return s.MapKey() == other.MapKey()
+}
+
+// MapKey returns the static to use as a map key. Equal statics have equal keys, the elements of arrays are
+// dropped and must be read from the original static.
+func (s Static) MapKey() Static {
+	s.elems = nil
+	return s
 }
 
 func (s Static) compare(other *Static) int {
@@ -609,6 +654,64 @@ func NewStaticKind(k Kind) Static {
 	}
 }
 
+// Arrays keep their elements and an encoding of the elements in S to keep Static comparable.
+
+func NewStaticStringArray(ss []string) Static {
+	elems := make([]Static, 0, len(ss))
+	for _, e := range ss {
+		elems = append(elems, NewStaticString(e))
+	}
+	// marshalling strings can't fail
+	b, _ := json.Marshal(ss)
+	return newStaticArray(TypeStringArray, string(b), elems)
+}
+
+func NewStaticIntArray(ns []int) Static {
+	elems := make([]Static, 0, len(ns))
+	encoded := make([]string, 0, len(ns))
+	for _, n := range ns {
+		elems = append(elems, NewStaticInt(n))
+		encoded = append(encoded, strconv.Itoa(n))
+	}
+	return newStaticArray(TypeIntArray, strings.Join(encoded, ","), elems)
+}
+
+func NewStaticFloatArray(fs []float64) Static {
+	elems := make([]Static, 0, len(fs))
+	encoded := make([]string, 0, len(fs))
+	for _, f := range fs {
+		elems = append(elems, NewStaticFloat(f))
+		encoded = append(encoded, strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return newStaticArray(TypeFloatArray, strings.Join(encoded, ","), elems)
+}
+
+func NewStaticBooleanArray(bs []bool) Static {
+	elems := make([]Static, 0, len(bs))
+	encoded := make([]string, 0, len(bs))
+	for _, b := range bs {
+		elems = append(elems, NewStaticBool(b))
+		encoded = append(encoded, strconv.FormatBool(b))
+	}
+	return newStaticArray(TypeBooleanArray, strings.Join(encoded, ","), elems)
+}
+
+func newStaticArray(t StaticType, encoded string, elems []Static) Static {
+	return Static{
+		Type:  t,
+		S:     encoded,
+		elems: &elems,
+	}
+}
+
+// elements returns the elements of an array. nil is returned for all other types and for map keys.
+func (s Static) elements() []Static {
+	if s.elems == nil {
+		return nil
+	}
+	return *s.elems
+}
+
 // **********************
 // Attributes
 // **********************
@@ -653,6 +756,12 @@ func (a Attribute) impliedType() StaticType {
 		return TypeString
 	case IntrinsicTraceRootSpan:
 		return TypeString
+	case IntrinsicEventName:
+		return TypeString
+	case IntrinsicLinkTraceID:
+		return TypeString
+	case IntrinsicLinkSpanID:
+		return TypeString
 	}
 
 	return TypeAttribute
@@ -663,11 +772,11 @@ func (Attribute) referencesSpan() bool {
 }
 
 // NewScopedAttribute creates a new scopedattribute with the given identifier string.
-// this handles parent, span, and resource scopes.
+// this handles parent, span, resource, event and link scopes.
 func NewScopedAttribute(scope AttributeScope, parent bool, att string) Attribute {
 	intrinsic := IntrinsicNone
-	// if we are explicitly passed a resource or span scopes then we shouldn't parse for intrinsic
-	if scope != AttributeScopeResource && scope != AttributeScopeSpan {
+	// if we are explicitly passed a resource, span, event or link scope then we shouldn't parse for intrinsic
+	if scope == AttributeScopeNone {
 		intrinsic = intrinsicFromString(att)
 	}
 
@@ -695,3 +804,35 @@ var _ pipelineElement = (*SpansetFilter)(nil)
 var _ pipelineElement = (*CoalesceOperation)(nil)
 var _ pipelineElement = (*ScalarFilter)(nil)
 var _ pipelineElement = (*GroupOperation)(nil)
+
+// **********************
+// Metrics
+// **********************
+
+// MetricsAggregate is a metrics function, i.e. rate() or quantile_over_time(),
+// applied to the spans matched by the pipeline.
+type MetricsAggregate struct {
+	op     MetricsAggregateOp
+	by     []Attribute
+	attr   Attribute
+	floats []float64
+	agg    SpanAggregator
+}
+
+func newMetricsAggregate(agg MetricsAggregateOp, by []Attribute) *MetricsAggregate {
+	return &MetricsAggregate{
+		op: agg,
+		by: by,
+	}
+}
+
+func newMetricsAggregateQuantileOverTime(attr Attribute, qs []float64, by []Attribute) *MetricsAggregate {
+	return &MetricsAggregate{
+		op:     metricsAggregateQuantileOverTime,
+		floats: qs,
+		attr:   attr,
+		by:     by,
+	}
+}
+
+var _ metricsFirstStageElement = (*MetricsAggregate)(nil)
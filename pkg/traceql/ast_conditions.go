@@ -82,3 +82,14 @@ func (a Attribute) extractConditions(request *FetchSpansRequest) {
 		Operands:  nil,
 	})
 }
+
+func (a *MetricsAggregate) extractConditions(request *FetchSpansRequest) {
+	// Group-by and quantile attributes are only needed for the spans matched
+	// by the pipeline so they are fetched in the second pass.
+	for _, b := range a.by {
+		request.SecondPassConditions = append(request.SecondPassConditions, Condition{Attribute: b})
+	}
+	if a.op == metricsAggregateQuantileOverTime {
+		request.SecondPassConditions = append(request.SecondPassConditions, Condition{Attribute: a.attr})
+	}
+}
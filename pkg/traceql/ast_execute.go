@@ -28,14 +28,14 @@ func (g GroupOperation) evaluate(ss []*Spanset) ([]*Spanset, error) {
 			}
 
 			// Check if the result already has a group in the map
-			group, ok := groups[result]
+			group, ok := groups[result.MapKey()]
 			if !ok {
 				// If not, create a new group and add it to the map
 				group = &Spanset{}
 				// copy all existing attributes forward
 				group.Attributes = append(group.Attributes, spanset.Attributes...)
 				group.AddAttribute(g.String(), result)
-				groups[result] = group
+				groups[result.MapKey()] = group
 			}
 
 			// Add the current spanset to the group
@@ -99,6 +99,15 @@ func (o SpansetOperation) evaluate(input []*Spanset) (output []*Spanset, err err
 				output = append(output, matchingSpanset)
 			}
 
+		case OpSpansetChild, OpSpansetParent, OpSpansetDescendant, OpSpansetAncestor, OpSpansetSibling,
+			OpSpansetNotChild, OpSpansetNotParent, OpSpansetNotDescendant, OpSpansetNotAncestor, OpSpansetNotSibling:
+			matches := structuralMatches(o.Op, allSpans(lhs), allSpans(rhs))
+			if len(matches) > 0 {
+				matchingSpanset := input[i].clone()
+				matchingSpanset.Spans = matches
+				output = append(output, matchingSpanset)
+			}
+
 		default:
 			return nil, fmt.Errorf("spanset operation (%v) not supported", o.Op)
 		}
@@ -107,6 +116,28 @@ func (o SpansetOperation) evaluate(input []*Spanset) (output []*Spanset, err err
 	return output, nil
 }
 
+// allSpans returns the unique spans of all given spansets
+func allSpans(ss []*Spanset) []Span {
+	if len(ss) == 1 {
+		return ss[0].Spans
+	}
+
+	var (
+		spans []Span
+		seen  = map[Span]struct{}{}
+	)
+	for _, s := range ss {
+		for _, span := range s.Spans {
+			if _, ok := seen[span]; ok {
+				continue
+			}
+			seen[span] = struct{}{}
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
 // SelectOperation evaluate is a no-op b/c the fetch layer has already decorated the spans with the requested attributes
 func (o SelectOperation) evaluate(input []*Spanset) (output []*Spanset, err error) {
 	return input, nil
@@ -258,6 +289,10 @@ func (o BinaryOperation) execute(span Span) (Static, error) {
 		return NewStaticBool(false), nil
 	}
 
+	if lhsT.isArray() || rhsT.isArray() {
+		return executeArrayOperation(o.Op, lhs, rhs)
+	}
+
 	if lhsT == TypeString && rhsT == TypeString {
 		switch o.Op {
 		case OpGreater:
@@ -312,6 +347,53 @@ func (o BinaryOperation) execute(span Span) (Static, error) {
 	}
 }
 
+// executeArrayOperation compares arrays element by element. An array matches if any of its elements
+// matches. The negated operators != and !~ match if none of the elements matches. Two arrays can only
+// be compared for equality.
+func executeArrayOperation(op Operator, lhs, rhs Static) (Static, error) {
+	if lhs.Type.isArray() && rhs.Type.isArray() {
+		switch op {
+		case OpEqual:
+			return NewStaticBool(lhs.Equals(rhs)), nil
+		case OpNotEqual:
+			return NewStaticBool(!lhs.Equals(rhs)), nil
+		}
+		return NewStaticBool(false), nil
+	}
+
+	negated := false
+	switch op {
+	case OpNotEqual:
+		op, negated = OpEqual, true
+	case OpNotRegex:
+		op, negated = OpRegex, true
+	}
+
+	var elems []Static
+	if lhs.Type.isArray() {
+		elems = lhs.elements()
+	} else {
+		elems = rhs.elements()
+	}
+
+	for _, e := range elems {
+		elemOp := BinaryOperation{Op: op, LHS: e, RHS: rhs}
+		if rhs.Type.isArray() {
+			elemOp = BinaryOperation{Op: op, LHS: lhs, RHS: e}
+		}
+
+		matched, err := elemOp.execute(nil)
+		if err != nil {
+			return NewStaticNil(), err
+		}
+		if matched.Type == TypeBoolean && matched.B {
+			return NewStaticBool(!negated), nil
+		}
+	}
+
+	return NewStaticBool(negated), nil
+}
+
 // why does this and the above exist?
 func binOp(op Operator, lhs, rhs Static) (bool, error) {
 	lhsT := lhs.impliedType()
@@ -358,6 +440,12 @@ func (o UnaryOperation) execute(span Span) (Static, error) {
 		}
 		return NewStaticBool(!static.B), nil
 	}
+	if o.Op == OpLen {
+		if !static.Type.isArray() {
+			return NewStaticNil(), nil
+		}
+		return NewStaticInt(len(static.elements())), nil
+	}
 	if o.Op == OpSub {
 		if !static.Type.isNumeric() {
 			return NewStaticNil(), fmt.Errorf("expression (%v) expected a numeric, but got %v", o, static.Type)
@@ -372,7 +460,7 @@ func (o UnaryOperation) execute(span Span) (Static, error) {
 		}
 	}
 
-	return NewStaticNil(), errors.New("UnaryOperation has Op different from Not and Sub")
+	return NewStaticNil(), errors.New("UnaryOperation has Op different from Not, Sub and Len")
 }
 
 func (s Static) execute(span Span) (Static, error) {
@@ -393,6 +481,10 @@ func (a Attribute) execute(span Span) (Static, error) {
 			}
 		}
 		for attribute, static := range atts {
+			// unscoped attributes don't match attributes of events and links
+			if attribute.Scope == AttributeScopeEvent || attribute.Scope == AttributeScopeLink {
+				continue
+			}
 			if a.Name == attribute.Name {
 				return static, nil
 			}
@@ -161,6 +161,35 @@ func TestSpansetFilter_matches(t *testing.T) {
 			},
 			matches: true,
 		},
+		{
+			query: `{ event.foo = "scope_event" && link.foo = "scope_link" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeEvent, false, "foo"): NewStaticString("scope_event"),
+					NewScopedAttribute(AttributeScopeLink, false, "foo"):  NewStaticString("scope_link"),
+				},
+			},
+			matches: true,
+		},
+		{
+			query: `{ .foo = "scope_event" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewScopedAttribute(AttributeScopeEvent, false, "foo"): NewStaticString("scope_event"),
+				},
+			},
+			matches: false,
+		},
+		{
+			query: `{ event:name = "exception" }`,
+			span: &mockSpan{
+				attributes: map[Attribute]Static{
+					NewIntrinsic(IntrinsicName):      NewStaticString("span"),
+					NewIntrinsic(IntrinsicEventName): NewStaticString("exception"),
+				},
+			},
+			matches: true,
+		},
 	}
 	for _, tt := range tests {
 		// create a evalTC and use testEvaluator
@@ -977,3 +1006,103 @@ func BenchmarkAggregate(b *testing.B) {
 		_, _ = agg.evaluate(ss)
 	}
 }
+
+func TestArrays(t *testing.T) {
+	spans := []Span{
+		&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{
+			NewScopedAttribute(AttributeScopeSpan, false, "tags"):  NewStaticStringArray([]string{"foo", "bar"}),
+			NewScopedAttribute(AttributeScopeSpan, false, "codes"): NewStaticIntArray([]int{200, 404}),
+		}},
+		&mockSpan{id: []byte{2}, attributes: map[Attribute]Static{
+			NewScopedAttribute(AttributeScopeSpan, false, "tags"):  NewStaticStringArray([]string{"baz"}),
+			NewScopedAttribute(AttributeScopeSpan, false, "codes"): NewStaticIntArray([]int{500}),
+		}},
+		&mockSpan{id: []byte{3}, attributes: map[Attribute]Static{
+			NewScopedAttribute(AttributeScopeSpan, false, "tags"): NewStaticString("foo"),
+		}},
+	}
+
+	testCases := []struct {
+		query    string
+		expected []byte
+	}{
+		{`{ span.tags = "foo" }`, []byte{1, 3}},
+		{`{ "bar" = span.tags }`, []byte{1}},
+		{`{ span.tags =~ "ba.*" }`, []byte{1, 2}},
+		{`{ span.tags != "foo" }`, []byte{2}},
+		{`{ span.tags !~ "ba.*" }`, []byte{3}},
+		{`{ span.codes >= 404 }`, []byte{1, 2}},
+		{`{ span.codes < 300 }`, []byte{1}},
+		{`{ span.codes = 404.0 }`, []byte{1}},
+		{`{ span.codes = "404" }`, nil},
+		{`{ len(span.tags) = 2 }`, []byte{1}},
+		{`{ len(span.tags) > 0 }`, []byte{1, 2}},
+		{`{ len(span.codes) = 1 && span.codes > 400 }`, []byte{2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			ast, err := Parse(tc.query)
+			require.NoError(t, err)
+			require.NoError(t, ast.validate())
+
+			actual, err := ast.Pipeline.evaluate([]*Spanset{{Spans: spans}})
+			require.NoError(t, err)
+
+			var ids []byte
+			for _, ss := range actual {
+				for _, s := range ss.Spans {
+					ids = append(ids, s.ID()...)
+				}
+			}
+			require.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestArrayStatics(t *testing.T) {
+	testCases := []struct {
+		static   Static
+		elements []Static
+		str      string
+	}{
+		{NewStaticStringArray([]string{"a", "b,c"}), []Static{NewStaticString("a"), NewStaticString("b,c")}, "[`a`, `b,c`]"},
+		{NewStaticIntArray([]int{1, -2}), []Static{NewStaticInt(1), NewStaticInt(-2)}, "[1, -2]"},
+		{NewStaticFloatArray([]float64{1.5}), []Static{NewStaticFloat(1.5)}, "[1.50000]"},
+		{NewStaticBooleanArray([]bool{true, false}), []Static{NewStaticBool(true), NewStaticBool(false)}, "[true, false]"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.elements, tc.static.elements())
+		require.Equal(t, tc.str, tc.static.String())
+	}
+
+	require.True(t, NewStaticIntArray([]int{1, 2}).Equals(NewStaticIntArray([]int{1, 2})))
+	require.False(t, NewStaticIntArray([]int{1, 2}).Equals(NewStaticIntArray([]int{2, 1})))
+}
+
+func TestGroupByArray(t *testing.T) {
+	tags := NewScopedAttribute(AttributeScopeSpan, false, "tags")
+	spans := []Span{
+		&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{tags: NewStaticStringArray([]string{"foo", "bar"})}},
+		&mockSpan{id: []byte{2}, attributes: map[Attribute]Static{tags: NewStaticStringArray([]string{"foo", "bar"})}},
+		&mockSpan{id: []byte{3}, attributes: map[Attribute]Static{tags: NewStaticStringArray([]string{"baz"})}},
+	}
+
+	ast, err := Parse(`{ } | by(span.tags)`)
+	require.NoError(t, err)
+
+	actual, err := ast.Pipeline.evaluate([]*Spanset{{Spans: spans}})
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+
+	// equal arrays are grouped together and the group keeps the elements of the array
+	for _, ss := range actual {
+		group := ss.Attributes[0].Val
+		if len(ss.Spans) == 2 {
+			require.Equal(t, []Static{NewStaticString("foo"), NewStaticString("bar")}, group.elements())
+		} else {
+			require.Equal(t, []Static{NewStaticString("baz")}, group.elements())
+		}
+	}
+}
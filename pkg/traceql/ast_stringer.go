@@ -7,6 +7,9 @@ import (
 )
 
 func (r RootExpr) String() string {
+	if r.MetricsPipeline != nil {
+		return r.Pipeline.String() + "|" + r.MetricsPipeline.String()
+	}
 	return r.Pipeline.String()
 }
 
@@ -34,6 +37,28 @@ func (o SelectOperation) String() string {
 	return "select(" + strings.Join(s, ", ") + ")"
 }
 
+func (a *MetricsAggregate) String() string {
+	s := a.op.String() + "("
+	if a.op == metricsAggregateQuantileOverTime {
+		qs := make([]string, 0, len(a.floats)+1)
+		qs = append(qs, a.attr.String())
+		for _, f := range a.floats {
+			qs = append(qs, strconv.FormatFloat(f, 'f', -1, 64))
+		}
+		s += strings.Join(qs, ", ")
+	}
+	s += ")"
+
+	if len(a.by) > 0 {
+		by := make([]string, 0, len(a.by))
+		for _, b := range a.by {
+			by = append(by, b.String())
+		}
+		s += " by(" + strings.Join(by, ", ") + ")"
+	}
+	return s
+}
+
 func (o ScalarOperation) String() string {
 	return binaryOp(o.Op, o.LHS, o.RHS)
 }
@@ -63,6 +88,9 @@ func (o BinaryOperation) String() string {
 }
 
 func (o UnaryOperation) String() string {
+	if o.Op == OpLen {
+		return o.Op.String() + "(" + o.Expression.String() + ")"
+	}
 	return unaryOp(o.Op, o.Expression)
 }
 
@@ -91,6 +119,13 @@ func (n Static) EncodeToString(quotes bool) string {
 		return n.Status.String()
 	case TypeKind:
 		return n.Kind.String()
+	case TypeStringArray, TypeIntArray, TypeFloatArray, TypeBooleanArray:
+		elems := n.elements()
+		encoded := make([]string, 0, len(elems))
+		for _, e := range elems {
+			encoded = append(encoded, e.EncodeToString(quotes))
+		}
+		return "[" + strings.Join(encoded, ", ") + "]"
 	}
 
 	return fmt.Sprintf("static(%d)", n.Type)
@@ -345,6 +345,8 @@ type mockSpan struct {
 	startTimeUnixNanos uint64
 	durationNanos      uint64
 	attributes         map[Attribute]Static
+
+	nestedSetLeft, nestedSetRight, nestedSetParent int32
 }
 
 func (m *mockSpan) Attributes() map[Attribute]Static {
@@ -359,3 +361,12 @@ func (m *mockSpan) StartTimeUnixNanos() uint64 {
 func (m *mockSpan) DurationNanos() uint64 {
 	return m.durationNanos
 }
+func (m *mockSpan) NestedSetLeft() int32 {
+	return m.nestedSetLeft
+}
+func (m *mockSpan) NestedSetRight() int32 {
+	return m.nestedSetRight
+}
+func (m *mockSpan) NestedSetParent() int32 {
+	return m.nestedSetParent
+}
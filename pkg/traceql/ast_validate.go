@@ -16,7 +16,15 @@ func (e unsupportedError) Error() string {
 }
 
 func (r RootExpr) validate() error {
-	return r.Pipeline.validate()
+	err := r.Pipeline.validate()
+	if err != nil {
+		return err
+	}
+
+	if r.MetricsPipeline != nil {
+		return r.MetricsPipeline.validate()
+	}
+	return nil
 }
 
 func (p Pipeline) validate() error {
@@ -29,6 +37,39 @@ func (p Pipeline) validate() error {
 	return nil
 }
 
+func (a *MetricsAggregate) validate() error {
+	if len(a.by) > maxGroupBys {
+		return fmt.Errorf("metrics group by %d values exceeds maximum of %d: %s", len(a.by), maxGroupBys, a.String())
+	}
+	for _, b := range a.by {
+		if err := b.validate(); err != nil {
+			return err
+		}
+	}
+
+	if a.op == metricsAggregateQuantileOverTime {
+		if err := a.attr.validate(); err != nil {
+			return err
+		}
+		switch a.attr.impliedType() {
+		case TypeAttribute, TypeInt, TypeFloat, TypeDuration:
+		default:
+			return fmt.Errorf("quantile_over_time requires a numeric attribute: %s", a.String())
+		}
+
+		if len(a.floats) == 0 {
+			return fmt.Errorf("quantile_over_time requires at least one quantile: %s", a.String())
+		}
+		for _, q := range a.floats {
+			if q < 0 || q > 1 {
+				return fmt.Errorf("quantile_over_time quantiles must be between 0 and 1: %s", a.String())
+			}
+		}
+	}
+
+	return nil
+}
+
 func (o GroupOperation) validate() error {
 	// todo: once grouping is supported the below validation will apply
 	if !o.Expression.referencesSpan() {
@@ -110,12 +151,6 @@ func (o SpansetOperation) validate() error {
 		return err
 	}
 
-	// supported spanset operations
-	switch o.Op {
-	case OpSpansetChild, OpSpansetDescendant, OpSpansetSibling:
-		return newUnsupportedError(fmt.Sprintf("spanset operation (%v)", o.Op))
-	}
-
 	return nil
 }
 
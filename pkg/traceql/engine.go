@@ -2,9 +2,11 @@ package traceql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -47,6 +49,9 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 	if err != nil {
 		return nil, err
 	}
+	if rootExpr.MetricsPipeline != nil {
+		return nil, errors.New("metrics queries are not supported by search, use the metrics query_range api")
+	}
 
 	fetchSpansRequest := e.createFetchSpansRequest(searchReq, rootExpr.Pipeline)
 
@@ -177,7 +182,9 @@ func (e *Engine) ExecuteTagValues(
 	var collectAttributeValue func(s Span) bool
 	switch tag.Scope {
 	case AttributeScopeResource,
-		AttributeScopeSpan: // If tag is scoped, we can check the map directly
+		AttributeScopeSpan,
+		AttributeScopeEvent,
+		AttributeScopeLink: // If tag is scoped, we can check the map directly
 		collectAttributeValue = func(s Span) bool {
 			if v, ok := s.Attributes()[tag]; ok {
 				return cb(v)
@@ -313,14 +320,25 @@ func (e *Engine) asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMet
 
 			staticAnyValue := static.asAnyValue()
 
+			key := attribute.Name
+			// attributes of events and links keep their scope to not be confused with span attributes
+			if attribute.Scope == AttributeScopeEvent || attribute.Scope == AttributeScopeLink {
+				key = attribute.String()
+			}
+
 			keyValue := &common_v1.KeyValue{
-				Key:   attribute.Name,
+				Key:   key,
 				Value: staticAnyValue,
 			}
 
 			tempopbSpan.Attributes = append(tempopbSpan.Attributes, keyValue)
 		}
 
+		// attributes are stored in a map, sort them to return the columns requested by select() in a stable order
+		sort.Slice(tempopbSpan.Attributes, func(i, j int) bool {
+			return tempopbSpan.Attributes[i].Key < tempopbSpan.Attributes[j].Key
+		})
+
 		metadata.SpanSet.Spans = append(metadata.SpanSet.Spans, tempopbSpan)
 	}
 
@@ -403,6 +421,19 @@ func (s Static) asAnyValue() *common_v1.AnyValue {
 				StringValue: s.Kind.String(),
 			},
 		}
+	case TypeStringArray, TypeIntArray, TypeFloatArray, TypeBooleanArray:
+		elems := s.elements()
+		values := make([]*common_v1.AnyValue, 0, len(elems))
+		for _, e := range elems {
+			values = append(values, e.asAnyValue())
+		}
+		return &common_v1.AnyValue{
+			Value: &common_v1.AnyValue_ArrayValue{
+				ArrayValue: &common_v1.ArrayValue{
+					Values: values,
+				},
+			},
+		}
 	}
 
 	return &common_v1.AnyValue{
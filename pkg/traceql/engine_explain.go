@@ -0,0 +1,54 @@
+package traceql
+
+import (
+	"errors"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// ExplainSearch returns how the search request is executed by ExecuteSearch: the stages of the pipeline
+// and the conditions the storage layer is asked to fetch. Conditions with an operator are pushed down to
+// the storage layer, conditions without one only fetch the attribute and are evaluated by the engine.
+// The statistics of the searched blocks and iterators are left to the caller.
+func (e *Engine) ExplainSearch(searchReq *tempopb.SearchRequest) (*tempopb.SearchExplain, error) {
+	rootExpr, err := e.parseQuery(searchReq)
+	if err != nil {
+		return nil, err
+	}
+	if rootExpr.MetricsPipeline != nil {
+		return nil, errors.New("metrics queries are not supported by search, use the metrics query_range api")
+	}
+
+	fetchSpansRequest := e.createFetchSpansRequest(searchReq, rootExpr.Pipeline)
+	fetchSpansRequest.SecondPassConditions = append(fetchSpansRequest.SecondPassConditions, SearchMetaConditionsWithout(fetchSpansRequest.Conditions)...)
+
+	explain := &tempopb.SearchExplain{
+		Pipeline:             make([]string, 0, len(rootExpr.Pipeline.Elements)),
+		Conditions:           explainConditions(fetchSpansRequest.Conditions),
+		AllConditions:        fetchSpansRequest.AllConditions,
+		SecondPassConditions: explainConditions(fetchSpansRequest.SecondPassConditions),
+	}
+	for _, element := range rootExpr.Pipeline.Elements {
+		explain.Pipeline = append(explain.Pipeline, element.String())
+	}
+
+	return explain, nil
+}
+
+func explainConditions(conditions []Condition) []*tempopb.ExplainCondition {
+	explained := make([]*tempopb.ExplainCondition, 0, len(conditions))
+	for _, c := range conditions {
+		ec := &tempopb.ExplainCondition{
+			Attribute:  c.Attribute.String(),
+			PushedDown: c.Op != OpNone,
+		}
+		if c.Op != OpNone {
+			ec.Op = c.Op.String()
+		}
+		for _, o := range c.Operands {
+			ec.Operands = append(ec.Operands, o.String())
+		}
+		explained = append(explained, ec)
+	}
+	return explained
+}
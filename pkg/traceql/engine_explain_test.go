@@ -0,0 +1,51 @@
+package traceql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestEngine_ExplainSearch(t *testing.T) {
+	e := NewEngine()
+
+	explain, err := e.ExplainSearch(&tempopb.SearchRequest{Query: `{ .foo = "bar" && .baz > 2 } | select(.qux)`})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"{ (.foo = `bar`) && (.baz > 2) }", "select(.qux)"}, explain.Pipeline)
+	// pipelines with more than one stage don't require all conditions
+	assert.False(t, explain.AllConditions)
+	assert.ElementsMatch(t, []*tempopb.ExplainCondition{
+		{Attribute: ".foo", Op: "=", Operands: []string{"`bar`"}, PushedDown: true},
+		{Attribute: ".baz", Op: ">", Operands: []string{"2"}, PushedDown: true},
+	}, explain.Conditions)
+
+	// select() and the metadata of the results are fetched in the second pass
+	secondPass := map[string]bool{}
+	for _, c := range explain.SecondPassConditions {
+		assert.False(t, c.PushedDown)
+		secondPass[c.Attribute] = true
+	}
+	assert.True(t, secondPass[".qux"])
+	assert.True(t, secondPass["rootServiceName"])
+
+	explain, err = e.ExplainSearch(&tempopb.SearchRequest{Query: `{ .foo = "bar" }`})
+	require.NoError(t, err)
+	assert.True(t, explain.AllConditions)
+
+	// conditions of an or are fetched independently, the engine evaluates the expression
+	explain, err = e.ExplainSearch(&tempopb.SearchRequest{Query: `{ .foo = "bar" || .baz = .qux }`})
+	require.NoError(t, err)
+	assert.False(t, explain.AllConditions)
+	assert.ElementsMatch(t, []*tempopb.ExplainCondition{
+		{Attribute: ".foo", Op: "=", Operands: []string{"`bar`"}, PushedDown: true},
+		{Attribute: ".baz"},
+		{Attribute: ".qux"},
+	}, explain.Conditions)
+
+	_, err = e.ExplainSearch(&tempopb.SearchRequest{Query: `{ .foo = `})
+	assert.Error(t, err)
+}
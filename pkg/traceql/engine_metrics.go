@@ -0,0 +1,689 @@
+package traceql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	// maxGroupBys is the maximum number of attributes a metrics query can be grouped by.
+	maxGroupBys = 5
+
+	internalLabelBucket = "__bucket"
+	labelQuantile       = "p"
+)
+
+// AlignRequest shifts the start and end of the request so that they are aligned
+// to the step. The start is rounded down and the end is rounded up, so that the
+// aligned range always covers the original one.
+func AlignRequest(req *tempopb.QueryRangeRequest) {
+	if req.Step == 0 {
+		return
+	}
+
+	req.Start = req.Start / req.Step * req.Step
+	if mod := req.End % req.Step; mod > 0 {
+		req.End += req.Step - mod
+	}
+}
+
+// IntervalCount is the number of steps in the given range.
+func IntervalCount(start, end, step uint64) int {
+	if step == 0 || end <= start {
+		return 0
+	}
+
+	intervals := (end - start) / step
+	if (end-start)%step > 0 {
+		intervals++
+	}
+	return int(intervals)
+}
+
+// TimestampOf returns the timestamp in nanoseconds of the start of the given interval.
+func TimestampOf(interval, start, step uint64) uint64 {
+	return start + interval*step
+}
+
+// IntervalOf returns the index of the interval the given timestamp falls in,
+// or -1 if it is outside of the range.
+func IntervalOf(ts, start, end, step uint64) int {
+	if step == 0 || ts < start || ts >= end {
+		return -1
+	}
+
+	return int((ts - start) / step)
+}
+
+// Label is a single dimension of a time series.
+type Label struct {
+	Name  string
+	Value Static
+}
+
+type Labels []Label
+
+// String returns the labels in prometheus format, i.e. {a="b", c="d"}
+func (ls Labels) String() string {
+	s := make([]string, 0, len(ls))
+	for _, l := range ls {
+		var v string
+		if l.Value.Type == TypeFloat {
+			// Don't use the fixed precision formatting of EncodeToString, it turns p=0.9 into p=0.90000
+			v = strconv.FormatFloat(l.Value.F, 'g', -1, 64)
+		} else {
+			v = l.Value.EncodeToString(false)
+		}
+		s = append(s, l.Name+"="+strconv.Quote(v))
+	}
+	return "{" + strings.Join(s, ", ") + "}"
+}
+
+type TimeSeries struct {
+	Labels Labels
+	Values []float64
+}
+
+// SeriesSet is a set of time series keyed by their prometheus formatted labels.
+type SeriesSet map[string]TimeSeries
+
+// ToProto converts the series set into the protobuf representation. Only non-zero
+// samples are included to keep the responses small.
+func (set SeriesSet) ToProto(req *tempopb.QueryRangeRequest) []*tempopb.TimeSeries {
+	resp := make([]*tempopb.TimeSeries, 0, len(set))
+
+	for promLabels, s := range set {
+		labels := make([]common_v1.KeyValue, 0, len(s.Labels))
+		for _, l := range s.Labels {
+			labels = append(labels, common_v1.KeyValue{
+				Key:   l.Name,
+				Value: l.Value.asAnyValue(),
+			})
+		}
+
+		samples := make([]tempopb.Sample, 0, len(s.Values))
+		for i, v := range s.Values {
+			if v == 0 {
+				continue
+			}
+			samples = append(samples, tempopb.Sample{
+				TimestampMs: time.Duration(TimestampOf(uint64(i), req.Start, req.Step)).Milliseconds(),
+				Value:       v,
+			})
+		}
+
+		resp = append(resp, &tempopb.TimeSeries{
+			PromLabels: promLabels,
+			Labels:     labels,
+			Samples:    samples,
+		})
+	}
+
+	return resp
+}
+
+// VectorAggregator turns the observed spans into the values of a single time series.
+type VectorAggregator interface {
+	Observe(Span)
+	Samples() []float64
+}
+
+// CountOverTimeAggregator counts the spans in each interval, each span adding the given weight.
+type CountOverTimeAggregator struct {
+	start, end, step uint64
+	weight           float64
+	ss               []float64
+}
+
+var _ VectorAggregator = (*CountOverTimeAggregator)(nil)
+
+func NewCountOverTimeAggregator(start, end, step uint64, weight float64) *CountOverTimeAggregator {
+	return &CountOverTimeAggregator{
+		start:  start,
+		end:    end,
+		step:   step,
+		weight: weight,
+		ss:     make([]float64, IntervalCount(start, end, step)),
+	}
+}
+
+func (c *CountOverTimeAggregator) Observe(span Span) {
+	interval := IntervalOf(span.StartTimeUnixNanos(), c.start, c.end, c.step)
+	if interval == -1 {
+		return
+	}
+	c.ss[interval] += c.weight
+}
+
+func (c *CountOverTimeAggregator) Samples() []float64 {
+	return c.ss
+}
+
+// SpanAggregator turns the observed spans into a set of time series.
+type SpanAggregator interface {
+	Observe(Span)
+	Series() SeriesSet
+}
+
+// FastValues is an array of attribute values that can be used as a map key.
+// The extra slot holds the value of the optional byFunc.
+type FastValues [maxGroupBys + 1]Static
+
+// GroupingAggregator groups spans by the values of the given attributes and
+// feeds each group into its own VectorAggregator.
+type GroupingAggregator struct {
+	// Config
+	by          []Attribute
+	byFunc      func(Span) (Static, bool) // Dynamic label computed from the span, i.e. a histogram bucket
+	byFuncLabel string
+	innerAgg    func() VectorAggregator
+
+	// Data
+	series map[FastValues]*groupedSeries
+	buf    FastValues
+	key    FastValues
+}
+
+// groupedSeries holds the values of a group, the map key doesn't hold the elements of arrays.
+type groupedSeries struct {
+	vals FastValues
+	agg  VectorAggregator
+}
+
+var _ SpanAggregator = (*GroupingAggregator)(nil)
+
+func NewGroupingAggregator(by []Attribute, byFunc func(Span) (Static, bool), byFuncLabel string, innerAgg func() VectorAggregator) *GroupingAggregator {
+	return &GroupingAggregator{
+		by:          by,
+		byFunc:      byFunc,
+		byFuncLabel: byFuncLabel,
+		innerAgg:    innerAgg,
+		series:      map[FastValues]*groupedSeries{},
+	}
+}
+
+func (g *GroupingAggregator) Observe(span Span) {
+	for i, b := range g.by {
+		// execute never returns an error for attributes and returns nil when the span doesn't have it
+		g.buf[i], _ = b.execute(span)
+		g.key[i] = g.buf[i].MapKey()
+	}
+
+	if g.byFunc != nil {
+		v, ok := g.byFunc(span)
+		if !ok {
+			// Span doesn't have the value we are grouping by.
+			return
+		}
+		g.buf[len(g.by)] = v
+		g.key[len(g.by)] = v.MapKey()
+	}
+
+	s, ok := g.series[g.key]
+	if !ok {
+		s = &groupedSeries{vals: g.buf, agg: g.innerAgg()}
+		g.series[g.key] = s
+	}
+	s.agg.Observe(span)
+}
+
+// Series returns the time series of all groups. Attributes that were missing
+// on the spans are not included in the labels.
+func (g *GroupingAggregator) Series() SeriesSet {
+	ss := SeriesSet{}
+
+	for _, s := range g.series {
+		vals, agg := s.vals, s.agg
+		labels := make(Labels, 0, len(g.by)+1)
+		for i, b := range g.by {
+			if vals[i].Type == TypeNil {
+				continue
+			}
+			labels = append(labels, Label{Name: b.String(), Value: vals[i]})
+		}
+		if g.byFunc != nil {
+			labels = append(labels, Label{Name: g.byFuncLabel, Value: vals[len(g.by)]})
+		}
+
+		promLabels := labels.String()
+		ss[promLabels] = TimeSeries{
+			Labels: labels,
+			Values: agg.Samples(),
+		}
+	}
+
+	return ss
+}
+
+func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest) {
+	var (
+		innerAgg    func() VectorAggregator
+		byFunc      func(Span) (Static, bool)
+		byFuncLabel string
+	)
+
+	switch a.op {
+	case metricsAggregateCountOverTime:
+		innerAgg = func() VectorAggregator {
+			return NewCountOverTimeAggregator(q.Start, q.End, q.Step, 1)
+		}
+
+	case metricsAggregateRate:
+		// Each span contributes 1/step to the per-second rate of its interval.
+		weight := 1 / time.Duration(q.Step).Seconds()
+		innerAgg = func() VectorAggregator {
+			return NewCountOverTimeAggregator(q.Start, q.End, q.Step, weight)
+		}
+
+	case metricsAggregateQuantileOverTime:
+		// Spans are counted into log2 buckets which are combined and turned
+		// into quantiles by the frontend. See QueryRangeCombiner.
+		innerAgg = func() VectorAggregator {
+			return NewCountOverTimeAggregator(q.Start, q.End, q.Step, 1)
+		}
+		byFunc = a.bucketize
+		byFuncLabel = internalLabelBucket
+	}
+
+	a.agg = NewGroupingAggregator(a.by, byFunc, byFuncLabel, innerAgg)
+}
+
+// bucketize returns the log2 bucket of the quantile attribute of the span. Durations are in seconds.
+func (a *MetricsAggregate) bucketize(span Span) (Static, bool) {
+	var v float64
+
+	if a.attr.Intrinsic == IntrinsicDuration {
+		v = time.Duration(span.DurationNanos()).Seconds()
+	} else {
+		s, _ := a.attr.execute(span)
+		switch s.Type {
+		case TypeDuration:
+			v = s.D.Seconds()
+		case TypeInt, TypeFloat:
+			v = s.asFloat()
+		default:
+			return Static{}, false
+		}
+	}
+
+	return NewStaticFloat(Log2Bucketize(v)), true
+}
+
+func (a *MetricsAggregate) observe(span Span) {
+	a.agg.Observe(span)
+}
+
+func (a *MetricsAggregate) result() SeriesSet {
+	return a.agg.Series()
+}
+
+// Log2Bucketize rounds the given value up to the next power of two.
+// Values less than or equal to zero are placed in the zero bucket.
+func Log2Bucketize(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+
+	return math.Pow(2, math.Ceil(math.Log2(v)))
+}
+
+// HistogramBucket is a log2 bucket with the count of values in (Max/2, Max].
+type HistogramBucket struct {
+	Max   float64
+	Count float64
+}
+
+// Log2Quantile returns the estimated quantile of the given log2 buckets. The
+// buckets must be sorted by Max. Within a bucket the value is interpolated
+// exponentially, the same as traceqlmetrics.LatencyHistogram.
+func Log2Quantile(p float64, buckets []HistogramBucket) float64 {
+	if math.IsNaN(p) || p < 0 || p > 1 || len(buckets) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, b := range buckets {
+		total += b.Count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	// Number of samples up to and including the quantile. Rounded up to better
+	// handle low sample counts.
+	want := math.Ceil(p * total)
+
+	for _, b := range buckets {
+		if want > b.Count {
+			want -= b.Count
+			continue
+		}
+
+		if b.Max <= 0 {
+			return 0
+		}
+
+		// Fraction of the way through this bucket, sample-count wise.
+		// The bucket spans (max/2, max] so interpolate exponentially.
+		return b.Max / 2 * math.Pow(2, want/b.Count)
+	}
+
+	return buckets[len(buckets)-1].Max
+}
+
+// MetricsEvaluator evaluates a compiled metrics query against one or more data sources.
+type MetricsEvaluator struct {
+	start, end      uint64
+	storageReq      *FetchSpansRequest
+	metricsPipeline metricsFirstStageElement
+	spansTotal      uint64
+	bytes           uint64
+}
+
+// CompileMetricsQueryRange returns an evaluator for the given metrics query. The
+// request must already be aligned to the step, see AlignRequest.
+func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest) (*MetricsEvaluator, error) {
+	if req.Start == 0 {
+		return nil, errors.New("start required")
+	}
+	if req.End <= req.Start {
+		return nil, errors.New("end must be greater than start")
+	}
+	if req.Step == 0 {
+		return nil, errors.New("step required")
+	}
+
+	expr, err := Parse(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("compiling query: %w", err)
+	}
+	if err := expr.validate(); err != nil {
+		return nil, err
+	}
+	if expr.MetricsPipeline == nil {
+		return nil, errors.New("query is not a metrics query")
+	}
+
+	storageReq := &FetchSpansRequest{
+		StartTimeUnixNanos: req.Start,
+		EndTimeUnixNanos:   req.End,
+		AllConditions:      true,
+	}
+	expr.Pipeline.extractConditions(storageReq)
+
+	// The pipeline is evaluated in between the passes, so that the group-by
+	// attributes are only fetched for the matching spans.
+	storageReq.SecondPass = func(s *Spanset) ([]*Spanset, error) {
+		return expr.Pipeline.evaluate([]*Spanset{s})
+	}
+	// The span start time is needed to sort spans into intervals.
+	storageReq.SecondPassConditions = append(storageReq.SecondPassConditions, Condition{Attribute: NewIntrinsic(IntrinsicSpanStartTime)})
+	expr.MetricsPipeline.extractConditions(storageReq)
+
+	expr.MetricsPipeline.init(req)
+
+	return &MetricsEvaluator{
+		start:           req.Start,
+		end:             req.End,
+		storageReq:      storageReq,
+		metricsPipeline: expr.MetricsPipeline,
+	}, nil
+}
+
+// Do fetches the matching spans from the given fetcher and records them.
+func (e *MetricsEvaluator) Do(ctx context.Context, f SpansetFetcher) error {
+	fetch, err := f.Fetch(ctx, *e.storageReq)
+	if errors.Is(err, util.ErrUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	defer fetch.Results.Close()
+
+	for {
+		ss, err := fetch.Results.Next(ctx)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if ss == nil {
+			break
+		}
+
+		for _, s := range ss.Spans {
+			// Traces overlapping the range can contain spans outside of it.
+			st := s.StartTimeUnixNanos()
+			if st < e.start || st >= e.end {
+				continue
+			}
+
+			e.metricsPipeline.observe(s)
+			e.spansTotal++
+		}
+	}
+
+	// Bytes can be nil when callback is no set
+	if fetch.Bytes != nil {
+		e.bytes += fetch.Bytes()
+	}
+
+	return nil
+}
+
+// Metrics returns the number of spans observed and the bytes read.
+func (e *MetricsEvaluator) Metrics() (spans uint64, bytes uint64) {
+	return e.spansTotal, e.bytes
+}
+
+func (e *MetricsEvaluator) Results() SeriesSet {
+	return e.metricsPipeline.result()
+}
+
+// QueryRangeCombiner merges the sharded results of a metrics query.
+type QueryRangeCombiner struct {
+	req       *tempopb.QueryRangeRequest
+	quantiles []float64
+	series    map[string]*combinedSeries
+	metrics   *tempopb.SearchMetrics
+}
+
+type combinedSeries struct {
+	labels []common_v1.KeyValue
+	values []float64
+}
+
+// NewQueryRangeCombiner returns a combiner for the given request. The request
+// must already be aligned to the step, see AlignRequest.
+func NewQueryRangeCombiner(req *tempopb.QueryRangeRequest) (*QueryRangeCombiner, error) {
+	expr, err := Parse(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &QueryRangeCombiner{
+		req:     req,
+		series:  map[string]*combinedSeries{},
+		metrics: &tempopb.SearchMetrics{},
+	}
+
+	if agg, ok := expr.MetricsPipeline.(*MetricsAggregate); ok && agg.op == metricsAggregateQuantileOverTime {
+		c.quantiles = agg.floats
+	}
+
+	return c, nil
+}
+
+func (c *QueryRangeCombiner) Combine(resp *tempopb.QueryRangeResponse) {
+	if resp == nil {
+		return
+	}
+
+	intervals := IntervalCount(c.req.Start, c.req.End, c.req.Step)
+
+	for _, s := range resp.Series {
+		existing, ok := c.series[s.PromLabels]
+		if !ok {
+			existing = &combinedSeries{
+				labels: s.Labels,
+				values: make([]float64, intervals),
+			}
+			c.series[s.PromLabels] = existing
+		}
+
+		for _, sample := range s.Samples {
+			ts := uint64(time.Duration(sample.TimestampMs) * time.Millisecond)
+			i := IntervalOf(ts, c.req.Start, c.req.End, c.req.Step)
+			if i == -1 {
+				continue
+			}
+			existing.values[i] += sample.Value
+		}
+	}
+
+	if resp.Metrics != nil {
+		c.metrics.InspectedBytes += resp.Metrics.InspectedBytes
+		c.metrics.InspectedTraces += resp.Metrics.InspectedTraces
+		c.metrics.TotalBlocks += resp.Metrics.TotalBlocks
+		c.metrics.CompletedJobs += resp.Metrics.CompletedJobs
+		c.metrics.TotalJobs += resp.Metrics.TotalJobs
+		c.metrics.TotalBlockBytes += resp.Metrics.TotalBlockBytes
+	}
+}
+
+// Response returns the combined series sorted by their labels.
+func (c *QueryRangeCombiner) Response() *tempopb.QueryRangeResponse {
+	var series []*tempopb.TimeSeries
+	if c.quantiles != nil {
+		series = c.quantileSeries()
+	} else {
+		series = make([]*tempopb.TimeSeries, 0, len(c.series))
+		for promLabels, s := range c.series {
+			series = append(series, &tempopb.TimeSeries{
+				PromLabels: promLabels,
+				Labels:     s.labels,
+				Samples:    c.samples(s.values, true),
+			})
+		}
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].PromLabels < series[j].PromLabels
+	})
+
+	return &tempopb.QueryRangeResponse{
+		Series:  series,
+		Metrics: c.metrics,
+	}
+}
+
+// quantileSeries converts the bucketed series into one series per quantile and group.
+func (c *QueryRangeCombiner) quantileSeries() []*tempopb.TimeSeries {
+	type group struct {
+		labels  Labels
+		buckets [][]HistogramBucket // per interval
+	}
+
+	intervals := IntervalCount(c.req.Start, c.req.End, c.req.Step)
+	groups := map[string]*group{}
+
+	for _, s := range c.series {
+		var (
+			labels = make(Labels, 0, len(s.labels))
+			bucket float64
+		)
+		for _, l := range s.labels {
+			if l.Key == internalLabelBucket {
+				bucket = l.Value.GetDoubleValue()
+				continue
+			}
+			labels = append(labels, Label{Name: l.Key, Value: staticFromAnyValue(l.Value)})
+		}
+
+		key := labels.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				labels:  labels,
+				buckets: make([][]HistogramBucket, intervals),
+			}
+			groups[key] = g
+		}
+
+		for i, v := range s.values {
+			if v == 0 {
+				continue
+			}
+			g.buckets[i] = append(g.buckets[i], HistogramBucket{Max: bucket, Count: v})
+		}
+	}
+
+	series := make([]*tempopb.TimeSeries, 0, len(groups)*len(c.quantiles))
+	for _, g := range groups {
+		for _, b := range g.buckets {
+			sort.Slice(b, func(i, j int) bool { return b[i].Max < b[j].Max })
+		}
+
+		for _, q := range c.quantiles {
+			labels := append(append(Labels{}, g.labels...), Label{Name: labelQuantile, Value: NewStaticFloat(q)})
+
+			kvs := make([]common_v1.KeyValue, 0, len(labels))
+			for _, l := range labels {
+				kvs = append(kvs, common_v1.KeyValue{Key: l.Name, Value: l.Value.asAnyValue()})
+			}
+
+			values := make([]float64, intervals)
+			for i, b := range g.buckets {
+				values[i] = Log2Quantile(q, b)
+			}
+
+			series = append(series, &tempopb.TimeSeries{
+				PromLabels: labels.String(),
+				Labels:     kvs,
+				// Quantiles of intervals without data are unknown rather than zero.
+				Samples: c.samples(values, false),
+			})
+		}
+	}
+
+	return series
+}
+
+func (c *QueryRangeCombiner) samples(values []float64, includeZeros bool) []tempopb.Sample {
+	samples := make([]tempopb.Sample, 0, len(values))
+	for i, v := range values {
+		if v == 0 && !includeZeros {
+			continue
+		}
+		samples = append(samples, tempopb.Sample{
+			TimestampMs: time.Duration(TimestampOf(uint64(i), c.req.Start, c.req.Step)).Milliseconds(),
+			Value:       v,
+		})
+	}
+	return samples
+}
+
+func staticFromAnyValue(v *common_v1.AnyValue) Static {
+	switch v.GetValue().(type) {
+	case *common_v1.AnyValue_StringValue:
+		return NewStaticString(v.GetStringValue())
+	case *common_v1.AnyValue_IntValue:
+		return NewStaticInt(int(v.GetIntValue()))
+	case *common_v1.AnyValue_DoubleValue:
+		return NewStaticFloat(v.GetDoubleValue())
+	case *common_v1.AnyValue_BoolValue:
+		return NewStaticBool(v.GetBoolValue())
+	}
+	return NewStaticNil()
+}
@@ -0,0 +1,217 @@
+package traceql
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignRequest(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(61 * time.Second),
+		End:   uint64(179 * time.Second),
+		Step:  uint64(time.Minute),
+	}
+	AlignRequest(req)
+
+	require.Equal(t, uint64(60*time.Second), req.Start)
+	require.Equal(t, uint64(180*time.Second), req.End)
+	require.Equal(t, 2, IntervalCount(req.Start, req.End, req.Step))
+}
+
+func TestIntervalOf(t *testing.T) {
+	tc := []struct {
+		ts, start, end, step uint64
+		expected             int
+	}{
+		{expected: -1},
+		{ts: 0, start: 10, end: 20, step: 1, expected: -1},
+		{ts: 10, start: 10, end: 20, step: 1, expected: 0},
+		{ts: 19, start: 10, end: 20, step: 1, expected: 9},
+		{ts: 20, start: 10, end: 20, step: 1, expected: -1},
+		{ts: 15, start: 10, end: 20, step: 5, expected: 1},
+	}
+
+	for _, c := range tc {
+		require.Equal(t, c.expected, IntervalOf(c.ts, c.start, c.end, c.step))
+	}
+}
+
+func TestLog2Quantile(t *testing.T) {
+	require.Equal(t, 0.0, Log2Quantile(0.5, nil))
+	require.Equal(t, 0.0, Log2Quantile(math.NaN(), []HistogramBucket{{Max: 1, Count: 1}}))
+
+	buckets := []HistogramBucket{
+		{Max: 1, Count: 5},
+		{Max: 2, Count: 5},
+	}
+
+	// All of the first bucket
+	require.Equal(t, 1.0, Log2Quantile(0.5, buckets))
+	// Halfway through the second bucket: 1 * 2^0.6
+	require.InDelta(t, math.Pow(2, 0.6), Log2Quantile(0.8, buckets), 0.0001)
+	require.Equal(t, 2.0, Log2Quantile(1, buckets))
+}
+
+func TestMetricsQueryRange(t *testing.T) {
+	var (
+		e   = NewEngine()
+		req = &tempopb.QueryRangeRequest{
+			Query: `{ .foo = "bar" } | count_over_time() by(span.baz)`,
+			Start: uint64(1 * time.Second),
+			End:   uint64(4 * time.Second),
+			Step:  uint64(1 * time.Second),
+		}
+		attrFoo = NewScopedAttribute(AttributeScopeSpan, false, "foo")
+		attrBaz = NewScopedAttribute(AttributeScopeSpan, false, "baz")
+	)
+
+	newSpan := func(ts time.Duration, foo, baz string) Span {
+		return &mockSpan{
+			startTimeUnixNanos: uint64(ts),
+			attributes: map[Attribute]Static{
+				attrFoo: NewStaticString(foo),
+				attrBaz: NewStaticString(baz),
+			},
+		}
+	}
+
+	fetcher := &MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{Spans: []Span{
+					newSpan(1*time.Second, "bar", "a"),
+					newSpan(1500*time.Millisecond, "bar", "a"),
+					newSpan(2*time.Second, "bar", "b"),
+					newSpan(2*time.Second, "nope", "b"), // filtered by the pipeline
+					newSpan(5*time.Second, "bar", "b"),  // outside of the range
+				}},
+			},
+		},
+	}
+
+	eval, err := e.CompileMetricsQueryRange(req)
+	require.NoError(t, err)
+	require.NoError(t, eval.Do(context.Background(), fetcher))
+
+	// Group by and span start time are fetched in the second pass
+	require.Equal(t, []Condition{
+		{Attribute: NewIntrinsic(IntrinsicSpanStartTime)},
+		{Attribute: attrBaz},
+	}, fetcher.capturedRequest.SecondPassConditions)
+
+	spans, _ := eval.Metrics()
+	require.Equal(t, uint64(3), spans)
+
+	results := eval.Results()
+	require.Equal(t, []float64{2, 0, 0}, results[`{span.baz="a"}`].Values)
+	require.Equal(t, []float64{0, 1, 0}, results[`{span.baz="b"}`].Values)
+
+	// Combine the results twice as if they came from two blocks
+	c, err := NewQueryRangeCombiner(req)
+	require.NoError(t, err)
+	c.Combine(&tempopb.QueryRangeResponse{Series: results.ToProto(req)})
+	c.Combine(&tempopb.QueryRangeResponse{Series: results.ToProto(req)})
+
+	resp := c.Response()
+	require.Len(t, resp.Series, 2)
+	require.Equal(t, `{span.baz="a"}`, resp.Series[0].PromLabels)
+	require.Equal(t, []tempopb.Sample{
+		{TimestampMs: 1000, Value: 4},
+		{TimestampMs: 2000, Value: 0},
+		{TimestampMs: 3000, Value: 0},
+	}, resp.Series[0].Samples)
+}
+
+func TestMetricsQueryRangeRate(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Query: `{ } | rate()`,
+		Start: uint64(10 * time.Second),
+		End:   uint64(20 * time.Second),
+		Step:  uint64(10 * time.Second),
+	}
+
+	fetcher := &MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{Spans: []Span{
+					&mockSpan{startTimeUnixNanos: uint64(11 * time.Second)},
+					&mockSpan{startTimeUnixNanos: uint64(12 * time.Second)},
+				}},
+			},
+		},
+	}
+
+	eval, err := NewEngine().CompileMetricsQueryRange(req)
+	require.NoError(t, err)
+	require.NoError(t, eval.Do(context.Background(), fetcher))
+
+	require.Equal(t, []float64{0.2}, eval.Results()[`{}`].Values)
+}
+
+func TestMetricsQueryRangeQuantile(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Query: `{ } | quantile_over_time(duration, 0.5, 1)`,
+		Start: uint64(1 * time.Second),
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+	}
+
+	newSpan := func(ts, dur time.Duration) Span {
+		return &mockSpan{startTimeUnixNanos: uint64(ts), durationNanos: uint64(dur)}
+	}
+
+	fetcher := &MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{Spans: []Span{
+					newSpan(1*time.Second, 500*time.Millisecond),
+					newSpan(1*time.Second, time.Second),
+					newSpan(1*time.Second, 4*time.Second),
+				}},
+			},
+		},
+	}
+
+	eval, err := NewEngine().CompileMetricsQueryRange(req)
+	require.NoError(t, err)
+	require.NoError(t, eval.Do(context.Background(), fetcher))
+
+	results := eval.Results()
+	require.Len(t, results, 3) // One series per bucket
+	require.Equal(t, []float64{1, 0}, results[`{__bucket="0.5"}`].Values)
+	require.Equal(t, []float64{1, 0}, results[`{__bucket="1"}`].Values)
+	require.Equal(t, []float64{1, 0}, results[`{__bucket="4"}`].Values)
+
+	c, err := NewQueryRangeCombiner(req)
+	require.NoError(t, err)
+	c.Combine(&tempopb.QueryRangeResponse{Series: results.ToProto(req)})
+
+	resp := c.Response()
+	require.Len(t, resp.Series, 2)
+	require.Equal(t, `{p="0.5"}`, resp.Series[0].PromLabels)
+	require.Equal(t, []tempopb.Sample{{TimestampMs: 1000, Value: 1}}, resp.Series[0].Samples)
+	require.Equal(t, `{p="1"}`, resp.Series[1].PromLabels)
+	require.Equal(t, []tempopb.Sample{{TimestampMs: 1000, Value: 4}}, resp.Series[1].Samples)
+}
+
+func TestCompileMetricsQueryRangeErrors(t *testing.T) {
+	tc := []struct {
+		req *tempopb.QueryRangeRequest
+	}{
+		{req: &tempopb.QueryRangeRequest{Query: `{ } | rate()`, End: 2, Step: 1}},
+		{req: &tempopb.QueryRangeRequest{Query: `{ } | rate()`, Start: 2, End: 1, Step: 1}},
+		{req: &tempopb.QueryRangeRequest{Query: `{ } | rate()`, Start: 1, End: 2}},
+		{req: &tempopb.QueryRangeRequest{Query: `{ }`, Start: 1, End: 2, Step: 1}},
+		{req: &tempopb.QueryRangeRequest{Query: `{ } | rate(`, Start: 1, End: 2, Step: 1}},
+	}
+
+	for _, c := range tc {
+		_, err := NewEngine().CompileMetricsQueryRange(c.req)
+		require.Error(t, err, c.req.Query)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"sort"
-	"strings"
 	"testing"
 	"time"
 
@@ -118,7 +117,7 @@ func TestEngine_Execute(t *testing.T) {
 				DurationNanos:     100_000_000,
 				Attributes: []*v1.KeyValue{
 					{
-						Key: "foo",
+						Key: "bar",
 						Value: &v1.AnyValue{
 							Value: &v1.AnyValue_StringValue{
 								StringValue: "value",
@@ -126,7 +125,7 @@ func TestEngine_Execute(t *testing.T) {
 						},
 					},
 					{
-						Key: "bar",
+						Key: "foo",
 						Value: &v1.AnyValue{
 							Value: &v1.AnyValue_StringValue{
 								StringValue: "value",
@@ -141,7 +140,7 @@ func TestEngine_Execute(t *testing.T) {
 				DurationNanos:     200_000_000,
 				Attributes: []*v1.KeyValue{
 					{
-						Key: "foo",
+						Key: "bar",
 						Value: &v1.AnyValue{
 							Value: &v1.AnyValue_StringValue{
 								StringValue: "value",
@@ -149,7 +148,7 @@ func TestEngine_Execute(t *testing.T) {
 						},
 					},
 					{
-						Key: "bar",
+						Key: "foo",
 						Value: &v1.AnyValue{
 							Value: &v1.AnyValue_StringValue{
 								StringValue: "value",
@@ -171,25 +170,57 @@ func TestEngine_Execute(t *testing.T) {
 		},
 	}
 
-	// Sort attributes for consistent equality checks
-	// This is needed because they are internally stored in maps
-	sort := func(mm []*tempopb.TraceSearchMetadata) {
-		for _, m := range mm {
-			for _, s := range m.SpanSet.Spans {
-				sort.Slice(s.Attributes, func(i, j int) bool {
-					return s.Attributes[i].Key < s.Attributes[j].Key
-				})
-			}
-		}
-	}
-	sort(expectedTraceSearchMetadata)
-	sort(response.Traces)
-
 	assert.Equal(t, expectedTraceSearchMetadata, response.Traces)
 
 	assert.Equal(t, uint64(100_00), response.Metrics.InspectedBytes)
 }
 
+func TestEngine_ExecuteSelect(t *testing.T) {
+	e := NewEngine()
+
+	req := &tempopb.SearchRequest{
+		Query: `{ .foo = "value" } | select(span.http.url, resource.pod)`,
+	}
+	spanSetFetcher := MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{
+					TraceID: []byte{1},
+					Spans: []Span{
+						// the selected attributes are added by the fetch layer in the second pass
+						&mockSpan{
+							id: []byte{1},
+							attributes: map[Attribute]Static{
+								NewScopedAttribute(AttributeScopeSpan, false, "http.url"): NewStaticString("/a"),
+								NewScopedAttribute(AttributeScopeResource, false, "pod"):  NewStaticString("pod-1"),
+								NewAttribute("foo"): NewStaticString("value"),
+								NewScopedAttribute(AttributeScopeSpan, false, "unselected"): NewStaticInt(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	response, err := e.ExecuteSearch(context.Background(), req, &spanSetFetcher)
+	require.NoError(t, err)
+
+	expectedSecondPassConditions := append([]Condition{
+		newCondition(NewScopedAttribute(AttributeScopeSpan, false, "http.url"), OpNone),
+		newCondition(NewScopedAttribute(AttributeScopeResource, false, "pod"), OpNone),
+	}, SearchMetaConditions()...)
+	assert.Equal(t, expectedSecondPassConditions, spanSetFetcher.capturedRequest.SecondPassConditions)
+
+	require.Len(t, response.Traces, 1)
+	require.Len(t, response.Traces[0].SpanSet.Spans, 1)
+
+	var keys []string
+	for _, kv := range response.Traces[0].SpanSet.Spans[0].Attributes {
+		keys = append(keys, kv.Key)
+	}
+	assert.Equal(t, []string{"foo", "http.url", "pod", "unselected"}, keys)
+}
+
 func TestEngine_asTraceSearchMetadata(t *testing.T) {
 	now := time.Now()
 
@@ -324,11 +355,6 @@ func TestEngine_asTraceSearchMetadata(t *testing.T) {
 		SpanSets:          []*tempopb.SpanSet{expectedSpanset},
 	}
 
-	// Ensure attributes are sorted to avoid a flaky test
-	sort.Slice(traceSearchMetadata.SpanSet.Spans[0].Attributes, func(i, j int) bool {
-		return strings.Compare(traceSearchMetadata.SpanSet.Spans[0].Attributes[i].Key, traceSearchMetadata.SpanSet.Spans[0].Attributes[j].Key) == -1
-	})
-
 	assert.Equal(t, expectedTraceSearchMetadata, traceSearchMetadata)
 }
 
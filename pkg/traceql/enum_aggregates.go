@@ -29,3 +29,24 @@ func (a AggregateOp) String() string {
 
 	return fmt.Sprintf("aggregate(%d)", a)
 }
+
+type MetricsAggregateOp int
+
+const (
+	metricsAggregateRate MetricsAggregateOp = iota
+	metricsAggregateCountOverTime
+	metricsAggregateQuantileOverTime
+)
+
+func (a MetricsAggregateOp) String() string {
+	switch a {
+	case metricsAggregateRate:
+		return "rate"
+	case metricsAggregateCountOverTime:
+		return "count_over_time"
+	case metricsAggregateQuantileOverTime:
+		return "quantile_over_time"
+	}
+
+	return fmt.Sprintf("aggregate(%d)", a)
+}
@@ -8,6 +8,8 @@ const (
 	AttributeScopeNone AttributeScope = iota
 	AttributeScopeResource
 	AttributeScopeSpan
+	AttributeScopeEvent
+	AttributeScopeLink
 	AttributeScopeUnknown
 
 	none = "none"
@@ -25,6 +27,10 @@ func (s AttributeScope) String() string {
 		return "span"
 	case AttributeScopeResource:
 		return "resource"
+	case AttributeScopeEvent:
+		return "event"
+	case AttributeScopeLink:
+		return "link"
 	}
 
 	return fmt.Sprintf("att(%d).", s)
@@ -36,6 +42,10 @@ func AttributeScopeFromString(s string) AttributeScope {
 		return AttributeScopeSpan
 	case "resource":
 		return AttributeScopeResource
+	case "event":
+		return AttributeScopeEvent
+	case "link":
+		return AttributeScopeLink
 	case "":
 		fallthrough
 	case none:
@@ -57,6 +67,9 @@ const (
 	IntrinsicTraceRootService
 	IntrinsicTraceRootSpan
 	IntrinsicTraceDuration
+	IntrinsicEventName
+	IntrinsicLinkTraceID
+	IntrinsicLinkSpanID
 
 	// not yet implemented in traceql but will be
 	IntrinsicParent
@@ -67,6 +80,12 @@ const (
 	IntrinsicTraceStartTime
 	IntrinsicSpanID
 	IntrinsicSpanStartTime
+
+	// nested set values of a span used to evaluate structural operators. these are only
+	// retrieved from the fetch layer and can't be used in queries.
+	IntrinsicNestedSetLeft
+	IntrinsicNestedSetRight
+	IntrinsicNestedSetParent
 )
 
 func (i Intrinsic) String() string {
@@ -83,6 +102,12 @@ func (i Intrinsic) String() string {
 		return "kind"
 	case IntrinsicChildCount:
 		return "childCount"
+	case IntrinsicEventName:
+		return "event:name"
+	case IntrinsicLinkTraceID:
+		return "link:traceID"
+	case IntrinsicLinkSpanID:
+		return "link:spanID"
 	// below is unimplemented
 	case IntrinsicParent:
 		return "parent"
@@ -100,6 +125,12 @@ func (i Intrinsic) String() string {
 		return "spanID"
 	case IntrinsicSpanStartTime:
 		return "spanStartTime"
+	case IntrinsicNestedSetLeft:
+		return "nestedSetLeft"
+	case IntrinsicNestedSetRight:
+		return "nestedSetRight"
+	case IntrinsicNestedSetParent:
+		return "nestedSetParent"
 	}
 
 	return fmt.Sprintf("intrinsic(%d)", i)
@@ -118,6 +149,12 @@ func intrinsicFromString(s string) Intrinsic {
 		return IntrinsicKind
 	case "childCount":
 		return IntrinsicChildCount
+	case "event:name":
+		return IntrinsicEventName
+	case "link:traceID":
+		return IntrinsicLinkTraceID
+	case "link:spanID":
+		return IntrinsicLinkSpanID
 	// unimplemented
 	case "parent":
 		return IntrinsicParent
@@ -28,6 +28,14 @@ const (
 	OpSpansetAnd
 	OpSpansetUnion
 	OpSpansetSibling
+	OpSpansetParent
+	OpSpansetAncestor
+	OpSpansetNotChild
+	OpSpansetNotParent
+	OpSpansetNotDescendant
+	OpSpansetNotAncestor
+	OpSpansetNotSibling
+	OpLen
 )
 
 func (op Operator) isBoolean() bool {
@@ -44,6 +52,20 @@ func (op Operator) isBoolean() bool {
 		op == OpNot
 }
 
+// isStructural returns true for spanset operators that relate spans by their position in the trace
+func (op Operator) isStructural() bool {
+	return op == OpSpansetChild ||
+		op == OpSpansetParent ||
+		op == OpSpansetDescendant ||
+		op == OpSpansetAncestor ||
+		op == OpSpansetSibling ||
+		op == OpSpansetNotChild ||
+		op == OpSpansetNotParent ||
+		op == OpSpansetNotDescendant ||
+		op == OpSpansetNotAncestor ||
+		op == OpSpansetNotSibling
+}
+
 func (op Operator) binaryTypesValid(lhsT StaticType, rhsT StaticType) bool {
 	return binaryTypeValid(op, lhsT) && binaryTypeValid(op, rhsT)
 }
@@ -53,6 +75,11 @@ func binaryTypeValid(op Operator, t StaticType) bool {
 		return true
 	}
 
+	// arrays support the comparisons of their elements
+	if t.isArray() {
+		return op != OpAnd && op != OpOr && op.isBoolean() && binaryTypeValid(op, t.elementType())
+	}
+
 	switch t {
 	case TypeBoolean:
 		return op == OpAnd ||
@@ -106,6 +133,8 @@ func (op Operator) unaryTypesValid(t StaticType) bool {
 		return t.isNumeric()
 	case OpNot:
 		return t == TypeBoolean
+	case OpLen:
+		return t.isArray()
 	}
 
 	return false
@@ -158,6 +187,22 @@ func (op Operator) String() string {
 		return "~"
 	case OpSpansetUnion:
 		return "||"
+	case OpSpansetParent:
+		return "<"
+	case OpSpansetAncestor:
+		return "<<"
+	case OpSpansetNotChild:
+		return "!>"
+	case OpSpansetNotParent:
+		return "!<"
+	case OpSpansetNotDescendant:
+		return "!>>"
+	case OpSpansetNotAncestor:
+		return "!<<"
+	case OpSpansetNotSibling:
+		return "!~"
+	case OpLen:
+		return "len"
 	}
 
 	return fmt.Sprintf("operator(%d)", op)
@@ -15,6 +15,10 @@ const (
 	TypeDuration
 	TypeStatus
 	TypeKind
+	TypeStringArray
+	TypeIntArray
+	TypeFloatArray
+	TypeBooleanArray
 )
 
 // isMatchingOperand returns whether two types can be combined with a binary operator. the kind of operator is
@@ -29,6 +33,12 @@ func (t StaticType) isMatchingOperand(otherT StaticType) bool {
 		return true
 	}
 
+	// arrays are compared element by element
+	t, otherT = t.elementType(), otherT.elementType()
+	if t == otherT {
+		return true
+	}
+
 	if t.isNumeric() && otherT.isNumeric() {
 		return true
 	}
@@ -40,6 +50,25 @@ func (t StaticType) isNumeric() bool {
 	return t == TypeInt || t == TypeFloat || t == TypeDuration
 }
 
+func (t StaticType) isArray() bool {
+	return t == TypeStringArray || t == TypeIntArray || t == TypeFloatArray || t == TypeBooleanArray
+}
+
+// elementType returns the type of the elements of an array type. all other types are returned unchanged.
+func (t StaticType) elementType() StaticType {
+	switch t {
+	case TypeStringArray:
+		return TypeString
+	case TypeIntArray:
+		return TypeInt
+	case TypeFloatArray:
+		return TypeFloat
+	case TypeBooleanArray:
+		return TypeBoolean
+	}
+	return t
+}
+
 // Status represents valid static values of typeStatus
 type Status int
 
@@ -14,6 +14,9 @@ import (
     coalesceOperation CoalesceOperation
     selectOperation SelectOperation
     selectArgs []FieldExpression
+    attribute Attribute
+    attributeList []Attribute
+    numericList []float64
 
     spansetExpression SpansetExpression
     spansetPipelineExpression SpansetExpression
@@ -29,6 +32,7 @@ import (
     wrappedScalarPipeline Pipeline
     scalarPipeline Pipeline
     aggregate Aggregate
+    metricsAggregation *MetricsAggregate
 
     fieldExpression FieldExpression
     static Static
@@ -47,6 +51,9 @@ import (
 %type <coalesceOperation> coalesceOperation
 %type <selectOperation> selectOperation
 %type <selectArgs> selectArgs
+%type <attribute> attribute
+%type <attributeList> attributeList
+%type <numericList> numericList
 
 %type <spansetExpression> spansetExpression
 %type <spansetPipelineExpression> spansetPipelineExpression
@@ -62,6 +69,7 @@ import (
 %type <wrappedScalarPipeline> wrappedScalarPipeline
 %type <scalarPipeline> scalarPipeline
 %type <aggregate> aggregate 
+%type <metricsAggregation> metricsAggregation
 
 %type <fieldExpression> fieldExpression
 %type <static> static
@@ -76,15 +84,17 @@ import (
                         NIL TRUE FALSE STATUS_ERROR STATUS_OK STATUS_UNSET
                         KIND_UNSPECIFIED KIND_INTERNAL KIND_SERVER KIND_CLIENT KIND_PRODUCER KIND_CONSUMER
                         IDURATION CHILDCOUNT NAME STATUS PARENT KIND ROOTNAME ROOTSERVICENAME TRACEDURATION
-                        PARENT_DOT RESOURCE_DOT SPAN_DOT
+                        PARENT_DOT RESOURCE_DOT SPAN_DOT EVENT_DOT LINK_DOT
+                        EVENT_NAME LINK_TRACE_ID LINK_SPAN_ID
                         COUNT AVG MAX MIN SUM
-                        BY COALESCE SELECT
+                        BY COALESCE SELECT LEN
+                        RATE COUNT_OVER_TIME QUANTILE_OVER_TIME
                         END_ATTRIBUTE
 
 // Operators are listed with increasing precedence.
 %left <binOp> PIPE
 %left <binOp> AND OR
-%left <binOp> EQ NEQ LT LTE GT GTE NRE RE DESC TILDE
+%left <binOp> EQ NEQ LT LTE GT GTE NRE RE DESC ANCE TILDE NOT_CHILD NOT_PARENT NOT_DESC NOT_ANCE
 %left <binOp> ADD SUB
 %left <binOp> NOT
 %left <binOp> MUL DIV MOD
@@ -98,19 +108,27 @@ root:
     spansetPipeline                             { yylex.(*lexer).expr = newRootExpr($1) }
   | spansetPipelineExpression                   { yylex.(*lexer).expr = newRootExpr($1) }
   | scalarPipelineExpressionFilter              { yylex.(*lexer).expr = newRootExpr($1) }
+  | spansetPipeline PIPE metricsAggregation     { yylex.(*lexer).expr = newRootExprWithMetrics($1, $3) }
   ;
 
 // **********************
 // Spanset Expressions
 // **********************
 spansetPipelineExpression: // shares the same operators as spansetExpression. split out for readability
-    OPEN_PARENS spansetPipelineExpression CLOSE_PARENS           { $$ = $2 }
-  | spansetPipelineExpression AND   spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
-  | spansetPipelineExpression GT    spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetChild, $1, $3) }
-  | spansetPipelineExpression DESC  spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
-  | spansetPipelineExpression OR    spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
-  | spansetPipelineExpression TILDE spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
-  | wrappedSpansetPipeline                                       { $$ = $1 }
+    OPEN_PARENS spansetPipelineExpression CLOSE_PARENS                { $$ = $2 }
+  | spansetPipelineExpression AND        spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
+  | spansetPipelineExpression GT         spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetChild, $1, $3) }
+  | spansetPipelineExpression DESC       spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
+  | spansetPipelineExpression OR         spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
+  | spansetPipelineExpression TILDE      spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
+  | spansetPipelineExpression LT         spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetParent, $1, $3) }
+  | spansetPipelineExpression ANCE       spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetAncestor, $1, $3) }
+  | spansetPipelineExpression NOT_CHILD  spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetNotChild, $1, $3) }
+  | spansetPipelineExpression NOT_PARENT spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetNotParent, $1, $3) }
+  | spansetPipelineExpression NOT_DESC   spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetNotDescendant, $1, $3) }
+  | spansetPipelineExpression NOT_ANCE   spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetNotAncestor, $1, $3) }
+  | spansetPipelineExpression NRE        spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetNotSibling, $1, $3) }
+  | wrappedSpansetPipeline                                            { $$ = $1 }
   ;
 
 wrappedSpansetPipeline:
@@ -146,13 +164,20 @@ selectArgs:
   ;
 
 spansetExpression: // shares the same operators as scalarPipelineExpression. split out for readability
-    OPEN_PARENS spansetExpression CLOSE_PARENS   { $$ = $2 }
-  | spansetExpression AND   spansetExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
-  | spansetExpression GT    spansetExpression    { $$ = newSpansetOperation(OpSpansetChild, $1, $3) }
-  | spansetExpression DESC  spansetExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
-  | spansetExpression OR    spansetExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
-  | spansetExpression TILDE spansetExpression    { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
-  | spansetFilter                                { $$ = $1 } 
+    OPEN_PARENS spansetExpression CLOSE_PARENS        { $$ = $2 }
+  | spansetExpression AND        spansetExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
+  | spansetExpression GT         spansetExpression    { $$ = newSpansetOperation(OpSpansetChild, $1, $3) }
+  | spansetExpression DESC       spansetExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
+  | spansetExpression OR         spansetExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
+  | spansetExpression TILDE      spansetExpression    { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
+  | spansetExpression LT         spansetExpression    { $$ = newSpansetOperation(OpSpansetParent, $1, $3) }
+  | spansetExpression ANCE       spansetExpression    { $$ = newSpansetOperation(OpSpansetAncestor, $1, $3) }
+  | spansetExpression NOT_CHILD  spansetExpression    { $$ = newSpansetOperation(OpSpansetNotChild, $1, $3) }
+  | spansetExpression NOT_PARENT spansetExpression    { $$ = newSpansetOperation(OpSpansetNotParent, $1, $3) }
+  | spansetExpression NOT_DESC   spansetExpression    { $$ = newSpansetOperation(OpSpansetNotDescendant, $1, $3) }
+  | spansetExpression NOT_ANCE   spansetExpression    { $$ = newSpansetOperation(OpSpansetNotAncestor, $1, $3) }
+  | spansetExpression NRE        spansetExpression    { $$ = newSpansetOperation(OpSpansetNotSibling, $1, $3) }
+  | spansetFilter                                     { $$ = $1 } 
   ;
 
 spansetFilter:
@@ -225,6 +250,35 @@ aggregate:
   | SUM OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateSum, $3) }
   ;
 
+// **********************
+// Metrics
+// **********************
+metricsAggregation:
+    RATE OPEN_PARENS CLOSE_PARENS                                                                            { $$ = newMetricsAggregate(metricsAggregateRate, nil) }
+  | RATE OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                                  { $$ = newMetricsAggregate(metricsAggregateRate, $6) }
+  | COUNT_OVER_TIME OPEN_PARENS CLOSE_PARENS                                                                 { $$ = newMetricsAggregate(metricsAggregateCountOverTime, nil) }
+  | COUNT_OVER_TIME OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                       { $$ = newMetricsAggregate(metricsAggregateCountOverTime, $6) }
+  | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS                                  { $$ = newMetricsAggregateQuantileOverTime($3, $5, nil) }
+  | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newMetricsAggregateQuantileOverTime($3, $5, $9) }
+  ;
+
+attributeList:
+    attribute                     { $$ = []Attribute{$1} }
+  | attributeList COMMA attribute { $$ = append($1, $3) }
+  ;
+
+attribute:
+    intrinsicField { $$ = $1 }
+  | attributeField { $$ = $1 }
+  ;
+
+numericList:
+    INTEGER                   { $$ = []float64{float64($1)} }
+  | FLOAT                     { $$ = []float64{$1} }
+  | numericList COMMA INTEGER { $$ = append($1, float64($3)) }
+  | numericList COMMA FLOAT   { $$ = append($1, $3) }
+  ;
+
 // **********************
 // FieldExpressions
 // **********************
@@ -248,6 +302,7 @@ fieldExpression:
   | fieldExpression OR fieldExpression       { $$ = newBinaryOperation(OpOr, $1, $3) }
   | SUB fieldExpression                      { $$ = newUnaryOperation(OpSub, $2) }
   | NOT fieldExpression                      { $$ = newUnaryOperation(OpNot, $2) }
+  | LEN OPEN_PARENS fieldExpression CLOSE_PARENS { $$ = newUnaryOperation(OpLen, $3) }
   | static                                   { $$ = $1 }
   | intrinsicField                           { $$ = $1 }
   | attributeField                           { $$ = $1 }
@@ -285,12 +340,17 @@ intrinsicField:
   | ROOTNAME        { $$ = NewIntrinsic(IntrinsicTraceRootSpan)    }
   | ROOTSERVICENAME { $$ = NewIntrinsic(IntrinsicTraceRootService) }
   | TRACEDURATION   { $$ = NewIntrinsic(IntrinsicTraceDuration)    }
+  | EVENT_NAME      { $$ = NewIntrinsic(IntrinsicEventName)        }
+  | LINK_TRACE_ID   { $$ = NewIntrinsic(IntrinsicLinkTraceID)      }
+  | LINK_SPAN_ID    { $$ = NewIntrinsic(IntrinsicLinkSpanID)       }
   ;
 
 attributeField:
     DOT IDENTIFIER END_ATTRIBUTE                      { $$ = NewAttribute($2)                                      }
   | RESOURCE_DOT IDENTIFIER END_ATTRIBUTE             { $$ = NewScopedAttribute(AttributeScopeResource, false, $2) }
   | SPAN_DOT IDENTIFIER END_ATTRIBUTE                 { $$ = NewScopedAttribute(AttributeScopeSpan, false, $2)     }
+  | EVENT_DOT IDENTIFIER END_ATTRIBUTE                { $$ = NewScopedAttribute(AttributeScopeEvent, false, $2)    }
+  | LINK_DOT IDENTIFIER END_ATTRIBUTE                 { $$ = NewScopedAttribute(AttributeScopeLink, false, $2)     }
   | PARENT_DOT IDENTIFIER END_ATTRIBUTE               { $$ = NewScopedAttribute(AttributeScopeNone, true, $2)      }
   | PARENT_DOT RESOURCE_DOT IDENTIFIER END_ATTRIBUTE  { $$ = NewScopedAttribute(AttributeScopeResource, true, $3)  }
   | PARENT_DOT SPAN_DOT IDENTIFIER END_ATTRIBUTE      { $$ = NewScopedAttribute(AttributeScopeSpan, true, $3)      }
@@ -1,17 +1,17 @@
-// Code generated by goyacc -o pkg/traceql/expr.y.go pkg/traceql/expr.y. DO NOT EDIT.
+// Code generated by goyacc -o expr.y.go expr.y. DO NOT EDIT.
 
-//line pkg/traceql/expr.y:2
+//line expr.y:2
 package traceql
 
 import __yyfmt__ "fmt"
 
-//line pkg/traceql/expr.y:2
+//line expr.y:2
 
 import (
 	"time"
 )
 
-//line pkg/traceql/expr.y:11
+//line expr.y:11
 type yySymType struct {
 	yys               int
 	root              RootExpr
@@ -19,6 +19,9 @@ type yySymType struct {
 	coalesceOperation CoalesceOperation
 	selectOperation   SelectOperation
 	selectArgs        []FieldExpression
+	attribute         Attribute
+	attributeList     []Attribute
+	numericList       []float64
 
 	spansetExpression         SpansetExpression
 	spansetPipelineExpression SpansetExpression
@@ -34,6 +37,7 @@ type yySymType struct {
 	wrappedScalarPipeline          Pipeline
 	scalarPipeline                 Pipeline
 	aggregate                      Aggregate
+	metricsAggregation             *MetricsAggregate
 
 	fieldExpression FieldExpression
 	static          Static
@@ -82,35 +86,49 @@ const TRACEDURATION = 57377
 const PARENT_DOT = 57378
 const RESOURCE_DOT = 57379
 const SPAN_DOT = 57380
-const COUNT = 57381
-const AVG = 57382
-const MAX = 57383
-const MIN = 57384
-const SUM = 57385
-const BY = 57386
-const COALESCE = 57387
-const SELECT = 57388
-const END_ATTRIBUTE = 57389
-const PIPE = 57390
-const AND = 57391
-const OR = 57392
-const EQ = 57393
-const NEQ = 57394
-const LT = 57395
-const LTE = 57396
-const GT = 57397
-const GTE = 57398
-const NRE = 57399
-const RE = 57400
-const DESC = 57401
-const TILDE = 57402
-const ADD = 57403
-const SUB = 57404
-const NOT = 57405
-const MUL = 57406
-const DIV = 57407
-const MOD = 57408
-const POW = 57409
+const EVENT_DOT = 57381
+const LINK_DOT = 57382
+const EVENT_NAME = 57383
+const LINK_TRACE_ID = 57384
+const LINK_SPAN_ID = 57385
+const COUNT = 57386
+const AVG = 57387
+const MAX = 57388
+const MIN = 57389
+const SUM = 57390
+const BY = 57391
+const COALESCE = 57392
+const SELECT = 57393
+const LEN = 57394
+const RATE = 57395
+const COUNT_OVER_TIME = 57396
+const QUANTILE_OVER_TIME = 57397
+const END_ATTRIBUTE = 57398
+const PIPE = 57399
+const AND = 57400
+const OR = 57401
+const EQ = 57402
+const NEQ = 57403
+const LT = 57404
+const LTE = 57405
+const GT = 57406
+const GTE = 57407
+const NRE = 57408
+const RE = 57409
+const DESC = 57410
+const ANCE = 57411
+const TILDE = 57412
+const NOT_CHILD = 57413
+const NOT_PARENT = 57414
+const NOT_DESC = 57415
+const NOT_ANCE = 57416
+const ADD = 57417
+const SUB = 57418
+const NOT = 57419
+const MUL = 57420
+const DIV = 57421
+const MOD = 57422
+const POW = 57423
 
 var yyToknames = [...]string{
 	"$end",
@@ -151,6 +169,11 @@ var yyToknames = [...]string{
 	"PARENT_DOT",
 	"RESOURCE_DOT",
 	"SPAN_DOT",
+	"EVENT_DOT",
+	"LINK_DOT",
+	"EVENT_NAME",
+	"LINK_TRACE_ID",
+	"LINK_SPAN_ID",
 	"COUNT",
 	"AVG",
 	"MAX",
@@ -159,6 +182,10 @@ var yyToknames = [...]string{
 	"BY",
 	"COALESCE",
 	"SELECT",
+	"LEN",
+	"RATE",
+	"COUNT_OVER_TIME",
+	"QUANTILE_OVER_TIME",
 	"END_ATTRIBUTE",
 	"PIPE",
 	"AND",
@@ -172,7 +199,12 @@ var yyToknames = [...]string{
 	"NRE",
 	"RE",
 	"DESC",
+	"ANCE",
 	"TILDE",
+	"NOT_CHILD",
+	"NOT_PARENT",
+	"NOT_DESC",
+	"NOT_ANCE",
 	"ADD",
 	"SUB",
 	"NOT",
@@ -181,6 +213,7 @@ var yyToknames = [...]string{
 	"MOD",
 	"POW",
 }
+
 var yyStatenames = [...]string{}
 
 const yyEofCode = 1
@@ -188,221 +221,273 @@ const yyErrCode = 2
 const yyInitialStackSize = 16
 
 //line yacctab:1
-var yyExca = [...]int{
+var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 194,
-	13, 53,
-	-2, 61,
+	-1, 238,
+	13, 68,
+	-2, 76,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 644
-
-var yyAct = [...]int{
-
-	68, 6, 8, 5, 7, 192, 2, 18, 226, 161,
-	162, 163, 172, 41, 172, 40, 159, 160, 64, 161,
-	162, 163, 172, 51, 35, 137, 133, 13, 36, 38,
-	113, 116, 112, 114, 28, 230, 229, 44, 125, 127,
-	128, 129, 130, 220, 173, 174, 164, 165, 166, 167,
-	168, 169, 171, 170, 135, 219, 159, 160, 218, 161,
-	162, 163, 172, 227, 30, 132, 155, 157, 31, 33,
-	175, 176, 177, 59, 60, 217, 61, 62, 63, 64,
-	61, 62, 63, 64, 198, 199, 147, 149, 150, 151,
-	152, 153, 154, 59, 60, 184, 61, 62, 63, 64,
-	28, 136, 46, 47, 132, 48, 49, 50, 51, 185,
-	186, 187, 188, 164, 165, 166, 167, 168, 169, 171,
-	170, 189, 181, 159, 160, 191, 161, 162, 163, 172,
-	189, 48, 49, 50, 51, 113, 116, 112, 114, 133,
-	140, 194, 225, 72, 120, 190, 196, 46, 47, 111,
-	48, 49, 50, 51, 110, 182, 183, 17, 109, 126,
-	200, 201, 202, 203, 204, 205, 206, 207, 208, 209,
-	210, 211, 212, 213, 214, 215, 190, 108, 173, 174,
-	164, 165, 166, 167, 168, 169, 171, 170, 107, 139,
-	159, 160, 66, 161, 162, 163, 172, 41, 65, 41,
-	228, 196, 173, 174, 164, 165, 166, 167, 168, 169,
-	171, 170, 222, 74, 159, 160, 221, 161, 162, 163,
-	172, 44, 134, 44, 75, 76, 77, 81, 100, 180,
-	67, 69, 179, 178, 80, 78, 79, 83, 82, 84,
-	85, 86, 87, 88, 89, 90, 91, 92, 93, 94,
-	96, 95, 97, 98, 99, 103, 101, 102, 34, 37,
-	58, 104, 105, 106, 35, 73, 43, 16, 36, 38,
-	4, 224, 45, 75, 76, 77, 81, 100, 12, 10,
-	69, 70, 71, 80, 78, 79, 83, 82, 84, 85,
-	86, 87, 88, 89, 90, 91, 92, 93, 94, 96,
-	95, 97, 98, 99, 103, 101, 102, 173, 174, 164,
-	165, 166, 167, 168, 169, 171, 170, 223, 156, 159,
-	160, 115, 161, 162, 163, 172, 1, 34, 37, 0,
-	70, 71, 0, 35, 29, 32, 216, 36, 38, 0,
-	30, 0, 0, 0, 31, 33, 42, 11, 0, 0,
-	0, 0, 0, 173, 174, 164, 165, 166, 167, 168,
-	169, 171, 170, 197, 0, 159, 160, 0, 161, 162,
-	163, 172, 173, 174, 164, 165, 166, 167, 168, 169,
-	171, 170, 158, 0, 159, 160, 0, 161, 162, 163,
-	172, 0, 138, 141, 142, 143, 144, 145, 146, 173,
-	174, 164, 165, 166, 167, 168, 169, 171, 170, 137,
-	0, 159, 160, 0, 161, 162, 163, 172, 0, 0,
-	173, 174, 164, 165, 166, 167, 168, 169, 171, 170,
-	0, 0, 159, 160, 0, 161, 162, 163, 172, 0,
-	0, 0, 0, 0, 0, 0, 0, 52, 53, 54,
-	55, 56, 57, 0, 0, 0, 0, 59, 60, 0,
-	61, 62, 63, 64, 52, 53, 54, 55, 56, 57,
-	0, 0, 0, 0, 59, 60, 0, 61, 62, 63,
-	64, 52, 53, 54, 55, 56, 57, 0, 0, 0,
-	0, 46, 47, 0, 48, 49, 50, 51, 19, 20,
-	21, 0, 17, 0, 117, 0, 19, 20, 21, 0,
-	17, 0, 195, 0, 0, 19, 20, 21, 0, 17,
-	0, 193, 0, 19, 20, 21, 0, 17, 0, 9,
-	0, 23, 26, 24, 25, 27, 14, 118, 15, 23,
-	26, 24, 25, 27, 14, 131, 15, 0, 23, 26,
-	24, 25, 27, 14, 22, 15, 23, 26, 24, 25,
-	27, 14, 22, 15, 19, 20, 21, 0, 17, 0,
-	117, 22, 19, 20, 21, 0, 0, 0, 148, 22,
-	0, 29, 32, 39, 3, 0, 0, 30, 0, 0,
-	0, 31, 33, 0, 0, 0, 0, 23, 26, 24,
-	25, 27, 0, 0, 0, 23, 26, 24, 25, 27,
-	0, 0, 0, 119, 121, 122, 123, 124, 0, 0,
-	22, 0, 75, 76, 77, 81, 0, 0, 22, 140,
-	0, 0, 80, 78, 79, 83, 82, 84, 85, 86,
-	87, 88, 89, 90,
+const yyLast = 868
+
+var yyAct = [...]int16{
+	89, 294, 88, 293, 5, 6, 8, 7, 82, 236,
+	2, 18, 210, 78, 55, 65, 282, 171, 28, 54,
+	211, 212, 202, 203, 204, 205, 206, 207, 209, 208,
+	284, 87, 283, 133, 134, 137, 135, 197, 198, 267,
+	199, 200, 201, 210, 266, 265, 156, 158, 159, 160,
+	161, 162, 163, 164, 165, 166, 167, 168, 264, 170,
+	170, 211, 212, 202, 203, 204, 205, 206, 207, 209,
+	208, 197, 198, 263, 199, 200, 201, 210, 197, 198,
+	13, 199, 200, 201, 210, 262, 301, 286, 193, 195,
+	58, 177, 213, 214, 215, 90, 91, 92, 96, 118,
+	285, 81, 83, 280, 171, 95, 93, 94, 98, 97,
+	99, 100, 101, 102, 103, 104, 105, 106, 107, 108,
+	109, 111, 110, 112, 113, 114, 123, 119, 120, 121,
+	122, 115, 116, 117, 175, 307, 299, 226, 227, 228,
+	229, 287, 86, 279, 73, 74, 233, 75, 76, 77,
+	78, 300, 299, 185, 187, 188, 189, 190, 191, 192,
+	222, 173, 233, 298, 299, 275, 84, 85, 60, 61,
+	274, 62, 63, 64, 65, 225, 133, 134, 137, 135,
+	296, 297, 174, 238, 199, 200, 201, 210, 240, 75,
+	76, 77, 78, 223, 224, 305, 73, 74, 289, 75,
+	76, 77, 78, 242, 243, 132, 244, 245, 246, 247,
+	248, 249, 250, 251, 252, 253, 254, 255, 256, 257,
+	258, 259, 234, 60, 61, 261, 62, 63, 64, 65,
+	17, 288, 157, 278, 276, 277, 62, 63, 64, 65,
+	235, 232, 55, 231, 55, 230, 216, 178, 144, 240,
+	131, 130, 281, 202, 203, 204, 205, 206, 207, 209,
+	208, 66, 67, 68, 69, 70, 71, 234, 197, 198,
+	129, 199, 200, 201, 210, 128, 73, 74, 127, 75,
+	76, 77, 78, 80, 79, 133, 134, 137, 135, 278,
+	278, 277, 277, 295, 57, 72, 124, 125, 126, 269,
+	278, 304, 277, 302, 303, 175, 278, 59, 277, 306,
+	291, 292, 90, 91, 92, 96, 118, 268, 58, 83,
+	58, 221, 95, 93, 94, 98, 97, 99, 100, 101,
+	102, 103, 104, 105, 106, 107, 108, 109, 111, 110,
+	112, 113, 114, 123, 119, 120, 121, 122, 115, 116,
+	117, 273, 66, 67, 68, 69, 70, 71, 220, 86,
+	219, 66, 67, 68, 69, 70, 71, 73, 74, 218,
+	75, 76, 77, 78, 217, 272, 60, 61, 16, 62,
+	63, 64, 65, 84, 85, 4, 12, 10, 290, 194,
+	136, 1, 0, 0, 0, 0, 211, 212, 202, 203,
+	204, 205, 206, 207, 209, 208, 271, 0, 0, 0,
+	0, 0, 0, 197, 198, 0, 199, 200, 201, 210,
+	211, 212, 202, 203, 204, 205, 206, 207, 209, 208,
+	270, 0, 0, 0, 0, 0, 0, 197, 198, 0,
+	199, 200, 201, 210, 0, 0, 0, 0, 0, 0,
+	0, 211, 212, 202, 203, 204, 205, 206, 207, 209,
+	208, 260, 0, 0, 0, 0, 0, 0, 197, 198,
+	0, 199, 200, 201, 210, 211, 212, 202, 203, 204,
+	205, 206, 207, 209, 208, 241, 0, 0, 0, 0,
+	0, 0, 197, 198, 0, 199, 200, 201, 210, 0,
+	0, 0, 0, 0, 0, 0, 211, 212, 202, 203,
+	204, 205, 206, 207, 209, 208, 196, 0, 0, 0,
+	0, 0, 0, 197, 198, 0, 199, 200, 201, 210,
+	211, 212, 202, 203, 204, 205, 206, 207, 209, 208,
+	0, 0, 0, 0, 56, 11, 0, 197, 198, 0,
+	199, 200, 201, 210, 19, 20, 21, 0, 17, 0,
+	141, 0, 0, 211, 212, 202, 203, 204, 205, 206,
+	207, 209, 208, 19, 20, 21, 0, 17, 0, 141,
+	197, 198, 0, 199, 200, 201, 210, 0, 0, 0,
+	0, 0, 23, 26, 24, 25, 27, 14, 142, 15,
+	0, 138, 139, 140, 176, 179, 180, 181, 182, 183,
+	184, 23, 26, 24, 25, 27, 14, 142, 15, 19,
+	20, 21, 0, 17, 22, 239, 0, 19, 20, 21,
+	0, 17, 0, 237, 0, 19, 20, 21, 0, 17,
+	0, 9, 0, 22, 0, 19, 20, 21, 0, 17,
+	0, 141, 0, 172, 0, 0, 0, 23, 26, 24,
+	25, 27, 14, 0, 15, 23, 26, 24, 25, 27,
+	14, 169, 15, 23, 26, 24, 25, 27, 14, 0,
+	15, 0, 0, 23, 26, 24, 25, 27, 0, 22,
+	0, 0, 0, 0, 0, 0, 0, 22, 41, 44,
+	0, 0, 46, 0, 42, 22, 52, 0, 43, 47,
+	45, 48, 49, 50, 51, 22, 29, 32, 0, 0,
+	34, 0, 30, 0, 40, 0, 31, 35, 33, 36,
+	37, 38, 39, 19, 20, 21, 41, 44, 0, 186,
+	46, 0, 42, 0, 52, 0, 43, 47, 45, 48,
+	49, 50, 51, 29, 32, 0, 0, 34, 0, 30,
+	0, 40, 0, 31, 35, 33, 36, 37, 38, 39,
+	0, 23, 26, 24, 25, 27, 46, 0, 42, 0,
+	52, 0, 43, 47, 45, 48, 49, 50, 51, 118,
+	34, 0, 30, 0, 40, 0, 31, 35, 33, 36,
+	37, 38, 39, 22, 53, 3, 0, 106, 107, 108,
+	109, 111, 110, 112, 113, 114, 123, 119, 120, 121,
+	122, 115, 116, 117, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 143, 145, 146, 147, 148, 149,
+	150, 151, 152, 153, 154, 155, 90, 91, 92, 96,
+	0, 0, 0, 178, 0, 0, 95, 93, 94, 98,
+	97, 99, 100, 101, 102, 103, 104, 105,
 }
-var yyPact = [...]int{
-
-	517, -1000, -14, 285, -1000, 278, -1000, -1000, -1000, 517,
-	-1000, 430, -1000, 413, 186, 180, -1000, 219, -1000, -1000,
-	-1000, -1000, 255, 176, 165, 146, 142, 137, 492, 132,
-	132, 132, 132, 132, 147, 147, 147, 147, 147, 532,
-	91, 209, 41, 88, 396, 617, 128, 128, 128, 128,
-	128, 128, -1000, -1000, -1000, -1000, -1000, -1000, 566, 566,
-	566, 566, 566, 566, 566, 268, 268, -1000, 371, 268,
-	268, 268, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+
+var yyPact = [...]int16{
+	629, -1000, -39, 695, -1000, 678, -1000, -1000, -1000, 629,
+	-1000, 301, -1000, 201, 272, 271, -1000, 90, -1000, -1000,
+	-1000, -1000, 290, 266, 263, 258, 239, 238, 548, 236,
+	236, 236, 236, 236, 236, 236, 236, 236, 236, 236,
+	236, 220, 220, 220, 220, 220, 220, 220, 220, 220,
+	220, 220, 220, 658, 47, 640, 148, 169, 292, 841,
+	235, 235, 235, 235, 235, 235, -1000, -1000, -1000, -1000,
+	-1000, -1000, 727, 727, 727, 727, 727, 727, 727, 307,
+	307, -1000, 505, 307, 307, 307, 234, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	229, 228, 225, 118, -1000, -1000, -1000, 82, 268, 268,
-	268, 268, 278, -1000, -1000, -1000, -1000, 558, 113, 9,
-	509, -1000, -1000, 9, -1000, -31, 147, -1000, -1000, -31,
-	-1000, -1000, -1000, 492, -1000, -1000, -1000, -1000, 86, -1000,
-	500, 67, 67, -44, -44, -44, -44, 32, 566, 16,
-	16, -49, -49, -49, -49, 350, 71, 153, -1000, 268,
-	268, 268, 268, 268, 268, 268, 268, 268, 268, 268,
-	268, 268, 268, 268, 268, 323, -55, -55, 28, 11,
-	8, -4, 212, 208, -1000, 304, 258, 129, -5, 209,
-	12, 50, 52, 509, -1000, 500, -22, -1000, -1000, 268,
-	-55, -55, -53, -53, -53, -45, -45, -45, -45, -45,
-	-45, -45, -45, -53, 62, 62, -1000, -1000, -1000, -1000,
-	-1000, -11, -12, -1000, -1000, -1000, -1000, -1000, 153, -1000,
-	-1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 370, 365,
+	356, 354, 317, 156, -1000, -1000, -1000, 162, 307, 307,
+	307, 307, -1000, 678, -1000, -1000, -1000, -1000, 233, 231,
+	229, 639, 228, 728, 621, -1000, -1000, 728, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 714, 220, -1000, -1000,
+	714, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 567, -1000, -1000, -1000, -1000, 93, -1000, 613, 158,
+	158, -66, -66, -66, -66, 69, 727, 111, 111, -68,
+	-68, -68, -68, 472, 190, -38, -1000, 307, 307, 307,
+	307, 307, 307, 307, 307, 307, 307, 307, 307, 307,
+	307, 307, 307, 448, 106, 106, 307, 29, 17, 2,
+	-11, -12, -17, 313, 295, -1000, 417, 393, 362, 338,
+	157, 152, 780, 640, 121, 130, 46, 621, -1000, 613,
+	-40, -1000, -1000, 307, 106, 106, -69, -69, -69, -4,
+	-4, -4, -4, -4, -4, -4, -4, -69, 193, 193,
+	-1000, 3, -1000, -1000, -1000, -1000, -1000, -1000, -24, -26,
+	-1000, -1000, -1000, -1000, 51, 38, 127, -1000, -1000, -1000,
+	567, -38, -1000, -1000, -1000, 219, 186, 304, 780, 780,
+	167, -1000, -1000, 150, -1000, 138, 37, 297, -1000, 780,
+	-1000, 183, -1000, -1000, -1000, 780, 122, -1000,
 }
-var yyPgo = [...]int{
 
-	0, 326, 4, 321, 2, 318, 3, 583, 279, 5,
-	278, 1, 260, 270, 346, 27, 267, 266, 7, 0,
-	143, 265, 213,
+var yyPgo = [...]int16{
+	0, 391, 7, 390, 6, 389, 1, 3, 388, 4,
+	804, 387, 9, 386, 5, 295, 385, 544, 80, 378,
+	294, 11, 205, 8, 31, 2, 0,
 }
-var yyR1 = [...]int{
-
-	0, 1, 1, 1, 7, 7, 7, 7, 7, 7,
-	7, 8, 9, 9, 9, 9, 9, 9, 9, 9,
-	9, 2, 3, 4, 5, 5, 6, 6, 6, 6,
-	6, 6, 6, 10, 10, 11, 12, 12, 12, 12,
-	12, 12, 13, 13, 14, 14, 14, 14, 14, 14,
-	14, 14, 16, 17, 15, 15, 15, 15, 15, 15,
-	15, 15, 15, 15, 15, 15, 15, 15, 18, 18,
-	18, 18, 18, 19, 19, 19, 19, 19, 19, 19,
-	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
-	19, 19, 19, 19, 19, 20, 20, 20, 20, 20,
-	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 21, 21, 21, 21, 21, 21, 21, 21, 21,
-	22, 22, 22, 22, 22, 22,
+
+var yyR1 = [...]int8{
+	0, 1, 1, 1, 1, 10, 10, 10, 10, 10,
+	10, 10, 10, 10, 10, 10, 10, 10, 10, 11,
+	12, 12, 12, 12, 12, 12, 12, 12, 12, 2,
+	3, 4, 5, 5, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 13, 13,
+	14, 15, 15, 15, 15, 15, 15, 16, 16, 17,
+	17, 17, 17, 17, 17, 17, 17, 19, 20, 18,
+	18, 18, 18, 18, 18, 18, 18, 18, 18, 18,
+	18, 18, 18, 21, 21, 21, 21, 21, 22, 22,
+	22, 22, 22, 22, 7, 7, 6, 6, 8, 8,
+	8, 8, 23, 23, 23, 23, 23, 23, 23, 23,
+	23, 23, 23, 23, 23, 23, 23, 23, 23, 23,
+	23, 23, 23, 23, 23, 24, 24, 24, 24, 24,
+	24, 24, 24, 24, 24, 24, 24, 24, 24, 24,
+	24, 25, 25, 25, 25, 25, 25, 25, 25, 25,
+	25, 25, 25, 26, 26, 26, 26, 26, 26, 26,
+	26,
 }
-var yyR2 = [...]int{
 
+var yyR2 = [...]int8{
 	0, 1, 1, 1, 3, 3, 3, 3, 3, 3,
-	1, 3, 1, 1, 1, 1, 3, 3, 3, 3,
-	3, 4, 3, 4, 1, 3, 3, 3, 3, 3,
-	3, 3, 1, 2, 3, 3, 1, 1, 1, 1,
-	1, 1, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 1, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 1, 1, 1, 1, 2, 2, 2, 3, 4,
-	4, 4, 4, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 1, 3,
+	1, 1, 1, 1, 3, 3, 3, 3, 3, 4,
+	3, 4, 1, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 1, 2, 3,
+	3, 1, 1, 1, 1, 1, 1, 3, 3, 3,
+	3, 3, 3, 3, 3, 3, 1, 3, 3, 3,
+	3, 3, 3, 3, 3, 3, 1, 1, 1, 1,
+	2, 2, 2, 3, 4, 4, 4, 4, 3, 7,
+	3, 7, 6, 10, 1, 3, 1, 1, 1, 1,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	2, 2, 1, 1, 1, 1, 1, 1, 1, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 2,
+	2, 4, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	3, 3, 3, 3, 4, 4,
+	1, 1, 1, 3, 3, 3, 3, 3, 3, 4,
+	4,
 }
-var yyChk = [...]int{
-
-	-1000, -1, -9, -7, -13, -6, -11, -2, -4, 12,
-	-8, -14, -10, -15, 44, 46, -16, 10, -18, 6,
-	7, 8, 62, 39, 41, 42, 40, 43, 48, 49,
-	55, 59, 50, 60, 49, 55, 59, 50, 60, -7,
-	-9, -6, -14, -17, -15, -12, 61, 62, 64, 65,
-	66, 67, 51, 52, 53, 54, 55, 56, -12, 61,
-	62, 64, 65, 66, 67, 12, 12, 11, -19, 12,
-	62, 63, -20, -21, -22, 5, 6, 7, 16, 17,
-	15, 8, 19, 18, 20, 21, 22, 23, 24, 25,
-	26, 27, 28, 29, 30, 32, 31, 33, 34, 35,
-	9, 37, 38, 36, 6, 7, 8, 12, 12, 12,
-	12, 12, -6, -11, -2, -3, -4, 12, 45, -7,
-	12, -7, -7, -7, -7, -6, 12, -6, -6, -6,
-	-6, 13, 13, 48, 13, 13, 13, 13, -14, -20,
-	12, -14, -14, -14, -14, -14, -14, -15, 12, -15,
-	-15, -15, -15, -15, -15, -19, -5, -19, 11, 61,
-	62, 64, 65, 66, 51, 52, 53, 54, 55, 56,
-	58, 57, 67, 49, 50, -19, -19, -19, 4, 4,
-	4, 4, 37, 38, 13, -19, -19, -19, -19, -6,
-	-15, 12, -9, 12, -18, 12, -9, 13, 13, 14,
-	-19, -19, -19, -19, -19, -19, -19, -19, -19, -19,
-	-19, -19, -19, -19, -19, -19, 13, 47, 47, 47,
-	47, 4, 4, 13, 13, 13, 13, 13, -19, 47,
-	47,
+
+var yyChk = [...]int16{
+	-1000, -1, -12, -10, -16, -9, -14, -2, -4, 12,
+	-11, -17, -13, -18, 49, 51, -19, 10, -21, 6,
+	7, 8, 76, 44, 46, 47, 45, 48, 57, 58,
+	64, 68, 59, 70, 62, 69, 71, 72, 73, 74,
+	66, 58, 64, 68, 59, 70, 62, 69, 71, 72,
+	73, 74, 66, -10, -12, -9, -17, -20, -18, -15,
+	75, 76, 78, 79, 80, 81, 60, 61, 62, 63,
+	64, 65, -15, 75, 76, 78, 79, 80, 81, 12,
+	12, 11, -23, 12, 76, 77, 52, -24, -25, -26,
+	5, 6, 7, 16, 17, 15, 8, 19, 18, 20,
+	21, 22, 23, 24, 25, 26, 27, 28, 29, 30,
+	32, 31, 33, 34, 35, 41, 42, 43, 9, 37,
+	38, 39, 40, 36, 6, 7, 8, 12, 12, 12,
+	12, 12, -22, -9, -14, -2, -3, -4, 53, 54,
+	55, 12, 50, -10, 12, -10, -10, -10, -10, -10,
+	-10, -10, -10, -10, -10, -10, -9, 12, -9, -9,
+	-9, -9, -9, -9, -9, -9, -9, -9, -9, 13,
+	13, 57, 13, 13, 13, 13, -17, -24, 12, -17,
+	-17, -17, -17, -17, -17, -18, 12, -18, -18, -18,
+	-18, -18, -18, -23, -5, -23, 11, 75, 76, 78,
+	79, 80, 60, 61, 62, 63, 64, 65, 67, 66,
+	81, 58, 59, -23, -23, -23, 12, 4, 4, 4,
+	4, 4, 4, 37, 38, 13, -23, -23, -23, -23,
+	12, 12, 12, -9, -18, 12, -12, 12, -21, 12,
+	-12, 13, 13, 14, -23, -23, -23, -23, -23, -23,
+	-23, -23, -23, -23, -23, -23, -23, -23, -23, -23,
+	13, -23, 56, 56, 56, 56, 56, 56, 4, 4,
+	13, 13, 13, 13, 13, 13, -6, -25, -26, 13,
+	57, -23, 13, 56, 56, 49, 49, 14, 12, 12,
+	-8, 6, 7, -7, -6, -7, 13, 14, 13, 14,
+	13, 49, 6, 7, -6, 12, -7, 13,
 }
-var yyDef = [...]int{
 
-	0, -2, 1, 2, 3, 12, 13, 14, 15, 0,
-	10, 0, 32, 0, 0, 0, 51, 0, 61, 62,
-	63, 64, 0, 0, 0, 0, 0, 0, 0, 0,
+var yyDef = [...]int16{
+	0, -2, 1, 2, 3, 20, 21, 22, 23, 0,
+	18, 0, 47, 0, 0, 0, 66, 0, 76, 77,
+	78, 79, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 12, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 36, 37, 38, 39, 40, 41, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 33, 0, 0,
-	0, 0, 92, 93, 94, 95, 96, 97, 98, 99,
-	100, 101, 102, 103, 104, 105, 106, 107, 108, 109,
-	110, 111, 112, 113, 114, 115, 116, 117, 118, 119,
-	0, 0, 0, 0, 65, 66, 67, 0, 0, 0,
-	0, 0, 16, 17, 18, 19, 20, 0, 0, 5,
-	0, 6, 7, 8, 9, 27, 0, 28, 29, 30,
-	31, 4, 11, 0, 26, 44, 52, 54, 42, 43,
-	0, 45, 46, 47, 48, 49, 50, 35, 0, 55,
-	56, 57, 58, 59, 60, 0, 0, 24, 34, 0,
+	0, 0, 0, 0, 0, 20, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 51, 52, 53, 54,
+	55, 56, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 48, 0, 0, 0, 0, 0, 122, 123, 124,
+	125, 126, 127, 128, 129, 130, 131, 132, 133, 134,
+	135, 136, 137, 138, 139, 140, 141, 142, 143, 144,
+	145, 146, 147, 148, 149, 150, 151, 152, 0, 0,
+	0, 0, 0, 0, 80, 81, 82, 0, 0, 0,
+	0, 0, 4, 24, 25, 26, 27, 28, 0, 0,
+	0, 0, 0, 6, 0, 7, 8, 9, 10, 11,
+	12, 13, 14, 15, 16, 17, 35, 0, 36, 37,
+	38, 39, 40, 41, 42, 43, 44, 45, 46, 5,
+	19, 0, 34, 59, 67, 69, 57, 58, 0, 60,
+	61, 62, 63, 64, 65, 50, 0, 70, 71, 72,
+	73, 74, 75, 0, 0, 32, 49, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 90, 91, 0, 0,
-	0, 0, 0, 0, 68, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, -2, 0, 0, 21, 23, 0,
-	74, 75, 76, 77, 78, 79, 80, 81, 82, 83,
-	84, 85, 86, 87, 88, 89, 73, 120, 121, 122,
-	123, 0, 0, 69, 70, 71, 72, 22, 25, 124,
-	125,
+	0, 0, 0, 0, 119, 120, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 83, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, -2, 0,
+	0, 29, 31, 0, 103, 104, 105, 106, 107, 108,
+	109, 110, 111, 112, 113, 114, 115, 116, 117, 118,
+	102, 0, 153, 154, 155, 156, 157, 158, 0, 0,
+	84, 85, 86, 87, 88, 90, 0, 96, 97, 30,
+	0, 33, 121, 159, 160, 0, 0, 0, 0, 0,
+	0, 98, 99, 0, 94, 0, 92, 0, 89, 0,
+	91, 0, 100, 101, 95, 0, 0, 93,
 }
-var yyTok1 = [...]int{
 
+var yyTok1 = [...]int8{
 	1,
 }
-var yyTok2 = [...]int{
 
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
 	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
 	52, 53, 54, 55, 56, 57, 58, 59, 60, 61,
-	62, 63, 64, 65, 66, 67,
+	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
+	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 }
-var yyTok3 = [...]int{
+
+var yyTok3 = [...]int8{
 	0,
 }
 
@@ -484,9 +569,9 @@ func yyErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := yyPact[state]
+	base := int(yyPact[state])
 	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
-		if n := base + tok; n >= 0 && n < yyLast && yyChk[yyAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -496,13 +581,13 @@ func yyErrorMessage(state, lookAhead int) string {
 
 	if yyDef[state] == -2 {
 		i := 0
-		for yyExca[i] != -1 || yyExca[i+1] != state {
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; yyExca[i] >= 0; i += 2 {
-			tok := yyExca[i]
+			tok := int(yyExca[i])
 			if tok < TOKSTART || yyExca[i+1] == 0 {
 				continue
 			}
@@ -533,30 +618,30 @@ func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		token = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			token = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		token = yyTok3[i+0]
+		token = int(yyTok3[i+0])
 		if token == char {
-			token = yyTok3[i+1]
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = yyTok2[1] /* unknown char */
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
@@ -611,7 +696,7 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
@@ -622,8 +707,8 @@ yynewstate:
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yytoken { /* valid shift */
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
 		yyrcvr.char = -1
 		yytoken = -1
 		yyVAL = yyrcvr.lval
@@ -636,7 +721,7 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
 		if yyrcvr.char < 0 {
 			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
@@ -645,18 +730,18 @@ yydefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
+			yyn = int(yyExca[xi+0])
 			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -678,10 +763,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -717,7 +802,7 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
 	// yyp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if yyp+1 >= len(yyS) {
@@ -728,16 +813,16 @@ yydefault:
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -745,751 +830,961 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:98
+//line expr.y:108
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipeline)
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:99
+//line expr.y:109
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipelineExpression)
 		}
 	case 3:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:100
+//line expr.y:110
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].scalarPipelineExpressionFilter)
 		}
 	case 4:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:107
+//line expr.y:111
 		{
-			yyVAL.spansetPipelineExpression = yyDollar[2].spansetPipelineExpression
+			yylex.(*lexer).expr = newRootExprWithMetrics(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation)
 		}
 	case 5:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:108
+//line expr.y:118
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = yyDollar[2].spansetPipelineExpression
 		}
 	case 6:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:109
+//line expr.y:119
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 7:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:110
+//line expr.y:120
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 8:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:111
+//line expr.y:121
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 9:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:112
+//line expr.y:122
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 10:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:123
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 11:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:124
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 12:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:125
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 13:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:126
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 14:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:127
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 15:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:128
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 16:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:129
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 17:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:130
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 18:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:113
+//line expr.y:131
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[1].wrappedSpansetPipeline
 		}
-	case 11:
+	case 19:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:117
+//line expr.y:135
 		{
 			yyVAL.wrappedSpansetPipeline = yyDollar[2].spansetPipeline
 		}
-	case 12:
+	case 20:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:120
+//line expr.y:138
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].spansetExpression)
 		}
-	case 13:
+	case 21:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:121
+//line expr.y:139
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].scalarFilter)
 		}
-	case 14:
+	case 22:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:122
+//line expr.y:140
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].groupOperation)
 		}
-	case 15:
+	case 23:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:123
+//line expr.y:141
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].selectOperation)
 		}
-	case 16:
+	case 24:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:124
+//line expr.y:142
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].spansetExpression)
 		}
-	case 17:
+	case 25:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:125
+//line expr.y:143
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].scalarFilter)
 		}
-	case 18:
+	case 26:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:126
+//line expr.y:144
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].groupOperation)
 		}
-	case 19:
+	case 27:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:127
+//line expr.y:145
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].coalesceOperation)
 		}
-	case 20:
+	case 28:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:128
+//line expr.y:146
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].selectOperation)
 		}
-	case 21:
+	case 29:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:132
+//line expr.y:150
 		{
 			yyVAL.groupOperation = newGroupOperation(yyDollar[3].fieldExpression)
 		}
-	case 22:
+	case 30:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:136
+//line expr.y:154
 		{
 			yyVAL.coalesceOperation = newCoalesceOperation()
 		}
-	case 23:
+	case 31:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:140
+//line expr.y:158
 		{
 			yyVAL.selectOperation = newSelectOperation(yyDollar[3].selectArgs)
 		}
-	case 24:
+	case 32:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:144
+//line expr.y:162
 		{
 			yyVAL.selectArgs = []FieldExpression{yyDollar[1].fieldExpression}
 		}
-	case 25:
+	case 33:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:145
+//line expr.y:163
 		{
 			yyVAL.selectArgs = append(yyDollar[1].selectArgs, yyDollar[3].fieldExpression)
 		}
-	case 26:
+	case 34:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:149
+//line expr.y:167
 		{
 			yyVAL.spansetExpression = yyDollar[2].spansetExpression
 		}
-	case 27:
+	case 35:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:150
+//line expr.y:168
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 28:
+	case 36:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:151
+//line expr.y:169
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 29:
+	case 37:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:152
+//line expr.y:170
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 30:
+	case 38:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:153
+//line expr.y:171
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 31:
+	case 39:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:154
+//line expr.y:172
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 32:
+	case 40:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:173
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 41:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:174
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 42:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:175
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 43:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:176
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 44:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:177
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 45:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:178
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 46:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:179
+		{
+			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+		}
+	case 47:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:155
+//line expr.y:180
 		{
 			yyVAL.spansetExpression = yyDollar[1].spansetFilter
 		}
-	case 33:
+	case 48:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:159
+//line expr.y:184
 		{
 			yyVAL.spansetFilter = newSpansetFilter(NewStaticBool(true))
 		}
-	case 34:
+	case 49:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:160
+//line expr.y:185
 		{
 			yyVAL.spansetFilter = newSpansetFilter(yyDollar[2].fieldExpression)
 		}
-	case 35:
+	case 50:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:164
+//line expr.y:189
 		{
 			yyVAL.scalarFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 36:
+	case 51:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:168
+//line expr.y:193
 		{
 			yyVAL.scalarFilterOperation = OpEqual
 		}
-	case 37:
+	case 52:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:169
+//line expr.y:194
 		{
 			yyVAL.scalarFilterOperation = OpNotEqual
 		}
-	case 38:
+	case 53:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:170
+//line expr.y:195
 		{
 			yyVAL.scalarFilterOperation = OpLess
 		}
-	case 39:
+	case 54:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:171
+//line expr.y:196
 		{
 			yyVAL.scalarFilterOperation = OpLessEqual
 		}
-	case 40:
+	case 55:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:172
+//line expr.y:197
 		{
 			yyVAL.scalarFilterOperation = OpGreater
 		}
-	case 41:
+	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:173
+//line expr.y:198
 		{
 			yyVAL.scalarFilterOperation = OpGreaterEqual
 		}
-	case 42:
+	case 57:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:180
+//line expr.y:205
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 43:
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:181
+//line expr.y:206
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].static)
 		}
-	case 44:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:185
+//line expr.y:210
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[2].scalarPipelineExpression
 		}
-	case 45:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:186
+//line expr.y:211
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpAdd, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 46:
+	case 61:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:187
+//line expr.y:212
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpSub, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 47:
+	case 62:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:188
+//line expr.y:213
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMult, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 48:
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:189
+//line expr.y:214
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpDiv, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 49:
+	case 64:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:190
+//line expr.y:215
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMod, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 50:
+	case 65:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:191
+//line expr.y:216
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpPower, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 51:
+	case 66:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:192
+//line expr.y:217
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[1].wrappedScalarPipeline
 		}
-	case 52:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:196
+//line expr.y:221
 		{
 			yyVAL.wrappedScalarPipeline = yyDollar[2].scalarPipeline
 		}
-	case 53:
+	case 68:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:200
+//line expr.y:225
 		{
 			yyVAL.scalarPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].aggregate)
 		}
-	case 54:
+	case 69:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:204
+//line expr.y:229
 		{
 			yyVAL.scalarExpression = yyDollar[2].scalarExpression
 		}
-	case 55:
+	case 70:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:205
+//line expr.y:230
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpAdd, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 56:
+	case 71:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:206
+//line expr.y:231
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpSub, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 57:
+	case 72:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:207
+//line expr.y:232
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMult, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 58:
+	case 73:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:208
+//line expr.y:233
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpDiv, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 59:
+	case 74:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:209
+//line expr.y:234
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMod, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 60:
+	case 75:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:210
+//line expr.y:235
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpPower, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 61:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:211
+//line expr.y:236
 		{
 			yyVAL.scalarExpression = yyDollar[1].aggregate
 		}
-	case 62:
+	case 77:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:212
+//line expr.y:237
 		{
 			yyVAL.scalarExpression = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 63:
+	case 78:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:213
+//line expr.y:238
 		{
 			yyVAL.scalarExpression = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 64:
+	case 79:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:214
+//line expr.y:239
 		{
 			yyVAL.scalarExpression = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 65:
+	case 80:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:215
+//line expr.y:240
 		{
 			yyVAL.scalarExpression = NewStaticInt(-yyDollar[2].staticInt)
 		}
-	case 66:
+	case 81:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:216
+//line expr.y:241
 		{
 			yyVAL.scalarExpression = NewStaticFloat(-yyDollar[2].staticFloat)
 		}
-	case 67:
+	case 82:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:217
+//line expr.y:242
 		{
 			yyVAL.scalarExpression = NewStaticDuration(-yyDollar[2].staticDuration)
 		}
-	case 68:
+	case 83:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:221
+//line expr.y:246
 		{
 			yyVAL.aggregate = newAggregate(aggregateCount, nil)
 		}
-	case 69:
+	case 84:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:222
+//line expr.y:247
 		{
 			yyVAL.aggregate = newAggregate(aggregateMax, yyDollar[3].fieldExpression)
 		}
-	case 70:
+	case 85:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:223
+//line expr.y:248
 		{
 			yyVAL.aggregate = newAggregate(aggregateMin, yyDollar[3].fieldExpression)
 		}
-	case 71:
+	case 86:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:224
+//line expr.y:249
 		{
 			yyVAL.aggregate = newAggregate(aggregateAvg, yyDollar[3].fieldExpression)
 		}
-	case 72:
+	case 87:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:225
+//line expr.y:250
 		{
 			yyVAL.aggregate = newAggregate(aggregateSum, yyDollar[3].fieldExpression)
 		}
-	case 73:
+	case 88:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:257
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, nil)
+		}
+	case 89:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line expr.y:258
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, yyDollar[6].attributeList)
+		}
+	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:232
+//line expr.y:259
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, nil)
+		}
+	case 91:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line expr.y:260
+		{
+			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, yyDollar[6].attributeList)
+		}
+	case 92:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:261
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, nil)
+		}
+	case 93:
+		yyDollar = yyS[yypt-10 : yypt+1]
+//line expr.y:262
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[9].attributeList)
+		}
+	case 94:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:266
+		{
+			yyVAL.attributeList = []Attribute{yyDollar[1].attribute}
+		}
+	case 95:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:267
+		{
+			yyVAL.attributeList = append(yyDollar[1].attributeList, yyDollar[3].attribute)
+		}
+	case 96:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:271
+		{
+			yyVAL.attribute = yyDollar[1].intrinsicField
+		}
+	case 97:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:272
+		{
+			yyVAL.attribute = yyDollar[1].attributeField
+		}
+	case 98:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:276
+		{
+			yyVAL.numericList = []float64{float64(yyDollar[1].staticInt)}
+		}
+	case 99:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:277
+		{
+			yyVAL.numericList = []float64{yyDollar[1].staticFloat}
+		}
+	case 100:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:278
+		{
+			yyVAL.numericList = append(yyDollar[1].numericList, float64(yyDollar[3].staticInt))
+		}
+	case 101:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:279
+		{
+			yyVAL.numericList = append(yyDollar[1].numericList, yyDollar[3].staticFloat)
+		}
+	case 102:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:286
 		{
 			yyVAL.fieldExpression = yyDollar[2].fieldExpression
 		}
-	case 74:
+	case 103:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:233
+//line expr.y:287
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAdd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 75:
+	case 104:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:234
+//line expr.y:288
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpSub, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 76:
+	case 105:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:235
+//line expr.y:289
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMult, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 77:
+	case 106:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:236
+//line expr.y:290
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpDiv, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 78:
+	case 107:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:237
+//line expr.y:291
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMod, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 79:
+	case 108:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:238
+//line expr.y:292
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 80:
+	case 109:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:239
+//line expr.y:293
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 81:
+	case 110:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:240
+//line expr.y:294
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLess, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 82:
+	case 111:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:241
+//line expr.y:295
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLessEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 83:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:242
+//line expr.y:296
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreater, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 84:
+	case 113:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:243
+//line expr.y:297
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreaterEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 85:
+	case 114:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:244
+//line expr.y:298
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 86:
+	case 115:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:245
+//line expr.y:299
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 87:
+	case 116:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:246
+//line expr.y:300
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpPower, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 88:
+	case 117:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:247
+//line expr.y:301
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAnd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 89:
+	case 118:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:248
+//line expr.y:302
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpOr, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 90:
+	case 119:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:249
+//line expr.y:303
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpSub, yyDollar[2].fieldExpression)
 		}
-	case 91:
+	case 120:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:250
+//line expr.y:304
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpNot, yyDollar[2].fieldExpression)
 		}
-	case 92:
+	case 121:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:305
+		{
+			yyVAL.fieldExpression = newUnaryOperation(OpLen, yyDollar[3].fieldExpression)
+		}
+	case 122:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:251
+//line expr.y:306
 		{
 			yyVAL.fieldExpression = yyDollar[1].static
 		}
-	case 93:
+	case 123:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:252
+//line expr.y:307
 		{
 			yyVAL.fieldExpression = yyDollar[1].intrinsicField
 		}
-	case 94:
+	case 124:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:253
+//line expr.y:308
 		{
 			yyVAL.fieldExpression = yyDollar[1].attributeField
 		}
-	case 95:
+	case 125:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:260
+//line expr.y:315
 		{
 			yyVAL.static = NewStaticString(yyDollar[1].staticStr)
 		}
-	case 96:
+	case 126:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:261
+//line expr.y:316
 		{
 			yyVAL.static = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 97:
+	case 127:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:262
+//line expr.y:317
 		{
 			yyVAL.static = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 98:
+	case 128:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:263
+//line expr.y:318
 		{
 			yyVAL.static = NewStaticBool(true)
 		}
-	case 99:
+	case 129:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:264
+//line expr.y:319
 		{
 			yyVAL.static = NewStaticBool(false)
 		}
-	case 100:
+	case 130:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:265
+//line expr.y:320
 		{
 			yyVAL.static = NewStaticNil()
 		}
-	case 101:
+	case 131:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:266
+//line expr.y:321
 		{
 			yyVAL.static = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 102:
+	case 132:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:267
+//line expr.y:322
 		{
 			yyVAL.static = NewStaticStatus(StatusOk)
 		}
-	case 103:
+	case 133:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:268
+//line expr.y:323
 		{
 			yyVAL.static = NewStaticStatus(StatusError)
 		}
-	case 104:
+	case 134:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:269
+//line expr.y:324
 		{
 			yyVAL.static = NewStaticStatus(StatusUnset)
 		}
-	case 105:
+	case 135:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:270
+//line expr.y:325
 		{
 			yyVAL.static = NewStaticKind(KindUnspecified)
 		}
-	case 106:
+	case 136:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:271
+//line expr.y:326
 		{
 			yyVAL.static = NewStaticKind(KindInternal)
 		}
-	case 107:
+	case 137:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:272
+//line expr.y:327
 		{
 			yyVAL.static = NewStaticKind(KindServer)
 		}
-	case 108:
+	case 138:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:273
+//line expr.y:328
 		{
 			yyVAL.static = NewStaticKind(KindClient)
 		}
-	case 109:
+	case 139:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:274
+//line expr.y:329
 		{
 			yyVAL.static = NewStaticKind(KindProducer)
 		}
-	case 110:
+	case 140:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:275
+//line expr.y:330
 		{
 			yyVAL.static = NewStaticKind(KindConsumer)
 		}
-	case 111:
+	case 141:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:279
+//line expr.y:334
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 112:
+	case 142:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:280
+//line expr.y:335
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicChildCount)
 		}
-	case 113:
+	case 143:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:281
+//line expr.y:336
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 114:
+	case 144:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:282
+//line expr.y:337
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 115:
+	case 145:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:283
+//line expr.y:338
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 116:
+	case 146:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:284
+//line expr.y:339
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicParent)
 		}
-	case 117:
+	case 147:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:285
+//line expr.y:340
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
 		}
-	case 118:
+	case 148:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:286
+//line expr.y:341
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootService)
 		}
-	case 119:
+	case 149:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:287
+//line expr.y:342
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceDuration)
 		}
-	case 120:
+	case 150:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:343
+		{
+			yyVAL.intrinsicField = NewIntrinsic(IntrinsicEventName)
+		}
+	case 151:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:344
+		{
+			yyVAL.intrinsicField = NewIntrinsic(IntrinsicLinkTraceID)
+		}
+	case 152:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:345
+		{
+			yyVAL.intrinsicField = NewIntrinsic(IntrinsicLinkSpanID)
+		}
+	case 153:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:291
+//line expr.y:349
 		{
 			yyVAL.attributeField = NewAttribute(yyDollar[2].staticStr)
 		}
-	case 121:
+	case 154:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:292
+//line expr.y:350
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, false, yyDollar[2].staticStr)
 		}
-	case 122:
+	case 155:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:293
+//line expr.y:351
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, false, yyDollar[2].staticStr)
 		}
-	case 123:
+	case 156:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:352
+		{
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeEvent, false, yyDollar[2].staticStr)
+		}
+	case 157:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:294
+//line expr.y:353
+		{
+			yyVAL.attributeField = NewScopedAttribute(AttributeScopeLink, false, yyDollar[2].staticStr)
+		}
+	case 158:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:354
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeNone, true, yyDollar[2].staticStr)
 		}
-	case 124:
+	case 159:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:295
+//line expr.y:355
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, true, yyDollar[3].staticStr)
 		}
-	case 125:
+	case 160:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:296
+//line expr.y:356
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, true, yyDollar[3].staticStr)
 		}
@@ -11,64 +11,78 @@ import (
 )
 
 var tokens = map[string]int{
-	",":               COMMA,
-	".":               DOT,
-	"{":               OPEN_BRACE,
-	"}":               CLOSE_BRACE,
-	"(":               OPEN_PARENS,
-	")":               CLOSE_PARENS,
-	"=":               EQ,
-	"!=":              NEQ,
-	"=~":              RE,
-	"!~":              NRE,
-	">":               GT,
-	">=":              GTE,
-	"<":               LT,
-	"<=":              LTE,
-	"+":               ADD,
-	"-":               SUB,
-	"/":               DIV,
-	"%":               MOD,
-	"*":               MUL,
-	"^":               POW,
-	"true":            TRUE,
-	"false":           FALSE,
-	"nil":             NIL,
-	"ok":              STATUS_OK,
-	"error":           STATUS_ERROR,
-	"unset":           STATUS_UNSET,
-	"unspecified":     KIND_UNSPECIFIED,
-	"internal":        KIND_INTERNAL,
-	"server":          KIND_SERVER,
-	"client":          KIND_CLIENT,
-	"producer":        KIND_PRODUCER,
-	"consumer":        KIND_CONSUMER,
-	"&&":              AND,
-	"||":              OR,
-	"!":               NOT,
-	"|":               PIPE,
-	">>":              DESC,
-	"~":               TILDE,
-	"duration":        IDURATION,
-	"childCount":      CHILDCOUNT,
-	"name":            NAME,
-	"status":          STATUS,
-	"kind":            KIND,
-	"rootName":        ROOTNAME,
-	"rootServiceName": ROOTSERVICENAME,
-	"traceDuration":   TRACEDURATION,
-	"parent":          PARENT,
-	"parent.":         PARENT_DOT,
-	"resource.":       RESOURCE_DOT,
-	"span.":           SPAN_DOT,
-	"count":           COUNT,
-	"avg":             AVG,
-	"max":             MAX,
-	"min":             MIN,
-	"sum":             SUM,
-	"by":              BY,
-	"coalesce":        COALESCE,
-	"select":          SELECT,
+	",":                  COMMA,
+	".":                  DOT,
+	"{":                  OPEN_BRACE,
+	"}":                  CLOSE_BRACE,
+	"(":                  OPEN_PARENS,
+	")":                  CLOSE_PARENS,
+	"=":                  EQ,
+	"!=":                 NEQ,
+	"=~":                 RE,
+	"!~":                 NRE,
+	">":                  GT,
+	">=":                 GTE,
+	"<":                  LT,
+	"<=":                 LTE,
+	"+":                  ADD,
+	"-":                  SUB,
+	"/":                  DIV,
+	"%":                  MOD,
+	"*":                  MUL,
+	"^":                  POW,
+	"true":               TRUE,
+	"false":              FALSE,
+	"nil":                NIL,
+	"ok":                 STATUS_OK,
+	"error":              STATUS_ERROR,
+	"unset":              STATUS_UNSET,
+	"unspecified":        KIND_UNSPECIFIED,
+	"internal":           KIND_INTERNAL,
+	"server":             KIND_SERVER,
+	"client":             KIND_CLIENT,
+	"producer":           KIND_PRODUCER,
+	"consumer":           KIND_CONSUMER,
+	"&&":                 AND,
+	"||":                 OR,
+	"!":                  NOT,
+	"|":                  PIPE,
+	">>":                 DESC,
+	"<<":                 ANCE,
+	"!>":                 NOT_CHILD,
+	"!<":                 NOT_PARENT,
+	"!>>":                NOT_DESC,
+	"!<<":                NOT_ANCE,
+	"~":                  TILDE,
+	"duration":           IDURATION,
+	"childCount":         CHILDCOUNT,
+	"name":               NAME,
+	"status":             STATUS,
+	"kind":               KIND,
+	"rootName":           ROOTNAME,
+	"rootServiceName":    ROOTSERVICENAME,
+	"traceDuration":      TRACEDURATION,
+	"parent":             PARENT,
+	"parent.":            PARENT_DOT,
+	"resource.":          RESOURCE_DOT,
+	"span.":              SPAN_DOT,
+	"event.":             EVENT_DOT,
+	"link.":              LINK_DOT,
+	"event:name":         EVENT_NAME,
+	"link:traceID":       LINK_TRACE_ID,
+	"link:spanID":        LINK_SPAN_ID,
+	"count":              COUNT,
+	"avg":                AVG,
+	"max":                MAX,
+	"min":                MIN,
+	"sum":                SUM,
+	"by":                 BY,
+	"coalesce":           COALESCE,
+	"select":             SELECT,
+	"len":                LEN,
+	"rate":               RATE,
+	"count_over_time":    COUNT_OVER_TIME,
+	"quantile_over_time": QUANTILE_OVER_TIME,
 }
 
 type lexer struct {
@@ -165,9 +179,23 @@ func (l *lexer) Lex(lval *yySymType) int {
 		return FLOAT
 	}
 
+	// intrinsics of events and links like event:name are a single token
+	if l.Peek() == ':' {
+		if tok, ok := tryScanScopedIntrinsic(l.TokenText(), &l.Scanner); ok {
+			return tok
+		}
+	}
+
 	tokStrNext := l.TokenText() + string(l.Peek())
 	if tok, ok := tokens[tokStrNext]; ok {
 		l.Next()
+
+		// check for three character tokens like !>>
+		if tok3, ok := tokens[tokStrNext+string(l.Peek())]; ok {
+			l.Next()
+			tok = tok3
+		}
+
 		l.parsingAttribute = startsAttribute(tok)
 		return tok
 	}
@@ -215,6 +243,30 @@ func tryScanDuration(number string, l *scanner.Scanner) (time.Duration, bool) {
 	return d, true
 }
 
+func tryScanScopedIntrinsic(scope string, l *scanner.Scanner) (int, bool) {
+	var sb strings.Builder
+	sb.WriteString(scope)
+	//copy the scanner to avoid advancing it in case it's not a scoped intrinsic.
+	s := *l
+	_, _ = sb.WriteRune(s.Next()) // :
+	consumed := 1
+	for r := s.Peek(); unicode.IsLetter(r); r = s.Peek() {
+		_, _ = sb.WriteRune(r)
+		_ = s.Next()
+		consumed++
+	}
+
+	tok, ok := tokens[sb.String()]
+	if !ok {
+		return 0, false
+	}
+	// we need to consume the scanner, now that we know this is a scoped intrinsic.
+	for i := 0; i < consumed; i++ {
+		_ = l.Next()
+	}
+	return tok, true
+}
+
 func parseDuration(d string) (time.Duration, error) {
 	var duration time.Duration
 	// Try to parse promql style durations first, to ensure that we support the same duration
@@ -261,5 +313,7 @@ func startsAttribute(tok int) bool {
 	return tok == DOT ||
 		tok == RESOURCE_DOT ||
 		tok == SPAN_DOT ||
+		tok == EVENT_DOT ||
+		tok == LINK_DOT ||
 		tok == PARENT_DOT
 }
@@ -40,6 +40,19 @@ func TestLexerAttributes(t *testing.T) {
 		{`resource.foo3`, []int{RESOURCE_DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`resource.foo+bar`, []int{RESOURCE_DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`resource.foo-bar`, []int{RESOURCE_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		// event attributes
+		{`event.foo`, []int{EVENT_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`event.count`, []int{EVENT_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`event.foo-bar`, []int{EVENT_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		// link attributes
+		{`link.foo`, []int{LINK_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`link.count`, []int{LINK_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		{`link.foo-bar`, []int{LINK_DOT, IDENTIFIER, END_ATTRIBUTE}},
+		// event and link intrinsics
+		{`event:name`, []int{EVENT_NAME}},
+		{`link:traceID`, []int{LINK_TRACE_ID}},
+		{`link:spanID`, []int{LINK_SPAN_ID}},
+		{`event:name="foo"`, []int{EVENT_NAME, EQ, STRING}},
 		// parent span attributes
 		{`parent.span.foo`, []int{PARENT_DOT, SPAN_DOT, IDENTIFIER, END_ATTRIBUTE}},
 		{`parent.span.count`, []int{PARENT_DOT, SPAN_DOT, IDENTIFIER, END_ATTRIBUTE}},
@@ -66,6 +79,23 @@ func TestLexerAttributes(t *testing.T) {
 	}))
 }
 
+func TestLexerSpansetOperators(t *testing.T) {
+	testLexer(t, ([]lexerTestCase{
+		{`{ } > { }`, []int{OPEN_BRACE, CLOSE_BRACE, GT, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } >> { }`, []int{OPEN_BRACE, CLOSE_BRACE, DESC, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } < { }`, []int{OPEN_BRACE, CLOSE_BRACE, LT, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } << { }`, []int{OPEN_BRACE, CLOSE_BRACE, ANCE, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } ~ { }`, []int{OPEN_BRACE, CLOSE_BRACE, TILDE, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !> { }`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_CHILD, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !>> { }`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_DESC, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !< { }`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_PARENT, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !<< { }`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_ANCE, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ } !~ { }`, []int{OPEN_BRACE, CLOSE_BRACE, NRE, OPEN_BRACE, CLOSE_BRACE}},
+		{`{}!>>{}`, []int{OPEN_BRACE, CLOSE_BRACE, NOT_DESC, OPEN_BRACE, CLOSE_BRACE}},
+		{`{ .a }!<<{ .b }`, []int{OPEN_BRACE, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_BRACE, NOT_ANCE, OPEN_BRACE, DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_BRACE}},
+	}))
+}
+
 func TestLexerDuration(t *testing.T) {
 	testLexer(t, ([]lexerTestCase{
 		// duration
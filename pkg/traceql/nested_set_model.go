@@ -0,0 +1,202 @@
+package traceql
+
+import "sort"
+
+// Structural operators are evaluated using the nested set model. Every span is assigned a
+// left and a right bound where the bounds of a span contain the bounds of all its descendants.
+// The left bound doubles as a numeric span ID and is used as the parent value of the span's
+// children. The fetch layer retrieves these values when a query contains structural operators.
+//
+// Spans without assigned bounds (left bound = 0) can't be placed in the trace and are never
+// returned by structural operators, including the negated ones.
+
+// structuralMatches returns all spans of rhs that are related to at least one span of lhs
+// according to the given structural operator. Negated operators return all spans of rhs
+// that are related to no span of lhs.
+func structuralMatches(op Operator, lhs, rhs []Span) []Span {
+	var (
+		related []bool
+		invert  bool
+	)
+
+	switch op {
+	case OpSpansetChild, OpSpansetNotChild:
+		related = childOf(lhs, rhs)
+		invert = op == OpSpansetNotChild
+	case OpSpansetParent, OpSpansetNotParent:
+		related = parentOf(lhs, rhs)
+		invert = op == OpSpansetNotParent
+	case OpSpansetDescendant, OpSpansetNotDescendant:
+		related = descendantOf(lhs, rhs)
+		invert = op == OpSpansetNotDescendant
+	case OpSpansetAncestor, OpSpansetNotAncestor:
+		related = ancestorOf(lhs, rhs)
+		invert = op == OpSpansetNotAncestor
+	case OpSpansetSibling, OpSpansetNotSibling:
+		related = siblingOf(lhs, rhs)
+		invert = op == OpSpansetNotSibling
+	default:
+		return nil
+	}
+
+	var matches []Span
+	for i, s := range rhs {
+		if !hasNestedSet(s) {
+			continue
+		}
+		if related[i] != invert {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
+
+func hasNestedSet(s Span) bool {
+	return s.NestedSetLeft() > 0 && s.NestedSetRight() > 0
+}
+
+// childOf returns for each span in rhs if its parent is in lhs
+func childOf(lhs, rhs []Span) []bool {
+	lefts := make(map[int32]struct{}, len(lhs))
+	for _, s := range lhs {
+		if hasNestedSet(s) {
+			lefts[s.NestedSetLeft()] = struct{}{}
+		}
+	}
+
+	related := make([]bool, len(rhs))
+	for i, s := range rhs {
+		if s.NestedSetParent() == 0 {
+			continue
+		}
+		_, related[i] = lefts[s.NestedSetParent()]
+	}
+	return related
+}
+
+// parentOf returns for each span in rhs if it is the parent of a span in lhs
+func parentOf(lhs, rhs []Span) []bool {
+	parents := make(map[int32]struct{}, len(lhs))
+	for _, s := range lhs {
+		if hasNestedSet(s) && s.NestedSetParent() != 0 {
+			parents[s.NestedSetParent()] = struct{}{}
+		}
+	}
+
+	related := make([]bool, len(rhs))
+	for i, s := range rhs {
+		_, related[i] = parents[s.NestedSetLeft()]
+	}
+	return related
+}
+
+// siblingOf returns for each span in rhs if another span in lhs has the same parent
+func siblingOf(lhs, rhs []Span) []bool {
+	lefts := make(map[int32]struct{}, len(lhs))
+	childCount := make(map[int32]int, len(lhs))
+	for _, s := range lhs {
+		if !hasNestedSet(s) || s.NestedSetParent() == 0 {
+			continue
+		}
+		if _, ok := lefts[s.NestedSetLeft()]; ok {
+			continue
+		}
+		lefts[s.NestedSetLeft()] = struct{}{}
+		childCount[s.NestedSetParent()]++
+	}
+
+	related := make([]bool, len(rhs))
+	for i, s := range rhs {
+		if s.NestedSetParent() == 0 {
+			continue
+		}
+		count := childCount[s.NestedSetParent()]
+		if _, ok := lefts[s.NestedSetLeft()]; ok {
+			count-- // a span is not its own sibling
+		}
+		related[i] = count > 0
+	}
+	return related
+}
+
+// nestedSetEntry is a span bound in a sweep over the nested set bounds of lhs and rhs
+type nestedSetEntry struct {
+	left, right int32
+	rhsIdx      int // index in rhs or -1 for spans of lhs
+}
+
+// sortedNestedSetEntries returns the bounds of all spans in lhs and rhs sorted by left bound.
+// If a span is in both lhs and rhs, the entry of lhs comes first if lhsFirst is true.
+func sortedNestedSetEntries(lhs, rhs []Span, lhsFirst bool) []nestedSetEntry {
+	entries := make([]nestedSetEntry, 0, len(lhs)+len(rhs))
+	for _, s := range lhs {
+		if hasNestedSet(s) {
+			entries = append(entries, nestedSetEntry{left: s.NestedSetLeft(), right: s.NestedSetRight(), rhsIdx: -1})
+		}
+	}
+	for i, s := range rhs {
+		if hasNestedSet(s) {
+			entries = append(entries, nestedSetEntry{left: s.NestedSetLeft(), right: s.NestedSetRight(), rhsIdx: i})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].left != entries[j].left {
+			return entries[i].left < entries[j].left
+		}
+		if lhsFirst {
+			return entries[i].rhsIdx < entries[j].rhsIdx
+		}
+		return entries[i].rhsIdx > entries[j].rhsIdx
+	})
+	return entries
+}
+
+// descendantOf returns for each span in rhs if one of its ancestors is in lhs. It sweeps over all
+// spans ordered by left bound and keeps a stack of the lhs spans that contain the current position.
+func descendantOf(lhs, rhs []Span) []bool {
+	related := make([]bool, len(rhs))
+	// rhs first to not count a span that is in lhs and rhs as its own descendant
+	entries := sortedNestedSetEntries(lhs, rhs, false)
+
+	var stack []int32 // right bounds of the open lhs spans
+	for _, e := range entries {
+		for len(stack) > 0 && stack[len(stack)-1] < e.left {
+			stack = stack[:len(stack)-1]
+		}
+
+		if e.rhsIdx < 0 {
+			stack = append(stack, e.right)
+			continue
+		}
+		related[e.rhsIdx] = len(stack) > 0
+	}
+	return related
+}
+
+// ancestorOf returns for each span in rhs if one of its descendants is in lhs. It sweeps over all
+// spans ordered by left bound and keeps a stack of the rhs spans that contain the current position.
+func ancestorOf(lhs, rhs []Span) []bool {
+	related := make([]bool, len(rhs))
+	// lhs first to not count a span that is in lhs and rhs as its own ancestor
+	entries := sortedNestedSetEntries(lhs, rhs, true)
+
+	var stack []nestedSetEntry // open rhs spans
+	for _, e := range entries {
+		for len(stack) > 0 && stack[len(stack)-1].right < e.left {
+			stack = stack[:len(stack)-1]
+		}
+
+		if e.rhsIdx >= 0 {
+			stack = append(stack, e)
+			continue
+		}
+
+		// all spans on the stack are ancestors of the current span. once a marked span
+		// is found all spans below it have been marked before.
+		for i := len(stack) - 1; i >= 0 && !related[stack[i].rhsIdx]; i-- {
+			related[stack[i].rhsIdx] = true
+		}
+	}
+	return related
+}
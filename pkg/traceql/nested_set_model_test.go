@@ -0,0 +1,117 @@
+package traceql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nestedSetTestSpans returns the spans of the following trace. db spans have the attribute
+// .db = true and x is a span without nested set values.
+//
+//	root (1, 14)
+//	├── a (2, 7)
+//	│   ├── b (3, 4)    db
+//	│   └── c (5, 6)
+//	└── d (8, 13)
+//	    ├── e (9, 10)   db
+//	    └── f (11, 12)  db
+//	x (0, 0)            db
+func nestedSetTestSpans() []Span {
+	newSpan := func(name string, left, right, parent int32, db bool) Span {
+		attrs := map[Attribute]Static{NewIntrinsic(IntrinsicName): NewStaticString(name)}
+		if db {
+			attrs[NewAttribute("db")] = NewStaticBool(true)
+		}
+		return &mockSpan{
+			id:              []byte(name),
+			attributes:      attrs,
+			nestedSetLeft:   left,
+			nestedSetRight:  right,
+			nestedSetParent: parent,
+		}
+	}
+
+	return []Span{
+		newSpan("root", 1, 14, 0, false),
+		newSpan("a", 2, 7, 1, false),
+		newSpan("b", 3, 4, 2, true),
+		newSpan("c", 5, 6, 2, false),
+		newSpan("d", 8, 13, 1, false),
+		newSpan("e", 9, 10, 8, true),
+		newSpan("f", 11, 12, 8, true),
+		newSpan("x", 0, 0, 0, true),
+	}
+}
+
+func TestStructuralOperators(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		// child
+		{`{ name = "a" } > { }`, []string{"b", "c"}},
+		{`{ name = "a" || name = "d" } > { .db = true }`, []string{"b", "e", "f"}},
+		{`{ name = "b" } > { }`, nil},
+		{`{ name = "a" } !> { }`, []string{"root", "a", "d", "e", "f"}},
+		{`{ } !> { .db = true }`, nil},
+		// parent
+		{`{ name = "b" } < { }`, []string{"a"}},
+		{`{ .db = true } < { }`, []string{"a", "d"}},
+		{`{ name = "root" } < { }`, nil},
+		{`{ .db = true } !< { name =~ "a|d|root" }`, []string{"root"}},
+		// descendant
+		{`{ name = "root" } >> { }`, []string{"a", "b", "c", "d", "e", "f"}},
+		{`{ name = "a" } >> { .db = true }`, []string{"b"}},
+		{`{ name = "a" || name = "b" } >> { name = "a" || name = "b" }`, []string{"b"}},
+		{`{ name = "a" } !>> { .db = true }`, []string{"e", "f"}},
+		// ancestor
+		{`{ name = "b" } << { }`, []string{"root", "a"}},
+		{`{ .db = true } << { }`, []string{"root", "a", "d"}},
+		{`{ name = "a" || name = "b" } << { name = "a" || name = "b" }`, []string{"a"}},
+		{`{ .db = true } !<< { name = "a" || name = "c" || name = "d" }`, []string{"c"}},
+		// sibling
+		{`{ name = "b" } ~ { }`, []string{"c"}},
+		{`{ .db = true } ~ { .db = true }`, []string{"e", "f"}},
+		{`{ name = "root" } ~ { }`, nil},
+		{`{ name = "b" } !~ { }`, []string{"root", "a", "b", "d", "e", "f"}},
+		// combined
+		{`{ name = "root" } > { } > { .db = true }`, []string{"b", "e", "f"}},
+		{`({ name = "d" } > { .db = true }) && { name = "x" }`, []string{"e", "f", "x"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			ast, err := Parse(tc.query)
+			require.NoError(t, err)
+			require.NoError(t, ast.validate())
+
+			input := []*Spanset{{Spans: nestedSetTestSpans()}}
+			actual, err := ast.Pipeline.evaluate(input)
+			require.NoError(t, err)
+
+			var names []string
+			for _, ss := range actual {
+				for _, s := range ss.Spans {
+					names = append(names, string(s.ID()))
+				}
+			}
+			require.ElementsMatch(t, tc.expected, names)
+		})
+	}
+}
+
+func TestStructuralOperatorsExtractConditions(t *testing.T) {
+	req, err := ExtractFetchSpansRequest(`{ .a = 1 } > { .b = 2 } >> { .c = 3 }`)
+	require.NoError(t, err)
+
+	require.False(t, req.AllConditions)
+	require.Equal(t, []Condition{
+		{NewAttribute("a"), OpEqual, Operands{NewStaticInt(1)}},
+		{NewAttribute("b"), OpEqual, Operands{NewStaticInt(2)}},
+		{NewIntrinsic(IntrinsicNestedSetLeft), OpNone, nil},
+		{NewIntrinsic(IntrinsicNestedSetRight), OpNone, nil},
+		{NewIntrinsic(IntrinsicNestedSetParent), OpNone, nil},
+		{NewAttribute("c"), OpEqual, Operands{NewStaticInt(3)}},
+	}, req.Conditions)
+}
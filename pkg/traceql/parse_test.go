@@ -78,7 +78,7 @@ func TestPipelineOperatorPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, newRootExpr(newPipeline(tc.expected)), actual)
 		})
 	}
 }
@@ -134,7 +134,7 @@ func TestPipelineSpansetOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, newRootExpr(newPipeline(tc.expected)), actual)
 		})
 	}
 }
@@ -216,7 +216,7 @@ func TestPipelineScalarOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, newRootExpr(newPipeline(tc.expected)), actual)
 		})
 	}
 }
@@ -256,7 +256,7 @@ func TestPipelines(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{tc.expected}, actual)
+			require.Equal(t, newRootExpr(tc.expected), actual)
 		})
 	}
 }
@@ -294,7 +294,7 @@ func TestGroupCoalesceOperation(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{tc.expected}, actual)
+			require.Equal(t, newRootExpr(tc.expected), actual)
 		})
 	}
 }
@@ -330,7 +330,7 @@ func TestSelectOperation(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{tc.expected}, actual)
+			require.Equal(t, newRootExpr(tc.expected), actual)
 		})
 	}
 }
@@ -399,7 +399,7 @@ func TestSpansetExpressionPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, newRootExpr(newPipeline(tc.expected)), actual)
 		})
 	}
 }
@@ -414,6 +414,13 @@ func TestSpansetExpressionOperators(t *testing.T) {
 		{in: "{ true } >> { false }", expected: newSpansetOperation(OpSpansetDescendant, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } || { false }", expected: newSpansetOperation(OpSpansetUnion, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } ~ { false }", expected: newSpansetOperation(OpSpansetSibling, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } < { false }", expected: newSpansetOperation(OpSpansetParent, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } << { false }", expected: newSpansetOperation(OpSpansetAncestor, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !> { false }", expected: newSpansetOperation(OpSpansetNotChild, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !< { false }", expected: newSpansetOperation(OpSpansetNotParent, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !>> { false }", expected: newSpansetOperation(OpSpansetNotDescendant, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !<< { false }", expected: newSpansetOperation(OpSpansetNotAncestor, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } !~ { false }", expected: newSpansetOperation(OpSpansetNotSibling, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		// this test was added to highlight the one shift/reduce conflict in the grammar. this could also be parsed as two spanset pipelines &&ed together.
 		{in: "({ true }) && ({ false })", expected: newSpansetOperation(OpSpansetAnd, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 	}
@@ -423,7 +430,7 @@ func TestSpansetExpressionOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, newRootExpr(newPipeline(tc.expected)), actual)
 		})
 	}
 }
@@ -480,7 +487,7 @@ func TestScalarExpressionPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, newRootExpr(newPipeline(tc.expected)), actual)
 		})
 	}
 }
@@ -502,7 +509,7 @@ func TestScalarExpressionOperators(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(tc.expected)}, actual)
+			require.Equal(t, newRootExpr(newPipeline(tc.expected)), actual)
 		})
 	}
 }
@@ -616,6 +623,12 @@ func TestSpansetFilterOperatorPrecedence(t *testing.T) {
 				newUnaryOperation(OpNot, NewAttribute("a")),
 				NewAttribute("b")),
 		},
+		{
+			in: "{ len(.a) > 1 }",
+			expected: newBinaryOperation(OpGreater,
+				newUnaryOperation(OpLen, NewAttribute("a")),
+				NewStaticInt(1)),
+		},
 		{
 			in: "{ !(.a = .b) }",
 			expected: newUnaryOperation(OpNot, newBinaryOperation(OpEqual,
@@ -641,7 +654,7 @@ func TestSpansetFilterOperatorPrecedence(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(tc.expected))), actual)
 		})
 	}
 }
@@ -683,7 +696,7 @@ func TestSpansetFilterStatics(t *testing.T) {
 			actual, err := Parse(tc.in)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(tc.expected))), actual)
 		})
 	}
 }
@@ -723,7 +736,7 @@ func TestSpansetFilterOperators(t *testing.T) {
 	test := func(q string, expected FieldExpression) {
 		actual, err := Parse(q)
 		require.NoError(t, err, q)
-		require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(expected))}, actual, q)
+		require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(expected))), actual, q)
 	}
 
 	for _, tc := range tests {
@@ -780,6 +793,12 @@ func TestAttributes(t *testing.T) {
 		{in: "parent.foo.bar.baz", expected: NewScopedAttribute(AttributeScopeNone, true, "foo.bar.baz")},
 		{in: "resource.foo.bar.baz", expected: NewScopedAttribute(AttributeScopeResource, false, "foo.bar.baz")},
 		{in: "span.foo.bar", expected: NewScopedAttribute(AttributeScopeSpan, false, "foo.bar")},
+		{in: "event.foo.bar", expected: NewScopedAttribute(AttributeScopeEvent, false, "foo.bar")},
+		{in: "event.name", expected: NewScopedAttribute(AttributeScopeEvent, false, "name")},
+		{in: "link.foo.bar", expected: NewScopedAttribute(AttributeScopeLink, false, "foo.bar")},
+		{in: "event:name", expected: NewIntrinsic(IntrinsicEventName)},
+		{in: "link:traceID", expected: NewIntrinsic(IntrinsicLinkTraceID)},
+		{in: "link:spanID", expected: NewIntrinsic(IntrinsicLinkSpanID)},
 		{in: "parent.resource.foo", expected: NewScopedAttribute(AttributeScopeResource, true, "foo")},
 		{in: "parent.span.foo", expected: NewScopedAttribute(AttributeScopeSpan, true, "foo")},
 		{in: "parent.resource.foo.bar.baz", expected: NewScopedAttribute(AttributeScopeResource, true, "foo.bar.baz")},
@@ -792,25 +811,25 @@ func TestAttributes(t *testing.T) {
 			actual, err := Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(tc.expected))), actual)
 
 			s = "{" + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(tc.expected))), actual)
 
 			s = "{ (" + tc.in + ") }"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(tc.expected))}, actual)
+			require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(tc.expected))), actual)
 
 			s = "{ " + tc.in + " + " + tc.in + " }"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(newBinaryOperation(OpAdd, tc.expected, tc.expected)))}, actual)
+			require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(newBinaryOperation(OpAdd, tc.expected, tc.expected)))), actual)
 		})
 	}
 }
@@ -838,65 +857,65 @@ func TestIntrinsics(t *testing.T) {
 			actual, err := Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    false,
 					Name:      tc.in,
 					Intrinsic: tc.expected,
-				}))}, actual)
+				}))), actual)
 
 			// as attribute e.g .duration
 			s = "{ ." + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    false,
 					Name:      tc.in,
 					Intrinsic: IntrinsicNone,
-				}))}, actual)
+				}))), actual)
 
 			// as span scoped attribute e.g span.duration
 			s = "{ span." + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeSpan,
 					Parent:    false,
 					Name:      tc.in,
 					Intrinsic: IntrinsicNone,
-				}))}, actual)
+				}))), actual)
 
 			// as resource scoped attribute e.g resource.duration
 			s = "{ resource." + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeResource,
 					Parent:    false,
 					Name:      tc.in,
 					Intrinsic: IntrinsicNone,
-				}))}, actual)
+				}))), actual)
 
 			// as parent scoped intrinsic e.g parent.duration
 			s = "{ parent." + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    true,
 					Name:      tc.in,
 					Intrinsic: tc.expected,
-				}))}, actual)
+				}))), actual)
 
 			// as nested parent scoped intrinsic e.g. parent.duration.foo
 			// this becomes lookup on attribute named "duration.foo"
@@ -904,39 +923,39 @@ func TestIntrinsics(t *testing.T) {
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeNone,
 					Parent:    true,
 					Name:      tc.in + ".foo",
 					Intrinsic: IntrinsicNone,
-				}))}, actual)
+				}))), actual)
 
 			// as parent resource scoped attribute e.g. parent.resource.duration
 			s = "{ parent.resource." + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeResource,
 					Parent:    true,
 					Name:      tc.in,
 					Intrinsic: IntrinsicNone,
-				}))}, actual)
+				}))), actual)
 
 			// as parent span scoped attribute e.g. praent.span.duration
 			s = "{ parent.span." + tc.in + "}"
 			actual, err = Parse(s)
 
 			require.NoError(t, err)
-			require.Equal(t, &RootExpr{newPipeline(
+			require.Equal(t, newRootExpr(newPipeline(
 				newSpansetFilter(Attribute{
 					Scope:     AttributeScopeSpan,
 					Parent:    true,
 					Name:      tc.in,
 					Intrinsic: IntrinsicNone,
-				}))}, actual)
+				}))), actual)
 		})
 	}
 }
@@ -951,6 +970,10 @@ func TestParseIdentifier(t *testing.T) {
 		".foo.bar":         NewAttribute("foo.bar"),
 		"resource.foo.bar": NewScopedAttribute(AttributeScopeResource, false, "foo.bar"),
 		"span.foo.bar":     NewScopedAttribute(AttributeScopeSpan, false, "foo.bar"),
+		"event.foo.bar":    NewScopedAttribute(AttributeScopeEvent, false, "foo.bar"),
+		"link.foo.bar":     NewScopedAttribute(AttributeScopeLink, false, "foo.bar"),
+		"event:name":       NewIntrinsic(IntrinsicEventName),
+		"link:traceID":     NewIntrinsic(IntrinsicLinkTraceID),
 	}
 	for i, expected := range testCases {
 		actual, err := ParseIdentifier(i)
@@ -973,7 +996,52 @@ func TestEmptyQuery(t *testing.T) {
 		t.Run(tc.in, func(t *testing.T) {
 			actual, err := Parse(tc.in)
 			require.NoError(t, err, tc.in)
-			require.Equal(t, &RootExpr{newPipeline(newSpansetFilter(NewStaticBool(true)))}, actual, tc.in)
+			require.Equal(t, newRootExpr(newPipeline(newSpansetFilter(NewStaticBool(true)))), actual, tc.in)
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected *RootExpr
+	}{
+		{
+			in: `{ } | rate()`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateRate, nil),
+			),
+		},
+		{
+			in: `{ } | count_over_time() by(name, span.foo)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateCountOverTime, []Attribute{
+					NewIntrinsic(IntrinsicName),
+					NewScopedAttribute(AttributeScopeSpan, false, "foo"),
+				}),
+			),
+		},
+		{
+			in: `{ } | quantile_over_time(duration, 0, 0.9, 1) by(resource.service.name)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateQuantileOverTime(
+					NewIntrinsic(IntrinsicDuration),
+					[]float64{0, 0.9, 1},
+					[]Attribute{NewScopedAttribute(AttributeScopeResource, false, "service.name")},
+				),
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
 		})
 	}
 }
@@ -76,6 +76,23 @@ func (f *FetchSpansRequest) appendCondition(c ...Condition) {
 	f.Conditions = append(f.Conditions, c...)
 }
 
+func (f *FetchSpansRequest) hasCondition(c Condition) bool {
+	for _, existing := range f.Conditions {
+		if existing.Attribute == c.Attribute && existing.Op == c.Op && len(existing.Operands) == 0 && len(c.Operands) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func nestedSetConditions() []Condition {
+	return []Condition{
+		{NewIntrinsic(IntrinsicNestedSetLeft), OpNone, nil},
+		{NewIntrinsic(IntrinsicNestedSetRight), OpNone, nil},
+		{NewIntrinsic(IntrinsicNestedSetParent), OpNone, nil},
+	}
+}
+
 type Span interface {
 	// these are the actual fields used by the engine to evaluate queries
 	// if a Filter parameter is passed the spans returned will only have this field populated
@@ -84,6 +101,12 @@ type Span interface {
 	ID() []byte
 	StartTimeUnixNanos() uint64
 	DurationNanos() uint64
+
+	// nested set values used to evaluate structural operators. see nested_set_model.go. these
+	// are only populated if requested from the fetch layer and are 0 otherwise.
+	NestedSetLeft() int32
+	NestedSetRight() int32
+	NestedSetParent() int32
 }
 
 // should we just make matched a field on the spanset instead of a special attribute?
@@ -107,6 +107,7 @@ func NewMetricsResults() *MetricsResults {
 }
 
 func (m *MetricsResults) Record(series traceql.Static, durationNanos uint64, err bool) {
+	series = series.MapKey()
 	s := m.Series[series]
 	if s == nil {
 		s = &LatencyHistogram{}
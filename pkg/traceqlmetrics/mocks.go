@@ -7,9 +7,11 @@ import (
 )
 
 type mockSpan struct {
-	start    uint64
-	duration uint64
-	attrs    map[traceql.Attribute]traceql.Static
+	start                         uint64
+	duration                      uint64
+	attrs                         map[traceql.Attribute]traceql.Static
+	nestedSetLeft, nestedSetRight int32
+	nestedSetParent               int32
 }
 
 var _ traceql.Span = (*mockSpan)(nil)
@@ -41,6 +43,13 @@ func (m *mockSpan) WithStart(t uint64) *mockSpan {
 	return m
 }
 
+func (m *mockSpan) WithNestedSet(left, right, parent int32) *mockSpan {
+	m.nestedSetLeft = left
+	m.nestedSetRight = right
+	m.nestedSetParent = parent
+	return m
+}
+
 func (m *mockSpan) WithErr() *mockSpan {
 	m.attrs[traceql.NewIntrinsic(traceql.IntrinsicStatus)] = traceql.NewStaticStatus(traceql.StatusError)
 	return m
@@ -50,6 +59,9 @@ func (m *mockSpan) Attributes() map[traceql.Attribute]traceql.Static { return m.
 func (m *mockSpan) ID() []byte                                       { return nil }
 func (m *mockSpan) StartTimeUnixNanos() uint64                       { return m.start }
 func (m *mockSpan) DurationNanos() uint64                            { return m.duration }
+func (m *mockSpan) NestedSetLeft() int32                             { return m.nestedSetLeft }
+func (m *mockSpan) NestedSetRight() int32                            { return m.nestedSetRight }
+func (m *mockSpan) NestedSetParent() int32                           { return m.nestedSetParent }
 
 type mockFetcher struct {
 	filter   traceql.SecondPassFn
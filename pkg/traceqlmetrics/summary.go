@@ -0,0 +1,285 @@
+package traceqlmetrics
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const summaryPathSeparator = " > "
+
+// TraceSummary is the call tree of multiple traces merged into one. Spans are aggregated into
+// nodes keyed by the path of service and span names from the root span of their trace.
+type TraceSummary struct {
+	Estimated  bool
+	TraceCount int
+	Nodes      map[string]*TraceSummaryNode
+}
+
+type TraceSummaryNode struct {
+	ServiceName string
+	Name        string
+	Latency     LatencyHistogram
+	Errors      int
+}
+
+func NewTraceSummary() *TraceSummary {
+	return &TraceSummary{
+		Nodes: map[string]*TraceSummaryNode{},
+	}
+}
+
+var (
+	summaryServiceName = traceql.NewScopedAttribute(traceql.AttributeScopeResource, false, "service.name")
+	summaryName        = traceql.NewIntrinsic(traceql.IntrinsicName)
+	summaryDuration    = traceql.NewIntrinsic(traceql.IntrinsicDuration)
+	summaryStatus      = traceql.NewIntrinsic(traceql.IntrinsicStatus)
+	summaryStatusErr   = traceql.NewStaticStatus(traceql.StatusError)
+)
+
+// RecordTrace adds all spans of a trace to the call tree. The spans are placed in the tree using
+// their nested set values. Spans without nested set values or whose parent is missing are
+// treated as roots.
+func (t *TraceSummary) RecordTrace(spans []traceql.Span) {
+	byLeft := make(map[int32]traceql.Span, len(spans))
+	for _, s := range spans {
+		if s.NestedSetLeft() > 0 {
+			byLeft[s.NestedSetLeft()] = s
+		}
+	}
+
+	paths := make(map[int32]string, len(spans))
+	var pathOf func(s traceql.Span) string
+	pathOf = func(s traceql.Span) string {
+		if p, ok := paths[s.NestedSetLeft()]; ok && s.NestedSetLeft() > 0 {
+			return p
+		}
+
+		p := summaryKey(s)
+		// the parent is always left of the span which guarantees termination for broken nested sets
+		if parent, ok := byLeft[s.NestedSetParent()]; ok && s.NestedSetParent() > 0 && s.NestedSetParent() < s.NestedSetLeft() {
+			p = pathOf(parent) + summaryPathSeparator + p
+		}
+
+		if s.NestedSetLeft() > 0 {
+			paths[s.NestedSetLeft()] = p
+		}
+		return p
+	}
+
+	for _, s := range spans {
+		path := pathOf(s)
+
+		n := t.Nodes[path]
+		if n == nil {
+			attrs := s.Attributes()
+			n = &TraceSummaryNode{
+				ServiceName: attrs[summaryServiceName].S,
+				Name:        attrs[summaryName].S,
+			}
+			t.Nodes[path] = n
+		}
+
+		n.Latency.Record(s.DurationNanos())
+		if s.Attributes()[summaryStatus] == summaryStatusErr {
+			n.Errors++
+		}
+	}
+
+	t.TraceCount++
+}
+
+func summaryKey(s traceql.Span) string {
+	attrs := s.Attributes()
+	return attrs[summaryServiceName].S + ":" + attrs[summaryName].S
+}
+
+func (t *TraceSummary) Combine(other *TraceSummary) {
+	t.TraceCount += other.TraceCount
+	if other.Estimated {
+		t.Estimated = true
+	}
+
+	for path, o := range other.Nodes {
+		n := t.Nodes[path]
+		if n == nil {
+			n = &TraceSummaryNode{
+				ServiceName: o.ServiceName,
+				Name:        o.Name,
+			}
+			t.Nodes[path] = n
+		}
+		n.Latency.Combine(o.Latency)
+		n.Errors += o.Errors
+	}
+}
+
+// ToProto returns the nodes of the call tree sorted by path.
+func (t *TraceSummary) ToProto() *tempopb.TraceSummaryResponse {
+	resp := &tempopb.TraceSummaryResponse{
+		TraceCount: uint32(t.TraceCount),
+		Nodes:      make([]*tempopb.TraceSummaryNode, 0, len(t.Nodes)),
+	}
+
+	for path, n := range t.Nodes {
+		count := n.Latency.Count()
+
+		var h []*tempopb.RawHistogram
+		for bucket, c := range n.Latency.Buckets() {
+			if c != 0 {
+				h = append(h, &tempopb.RawHistogram{
+					Bucket: uint64(bucket),
+					Count:  uint64(c),
+				})
+			}
+		}
+
+		node := &tempopb.TraceSummaryNode{
+			Path:             path,
+			ServiceName:      n.ServiceName,
+			Name:             n.Name,
+			Count:            uint64(count),
+			ErrorCount:       uint64(n.Errors),
+			P50:              n.Latency.Percentile(0.5),
+			P90:              n.Latency.Percentile(0.9),
+			P95:              n.Latency.Percentile(0.95),
+			P99:              n.Latency.Percentile(0.99),
+			LatencyHistogram: h,
+		}
+		if count > 0 {
+			node.ErrorRate = float64(n.Errors) / float64(count)
+		}
+		resp.Nodes = append(resp.Nodes, node)
+	}
+
+	sort.Slice(resp.Nodes, func(i, j int) bool {
+		return resp.Nodes[i].Path < resp.Nodes[j].Path
+	})
+
+	return resp
+}
+
+// TraceSummaryFromProto rebuilds the call tree from the latency histograms of the response.
+func TraceSummaryFromProto(resp *tempopb.TraceSummaryResponse) *TraceSummary {
+	t := NewTraceSummary()
+	t.TraceCount = int(resp.TraceCount)
+
+	for _, n := range resp.Nodes {
+		var b [64]int
+		for _, l := range n.LatencyHistogram {
+			if l.Bucket < uint64(len(b)) {
+				b[l.Bucket] += int(l.Count)
+			}
+		}
+
+		t.Nodes[n.Path] = &TraceSummaryNode{
+			ServiceName: n.ServiceName,
+			Name:        n.Name,
+			Latency:     *New(b),
+			Errors:      int(n.ErrorCount),
+		}
+	}
+
+	return t
+}
+
+// GetTraceSummary merges the traces matching the query into a call tree. Matching a query requires
+// all spans of a trace, so every span of the fetched traces is read and not only the ones matching
+// the conditions of the query.
+func GetTraceSummary(ctx context.Context, query string, traceLimit int, start, end uint64, fetcher traceql.SpansetFetcher) (*TraceSummary, error) {
+	expr, err := traceql.Parse(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing query")
+	}
+	if expr.MetricsPipeline != nil {
+		return nil, errors.New("metrics queries are not supported by trace summaries")
+	}
+
+	eval, req, err := traceql.NewEngine().Compile(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "compiling query")
+	}
+
+	// Only select the attributes needed to evaluate the query without filtering spans. The
+	// nested set values are required to build the call tree and make the fetch layer return
+	// all spans of a trace.
+	conditions := []traceql.Condition{
+		{Attribute: summaryServiceName},
+		{Attribute: summaryName},
+		{Attribute: summaryDuration},
+		{Attribute: summaryStatus},
+		{Attribute: traceql.NewIntrinsic(traceql.IntrinsicNestedSetLeft)},
+		{Attribute: traceql.NewIntrinsic(traceql.IntrinsicNestedSetRight)},
+		{Attribute: traceql.NewIntrinsic(traceql.IntrinsicNestedSetParent)},
+	}
+	for _, c := range req.Conditions {
+		if !containsAttribute(conditions, c.Attribute) {
+			conditions = append(conditions, traceql.Condition{Attribute: c.Attribute})
+		}
+	}
+
+	req.Conditions = conditions
+	req.AllConditions = false
+	req.StartTimeUnixNanos = start
+	req.EndTimeUnixNanos = end
+
+	summary := NewTraceSummary()
+
+	// Evaluate and record the traces in the second pass callback and return nil to
+	// discard them. This lets the fetch layer repool the spans.
+	req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) {
+		spans := s.Spans
+
+		out, err := eval([]*traceql.Spanset{s})
+		if err != nil {
+			return nil, err
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+
+		summary.RecordTrace(spans)
+		if traceLimit > 0 && summary.TraceCount >= traceLimit {
+			return nil, io.EOF
+		}
+
+		return nil, nil
+	}
+
+	res, err := fetcher.Fetch(ctx, *req)
+	if err == util.ErrUnsupported {
+		return summary, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Results.Close()
+
+	for {
+		ss, err := res.Results.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ss == nil {
+			break
+		}
+	}
+
+	summary.Estimated = traceLimit > 0 && summary.TraceCount >= traceLimit
+	return summary, nil
+}
+
+func containsAttribute(conditions []traceql.Condition, a traceql.Attribute) bool {
+	for _, c := range conditions {
+		if c.Attribute == a {
+			return true
+		}
+	}
+	return false
+}
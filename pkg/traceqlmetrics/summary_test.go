@@ -0,0 +1,126 @@
+package traceqlmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+// summaryTestTrace returns the spans of the following trace
+//
+//	frontend:GET (1, 8)
+//	├── backend:query (2, 5)
+//	│   └── db:SELECT (3, 4)
+//	└── backend:query (6, 7)   error
+func summaryTestTrace(durationFactor uint64) []traceql.Span {
+	span := func(service, name string, duration uint64, left, right, parent int32) *mockSpan {
+		return newMockSpan().
+			WithAttributes("resource.service.name", service, "name", name).
+			WithDuration(duration*durationFactor).
+			WithNestedSet(left, right, parent)
+	}
+
+	return []traceql.Span{
+		span("frontend", "GET", 1000, 1, 8, 0),
+		span("backend", "query", 100, 2, 5, 1),
+		span("db", "SELECT", 10, 3, 4, 2),
+		span("backend", "query", 200, 6, 7, 1).WithErr(),
+	}
+}
+
+func TestTraceSummaryRecordTrace(t *testing.T) {
+	s := NewTraceSummary()
+	s.RecordTrace(summaryTestTrace(1))
+
+	// a span without nested set values is a root
+	s.RecordTrace([]traceql.Span{
+		newMockSpan().WithAttributes("resource.service.name", "frontend", "name", "GET").WithDuration(1000),
+	})
+
+	require.Equal(t, 2, s.TraceCount)
+	require.Len(t, s.Nodes, 3)
+
+	root := s.Nodes["frontend:GET"]
+	require.NotNil(t, root)
+	require.Equal(t, "frontend", root.ServiceName)
+	require.Equal(t, "GET", root.Name)
+	require.Equal(t, 2, root.Latency.Count())
+
+	query := s.Nodes["frontend:GET > backend:query"]
+	require.NotNil(t, query)
+	require.Equal(t, 2, query.Latency.Count())
+	require.Equal(t, 1, query.Errors)
+
+	db := s.Nodes["frontend:GET > backend:query > db:SELECT"]
+	require.NotNil(t, db)
+	require.Equal(t, 1, db.Latency.Count())
+}
+
+func TestTraceSummaryCombineAndProto(t *testing.T) {
+	a := NewTraceSummary()
+	a.RecordTrace(summaryTestTrace(1))
+
+	b := NewTraceSummary()
+	b.RecordTrace(summaryTestTrace(2))
+	b.RecordTrace(summaryTestTrace(4))
+
+	// round trip b like the frontend does with the results of the sharded jobs
+	a.Combine(TraceSummaryFromProto(b.ToProto()))
+
+	resp := a.ToProto()
+	require.Equal(t, uint32(3), resp.TraceCount)
+
+	var paths []string
+	for _, n := range resp.Nodes {
+		paths = append(paths, n.Path)
+	}
+	require.Equal(t, []string{
+		"frontend:GET",
+		"frontend:GET > backend:query",
+		"frontend:GET > backend:query > db:SELECT",
+	}, paths)
+
+	query := resp.Nodes[1]
+	require.Equal(t, "backend", query.ServiceName)
+	require.Equal(t, "query", query.Name)
+	require.Equal(t, uint64(6), query.Count)
+	require.Equal(t, uint64(3), query.ErrorCount)
+	require.Equal(t, 0.5, query.ErrorRate)
+	require.Equal(t, uint64(256), query.P50)  // durations 100, 200, 200, 400, 400, 800
+	require.Equal(t, uint64(1024), query.P99) // 800 is in the bucket 512-1024
+}
+
+func TestGetTraceSummary(t *testing.T) {
+	testCases := []struct {
+		query      string
+		traceCount int
+	}{
+		{`{}`, 1},
+		{`{ resource.service.name = "db" }`, 1},
+		{`{ name = "query" } > { name = "SELECT" }`, 1},
+		{`{ resource.service.name = "cache" }`, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			m := &mockFetcher{
+				Spansets: []*traceql.Spanset{{Spans: summaryTestTrace(1)}},
+			}
+
+			res, err := GetTraceSummary(context.TODO(), tc.query, 0, 0, 0, m)
+			require.NoError(t, err)
+			require.Equal(t, tc.traceCount, res.TraceCount)
+
+			if tc.traceCount > 0 {
+				// all spans of the trace are aggregated and not only the matching ones
+				require.Len(t, res.Nodes, 3)
+			}
+		})
+	}
+
+	_, err := GetTraceSummary(context.TODO(), `{} | rate()`, 0, 0, 0, &mockFetcher{})
+	require.Error(t, err)
+}
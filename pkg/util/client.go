@@ -17,7 +17,8 @@ import (
 const (
 	orgIDHeader = "X-Scope-OrgID"
 
-	QueryTraceEndpoint = "/api/traces"
+	QueryTraceEndpoint     = "/api/traces"
+	QueryTraceDiffEndpoint = "/api/traces/diff"
 
 	acceptHeader        = "Accept"
 	applicationProtobuf = "application/protobuf"
@@ -162,6 +163,34 @@ func (c *Client) QueryTrace(id string) (*tempopb.Trace, error) {
 	return m, nil
 }
 
+// QueryTraceCriticalPath returns the critical path of the trace with the given id
+func (c *Client) QueryTraceCriticalPath(id string) (*tempopb.TraceCriticalPathResponse, error) {
+	m := &tempopb.TraceCriticalPathResponse{}
+	resp, err := c.getFor(c.BaseURL+QueryTraceEndpoint+"/"+id+"/criticalpath", m)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, ErrTraceNotFound
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// QueryTraceDiff compares the traces with the ids a and b
+func (c *Client) QueryTraceDiff(a, b string) (*tempopb.TraceDiffResponse, error) {
+	m := &tempopb.TraceDiffResponse{}
+	resp, err := c.getFor(c.BaseURL+QueryTraceDiffEndpoint+"?"+url.Values{"a": {a}, "b": {b}}.Encode(), m)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, ErrTraceNotFound
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
 func (c *Client) SearchTraceQL(query string) (*tempopb.SearchResponse, error) {
 	m := &tempopb.SearchResponse{}
 	_, err := c.getFor(c.buildQueryURL("q", query, 0, 0), m)
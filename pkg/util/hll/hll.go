@@ -0,0 +1,86 @@
+package hll
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	precision = 10
+	registers = 1 << precision
+)
+
+// Sketch is a HyperLogLog sketch estimating the number of distinct hashes inserted into it. It uses
+// 2^10 registers, the standard error of the estimate is about 3%.
+type Sketch struct {
+	registers [registers]uint8
+}
+
+func New() *Sketch {
+	return &Sketch{}
+}
+
+// Insert adds a hash to the sketch. Hashes must be uniformly distributed, e.g. the output of xxhash.
+func (s *Sketch) Insert(hash uint64) {
+	idx := hash >> (64 - precision)
+	// the rank is the position of the first set bit in the remaining bits. the sentinel bit bounds it
+	// to the number of remaining bits + 1.
+	w := hash<<precision | 1<<(precision-1)
+	rank := uint8(bits.LeadingZeros64(w) + 1)
+	if rank > s.registers[idx] {
+		s.registers[idx] = rank
+	}
+}
+
+// Merge adds the hashes inserted into o to the sketch.
+func (s *Sketch) Merge(o *Sketch) {
+	for i, r := range o.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+}
+
+// Estimate returns the estimated number of distinct hashes inserted into the sketch.
+func (s *Sketch) Estimate() uint64 {
+	var (
+		sum   float64
+		zeros int
+	)
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	m := float64(registers)
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+
+	// use linear counting for small cardinalities, it's more accurate while there are empty registers
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// Marshal returns the registers of the sketch.
+func (s *Sketch) Marshal() []byte {
+	b := make([]byte, registers)
+	copy(b, s.registers[:])
+	return b
+}
+
+// Unmarshal restores a sketch from the output of Marshal.
+func Unmarshal(b []byte) (*Sketch, error) {
+	if len(b) != registers {
+		return nil, fmt.Errorf("invalid sketch length %d, expected %d", len(b), registers)
+	}
+
+	s := &Sketch{}
+	copy(s.registers[:], b)
+	return s, nil
+}
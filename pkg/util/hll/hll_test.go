@@ -0,0 +1,54 @@
+package hll
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimate(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 100, 1000, 10_000, 100_000, 1_000_000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			s := New()
+			for i := 0; i < n; i++ {
+				s.Insert(xxhash.Sum64String(strconv.Itoa(i)))
+				// duplicates don't change the estimate
+				s.Insert(xxhash.Sum64String(strconv.Itoa(i)))
+			}
+
+			// allow about 3 standard errors
+			assert.InDelta(t, n, s.Estimate(), float64(n)*0.1+1)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := New(), New()
+	for i := 0; i < 10_000; i++ {
+		a.Insert(xxhash.Sum64String(strconv.Itoa(i)))
+	}
+	for i := 5_000; i < 20_000; i++ {
+		b.Insert(xxhash.Sum64String(strconv.Itoa(i)))
+	}
+
+	a.Merge(b)
+	assert.InDelta(t, 20_000, a.Estimate(), 20_000*0.1)
+}
+
+func TestMarshal(t *testing.T) {
+	s := New()
+	for i := 0; i < 1000; i++ {
+		s.Insert(xxhash.Sum64String(strconv.Itoa(i)))
+	}
+
+	actual, err := Unmarshal(s.Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, s, actual)
+	assert.Equal(t, s.Estimate(), actual.Estimate())
+
+	_, err = Unmarshal([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
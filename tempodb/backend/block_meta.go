@@ -2,9 +2,13 @@ package backend
 
 import (
 	"bytes"
+	"fmt"
+	"hash/fnv"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/tempopb"
 )
 
 type CompactedBlockMeta struct {
@@ -30,6 +34,126 @@ type BlockMeta struct {
 	DataEncoding    string    `json:"dataEncoding"`    // DataEncoding is a string provided externally, but tracked by tempodb that indicates the way the bytes are encoded
 	BloomShardCount uint16    `json:"bloomShards"`     // Number of bloom filter shards
 	FooterSize      uint32    `json:"footerSize"`      // Size of data file footer (parquet)
+
+	// DedicatedColumns configuration for attributes (used by vParquet3)
+	DedicatedColumns DedicatedColumns `json:"dedicatedColumns,omitempty"`
+}
+
+// DedicatedColumnType is the type of the values in the dedicated attribute column. Only 'string' is supported.
+type DedicatedColumnType string
+
+// DedicatedColumnScope is the scope of the attribute that is stored in a dedicated column. Possible values are 'resource' and 'span'.
+type DedicatedColumnScope string
+
+const (
+	DedicatedColumnTypeString DedicatedColumnType = "string"
+
+	DedicatedColumnScopeResource DedicatedColumnScope = "resource"
+	DedicatedColumnScopeSpan     DedicatedColumnScope = "span"
+
+	DefaultDedicatedColumnType  = DedicatedColumnTypeString
+	DefaultDedicatedColumnScope = DedicatedColumnScopeSpan
+)
+
+// DedicatedColumn contains the configuration for a single attribute with the given name that should
+// be stored in a dedicated column instead of the generic attribute column.
+type DedicatedColumn struct {
+	// The Scope of the attribute
+	Scope DedicatedColumnScope `yaml:"scope" json:"scope"`
+	// The Name of the attribute stored in the dedicated column
+	Name string `yaml:"name" json:"name"`
+	// The Type of attribute value
+	Type DedicatedColumnType `yaml:"type" json:"type"`
+}
+
+// UnmarshalYAML applies the default scope and type to columns that don't specify them.
+func (dc *DedicatedColumn) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain DedicatedColumn
+	*dc = DedicatedColumn{
+		Scope: DefaultDedicatedColumnScope,
+		Type:  DefaultDedicatedColumnType,
+	}
+	return unmarshal((*plain)(dc))
+}
+
+// DedicatedColumns represents a set of configured dedicated columns.
+type DedicatedColumns []DedicatedColumn
+
+// Validate returns an error if any of the columns has an empty name, an unknown scope or type, or if
+// the same attribute is configured more than once.
+func (dcs DedicatedColumns) Validate() error {
+	seen := make(map[DedicatedColumn]struct{}, len(dcs))
+	for _, dc := range dcs {
+		if dc.Name == "" {
+			return fmt.Errorf("dedicated column name must not be empty")
+		}
+		if dc.Scope != DedicatedColumnScopeResource && dc.Scope != DedicatedColumnScopeSpan {
+			return fmt.Errorf("dedicated column %s has invalid scope %q, must be %q or %q", dc.Name, dc.Scope, DedicatedColumnScopeResource, DedicatedColumnScopeSpan)
+		}
+		if dc.Type != DedicatedColumnTypeString {
+			return fmt.Errorf("dedicated column %s has invalid type %q, must be %q", dc.Name, dc.Type, DedicatedColumnTypeString)
+		}
+
+		key := DedicatedColumn{Scope: dc.Scope, Name: dc.Name}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("dedicated column %s with scope %s is configured more than once", dc.Name, dc.Scope)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+// Hash returns a hash over all columns and their order. Blocks with differing hashes store
+// attributes in different columns. An empty set of columns hashes to 0.
+func (dcs DedicatedColumns) Hash() uint64 {
+	if len(dcs) == 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	for _, dc := range dcs {
+		_, _ = h.Write([]byte(dc.Scope))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(dc.Name))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(dc.Type))
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// ToTempopb returns the columns as sent in the requests searching a block.
+func (dcs DedicatedColumns) ToTempopb() []*tempopb.DedicatedColumn {
+	if len(dcs) == 0 {
+		return nil
+	}
+
+	cols := make([]*tempopb.DedicatedColumn, 0, len(dcs))
+	for _, dc := range dcs {
+		cols = append(cols, &tempopb.DedicatedColumn{
+			Scope: string(dc.Scope),
+			Name:  dc.Name,
+			Type:  string(dc.Type),
+		})
+	}
+	return cols
+}
+
+// DedicatedColumnsFromTempopb returns the columns sent in a request searching a block.
+func DedicatedColumnsFromTempopb(cols []*tempopb.DedicatedColumn) DedicatedColumns {
+	if len(cols) == 0 {
+		return nil
+	}
+
+	dcs := make(DedicatedColumns, 0, len(cols))
+	for _, c := range cols {
+		dcs = append(dcs, DedicatedColumn{
+			Scope: DedicatedColumnScope(c.Scope),
+			Name:  c.Name,
+			Type:  DedicatedColumnType(c.Type),
+		})
+	}
+	return dcs
 }
 
 func NewBlockMeta(tenantID string, blockID uuid.UUID, version string, encoding Encoding, dataEncoding string) *BlockMeta {
@@ -73,3 +197,8 @@ func (b *BlockMeta) ObjectAdded(id []byte, start uint32, end uint32) {
 
 	b.TotalObjects++
 }
+
+// DedicatedColumnsHash returns the hash of the dedicated columns of this block
+func (b *BlockMeta) DedicatedColumnsHash() uint64 {
+	return b.DedicatedColumns.Hash()
+}
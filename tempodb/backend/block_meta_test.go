@@ -7,6 +7,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -119,3 +121,119 @@ func TestBlockMetaParsing(t *testing.T) {
 	err := json.Unmarshal([]byte(inputJSON), &blockMeta)
 	assert.NoError(t, err, "expected to be able to unmarshal from JSON")
 }
+
+func TestBlockMetaDedicatedColumnsParsing(t *testing.T) {
+	inputJSON := `
+{
+    "format": "vParquet3",
+    "blockID": "00000000-0000-0000-0000-000000000000",
+    "tenantID": "single-tenant",
+    "dedicatedColumns": [
+        {"scope": "resource", "name": "namespace", "type": "string"},
+        {"scope": "span", "name": "db.system", "type": "string"}
+    ]
+}
+`
+
+	blockMeta := BlockMeta{}
+	err := json.Unmarshal([]byte(inputJSON), &blockMeta)
+	require.NoError(t, err)
+
+	expected := DedicatedColumns{
+		{Scope: DedicatedColumnScopeResource, Name: "namespace", Type: DedicatedColumnTypeString},
+		{Scope: DedicatedColumnScopeSpan, Name: "db.system", Type: DedicatedColumnTypeString},
+	}
+	assert.Equal(t, expected, blockMeta.DedicatedColumns)
+
+	// blocks without dedicated columns don't write the field
+	b, err := json.Marshal(&BlockMeta{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "dedicatedColumns")
+}
+
+func TestDedicatedColumnsUnmarshalYAMLDefaults(t *testing.T) {
+	input := `
+- name: db.system
+- name: namespace
+  scope: resource
+`
+
+	var dcs DedicatedColumns
+	err := yaml.Unmarshal([]byte(input), &dcs)
+	require.NoError(t, err)
+
+	expected := DedicatedColumns{
+		{Scope: DedicatedColumnScopeSpan, Name: "db.system", Type: DedicatedColumnTypeString},
+		{Scope: DedicatedColumnScopeResource, Name: "namespace", Type: DedicatedColumnTypeString},
+	}
+	assert.Equal(t, expected, dcs)
+}
+
+func TestDedicatedColumnsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dcs     DedicatedColumns
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			dcs: DedicatedColumns{
+				{Scope: DedicatedColumnScopeSpan, Name: "db.system", Type: DedicatedColumnTypeString},
+				{Scope: DedicatedColumnScopeResource, Name: "db.system", Type: DedicatedColumnTypeString},
+			},
+		},
+		{
+			name:    "empty name",
+			dcs:     DedicatedColumns{{Scope: DedicatedColumnScopeSpan, Type: DedicatedColumnTypeString}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid scope",
+			dcs:     DedicatedColumns{{Scope: "event", Name: "foo", Type: DedicatedColumnTypeString}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid type",
+			dcs:     DedicatedColumns{{Scope: DedicatedColumnScopeSpan, Name: "foo", Type: "int"}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate",
+			dcs: DedicatedColumns{
+				{Scope: DedicatedColumnScopeSpan, Name: "foo", Type: DedicatedColumnTypeString},
+				{Scope: DedicatedColumnScopeSpan, Name: "foo", Type: DedicatedColumnTypeString},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dcs.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDedicatedColumnsHash(t *testing.T) {
+	a := DedicatedColumns{
+		{Scope: DedicatedColumnScopeSpan, Name: "foo", Type: DedicatedColumnTypeString},
+		{Scope: DedicatedColumnScopeResource, Name: "bar", Type: DedicatedColumnTypeString},
+	}
+	b := DedicatedColumns{
+		{Scope: DedicatedColumnScopeResource, Name: "bar", Type: DedicatedColumnTypeString},
+		{Scope: DedicatedColumnScopeSpan, Name: "foo", Type: DedicatedColumnTypeString},
+	}
+
+	assert.Equal(t, uint64(0), DedicatedColumns{}.Hash())
+	assert.Equal(t, a.Hash(), append(DedicatedColumns{}, a...).Hash())
+	assert.NotEqual(t, a.Hash(), b.Hash(), "order determines the column a value is stored in")
+	assert.NotEqual(t, uint64(0), a.Hash())
+}
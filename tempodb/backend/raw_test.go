@@ -82,6 +82,12 @@ func TestReader(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBlocks, actualBlocks)
 
+	// objects of the tenant are skipped
+	m.L = []string{uuid1.String(), TenantIndexName, SavedQueriesName, uuid2.String()}
+	actualBlocks, err = r.Blocks(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedBlocks, actualBlocks)
+
 	// should fail b/c meta is not valid
 	meta, err := r.BlockMeta(ctx, uuid.New(), "test")
 	assert.Error(t, err)
@@ -24,6 +24,10 @@ type List struct {
 	removed          PerTenant
 	compactedAdded   PerTenantCompacted
 	compactedRemoved PerTenantCompacted
+
+	// used by the queriers to track blocks added before they are polled
+	unpolled map[string]map[uuid.UUID]struct{}
+	dropped  map[string]map[uuid.UUID]struct{}
 }
 
 func New() *List {
@@ -35,6 +39,9 @@ func New() *List {
 		removed:          make(PerTenant),
 		compactedAdded:   make(PerTenantCompacted),
 		compactedRemoved: make(PerTenantCompacted),
+
+		unpolled: make(map[string]map[uuid.UUID]struct{}),
+		dropped:  make(map[string]map[uuid.UUID]struct{}),
 	}
 }
 
@@ -85,6 +92,7 @@ func (l *List) ApplyPollResults(m PerTenant, c PerTenantCompacted) {
 	l.mtx.Lock()
 	defer l.mtx.Unlock()
 
+	previous := l.metas
 	l.metas = m
 	l.compactedMetas = c
 
@@ -97,6 +105,66 @@ func (l *List) ApplyPollResults(m PerTenant, c PerTenantCompacted) {
 	l.removed = make(PerTenant)
 	l.compactedAdded = make(PerTenantCompacted)
 	l.compactedRemoved = make(PerTenantCompacted)
+
+	// remember the polled blocks that are gone so they aren't added again before they are polled
+	l.dropped = make(map[string]map[uuid.UUID]struct{})
+	for tenantID, metas := range previous {
+		known := l.knownInternal(tenantID)
+		for _, b := range metas {
+			if _, ok := l.unpolled[tenantID][b.BlockID]; ok {
+				continue
+			}
+			if _, ok := known[b.BlockID]; ok {
+				continue
+			}
+			if l.dropped[tenantID] == nil {
+				l.dropped[tenantID] = make(map[uuid.UUID]struct{})
+			}
+			l.dropped[tenantID][b.BlockID] = struct{}{}
+		}
+	}
+	l.unpolled = make(map[string]map[uuid.UUID]struct{})
+}
+
+// AddUnpolled adds the metas of blocks written by other components before they are found by polling. Blocks
+// known to the blocklist, compacted or dropped by the last poll are ignored. Unlike Update the blocks aren't
+// retained, the next poll lists them or not.
+func (l *List) AddUnpolled(tenantID string, add []*backend.BlockMeta) {
+	if tenantID == "" {
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	known := l.knownInternal(tenantID)
+	for _, b := range add {
+		if _, ok := known[b.BlockID]; ok {
+			continue
+		}
+		if _, ok := l.dropped[tenantID][b.BlockID]; ok {
+			continue
+		}
+		known[b.BlockID] = struct{}{}
+
+		l.metas[tenantID] = append(l.metas[tenantID], b)
+		if l.unpolled[tenantID] == nil {
+			l.unpolled[tenantID] = make(map[uuid.UUID]struct{})
+		}
+		l.unpolled[tenantID][b.BlockID] = struct{}{}
+	}
+}
+
+// knownInternal returns the IDs of the regular and compacted blocks of the tenant. It must be called under lock
+func (l *List) knownInternal(tenantID string) map[uuid.UUID]struct{} {
+	known := make(map[uuid.UUID]struct{}, len(l.metas[tenantID])+len(l.compactedMetas[tenantID]))
+	for _, b := range l.metas[tenantID] {
+		known[b.BlockID] = struct{}{}
+	}
+	for _, b := range l.compactedMetas[tenantID] {
+		known[b.BlockID] = struct{}{}
+	}
+	return known
 }
 
 // Update Adds and removes regular or compacted blocks from the in-memory blocklist.
@@ -640,3 +640,31 @@ func TestUpdatesSaved(t *testing.T) {
 		assert.Equal(t, tc.expectedCompacted, actualCompacted)
 	}
 }
+
+func TestAddUnpolled(t *testing.T) {
+	live := &backend.BlockMeta{BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}
+	compacted := &backend.CompactedBlockMeta{BlockMeta: backend.BlockMeta{BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}}
+	deleted := &backend.BlockMeta{BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}
+	flushed := &backend.BlockMeta{BlockID: uuid.MustParse("00000000-0000-0000-0000-000000000004")}
+
+	l := New()
+	l.ApplyPollResults(PerTenant{testTenantID: {live, deleted}}, PerTenantCompacted{testTenantID: {compacted}})
+	l.ApplyPollResults(PerTenant{testTenantID: {live}}, PerTenantCompacted{testTenantID: {compacted}})
+
+	// known, compacted and dropped blocks are ignored
+	l.AddUnpolled(testTenantID, []*backend.BlockMeta{live, &compacted.BlockMeta, deleted, flushed, flushed})
+	assert.Equal(t, []*backend.BlockMeta{live, flushed}, l.Metas(testTenantID))
+	assert.Equal(t, []*backend.CompactedBlockMeta{compacted}, l.CompactedMetas(testTenantID))
+
+	// the poll replaces unpolled blocks, they can be added again if they weren't listed yet
+	l.ApplyPollResults(PerTenant{testTenantID: {live}}, PerTenantCompacted{testTenantID: {compacted}})
+	assert.Equal(t, []*backend.BlockMeta{live}, l.Metas(testTenantID))
+
+	l.AddUnpolled(testTenantID, []*backend.BlockMeta{flushed})
+	assert.Equal(t, []*backend.BlockMeta{live, flushed}, l.Metas(testTenantID))
+
+	// unpolled blocks compacted before they were polled are ignored after the poll
+	l.ApplyPollResults(PerTenant{testTenantID: {live}}, PerTenantCompacted{testTenantID: {compacted, {BlockMeta: *flushed}}})
+	l.AddUnpolled(testTenantID, []*backend.BlockMeta{flushed})
+	assert.Equal(t, []*backend.BlockMeta{live}, l.Metas(testTenantID))
+}
@@ -696,6 +696,65 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 			},
 			expectedHash2: fmt.Sprintf("%v-%v-%v", tenantID, 0, now.Unix()),
 		},
+		{
+			name: "ensures blocks with different dedicated columns are not compacted together",
+			blocklist: []*backend.BlockMeta{
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "foo", Type: "string"}},
+				},
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "bar", Type: "string"}},
+				},
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "foo", Type: "string"}},
+				},
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "bar", Type: "string"}},
+				},
+			},
+			expected: []*backend.BlockMeta{
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "bar", Type: "string"}},
+				},
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "bar", Type: "string"}},
+				},
+			},
+			expectedHash: fmt.Sprintf("%v-%v-%v", tenantID, 0, now.Unix()),
+			expectedSecond: []*backend.BlockMeta{
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "foo", Type: "string"}},
+				},
+				{
+					BlockID:          uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+					EndTime:          now,
+					Version:          "vParquet3",
+					DedicatedColumns: backend.DedicatedColumns{{Scope: "span", Name: "foo", Type: "string"}},
+				},
+			},
+			expectedHash2: fmt.Sprintf("%v-%v-%v", tenantID, 0, now.Unix()),
+		},
 	}
 
 	for _, tt := range tests {
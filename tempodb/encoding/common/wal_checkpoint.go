@@ -0,0 +1,92 @@
+package common
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+)
+
+// IDCheckpointSuffix is the suffix of the files holding the IDs of the flushed pages of the parquet wal blocks.
+const IDCheckpointSuffix = ".ids"
+
+// An ID checkpoint records the IDs and row numbers of a flushed page of a wal block so the page doesn't need to be
+// read on replay:
+//
+//	| crc32 (4 bytes) | page size (8 bytes) | count (4 bytes) | entries |
+//
+// Every entry is | id length (4 bytes) | id | row number (8 bytes) |. The crc32 covers everything after it.
+const idCheckpointHeaderLength = 16
+
+// WriteIDCheckpoint writes the IDs and row numbers of a flushed page of size bytes to path.
+func WriteIDCheckpoint(path string, ids *IDMap[int64], size int64) error {
+	entries := ids.EntriesSortedByID()
+
+	length := idCheckpointHeaderLength
+	for _, e := range entries {
+		length += 4 + len(e.ID) + 8
+	}
+
+	buff := make([]byte, length)
+	binary.LittleEndian.PutUint64(buff[4:12], uint64(size))
+	binary.LittleEndian.PutUint32(buff[12:16], uint32(len(entries)))
+	pos := idCheckpointHeaderLength
+	for _, e := range entries {
+		binary.LittleEndian.PutUint32(buff[pos:], uint32(len(e.ID)))
+		pos += 4
+		pos += copy(buff[pos:], e.ID)
+		binary.LittleEndian.PutUint64(buff[pos:], uint64(e.Entry))
+		pos += 8
+	}
+	binary.LittleEndian.PutUint32(buff[0:4], crc32.ChecksumIEEE(buff[4:]))
+
+	err := os.WriteFile(path, buff, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing id checkpoint: %w", err)
+	}
+	return nil
+}
+
+// ReadIDCheckpoint returns the IDs and row numbers of a flushed page of size bytes from the checkpoint at path. It
+// returns nil if the checkpoint is missing, corrupted or written for a page of another size, the page must be read
+// instead.
+func ReadIDCheckpoint(path string, size int64) (*IDMap[int64], error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < idCheckpointHeaderLength || crc32.ChecksumIEEE(b[4:]) != binary.LittleEndian.Uint32(b[0:4]) ||
+		binary.LittleEndian.Uint64(b[4:12]) != uint64(size) {
+		return nil, nil
+	}
+
+	count := int(binary.LittleEndian.Uint32(b[12:16]))
+	ids := NewIDMap[int64]()
+	b = b[idCheckpointHeaderLength:]
+	for i := 0; i < count; i++ {
+		if len(b) < 4 {
+			return nil, nil
+		}
+		idLength := int(binary.LittleEndian.Uint32(b))
+		b = b[4:]
+		if idLength > len(b) || len(b)-idLength < 8 {
+			return nil, nil
+		}
+
+		id := make(ID, idLength)
+		copy(id, b)
+		b = b[idLength:]
+		ids.Set(id, int64(binary.LittleEndian.Uint64(b)))
+		b = b[8:]
+	}
+	if len(b) != 0 {
+		return nil, nil
+	}
+
+	return ids, nil
+}
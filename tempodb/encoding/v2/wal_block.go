@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"math"
 	"os"
@@ -41,6 +42,9 @@ type walBlock struct {
 	filepath string
 	readFile *os.File
 	once     sync.Once
+
+	// checkpoint holds the records appended since the last flush, nil if the block was replayed
+	checkpoint *checkpoint
 }
 
 func createWALBlock(id uuid.UUID, tenantID string, filepath string, e backend.Encoding, dataEncoding string, ingestionSlack time.Duration) (common.WALBlock, error) {
@@ -59,10 +63,20 @@ func createWALBlock(id uuid.UUID, tenantID string, filepath string, e backend.En
 		filepath:       filepath,
 		ingestionSlack: ingestionSlack,
 		encoder:        enc,
+		checkpoint:     newCheckpoint(0),
 	}
 
 	name := h.fullFilename()
 
+	err = os.MkdirAll(checkpointDir(h.filepath), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Remove(h.checkpointPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
 	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return nil, err
@@ -108,7 +122,22 @@ func openWALBlock(filename string, path string, ingestionSlack time.Duration, ad
 		return nil, nil, fmt.Errorf("creating object decoder: %w", err)
 	}
 
-	records, warning, err := ReplayWALAndGetRecords(f, e, func(bytes []byte) error {
+	// skip the objects recorded by the checkpoint, the whole file is replayed if it is missing or invalid
+	c, err := readCheckpoint(b.checkpointPath(), f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	if c == nil {
+		c = newCheckpoint(0)
+	}
+	blockStart, blockEnd = c.start, c.end
+
+	_, err = f.Seek(int64(c.offset), io.SeekStart)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seeking wal file: %w", err)
+	}
+
+	records, warning, err := replayWALAndGetRecordsFrom(f, e, c.offset, func(bytes []byte) error {
 		start, end, err := dec.FastRange(bytes)
 		if err == decoder.ErrUnsupported {
 			now := uint32(time.Now().Unix())
@@ -132,6 +161,9 @@ func openWALBlock(filename string, path string, ingestionSlack time.Duration, ad
 		return nil, nil, err
 	}
 
+	records = append(c.records, records...)
+	SortRecords(records)
+
 	b.appender = NewRecordAppender(records)
 	b.meta.TotalObjects = b.appender.Length()
 	b.meta.StartTime = time.Unix(int64(blockStart), 0)
@@ -157,12 +189,21 @@ func ownsWALBlock(entry fs.DirEntry) bool {
 // Append adds an id and object to this wal block. start/end should indicate the time range
 // associated with the past object. They are unix epoch seconds.
 func (a *walBlock) Append(id common.ID, b []byte, start, end uint32) error {
+	offset := a.appender.DataLength()
 	err := a.appender.Append(id, b)
 	if err != nil {
 		return err
 	}
 	start, end = a.adjustTimeRangeForSlack(start, end, 0)
 	a.meta.ObjectAdded(id, start, end)
+
+	if a.checkpoint != nil {
+		a.checkpoint.add(Record{
+			ID:     id,
+			Start:  offset,
+			Length: uint32(a.appender.DataLength() - offset),
+		}, start, end)
+	}
 	return nil
 }
 
@@ -180,7 +221,34 @@ func (a *walBlock) AppendTrace(id common.ID, trace *tempopb.Trace, start, end ui
 	return a.Append(id, buff2, start, end)
 }
 
+// Flush appends the objects written since the last flush to the checkpoint of the block. The wal file is synced
+// first so the checkpoint never records objects that aren't on disk, the crc of the objects is read back from the
+// file.
 func (a *walBlock) Flush() error {
+	if a.checkpoint == nil || len(a.checkpoint.records) == 0 || a.appendFile == nil {
+		return nil
+	}
+
+	err := a.appendFile.Sync()
+	if err != nil {
+		return err
+	}
+
+	f, err := a.file()
+	if err != nil {
+		return err
+	}
+	a.checkpoint.dataCRC, err = dataCRC(f, a.checkpoint.from, a.checkpoint.offset)
+	if err != nil {
+		return err
+	}
+
+	err = appendCheckpoint(a.checkpointPath(), a.checkpoint)
+	if err != nil {
+		return err
+	}
+
+	a.checkpoint = newCheckpoint(a.checkpoint.offset)
 	return nil
 }
 
@@ -239,6 +307,10 @@ func (a *walBlock) Clear() error {
 	_ = a.appender.Complete()
 
 	name := a.fullFilename()
+	err := os.Remove(a.checkpointPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
 	return os.Remove(name)
 }
 
@@ -335,6 +407,10 @@ func (a *walBlock) fullFilenameSeparator(separator string) string {
 	return filepath.Join(a.filepath, filename)
 }
 
+func (a *walBlock) checkpointPath() string {
+	return checkpointPath(a.filepath, filepath.Base(a.fullFilename()))
+}
+
 func (a *walBlock) file() (*os.File, error) {
 	var err error
 	a.once.Do(func() {
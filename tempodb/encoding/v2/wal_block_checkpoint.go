@@ -0,0 +1,214 @@
+package v2
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// CheckpointDir is the folder of the wal holding the checkpoints of the v2 wal blocks.
+const CheckpointDir = "checkpoints"
+
+// A checkpoint records the records and time range of the objects of a wal block up to an offset of its file. It is
+// written in chunks appended every time the block is flushed:
+//
+//	| crc32 (4 bytes) | payload length (4 bytes) | offset (8 bytes) | data crc32 (4 bytes) | start (4 bytes) | end (4 bytes) | records |
+//
+// The crc32 covers the payload, which starts at the offset field. The data crc32 covers the bytes of the wal file
+// between the offsets of the previous and this chunk, the records of a chunk span exactly these bytes. Replay reads
+// all the valid chunks, verifies each of them against the wal file and only reads the objects after the offset of
+// the last verified chunk.
+const (
+	checkpointChunkHeaderLength  = 8
+	checkpointChunkPayloadHeader = 20
+)
+
+type checkpoint struct {
+	records []Record
+	from    uint64
+	offset  uint64
+	dataCRC uint32
+	start   uint32
+	end     uint32
+}
+
+// newCheckpoint returns a checkpoint of the objects written to the wal file after the offset from.
+func newCheckpoint(from uint64) *checkpoint {
+	return &checkpoint{
+		from:   from,
+		offset: from,
+		start:  math.MaxUint32,
+	}
+}
+
+// add tracks a record written to the wal file and the time range of its object.
+func (c *checkpoint) add(r Record, start, end uint32) {
+	c.records = append(c.records, r)
+	if start < c.start {
+		c.start = start
+	}
+	if end > c.end {
+		c.end = end
+	}
+	c.offset = r.Start + uint64(r.Length)
+}
+
+// merge adds the records and the time range of the next chunk.
+func (c *checkpoint) merge(chunk *checkpoint) {
+	c.records = append(c.records, chunk.records...)
+	c.offset = chunk.offset
+	if chunk.start < c.start {
+		c.start = chunk.start
+	}
+	if chunk.end > c.end {
+		c.end = chunk.end
+	}
+}
+
+// marshalChunk marshals the checkpoint as a chunk of a checkpoint file.
+func (c *checkpoint) marshalChunk() ([]byte, error) {
+	payloadLength := checkpointChunkPayloadHeader + len(c.records)*recordLength
+	buff := make([]byte, checkpointChunkHeaderLength+payloadLength)
+
+	payload := buff[checkpointChunkHeaderLength:]
+	binary.LittleEndian.PutUint64(payload[0:8], c.offset)
+	binary.LittleEndian.PutUint32(payload[8:12], c.dataCRC)
+	binary.LittleEndian.PutUint32(payload[12:16], c.start)
+	binary.LittleEndian.PutUint32(payload[16:20], c.end)
+	err := staticRecord.MarshalRecordsToBuffer(c.records, payload[checkpointChunkPayloadHeader:])
+	if err != nil {
+		return nil, err
+	}
+
+	binary.LittleEndian.PutUint32(buff[0:4], crc32.ChecksumIEEE(payload))
+	binary.LittleEndian.PutUint32(buff[4:8], uint32(payloadLength))
+	return buff, nil
+}
+
+// unmarshalCheckpoint returns the valid chunks of a checkpoint file. Reading stops at the first corrupted or
+// partially written chunk, the chunks before it are still valid.
+func unmarshalCheckpoint(b []byte) []*checkpoint {
+	var chunks []*checkpoint
+	from := uint64(0)
+	for len(b) >= checkpointChunkHeaderLength {
+		crc := binary.LittleEndian.Uint32(b[0:4])
+		payloadLength := int(binary.LittleEndian.Uint32(b[4:8]))
+		b = b[checkpointChunkHeaderLength:]
+
+		if payloadLength <= checkpointChunkPayloadHeader || payloadLength > len(b) ||
+			(payloadLength-checkpointChunkPayloadHeader)%recordLength != 0 {
+			break
+		}
+		payload := b[:payloadLength]
+		b = b[payloadLength:]
+		if crc32.ChecksumIEEE(payload) != crc {
+			break
+		}
+
+		c := newCheckpoint(from)
+		offset := binary.LittleEndian.Uint64(payload[0:8])
+		c.dataCRC = binary.LittleEndian.Uint32(payload[8:12])
+		start := binary.LittleEndian.Uint32(payload[12:16])
+		end := binary.LittleEndian.Uint32(payload[16:20])
+
+		records := payload[checkpointChunkPayloadHeader:]
+		count := staticRecord.RecordCount(records)
+		valid := true
+		for i := 0; i < count && valid; i++ {
+			r := staticRecord.UnmarshalRecord(records[i*recordLength : (i+1)*recordLength])
+			// records must follow each other from the offset of the previous chunk
+			valid = r.Start == c.offset
+			c.add(r, start, end)
+		}
+		if !valid || c.offset != offset {
+			break
+		}
+
+		chunks = append(chunks, c)
+		from = offset
+	}
+
+	return chunks
+}
+
+// readCheckpoint returns the checkpoint of the wal file if it is consistent with the file. The bytes of the wal file
+// covered by every chunk are checked against the crc of the chunk, the checkpoint ends at the first chunk that
+// doesn't match the file. It returns nil if there is no valid checkpoint and the whole file must be replayed.
+func readCheckpoint(path string, file *os.File) (*checkpoint, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var c *checkpoint
+	for _, chunk := range unmarshalCheckpoint(b) {
+		if uint64(info.Size()) < chunk.offset {
+			break
+		}
+
+		crc, err := dataCRC(file, chunk.from, chunk.offset)
+		if err != nil {
+			return nil, err
+		}
+		if crc != chunk.dataCRC {
+			break
+		}
+
+		if c == nil {
+			c = newCheckpoint(0)
+		}
+		c.merge(chunk)
+	}
+
+	return c, nil
+}
+
+// dataCRC returns the crc32 of the bytes of the wal file between the offsets from and to.
+func dataCRC(file io.ReaderAt, from, to uint64) (uint32, error) {
+	h := crc32.NewIEEE()
+	_, err := io.Copy(h, io.NewSectionReader(file, int64(from), int64(to-from)))
+	if err != nil {
+		return 0, err
+	}
+	return h.Sum32(), nil
+}
+
+// appendCheckpoint appends the chunk of the checkpoint to the checkpoint file.
+func appendCheckpoint(path string, c *checkpoint) error {
+	chunk, err := c.marshalChunk()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = f.Write(chunk); err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+func checkpointDir(walPath string) string {
+	return filepath.Join(walPath, CheckpointDir)
+}
+
+func checkpointPath(walPath, walFilename string) string {
+	return filepath.Join(checkpointDir(walPath), walFilename)
+}
@@ -0,0 +1,220 @@
+package v2
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestCheckpointReplay(t *testing.T) {
+	dir := t.TempDir()
+	block := createCheckpointTestBlock(t, dir)
+
+	ids := appendCheckpointTestTraces(t, block, 10)
+	require.NoError(t, block.Flush())
+	ids = append(ids, appendCheckpointTestTraces(t, block, 10)...)
+	require.NoError(t, block.Flush())
+	// the tail isn't checkpointed
+	ids = append(ids, appendCheckpointTestTraces(t, block, 5)...)
+
+	c := readCheckpointTestBlock(t, block)
+	require.NotNil(t, c)
+	require.Len(t, c.records, 20)
+	require.Equal(t, c.records[19].Start+uint64(c.records[19].Length), c.offset)
+
+	replayed := openCheckpointTestBlock(t, block)
+	require.Equal(t, len(ids), replayed.BlockMeta().TotalObjects)
+	for _, id := range ids {
+		tr, err := replayed.FindTraceByID(context.Background(), id, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+	}
+	require.NotZero(t, replayed.BlockMeta().StartTime)
+	require.NotZero(t, replayed.BlockMeta().EndTime)
+
+	// the checkpoint is removed with the block
+	require.NoError(t, block.Clear())
+	_, err := os.Stat(block.checkpointPath())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCheckpointVerifiesWALFile(t *testing.T) {
+	tcs := []struct {
+		name            string
+		corruptedObject int
+		expectedRecords int
+	}{
+		{
+			name:            "first chunk",
+			corruptedObject: 5,
+			expectedRecords: 0,
+		},
+		{
+			name:            "second chunk",
+			corruptedObject: 15,
+			expectedRecords: 10,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			block := createCheckpointTestBlock(t, t.TempDir())
+
+			ids := appendCheckpointTestTraces(t, block, 10)
+			require.NoError(t, block.Flush())
+			ids = append(ids, appendCheckpointTestTraces(t, block, 10)...)
+			require.NoError(t, block.Flush())
+
+			c := readCheckpointTestBlock(t, block)
+			require.NotNil(t, c)
+			corruptWALFile(t, block, int64(c.records[tc.corruptedObject].Start)+10)
+
+			// the checkpoint ends before the chunk covering the corrupted object
+			c = readCheckpointTestBlock(t, block)
+			if tc.expectedRecords == 0 {
+				require.Nil(t, c)
+			} else {
+				require.Len(t, c.records, tc.expectedRecords)
+			}
+
+			// the replay of the objects after the checkpoint stops at the corrupted object
+			replayed, warning, err := openWALBlock(filepath.Base(block.fullFilename()), block.filepath, 0, 0)
+			require.NoError(t, err)
+			require.Error(t, warning)
+			require.Equal(t, tc.corruptedObject, replayed.BlockMeta().TotalObjects)
+			for _, id := range ids[:tc.corruptedObject] {
+				tr, err := replayed.FindTraceByID(context.Background(), id, common.DefaultSearchOptions())
+				require.NoError(t, err)
+				require.NotNil(t, tr)
+			}
+		})
+	}
+}
+
+func TestCheckpointFallsBackToFullReplay(t *testing.T) {
+	tcs := []struct {
+		name    string
+		corrupt func(t *testing.T, b *walBlock)
+	}{
+		{
+			name: "corrupted checkpoint",
+			corrupt: func(t *testing.T, b *walBlock) {
+				require.NoError(t, os.WriteFile(b.checkpointPath(), []byte("not a checkpoint"), 0644))
+			},
+		},
+		{
+			name: "corrupted last chunk",
+			corrupt: func(t *testing.T, b *walBlock) {
+				f, err := os.OpenFile(b.checkpointPath(), os.O_RDWR, 0644)
+				require.NoError(t, err)
+				defer f.Close()
+				info, err := f.Stat()
+				require.NoError(t, err)
+				_, err = f.WriteAt([]byte{0xFF, 0xFF, 0xFF, 0xFF}, info.Size()-4)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "truncated checkpoint",
+			corrupt: func(t *testing.T, b *walBlock) {
+				info, err := os.Stat(b.checkpointPath())
+				require.NoError(t, err)
+				require.NoError(t, os.Truncate(b.checkpointPath(), info.Size()-10))
+			},
+		},
+		{
+			name: "replaced wal file",
+			corrupt: func(t *testing.T, b *walBlock) {
+				// the checkpoint of another wal file
+				other := createCheckpointTestBlock(t, t.TempDir())
+				appendCheckpointTestTraces(t, other, 20)
+				require.NoError(t, other.Flush())
+				require.NoError(t, os.Rename(other.checkpointPath(), b.checkpointPath()))
+			},
+		},
+		{
+			name: "missing checkpoint",
+			corrupt: func(t *testing.T, b *walBlock) {
+				require.NoError(t, os.Remove(b.checkpointPath()))
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			block := createCheckpointTestBlock(t, t.TempDir())
+
+			ids := appendCheckpointTestTraces(t, block, 10)
+			require.NoError(t, block.Flush())
+			ids = append(ids, appendCheckpointTestTraces(t, block, 10)...)
+			require.NoError(t, block.Flush())
+
+			tc.corrupt(t, block)
+
+			replayed := openCheckpointTestBlock(t, block)
+			require.Equal(t, len(ids), replayed.BlockMeta().TotalObjects)
+			for _, id := range ids {
+				tr, err := replayed.FindTraceByID(context.Background(), id, common.DefaultSearchOptions())
+				require.NoError(t, err)
+				require.NotNil(t, tr)
+			}
+		})
+	}
+}
+
+func createCheckpointTestBlock(t *testing.T, dir string) *walBlock {
+	block, err := createWALBlock(uuid.New(), testTenantID, dir, backend.EncSnappy, model.CurrentEncoding, 0)
+	require.NoError(t, err)
+	return block.(*walBlock)
+}
+
+func openCheckpointTestBlock(t *testing.T, b *walBlock) common.WALBlock {
+	replayed, warning, err := openWALBlock(filepath.Base(b.fullFilename()), b.filepath, 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, warning)
+	return replayed
+}
+
+func readCheckpointTestBlock(t *testing.T, b *walBlock) *checkpoint {
+	f, err := os.Open(b.fullFilename())
+	require.NoError(t, err)
+	defer f.Close()
+
+	c, err := readCheckpoint(b.checkpointPath(), f)
+	require.NoError(t, err)
+	return c
+}
+
+func appendCheckpointTestTraces(t *testing.T, b *walBlock, count int) []common.ID {
+	enc := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	ids := make([]common.ID, 0, count)
+	for i := 0; i < count; i++ {
+		id := test.ValidTraceID(nil)
+		b1, err := enc.PrepareForWrite(test.MakeTrace(2, id), 0, 0)
+		require.NoError(t, err)
+		b2, err := enc.ToObject([][]byte{b1})
+		require.NoError(t, err)
+		require.NoError(t, b.Append(id, b2, 0, 0))
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// corruptWALFile overwrites bytes of the wal file at the offset
+func corruptWALFile(t *testing.T, b *walBlock, offset int64) {
+	f, err := os.OpenFile(b.fullFilename(), os.O_RDWR, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteAt([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, offset)
+	require.NoError(t, err)
+}
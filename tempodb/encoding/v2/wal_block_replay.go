@@ -11,6 +11,11 @@ import (
 
 // ReplayWALAndGetRecords replays a WAL file that could contain either traces or searchdata
 func ReplayWALAndGetRecords(file *os.File, enc backend.Encoding, handleObj func([]byte) error) ([]Record, error, error) {
+	return replayWALAndGetRecordsFrom(file, enc, 0, handleObj)
+}
+
+// replayWALAndGetRecordsFrom replays a WAL file from the current position of the file which must be at offset
+func replayWALAndGetRecordsFrom(file *os.File, enc backend.Encoding, offset uint64, handleObj func([]byte) error) ([]Record, error, error) {
 	dataReader, err := NewDataReader(backend.NewContextReaderWithAllReader(file), enc)
 	if err != nil {
 		return nil, nil, err
@@ -22,7 +27,7 @@ func ReplayWALAndGetRecords(file *os.File, enc backend.Encoding, handleObj func(
 	var pageLen uint32
 	var id []byte
 	objectReader := NewObjectReaderWriter()
-	currentOffset := uint64(0)
+	currentOffset := offset
 	for {
 		buffer, pageLen, err = dataReader.NextPage(buffer)
 		if err == io.EOF {
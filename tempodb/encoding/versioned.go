@@ -13,6 +13,7 @@ import (
 	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
 	"github.com/grafana/tempo/tempodb/encoding/vparquet"
 	"github.com/grafana/tempo/tempodb/encoding/vparquet2"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
 )
 
 // VersionedEncoding represents a backend block version, and the methods to
@@ -47,8 +48,9 @@ type VersionedEncoding interface {
 	// OpenWALBlock opens an existing appendable block for the WAL
 	OpenWALBlock(filename string, path string, ingestionSlack time.Duration, additionalStartSlack time.Duration) (common.WALBlock, error, error)
 
-	// CreateWALBlock creates a new appendable block for the WAL
-	CreateWALBlock(id uuid.UUID, tenantID string, filepath string, e backend.Encoding, dataEncoding string, ingestionSlack time.Duration) (common.WALBlock, error)
+	// CreateWALBlock creates a new appendable block for the WAL. Dedicated columns are ignored by
+	// encodings that don't support them.
+	CreateWALBlock(id uuid.UUID, tenantID string, filepath string, e backend.Encoding, dataEncoding string, ingestionSlack time.Duration, dedicatedColumns backend.DedicatedColumns) (common.WALBlock, error)
 
 	// OwnsWALBlock indicates if this encoding owns the WAL block
 	OwnsWALBlock(entry fs.DirEntry) bool
@@ -63,6 +65,8 @@ func FromVersion(v string) (VersionedEncoding, error) {
 		return vparquet.Encoding{}, nil
 	case vparquet2.VersionString:
 		return vparquet2.Encoding{}, nil
+	case vparquet3.VersionString:
+		return vparquet3.Encoding{}, nil
 	default:
 		return nil, fmt.Errorf("%s is not a valid block version", v)
 	}
@@ -79,6 +83,7 @@ func AllEncodings() []VersionedEncoding {
 		v2.Encoding{},
 		vparquet.Encoding{},
 		vparquet2.Encoding{},
+		vparquet3.Encoding{},
 	}
 }
 
@@ -48,6 +48,17 @@ func (s *span) DurationNanos() uint64 {
 	return s.endtimeUnixNanos - s.startTimeUnixNanos
 }
 
+// nested set values are not stored in vParquet blocks
+func (s *span) NestedSetParent() int32 {
+	return 0
+}
+func (s *span) NestedSetLeft() int32 {
+	return 0
+}
+func (s *span) NestedSetRight() int32 {
+	return 0
+}
+
 // attributesMatched counts all attributes in the map as well as metadata fields like start/end/id
 func (s *span) attributesMatched() int {
 	count := 0
@@ -224,6 +235,11 @@ func (b *backendBlock) Fetch(ctx context.Context, req traceql.FetchSpansRequest,
 
 func checkConditions(conditions []traceql.Condition) error {
 	for _, cond := range conditions {
+		switch cond.Attribute.Intrinsic {
+		case traceql.IntrinsicNestedSetLeft, traceql.IntrinsicNestedSetRight, traceql.IntrinsicNestedSetParent:
+			return fmt.Errorf("structural operators are not supported by block format %s. condition: %+v", VersionString, cond)
+		}
+
 		opCount := len(cond.Operands)
 
 		switch cond.Op {
@@ -95,7 +95,7 @@ func openWALBlock(filename string, path string, ingestionSlack time.Duration, _
 
 	var warning error
 	for _, f := range files {
-		if f.Name() == backend.MetaName {
+		if f.Name() == backend.MetaName || strings.HasSuffix(f.Name(), common.IDCheckpointSuffix) {
 			continue
 		}
 
@@ -121,25 +121,37 @@ func openWALBlock(filename string, path string, ingestionSlack time.Duration, _
 		defer file.Close()
 		pf := file.parquetFile
 
-		// iterate the parquet file and build the meta
-		iter := makeIterFunc(context.Background(), pf.RowGroups(), pf)(columnPathTraceID, nil, columnPathTraceID)
-		defer iter.Close()
-
-		for {
-			match, err := iter.Next()
-			if err != nil {
-				return nil, nil, fmt.Errorf("error iterating wal page [%s %d]: %w", b.meta.BlockID.String(), i, err)
-			}
-			if match == nil {
-				break
+		// the ids of the page are read from its checkpoint. if it is missing or doesn't match the page, iterate the
+		// parquet file and build the meta
+		ids, err := common.ReadIDCheckpoint(path+common.IDCheckpointSuffix, i.Size())
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading id checkpoint: %s %w", path, err)
+		}
+		if ids != nil && int64(ids.Len()) == pf.NumRows() {
+			for _, e := range ids.EntriesSortedByID() {
+				b.meta.ObjectAdded(e.ID, 0, 0)
 			}
+			page.ids = ids
+		} else {
+			iter := makeIterFunc(context.Background(), pf.RowGroups(), pf)(columnPathTraceID, nil, columnPathTraceID)
+			defer iter.Close()
+
+			for {
+				match, err := iter.Next()
+				if err != nil {
+					return nil, nil, fmt.Errorf("error iterating wal page [%s %d]: %w", b.meta.BlockID.String(), i, err)
+				}
+				if match == nil {
+					break
+				}
 
-			for _, e := range match.Entries {
-				switch e.Key {
-				case columnPathTraceID:
-					traceID := e.Value.ByteArray()
-					b.meta.ObjectAdded(traceID, 0, 0)
-					page.ids.Set(traceID, match.RowNumber[0]) // Save rownumber for the trace ID
+				for _, e := range match.Entries {
+					switch e.Key {
+					case columnPathTraceID:
+						traceID := e.Value.ByteArray()
+						b.meta.ObjectAdded(traceID, 0, 0)
+						page.ids.Set(traceID, match.RowNumber[0]) // Save rownumber for the trace ID
+					}
 				}
 			}
 		}
@@ -428,6 +440,12 @@ func (b *walBlock) Flush() (err error) {
 		return fmt.Errorf("error closing file: %w", err)
 	}
 
+	// the page isn't written to anymore, checkpoint its ids so it doesn't need to be read on replay
+	err = common.WriteIDCheckpoint(b.file.Name()+common.IDCheckpointSuffix, b.ids, sz)
+	if err != nil {
+		return err
+	}
+
 	b.writeFlush(newWalBlockFlush(b.file.Name(), b.ids))
 	b.flushedSize += sz
 	b.unflushedSize = 0
@@ -130,6 +130,55 @@ func TestPartialReplay(t *testing.T) {
 	require.Equal(t, count/2, gotCount)
 }
 
+// TestReplayIDCheckpoint verifies that the ids of the flushed pages are read from their checkpoints on replay and
+// that the pages are read if the checkpoints are missing or invalid.
+func TestReplayIDCheckpoint(t *testing.T) {
+	decoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	w, err := createWALBlock(uuid.New(), "fake", t.TempDir(), backend.EncNone, model.CurrentEncoding, 0)
+	require.NoError(t, err)
+
+	ids := make([]common.ID, 10)
+	for i := range ids {
+		ids[i] = test.ValidTraceID(nil)
+		b1, err := decoder.PrepareForWrite(test.MakeTrace(1, ids[i]), 0, 0)
+		require.NoError(t, err)
+		b2, err := decoder.ToObject([][]byte{b1})
+		require.NoError(t, err)
+		require.NoError(t, w.Append(ids[i], b2, 0, 0))
+	}
+	require.NoError(t, w.Flush())
+
+	replay := func() *walBlock {
+		w2, warning, err := openWALBlock(filepath.Base(w.walPath()), filepath.Dir(w.walPath()), 0, 0)
+		require.NoError(t, err)
+		require.NoError(t, warning)
+		require.Equal(t, len(ids), w2.BlockMeta().TotalObjects)
+		return w2.(*walBlock)
+	}
+
+	// the checkpoint is used instead of the trace id column
+	fpath := w.filepathOf(1)
+	info, err := os.Stat(fpath)
+	require.NoError(t, err)
+	checkpointed := common.NewIDMap[int64]()
+	for i := range ids {
+		checkpointed.Set(test.ValidTraceID(nil), int64(i))
+	}
+	require.NoError(t, common.WriteIDCheckpoint(fpath+common.IDCheckpointSuffix, checkpointed, info.Size()))
+	require.Equal(t, checkpointed.EntriesSortedByID(), replay().flushed[0].ids.EntriesSortedByID())
+
+	// a checkpoint of a page of another size or a corrupted checkpoint isn't used
+	require.NoError(t, common.WriteIDCheckpoint(fpath+common.IDCheckpointSuffix, checkpointed, info.Size()+1))
+	require.Equal(t, w.flushed[0].ids.EntriesSortedByID(), replay().flushed[0].ids.EntriesSortedByID())
+
+	require.NoError(t, os.WriteFile(fpath+common.IDCheckpointSuffix, []byte("not a checkpoint"), 0644))
+	require.Equal(t, w.flushed[0].ids.EntriesSortedByID(), replay().flushed[0].ids.EntriesSortedByID())
+
+	require.NoError(t, os.Remove(fpath+common.IDCheckpointSuffix))
+	require.Equal(t, w.flushed[0].ids.EntriesSortedByID(), replay().flushed[0].ids.EntriesSortedByID())
+}
+
 func TestParseFilename(t *testing.T) {
 	tests := []struct {
 		name            string
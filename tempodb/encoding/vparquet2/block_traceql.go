@@ -1,6 +1,7 @@
 package vparquet2
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -11,11 +12,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/protobuf/jsonpb" //nolint:all //deprecated
 	"github.com/pkg/errors"
 	"github.com/segmentio/parquet-go"
 
 	"github.com/grafana/tempo/pkg/parquetquery"
 	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
@@ -28,6 +32,9 @@ type span struct {
 	id                 []byte
 	startTimeUnixNanos uint64
 	durationNanos      uint64
+	nestedSetParent    int32
+	nestedSetLeft      int32
+	nestedSetRight     int32
 
 	// metadata used to track the span in the parquet file
 	rowNum         parquetquery.RowNumber
@@ -47,6 +54,15 @@ func (s *span) StartTimeUnixNanos() uint64 {
 func (s *span) DurationNanos() uint64 {
 	return s.durationNanos
 }
+func (s *span) NestedSetParent() int32 {
+	return s.nestedSetParent
+}
+func (s *span) NestedSetLeft() int32 {
+	return s.nestedSetLeft
+}
+func (s *span) NestedSetRight() int32 {
+	return s.nestedSetRight
+}
 
 // attributesMatched counts all attributes in the map as well as metadata fields like start/end/id
 func (s *span) attributesMatched() int {
@@ -89,6 +105,9 @@ func putSpan(s *span) {
 	s.id = nil
 	s.startTimeUnixNanos = 0
 	s.durationNanos = 0
+	s.nestedSetParent = 0
+	s.nestedSetLeft = 0
+	s.nestedSetRight = 0
 	s.rowNum = parquetquery.EmptyRowNumber()
 	s.cbSpansetFinal = false
 	s.cbSpanset = nil
@@ -169,13 +188,24 @@ const (
 	columnPathSpanHTTPStatusCode = "rs.list.element.ss.list.element.Spans.list.element.HttpStatusCode"
 	columnPathSpanHTTPMethod     = "rs.list.element.ss.list.element.Spans.list.element.HttpMethod"
 	columnPathSpanHTTPURL        = "rs.list.element.ss.list.element.Spans.list.element.HttpUrl"
+	columnPathSpanParentID       = "rs.list.element.ss.list.element.Spans.list.element.ParentID"
+	columnPathSpanNestedSetLeft  = "rs.list.element.ss.list.element.Spans.list.element.NestedSetLeft"
+	columnPathSpanNestedSetRight = "rs.list.element.ss.list.element.Spans.list.element.NestedSetRight"
+	columnPathSpanLinks          = "rs.list.element.ss.list.element.Spans.list.element.Links"
 
-	otherEntrySpansetKey = "spanset"
-	otherEntrySpanKey    = "span"
+	columnPathEventName      = "rs.list.element.ss.list.element.Spans.list.element.Events.list.element.Name"
+	columnPathEventAttrKey   = "rs.list.element.ss.list.element.Spans.list.element.Events.list.element.Attrs.list.element.Key"
+	columnPathEventAttrValue = "rs.list.element.ss.list.element.Spans.list.element.Events.list.element.Attrs.list.element.Value"
+
+	otherEntrySpansetKey    = "spanset"
+	otherEntrySpanKey       = "span"
+	otherEntryScopedAttrKey = "scopedAttr"
 
 	// a fake intrinsic scope at the trace lvl
 	intrinsicScopeTrace = -1
 	intrinsicScopeSpan  = -2
+	intrinsicScopeEvent = -3
+	intrinsicScopeLink  = -4
 )
 
 // todo: scope is the only field used here. either remove the other fields or use them.
@@ -191,6 +221,15 @@ var intrinsicColumnLookups = map[traceql.Intrinsic]struct {
 	traceql.IntrinsicSpanID:        {intrinsicScopeSpan, traceql.TypeString, columnPathSpanID},
 	traceql.IntrinsicSpanStartTime: {intrinsicScopeSpan, traceql.TypeString, columnPathSpanStartTime},
 
+	traceql.IntrinsicNestedSetLeft:   {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanNestedSetLeft},
+	traceql.IntrinsicNestedSetRight:  {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanNestedSetRight},
+	traceql.IntrinsicNestedSetParent: {intrinsicScopeSpan, traceql.TypeInt, columnPathSpanParentID},
+
+	traceql.IntrinsicEventName: {intrinsicScopeEvent, traceql.TypeString, columnPathEventName},
+	// link ids are part of the proto encoded links and don't have their own column
+	traceql.IntrinsicLinkTraceID: {intrinsicScopeLink, traceql.TypeString, ""},
+	traceql.IntrinsicLinkSpanID:  {intrinsicScopeLink, traceql.TypeString, ""},
+
 	traceql.IntrinsicTraceRootService: {intrinsicScopeTrace, traceql.TypeString, columnPathRootServiceName},
 	traceql.IntrinsicTraceRootSpan:    {intrinsicScopeTrace, traceql.TypeString, columnPathRootSpanName},
 	traceql.IntrinsicTraceDuration:    {intrinsicScopeTrace, traceql.TypeString, columnPathDurationNanos},
@@ -682,12 +721,21 @@ func fetch(ctx context.Context, req traceql.FetchSpansRequest, pf *parquet.File,
 func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, conds []traceql.Condition, allConditions bool, start uint64, end uint64, pf *parquet.File, opts common.SearchOptions) (parquetquery.Iterator, error) {
 	// Categorize conditions into span-level or resource-level
 	var (
-		mingledConditions  bool
-		spanConditions     []traceql.Condition
-		resourceConditions []traceql.Condition
-		traceConditions    []traceql.Condition
+		mingledConditions   bool
+		spanConditions      []traceql.Condition
+		resourceConditions  []traceql.Condition
+		traceConditions     []traceql.Condition
+		nestedSetConditions []traceql.Condition
 	)
 	for _, cond := range conds {
+		// Nested set values are only fetched to evaluate structural operators
+		// and are added to the span conditions below.
+		switch cond.Attribute.Intrinsic {
+		case traceql.IntrinsicNestedSetLeft, traceql.IntrinsicNestedSetRight, traceql.IntrinsicNestedSetParent:
+			nestedSetConditions = append(nestedSetConditions, cond)
+			continue
+		}
+
 		// If no-scoped intrinsic then assign default scope
 		scope := cond.Attribute.Scope
 		if cond.Attribute.Scope == traceql.AttributeScopeNone {
@@ -708,6 +756,12 @@ func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, c
 			spanConditions = append(spanConditions, cond)
 			continue
 
+		case traceql.AttributeScopeEvent, intrinsicScopeEvent,
+			traceql.AttributeScopeLink, intrinsicScopeLink:
+			// Events and links are nested in the span and evaluated by the span iterator
+			spanConditions = append(spanConditions, cond)
+			continue
+
 		case traceql.AttributeScopeResource:
 			resourceConditions = append(resourceConditions, cond)
 			continue
@@ -733,18 +787,23 @@ func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, c
 	// matched upstream to a resource.
 	// TODO - After introducing AllConditions it seems like some of this logic overlaps.
 	//        Determine if it can be generalized or simplified.
+	// Structural operators relate spans across the whole trace, e.g. { .a = 1 } !> { } matches
+	// spans of traces without a span with a=1. Spans can't be dropped early and all of them
+	// are evaluated by the engine.
+	structural := len(nestedSetConditions) > 0
+
 	var (
 		// If there are only span conditions, then don't return a span upstream
 		// unless it matches at least 1 span-level condition.
-		spanRequireAtLeastOneMatch = len(spanConditions) > 0 && len(resourceConditions) == 0 && len(traceConditions) == 0
+		spanRequireAtLeastOneMatch = len(spanConditions) > 0 && len(resourceConditions) == 0 && len(traceConditions) == 0 && !structural
 
 		// If there are only resource conditions, then don't return a resource upstream
 		// unless it matches at least 1 resource-level condition.
-		batchRequireAtLeastOneMatch = len(spanConditions) == 0 && len(resourceConditions) > 0 && len(traceConditions) == 0
+		batchRequireAtLeastOneMatch = len(spanConditions) == 0 && len(resourceConditions) > 0 && len(traceConditions) == 0 && !structural
 
 		// Don't return the final spanset upstream unless it matched at least 1 condition
 		// anywhere, except in the case of the empty query: {}
-		batchRequireAtLeastOneMatchOverall = len(conds) > 0 && len(traceConditions) == 0 && len(traceConditions) == 0
+		batchRequireAtLeastOneMatchOverall = len(conds) > 0 && len(traceConditions) == 0 && !structural
 	)
 
 	// Optimization for queries like {resource.x... && span.y ...}
@@ -752,6 +811,8 @@ func createAllIterator(ctx context.Context, primaryIter parquetquery.Iterator, c
 	// one either resource or span.
 	allConditions = allConditions && !mingledConditions
 
+	spanConditions = append(spanConditions, nestedSetConditions...)
+
 	spanIter, err := createSpanIterator(makeIter, primaryIter, spanConditions, spanRequireAtLeastOneMatch, allConditions)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating span iterator")
@@ -774,6 +835,8 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 		columnPredicates  = map[string][]parquetquery.Predicate{}
 		iters             []parquetquery.Iterator
 		genericConditions []traceql.Condition
+		eventConditions   []traceql.Condition
+		linkConditions    []traceql.Condition
 	)
 
 	addPredicate := func(columnPath string, p parquetquery.Predicate) {
@@ -781,6 +844,19 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 	}
 
 	for _, cond := range conditions {
+		// Events and links have their own iterators
+		switch {
+		case cond.Attribute.Scope == traceql.AttributeScopeEvent,
+			cond.Attribute.Intrinsic == traceql.IntrinsicEventName:
+			eventConditions = append(eventConditions, cond)
+			continue
+		case cond.Attribute.Scope == traceql.AttributeScopeLink,
+			cond.Attribute.Intrinsic == traceql.IntrinsicLinkTraceID,
+			cond.Attribute.Intrinsic == traceql.IntrinsicLinkSpanID:
+			linkConditions = append(linkConditions, cond)
+			continue
+		}
+
 		// Intrinsic?
 		switch cond.Attribute.Intrinsic {
 		case traceql.IntrinsicSpanID:
@@ -801,6 +877,13 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 			columnSelectAs[columnPathSpanStartTime] = columnPathSpanStartTime
 			continue
 
+		case traceql.IntrinsicNestedSetLeft, traceql.IntrinsicNestedSetRight, traceql.IntrinsicNestedSetParent:
+			// only used to evaluate structural operators, no filtering
+			columnPath := intrinsicColumnLookups[cond.Attribute.Intrinsic].columnPath
+			addPredicate(columnPath, nil)
+			columnSelectAs[columnPath] = columnPath
+			continue
+
 		case traceql.IntrinsicName:
 			pred, err := createStringPredicate(cond.Op, cond.Operands)
 			if err != nil {
@@ -871,6 +954,22 @@ func createSpanIterator(makeIter makeIterFn, primaryIter parquetquery.Iterator,
 		iters = append(iters, attrIter)
 	}
 
+	eventIter, err := createEventIterator(makeIter, eventConditions, allConditions)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating event iterator")
+	}
+	if eventIter != nil {
+		iters = append(iters, eventIter)
+	}
+
+	linkIter, err := createLinkIterator(makeIter, linkConditions, allConditions)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating link iterator")
+	}
+	if linkIter != nil {
+		iters = append(iters, linkIter)
+	}
+
 	for columnPath, predicates := range columnPredicates {
 		iters = append(iters, makeIter(columnPath, parquetquery.NewOrPredicate(predicates...), columnSelectAs[columnPath]))
 	}
@@ -1443,7 +1542,13 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	}
 
 	for _, e := range res.OtherEntries {
-		if e.Key == otherEntrySpanKey {
+		switch e.Key {
+		case otherEntrySpanKey:
+			continue
+		case otherEntryScopedAttrKey:
+			// attributes of events and links
+			a := e.Value.(scopedAttribute)
+			sp.attributes[a.attr] = a.val
 			continue
 		}
 		sp.attributes[newSpanAttr(e.Key)] = e.Value.(traceql.Static)
@@ -1458,6 +1563,12 @@ func (c *spanCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 			sp.id = kv.Value.ByteArray()
 		case columnPathSpanStartTime:
 			sp.startTimeUnixNanos = kv.Value.Uint64()
+		case columnPathSpanParentID:
+			sp.nestedSetParent = kv.Value.Int32()
+		case columnPathSpanNestedSetLeft:
+			sp.nestedSetLeft = kv.Value.Int32()
+		case columnPathSpanNestedSetRight:
+			sp.nestedSetRight = kv.Value.Int32()
 		case columnPathSpanDuration:
 			durationNanos = kv.Value.Uint64()
 			sp.durationNanos = durationNanos
@@ -1756,6 +1867,322 @@ func (c *attributeCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
 	return true
 }
 
+// createEventIterator iterates through the events of the spans and returns the values of the events
+// that match at least one of the given conditions, or all of them if allConditions is true.
+func createEventIterator(makeIter makeIterFn, conditions []traceql.Condition, allConditions bool) (parquetquery.Iterator, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var (
+		iters          []parquetquery.Iterator
+		namePreds      []parquetquery.Predicate
+		attrConditions []traceql.Condition
+	)
+	for _, cond := range conditions {
+		if cond.Attribute.Intrinsic == traceql.IntrinsicEventName {
+			pred, err := createStringPredicate(cond.Op, cond.Operands)
+			if err != nil {
+				return nil, errors.Wrap(err, "creating event name predicate")
+			}
+			namePreds = append(namePreds, pred)
+			continue
+		}
+		attrConditions = append(attrConditions, cond)
+	}
+
+	if len(namePreds) > 0 {
+		iters = append(iters, makeIter(columnPathEventName, parquetquery.NewOrPredicate(namePreds...), columnPathEventName))
+	}
+
+	if len(attrConditions) > 0 {
+		matcher, err := newAttributeMatcher(attrConditions)
+		if err != nil {
+			return nil, err
+		}
+
+		// Event attribute values are proto encoded and can't be filtered by column predicates.
+		// The collector decodes them and matches them against the conditions instead.
+		iters = append(iters, parquetquery.NewJoinIterator(DefinitionLevelResourceSpansILSSpanEventAttrs,
+			[]parquetquery.Iterator{
+				makeIter(columnPathEventAttrKey, parquetquery.NewStringInPredicate(matcher.names()), "key"),
+				makeIter(columnPathEventAttrValue, nil, "value"),
+			},
+			&eventAttributeCollector{matcher: matcher}))
+	}
+
+	if allConditions {
+		return parquetquery.NewJoinIterator(DefinitionLevelResourceSpansILSSpanEvent, iters,
+			&eventCollector{minAttributes: distinctAttributes(conditions)}), nil
+	}
+
+	return parquetquery.NewUnionIterator(DefinitionLevelResourceSpansILSSpanEvent, iters,
+		&eventCollector{minAttributes: 1}), nil
+}
+
+// createLinkIterator returns the values of the first link of each span that matches at least one of
+// the given conditions, or all of them if allConditions is true. Links are stored proto encoded in
+// a single column per span and are decoded and matched by the collector.
+func createLinkIterator(makeIter makeIterFn, conditions []traceql.Condition, allConditions bool) (parquetquery.Iterator, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	matcher, err := newAttributeMatcher(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	minAttributes := 1
+	if allConditions {
+		minAttributes = distinctAttributes(conditions)
+	}
+
+	return parquetquery.NewJoinIterator(DefinitionLevelResourceSpansILSSpan,
+		[]parquetquery.Iterator{makeIter(columnPathSpanLinks, nil, columnPathSpanLinks)},
+		&linkCollector{matcher: matcher, minAttributes: minAttributes}), nil
+}
+
+func distinctAttributes(conditions []traceql.Condition) int {
+	distinct := map[traceql.Attribute]struct{}{}
+	for _, cond := range conditions {
+		distinct[cond.Attribute] = struct{}{}
+	}
+	return len(distinct)
+}
+
+// scopedAttribute is a value of an event or link that is added to the attributes of its span
+type scopedAttribute struct {
+	attr traceql.Attribute
+	val  traceql.Static
+}
+
+// attributeMatcher matches values against conditions. It is used for values that can't
+// be filtered by column predicates like the proto encoded attributes of events and links.
+type attributeMatcher struct {
+	conditions map[traceql.Attribute][]attributeCondition
+}
+
+type attributeCondition struct {
+	typ  traceql.StaticType
+	pred parquetquery.Predicate // nil matches all values
+}
+
+func newAttributeMatcher(conditions []traceql.Condition) (*attributeMatcher, error) {
+	m := &attributeMatcher{conditions: map[traceql.Attribute][]attributeCondition{}}
+	for _, cond := range conditions {
+		pred, err := createPredicate(cond.Op, cond.Operands)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating attribute predicate")
+		}
+		m.conditions[cond.Attribute] = append(m.conditions[cond.Attribute], attributeCondition{
+			typ:  operandType(cond.Operands),
+			pred: pred,
+		})
+	}
+	return m, nil
+}
+
+// names returns the names of all non-intrinsic attributes
+func (m *attributeMatcher) names() []string {
+	names := make([]string, 0, len(m.conditions))
+	for a := range m.conditions {
+		if a.Intrinsic == traceql.IntrinsicNone {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// match returns true if the value matches at least one of the conditions of the attribute
+func (m *attributeMatcher) match(a traceql.Attribute, v traceql.Static) bool {
+	for _, c := range m.conditions[a] {
+		if c.pred == nil {
+			return true
+		}
+		if c.typ != v.Type {
+			continue
+		}
+
+		var pv parquet.Value
+		switch v.Type {
+		case traceql.TypeString:
+			pv = parquet.ByteArrayValue([]byte(v.S))
+		case traceql.TypeInt:
+			pv = parquet.Int64Value(int64(v.N))
+		case traceql.TypeFloat:
+			pv = parquet.DoubleValue(v.F)
+		case traceql.TypeBoolean:
+			pv = parquet.BooleanValue(v.B)
+		default:
+			continue
+		}
+
+		if c.pred.KeepValue(pv) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyValueToStatic converts the proto value of an event or link attribute. Arrays and
+// key value lists aren't supported.
+func anyValueToStatic(v *v1_common.AnyValue) (traceql.Static, bool) {
+	switch v := v.GetValue().(type) {
+	case *v1_common.AnyValue_StringValue:
+		return traceql.NewStaticString(v.StringValue), true
+	case *v1_common.AnyValue_IntValue:
+		return traceql.NewStaticInt(int(v.IntValue)), true
+	case *v1_common.AnyValue_DoubleValue:
+		return traceql.NewStaticFloat(v.DoubleValue), true
+	case *v1_common.AnyValue_BoolValue:
+		return traceql.NewStaticBool(v.BoolValue), true
+	}
+	return traceql.Static{}, false
+}
+
+// eventAttributeCollector decodes the proto encoded event attribute values and
+// only keeps the ones that match the conditions.
+type eventAttributeCollector struct {
+	matcher *attributeMatcher
+}
+
+var _ parquetquery.GroupPredicate = (*eventAttributeCollector)(nil)
+
+func (c *eventAttributeCollector) String() string {
+	return "eventAttributeCollector{}"
+}
+
+func (c *eventAttributeCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
+	var (
+		key   string
+		value []byte
+	)
+	for _, e := range res.Entries {
+		switch e.Key {
+		case "key":
+			key = e.Value.String()
+		case "value":
+			value = e.Value.ByteArray()
+		}
+	}
+
+	// event attributes are currently encoded as proto, but were previously json.
+	protoVal := &v1_common.AnyValue{}
+	if err := protoVal.Unmarshal(value); err != nil {
+		_ = jsonpb.Unmarshal(bytes.NewBuffer(value), protoVal)
+	}
+
+	val, ok := anyValueToStatic(protoVal)
+	if !ok || !c.matcher.match(newEventAttr(key), val) {
+		return false
+	}
+
+	res.Entries = res.Entries[:0]
+	res.OtherEntries = res.OtherEntries[:0]
+	res.AppendOtherValue(key, val)
+
+	return true
+}
+
+// eventCollector turns the values of an event into attributes of its span
+type eventCollector struct {
+	minAttributes int
+
+	// shared buffer used in KeepGroup, won't work if the collector is accessed concurrently
+	buffer []scopedAttribute
+}
+
+var _ parquetquery.GroupPredicate = (*eventCollector)(nil)
+
+func (c *eventCollector) String() string {
+	return fmt.Sprintf("eventCollector(%d)", c.minAttributes)
+}
+
+func (c *eventCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
+	c.buffer = c.buffer[:0]
+
+	for _, e := range res.Entries {
+		if e.Key == columnPathEventName {
+			c.buffer = append(c.buffer, scopedAttribute{
+				attr: traceql.NewIntrinsic(traceql.IntrinsicEventName),
+				val:  traceql.NewStaticString(e.Value.String()),
+			})
+		}
+	}
+	for _, e := range res.OtherEntries {
+		c.buffer = append(c.buffer, scopedAttribute{attr: newEventAttr(e.Key), val: e.Value.(traceql.Static)})
+	}
+
+	if len(c.buffer) < c.minAttributes {
+		return false
+	}
+
+	res.Entries = res.Entries[:0]
+	res.OtherEntries = res.OtherEntries[:0]
+	for _, a := range c.buffer {
+		res.AppendOtherValue(otherEntryScopedAttrKey, a)
+	}
+
+	return true
+}
+
+// linkCollector decodes the links of a span and turns the values of the first
+// matching link into attributes of its span
+type linkCollector struct {
+	matcher       *attributeMatcher
+	minAttributes int
+
+	// shared buffer used in KeepGroup, won't work if the collector is accessed concurrently
+	buffer []scopedAttribute
+}
+
+var _ parquetquery.GroupPredicate = (*linkCollector)(nil)
+
+func (c *linkCollector) String() string {
+	return fmt.Sprintf("linkCollector(%d)", c.minAttributes)
+}
+
+func (c *linkCollector) KeepGroup(res *parquetquery.IteratorResult) bool {
+	links := tempopb.LinkSlice{}
+	for _, e := range res.Entries {
+		if e.Key == columnPathSpanLinks {
+			if err := links.Unmarshal(e.Value.ByteArray()); err != nil {
+				return false
+			}
+		}
+	}
+
+	for _, l := range links.Links {
+		c.buffer = c.buffer[:0]
+
+		c.appendIfMatch(traceql.NewIntrinsic(traceql.IntrinsicLinkTraceID), traceql.NewStaticString(util.TraceIDToHexString(l.TraceId)))
+		c.appendIfMatch(traceql.NewIntrinsic(traceql.IntrinsicLinkSpanID), traceql.NewStaticString(util.SpanIDToHexString(l.SpanId)))
+		for _, a := range l.Attributes {
+			if val, ok := anyValueToStatic(a.Value); ok {
+				c.appendIfMatch(newLinkAttr(a.Key), val)
+			}
+		}
+
+		if len(c.buffer) > 0 && len(c.buffer) >= c.minAttributes {
+			res.Entries = res.Entries[:0]
+			res.OtherEntries = res.OtherEntries[:0]
+			for _, a := range c.buffer {
+				res.AppendOtherValue(otherEntryScopedAttrKey, a)
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *linkCollector) appendIfMatch(a traceql.Attribute, v traceql.Static) {
+	if c.matcher.match(a, v) {
+		c.buffer = append(c.buffer, scopedAttribute{attr: a, val: v})
+	}
+}
+
 func newSpanAttr(name string) traceql.Attribute {
 	return traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, name)
 }
@@ -1763,3 +2190,11 @@ func newSpanAttr(name string) traceql.Attribute {
 func newResAttr(name string) traceql.Attribute {
 	return traceql.NewScopedAttribute(traceql.AttributeScopeResource, false, name)
 }
+
+func newEventAttr(name string) traceql.Attribute {
+	return traceql.NewScopedAttribute(traceql.AttributeScopeEvent, false, name)
+}
+
+func newLinkAttr(name string) traceql.Attribute {
+	return traceql.NewScopedAttribute(traceql.AttributeScopeLink, false, name)
+}
@@ -14,9 +14,11 @@ import (
 
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/traceqlmetrics"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
@@ -91,6 +93,17 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = error}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = 2}`),
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = client }`),
+		// Events and links
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "e2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name =~ "exc.*"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.type = "NullPointerException"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.count > 1}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "exception" && event.exception.type = "NullPointerException"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "e1" || event.exception.type = "NullPointerException"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:spanID = "0000000000000002"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "value"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "1" && link.key =~ "val.*" && name = "hello"}`),
 		// Resource well-known attributes
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`), // Overridden at span
 		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelCluster + ` = "cluster"}`),
@@ -229,17 +242,25 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 	searchesThatDontMatch := []traceql.FetchSpansRequest{
 		// TODO - Should the below query return data or not?  It does match the resource
 		// makeReq(parse(t, `{.foo = "abc"}`)),                           // This should not return results because the span has overridden this attribute to "def".
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`),                                     // Regex IN
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ ".*"}`),                                        // String Not Regex
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.bool = true}`),                                    // Bool not match
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >  100s}`),                       // Intrinsic: duration
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = ok}`),                            // Intrinsic: status
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "nothello"}`),                      // Intrinsic: name
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = producer }`),                       // Intrinsic: kind
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "notmyservice"}`),          // Well-known attribute: service.name not match
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 200}`),                  // Well-known attribute: http.status_code not match
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` > 600}`),                  // Well-known attribute: http.status_code not match
-		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "xyz" || .` + LabelHTTPStatusCode + " = 1000}"), // Matches neither condition
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`),                                        // Regex IN
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ ".*"}`),                                           // String Not Regex
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.bool = true}`),                                       // Bool not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >  100s}`),                          // Intrinsic: duration
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = ok}`),                               // Intrinsic: status
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "nothello"}`),                         // Intrinsic: name
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = producer }`),                          // Intrinsic: kind
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "nope"}`),                                    // Event name
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.type = "Other"}`),                         // Event attribute
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.count = "2"}`),                            // Event attribute type mismatch
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.foo = "def"}`),                                      // Span attribute, not event
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "e2" && event.exception.count = 2}`),         // Different events
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "2"}`),                                     // Link trace ID
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "other"}`),                                     // Link attribute
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "value" && link:spanID = "0000000000000003"}`), // Link attribute and span ID
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "notmyservice"}`),             // Well-known attribute: service.name not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 200}`),                     // Well-known attribute: http.status_code not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` > 600}`),                     // Well-known attribute: http.status_code not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "xyz" || .` + LabelHTTPStatusCode + " = 1000}"),    // Matches neither condition
 		{
 			// Time range after trace
 			StartTimeUnixNanos: uint64(3000 * time.Second),
@@ -328,6 +349,285 @@ func TestBackendBlockSearchTraceQL(t *testing.T) {
 	}
 }
 
+func TestBackendBlockQueryRange(t *testing.T) {
+	tr := fullyPopulatedTestTrace(nil)
+	// move the span into the trace time range
+	tr.ResourceSpans[0].ScopeSpans[0].Spans[0].StartTimeUnixNano = uint64(1005 * time.Second)
+
+	b := makeBackendBlockWithTraces(t, []*Trace{tr})
+	ctx := context.Background()
+
+	req := &tempopb.QueryRangeRequest{
+		Query: `{ span.foo = "def" } | count_over_time() by(resource.service.name, span.bar)`,
+		Start: uint64(1000 * time.Second),
+		End:   uint64(1010 * time.Second),
+		Step:  uint64(5 * time.Second),
+	}
+
+	eval, err := traceql.NewEngine().CompileMetricsQueryRange(req)
+	require.NoError(t, err)
+
+	err = eval.Do(ctx, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	}))
+	require.NoError(t, err)
+
+	results := eval.Results()
+	require.Len(t, results, 1)
+	require.Equal(t, []float64{0, 1}, results[`{resource.service.name="myservice", span.bar="123"}`].Values)
+}
+
+func TestBackendBlockStructuralQueries(t *testing.T) {
+	// root
+	// ├── a
+	// │   ├── b (db)
+	// │   └── c
+	// └── d (db)
+	traceID := test.ValidTraceID(nil)
+	spanIDs := map[string][]byte{}
+	newSpan := func(name, parent string, db bool) *v1.Span {
+		spanIDs[name] = test.ValidTraceID(nil)[:8]
+		s := &v1.Span{
+			TraceId:           traceID,
+			SpanId:            spanIDs[name],
+			ParentSpanId:      spanIDs[parent],
+			Name:              name,
+			StartTimeUnixNano: uint64(1000 * time.Second),
+			EndTimeUnixNano:   uint64(1001 * time.Second),
+		}
+		if db {
+			s.Attributes = []*v1_common.KeyValue{{Key: "db.system", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "mysql"}}}}
+		}
+		return s
+	}
+	spans := []*v1.Span{
+		newSpan("root", "", false),
+		newSpan("a", "root", false),
+		newSpan("b", "a", true),
+		newSpan("c", "a", false),
+		newSpan("d", "root", true),
+	}
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{ScopeSpans: []*v1.ScopeSpans{{Spans: spans}}}}}
+
+	b := makeBackendBlockWithTraces(t, []*Trace{traceToParquet(traceID, tr, nil)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{`{ name = "a" } > { }`, []string{"b", "c"}},
+		{`{ name = "root" } >> { .db.system = "mysql" }`, []string{"b", "d"}},
+		{`{ name = "b" } < { }`, []string{"a"}},
+		{`{ .db.system = "mysql" } << { }`, []string{"root", "a"}},
+		{`{ name = "b" } ~ { }`, []string{"c"}},
+		{`{ name = "a" } !> { .db.system = "mysql" }`, []string{"d"}},
+		{`{ name = "a" } !>> { .db.system = "mysql" }`, []string{"d"}},
+		{`{ .db.system = "mysql" } !< { name != "b" && name != "d" }`, []string{"c"}},
+		{`{ .db.system = "mysql" } !<< { name = "a" || name = "c" }`, []string{"c"}},
+		{`{ name = "a" } !~ { name != "root" }`, []string{"a", "b", "c"}},
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, tc := range testCases {
+		resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, fetcher)
+		require.NoError(t, err, tc.query)
+
+		var actual []string
+		for _, tr := range resp.Traces {
+			for _, s := range tr.SpanSet.Spans {
+				for name, id := range spanIDs {
+					if util.SpanIDToHexString(id) == s.SpanID {
+						actual = append(actual, name)
+					}
+				}
+			}
+		}
+		require.ElementsMatch(t, tc.expected, actual, tc.query)
+	}
+}
+
+func TestBackendBlockSelect(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	rootID := []byte{1, 1, 1, 1, 1, 1, 1, 1}
+	childID := []byte{2, 2, 2, 2, 2, 2, 2, 2}
+	stringKV := func(k, v string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+	}
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{stringKV("service.name", "svc"), stringKV("pod", "pod-1")}},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+			{
+				TraceId:           traceID,
+				SpanId:            rootID,
+				Name:              "root",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+				Attributes:        []*v1_common.KeyValue{stringKV("http.url", "/a"), stringKV("foo", "bar")},
+			},
+			{
+				TraceId:           traceID,
+				SpanId:            childID,
+				ParentSpanId:      rootID,
+				Name:              "child",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+			},
+		}}},
+	}}}
+
+	b := makeBackendBlockWithTraces(t, []*Trace{traceToParquet(traceID, tr, nil)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query    string
+		expected map[string][]*v1_common.KeyValue // span ID -> attributes
+	}{
+		{
+			query: `{ } | select(span.http.url, resource.pod)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID):  {stringKV("http.url", "/a"), stringKV("pod", "pod-1")},
+				util.SpanIDToHexString(childID): {stringKV("pod", "pod-1")},
+			},
+		},
+		{
+			// attributes of the filter are returned as well
+			query: `{ name = "root" } | select(span.foo, status)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("foo", "bar"), stringKV("status", "unset")},
+			},
+		},
+		{
+			query: `{ .foo = "bar" } | select(.http.url)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("foo", "bar"), stringKV("http.url", "/a")},
+			},
+		},
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, tc := range testCases {
+		resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, fetcher)
+		require.NoError(t, err, tc.query)
+		require.Len(t, resp.Traces, 1, tc.query)
+
+		actual := map[string][]*v1_common.KeyValue{}
+		for _, s := range resp.Traces[0].SpanSet.Spans {
+			actual[s.SpanID] = s.Attributes
+		}
+		require.Equal(t, tc.expected, actual, tc.query)
+	}
+}
+
+func TestBackendBlockEventsAndLinks(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	linkedTraceID := test.ValidTraceID(nil)
+	rootID := []byte{1, 1, 1, 1, 1, 1, 1, 1}
+	childID := []byte{2, 2, 2, 2, 2, 2, 2, 2}
+	stringKV := func(k, v string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+	}
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{stringKV("service.name", "svc")}},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+			{
+				TraceId:           traceID,
+				SpanId:            rootID,
+				Name:              "root",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+				Events: []*v1.Span_Event{
+					{Name: "exception", Attributes: []*v1_common.KeyValue{stringKV("exception.type", "NullPointerException")}},
+					{Name: "retry", Attributes: []*v1_common.KeyValue{stringKV("reason", "timeout")}},
+				},
+				Links: []*v1.Span_Link{
+					{TraceId: linkedTraceID, SpanId: childID, Attributes: []*v1_common.KeyValue{stringKV("tenant", "other")}},
+				},
+			},
+			{
+				TraceId:           traceID,
+				SpanId:            childID,
+				ParentSpanId:      rootID,
+				Name:              "child",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+				Events: []*v1.Span_Event{
+					{Name: "exception", Attributes: []*v1_common.KeyValue{stringKV("exception.type", "Timeout")}},
+				},
+			},
+		}}},
+	}}}
+
+	b := makeBackendBlockWithTraces(t, []*Trace{traceToParquet(traceID, tr, nil)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query    string
+		expected map[string][]*v1_common.KeyValue // span ID -> attributes
+	}{
+		{
+			query: `{ event:name = "exception" }`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID):  {stringKV("event:name", "exception")},
+				util.SpanIDToHexString(childID): {stringKV("event:name", "exception")},
+			},
+		},
+		{
+			query: `{ event:name = "exception" && event.exception.type = "Timeout" }`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(childID): {stringKV("event.exception.type", "Timeout"), stringKV("event:name", "exception")},
+			},
+		},
+		{
+			// conditions are matched within a single event
+			query: `{ event:name = "retry" && event.exception.type = "NullPointerException" }`,
+		},
+		{
+			query: `{ link.tenant = "other" } | select(link:traceID)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("link.tenant", "other"), stringKV("link:traceID", util.TraceIDToHexString(linkedTraceID))},
+			},
+		},
+		{
+			query: `{ link:spanID = "` + util.SpanIDToHexString(childID) + `" && name = "root" }`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("link:spanID", util.SpanIDToHexString(childID))},
+			},
+		},
+		{
+			// unscoped attributes don't match events
+			query: `{ .exception.type = "Timeout" }`,
+		},
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, tc := range testCases {
+		resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, fetcher)
+		require.NoError(t, err, tc.query)
+
+		if tc.expected == nil {
+			require.Len(t, resp.Traces, 0, tc.query)
+			continue
+		}
+		require.Len(t, resp.Traces, 1, tc.query)
+
+		actual := map[string][]*v1_common.KeyValue{}
+		for _, s := range resp.Traces[0].SpanSet.Spans {
+			actual[s.SpanID] = s.Attributes
+		}
+		require.Equal(t, tc.expected, actual, tc.query)
+	}
+}
+
 func makeReq(conditions ...traceql.Condition) traceql.FetchSpansRequest {
 	return traceql.FetchSpansRequest{
 		Conditions: conditions,
@@ -375,6 +675,14 @@ func fullyPopulatedTestTrace(id common.ID) *Trace {
 		panic("failed to marshal links")
 	}
 
+	protoValue := func(v *v1_common.AnyValue) []byte {
+		b, err := v.Marshal()
+		if err != nil {
+			panic("failed to marshal event attribute")
+		}
+		return b
+	}
+
 	return &Trace{
 		TraceID:           test.ValidTraceID(id),
 		StartTimeUnixNano: uint64(1000 * time.Second),
@@ -434,6 +742,10 @@ func fullyPopulatedTestTrace(id common.ID) *Trace {
 										{Key: "bar", Value: []byte("fake proto encoded data. i hope this never matters")},
 									}},
 									{TimeUnixNano: 2, Name: "e2", Attrs: []EventAttribute{}},
+									{TimeUnixNano: 3, Name: "exception", Attrs: []EventAttribute{
+										{Key: "exception.type", Value: protoValue(&v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "NullPointerException"}})},
+										{Key: "exception.count", Value: protoValue(&v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: 2}})},
+									}},
 								},
 								Links: linkBytes,
 							},
@@ -0,0 +1,1099 @@
+package vparquet3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/traceqlmetrics"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestOne(t *testing.T) {
+	wantTr := fullyPopulatedTestTrace(nil)
+	b := makeBackendBlockWithTraces(t, []*Trace{wantTr})
+	ctx := context.Background()
+	req := traceql.MustExtractFetchSpansRequestWithMetadata(`{ span.foo = "bar" || duration > 1s }`)
+
+	req.StartTimeUnixNanos = uint64(1000 * time.Second)
+	req.EndTimeUnixNanos = uint64(1001 * time.Second)
+
+	resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+	require.NoError(t, err, "search request:", req)
+
+	spanSet, err := resp.Results.Next(ctx)
+	require.NoError(t, err, "search request:", req)
+
+	fmt.Println("-----------")
+	fmt.Println(resp.Results.(*spansetIterator).iter)
+	fmt.Println("-----------")
+	fmt.Println(spanSet)
+}
+
+func TestBackendBlockSearchTraceQL(t *testing.T) {
+	numTraces := 250
+	traces := make([]*Trace, 0, numTraces)
+	wantTraceIdx := rand.Intn(numTraces)
+	wantTraceID := test.ValidTraceID(nil)
+	for i := 0; i < numTraces; i++ {
+		if i == wantTraceIdx {
+			traces = append(traces, fullyPopulatedTestTrace(wantTraceID))
+			continue
+		}
+
+		id := test.ValidTraceID(nil)
+		tr := traceToParquet(&backend.BlockMeta{}, id, test.MakeTrace(1, id), nil)
+		traces = append(traces, tr)
+	}
+
+	b := makeBackendBlockWithTraces(t, traces)
+	ctx := context.Background()
+
+	searchesThatMatch := []traceql.FetchSpansRequest{
+		{}, // Empty request
+		{
+			// Time range inside trace
+			StartTimeUnixNanos: uint64(1100 * time.Second),
+			EndTimeUnixNanos:   uint64(1200 * time.Second),
+		},
+		{
+			// Time range overlap start
+			StartTimeUnixNanos: uint64(900 * time.Second),
+			EndTimeUnixNanos:   uint64(1100 * time.Second),
+		},
+		{
+			// Time range overlap end
+			StartTimeUnixNanos: uint64(1900 * time.Second),
+			EndTimeUnixNanos:   uint64(2100 * time.Second),
+		},
+		// Intrinsics
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "hello"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` =  100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >  99s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >= 100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` <  101s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` <= 100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` <= 100s}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = error}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = 2}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = client }`),
+		// Events and links
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "e2"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name =~ "exc.*"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.type = "NullPointerException"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.count > 1}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "exception" && event.exception.type = "NullPointerException"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "e1" || event.exception.type = "NullPointerException"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:spanID = "0000000000000002"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "value"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "1" && link.key =~ "val.*" && name = "hello"}`),
+		// Resource well-known attributes
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`), // Overridden at span
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelCluster + ` = "cluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelNamespace + ` = "namespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelPod + ` = "pod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelContainer + ` = "container"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sNamespaceName + ` = "k8snamespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sClusterName + ` = "k8scluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sPodName + ` = "k8spod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelK8sContainerName + ` = "k8scontainer"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` = "myservice"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelCluster + ` = "cluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelNamespace + ` = "namespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelPod + ` = "pod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelContainer + ` = "container"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sNamespaceName + ` = "k8snamespace"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sClusterName + ` = "k8scluster"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sPodName + ` = "k8spod"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelK8sContainerName + ` = "k8scontainer"}`),
+		// Comparing strings
+
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` > "myservic"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` >= "myservic"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` < "myservice1"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` <= "myservice1"}`),
+		// Span well-known attributes
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 500}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPMethod + ` = "get"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPUrl + ` = "url/hello/world"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.` + LabelHTTPStatusCode + ` = 500}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.` + LabelHTTPMethod + ` = "get"}`),
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.` + LabelHTTPUrl + ` = "url/hello/world"}`),
+		// Basic data types and operations
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float = 456.78}`),      // Float ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float != 456.79}`),     // Float !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float > 456.7}`),       // Float >
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float >= 456.78}`),     // Float >=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.float < 456.781}`),     // Float <
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bool = false}`),        // Bool ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bool != true}`),        // Bool !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar = 123}`),           // Int ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar != 124}`),          // Int !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar > 122}`),           // Int >
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar >= 123}`),          // Int >=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar < 124}`),           // Int <
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.bar <= 123}`),          // Int <=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "def"}`),         // String ==
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo != "deg"}`),        // String !=
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "d.*"}`),        // String Regex
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ "x.*"}`),        // String Not Regex
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.foo = "abc"}`), // Resource-level only
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.foo = "def"}`),     // Span-level only
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo}`),                 // Projection only
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.tags = "t1"}`),     // String array
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.codes > 400}`),         // Int array
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.res.tags}`),    // Resource-level array
+		makeReq(
+			// Matches either condition
+			parse(t, `{.foo = "baz"}`),
+			parse(t, `{.`+LabelHTTPStatusCode+` > 100}`),
+		),
+		makeReq(
+			// Same as above but reversed order
+			parse(t, `{.`+LabelHTTPStatusCode+` > 100}`),
+			parse(t, `{.foo = "baz"}`),
+		),
+		makeReq(
+			// Same attribute with mixed types
+			parse(t, `{.foo > 100}`),
+			parse(t, `{.foo = "def"}`),
+		),
+		makeReq(
+			// Multiple conditions on same well-known attribute, matches either
+			parse(t, `{.`+LabelHTTPStatusCode+` = 500}`),
+			parse(t, `{.`+LabelHTTPStatusCode+` > 500}`),
+		),
+		makeReq(
+			// Mix of duration with other conditions
+			parse(t, `{`+LabelName+` = "hello"}`),   // Match
+			parse(t, `{`+LabelDuration+` < 100s }`), // No match
+		),
+
+		// Edge cases
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.name = "Bob"}`),                                 // Almost conflicts with intrinsic but still works
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{resource.` + LabelServiceName + ` = 123}`),       // service.name doesn't match type of dedicated column
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "spanservicename"}`), // service.name present on span
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = "500ouch"}`),      // http.status_code doesn't match type of dedicated column
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "def"}`),
+		{
+			// Range at unscoped
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{.`+LabelHTTPStatusCode+` >= 500}`),
+				parse(t, `{.`+LabelHTTPStatusCode+` <= 600}`),
+			},
+		},
+		{
+			// Range at span scope
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{span.`+LabelHTTPStatusCode+` >= 500}`),
+				parse(t, `{span.`+LabelHTTPStatusCode+` <= 600}`),
+			},
+		},
+		{
+			// Range at resource scope
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.`+LabelServiceName+` >= 122}`),
+				parse(t, `{resource.`+LabelServiceName+` <= 124}`),
+			},
+		},
+	}
+
+	for _, req := range searchesThatMatch {
+		if req.SecondPass == nil {
+			req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+			req.SecondPassConditions = traceql.SearchMetaConditions()
+		}
+
+		resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+		require.NoError(t, err, "search request:%v", req)
+
+		found := false
+		for {
+			spanSet, err := resp.Results.Next(ctx)
+			require.NoError(t, err, "search request:%v", req)
+			if spanSet == nil {
+				break
+			}
+			found = bytes.Equal(spanSet.TraceID, wantTraceID)
+			if found {
+				break
+			}
+		}
+		require.True(t, found, "search request:%v", req)
+	}
+
+	searchesThatDontMatch := []traceql.FetchSpansRequest{
+		// TODO - Should the below query return data or not?  It does match the resource
+		// makeReq(parse(t, `{.foo = "abc"}`)),                           // This should not return results because the span has overridden this attribute to "def".
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo =~ "xyz.*"}`),                                        // Regex IN
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo !~ ".*"}`),                                           // String Not Regex
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{span.bool = true}`),                                       // Bool not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelDuration + ` >  100s}`),                          // Intrinsic: duration
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = ok}`),                               // Intrinsic: status
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "nothello"}`),                         // Intrinsic: name
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = producer }`),                          // Intrinsic: kind
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "nope"}`),                                    // Event name
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.type = "Other"}`),                         // Event attribute
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.exception.count = "2"}`),                            // Event attribute type mismatch
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event.foo = "def"}`),                                      // Span attribute, not event
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "e2" && event.exception.count = 2}`),         // Different events
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link:traceID = "2"}`),                                     // Link trace ID
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "other"}`),                                     // Link attribute
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{link.key = "value" && link:spanID = "0000000000000003"}`), // Link attribute and span ID
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelServiceName + ` = "notmyservice"}`),             // Well-known attribute: service.name not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` = 200}`),                     // Well-known attribute: http.status_code not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.` + LabelHTTPStatusCode + ` > 600}`),                     // Well-known attribute: http.status_code not match
+		traceql.MustExtractFetchSpansRequestWithMetadata(`{.foo = "xyz" || .` + LabelHTTPStatusCode + " = 1000}"),    // Matches neither condition
+		{
+			// Time range after trace
+			StartTimeUnixNanos: uint64(3000 * time.Second),
+			EndTimeUnixNanos:   uint64(4000 * time.Second),
+		},
+		{
+			// Time range before trace
+			StartTimeUnixNanos: uint64(600 * time.Second),
+			EndTimeUnixNanos:   uint64(700 * time.Second),
+		},
+		{
+			// Matches some conditions but not all
+			// Mix of span-level columns
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{span.foo = "baz"}`),                   // no match
+				parse(t, `{span.`+LabelHTTPStatusCode+` > 100}`), // match
+				parse(t, `{name = "hello"}`),                     // match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Only span generic attr lookups
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{span.foo = "baz"}`), // no match
+				parse(t, `{span.bar = 123}`),   // match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Mix of span and resource columns
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.cluster = "cluster"}`),     // match
+				parse(t, `{resource.namespace = "namespace"}`), // match
+				parse(t, `{span.foo = "baz"}`),                 // no match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Mix of resource columns
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.cluster = "notcluster"}`),  // no match
+				parse(t, `{resource.namespace = "namespace"}`), // match
+				parse(t, `{resource.foo = "abc"}`),             // match
+			},
+		},
+		{
+			// Matches some conditions but not all
+			// Only resource generic attr lookups
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{resource.foo = "abc"}`), // match
+				parse(t, `{resource.bar = 123}`),   // no match
+			},
+		},
+		{
+			// Mix of duration with other conditions
+			AllConditions: true,
+			Conditions: []traceql.Condition{
+				parse(t, `{`+LabelName+` = "nothello"}`), // No match
+				parse(t, `{`+LabelDuration+` = 100s }`),  // Match
+			},
+		},
+	}
+
+	for _, req := range searchesThatDontMatch {
+		if req.SecondPass == nil {
+			req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+			req.SecondPassConditions = traceql.SearchMetaConditions()
+		}
+
+		resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+		require.NoError(t, err, "search request:", req)
+
+		for {
+			spanSet, err := resp.Results.Next(ctx)
+			require.NoError(t, err, "search request:", req)
+			if spanSet == nil {
+				break
+			}
+			require.NotEqual(t, wantTraceID, spanSet.TraceID, "search request:", req)
+		}
+	}
+}
+
+func TestBackendBlockQueryRange(t *testing.T) {
+	tr := fullyPopulatedTestTrace(nil)
+	// move the span into the trace time range
+	tr.ResourceSpans[0].ScopeSpans[0].Spans[0].StartTimeUnixNano = uint64(1005 * time.Second)
+
+	b := makeBackendBlockWithTraces(t, []*Trace{tr})
+	ctx := context.Background()
+
+	req := &tempopb.QueryRangeRequest{
+		Query: `{ span.foo = "def" } | count_over_time() by(resource.service.name, span.bar)`,
+		Start: uint64(1000 * time.Second),
+		End:   uint64(1010 * time.Second),
+		Step:  uint64(5 * time.Second),
+	}
+
+	eval, err := traceql.NewEngine().CompileMetricsQueryRange(req)
+	require.NoError(t, err)
+
+	err = eval.Do(ctx, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	}))
+	require.NoError(t, err)
+
+	results := eval.Results()
+	require.Len(t, results, 1)
+	require.Equal(t, []float64{0, 1}, results[`{resource.service.name="myservice", span.bar="123"}`].Values)
+}
+
+func TestBackendBlockStructuralQueries(t *testing.T) {
+	// root
+	// ├── a
+	// │   ├── b (db)
+	// │   └── c
+	// └── d (db)
+	traceID := test.ValidTraceID(nil)
+	spanIDs := map[string][]byte{}
+	newSpan := func(name, parent string, db bool) *v1.Span {
+		spanIDs[name] = test.ValidTraceID(nil)[:8]
+		s := &v1.Span{
+			TraceId:           traceID,
+			SpanId:            spanIDs[name],
+			ParentSpanId:      spanIDs[parent],
+			Name:              name,
+			StartTimeUnixNano: uint64(1000 * time.Second),
+			EndTimeUnixNano:   uint64(1001 * time.Second),
+		}
+		if db {
+			s.Attributes = []*v1_common.KeyValue{{Key: "db.system", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "mysql"}}}}
+		}
+		return s
+	}
+	spans := []*v1.Span{
+		newSpan("root", "", false),
+		newSpan("a", "root", false),
+		newSpan("b", "a", true),
+		newSpan("c", "a", false),
+		newSpan("d", "root", true),
+	}
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{ScopeSpans: []*v1.ScopeSpans{{Spans: spans}}}}}
+
+	b := makeBackendBlockWithTraces(t, []*Trace{traceToParquet(&backend.BlockMeta{}, traceID, tr, nil)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{`{ name = "a" } > { }`, []string{"b", "c"}},
+		{`{ name = "root" } >> { .db.system = "mysql" }`, []string{"b", "d"}},
+		{`{ name = "b" } < { }`, []string{"a"}},
+		{`{ .db.system = "mysql" } << { }`, []string{"root", "a"}},
+		{`{ name = "b" } ~ { }`, []string{"c"}},
+		{`{ name = "a" } !> { .db.system = "mysql" }`, []string{"d"}},
+		{`{ name = "a" } !>> { .db.system = "mysql" }`, []string{"d"}},
+		{`{ .db.system = "mysql" } !< { name != "b" && name != "d" }`, []string{"c"}},
+		{`{ .db.system = "mysql" } !<< { name = "a" || name = "c" }`, []string{"c"}},
+		{`{ name = "a" } !~ { name != "root" }`, []string{"a", "b", "c"}},
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, tc := range testCases {
+		resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, fetcher)
+		require.NoError(t, err, tc.query)
+
+		var actual []string
+		for _, tr := range resp.Traces {
+			for _, s := range tr.SpanSet.Spans {
+				for name, id := range spanIDs {
+					if util.SpanIDToHexString(id) == s.SpanID {
+						actual = append(actual, name)
+					}
+				}
+			}
+		}
+		require.ElementsMatch(t, tc.expected, actual, tc.query)
+	}
+}
+
+func TestBackendBlockSelect(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	rootID := []byte{1, 1, 1, 1, 1, 1, 1, 1}
+	childID := []byte{2, 2, 2, 2, 2, 2, 2, 2}
+	stringKV := func(k, v string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+	}
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{stringKV("service.name", "svc"), stringKV("pod", "pod-1")}},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+			{
+				TraceId:           traceID,
+				SpanId:            rootID,
+				Name:              "root",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+				Attributes:        []*v1_common.KeyValue{stringKV("http.url", "/a"), stringKV("foo", "bar")},
+			},
+			{
+				TraceId:           traceID,
+				SpanId:            childID,
+				ParentSpanId:      rootID,
+				Name:              "child",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+			},
+		}}},
+	}}}
+
+	b := makeBackendBlockWithTraces(t, []*Trace{traceToParquet(&backend.BlockMeta{}, traceID, tr, nil)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query    string
+		expected map[string][]*v1_common.KeyValue // span ID -> attributes
+	}{
+		{
+			query: `{ } | select(span.http.url, resource.pod)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID):  {stringKV("http.url", "/a"), stringKV("pod", "pod-1")},
+				util.SpanIDToHexString(childID): {stringKV("pod", "pod-1")},
+			},
+		},
+		{
+			// attributes of the filter are returned as well
+			query: `{ name = "root" } | select(span.foo, status)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("foo", "bar"), stringKV("status", "unset")},
+			},
+		},
+		{
+			query: `{ .foo = "bar" } | select(.http.url)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("foo", "bar"), stringKV("http.url", "/a")},
+			},
+		},
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, tc := range testCases {
+		resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, fetcher)
+		require.NoError(t, err, tc.query)
+		require.Len(t, resp.Traces, 1, tc.query)
+
+		actual := map[string][]*v1_common.KeyValue{}
+		for _, s := range resp.Traces[0].SpanSet.Spans {
+			actual[s.SpanID] = s.Attributes
+		}
+		require.Equal(t, tc.expected, actual, tc.query)
+	}
+}
+
+func TestBackendBlockArrays(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	kv := func(k string, v *v1_common.AnyValue) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: k, Value: v}
+	}
+	strs := func(ss ...string) *v1_common.AnyValue {
+		arr := &v1_common.ArrayValue{}
+		for _, s := range ss {
+			arr.Values = append(arr.Values, &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: s}})
+		}
+		return &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: arr}}
+	}
+	ints := func(ns ...int64) *v1_common.AnyValue {
+		arr := &v1_common.ArrayValue{}
+		for _, n := range ns {
+			arr.Values = append(arr.Values, &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: n}})
+		}
+		return &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: arr}}
+	}
+	spanNames := map[string]string{}
+	newSpan := func(name string, id byte, attrs ...*v1_common.KeyValue) *v1.Span {
+		spanNames[util.SpanIDToHexString([]byte{id, 0, 0, 0, 0, 0, 0, 0})] = name
+		return &v1.Span{
+			TraceId:           traceID,
+			SpanId:            []byte{id, 0, 0, 0, 0, 0, 0, 0},
+			Name:              name,
+			StartTimeUnixNano: uint64(1000 * time.Second),
+			EndTimeUnixNano:   uint64(1001 * time.Second),
+			Attributes:        attrs,
+		}
+	}
+	spans := []*v1.Span{
+		newSpan("a", 1, kv("tags", strs("foo", "bar")), kv("codes", ints(200, 404))),
+		newSpan("b", 2, kv("tags", strs("baz")), kv("codes", ints(500)),
+			kv("flags", &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{Values: []*v1_common.AnyValue{
+				{Value: &v1_common.AnyValue_BoolValue{BoolValue: true}},
+			}}}})),
+		newSpan("c", 3, kv("tags", &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "foo"}}),
+			kv("ratios", &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{Values: []*v1_common.AnyValue{
+				{Value: &v1_common.AnyValue_DoubleValue{DoubleValue: 0.5}},
+				{Value: &v1_common.AnyValue_DoubleValue{DoubleValue: 1.5}},
+			}}}})),
+	}
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		Resource:   &v1_resource.Resource{Attributes: []*v1_common.KeyValue{kv("envs", strs("prod", "eu"))}},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+	}}}
+
+	b := makeBackendBlockWithTraces(t, []*Trace{traceToParquet(&backend.BlockMeta{}, traceID, tr, nil)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{`{ span.tags = "foo" }`, []string{"a", "c"}},
+		{`{ span.tags != "foo" }`, []string{"b"}},
+		{`{ span.tags =~ "ba.*" }`, []string{"a", "b"}},
+		{`{ span.tags = "foo" && span.tags = "bar" }`, []string{"a"}},
+		{`{ .codes >= 404 }`, []string{"a", "b"}},
+		{`{ len(span.codes) = 2 }`, []string{"a"}},
+		{`{ span.ratios > 1.0 }`, []string{"c"}},
+		{`{ span.flags = true }`, []string{"b"}},
+		{`{ resource.envs = "eu" && name = "b" }`, []string{"b"}},
+		{`{ resource.envs = "us" }`, nil},
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, tc := range testCases {
+		resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, fetcher)
+		require.NoError(t, err, tc.query)
+
+		var actual []string
+		for _, tr := range resp.Traces {
+			for _, s := range tr.SpanSet.Spans {
+				actual = append(actual, spanNames[s.SpanID])
+			}
+		}
+		require.ElementsMatch(t, tc.expected, actual, tc.query)
+	}
+
+	// arrays are returned by select()
+	resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: `{ name = "a" } | select(span.tags)`}, fetcher)
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 1)
+	require.Equal(t, []*v1_common.KeyValue{kv("tags", strs("foo", "bar"))}, resp.Traces[0].SpanSet.Spans[0].Attributes)
+}
+
+func TestBackendBlockEventsAndLinks(t *testing.T) {
+	traceID := test.ValidTraceID(nil)
+	linkedTraceID := test.ValidTraceID(nil)
+	rootID := []byte{1, 1, 1, 1, 1, 1, 1, 1}
+	childID := []byte{2, 2, 2, 2, 2, 2, 2, 2}
+	stringKV := func(k, v string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+	}
+	tr := &tempopb.Trace{Batches: []*v1.ResourceSpans{{
+		Resource: &v1_resource.Resource{Attributes: []*v1_common.KeyValue{stringKV("service.name", "svc")}},
+		ScopeSpans: []*v1.ScopeSpans{{Spans: []*v1.Span{
+			{
+				TraceId:           traceID,
+				SpanId:            rootID,
+				Name:              "root",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+				Events: []*v1.Span_Event{
+					{Name: "exception", Attributes: []*v1_common.KeyValue{stringKV("exception.type", "NullPointerException")}},
+					{Name: "retry", Attributes: []*v1_common.KeyValue{stringKV("reason", "timeout")}},
+				},
+				Links: []*v1.Span_Link{
+					{TraceId: linkedTraceID, SpanId: childID, Attributes: []*v1_common.KeyValue{stringKV("tenant", "other")}},
+				},
+			},
+			{
+				TraceId:           traceID,
+				SpanId:            childID,
+				ParentSpanId:      rootID,
+				Name:              "child",
+				StartTimeUnixNano: uint64(1000 * time.Second),
+				EndTimeUnixNano:   uint64(1001 * time.Second),
+				Events: []*v1.Span_Event{
+					{Name: "exception", Attributes: []*v1_common.KeyValue{stringKV("exception.type", "Timeout")}},
+				},
+			},
+		}}},
+	}}}
+
+	b := makeBackendBlockWithTraces(t, []*Trace{traceToParquet(&backend.BlockMeta{}, traceID, tr, nil)})
+	ctx := context.Background()
+
+	testCases := []struct {
+		query    string
+		expected map[string][]*v1_common.KeyValue // span ID -> attributes
+	}{
+		{
+			query: `{ event:name = "exception" }`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID):  {stringKV("event:name", "exception")},
+				util.SpanIDToHexString(childID): {stringKV("event:name", "exception")},
+			},
+		},
+		{
+			query: `{ event:name = "exception" && event.exception.type = "Timeout" }`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(childID): {stringKV("event.exception.type", "Timeout"), stringKV("event:name", "exception")},
+			},
+		},
+		{
+			// conditions are matched within a single event
+			query: `{ event:name = "retry" && event.exception.type = "NullPointerException" }`,
+		},
+		{
+			query: `{ link.tenant = "other" } | select(link:traceID)`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("link.tenant", "other"), stringKV("link:traceID", util.TraceIDToHexString(linkedTraceID))},
+			},
+		},
+		{
+			query: `{ link:spanID = "` + util.SpanIDToHexString(childID) + `" && name = "root" }`,
+			expected: map[string][]*v1_common.KeyValue{
+				util.SpanIDToHexString(rootID): {stringKV("link:spanID", util.SpanIDToHexString(childID))},
+			},
+		},
+		{
+			// unscoped attributes don't match events
+			query: `{ .exception.type = "Timeout" }`,
+		},
+	}
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, tc := range testCases {
+		resp, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, fetcher)
+		require.NoError(t, err, tc.query)
+
+		if tc.expected == nil {
+			require.Len(t, resp.Traces, 0, tc.query)
+			continue
+		}
+		require.Len(t, resp.Traces, 1, tc.query)
+
+		actual := map[string][]*v1_common.KeyValue{}
+		for _, s := range resp.Traces[0].SpanSet.Spans {
+			actual[s.SpanID] = s.Attributes
+		}
+		require.Equal(t, tc.expected, actual, tc.query)
+	}
+}
+
+func TestBackendBlockTraceSummary(t *testing.T) {
+	stringKV := func(k, v string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: k, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}}}
+	}
+	batch := func(service string, spans ...*v1.Span) *v1.ResourceSpans {
+		return &v1.ResourceSpans{
+			Resource:   &v1_resource.Resource{Attributes: []*v1_common.KeyValue{stringKV("service.name", service)}},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+		}
+	}
+	span := func(id, parent byte, name string) *v1.Span {
+		s := &v1.Span{
+			SpanId:            []byte{id, 0, 0, 0, 0, 0, 0, 0},
+			Name:              name,
+			StartTimeUnixNano: uint64(1000 * time.Second),
+			EndTimeUnixNano:   uint64(1001 * time.Second),
+		}
+		if parent != 0 {
+			s.ParentSpanId = []byte{parent, 0, 0, 0, 0, 0, 0, 0}
+		}
+		return s
+	}
+
+	// frontend:GET > backend:query > db:SELECT
+	id1 := test.ValidTraceID(nil)
+	tr1 := &tempopb.Trace{Batches: []*v1.ResourceSpans{
+		batch("frontend", span(1, 0, "GET")),
+		batch("backend", span(2, 1, "query")),
+		batch("db", span(3, 2, "SELECT")),
+	}}
+	// frontend:GET > backend:query
+	id2 := test.ValidTraceID(nil)
+	tr2 := &tempopb.Trace{Batches: []*v1.ResourceSpans{
+		batch("frontend", span(1, 0, "GET")),
+		batch("backend", span(2, 1, "query")),
+	}}
+
+	traces := []*Trace{
+		traceToParquet(&backend.BlockMeta{}, id1, tr1, nil),
+		traceToParquet(&backend.BlockMeta{}, id2, tr2, nil),
+	}
+	sort.Slice(traces, func(i, j int) bool { return bytes.Compare(traces[i].TraceID, traces[j].TraceID) < 0 })
+	b := makeBackendBlockWithTraces(t, traces)
+
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	testCases := []struct {
+		query    string
+		limit    int
+		expected map[string]int // path -> count
+	}{
+		{
+			query: `{}`,
+			expected: map[string]int{
+				"frontend:GET":                             2,
+				"frontend:GET > backend:query":             2,
+				"frontend:GET > backend:query > db:SELECT": 1,
+			},
+		},
+		{
+			// all spans of matching traces are aggregated
+			query: `{ resource.service.name = "db" }`,
+			expected: map[string]int{
+				"frontend:GET":                             1,
+				"frontend:GET > backend:query":             1,
+				"frontend:GET > backend:query > db:SELECT": 1,
+			},
+		},
+		{
+			query: `{ name = "query" } > { }`,
+			expected: map[string]int{
+				"frontend:GET":                             1,
+				"frontend:GET > backend:query":             1,
+				"frontend:GET > backend:query > db:SELECT": 1,
+			},
+		},
+		{
+			query:    `{ name = "nope" }`,
+			expected: map[string]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			res, err := traceqlmetrics.GetTraceSummary(context.Background(), tc.query, tc.limit, 0, 0, fetcher)
+			require.NoError(t, err)
+
+			actual := map[string]int{}
+			for path, n := range res.Nodes {
+				actual[path] = n.Latency.Count()
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	res, err := traceqlmetrics.GetTraceSummary(context.Background(), `{}`, 1, 0, 0, fetcher)
+	require.NoError(t, err)
+	require.Equal(t, 1, res.TraceCount)
+	require.True(t, res.Estimated)
+}
+
+func makeReq(conditions ...traceql.Condition) traceql.FetchSpansRequest {
+	return traceql.FetchSpansRequest{
+		Conditions: conditions,
+		SecondPass: func(s *traceql.Spanset) ([]*traceql.Spanset, error) {
+			return []*traceql.Spanset{s}, nil
+		},
+		SecondPassConditions: traceql.SearchMetaConditions(),
+	}
+}
+
+func parse(t *testing.T, q string) traceql.Condition {
+
+	req, err := traceql.ExtractFetchSpansRequest(q)
+	require.NoError(t, err, "query:", q)
+
+	return req.Conditions[0]
+}
+
+func fullyPopulatedTestTrace(id common.ID) *Trace {
+	// Helper functions to make pointers
+	strPtr := func(s string) *string { return &s }
+	intPtr := func(i int64) *int64 { return &i }
+	fltPtr := func(f float64) *float64 { return &f }
+	boolPtr := func(b bool) *bool { return &b }
+
+	links := tempopb.LinkSlice{
+		Links: []*v1.Span_Link{
+			{
+				TraceId:                []byte{0x01},
+				SpanId:                 []byte{0x02},
+				TraceState:             "state",
+				DroppedAttributesCount: 3,
+				Attributes: []*v1_common.KeyValue{
+					{
+						Key:   "key",
+						Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "value"}},
+					},
+				},
+			},
+		},
+	}
+	linkBytes := make([]byte, links.Size())
+	_, err := links.MarshalTo(linkBytes)
+	if err != nil {
+		panic("failed to marshal links")
+	}
+
+	protoValue := func(v *v1_common.AnyValue) []byte {
+		b, err := v.Marshal()
+		if err != nil {
+			panic("failed to marshal event attribute")
+		}
+		return b
+	}
+
+	return &Trace{
+		TraceID:           test.ValidTraceID(id),
+		StartTimeUnixNano: uint64(1000 * time.Second),
+		EndTimeUnixNano:   uint64(2000 * time.Second),
+		DurationNano:      uint64((100 * time.Millisecond).Nanoseconds()),
+		RootServiceName:   "RootService",
+		RootSpanName:      "RootSpan",
+		ResourceSpans: []ResourceSpans{
+			{
+				Resource: Resource{
+					ServiceName:      "myservice",
+					Cluster:          strPtr("cluster"),
+					Namespace:        strPtr("namespace"),
+					Pod:              strPtr("pod"),
+					Container:        strPtr("container"),
+					K8sClusterName:   strPtr("k8scluster"),
+					K8sNamespaceName: strPtr("k8snamespace"),
+					K8sPodName:       strPtr("k8spod"),
+					K8sContainerName: strPtr("k8scontainer"),
+					Attrs: []Attribute{
+						{Key: "foo", Value: strPtr("abc")},
+						{Key: LabelServiceName, ValueInt: intPtr(123)}, // Different type than dedicated column
+						{Key: "res.tags", ValueStringArray: []string{"r1", "r2"}},
+					},
+				},
+				ScopeSpans: []ScopeSpans{
+					{
+						Spans: []Span{
+							{
+								SpanID:                 []byte("spanid"),
+								Name:                   "hello",
+								StartTimeUnixNano:      uint64(100 * time.Second),
+								DurationNano:           uint64(100 * time.Second),
+								HttpMethod:             strPtr("get"),
+								HttpUrl:                strPtr("url/hello/world"),
+								HttpStatusCode:         intPtr(500),
+								ParentSpanID:           []byte{},
+								StatusCode:             int(v1.Status_STATUS_CODE_ERROR),
+								StatusMessage:          v1.Status_STATUS_CODE_ERROR.String(),
+								TraceState:             "tracestate",
+								Kind:                   int(v1.Span_SPAN_KIND_CLIENT),
+								DroppedAttributesCount: 42,
+								DroppedEventsCount:     43,
+								Attrs: []Attribute{
+									{Key: "foo", Value: strPtr("def")},
+									{Key: "bar", ValueInt: intPtr(123)},
+									{Key: "float", ValueDouble: fltPtr(456.78)},
+									{Key: "bool", ValueBool: boolPtr(false)},
+									{Key: "tags", ValueStringArray: []string{"t1", "t2"}},
+									{Key: "codes", ValueIntArray: []int64{200, 404}},
+
+									// Edge-cases
+									{Key: LabelName, Value: strPtr("Bob")},                    // Conflicts with intrinsic but still looked up by .name
+									{Key: LabelServiceName, Value: strPtr("spanservicename")}, // Overrides resource-level dedicated column
+									{Key: LabelHTTPStatusCode, Value: strPtr("500ouch")},      // Different type than dedicated column
+								},
+								Events: []Event{
+									{TimeUnixNano: 1, Name: "e1", Attrs: []EventAttribute{
+										{Key: "foo", Value: []byte("fake proto encoded data. i hope this never matters")},
+										{Key: "bar", Value: []byte("fake proto encoded data. i hope this never matters")},
+									}},
+									{TimeUnixNano: 2, Name: "e2", Attrs: []EventAttribute{}},
+									{TimeUnixNano: 3, Name: "exception", Attrs: []EventAttribute{
+										{Key: "exception.type", Value: protoValue(&v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "NullPointerException"}})},
+										{Key: "exception.count", Value: protoValue(&v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: 2}})},
+									}},
+								},
+								Links: linkBytes,
+							},
+						},
+					},
+				},
+			},
+			{
+				Resource: Resource{
+					ServiceName: "service2",
+				},
+				ScopeSpans: []ScopeSpans{
+					{
+						Spans: []Span{
+							{
+								SpanID: []byte("spanid2"),
+								Name:   "world",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func BenchmarkBackendBlockTraceQL(b *testing.B) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		// span
+		{"spanAttNameNoMatch", "{ span.foo = `bar` }"},
+		{"spanAttValNoMatch", "{ span.bloom = `bar` }"},
+		{"spanAttValMatch", "{ span.bloom > 0 }"},
+		{"spanAttIntrinsicNoMatch", "{ name = `asdfasdf` }"},
+		{"spanAttIntrinsicMatch", "{ name = `gcs.ReadRange` }"},
+		{"spanAttIntrinsicRegexNoMatch", "{ name =~ `asdfasdf` }"},
+		{"spanAttIntrinsicRegexMatch", "{ name =~ `gcs.ReadRange` }"},
+
+		// resource
+		{"resourceAttNameNoMatch", "{ resource.foo = `bar` }"},
+		{"resourceAttValNoMatch", "{ resource.module.path = `bar` }"},
+		{"resourceAttValMatch", "{ resource.os.type = `linux` }"},
+		{"resourceAttIntrinsicNoMatch", "{ resource.service.name = `a` }"},
+		{"resourceAttIntrinsicMatch", "{ resource.service.name = `tempo-query-frontend` }"},
+
+		// mixed
+		{"mixedNameNoMatch", "{ .foo = `bar` }"},
+		{"mixedValNoMatch", "{ .bloom = `bar` }"},
+		{"mixedValMixedMatchAnd", "{ resource.foo = `bar` && name = `gcs.ReadRange` }"},
+		{"mixedValMixedMatchOr", "{ resource.foo = `bar` || name = `gcs.ReadRange` }"},
+		{"mixedValBothMatch", "{ resource.service.name = `query-frontend` && name = `gcs.ReadRange` }"},
+	}
+
+	ctx := context.TODO()
+	tenantID := "1"
+	blockID := uuid.MustParse("000d37d0-1e66-4f4e-bbd4-f85c1deb6e5e")
+
+	r, _, _, err := local.New(&local.Config{
+		Path: path.Join("/home/joe/testblock/"),
+	})
+	require.NoError(b, err)
+
+	rr := backend.NewReader(r)
+	meta, err := rr.BlockMeta(ctx, blockID, tenantID)
+	require.NoError(b, err)
+
+	opts := common.DefaultSearchOptions()
+	opts.StartPage = 10
+	opts.TotalPages = 10
+
+	block := newBackendBlock(meta, rr)
+	_, _, err = block.openForSearch(ctx, opts)
+	require.NoError(b, err)
+
+	for _, tc := range testCases {
+
+		b.Run(tc.name, func(b *testing.B) {
+			b.ResetTimer()
+			bytesRead := 0
+
+			for i := 0; i < b.N; i++ {
+				e := traceql.NewEngine()
+
+				resp, err := e.ExecuteSearch(ctx, &tempopb.SearchRequest{Query: tc.query}, traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+					return block.Fetch(ctx, req, opts)
+				}))
+				require.NoError(b, err)
+				require.NotNil(b, resp)
+
+				// Read first 20 results (if any)
+				bytesRead += int(resp.Metrics.InspectedBytes)
+			}
+			b.SetBytes(int64(bytesRead) / int64(b.N))
+			b.ReportMetric(float64(bytesRead)/float64(b.N)/1000.0/1000.0, "MB_io/op")
+		})
+	}
+}
+
+// BenchmarkBackendBlockGetMetrics This doesn't really belong here but I can't think of
+// a better place that has access to all of the packages, especially the backend.
+func BenchmarkBackendBlockGetMetrics(b *testing.B) {
+	testCases := []struct {
+		query   string
+		groupby string
+	}{
+		//{"{ resource.service.name = `gme-ingester` }", "resource.cluster"},
+		{"{}", "name"},
+	}
+
+	ctx := context.TODO()
+	tenantID := "1"
+	blockID := uuid.MustParse("2968a567-5873-4e4c-b3cb-21c106c6714b")
+
+	r, _, _, err := local.New(&local.Config{
+		Path: path.Join("/Users/marty/src/tmp/"),
+	})
+	require.NoError(b, err)
+
+	rr := backend.NewReader(r)
+	meta, err := rr.BlockMeta(ctx, blockID, tenantID)
+	require.NoError(b, err)
+
+	opts := common.DefaultSearchOptions()
+	opts.StartPage = 10
+	opts.TotalPages = 10
+
+	block := newBackendBlock(meta, rr)
+	_, _, err = block.openForSearch(ctx, opts)
+	require.NoError(b, err)
+
+	for _, tc := range testCases {
+
+		b.Run(tc.query+"/"+tc.groupby, func(b *testing.B) {
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				f := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+					return block.Fetch(ctx, req, opts)
+				})
+
+				r, err := traceqlmetrics.GetMetrics(ctx, tc.query, tc.groupby, 0, 0, 0, f)
+
+				require.NoError(b, err)
+				require.NotNil(b, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package vparquet3
+
+import (
+	"fmt"
+
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// Column paths for spare dedicated attribute columns
+var dedicatedColumnPaths = map[backend.DedicatedColumnScope]map[backend.DedicatedColumnType][]string{
+	backend.DedicatedColumnScopeResource: {
+		backend.DedicatedColumnTypeString: {
+			"rs.list.element.Resource.DedicatedAttributes.String01",
+			"rs.list.element.Resource.DedicatedAttributes.String02",
+			"rs.list.element.Resource.DedicatedAttributes.String03",
+			"rs.list.element.Resource.DedicatedAttributes.String04",
+			"rs.list.element.Resource.DedicatedAttributes.String05",
+			"rs.list.element.Resource.DedicatedAttributes.String06",
+			"rs.list.element.Resource.DedicatedAttributes.String07",
+			"rs.list.element.Resource.DedicatedAttributes.String08",
+			"rs.list.element.Resource.DedicatedAttributes.String09",
+			"rs.list.element.Resource.DedicatedAttributes.String10",
+		},
+	},
+	backend.DedicatedColumnScopeSpan: {
+		backend.DedicatedColumnTypeString: {
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String01",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String02",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String03",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String04",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String05",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String06",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String07",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String08",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String09",
+			"rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String10",
+		},
+	},
+}
+
+// ValidateDedicatedColumns returns an error if the given dedicated columns can't be stored in a
+// vParquet3 block, either because they are invalid or because a scope exceeds the available columns.
+func ValidateDedicatedColumns(dedicatedColumns backend.DedicatedColumns) error {
+	if err := dedicatedColumns.Validate(); err != nil {
+		return err
+	}
+
+	counts := map[backend.DedicatedColumnScope]map[backend.DedicatedColumnType]int{}
+	for _, dc := range dedicatedColumns {
+		if counts[dc.Scope] == nil {
+			counts[dc.Scope] = map[backend.DedicatedColumnType]int{}
+		}
+		counts[dc.Scope][dc.Type]++
+		if max := len(dedicatedColumnPaths[dc.Scope][dc.Type]); counts[dc.Scope][dc.Type] > max {
+			return fmt.Errorf("number of dedicated columns with scope %s and type %s exceeds the maximum of %d", dc.Scope, dc.Type, max)
+		}
+	}
+	return nil
+}
+
+// dedicatedColumn is a column that stores the values of one attribute
+type dedicatedColumn struct {
+	Type        backend.DedicatedColumnType
+	ColumnPath  string
+	ColumnIndex int
+}
+
+func (dc *dedicatedColumn) staticType() traceql.StaticType {
+	switch dc.Type {
+	case backend.DedicatedColumnTypeString:
+		return traceql.TypeString
+	default:
+		return traceql.TypeNil
+	}
+}
+
+// readValue returns the value stored in the column or nil if the attribute isn't set
+func (dc *dedicatedColumn) readValue(attrs *DedicatedAttributes) *v1.AnyValue {
+	switch dc.Type {
+	case backend.DedicatedColumnTypeString:
+		strVal := attrs.stringColumn(dc.ColumnIndex)
+		if strVal == nil || *strVal == nil {
+			return nil
+		}
+		return &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: **strVal}}
+	default:
+		return nil
+	}
+}
+
+// writeValue stores the value in the column and returns true. Values of a type
+// that doesn't match the column aren't written and false is returned.
+func (dc *dedicatedColumn) writeValue(attrs *DedicatedAttributes, value *v1.AnyValue) bool {
+	switch dc.Type {
+	case backend.DedicatedColumnTypeString:
+		strVal, ok := value.Value.(*v1.AnyValue_StringValue)
+		if !ok {
+			return false
+		}
+		col := attrs.stringColumn(dc.ColumnIndex)
+		if col == nil {
+			return false
+		}
+		*col = &strVal.StringValue
+		return true
+	default:
+		return false
+	}
+}
+
+// dedicatedColumnMapping maps attribute names to dedicated columns while preserving the
+// order in which they were configured.
+type dedicatedColumnMapping struct {
+	mapping map[string]dedicatedColumn
+	keys    []string
+}
+
+func newDedicatedColumnMapping(size int) dedicatedColumnMapping {
+	return dedicatedColumnMapping{
+		mapping: make(map[string]dedicatedColumn, size),
+		keys:    make([]string, 0, size),
+	}
+}
+
+func (dm *dedicatedColumnMapping) put(attr string, col dedicatedColumn) {
+	if _, ok := dm.mapping[attr]; !ok {
+		dm.keys = append(dm.keys, attr)
+	}
+	dm.mapping[attr] = col
+}
+
+func (dm dedicatedColumnMapping) get(attr string) (dedicatedColumn, bool) {
+	col, ok := dm.mapping[attr]
+	return col, ok
+}
+
+func (dm dedicatedColumnMapping) forEach(callback func(attr string, column dedicatedColumn)) {
+	for _, k := range dm.keys {
+		callback(k, dm.mapping[k])
+	}
+}
+
+// dedicatedColumnsToColumnMapping returns the mapping of the dedicated columns with the given scope.
+// Columns are assigned in the order they are configured. Columns exceeding the available spare
+// columns are ignored.
+func dedicatedColumnsToColumnMapping(dedicatedColumns backend.DedicatedColumns, scope backend.DedicatedColumnScope) dedicatedColumnMapping {
+	mapping := newDedicatedColumnMapping(len(dedicatedColumns))
+	indexByType := map[backend.DedicatedColumnType]int{}
+
+	for _, c := range dedicatedColumns {
+		if c.Scope != scope {
+			continue
+		}
+
+		paths := dedicatedColumnPaths[c.Scope][c.Type]
+		i := indexByType[c.Type]
+		if i >= len(paths) {
+			continue
+		}
+		indexByType[c.Type]++
+
+		mapping.put(c.Name, dedicatedColumn{
+			Type:        c.Type,
+			ColumnPath:  paths[i],
+			ColumnIndex: i,
+		})
+	}
+
+	return mapping
+}
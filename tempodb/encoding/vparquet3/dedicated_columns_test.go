@@ -0,0 +1,262 @@
+package vparquet3
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var testDedicatedColumns = backend.DedicatedColumns{
+	{Scope: backend.DedicatedColumnScopeResource, Name: "dedicated.resource.1", Type: backend.DedicatedColumnTypeString},
+	{Scope: backend.DedicatedColumnScopeResource, Name: "dedicated.resource.2", Type: backend.DedicatedColumnTypeString},
+	{Scope: backend.DedicatedColumnScopeSpan, Name: "dedicated.span.1", Type: backend.DedicatedColumnTypeString},
+}
+
+func TestDedicatedColumnsToColumnMapping(t *testing.T) {
+	resMapping := dedicatedColumnsToColumnMapping(testDedicatedColumns, backend.DedicatedColumnScopeResource)
+	spanMapping := dedicatedColumnsToColumnMapping(testDedicatedColumns, backend.DedicatedColumnScopeSpan)
+
+	var resAttrs []string
+	resMapping.forEach(func(attr string, _ dedicatedColumn) { resAttrs = append(resAttrs, attr) })
+	assert.Equal(t, []string{"dedicated.resource.1", "dedicated.resource.2"}, resAttrs)
+
+	col, ok := resMapping.get("dedicated.resource.2")
+	require.True(t, ok)
+	assert.Equal(t, dedicatedColumn{
+		Type:        backend.DedicatedColumnTypeString,
+		ColumnPath:  "rs.list.element.Resource.DedicatedAttributes.String02",
+		ColumnIndex: 1,
+	}, col)
+
+	col, ok = spanMapping.get("dedicated.span.1")
+	require.True(t, ok)
+	assert.Equal(t, dedicatedColumn{
+		Type:        backend.DedicatedColumnTypeString,
+		ColumnPath:  "rs.list.element.ss.list.element.Spans.list.element.DedicatedAttributes.String01",
+		ColumnIndex: 0,
+	}, col)
+
+	_, ok = spanMapping.get("dedicated.resource.1")
+	assert.False(t, ok)
+}
+
+func TestValidateDedicatedColumns(t *testing.T) {
+	require.NoError(t, ValidateDedicatedColumns(nil))
+	require.NoError(t, ValidateDedicatedColumns(testDedicatedColumns))
+
+	tooMany := backend.DedicatedColumns{}
+	for i := 0; i < 11; i++ {
+		tooMany = append(tooMany, backend.DedicatedColumn{Scope: backend.DedicatedColumnScopeSpan, Name: uuid.NewString(), Type: backend.DedicatedColumnTypeString})
+	}
+	require.Error(t, ValidateDedicatedColumns(tooMany))
+	require.NoError(t, ValidateDedicatedColumns(tooMany[:10]))
+
+	require.Error(t, ValidateDedicatedColumns(backend.DedicatedColumns{{Scope: "event", Name: "foo", Type: backend.DedicatedColumnTypeString}}))
+}
+
+func TestProtoParquetRoundTripDedicatedColumns(t *testing.T) {
+	meta := &backend.BlockMeta{DedicatedColumns: testDedicatedColumns}
+
+	id := test.ValidTraceID(nil)
+	expectedTrace := makeTraceWithDedicatedAttributes(id)
+
+	parquetTrace := traceToParquet(meta, id, expectedTrace, nil)
+
+	// values are stored in the dedicated columns and not as generic attributes
+	rs := parquetTrace.ResourceSpans[0]
+	require.NotNil(t, rs.Resource.DedicatedAttributes.String01)
+	assert.Equal(t, "res1", *rs.Resource.DedicatedAttributes.String01)
+	require.NotNil(t, rs.Resource.DedicatedAttributes.String02)
+	assert.Equal(t, "res2", *rs.Resource.DedicatedAttributes.String02)
+	for _, a := range rs.Resource.Attrs {
+		assert.NotContains(t, a.Key, "dedicated")
+	}
+
+	span := rs.ScopeSpans[0].Spans[0]
+	require.NotNil(t, span.DedicatedAttributes.String01)
+	assert.Equal(t, "span1", *span.DedicatedAttributes.String01)
+	for _, a := range span.Attrs {
+		assert.NotContains(t, a.Key, "dedicated")
+	}
+
+	// values with a type that doesn't match the dedicated column are stored as generic attributes
+	span = rs.ScopeSpans[0].Spans[1]
+	assert.Nil(t, span.DedicatedAttributes.String01)
+	found := false
+	for _, a := range span.Attrs {
+		if a.Key == "dedicated.span.1" {
+			require.NotNil(t, a.ValueInt)
+			assert.Equal(t, int64(123), *a.ValueInt)
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	actualTrace := parquetTraceToTempopbTrace(meta, parquetTrace)
+	assert.Equal(t, expectedTrace, actualTrace)
+}
+
+func TestBackendBlockDedicatedColumns(t *testing.T) {
+	id := test.ValidTraceID(nil)
+	meta := &backend.BlockMeta{DedicatedColumns: testDedicatedColumns}
+
+	otherID := test.ValidTraceID(nil)
+	traces := []*Trace{
+		traceToParquet(meta, id, makeTraceWithDedicatedAttributes(id), nil),
+		traceToParquet(meta, otherID, test.MakeTrace(1, otherID), nil),
+	}
+	b := makeBackendBlockWithTracesAndDedicatedColumns(t, traces, testDedicatedColumns)
+	ctx := context.Background()
+
+	t.Run("traceql", func(t *testing.T) {
+		searchesThatMatch := []string{
+			`{resource.dedicated.resource.1 = "res1"}`,
+			`{resource.dedicated.resource.2 =~ "res.*"}`,
+			`{.dedicated.resource.2 = "res2"}`,
+			`{span.dedicated.span.1 = "span1"}`,
+			`{.dedicated.span.1 = "span1"}`,
+			`{span.dedicated.span.1 = 123}`, // type doesn't match the column, stored as generic attribute
+			`{span.dedicated.span.1 = "span1" && resource.dedicated.resource.1 = "res1"}`,
+		}
+		searchesThatDontMatch := []string{
+			`{resource.dedicated.resource.1 = "res2"}`,
+			`{span.dedicated.span.1 = "res1"}`,
+			`{resource.dedicated.span.1 = "span1"}`,
+			`{span.dedicated.span.1 = "span1" && resource.dedicated.resource.1 = "nope"}`,
+		}
+
+		for _, q := range searchesThatMatch {
+			ss := fetchAll(t, b, q)
+			require.Len(t, ss, 1, q)
+			require.Equal(t, []byte(id), ss[0].TraceID, q)
+		}
+		for _, q := range searchesThatDontMatch {
+			require.Empty(t, fetchAll(t, b, q), q)
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		resp, err := b.Search(ctx, &tempopb.SearchRequest{Tags: map[string]string{"dedicated.span.1": "span1"}, Limit: 10}, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.Len(t, resp.Traces, 1)
+		require.Equal(t, util.TraceIDToHexString(id), resp.Traces[0].TraceID)
+
+		resp, err = b.Search(ctx, &tempopb.SearchRequest{Tags: map[string]string{"dedicated.resource.1": "nope"}, Limit: 10}, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.Len(t, resp.Traces, 0)
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		for scope, expected := range map[traceql.AttributeScope][]string{
+			traceql.AttributeScopeResource: {"dedicated.resource.1", "dedicated.resource.2"},
+			traceql.AttributeScopeSpan:     {"dedicated.span.1"},
+		} {
+			found := map[string]struct{}{}
+			err := b.SearchTags(ctx, scope, func(s string) { found[s] = struct{}{} }, common.DefaultSearchOptions())
+			require.NoError(t, err)
+			for _, tag := range expected {
+				assert.Contains(t, found, tag, scope)
+			}
+		}
+	})
+
+	t.Run("tag values", func(t *testing.T) {
+		testCases := []struct {
+			tag  string
+			vals []traceql.Static
+		}{
+			{"resource.dedicated.resource.1", []traceql.Static{traceql.NewStaticString("res1")}},
+			{".dedicated.resource.2", []traceql.Static{traceql.NewStaticString("res2")}},
+			{"span.dedicated.span.1", []traceql.Static{traceql.NewStaticString("span1"), traceql.NewStaticInt(123)}},
+		}
+
+		for _, tc := range testCases {
+			var got []traceql.Static
+			cb := func(v traceql.Static) bool {
+				got = append(got, v)
+				return false
+			}
+
+			err := b.SearchTagValuesV2(ctx, traceql.MustParseIdentifier(tc.tag), cb, common.DefaultSearchOptions())
+			require.NoError(t, err, tc.tag)
+			require.ElementsMatch(t, tc.vals, got, tc.tag)
+		}
+	})
+}
+
+func TestCompactorDifferentDedicatedColumns(t *testing.T) {
+	c := NewCompactor(common.CompactionOptions{})
+	inputs := []*backend.BlockMeta{
+		{BlockID: uuid.New(), DedicatedColumns: testDedicatedColumns},
+		{BlockID: uuid.New(), DedicatedColumns: testDedicatedColumns[:1]},
+	}
+
+	_, err := c.Compact(context.Background(), nil, nil, nil, inputs)
+	require.ErrorContains(t, err, "different dedicated columns")
+}
+
+func fetchAll(t *testing.T, b *backendBlock, q string) []*traceql.Spanset {
+	ctx := context.Background()
+	req := traceql.MustExtractFetchSpansRequestWithMetadata(q)
+	req.SecondPass = func(s *traceql.Spanset) ([]*traceql.Spanset, error) { return []*traceql.Spanset{s}, nil }
+	req.SecondPassConditions = traceql.SearchMetaConditions()
+
+	resp, err := b.Fetch(ctx, req, common.DefaultSearchOptions())
+	require.NoError(t, err, q)
+
+	var results []*traceql.Spanset
+	for {
+		ss, err := resp.Results.Next(ctx)
+		require.NoError(t, err, q)
+		if ss == nil {
+			break
+		}
+		results = append(results, ss)
+	}
+	return results
+}
+
+// makeTraceWithDedicatedAttributes returns a trace with the attributes of testDedicatedColumns. The second
+// span has a non-string value for the dedicated span attribute.
+func makeTraceWithDedicatedAttributes(id []byte) *tempopb.Trace {
+	tr := test.MakeTraceWithSpanCount(1, 2, id)
+	now := uint64(time.Now().UnixNano())
+
+	rs := tr.Batches[0]
+	rs.Resource.Attributes = append(rs.Resource.Attributes,
+		&v1.KeyValue{Key: "dedicated.resource.1", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "res1"}}},
+		&v1.KeyValue{Key: "dedicated.resource.2", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "res2"}}},
+	)
+
+	// keep all spans in a single scope
+	for _, ss := range rs.ScopeSpans[1:] {
+		rs.ScopeSpans[0].Spans = append(rs.ScopeSpans[0].Spans, ss.Spans...)
+	}
+	rs.ScopeSpans = rs.ScopeSpans[:1]
+
+	spans := rs.ScopeSpans[0].Spans
+	for _, s := range spans {
+		s.StartTimeUnixNano = now
+		s.EndTimeUnixNano = now + 1
+	}
+	spans[0].Attributes = append(spans[0].Attributes,
+		&v1.KeyValue{Key: "dedicated.span.1", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "span1"}}},
+	)
+	spans[1].Attributes = append(spans[1].Attributes,
+		&v1.KeyValue{Key: "dedicated.span.1", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 123}}},
+	)
+
+	return tr
+}
@@ -0,0 +1,507 @@
+package vparquet3
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+	"github.com/segmentio/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestProtoParquetRoundTrip(t *testing.T) {
+	// This test round trips a proto trace and checks that the transformation works as expected
+	// Proto -> Parquet -> Proto
+
+	traceIDA := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}
+	expectedTrace := parquetTraceToTempopbTrace(&backend.BlockMeta{}, fullyPopulatedTestTrace(traceIDA))
+
+	parquetTrace := traceToParquet(&backend.BlockMeta{}, traceIDA, expectedTrace, nil)
+	actualTrace := parquetTraceToTempopbTrace(&backend.BlockMeta{}, parquetTrace)
+	assert.Equal(t, expectedTrace, actualTrace)
+}
+
+func TestProtoToParquetEmptyTrace(t *testing.T) {
+	want := &Trace{
+		TraceID:       make([]byte, 16),
+		ResourceSpans: nil,
+	}
+
+	got := traceToParquet(&backend.BlockMeta{}, nil, &tempopb.Trace{}, nil)
+	require.Equal(t, want, got)
+}
+
+func TestProtoParquetRando(t *testing.T) {
+	trp := &Trace{}
+	for i := 0; i < 100; i++ {
+		batches := rand.Intn(15)
+		id := test.ValidTraceID(nil)
+		expectedTrace := test.MakeTrace(batches, id)
+
+		parqTr := traceToParquet(&backend.BlockMeta{}, id, expectedTrace, trp)
+		actualTrace := parquetTraceToTempopbTrace(&backend.BlockMeta{}, parqTr)
+		require.Equal(t, expectedTrace, actualTrace)
+	}
+}
+
+func TestFieldsAreCleared(t *testing.T) {
+	traceID := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}
+	complexTrace := parquetTraceToTempopbTrace(&backend.BlockMeta{}, fullyPopulatedTestTrace(traceID))
+	simpleTrace := &tempopb.Trace{
+		Batches: []*v1_trace.ResourceSpans{
+			{
+				Resource: &v1_resource.Resource{
+					Attributes: []*v1.KeyValue{
+						{Key: "i", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 123}}},
+					},
+				},
+				ScopeSpans: []*v1_trace.ScopeSpans{
+					{
+						Scope: &v1.InstrumentationScope{},
+						Spans: []*v1_trace.Span{
+							{
+								TraceId: traceID,
+								Status:  &v1_trace.Status{},
+								Attributes: []*v1.KeyValue{
+									{
+										Key: "a",
+										Value: &v1.AnyValue{
+											Value: &v1.AnyValue_StringValue{StringValue: "b"},
+										},
+									},
+								},
+								Events: []*v1_trace.Span_Event{
+									{
+										// An attribute of every type
+										Attributes: []*v1.KeyValue{
+											// String
+											{Key: "i", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 123}}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// first convert a trace that sets all fields and then convert
+	// a minimal trace to make sure nothing bleeds through
+	tr := &Trace{}
+	_ = traceToParquet(&backend.BlockMeta{}, traceID, complexTrace, tr)
+	parqTr := traceToParquet(&backend.BlockMeta{}, traceID, simpleTrace, tr)
+	actualTrace := parquetTraceToTempopbTrace(&backend.BlockMeta{}, parqTr)
+	require.Equal(t, simpleTrace, actualTrace)
+}
+
+func TestTraceToParquet(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	intPtr := func(i int64) *int64 { return &i }
+
+	traceID := common.ID{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}
+
+	tsc := []struct {
+		name     string
+		id       common.ID
+		trace    tempopb.Trace
+		expected Trace
+	}{
+		{
+			name: "span and resource attributes",
+			id:   traceID,
+			trace: tempopb.Trace{
+				Batches: []*v1_trace.ResourceSpans{{
+					Resource: &v1_resource.Resource{
+						Attributes: []*v1.KeyValue{
+							{Key: "res.attr", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 123}}},
+							{Key: "service.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "service-a"}}},
+							{Key: "cluster", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "cluster-a"}}},
+							{Key: "namespace", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "namespace-a"}}},
+							{Key: "pod", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "pod-a"}}},
+							{Key: "container", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "container-a"}}},
+							{Key: "k8s.cluster.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "k8s-cluster-a"}}},
+							{Key: "k8s.namespace.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "k8s-namespace-a"}}},
+							{Key: "k8s.pod.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "k8s-pod-a"}}},
+							{Key: "k8s.container.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "k8s-container-a"}}},
+						},
+					},
+					ScopeSpans: []*v1_trace.ScopeSpans{{
+						Scope: &v1.InstrumentationScope{},
+						Spans: []*v1_trace.Span{{
+							Name:   "span-a",
+							SpanId: common.ID{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+							Attributes: []*v1.KeyValue{
+								{Key: "span.attr", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "aaa"}}},
+								{Key: "http.method", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "POST"}}},
+								{Key: "http.url", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "https://example.com"}}},
+								{Key: "http.status_code", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 201}}},
+							},
+						}},
+					}},
+				}},
+			},
+			expected: Trace{
+				TraceID:         traceID,
+				TraceIDText:     "102030405060708090a0b0c0d0e0f",
+				RootSpanName:    "span-a",
+				RootServiceName: "service-a",
+				ResourceSpans: []ResourceSpans{{
+					Resource: Resource{
+						ServiceName:      "service-a",
+						Cluster:          strPtr("cluster-a"),
+						Namespace:        strPtr("namespace-a"),
+						Pod:              strPtr("pod-a"),
+						Container:        strPtr("container-a"),
+						K8sClusterName:   strPtr("k8s-cluster-a"),
+						K8sNamespaceName: strPtr("k8s-namespace-a"),
+						K8sPodName:       strPtr("k8s-pod-a"),
+						K8sContainerName: strPtr("k8s-container-a"),
+						Attrs: []Attribute{
+							{Key: "res.attr", ValueInt: intPtr(int64(123))},
+						},
+					},
+					ScopeSpans: []ScopeSpans{{
+						Spans: []Span{{
+							Name:           "span-a",
+							SpanID:         []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+							NestedSetLeft:  1,
+							NestedSetRight: 2,
+							HttpMethod:     strPtr("POST"),
+							HttpUrl:        strPtr("https://example.com"),
+							HttpStatusCode: intPtr(201),
+							Attrs: []Attribute{
+								{Key: "span.attr", Value: strPtr("aaa")},
+							},
+						}},
+					}},
+				}},
+			},
+		},
+		{
+			name: "nested set model bounds",
+			id:   traceID,
+			trace: tempopb.Trace{
+				Batches: []*v1_trace.ResourceSpans{{
+					Resource: &v1_resource.Resource{
+						Attributes: []*v1.KeyValue{
+							{Key: "service.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "service-a"}}},
+						},
+					},
+					ScopeSpans: []*v1_trace.ScopeSpans{{
+						Scope: &v1.InstrumentationScope{},
+						Spans: []*v1_trace.Span{
+							{
+								Name:   "span-a",
+								SpanId: common.ID{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								Attributes: []*v1.KeyValue{
+									{Key: "span.attr", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "aaa"}}},
+								},
+							},
+							{
+								Name:         "span-b",
+								SpanId:       common.ID{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+								ParentSpanId: common.ID{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								Attributes: []*v1.KeyValue{
+									{Key: "span.attr", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "bbb"}}},
+								},
+							},
+							{
+								Name:         "span-c",
+								SpanId:       common.ID{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03},
+								ParentSpanId: common.ID{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								Attributes: []*v1.KeyValue{
+									{Key: "span.attr", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "ccc"}}},
+								},
+							},
+						},
+					}},
+				}},
+			},
+			expected: Trace{
+				TraceID:         traceID,
+				TraceIDText:     "102030405060708090a0b0c0d0e0f",
+				RootSpanName:    "span-a",
+				RootServiceName: "service-a",
+				ResourceSpans: []ResourceSpans{{
+					Resource: Resource{
+						ServiceName: "service-a",
+						Attrs:       []Attribute{},
+					},
+					ScopeSpans: []ScopeSpans{{
+						Spans: []Span{
+							{
+								Name:           "span-a",
+								SpanID:         []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								NestedSetLeft:  1,
+								NestedSetRight: 6,
+								Attrs: []Attribute{
+									{Key: "span.attr", Value: strPtr("aaa")},
+								},
+							},
+							{
+								Name:           "span-b",
+								SpanID:         []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+								ParentSpanID:   []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								ParentID:       1,
+								NestedSetLeft:  2,
+								NestedSetRight: 3,
+								Attrs: []Attribute{
+									{Key: "span.attr", Value: strPtr("bbb")},
+								},
+							},
+							{
+								Name:           "span-c",
+								SpanID:         []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03},
+								ParentSpanID:   []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+								ParentID:       1,
+								NestedSetLeft:  4,
+								NestedSetRight: 5,
+								Attrs: []Attribute{
+									{Key: "span.attr", Value: strPtr("ccc")},
+								},
+							},
+						},
+					}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tsc {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual Trace
+			traceToParquet(&backend.BlockMeta{}, tt.id, &tt.trace, &actual)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func BenchmarkProtoToParquet(b *testing.B) {
+	batchCount := 100
+	spanCounts := []int{
+		100, 1000,
+		10000,
+	}
+
+	for _, spanCount := range spanCounts {
+		b.Run("SpanCount:"+humanize.SI(float64(batchCount*spanCount), ""), func(b *testing.B) {
+
+			id := test.ValidTraceID(nil)
+			tr := test.MakeTraceWithSpanCount(batchCount, spanCount, id)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_ = traceToParquet(&backend.BlockMeta{}, id, tr, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkEventToParquet(b *testing.B) {
+	e := &v1_trace.Span_Event{
+		TimeUnixNano: 1000,
+		Name:         "blerg",
+		Attributes: []*v1.KeyValue{
+			// String
+			{Key: "s", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "s2"}}},
+
+			// Int
+			{Key: "i", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 123}}},
+
+			// Double
+			{Key: "d", Value: &v1.AnyValue{Value: &v1.AnyValue_DoubleValue{DoubleValue: 123.456}}},
+
+			// Bool
+			{Key: "b", Value: &v1.AnyValue{Value: &v1.AnyValue_BoolValue{BoolValue: true}}},
+
+			// KVList
+			{Key: "kv", Value: &v1.AnyValue{Value: &v1.AnyValue_KvlistValue{KvlistValue: &v1.KeyValueList{Values: []*v1.KeyValue{
+				{Key: "s2", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "s3"}}},
+				{Key: "i2", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 789}}},
+			}}}}},
+
+			// Array
+			{Key: "a", Value: &v1.AnyValue{Value: &v1.AnyValue_ArrayValue{ArrayValue: &v1.ArrayValue{Values: []*v1.AnyValue{
+				{Value: &v1.AnyValue_StringValue{StringValue: "s4"}},
+				{Value: &v1.AnyValue_IntValue{IntValue: 101112}},
+			}}}}},
+		},
+	}
+
+	ee := &Event{}
+	for i := 0; i < b.N; i++ {
+		eventToParquet(e, ee)
+	}
+}
+
+func BenchmarkDeconstruct(b *testing.B) {
+
+	batchCount := 100
+	spanCounts := []int{
+		100, 1000,
+		10000,
+	}
+
+	poolSizes := []int{
+		100_000,
+		30_000_000,
+	}
+
+	for _, spanCount := range spanCounts {
+		for _, poolSize := range poolSizes {
+			ss := humanize.SI(float64(batchCount*spanCount), "")
+			ps := humanize.SI(float64(poolSize), "")
+			b.Run(fmt.Sprintf("SpanCount%v/Pool%v", ss, ps), func(b *testing.B) {
+
+				id := test.ValidTraceID(nil)
+				tr := traceToParquet(&backend.BlockMeta{}, id, test.MakeTraceWithSpanCount(batchCount, spanCount, id), nil)
+				sch := parquet.SchemaOf(tr)
+
+				b.ResetTimer()
+
+				pool := newRowPool(poolSize)
+
+				for i := 0; i < b.N; i++ {
+					r2 := sch.Deconstruct(pool.Get(), tr)
+					pool.Put(r2)
+				}
+			})
+		}
+	}
+}
+
+func TestAttrToParquetArrays(t *testing.T) {
+	arr := func(vals ...*v1.AnyValue) *v1.KeyValue {
+		return &v1.KeyValue{Key: "a", Value: &v1.AnyValue{Value: &v1.AnyValue_ArrayValue{ArrayValue: &v1.ArrayValue{Values: vals}}}}
+	}
+	str := &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "s"}}
+	i := &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 1}}
+	d := &v1.AnyValue{Value: &v1.AnyValue_DoubleValue{DoubleValue: 1.5}}
+	b := &v1.AnyValue{Value: &v1.AnyValue_BoolValue{BoolValue: true}}
+
+	testCases := []struct {
+		name     string
+		kv       *v1.KeyValue
+		expected Attribute
+	}{
+		{"strings", arr(str, str), Attribute{Key: "a", ValueStringArray: []string{"s", "s"}}},
+		{"ints", arr(i), Attribute{Key: "a", ValueIntArray: []int64{1}}},
+		{"doubles", arr(d, d), Attribute{Key: "a", ValueDoubleArray: []float64{1.5, 1.5}}},
+		{"bools", arr(b), Attribute{Key: "a", ValueBoolArray: []bool{true}}},
+		{"mixed", arr(str, i), Attribute{Key: "a", ValueArray: `{"arrayValue":{"values":[{"stringValue":"s"},{"intValue":"1"}]}}`}},
+		{"empty", arr(), Attribute{Key: "a", ValueArray: `{"arrayValue":{}}`}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := Attribute{}
+			attrToParquet(tc.kv, &actual)
+
+			// typed arrays are reused, compare them by content
+			assert.ElementsMatch(t, tc.expected.ValueStringArray, actual.ValueStringArray)
+			assert.ElementsMatch(t, tc.expected.ValueIntArray, actual.ValueIntArray)
+			assert.ElementsMatch(t, tc.expected.ValueDoubleArray, actual.ValueDoubleArray)
+			assert.ElementsMatch(t, tc.expected.ValueBoolArray, actual.ValueBoolArray)
+			assert.Equal(t, tc.expected.ValueArray, actual.ValueArray)
+
+			assert.Equal(t, []*v1.KeyValue{tc.kv}, parquetToProtoAttrs([]Attribute{actual}))
+		})
+	}
+}
+
+func TestParquetRowSizeEstimate(t *testing.T) {
+	// use this test to parse actual Parquet files and compare the two methods of estimating row size
+	s := []string{}
+
+	for _, s := range s {
+		estimateRowSize(t, s)
+	}
+}
+
+func estimateRowSize(t *testing.T, name string) {
+	f, err := os.OpenFile(name, os.O_RDONLY, 0644)
+	require.NoError(t, err)
+
+	fi, err := f.Stat()
+	require.NoError(t, err)
+
+	pf, err := parquet.OpenFile(f, fi.Size())
+	require.NoError(t, err)
+
+	r := parquet.NewGenericReader[*Trace](pf)
+	row := make([]*Trace, 1)
+
+	totalProtoSize := int64(0)
+	totalTraceSize := int64(0)
+	for {
+		_, err := r.Read(row)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+
+		tr := row[0]
+		sch := parquet.SchemaOf(tr)
+		row := sch.Deconstruct(nil, tr)
+
+		totalProtoSize += int64(estimateMarshalledSizeFromParquetRow(row))
+		totalTraceSize += int64(estimateMarshalledSizeFromTrace(tr))
+	}
+
+	fmt.Println(pf.Size(), ",", len(pf.RowGroups()), ",", totalProtoSize, ",", totalTraceSize)
+}
+
+func TestExtendReuseSlice(t *testing.T) {
+	tcs := []struct {
+		sz       int
+		in       []int
+		expected []int
+	}{
+		{
+			sz:       0,
+			in:       []int{1, 2, 3},
+			expected: []int{},
+		},
+		{
+			sz:       2,
+			in:       []int{1, 2, 3},
+			expected: []int{1, 2},
+		},
+		{
+			sz:       5,
+			in:       []int{1, 2, 3},
+			expected: []int{1, 2, 3, 0, 0},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(fmt.Sprintf("%v", tc.sz), func(t *testing.T) {
+			out := extendReuseSlice(tc.sz, tc.in)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func BenchmarkExtendReuseSlice(b *testing.B) {
+	in := []int{1, 2, 3}
+	for i := 0; i < b.N; i++ {
+		_ = extendReuseSlice(100, in)
+	}
+}
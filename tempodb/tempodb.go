@@ -79,6 +79,7 @@ type Reader interface {
 	Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error)
 	Fetch(ctx context.Context, meta *backend.BlockMeta, req traceql.FetchSpansRequest, opts common.SearchOptions) (traceql.FetchSpansResponse, error)
 	BlockMetas(tenantID string) []*backend.BlockMeta
+	AddBlockMetas(tenantID string, metas []*backend.BlockMeta)
 	EnablePolling(sharder blocklist.JobSharder)
 
 	Shutdown()
@@ -162,9 +163,9 @@ func New(cfg *Config, logger gkLog.Logger) (Reader, Writer, Compactor, error) {
 
 	switch cfg.Cache {
 	case "redis":
-		cacheBackend = redis.NewClient(cfg.Redis, cfg.BackgroundCache, logger)
+		cacheBackend = redis.NewClient(cfg.Redis, cfg.BackgroundCache, "tempo", logger)
 	case "memcached":
-		cacheBackend = memcached.NewClient(cfg.Memcached, cfg.BackgroundCache, logger)
+		cacheBackend = memcached.NewClient(cfg.Memcached, cfg.BackgroundCache, "tempo", logger)
 	}
 
 	if cacheBackend != nil {
@@ -239,6 +240,8 @@ func (rw *readerWriter) CompleteBlockWithBackend(ctx context.Context, block comm
 		EndTime:      walMeta.EndTime,
 		DataEncoding: walMeta.DataEncoding,
 
+		DedicatedColumns: walMeta.DedicatedColumns,
+
 		// Other
 		Encoding: rw.cfg.Block.Encoding,
 	}
@@ -264,6 +267,12 @@ func (rw *readerWriter) BlockMetas(tenantID string) []*backend.BlockMeta {
 	return rw.blocklist.Metas(tenantID)
 }
 
+// AddBlockMetas adds the metas of blocks written by other components to the blocklist before they are found by
+// polling the backend. Blocks already in the blocklist, compacted or dropped by the last poll are ignored.
+func (rw *readerWriter) AddBlockMetas(tenantID string, metas []*backend.BlockMeta) {
+	rw.blocklist.AddUnpolled(tenantID, metas)
+}
+
 func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([]*tempopb.Trace, []error, error) {
 	// tracing instrumentation
 	logger := log.WithContext(ctx, log.Logger)
@@ -111,6 +111,48 @@ func TestDB(t *testing.T) {
 	}
 }
 
+func TestAddBlockMetas(t *testing.T) {
+	r, w, _, _ := testConfig(t, backend.EncGZIP, 0)
+	r.EnablePolling(&mockJobSharder{})
+
+	head, err := w.WAL().NewBlock(uuid.New(), testTenantID, model.CurrentEncoding)
+	require.NoError(t, err)
+
+	id := test.ValidTraceID(nil)
+	req := test.MakeTrace(10, id)
+	writeTraceToWal(t, head, model.MustNewSegmentDecoder(model.CurrentEncoding), id, req, 0, 0)
+
+	complete, err := w.CompleteBlock(context.Background(), head)
+	require.NoError(t, err)
+
+	// the block is not found before polling
+	found, failedBlocks, err := r.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0)
+	require.NoError(t, err)
+	require.Nil(t, failedBlocks)
+	require.Empty(t, found)
+
+	// added metas are found immediately and only added once
+	r.AddBlockMetas(testTenantID, []*backend.BlockMeta{complete.BlockMeta()})
+	r.AddBlockMetas(testTenantID, []*backend.BlockMeta{complete.BlockMeta()})
+	require.Len(t, r.BlockMetas(testTenantID), 1)
+
+	found, failedBlocks, err = r.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax, 0, 0)
+	require.NoError(t, err)
+	require.Nil(t, failedBlocks)
+	require.Len(t, found, 1)
+	require.True(t, proto.Equal(req, found[0]))
+
+	// and kept after polling the backend
+	r.(*readerWriter).pollBlocklist()
+	require.Len(t, r.BlockMetas(testTenantID), 1)
+
+	// compacted blocks aren't added again
+	require.NoError(t, r.(*readerWriter).c.MarkBlockCompacted(complete.BlockMeta().BlockID, testTenantID))
+	r.(*readerWriter).pollBlocklist()
+	r.AddBlockMetas(testTenantID, []*backend.BlockMeta{complete.BlockMeta()})
+	require.Empty(t, r.BlockMetas(testTenantID))
+}
+
 func TestNoCompactionWhenCompactionRange0(t *testing.T) {
 	_, _, c, _ := testConfig(t, backend.EncGZIP, 0)
 
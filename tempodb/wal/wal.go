@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
+	v2 "github.com/grafana/tempo/tempodb/encoding/v2"
 )
 
 const (
@@ -99,6 +100,11 @@ func (w *WAL) RescanBlocks(additionalStartSlack time.Duration, log log.Logger) (
 	encodings := encoding.AllEncodings()
 	blocks := make([]common.WALBlock, 0, len(files))
 	for _, f := range files {
+		// the folders of the local backend and the checkpoints of the v2 blocks don't hold wal blocks
+		if f.IsDir() && (f.Name() == blocksDir || f.Name() == v2.CheckpointDir) {
+			continue
+		}
+
 		// find owner
 		var owner encoding.VersionedEncoding
 		for _, e := range encodings {
@@ -155,15 +161,21 @@ func (w *WAL) RescanBlocks(additionalStartSlack time.Duration, log log.Logger) (
 }
 
 func (w *WAL) NewBlock(id uuid.UUID, tenantID string, dataEncoding string) (common.WALBlock, error) {
-	return w.newBlock(id, tenantID, dataEncoding, w.c.Version)
+	return w.newBlock(id, tenantID, dataEncoding, w.c.Version, nil)
+}
+
+// NewBlockWithDedicatedColumns creates a new WAL block that stores the given attributes in dedicated
+// columns if the configured block version supports it.
+func (w *WAL) NewBlockWithDedicatedColumns(id uuid.UUID, tenantID string, dataEncoding string, dc backend.DedicatedColumns) (common.WALBlock, error) {
+	return w.newBlock(id, tenantID, dataEncoding, w.c.Version, dc)
 }
 
-func (w *WAL) newBlock(id uuid.UUID, tenantID string, dataEncoding string, blockVersion string) (common.WALBlock, error) {
+func (w *WAL) newBlock(id uuid.UUID, tenantID string, dataEncoding string, blockVersion string, dc backend.DedicatedColumns) (common.WALBlock, error) {
 	v, err := encoding.FromVersion(blockVersion)
 	if err != nil {
 		return nil, err
 	}
-	return v.CreateWALBlock(id, tenantID, w.c.Filepath, w.c.Encoding, dataEncoding, w.c.IngestionSlack)
+	return v.CreateWALBlock(id, tenantID, w.c.Filepath, w.c.Encoding, dataEncoding, w.c.IngestionSlack, dc)
 }
 
 func (w *WAL) GetFilepath() string {